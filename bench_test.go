@@ -0,0 +1,98 @@
+package pg_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// A query with the substitutions made by the manager for a list
+	benchQuery = `WITH q AS (SELECT * FROM ${"schema"}."pg_database" WHERE name = @name ${where}) SELECT * FROM q ${orderby} ${offsetlimit}`
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// BENCHMARKS
+
+func Benchmark_Bind_Replace(b *testing.B) {
+	bind := pg.NewBind("schema", "pg_catalog", "name", "postgres", "where", "", "orderby", "ORDER BY name", "offsetlimit", "LIMIT 10")
+	b.ReportAllocs()
+	for b.Loop() {
+		bind.Replace(benchQuery)
+	}
+}
+
+func Benchmark_Bind_Copy(b *testing.B) {
+	bind := pg.NewBind("schema", "pg_catalog", "name", "postgres", "where", "", "orderby", "ORDER BY name", "offsetlimit", "LIMIT 10")
+	b.ReportAllocs()
+	for b.Loop() {
+		bind.Copy("as", "t (count BIGINT)")
+	}
+}
+
+func Benchmark_Pool_Scan(b *testing.B) {
+	ctx := context.Background()
+	benchTable(b, 100)
+
+	// List and scan the rows
+	b.ReportAllocs()
+	for b.Loop() {
+		var list TestList
+		if err := conn.List(ctx, &list, list); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_Pool_CRUD(b *testing.B) {
+	ctx := context.Background()
+	benchTable(b, 0)
+
+	// Insert, get, update and delete a row
+	b.ReportAllocs()
+	for b.Loop() {
+		test := Test{Name: "insert"}
+		if err := conn.Insert(ctx, &test, test); err != nil {
+			b.Fatal(err)
+		}
+		if err := conn.Get(ctx, &test, test); err != nil {
+			b.Fatal(err)
+		}
+		test.Name = "update"
+		if err := conn.Update(ctx, &test, test, test); err != nil {
+			b.Fatal(err)
+		}
+		if err := conn.Delete(ctx, &test, test); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Create the table for the Test rows with a number of rows, which is dropped
+// when the benchmark ends
+func benchTable(b *testing.B, rows int) {
+	b.Helper()
+	ctx := context.Background()
+	if err := conn.Exec(ctx, "CREATE TABLE test (id SERIAL PRIMARY KEY, name TEXT NOT NULL)"); err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		if err := conn.Exec(context.Background(), "DROP TABLE test"); err != nil {
+			b.Error(err)
+		}
+	})
+	for range rows {
+		test := Test{Name: "row"}
+		if err := conn.Insert(ctx, &test, test); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
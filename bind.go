@@ -5,12 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
-	"os"
 	"strings"
 	"sync"
+	"time"
 
 	// Packages
 	pgx "github.com/jackc/pgx/v5"
+	pgconn "github.com/jackc/pgx/v5/pgconn"
 	types "github.com/mutablelogic/go-pg/pkg/types"
 )
 
@@ -18,14 +19,24 @@ import (
 // TYPES
 
 // Bind represents a set of variables and arguments to be used in a query.
-// The vars are substituted in the query string itself, while the args are
-// passed as arguments to the query.
+// The ${key} vars are substituted in the query string itself, for
+// identifiers and fragments of SQL, while the @key args are passed as
+// pgx.NamedArgs, so values are never part of the query string. For queries
+// on a remote database, the @key args are replaced by quoted literals, since
+// the query is sent to the remote database as text.
 type Bind struct {
 	sync.RWMutex
 	vars   pgx.NamedArgs
 	dblink string // Used when executing transactions remotely
+	lint   bool   // Check queries with Lint before they are executed
 }
 
+// quotedIdent is an identifier which has been quoted with SetIdentifier
+type quotedIdent string
+
+// quotedLiteral is a constant which has been quoted with SetLiteral
+type quotedLiteral string
+
 ///////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
@@ -74,7 +85,7 @@ func (bind *Bind) Copy(pairs ...any) *Bind {
 	}
 
 	// Return the copied Bind object
-	return &Bind{vars: varsCopy, dblink: bind.dblink}
+	return &Bind{vars: varsCopy, dblink: bind.dblink, lint: bind.lint}
 }
 
 // Return a new bind object with the given database link
@@ -83,7 +94,7 @@ func (bind *Bind) withRemote(database string) *Bind {
 	maps.Copy(varsCopy, bind.vars)
 
 	// Return the copied Bind object
-	return &Bind{vars: varsCopy, dblink: "dbname=" + types.Quote(database)}
+	return &Bind{vars: varsCopy, dblink: "dbname=" + types.Quote(database), lint: bind.lint}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -117,6 +128,39 @@ func (bind *Bind) Set(key string, value any) string {
 	return "@" + key
 }
 
+// SetIdentifier sets a bind var to an identifier, such as a table name, and
+// returns the quoted identifier. When there is more than one part, the parts
+// are quoted separately and joined with a dot. The identifier is substituted
+// as it is with ${key} or ${"key"}, including in an identifier position such
+// as after FROM, where other values are rejected unless they are identifiers.
+func (bind *Bind) SetIdentifier(key string, parts ...string) string {
+	bind.Lock()
+	defer bind.Unlock()
+
+	if key == "" || len(parts) == 0 {
+		return ""
+	}
+	value := pgx.Identifier(parts).Sanitize()
+	bind.vars[key] = quotedIdent(value)
+	return value
+}
+
+// SetLiteral sets a bind var to a constant, such as a string which is
+// quoted and escaped, and returns the constant. The constant is substituted
+// as it is with ${key} or ${'key'}. This is for statements which can't have
+// arguments, such as ALTER SYSTEM; otherwise use Set and @key.
+func (bind *Bind) SetLiteral(key string, value any) string {
+	bind.Lock()
+	defer bind.Unlock()
+
+	if key == "" {
+		return ""
+	}
+	quoted := literal(value)
+	bind.vars[key] = quotedLiteral(quoted)
+	return quoted
+}
+
 // Get returns a bind var by key.
 func (bind *Bind) Get(key string) any {
 	bind.RLock()
@@ -191,23 +235,33 @@ func (bind *Bind) QueryRow(ctx context.Context, conn pgx.Tx, query string) pgx.R
 	bind.RLock()
 	defer bind.RUnlock()
 
+	// Merge the bind vars from the context
+	vars := bind.merge(ctx)
+
 	// dblink version
 	if bind.dblink != "" {
 		// 'as' is used to define the column names
 		var def string
-		if bind.Has("as") {
-			def = ` AS ` + bind.Get("as").(string)
+		if as, ok := vars["as"].(string); ok {
+			def = ` AS ` + as
+		}
+		query, err := bind.replace(query, vars)
+		if err != nil {
+			return &row{err: err}
 		}
-		// TODO: Attempt to unroll the @parameters in the query
 		return conn.QueryRow(ctx, replace(dblinkSelect, pgx.NamedArgs{
 			"conn":  bind.dblink,
-			"query": bind.Replace(query),
+			"query": unroll(query, vars),
 			"as":    def,
 		}))
 	}
 
 	// normal version
-	return conn.QueryRow(ctx, bind.Replace(query), bind.vars)
+	query, err := bind.replace(query, vars)
+	if err != nil {
+		return &row{err: err}
+	}
+	return conn.QueryRow(ctx, query, vars)
 }
 
 // Query a set of rows and return the result
@@ -215,22 +269,33 @@ func (bind *Bind) Query(ctx context.Context, conn pgx.Tx, query string) (pgx.Row
 	bind.RLock()
 	defer bind.RUnlock()
 
+	// Merge the bind vars from the context
+	vars := bind.merge(ctx)
+
 	// dblink version
 	if bind.dblink != "" {
 		// 'as' is used to define the column names
 		var def string
-		if bind.Has("as") {
-			def = ` AS ` + bind.Get("as").(string)
+		if as, ok := vars["as"].(string); ok {
+			def = ` AS ` + as
+		}
+		query, err := bind.replace(query, vars)
+		if err != nil {
+			return nil, err
 		}
 		return conn.Query(ctx, replace(dblinkSelect, pgx.NamedArgs{
 			"conn":  bind.dblink,
-			"query": bind.Replace(query),
+			"query": unroll(query, vars),
 			"as":    def,
 		}))
 	}
 
 	// normal version
-	return conn.Query(ctx, bind.Replace(query), bind.vars)
+	query, err := bind.replace(query, vars)
+	if err != nil {
+		return nil, err
+	}
+	return conn.Query(ctx, query, vars)
 }
 
 // Exec executes a query.
@@ -238,35 +303,57 @@ func (bind *Bind) Exec(ctx context.Context, conn pgx.Tx, query string) error {
 	bind.RLock()
 	defer bind.RUnlock()
 
-	// dblink version
+	// Merge the bind vars from the context
+	vars := bind.merge(ctx)
+
+	query, err := bind.replace(query, vars)
+	if err != nil {
+		return err
+	}
+
+	// dblink version, which returns the command tag of the remote statement
+	start := time.Now()
 	if bind.dblink != "" {
-		// TODO: Attempt to unroll the parameters
-		_, err := conn.Exec(ctx, replace(dblinkExec, pgx.NamedArgs{
+		var status string
+		err := conn.QueryRow(ctx, replace(dblinkExec, pgx.NamedArgs{
 			"conn":  bind.dblink,
-			"query": bind.Replace(query),
-		}))
+			"query": unroll(query, vars),
+		})).Scan(&status)
+		if err == nil {
+			setResult(ctx, pgconn.NewCommandTag(status), start)
+		}
 		return err
 	}
 
 	// normal version
-	_, err := conn.Exec(ctx, bind.Replace(query), bind.vars)
+	tag, err := conn.Exec(ctx, query, vars)
+	if err == nil {
+		setResult(ctx, tag, start)
+	}
 	return err
 }
 
 // Queue a query - for bulk operations
-func (bind *Bind) queuerow(batch *pgx.Batch, query string, reader Reader) {
+func (bind *Bind) queuerow(ctx context.Context, batch *pgx.Batch, query string, reader Reader) error {
 	bind.RLock()
 	defer bind.RUnlock()
-	queuedquery := batch.Queue(bind.Replace(query), bind.vars)
+	vars := bind.merge(ctx)
+	query, err := bind.replace(query, vars)
+	if err != nil {
+		return err
+	}
+	queuedquery := batch.Queue(query, vars)
 	queuedquery.QueryRow(func(row pgx.Row) error {
 		return reader.Scan(row)
 	})
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
-// Replace returns a query string with ${subtitution} replaced by the values:
+// Replace returns a query string with ${subtitution} replaced by the values.
+// Each query is parsed once, and the parsed query is kept for later calls:
 //   - ${key} => value
 //   - ${'key'} => 'value'
 //   - ${"key"} => "value"
@@ -276,43 +363,77 @@ func (bind *Bind) Replace(query string) string {
 	return replace(query, bind.vars)
 }
 
-func replace(query string, vars pgx.NamedArgs) string {
-	fetch := func(key string) string {
-		return fmt.Sprint(vars[key])
-	}
-	return os.Expand(query, func(key string) string {
-		if key == "$" { // $$ => $$
-			return "$$"
-		}
-		if types.IsNumeric(key) {
-			return "$" + key // $1 => $1
+// Return the bind vars with the bind vars from the context which are not
+// already bound. The caller must hold the lock
+func (bind *Bind) merge(ctx context.Context) pgx.NamedArgs {
+	var vars pgx.NamedArgs
+	for key, value := range contextBind(ctx) {
+		if _, exists := bind.vars[key]; exists {
+			continue
 		}
-		if types.IsSingleQuoted(key) { // ${'key'} => 'value'
-			// Special case where value is []string and single quote for IN (${key})
-			key := strings.Trim(key, "'")
-			value := vars[key]
-			switch v := value.(type) {
-			case []string:
-				result := make([]string, len(v))
-				for i, s := range v {
-					result[i] = types.Quote(s)
-				}
-				return strings.Join(result, ",")
-			default:
-				return types.Quote(fetch(key))
-			}
+		if vars == nil {
+			vars = maps.Clone(bind.vars)
 		}
-		if types.IsDoubleQuoted(key) { // ${"key"} => "value"
-			return types.DoubleQuote(fetch(strings.Trim(key, "\"")))
+		vars[key] = value
+	}
+	if vars == nil {
+		return bind.vars
+	}
+	return vars
+}
+
+// Return a query with the bind vars substituted, or an error if a bind var
+// can't be substituted safely, or the query fails the checks of WithLint when
+// it is enabled.
+// The caller must hold the lock
+func (bind *Bind) replace(query string, vars pgx.NamedArgs) (string, error) {
+	if bind.lint {
+		if err := Lint(query); err != nil {
+			return "", err
 		}
-		return fetch(key) // ${key} => value
-	})
+	}
+	t := compile(query)
+	if err := t.check(vars, bind.lint); err != nil {
+		return "", err
+	}
+	return t.render(vars), nil
+}
+
+func replace(query string, vars pgx.NamedArgs) string {
+	return compile(query).render(vars)
+}
+
+// Return a query with the bind vars substituted and the @name arguments
+// replaced by literals, for a query which is sent as text to the remote
+// database, and the column definitions set with the 'as' bind var
+func (bind *Bind) remote(ctx context.Context, query string) (string, string, error) {
+	bind.RLock()
+	defer bind.RUnlock()
+
+	// Merge the bind vars from the context
+	vars := bind.merge(ctx)
+
+	// 'as' is used to define the column names
+	var def string
+	if as, ok := vars["as"].(string); ok {
+		def = ` AS ` + as
+	}
+	query, err := bind.replace(query, vars)
+	if err != nil {
+		return "", "", err
+	}
+	return unroll(query, vars), def, nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // SQL
 
 const (
-	dblinkSelect = "SELECT * FROM dblink(${'conn'}, ${'query'}, true)${as}"
-	dblinkExec   = "SELECT dblink_exec(${'conn'}, ${'query'}, true)"
+	dblinkSelect     = "SELECT * FROM dblink(${'conn'}, ${'query'}, true)${as}"
+	dblinkExec       = "SELECT dblink_exec(${'conn'}, ${'query'}, true)"
+	dblinkConnect    = "SELECT dblink_connect(${'name'}, ${'conn'})"
+	dblinkDisconnect = "SELECT dblink_disconnect(${'name'})"
+	dblinkOpen       = "SELECT dblink_open(${'name'}, ${'name'}, ${'query'}, true)"
+	dblinkFetch      = "SELECT * FROM dblink_fetch(${'name'}, ${'name'}, ${count}, true)${as}"
+	dblinkClose      = "SELECT dblink_close(${'name'}, ${'name'}, true)"
 )
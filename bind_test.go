@@ -90,3 +90,22 @@ func Test_Bind_003(t *testing.T) {
 	)
 	assert.Equal("IN ('a','b','c')", bind.Replace("IN (${'list'})"))
 }
+
+func Test_Bind_004(t *testing.T) {
+	assert := assert.New(t)
+
+	// Identifiers are quoted, and parts are joined with a dot
+	bind := pg.NewBind()
+	assert.Equal(`"public"."my ""table"""`, bind.SetIdentifier("table", "public", `my "table"`))
+	assert.Equal(`SELECT * FROM "public"."my ""table"""`, bind.Replace(`SELECT * FROM ${table}`))
+	assert.Equal(`SELECT * FROM "public"."my ""table"""`, bind.Replace(`SELECT * FROM ${"table"}`))
+	assert.Equal("", bind.SetIdentifier("table"))
+	assert.Equal("", bind.SetIdentifier("", "a"))
+
+	// Literals are quoted and escaped
+	assert.Equal(`'o''brien'`, bind.SetLiteral("name", "o'brien"))
+	assert.Equal(`E'a\\b'`, bind.SetLiteral("path", `a\b`))
+	assert.Equal(`42`, bind.SetLiteral("id", 42))
+	assert.Equal(`ALTER SYSTEM SET work_mem = 'o''brien'`, bind.Replace(`ALTER SYSTEM SET work_mem = ${'name'}`))
+	assert.Equal(`SELECT 'o''brien', 42`, bind.Replace(`SELECT ${name}, ${id}`))
+}
@@ -41,7 +41,7 @@ func (conn *bulkconn) Bulk(context.Context, func(Conn) error) error {
 }
 
 // Execute a query
-func (conn *bulkconn) Exec(context.Context, string) error {
+func (conn *bulkconn) Exec(context.Context, string, ...ExecOpt) error {
 	return ErrNotImplemented
 }
 
@@ -50,9 +50,18 @@ func (conn *bulkconn) Insert(ctx context.Context, reader Reader, writer Writer)
 	if query, err := writer.Insert(conn.bind); err != nil {
 		return err
 	} else {
-		conn.bind.Copy().queuerow(&conn.batch, query, reader)
+		return conn.bind.Copy().queuerow(ctx, &conn.batch, query, reader)
+	}
+}
+
+// Perform an upsert
+func (conn *bulkconn) Upsert(ctx context.Context, reader Reader, writer Writer, target ...string) error {
+	bind := conn.bind.Copy()
+	if query, err := upsertQuery(bind, writer, target...); err != nil {
+		return err
+	} else {
+		return bind.queuerow(ctx, &conn.batch, query, reader)
 	}
-	return nil
 }
 
 // Perform an update
@@ -65,6 +74,16 @@ func (conn *bulkconn) Delete(context.Context, Reader, Selector) error {
 	return ErrNotImplemented
 }
 
+// Perform an update, returning the number of rows updated
+func (conn *bulkconn) UpdateMany(context.Context, Selector, Writer) (int64, error) {
+	return 0, ErrNotImplemented
+}
+
+// Perform a delete, returning the number of rows deleted
+func (conn *bulkconn) DeleteMany(context.Context, Selector) (int64, error) {
+	return 0, ErrNotImplemented
+}
+
 // Perform a get
 func (conn *bulkconn) Get(context.Context, Reader, Selector) error {
 	return ErrNotImplemented
@@ -76,6 +95,16 @@ func (conn *bulkconn) List(context.Context, Reader, Selector) error {
 	return ErrNotImplemented
 }
 
+// Perform a list, calling the function for each row
+func (conn *bulkconn) ListStream(context.Context, Selector, func(Row) error) error {
+	return ErrNotImplemented
+}
+
+// Declare a cursor
+func (conn *bulkconn) Cursor(context.Context, string, uint) (Cursor, error) {
+	return nil, ErrNotImplemented
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
@@ -0,0 +1,142 @@
+package main
+
+import (
+	// Packages
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type AlertRuleCommands struct {
+	ListAlertRules  ListAlertRulesCommand  `cmd:"" name:"alertrules" help:"List alert rules."`
+	ListAlerts      ListAlertsCommand      `cmd:"" name:"alerts" help:"List alert rules which are firing."`
+	GetAlertRule    GetAlertRuleCommand    `cmd:"" name:"alertrule" help:"Get alert rule."`
+	CreateAlertRule CreateAlertRuleCommand `cmd:"" name:"create-alertrule" help:"Create alert rule."`
+	UpdateAlertRule UpdateAlertRuleCommand `cmd:"" name:"update-alertrule" help:"Update alert rule."`
+	DeleteAlertRule DeleteAlertRuleCommand `cmd:"" name:"delete-alertrule" help:"Delete alert rule."`
+}
+
+type ListAlertRulesCommand struct {
+	Enabled *bool   `name:"enabled" help:"Filter by whether the rule is evaluated"`
+	Offset  uint64  `name:"offset" help:"Offset for pagination"`
+	Limit   *uint64 `name:"limit" help:"Limit for pagination"`
+}
+
+type ListAlertsCommand struct {
+	Offset uint64  `name:"offset" help:"Offset for pagination"`
+	Limit  *uint64 `name:"limit" help:"Limit for pagination"`
+}
+
+type GetAlertRuleCommand struct {
+	Name string `arg:"" name:"name" help:"Rule name"`
+}
+
+type CreateAlertRuleCommand struct {
+	schema.AlertRuleMeta
+}
+
+type UpdateAlertRuleCommand struct {
+	schema.AlertRuleMeta
+}
+
+type DeleteAlertRuleCommand struct {
+	GetAlertRuleCommand
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *ListAlertRulesCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List rules
+	rules, err := client.ListAlertRules(ctx.ctx,
+		httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit),
+		httpclient.WithEnabled(cmd.Enabled),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(rules)
+}
+
+func (cmd *ListAlertsCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List firing rules
+	alerts, err := client.ListAlerts(ctx.ctx, httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit))
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(alerts)
+}
+
+func (cmd *GetAlertRuleCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get rule
+	rule, err := client.GetAlertRule(ctx.ctx, cmd.Name)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(rule)
+}
+
+func (cmd *CreateAlertRuleCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Create rule
+	rule, err := client.CreateAlertRule(ctx.ctx, cmd.AlertRuleMeta)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(rule)
+}
+
+func (cmd *UpdateAlertRuleCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Update rule
+	rule, err := client.UpdateAlertRule(ctx.ctx, cmd.Name, cmd.AlertRuleMeta)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(rule)
+}
+
+func (cmd *DeleteAlertRuleCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Delete rule
+	return client.DeleteAlertRule(ctx.ctx, cmd.Name)
+}
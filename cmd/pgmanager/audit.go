@@ -0,0 +1,53 @@
+package main
+
+import (
+	"time"
+
+	// Packages
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type AuditCommands struct {
+	ListAudit ListAuditCommand `cmd:"" name:"audit" help:"List create, update and delete operations in the audit log."`
+}
+
+type ListAuditCommand struct {
+	Resource *string   `name:"resource" help:"Filter by resource, such as role or database"`
+	Actor    *string   `name:"actor" help:"Filter by the user which made the request"`
+	Since    time.Time `name:"since" help:"Operations at or after this time"`
+	Until    time.Time `name:"until" help:"Operations before this time"`
+	Request  *string   `name:"request" help:"Filter by a JSON document which the request contains"`
+	Path     *string   `name:"path" help:"Filter by a jsonpath expression which matches the request"`
+	Offset   uint64    `name:"offset" help:"Offset for pagination"`
+	Limit    *uint64   `name:"limit" help:"Limit for pagination"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *ListAuditCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List the audit log
+	audit, err := client.ListAudit(ctx.ctx,
+		httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit),
+		httpclient.WithResource(cmd.Resource),
+		httpclient.WithActor(cmd.Actor),
+		httpclient.WithSince(&cmd.Since),
+		httpclient.WithUntil(&cmd.Until),
+		httpclient.WithRequest(cmd.Request),
+		httpclient.WithPath(cmd.Path),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(audit)
+}
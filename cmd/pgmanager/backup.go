@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	// Packages
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type BackupCommands struct {
+	GetBackup       GetBackupCommand       `cmd:"" name:"backup" help:"Get base backup in progress."`
+	StartBackup     StartBackupCommand     `cmd:"" name:"start-backup" help:"Start a base backup."`
+	StopBackup      StopBackupCommand      `cmd:"" name:"stop-backup" help:"Stop the base backup in progress."`
+	DumpDatabase    DumpDatabaseCommand    `cmd:"" name:"dump-database" help:"Dump a database."`
+	RestoreDatabase RestoreDatabaseCommand `cmd:"" name:"restore-database" help:"Restore a database from a dump."`
+}
+
+type GetBackupCommand struct{}
+
+type StartBackupCommand struct {
+	schema.BackupMeta
+	Queue    bool `name:"queue" help:"Queue until the next maintenance window opens"`
+	Override bool `name:"override" help:"Start now, outside of the maintenance windows"`
+}
+
+type StopBackupCommand struct {
+	Wait bool `name:"wait" help:"Wait for WAL segments to be archived"`
+}
+
+type DumpDatabaseCommand struct {
+	Name       string `arg:"" name:"name" help:"Database name"`
+	Format     string `name:"format" help:"Dump format (plain, custom, tar)" default:"plain"`
+	SchemaOnly bool   `name:"schema-only" help:"Dump only the object definitions"`
+	DataOnly   bool   `name:"data-only" help:"Dump only the data"`
+	Out        string `name:"out" short:"o" type:"path" help:"Output file (defaults to stdout)"`
+}
+
+type RestoreDatabaseCommand struct {
+	Name              string `arg:"" name:"name" help:"Database name"`
+	Format            string `name:"format" help:"Dump format (plain, custom, tar), detected when not set"`
+	Clean             bool   `name:"clean" help:"Drop objects before recreating them"`
+	SingleTransaction bool   `name:"single-transaction" help:"Restore as a single transaction"`
+	Input             string `name:"input" short:"i" help:"Input file (defaults to stdin)"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *GetBackupCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the backup in progress
+	backup, err := client.GetBackup(ctx.ctx)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(backup)
+}
+
+func (cmd *StartBackupCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Queue the backup until the next maintenance window opens
+	if cmd.Queue {
+		task, err := client.QueueMaintenance(ctx.ctx, schema.MaintenanceTaskMeta{
+			Operation: schema.MaintenanceBackup,
+			Label:     cmd.Label,
+			Fast:      cmd.Fast,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(task)
+		return nil
+	}
+
+	// Start the backup
+	backup, err := client.StartBackup(ctx.ctx, cmd.BackupMeta, httpclient.WithOverride(cmd.Override))
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(backup)
+}
+
+func (cmd *StopBackupCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Stop the backup
+	backup, err := client.StopBackup(ctx.ctx, httpclient.WithWait(cmd.Wait))
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(backup)
+}
+
+func (cmd *DumpDatabaseCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Set the output
+	var w io.Writer = os.Stdout
+	if cmd.Out != "" {
+		f, err := os.Create(cmd.Out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	// Dump the database
+	return client.DumpDatabase(ctx.ctx, w, cmd.Name,
+		httpclient.WithFormat(cmd.Format),
+		httpclient.WithSchemaOnly(cmd.SchemaOnly),
+		httpclient.WithDataOnly(cmd.DataOnly),
+	)
+}
+
+func (cmd *RestoreDatabaseCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Set the input
+	var r io.Reader = os.Stdin
+	if cmd.Input != "" {
+		f, err := os.Open(cmd.Input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	// Report progress messages to stderr
+	progress := func(progress schema.DatabaseRestoreProgress) {
+		if progress.Message != "" {
+			fmt.Fprintln(os.Stderr, progress.Message)
+		}
+	}
+
+	// Restore the database
+	result, err := client.RestoreDatabase(ctx.ctx, r, cmd.Name, progress,
+		httpclient.WithFormat(cmd.Format),
+		httpclient.WithClean(cmd.Clean),
+		httpclient.WithSingleTransaction(cmd.SingleTransaction),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(result)
+}
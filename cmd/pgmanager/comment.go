@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type CommentCommands struct {
+	Comment CommentCommand `cmd:"" name:"comment" help:"Set or remove the comment on a database, role, schema or object."`
+}
+
+type CommentCommand struct {
+	Type      string `arg:"" name:"type" enum:"database,role,schema,object" help:"Type of object (database, role, schema, object)"`
+	Name      string `arg:"" name:"name" help:"Name of the database, role, schema or object"`
+	Comment   string `arg:"" name:"comment" optional:"" help:"Comment, or empty to remove the comment"`
+	Database  string `name:"database" short:"d" help:"Database of the schema or object"`
+	Namespace string `name:"schema" short:"s" help:"Schema of the object"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *CommentCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Set the comment
+	if err := client.SetComment(ctx.ctx, schema.CommentTarget{
+		Type:     schema.CommentType(cmd.Type),
+		Database: cmd.Database,
+		Schema:   cmd.Namespace,
+		Name:     cmd.Name,
+		Comment:  cmd.Comment,
+	}); err != nil {
+		return err
+	}
+
+	if cmd.Comment == "" {
+		fmt.Println("Comment removed successfully")
+	} else {
+		fmt.Println("Comment set successfully")
+	}
+	return nil
+}
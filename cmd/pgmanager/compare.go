@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type CompareCommands struct {
+	Profile ProfileCommand `cmd:"" name:"profile" help:"Get the settings, installed extensions and roles of the server."`
+	Compare CompareCommand `cmd:"" name:"compare" help:"Compare settings, installed extensions and roles with another server."`
+	Diff    DiffCommand    `cmd:"" name:"diff-schemas" help:"Compare tables, columns, constraints and indexes of two databases."`
+}
+
+type ProfileCommand struct{}
+
+type CompareCommand struct {
+	URL   string `arg:"" name:"url" help:"API endpoint of the other server, such as http://localhost:8081/api/v1"`
+	Token string `name:"other-token" env:"PG_OTHER_TOKEN" help:"Bearer token to send to the other server"`
+}
+
+type DiffCommand struct {
+	Database string `arg:"" name:"database" help:"Database to migrate"`
+	Other    string `arg:"" name:"other" help:"Database to compare with"`
+	schema.SchemaDiffRequest
+	PlanFile string `name:"plan-file" type:"path" help:"Write the differences and statements to a plan file, which is checked with plan verify"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *ProfileCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the profile
+	profile, err := client.GetProfile(ctx.ctx)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(profile)
+}
+
+func (cmd *CompareCommand) Run(ctx *Globals) error {
+	this, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the profile of the other server
+	opts := []client.ClientOpt{}
+	if ctx.Debug {
+		opts = append(opts, client.OptTrace(os.Stderr, true))
+	}
+	if cmd.Token != "" {
+		opts = append(opts, httpclient.WithToken(cmd.Token))
+	}
+	other, err := httpclient.New(cmd.URL, opts...)
+	if err != nil {
+		return err
+	}
+	profile, err := other.GetProfile(ctx.ctx)
+	if err != nil {
+		return err
+	}
+
+	// Compare with this server
+	comparison, err := this.CompareProfile(ctx.ctx, *profile)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(comparison)
+}
+
+func (cmd *DiffCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Compare the databases
+	diff, err := client.DiffSchemas(ctx.ctx, cmd.Database, cmd.Other, cmd.SchemaDiffRequest)
+	if err != nil {
+		return err
+	}
+
+	// Write the plan file
+	if cmd.PlanFile != "" {
+		if err := writePlan(cmd.PlanFile, schema.NewSchemaPlan(cmd.Database, cmd.Other, cmd.SchemaDiffRequest, *diff)); err != nil {
+			return err
+		}
+	}
+
+	// Print
+	return ctx.Print(diff)
+}
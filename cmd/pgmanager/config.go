@@ -0,0 +1,109 @@
+package main
+
+import (
+	// Packages
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type ConfigCommands struct {
+	ListConfigFiles ListConfigFilesCommand `cmd:"" name:"config-files" help:"List settings in the configuration files."`
+	ListHBARules    ListHBARulesCommand    `cmd:"" name:"hba-rules" help:"List rules in pg_hba.conf."`
+	AddHBARule      AddHBARuleCommand      `cmd:"" name:"add-hba-rule" help:"Add a rule to pg_hba.conf and reload the configuration."`
+	RemoveHBARule   RemoveHBARuleCommand   `cmd:"" name:"remove-hba-rule" help:"Remove a rule from pg_hba.conf and reload the configuration."`
+}
+
+type ListConfigFilesCommand struct {
+	Error  *bool   `name:"error" help:"Filter by entries which have an error"`
+	Offset uint64  `name:"offset" help:"Offset for pagination"`
+	Limit  *uint64 `name:"limit" help:"Limit for pagination"`
+}
+
+type ListHBARulesCommand struct {
+	ListConfigFilesCommand
+}
+
+type AddHBARuleCommand struct {
+	schema.HBARuleMeta
+	Before uint64 `name:"before" help:"Insert before this line, or append when zero"`
+}
+
+type RemoveHBARuleCommand struct {
+	Line uint64 `arg:"" name:"line" help:"Line number of the rule"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *ListConfigFilesCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List settings
+	settings, err := client.ListConfigFiles(ctx.ctx,
+		httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit),
+		httpclient.WithError(cmd.Error),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(settings)
+}
+
+func (cmd *ListHBARulesCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List rules
+	rules, err := client.ListHBARules(ctx.ctx,
+		httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit),
+		httpclient.WithError(cmd.Error),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(rules)
+}
+
+func (cmd *AddHBARuleCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Add rule
+	rules, err := client.AddHBARule(ctx.ctx, cmd.HBARuleMeta, cmd.Before)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(rules)
+}
+
+func (cmd *RemoveHBARuleCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Remove rule
+	rules, err := client.RemoveHBARule(ctx.ctx, cmd.Line)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(rules)
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	// Packages
 	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -14,12 +16,15 @@ type ConnectionCommands struct {
 	ListConnection   ListConnectionCommand   `cmd:"" name:"connections" help:"List connections."`
 	GetConnection    GetConnectionCommand    `cmd:"" name:"connection" help:"Get connection."`
 	DeleteConnection DeleteConnectionCommand `cmd:"" name:"delete-connection" help:"Delete (terminate) connection."`
+	WatchConnections WatchConnectionsCommand `cmd:"" name:"watch-connections" help:"Watch connections open, start queries, change state and close."`
+	LongRunning      LongRunningCommand      `cmd:"" name:"long-running" help:"List long running queries, or connections idle in a transaction."`
 }
 
 type ListConnectionCommand struct {
 	Database string `name:"database" help:"Filter by database name"`
 	Role     string `name:"role" help:"Filter by role name"`
 	State    string `name:"state" help:"Filter by state (active, idle, etc.)"`
+	After    string `name:"after" help:"Token for the next page"`
 }
 
 type GetConnectionCommand struct {
@@ -30,6 +35,17 @@ type DeleteConnectionCommand struct {
 	GetConnectionCommand
 }
 
+type WatchConnectionsCommand struct {
+	Database string        `name:"database" help:"Filter by database name"`
+	Role     string        `name:"role" help:"Filter by role name"`
+	Interval time.Duration `name:"interval" help:"Interval between polls" default:"1s"`
+}
+
+type LongRunningCommand struct {
+	Threshold time.Duration `name:"threshold" help:"Minimum duration" default:"1m"`
+	Idle      bool          `name:"idle" help:"List connections idle in a transaction, rather than running a query"`
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // COMMANDS
 
@@ -50,6 +66,9 @@ func (cmd *ListConnectionCommand) Run(ctx *Globals) error {
 	if cmd.State != "" {
 		opts = append(opts, httpclient.OptState(cmd.State))
 	}
+	if cmd.After != "" {
+		opts = append(opts, httpclient.WithAfter(cmd.After))
+	}
 
 	// List connections
 	connections, err := client.ListConnections(ctx.ctx, opts...)
@@ -58,8 +77,7 @@ func (cmd *ListConnectionCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(connections)
-	return nil
+	return ctx.Print(connections)
 }
 
 func (cmd *GetConnectionCommand) Run(ctx *Globals) error {
@@ -75,8 +93,7 @@ func (cmd *GetConnectionCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(connection)
-	return nil
+	return ctx.Print(connection)
 }
 
 func (cmd *DeleteConnectionCommand) Run(ctx *Globals) error {
@@ -93,3 +110,45 @@ func (cmd *DeleteConnectionCommand) Run(ctx *Globals) error {
 	// Return success
 	return nil
 }
+
+func (cmd *WatchConnectionsCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Build options
+	opts := []httpclient.Opt{httpclient.WithInterval(cmd.Interval)}
+	if cmd.Database != "" {
+		opts = append(opts, httpclient.OptDatabase(cmd.Database))
+	}
+	if cmd.Role != "" {
+		opts = append(opts, httpclient.OptRole(cmd.Role))
+	}
+
+	// Print each change until interrupted
+	return client.WatchConnections(ctx.ctx, func(event schema.ConnectionEvent) {
+		fmt.Println(event)
+	}, opts...)
+}
+
+func (cmd *LongRunningCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List the connections
+	var list *schema.LongRunningQueryList
+	if cmd.Idle {
+		list, err = client.ListIdleInTransaction(ctx.ctx, cmd.Threshold)
+	} else {
+		list, err = client.ListLongRunningQueries(ctx.ctx, cmd.Threshold)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(list)
+}
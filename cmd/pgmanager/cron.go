@@ -0,0 +1,129 @@
+package main
+
+import (
+	// Packages
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type CronCommands struct {
+	ListCronJobs    ListCronJobsCommand    `cmd:"" name:"cronjobs" help:"List pg_cron jobs."`
+	GetCronJob      GetCronJobCommand      `cmd:"" name:"cronjob" help:"Get pg_cron job."`
+	CreateCronJob   CreateCronJobCommand   `cmd:"" name:"create-cronjob" help:"Schedule a pg_cron job."`
+	DeleteCronJob   DeleteCronJobCommand   `cmd:"" name:"delete-cronjob" help:"Unschedule a pg_cron job."`
+	ListCronJobRuns ListCronJobRunsCommand `cmd:"" name:"cronruns" help:"List pg_cron job run history."`
+}
+
+type ListCronJobsCommand struct {
+	Database *string `name:"database" help:"Filter by database name"`
+	Username *string `name:"username" help:"Filter by role name"`
+	Offset   uint64  `name:"offset" help:"Offset for pagination"`
+	Limit    *uint64 `name:"limit" help:"Limit for pagination"`
+}
+
+type GetCronJobCommand struct {
+	Id uint64 `arg:"" name:"id" help:"Job identifier"`
+}
+
+type CreateCronJobCommand struct {
+	schema.CronJobMeta
+}
+
+type DeleteCronJobCommand struct {
+	GetCronJobCommand
+}
+
+type ListCronJobRunsCommand struct {
+	Job    *uint64 `name:"job" help:"Filter by job identifier"`
+	Status *string `name:"status" help:"Filter by status"`
+	Offset uint64  `name:"offset" help:"Offset for pagination"`
+	Limit  *uint64 `name:"limit" help:"Limit for pagination"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *ListCronJobsCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List jobs
+	jobs, err := client.ListCronJobs(ctx.ctx,
+		httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit),
+		httpclient.WithDatabase(cmd.Database),
+		httpclient.WithUsername(cmd.Username),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(jobs)
+}
+
+func (cmd *GetCronJobCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get job
+	job, err := client.GetCronJob(ctx.ctx, cmd.Id)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(job)
+}
+
+func (cmd *CreateCronJobCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Create job
+	job, err := client.CreateCronJob(ctx.ctx, cmd.CronJobMeta)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(job)
+}
+
+func (cmd *DeleteCronJobCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Delete job
+	return client.DeleteCronJob(ctx.ctx, cmd.Id)
+}
+
+func (cmd *ListCronJobRunsCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List job runs
+	runs, err := client.ListCronJobRuns(ctx.ctx,
+		httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit),
+		httpclient.WithJob(cmd.Job),
+		httpclient.WithStatus(cmd.Status),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(runs)
+}
@@ -15,6 +15,7 @@ type DatabaseCommands struct {
 	ListDatabase   ListDatabaseCommand   `cmd:"" name:"databases" help:"List databases."`
 	GetDatabase    GetDatabaseCommand    `cmd:"" name:"database" help:"Get database."`
 	CreateDatabase CreateDatabaseCommand `cmd:"" name:"create-database" help:"Create database."`
+	CloneDatabase  CloneDatabaseCommand  `cmd:"" name:"clone-database" help:"Copy a database to a new database."`
 	DeleteDatabase DeleteDatabaseCommand `cmd:"" name:"delete-database" help:"Delete database."`
 	UpdateDatabase UpdateDatabaseCommand `cmd:"" name:"update-database" help:"Update database."`
 }
@@ -34,8 +35,16 @@ type DeleteDatabaseCommand struct {
 
 type CreateDatabaseCommand struct {
 	GetDatabaseCommand
-	Owner string   `name:"owner" help:"Database owner"`
-	Acl   []string `name:"acl" help:"Access control list entries (format: role:priv,priv,... e.g. myuser:SELECT,INSERT)"`
+	Owner    string   `name:"owner" help:"Database owner"`
+	Template string   `name:"template" help:"Template database to copy"`
+	Acl      []string `name:"acl" help:"Access control list entries (format: role:priv,priv,... e.g. myuser:SELECT,INSERT)"`
+	Comment  *string  `name:"comment" help:"Database comment"`
+}
+
+type CloneDatabaseCommand struct {
+	GetDatabaseCommand
+	Target string `arg:"" name:"target" help:"Name of the new database"`
+	schema.DatabaseCloneRequest
 }
 
 type UpdateDatabaseCommand struct {
@@ -43,6 +52,7 @@ type UpdateDatabaseCommand struct {
 	NewName string   `name:"rename" help:"Rename database to this name"`
 	Owner   string   `name:"owner" help:"Database owner"`
 	Acl     []string `name:"acl" help:"Access control list entries (format: role:priv,priv,... e.g. myuser:SELECT,INSERT)"`
+	Comment *string  `name:"comment" help:"Database comment, or empty to remove the comment"`
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -61,8 +71,7 @@ func (cmd *ListDatabaseCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(databases)
-	return nil
+	return ctx.Print(databases)
 }
 
 func (cmd *GetDatabaseCommand) Run(ctx *Globals) error {
@@ -78,8 +87,7 @@ func (cmd *GetDatabaseCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(database)
-	return nil
+	return ctx.Print(database)
 }
 
 func (cmd *CreateDatabaseCommand) Run(ctx *Globals) error {
@@ -100,17 +108,34 @@ func (cmd *CreateDatabaseCommand) Run(ctx *Globals) error {
 
 	// Create database
 	database, err := client.CreateDatabase(ctx.ctx, schema.DatabaseMeta{
-		Name:  cmd.Name,
-		Owner: cmd.Owner,
-		Acl:   acl,
+		Name:     cmd.Name,
+		Owner:    cmd.Owner,
+		Template: cmd.Template,
+		Acl:      acl,
+		Comment:  cmd.Comment,
 	})
 	if err != nil {
 		return err
 	}
 
 	// Print
-	fmt.Println(database)
-	return nil
+	return ctx.Print(database)
+}
+
+func (cmd *CloneDatabaseCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Clone database
+	database, err := client.CloneDatabase(ctx.ctx, cmd.Name, cmd.Target, cmd.DatabaseCloneRequest)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(database)
 }
 
 func (cmd *DeleteDatabaseCommand) Run(ctx *Globals) error {
@@ -146,8 +171,9 @@ func (cmd *UpdateDatabaseCommand) Run(ctx *Globals) error {
 
 	// Build meta
 	meta := schema.DatabaseMeta{
-		Owner: cmd.Owner,
-		Acl:   acl,
+		Owner:   cmd.Owner,
+		Acl:     acl,
+		Comment: cmd.Comment,
 	}
 	if cmd.NewName != "" {
 		meta.Name = cmd.NewName
@@ -160,6 +186,5 @@ func (cmd *UpdateDatabaseCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(database)
-	return nil
+	return ctx.Print(database)
 }
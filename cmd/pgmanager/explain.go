@@ -0,0 +1,36 @@
+package main
+
+import (
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type ExplainCommands struct {
+	Explain ExplainCommand `cmd:"" name:"explain" help:"Explain a query."`
+}
+
+type ExplainCommand struct {
+	schema.ExplainRequest
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *ExplainCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Explain the query
+	explain, err := client.ExplainQuery(ctx.ctx, cmd.ExplainRequest)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(explain)
+}
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"fmt"
-
 	// Packages
 	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
@@ -82,8 +80,7 @@ func (cmd *ListExtensionCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(extensions)
-	return nil
+	return ctx.Print(extensions)
 }
 
 func (cmd *GetExtensionCommand) Run(ctx *Globals) error {
@@ -99,8 +96,7 @@ func (cmd *GetExtensionCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(extension)
-	return nil
+	return ctx.Print(extension)
 }
 
 func (cmd *CreateExtensionCommand) Run(ctx *Globals) error {
@@ -127,8 +123,7 @@ func (cmd *CreateExtensionCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(extension)
-	return nil
+	return ctx.Print(extension)
 }
 
 func (cmd *DeleteExtensionCommand) Run(ctx *Globals) error {
@@ -171,6 +166,5 @@ func (cmd *UpdateExtensionCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(extension)
-	return nil
+	return ctx.Print(extension)
 }
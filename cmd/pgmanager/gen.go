@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	gen "github.com/mutablelogic/go-pg/pkg/gen"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type GenCommands struct {
+	Gen GenCommand `cmd:"" name:"gen" help:"Generate Go types, readers, writers and selectors for tables."`
+}
+
+type GenCommand struct {
+	Database string   `arg:"" name:"database" help:"Database name"`
+	Tables   []string `arg:"" name:"table" help:"Table, view or materialized view as schema.name"`
+	Package  string   `name:"package" short:"p" default:"model" help:"Package name for the generated code"`
+	Out      string   `name:"out" short:"o" type:"path" help:"Output file (default is standard output)"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *GenCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the columns for each table
+	tables := make([]gen.Table, 0, len(cmd.Tables))
+	for _, table := range cmd.Tables {
+		namespace, name, ok := strings.Cut(table, ".")
+		if !ok {
+			return pg.ErrBadParameter.Withf("expected schema.name, got %q", table)
+		}
+		columns, err := client.ListColumns(ctx.ctx, cmd.Database, namespace, name)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, gen.Table{Schema: namespace, Name: name, Columns: columns.Body})
+	}
+
+	// Write to standard output
+	if cmd.Out == "" {
+		return gen.Generate(os.Stdout, cmd.Package, tables...)
+	}
+
+	// Write to a file
+	w, err := os.Create(cmd.Out)
+	if err != nil {
+		return err
+	}
+	if err := gen.Generate(w, cmd.Package, tables...); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
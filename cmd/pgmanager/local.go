@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	httphandler "github.com/mutablelogic/go-pg/pkg/manager/httphandler"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// pipeListener is a listener for connections opened within the process, so
+// that a command connected directly to a database is served by the same
+// handlers as the server, without listening on the network
+type pipeListener struct {
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+type pipeAddr struct{}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	pipeNetwork = "pipe"
+	pipeHost    = "pgmanager"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Accept waits for a connection opened with Dial
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close stops accepting connections
+func (l *pipeListener) Close() error {
+	l.once.Do(func() {
+		close(l.done)
+	})
+	return nil
+}
+
+// Addr returns the address of the listener
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+// Dial opens a connection to the listener, ignoring the network and address
+func (l *pipeListener) Dial(ctx context.Context, _, _ string) (net.Conn, error) {
+	server, client := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (pipeAddr) Network() string {
+	return pipeNetwork
+}
+
+func (pipeAddr) String() string {
+	return pipeHost
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Connect to the database with the URL, and serve the API within the process.
+// Returns the endpoint and the function which opens connections to it. The
+// server and the connection pool are closed by Close.
+func (g *Globals) serve() (string, httpclient.DialFunc, error) {
+	opts := []pg.Opt{
+		pg.WithURL(g.URL),
+	}
+	if g.Debug {
+		opts = append(opts, pg.WithTrace(func(ctx context.Context, query string, args any, err error) {
+			fmt.Println("PG TRACE:", query, args, err)
+		}))
+	}
+
+	// Create a pool connection, and ping the database
+	conn, err := pg.NewPool(g.ctx, opts...)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := conn.Ping(g.ctx); err != nil {
+		conn.Close()
+		return "", nil, err
+	}
+
+	// Create the manager, and register the handlers
+	manager, err := manager.New(g.ctx, conn)
+	if err != nil {
+		conn.Close()
+		return "", nil, err
+	}
+	router := http.NewServeMux()
+	httphandler.RegisterHandlers(router, g.HTTP.Prefix, manager, httphandler.Options{})
+
+	// Promotion is allowed, since the connection has the credentials of the
+	// database rather than of the API
+	httphandler.RegisterRecoveryHandlers(router, g.HTTP.Prefix, manager)
+
+	// Serve requests until closed
+	listener := newPipeListener()
+	server := &http.Server{Handler: router}
+	go server.Serve(listener)
+	g.close = func() {
+		server.Close()
+		conn.Close()
+	}
+
+	// Return the endpoint
+	return "http://" + pipeHost + g.HTTP.Prefix, listener.Dial, nil
+}
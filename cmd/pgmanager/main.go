@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
 	// Packages
 	kong "github.com/alecthomas/kong"
@@ -21,6 +23,19 @@ type Globals struct {
 	// Debug option
 	Debug bool `name:"debug" help:"Enable debug logging"`
 
+	// Format of responses
+	Output string `name:"output" enum:"table,json,yaml,csv" help:"Output format (table, json, yaml, csv)" default:"table"`
+
+	// Database to connect to directly, instead of the server
+	URL string `name:"url" env:"PG_URL" help:"Database URL, such as postgres://localhost/postgres, to connect to directly instead of the server"`
+
+	// Credentials for servers which require them
+	Token string `name:"token" env:"PG_TOKEN" help:"Bearer token to send with each request"`
+
+	// Requests which fail with a transient error from a proxy are repeated
+	Retry        uint          `name:"retry" help:"Number of times to repeat a request after a 502, 503 or 504 response" default:"3"`
+	RetryBackoff time.Duration `name:"retry-backoff" help:"Delay before a request is repeated, which doubles for each further repeat" default:"250ms"`
+
 	// HTTP server options
 	HTTP struct {
 		Prefix string `name:"prefix" help:"HTTP path prefix" default:"/api/v1"`
@@ -30,21 +45,39 @@ type Globals struct {
 	// Private fields
 	ctx    context.Context
 	cancel context.CancelFunc
+	close  func()
 }
 
 type CLI struct {
 	Globals
+	AlertRuleCommands
+	AuditCommands
+	BackupCommands
+	CommentCommands
+	CompareCommands
+	ConfigCommands
 	ConnectionCommands
+	CronCommands
 	DatabaseCommands
+	ExplainCommands
 	ExtensionCommands
+	GenCommands
+	MaintenanceCommands
+	NotifyCommands
+	ProvisionCommands
+	RecoveryCommands
+	ReplicationCommands
 	ReplicationSlotCommands
 	RoleCommands
 	SchemaCommands
 	ObjectCommands
+	PlanCommands
+	QueryCommands
 	ServerCommands
 	SettingCommands
 	StatementCommands
 	TablespaceCommands
+	TopCommands
 	VersionCommands
 }
 
@@ -56,11 +89,13 @@ func main() {
 	ctx := kong.Parse(cli)
 
 	// Create the context and cancel function
-	cli.Globals.ctx, cli.Globals.cancel = signal.NotifyContext(context.Background(), os.Interrupt)
+	cli.Globals.ctx, cli.Globals.cancel = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cli.Globals.cancel()
 
 	// Call the Run() method of the selected parsed command.
-	if err := ctx.Run(&cli.Globals); err != nil {
+	err := ctx.Run(&cli.Globals)
+	cli.Globals.Close()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
@@ -70,6 +105,27 @@ func main() {
 // PRIVATE METHODS
 
 func (g *Globals) Client() (*httpclient.Client, error) {
+	// Client options
+	opts := []client.ClientOpt{}
+	if g.Debug {
+		opts = append(opts, client.OptTrace(os.Stderr, true))
+	}
+	if g.Token != "" {
+		opts = append(opts, httpclient.WithToken(g.Token))
+	}
+	if g.Retry > 0 {
+		opts = append(opts, httpclient.WithRetry(g.Retry, g.RetryBackoff))
+	}
+
+	// Serve the API within the process when connecting to a database directly
+	if g.URL != "" {
+		endpoint, dial, err := g.serve()
+		if err != nil {
+			return nil, err
+		}
+		return httpclient.New(endpoint, append([]client.ClientOpt{httpclient.WithDial(dial)}, opts...)...)
+	}
+
 	scheme := "http"
 	host, port, err := net.SplitHostPort(g.HTTP.Addr)
 	if err != nil {
@@ -90,12 +146,19 @@ func (g *Globals) Client() (*httpclient.Client, error) {
 		scheme = "https"
 	}
 
-	// Client options
-	opts := []client.ClientOpt{}
-	if g.Debug {
-		opts = append(opts, client.OptTrace(os.Stderr, true))
-	}
-
 	// Create a client with the calculated endpoint
 	return httpclient.New(fmt.Sprintf("%s://%s:%v%s", scheme, host, portn, g.HTTP.Prefix), opts...)
 }
+
+// Print writes a response to standard output, in the format set by the
+// --output flag
+func (g *Globals) Print(v any) error {
+	return printer{os.Stdout, g.Output}.Print(v)
+}
+
+// Close the database connection, when the command connected directly
+func (g *Globals) Close() {
+	if g.close != nil {
+		g.close()
+	}
+}
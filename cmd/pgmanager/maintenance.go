@@ -0,0 +1,29 @@
+package main
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type MaintenanceCommands struct {
+	GetMaintenance GetMaintenanceCommand `cmd:"" name:"maintenance" help:"Get maintenance windows, and the reindexes and backups which are queued or have recently run."`
+}
+
+type GetMaintenanceCommand struct{}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *GetMaintenanceCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the maintenance windows and tasks
+	maintenance, err := client.GetMaintenance(ctx.ctx)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(maintenance)
+}
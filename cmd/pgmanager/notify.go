@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type NotifyCommands struct {
+	Subscribe SubscribeCommand `cmd:"" name:"subscribe" help:"Print notifications on a channel until interrupted."`
+}
+
+type SubscribeCommand struct {
+	Channel string `arg:"" name:"channel" help:"Channel name"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *SubscribeCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Print each notification
+	return client.Subscribe(ctx.ctx, cmd.Channel, func(notification schema.Notification) error {
+		fmt.Println(notification)
+		return nil
+	})
+}
@@ -2,17 +2,29 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	// Packages
 	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
 // TYPES
 
 type ObjectCommands struct {
-	ListObjects ListObjectsCommand `cmd:"" name:"objects" help:"List objects."`
-	GetObject   GetObjectCommand   `cmd:"" name:"object" help:"Get object."`
+	ListObjects    ListObjectsCommand    `cmd:"" name:"objects" help:"List objects."`
+	GetObject      GetObjectCommand      `cmd:"" name:"object" help:"Get object."`
+	ObjectDDL      ObjectDDLCommand      `cmd:"" name:"object-ddl" help:"Get the statements which create a table, view, sequence, index or function."`
+	ExportObject   ExportObjectCommand   `cmd:"" name:"export-object" help:"Export the rows of a table or view as newline-delimited JSON."`
+	ReindexObject  ReindexObjectCommand  `cmd:"" name:"reindex-object" help:"Rebuild an index, or the indexes of a table."`
+	VerifyObject   VerifyObjectCommand   `cmd:"" name:"verify-object" help:"Check a table, materialized view or btree index for corruption with amcheck."`
+	ColumnStats    ColumnStatsCommand    `cmd:"" name:"column-stats" help:"Get the planner statistics and statistics targets of the columns of a table."`
+	SetStatsTarget SetStatsTargetCommand `cmd:"" name:"set-statistics-target" help:"Set the statistics target of a column, or reset it to the default."`
+	StaleTables    StaleTablesCommand    `cmd:"" name:"stale-tables" help:"List tables with no reads or writes, which are candidates for archiving."`
+	Bloat          BloatCommand          `cmd:"" name:"bloat" help:"Estimate the bloat of tables and indexes, and the growth of each database."`
+	IOStats        IOStatsCommand        `cmd:"" name:"iostats" help:"List the cache hit ratios of tables and indexes."`
 }
 
 type ListObjectsCommand struct {
@@ -21,6 +33,31 @@ type ListObjectsCommand struct {
 	Type      string  `name:"type" short:"t" help:"Filter by object type (TABLE, VIEW, INDEX, SEQUENCE, etc.)"`
 	Offset    uint64  `name:"offset" help:"Offset for pagination"`
 	Limit     *uint64 `name:"limit" help:"Limit for pagination"`
+	After     string  `name:"after" help:"Token for the next page"`
+}
+
+type StaleTablesCommand struct {
+	Database  string  `name:"database" short:"d" help:"Filter by database name"`
+	Namespace *string `name:"schema" short:"s" help:"Filter by schema (namespace) name"`
+	Days      *uint64 `name:"days" help:"Days without autovacuum or autoanalyze activity (default 30)"`
+	Offset    uint64  `name:"offset" help:"Offset for pagination"`
+	Limit     *uint64 `name:"limit" help:"Limit for pagination"`
+}
+
+type BloatCommand struct {
+	Database  string  `name:"database" short:"d" help:"Filter by database name"`
+	Namespace *string `name:"schema" short:"s" help:"Filter by schema (namespace) name"`
+	Type      *string `name:"type" short:"t" help:"Filter by type (TABLE or INDEX)"`
+	Offset    uint64  `name:"offset" help:"Offset for pagination"`
+	Limit     *uint64 `name:"limit" help:"Limit for pagination"`
+}
+
+type IOStatsCommand struct {
+	Database  string  `name:"database" short:"d" help:"Filter by database name"`
+	Namespace *string `name:"schema" short:"s" help:"Filter by schema (namespace) name"`
+	Type      *string `name:"type" short:"t" help:"Filter by type (TABLE or INDEX)"`
+	Offset    uint64  `name:"offset" help:"Offset for pagination"`
+	Limit     *uint64 `name:"limit" help:"Limit for pagination"`
 }
 
 type GetObjectCommand struct {
@@ -29,6 +66,36 @@ type GetObjectCommand struct {
 	Name      string `arg:"" name:"name" help:"Object name"`
 }
 
+type ObjectDDLCommand struct {
+	GetObjectCommand
+}
+
+type ExportObjectCommand struct {
+	GetObjectCommand
+	Out string `name:"out" short:"o" type:"path" help:"Output file (defaults to stdout)"`
+}
+
+type ColumnStatsCommand struct {
+	GetObjectCommand
+}
+
+type SetStatsTargetCommand struct {
+	GetObjectCommand
+	schema.ColumnStatisticsMeta
+}
+
+type VerifyObjectCommand struct {
+	GetObjectCommand
+	schema.ObjectVerifyRequest
+}
+
+type ReindexObjectCommand struct {
+	GetObjectCommand
+	Concurrently bool `name:"concurrently" help:"Rebuild without locking out writes"`
+	Queue        bool `name:"queue" help:"Queue until the next maintenance window opens"`
+	Override     bool `name:"override" help:"Rebuild now, outside of the maintenance windows"`
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // COMMANDS
 
@@ -39,29 +106,24 @@ func (cmd *ListObjectsCommand) Run(ctx *Globals) error {
 	}
 
 	// List objects
-	objects, err := client.ListObjects(ctx.ctx, cmd.Database, cmd.Namespace, httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit))
+	objects, err := client.ListObjects(ctx.ctx, cmd.Database, cmd.Namespace, httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit), httpclient.WithAfter(cmd.After))
 	if err != nil {
 		return err
 	}
 
 	// Filter by type if specified (client-side filtering since API may not support it in path)
 	if cmd.Type != "" {
-		var filtered []interface{}
+		filtered := make([]schema.Object, 0, len(objects.Body))
 		for _, obj := range objects.Body {
 			if obj.Type == cmd.Type {
 				filtered = append(filtered, obj)
 			}
 		}
-		fmt.Printf("Count: %d (filtered from %d)\n", len(filtered), objects.Count)
-		for _, obj := range filtered {
-			fmt.Println(obj)
-		}
-		return nil
+		objects.Body = filtered
 	}
 
 	// Print
-	fmt.Println(objects)
-	return nil
+	return ctx.Print(objects)
 }
 
 func (cmd *GetObjectCommand) Run(ctx *Globals) error {
@@ -77,6 +139,178 @@ func (cmd *GetObjectCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(obj)
-	return nil
+	return ctx.Print(obj)
+}
+
+func (cmd *ObjectDDLCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the statements
+	ddl, err := client.GetObjectDDL(ctx.ctx, cmd.Database, cmd.Namespace, cmd.Name)
+	if err != nil {
+		return err
+	}
+
+	// Print the statements as a script, which would be truncated in a table
+	if ctx.Output == outputTable {
+		for _, statement := range ddl.Statements {
+			fmt.Print(statement, ";\n\n")
+		}
+		return nil
+	}
+
+	// Print
+	return ctx.Print(ddl)
+}
+
+func (cmd *ExportObjectCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Set the output
+	var w io.Writer = os.Stdout
+	if cmd.Out != "" {
+		f, err := os.Create(cmd.Out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	// Export the rows
+	return client.ExportObject(ctx.ctx, w, cmd.Database, cmd.Namespace, cmd.Name)
+}
+
+func (cmd *ColumnStatsCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the column statistics
+	stats, err := client.GetColumnStatistics(ctx.ctx, cmd.Database, cmd.Namespace, cmd.Name)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(stats)
+}
+
+func (cmd *SetStatsTargetCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Set the statistics target
+	stats, err := client.SetStatisticsTarget(ctx.ctx, cmd.Database, cmd.Namespace, cmd.Name, cmd.ColumnStatisticsMeta)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(stats)
+}
+
+func (cmd *ReindexObjectCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Queue the reindex until the next maintenance window opens
+	if cmd.Queue {
+		task, err := client.QueueMaintenance(ctx.ctx, schema.MaintenanceTaskMeta{
+			Operation:    schema.MaintenanceReindex,
+			Database:     cmd.Database,
+			Schema:       cmd.Namespace,
+			Name:         cmd.Name,
+			Concurrently: cmd.Concurrently,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(task)
+		return nil
+	}
+
+	// Reindex the object
+	obj, err := client.ReindexObject(ctx.ctx, cmd.Database, cmd.Namespace, cmd.Name, httpclient.WithConcurrently(cmd.Concurrently), httpclient.WithOverride(cmd.Override))
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(obj)
+}
+
+func (cmd *VerifyObjectCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Check the object for corruption
+	verification, err := client.VerifyObject(ctx.ctx, cmd.Database, cmd.Namespace, cmd.Name, cmd.ObjectVerifyRequest)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(verification)
+}
+
+func (cmd *StaleTablesCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List stale tables
+	tables, err := client.ListStaleTables(ctx.ctx, cmd.Database, httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit), httpclient.WithSchema(cmd.Namespace), httpclient.WithDays(cmd.Days))
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(tables)
+}
+
+func (cmd *BloatCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the bloat report
+	report, err := client.GetBloatReport(ctx.ctx, cmd.Database, httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit), httpclient.WithSchema(cmd.Namespace), httpclient.WithType(cmd.Type))
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(report)
+}
+
+func (cmd *IOStatsCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List the IO statistics
+	stats, err := client.GetIOStats(ctx.ctx, cmd.Database, httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit), httpclient.WithSchema(cmd.Namespace), httpclient.WithType(cmd.Type))
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(stats)
 }
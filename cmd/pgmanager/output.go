@@ -0,0 +1,272 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	// Packages
+	yaml "gopkg.in/yaml.v3"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// printer writes responses to the output in a format
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// tabler is a response which is written as a table with its own columns,
+// rather than with a column for each field
+type tabler interface {
+	Table() ([]string, [][]string)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputCSV   = "csv"
+)
+
+const (
+	// Field of a list response with the items
+	outputBody = "body"
+
+	// Maximum width of a value in a table, after which it is truncated
+	outputMaxWidth = 60
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Print writes a response in the format set by the --output flag. A list
+// response is written as one row for each item, and any other response as a
+// single row, or as a field and value for each field in a table
+func (p printer) Print(v any) error {
+	if p.format == outputJSON {
+		return p.json(v)
+	} else if t, ok := v.(tabler); ok && p.format != outputYAML {
+		columns, rows := t.Table()
+		if p.format == outputCSV {
+			return p.csv(columns, rows)
+		}
+		return p.table(columns, rows)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// JSON is YAML, so decode the response keeping the order of the fields
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	node := &doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	switch p.format {
+	case outputYAML:
+		return p.yaml(node)
+	case outputCSV:
+		columns, rows := table(node)
+		return p.csv(columns, rows)
+	default:
+		if node.Kind == yaml.MappingNode && field(node, outputBody) == nil {
+			return p.fields(node)
+		}
+		columns, rows := table(node)
+		return p.table(columns, rows)
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (p printer) json(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(data))
+	return err
+}
+
+func (p printer) yaml(node *yaml.Node) error {
+	block(node)
+	enc := yaml.NewEncoder(p.w)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func (p printer) csv(columns []string, rows [][]string) error {
+	w := csv.NewWriter(p.w)
+	if err := w.Write(columns); err != nil {
+		return err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+func (p printer) table(columns []string, rows [][]string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	w := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		for i := range row {
+			row[i] = truncate(row[i])
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func (p printer) fields(node *yaml.Node) error {
+	w := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if value := cell(node.Content[i+1]); value != "" {
+			fmt.Fprintf(w, "%s\t%s\n", strings.ToUpper(node.Content[i].Value), truncate(value))
+		}
+	}
+	return w.Flush()
+}
+
+// Return the columns and rows of a response. The rows are the items of a
+// list response or array, or the response itself, and the columns are the
+// fields of the rows in the order they first appear
+func table(node *yaml.Node) ([]string, [][]string) {
+	var items []*yaml.Node
+	switch {
+	case node.Kind == yaml.SequenceNode:
+		items = node.Content
+	case node.Kind == yaml.MappingNode && field(node, outputBody) != nil:
+		items = field(node, outputBody).Content
+	case node.Kind == yaml.MappingNode:
+		items = []*yaml.Node{node}
+	default:
+		return []string{"value"}, [][]string{{cell(node)}}
+	}
+
+	// Determine the columns
+	var columns []string
+	index := make(map[string]int)
+	for _, item := range items {
+		for i := 0; item.Kind == yaml.MappingNode && i+1 < len(item.Content); i += 2 {
+			if key := item.Content[i].Value; !contains(index, key) {
+				index[key] = len(columns)
+				columns = append(columns, key)
+			}
+		}
+	}
+	if len(columns) == 0 && len(items) > 0 {
+		columns, index = []string{"value"}, nil
+	}
+
+	// Determine the rows
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		row := make([]string, len(columns))
+		if item.Kind != yaml.MappingNode {
+			row[0] = cell(item)
+		}
+		for i := 0; item.Kind == yaml.MappingNode && i+1 < len(item.Content); i += 2 {
+			row[index[item.Content[i].Value]] = cell(item.Content[i+1])
+		}
+		rows = append(rows, row)
+	}
+	return columns, rows
+}
+
+// Return the value of a field of a mapping, or nil
+func field(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// Return a value as the text of a cell. Arrays of values are separated by
+// commas, and objects are written as JSON
+func cell(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return ""
+		}
+		return node.Value
+	case yaml.SequenceNode:
+		values := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return compact(node)
+			}
+			values = append(values, cell(item))
+		}
+		return strings.Join(values, ",")
+	default:
+		return compact(node)
+	}
+}
+
+// Return a value as JSON on a single line
+func compact(node *yaml.Node) string {
+	var v any
+	if err := node.Decode(&v); err != nil {
+		return err.Error()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+// Set the style of a node and its children to block style, since the nodes
+// decoded from JSON use flow style
+func block(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		node.Style &^= yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		block(child)
+	}
+}
+
+// Return a value on a single line, truncated to the maximum width
+func truncate(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if r := []rune(value); len(r) > outputMaxWidth {
+		return string(r[:outputMaxWidth-1]) + "…"
+	}
+	return value
+}
+
+func contains(index map[string]int, key string) bool {
+	_, exists := index[key]
+	return exists
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type PlanCommands struct {
+	Plan PlanCommand `cmd:"" name:"plan" help:"Check plan files written by diff-schemas with --plan-file."`
+}
+
+type PlanCommand struct {
+	Verify PlanVerifyCommand `cmd:"" name:"verify" help:"Check a plan file has not been modified, and its statements still migrate the database."`
+}
+
+type PlanVerifyCommand struct {
+	File string `arg:"" name:"file" type:"existingfile" help:"Plan file written by diff-schemas with --plan-file"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *PlanVerifyCommand) Run(ctx *Globals) error {
+	plan, err := readPlan(cmd.File)
+	if err != nil {
+		return err
+	}
+
+	// Compare the databases again
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+	diff, err := client.DiffSchemas(ctx.ctx, plan.Database, plan.Other, plan.SchemaDiffRequest)
+	if err != nil {
+		return err
+	}
+
+	// Check the plan against the current differences
+	if err := plan.Verify(*diff); err != nil {
+		return err
+	}
+
+	// Print
+	fmt.Fprintf(os.Stderr, "Plan %s verified with %d statement(s)\n", cmd.File, len(plan.Statements))
+	return ctx.Print(plan)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Write a plan file, replacing any existing file
+func writePlan(path string, plan schema.SchemaPlan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Read a plan file, refusing fields which are not part of a plan
+func readPlan(path string) (*schema.SchemaPlan, error) {
+	r, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var plan schema.SchemaPlan
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&plan); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &plan, nil
+}
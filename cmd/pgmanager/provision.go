@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	// Packages
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type ProvisionCommands struct {
+	Provision ProvisionCommand `cmd:"" name:"provision" help:"Provision resources in one step."`
+}
+
+type ProvisionCommand struct {
+	Tenant     ProvisionTenantCommand `cmd:"" name:"tenant" help:"Create a database with schemas and extensions, dropping it again if any step fails."`
+	DropTenant DropTenantCommand      `cmd:"" name:"drop-tenant" help:"Drop a tenant database, with its schemas and extensions."`
+}
+
+type ProvisionTenantCommand struct {
+	Database   string   `name:"database" required:"" help:"Database name"`
+	Owner      string   `name:"owner" required:"" help:"Owner of the database and schemas, which needs to exist"`
+	Template   string   `name:"template" help:"Template database to copy"`
+	Schemas    []string `name:"schemas" help:"Schemas to create in the database"`
+	Extensions []string `name:"extensions" help:"Extensions to install in the database"`
+	Cascade    bool     `name:"cascade" help:"Install the extensions which the extensions depend on"`
+}
+
+type DropTenantCommand struct {
+	Database string `name:"database" required:"" help:"Database name"`
+	Force    bool   `name:"force" help:"Drop the database even if there are connections to it"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *ProvisionTenantCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Check the owner exists before anything is created
+	if _, err := client.GetRole(ctx.ctx, cmd.Owner); err != nil {
+		return fmt.Errorf("owner %q: %w", cmd.Owner, err)
+	}
+
+	// Create the database
+	database, err := client.CreateDatabase(ctx.ctx, schema.DatabaseMeta{
+		Name:     cmd.Database,
+		Owner:    cmd.Owner,
+		Template: cmd.Template,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create the schemas and extensions, and drop the database if any fail so
+	// that nothing is left half-provisioned
+	schemas, extensions, err := cmd.provision(ctx.ctx, client)
+	if err != nil {
+		dropErr := client.DeleteDatabase(context.WithoutCancel(ctx.ctx), cmd.Database, httpclient.WithForce(true))
+		if dropErr != nil {
+			dropErr = fmt.Errorf("drop database %q: %w", cmd.Database, dropErr)
+		}
+		return errors.Join(err, dropErr)
+	}
+
+	// Print
+	if err := ctx.Print(database); err != nil {
+		return err
+	}
+	if err := ctx.Print(schemas); err != nil {
+		return err
+	}
+	return ctx.Print(extensions)
+}
+
+func (cmd *DropTenantCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Dropping the database drops its schemas and extensions
+	return client.DeleteDatabase(ctx.ctx, cmd.Database, httpclient.WithForce(cmd.Force))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Create the schemas and extensions in the provisioned database
+func (cmd *ProvisionTenantCommand) provision(ctx context.Context, client *httpclient.Client) ([]*schema.Schema, []*schema.Extension, error) {
+	schemas := make([]*schema.Schema, 0, len(cmd.Schemas))
+	for _, name := range cmd.Schemas {
+		namespace, err := client.CreateSchema(ctx, cmd.Database, schema.SchemaMeta{
+			Name:  name,
+			Owner: cmd.Owner,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("schema %q: %w", name, err)
+		}
+		schemas = append(schemas, namespace)
+	}
+
+	extensions := make([]*schema.Extension, 0, len(cmd.Extensions))
+	for _, name := range cmd.Extensions {
+		extension, err := client.CreateExtension(ctx, schema.ExtensionMeta{
+			Name:     name,
+			Database: cmd.Database,
+		}, httpclient.OptCascade(cmd.Cascade))
+		if err != nil {
+			return nil, nil, fmt.Errorf("extension %q: %w", name, err)
+		}
+		extensions = append(extensions, extension)
+	}
+
+	return schemas, extensions, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type QueryCommands struct {
+	Query QueryCommand `cmd:"" name:"query" help:"Run a read-only query."`
+}
+
+type QueryCommand struct {
+	schema.QueryRequest
+}
+
+// queryResult is a query result which is written with the columns of the
+// query in a table
+type queryResult schema.QueryResult
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *QueryCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Run the query
+	result, err := client.Query(ctx.ctx, cmd.QueryRequest)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print((*queryResult)(result))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Table returns the columns and rows of the result
+func (r *queryResult) Table() ([]string, [][]string) {
+	columns := make([]string, len(r.Columns))
+	for i, column := range r.Columns {
+		columns[i] = column.Name
+	}
+	rows := make([][]string, len(r.Rows))
+	for i, row := range r.Rows {
+		rows[i] = make([]string, len(row))
+		for j, value := range row {
+			switch value := value.(type) {
+			case nil:
+			case string:
+				rows[i][j] = value
+			case map[string]any, []any:
+				data, _ := json.Marshal(value)
+				rows[i][j] = string(data)
+			default:
+				rows[i][j] = fmt.Sprint(value)
+			}
+		}
+	}
+	return columns, rows
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type RecoveryCommands struct {
+	Recovery RecoveryCommand `cmd:"" name:"recovery" help:"Get whether the server is a standby, with the positions of the WAL it has received and replayed."`
+	Promote  PromoteCommand  `cmd:"" name:"promote" help:"Promote the standby to a primary."`
+}
+
+type RecoveryCommand struct{}
+
+type PromoteCommand struct {
+	schema.PromoteRequest
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *RecoveryCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the recovery status
+	status, err := client.GetRecoveryStatus(ctx.ctx)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(status)
+}
+
+func (cmd *PromoteCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Promote the standby
+	status, err := client.PromoteStandby(ctx.ctx, cmd.PromoteRequest)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(status)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type ReplicationCommands struct {
+	ReplicationStatus ReplicationStatusCommand `cmd:"" name:"replication-status" help:"Get the standbys which stream from the server, and the WAL receiver when the server is a standby."`
+}
+
+type ReplicationStatusCommand struct{}
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *ReplicationStatusCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the replication status
+	status, err := client.GetReplicationStatus(ctx.ctx)
+	if err != nil {
+		return err
+	}
+
+	// Print the WAL receiver and the standbys as separate tables
+	if ctx.Output == outputTable {
+		if status.Receiver != nil {
+			if err := ctx.Print(status.Receiver); err != nil {
+				return err
+			}
+		}
+		return ctx.Print(schema.ReplicationStandbyList{Count: uint64(len(status.Standbys)), Body: status.Standbys})
+	}
+
+	// Print
+	return ctx.Print(status)
+}
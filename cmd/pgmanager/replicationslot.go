@@ -1,8 +1,6 @@
 package main
 
 import (
-	"fmt"
-
 	// Packages
 	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
@@ -56,8 +54,7 @@ func (cmd *ListReplicationSlotCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(slots)
-	return nil
+	return ctx.Print(slots)
 }
 
 func (cmd *GetReplicationSlotCommand) Run(ctx *Globals) error {
@@ -73,8 +70,7 @@ func (cmd *GetReplicationSlotCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(slot)
-	return nil
+	return ctx.Print(slot)
 }
 
 func (cmd *CreateReplicationSlotCommand) Run(ctx *Globals) error {
@@ -97,8 +93,7 @@ func (cmd *CreateReplicationSlotCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(slot)
-	return nil
+	return ctx.Print(slot)
 }
 
 func (cmd *DeleteReplicationSlotCommand) Run(ctx *Globals) error {
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"fmt"
-
 	// Packages
 	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
@@ -51,6 +49,7 @@ type CreateRoleCommand struct {
 	ConnectionLimit *uint64  `name:"connection-limit" help:"Connection limit (-1 for unlimited)"`
 	Password        string   `name:"password" help:"Role password"`
 	Groups          []string `name:"memberof" help:"Group memberships (role names)"`
+	Comment         *string  `name:"comment" help:"Role comment"`
 }
 
 type UpdateRoleCommand struct {
@@ -66,6 +65,7 @@ type UpdateRoleCommand struct {
 	ConnectionLimit *uint64  `name:"connection-limit" help:"Connection limit (-1 for unlimited)"`
 	Password        string   `name:"password" help:"Role password"`
 	Groups          []string `name:"memberof" help:"Group memberships (role names)"`
+	Comment         *string  `name:"comment" help:"Role comment, or empty to remove the comment"`
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -84,8 +84,7 @@ func (cmd *ListRoleCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(roles)
-	return nil
+	return ctx.Print(roles)
 }
 
 func (cmd *GetRoleCommand) Run(ctx *Globals) error {
@@ -101,8 +100,7 @@ func (cmd *GetRoleCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(role)
-	return nil
+	return ctx.Print(role)
 }
 
 func (cmd *CreateRoleCommand) Run(ctx *Globals) error {
@@ -113,8 +111,9 @@ func (cmd *CreateRoleCommand) Run(ctx *Globals) error {
 
 	// Build role meta
 	meta := schema.RoleMeta{
-		Name:   cmd.Name,
-		Groups: cmd.Groups,
+		Name:    cmd.Name,
+		Groups:  cmd.Groups,
+		Comment: cmd.Comment,
 	}
 
 	// Handle boolean flags with explicit true/false
@@ -181,8 +180,7 @@ func (cmd *CreateRoleCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(role)
-	return nil
+	return ctx.Print(role)
 }
 
 func (cmd *DeleteRoleCommand) Run(ctx *Globals) error {
@@ -208,7 +206,8 @@ func (cmd *UpdateRoleCommand) Run(ctx *Globals) error {
 
 	// Build role meta
 	meta := schema.RoleMeta{
-		Groups: cmd.Groups,
+		Groups:  cmd.Groups,
+		Comment: cmd.Comment,
 	}
 	if cmd.NewName != "" {
 		meta.Name = cmd.NewName
@@ -248,6 +247,5 @@ func (cmd *UpdateRoleCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(role)
-	return nil
+	return ctx.Print(role)
 }
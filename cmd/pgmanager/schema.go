@@ -13,6 +13,7 @@ import (
 
 type SchemaCommands struct {
 	ListSchema   ListSchemaCommand   `cmd:"" name:"schemas" help:"List schemas."`
+	SchemaSizes  SchemaSizesCommand  `cmd:"" name:"schema-sizes" help:"List schema sizes."`
 	GetSchema    GetSchemaCommand    `cmd:"" name:"schema" help:"Get schema."`
 	CreateSchema CreateSchemaCommand `cmd:"" name:"create-schema" help:"Create schema."`
 	DeleteSchema DeleteSchemaCommand `cmd:"" name:"delete-schema" help:"Delete schema."`
@@ -25,6 +26,10 @@ type ListSchemaCommand struct {
 	Limit    *uint64 `name:"limit" help:"Limit for pagination"`
 }
 
+type SchemaSizesCommand struct {
+	ListSchemaCommand
+}
+
 type GetSchemaCommand struct {
 	Database  string `arg:"" name:"database" help:"Database name"`
 	Namespace string `arg:"" name:"namespace" help:"Schema (namespace) name"`
@@ -40,6 +45,7 @@ type CreateSchemaCommand struct {
 	Name     string   `arg:"" name:"name" help:"Schema name"`
 	Owner    string   `name:"owner" help:"Schema owner (defaults to current user)"`
 	Acl      []string `name:"acl" help:"Access control list entries (format: role:priv,priv,... e.g. myuser:USAGE,CREATE)"`
+	Comment  *string  `name:"comment" help:"Schema comment"`
 }
 
 type UpdateSchemaCommand struct {
@@ -47,6 +53,7 @@ type UpdateSchemaCommand struct {
 	NewName string   `name:"rename" help:"Rename schema to this name"`
 	Owner   string   `name:"owner" help:"Schema owner"`
 	Acl     []string `name:"acl" help:"Access control list entries (format: role:priv,priv,... e.g. myuser:USAGE,CREATE)"`
+	Comment *string  `name:"comment" help:"Schema comment, or empty to remove the comment"`
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -65,8 +72,23 @@ func (cmd *ListSchemaCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(schemas)
-	return nil
+	return ctx.Print(schemas)
+}
+
+func (cmd *SchemaSizesCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List schema sizes
+	sizes, err := client.ListSchemaSizes(ctx.ctx, cmd.Database, httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit))
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(sizes)
 }
 
 func (cmd *GetSchemaCommand) Run(ctx *Globals) error {
@@ -82,8 +104,7 @@ func (cmd *GetSchemaCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(s)
-	return nil
+	return ctx.Print(s)
 }
 
 func (cmd *CreateSchemaCommand) Run(ctx *Globals) error {
@@ -104,17 +125,17 @@ func (cmd *CreateSchemaCommand) Run(ctx *Globals) error {
 
 	// Create schema
 	s, err := client.CreateSchema(ctx.ctx, cmd.Database, schema.SchemaMeta{
-		Name:  cmd.Name,
-		Owner: cmd.Owner,
-		Acl:   acl,
+		Name:    cmd.Name,
+		Owner:   cmd.Owner,
+		Acl:     acl,
+		Comment: cmd.Comment,
 	})
 	if err != nil {
 		return err
 	}
 
 	// Print
-	fmt.Println(s)
-	return nil
+	return ctx.Print(s)
 }
 
 func (cmd *DeleteSchemaCommand) Run(ctx *Globals) error {
@@ -150,8 +171,9 @@ func (cmd *UpdateSchemaCommand) Run(ctx *Globals) error {
 
 	// Build meta
 	meta := schema.SchemaMeta{
-		Owner: cmd.Owner,
-		Acl:   acl,
+		Owner:   cmd.Owner,
+		Acl:     acl,
+		Comment: cmd.Comment,
 	}
 	if cmd.NewName != "" {
 		meta.Name = cmd.NewName
@@ -164,6 +186,5 @@ func (cmd *UpdateSchemaCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(s)
-	return nil
+	return ctx.Print(s)
 }
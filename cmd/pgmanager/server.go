@@ -1,15 +1,21 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
 	manager "github.com/mutablelogic/go-pg/pkg/manager"
 	httphandler "github.com/mutablelogic/go-pg/pkg/manager/httphandler"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
 	version "github.com/mutablelogic/go-pg/pkg/version"
 	httpserver "github.com/mutablelogic/go-server/pkg/httpserver"
 )
@@ -18,20 +24,99 @@ import (
 // TYPES
 
 type ServerCommands struct {
-	RunServer RunServer `cmd:"" name:"run" help:"Run server."`
+	Serve     ServeCommand     `cmd:"" name:"serve" aliases:"run" help:"Serve the API, until interrupted."`
+	GetServer GetServerCommand `cmd:"" name:"server" help:"Get connected server, including whether it is a standby."`
 }
 
-type RunServer struct {
-	URL string `arg:"" name:"url" help:"Database URL" default:""`
+type GetServerCommand struct{}
+
+type ServeCommand struct {
+	URL string `arg:"" name:"url" help:"Database URL, or the URL set with --url, or otherwise the PG* environment variables" default:""`
 	UI  bool   `name:"ui" help:"Enable frontend UI" default:"false"`
 
+	// Time to wait for requests to complete when interrupted, after which
+	// connections are closed
+	ShutdownTimeout time.Duration `name:"shutdown-timeout" env:"PG_SHUTDOWN_TIMEOUT" help:"Time to wait for requests to complete when interrupted" default:"30s"`
+
+	// Resource options
+	API struct {
+		Resources []string `name:"resources" env:"PG_API_RESOURCES" help:"Resources to register, or all resources when empty"`
+		Disable   []string `name:"disable" env:"PG_API_DISABLE" help:"Resources which are not registered"`
+		ReadOnly  bool     `name:"read-only" env:"PG_API_READ_ONLY" help:"Refuse requests which create, update or delete resources" default:"false"`
+
+		// Credentials, which are required when any are set
+		AdminTokens []string `name:"admin-token" env:"PG_API_ADMIN_TOKEN" help:"Bearer tokens which can make any request"`
+		ReadTokens  []string `name:"read-token" env:"PG_API_READ_TOKEN" help:"Bearer tokens which can make requests which do not modify the server"`
+		AdminUsers  []string `name:"admin-user" env:"PG_API_ADMIN_USER" help:"Users which can make any request, as user:password"`
+		ReadUsers   []string `name:"read-user" env:"PG_API_READ_USER" help:"Users which can make requests which do not modify the server, as user:password"`
+
+		// Limits, so that slow or frequent requests do not exhaust the connection pool
+		RateLimit     float64                  `name:"rate-limit" env:"PG_API_RATE_LIMIT" help:"Requests per second allowed from each client, or no limit when zero"`
+		RateBurst     uint                     `name:"rate-burst" env:"PG_API_RATE_BURST" help:"Requests each client can make at once, before they are limited to the rate" default:"10"`
+		Timeout       time.Duration            `name:"timeout" env:"PG_API_TIMEOUT" help:"Time after which requests are cancelled, or no timeout when zero"`
+		RouteTimeouts map[string]time.Duration `name:"route-timeout" env:"PG_API_ROUTE_TIMEOUT" help:"Timeouts for paths, such as 'setting/history=5s', separated by semicolons"`
+
+		// Cross-origin requests, such as from a frontend served from another origin
+		CORSOrigins []string `name:"cors-origin" env:"PG_API_CORS_ORIGIN" help:"Origins which can make cross-origin requests, or '*' for any origin"`
+		CORSMethods []string `name:"cors-method" env:"PG_API_CORS_METHOD" help:"Methods which can be used in cross-origin requests, or all methods when empty"`
+
+		// Number of series of statement metrics
+		StatementMetrics uint `name:"statement-metrics" env:"PG_API_STATEMENT_METRICS" help:"Number of role and database pairs with statement metrics, or no statement metrics when zero" default:"10"`
+
+		// Caching and timeouts for metrics collected from the database
+		MetricsCache   time.Duration `name:"metrics-cache" env:"PG_API_METRICS_CACHE" help:"Duration for which metrics are cached between scrapes, or no caching when zero"`
+		MetricsTimeout time.Duration `name:"metrics-timeout" env:"PG_API_METRICS_TIMEOUT" help:"Time allowed for each metrics collector to query the database" default:"10s"`
+	} `embed:"" prefix:"api."`
+
 	// Postgres options
 	PG struct {
 		// Database options
-		User     string `name:"user" env:"PG_USER" help:"Database user"`
-		Password string `name:"password" env:"PG_PASSWORD" help:"Database password"`
+		User     string        `name:"user" env:"PG_USER" help:"Database user"`
+		Password string        `name:"password" env:"PG_PASSWORD" help:"Database password"`
+		Dedup    bool          `name:"dedup" env:"PG_DEDUP" help:"Share identical concurrent read queries" default:"false"`
+		Cache    *uint         `name:"statement-cache" env:"PG_STATEMENT_CACHE" help:"Number of prepared statements to cache on each connection"`
+		Retry    uint          `name:"retry" env:"PG_RETRY" help:"Maximum number of attempts for operations which fail with transient errors" default:"1"`
+		Slow     time.Duration `name:"slow-query" env:"PG_SLOW_QUERY" help:"Log queries which take at least this duration, and queries which fail"`
+		Timeout  time.Duration `name:"query-timeout" env:"PG_QUERY_TIMEOUT" help:"Time after which statements are cancelled, or no timeout when zero"`
+
+		// TLS options
+		SSLMode     string `name:"sslmode" env:"PG_SSLMODE" enum:",disable,allow,prefer,require,verify-ca,verify-full" help:"SSL mode (disable, allow, prefer, require, verify-ca, verify-full)" default:""`
+		SSLRootCert string `name:"sslrootcert" env:"PG_SSLROOTCERT" type:"path" help:"File of root certificates which the server certificate is checked against"`
+		SSLCert     string `name:"sslcert" env:"PG_SSLCERT" type:"path" help:"Client certificate file, for servers which require client certificates"`
+		SSLKey      string `name:"sslkey" env:"PG_SSLKEY" type:"path" help:"Client key file, for servers which require client certificates"`
+
+		// Tool options
+		PgDump    string `name:"pg-dump" env:"PG_DUMP" help:"Path to pg_dump binary"`
+		PgRestore string `name:"pg-restore" env:"PG_RESTORE" help:"Path to pg_restore binary"`
+		Psql      string `name:"psql" env:"PG_PSQL" help:"Path to psql binary"`
+
+		// Time a base backup can hold its connection before it is abandoned
+		BackupTimeout time.Duration `name:"backup-timeout" env:"PG_BACKUP_TIMEOUT" help:"Time a base backup can remain in progress before it is abandoned" default:"24h"`
+
+		// Path to pg_hba.conf, which enables adding and removing rules when set
+		HBAFile string `name:"hba-file" env:"PG_HBA_FILE" help:"Absolute path to pg_hba.conf, which enables adding and removing rules"`
+
+		// Times when heavy operations can run, such as "sat,sun 01:00-05:00" in UTC
+		Maintenance []string `name:"maintenance-window" env:"PG_MAINTENANCE_WINDOW" sep:";" help:"Times when reindexing and backups can run, such as 'sat,sun 01:00-05:00' in UTC, separated by semicolons"`
 	} `embed:"" prefix:"pg."`
 
+	// Alert rule options
+	Alert struct {
+		Interval time.Duration `name:"interval" env:"PG_ALERT_INTERVAL" help:"Interval between evaluating alert rules" default:"1m"`
+		SMTP     string        `name:"smtp" env:"PG_ALERT_SMTP" help:"Mail server for email notifications, as host:port"`
+		From     string        `name:"from" env:"PG_ALERT_FROM" help:"Sender address for email notifications"`
+		User     string        `name:"smtp-user" env:"PG_ALERT_SMTP_USER" help:"Mail server user"`
+		Password string        `name:"smtp-password" env:"PG_ALERT_SMTP_PASSWORD" help:"Mail server password"`
+		Rules    []string      `name:"rule" env:"PG_ALERT_RULES" sep:";" help:"Alert rules, such as 'dead dead_tuples>0.2 slack https://hooks.slack.com/...', separated by semicolons"`
+	} `embed:"" prefix:"alert."`
+
+	// Ad-hoc query rules
+	Query struct {
+		Schemas       []string `name:"schemas" env:"PG_QUERY_SCHEMAS" help:"Schemas which ad-hoc queries may read from"`
+		DenyFunctions []string `name:"deny-functions" env:"PG_QUERY_DENY_FUNCTIONS" help:"Functions which ad-hoc queries may not call"`
+		MaxCost       float64  `name:"max-cost" env:"PG_QUERY_MAX_COST" help:"Maximum estimated cost of an ad-hoc query"`
+	} `embed:"" prefix:"query."`
+
 	// TLS server options
 	TLS struct {
 		ServerName string `name:"name" help:"TLS server name"`
@@ -40,19 +125,77 @@ type RunServer struct {
 	} `embed:"" prefix:"tls."`
 }
 
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Time allowed for a client to send the headers of a request
+	serveReadHeaderTimeout = 10 * time.Second
+)
+
 ///////////////////////////////////////////////////////////////////////////////
 // COMMANDS
 
-func (cmd *RunServer) Run(ctx *Globals) error {
+func (cmd *GetServerCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get server
+	server, err := client.GetServer(ctx.ctx)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(server)
+}
+
+func (cmd *ServeCommand) Run(ctx *Globals) error {
+	url := cmp.Or(cmd.URL, ctx.URL)
 	opts := []pg.Opt{
-		pg.WithURL(cmd.URL),
+		pg.WithURL(url),
+	}
+	if url == "" {
+		opts = append(opts, pg.WithEnv())
 	}
 	if cmd.PG.User != "" || cmd.PG.Password != "" {
 		opts = append(opts, pg.WithCredentials(cmd.PG.User, cmd.PG.Password))
 	}
+	if cmd.PG.SSLMode != "" {
+		opts = append(opts, pg.WithSSLMode(cmd.PG.SSLMode))
+	}
+	if cmd.PG.SSLRootCert != "" {
+		opts = append(opts, pg.WithSSLRootCert(cmd.PG.SSLRootCert))
+	}
+	if cmd.PG.SSLCert != "" || cmd.PG.SSLKey != "" {
+		opts = append(opts, pg.WithClientCert(cmd.PG.SSLCert, cmd.PG.SSLKey))
+	}
+	if cmd.PG.Dedup {
+		opts = append(opts, pg.WithDeduplication())
+	}
+	if cmd.PG.Cache != nil {
+		opts = append(opts, pg.WithStatementCache(*cmd.PG.Cache))
+	}
+	if cmd.PG.Retry > 1 {
+		policy := pg.DefaultRetryPolicy
+		policy.Attempts = cmd.PG.Retry
+		opts = append(opts, pg.WithRetry(policy))
+	}
+	if cmd.PG.Timeout > 0 {
+		opts = append(opts, pg.WithQueryTimeout(cmd.PG.Timeout))
+	}
+	if cmd.PG.Slow > 0 {
+		opts = append(opts, pg.WithLogger(slog.Default(), slog.LevelDebug), pg.WithSlowQuery(cmd.PG.Slow))
+	}
 	if ctx.Debug {
 		opts = append(opts, pg.WithTrace(func(ctx context.Context, query string, args any, err error) {
-			fmt.Println("PG TRACE:", query, args, err)
+			if values := pg.ContextValues(ctx); values != nil {
+				fmt.Println("PG TRACE:", values, query, args, err)
+			} else {
+				fmt.Println("PG TRACE:", query, args, err)
+			}
 		}))
 	}
 
@@ -68,15 +211,79 @@ func (cmd *RunServer) Run(ctx *Globals) error {
 		return err
 	}
 
+	// Parse the maintenance windows
+	var windows []schema.MaintenanceWindow
+	for _, v := range cmd.PG.Maintenance {
+		window, err := schema.ParseMaintenanceWindow(v)
+		if err != nil {
+			return err
+		}
+		windows = append(windows, window)
+	}
+
+	// Parse the alert rules
+	var rules []schema.AlertRuleMeta
+	for _, v := range cmd.Alert.Rules {
+		rule, err := schema.ParseAlertRule(v)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+
 	// Create the manager
-	manager, err := manager.New(ctx.ctx, conn)
+	manager, err := manager.New(ctx.ctx, conn,
+		manager.WithPgDump(cmd.PG.PgDump),
+		manager.WithPgRestore(cmd.PG.PgRestore),
+		manager.WithPsql(cmd.PG.Psql),
+		manager.WithBackupTimeout(cmd.PG.BackupTimeout),
+		manager.WithHBAFile(cmd.PG.HBAFile),
+		manager.WithQueryRules(schema.QueryRules{
+			Schemas:       cmd.Query.Schemas,
+			DenyFunctions: cmd.Query.DenyFunctions,
+			MaxCost:       cmd.Query.MaxCost,
+		}),
+		manager.WithMaintenanceWindows(windows...),
+		manager.WithSMTP(cmd.Alert.SMTP, cmd.Alert.From, cmd.Alert.User, cmd.Alert.Password),
+		manager.WithAlertRules(rules...),
+		manager.WithStatementMetrics(cmd.API.StatementMetrics),
+		manager.WithMetricsCache(cmd.API.MetricsCache),
+		manager.WithMetricsTimeout(cmd.API.MetricsTimeout),
+	)
 	if err != nil {
 		return err
 	}
 
+	// Evaluate the alert rules in the background
+	go func() {
+		if err := manager.RunAlertRules(ctx.ctx, cmd.Alert.Interval, func(err error) {
+			slog.Error("alert rules", "error", err)
+		}); err != nil {
+			slog.Error("alert rules", "error", err)
+		}
+	}()
+
 	// Register HTTP handlers
 	router := http.NewServeMux()
-	httphandler.RegisterBackendHandlers(router, ctx.HTTP.Prefix, manager)
+	handlerOpts := httphandler.Options{
+		ReadOnly:  cmd.API.ReadOnly,
+		RateLimit: cmd.API.RateLimit,
+		RateBurst: cmd.API.RateBurst,
+		Timeout:   cmd.API.Timeout,
+		Timeouts:  cmd.API.RouteTimeouts,
+	}.WithCORS(cmd.API.CORSOrigins, cmd.API.CORSMethods)
+	for _, resource := range cmd.API.Resources {
+		handlerOpts.Resources = append(handlerOpts.Resources, httphandler.Resource(resource))
+	}
+	for _, resource := range cmd.API.Disable {
+		handlerOpts.Disable = append(handlerOpts.Disable, httphandler.Resource(resource))
+	}
+	if auth, err := cmd.credentials(); err != nil {
+		return err
+	} else if auth != nil {
+		handlerOpts.Auth = auth
+	}
+	httphandler.RegisterHandlers(router, ctx.HTTP.Prefix, manager, handlerOpts)
 	httphandler.RegisterFrontendHandler(router, "", cmd.UI)
 
 	// Create a TLS config
@@ -88,14 +295,89 @@ func (cmd *RunServer) Run(ctx *Globals) error {
 		}
 	}
 
-	// Create a HTTP server
-	server, err := httpserver.New(ctx.HTTP.Addr, router, tlsconfig)
-	if err != nil {
-		return err
+	// Create a HTTP server. There is no write timeout, since streams and
+	// WebSockets are open for as long as the client needs them, and requests
+	// are instead cancelled after --api.timeout
+	server := &http.Server{
+		Addr:              ctx.HTTP.Addr,
+		Handler:           router,
+		TLSConfig:         tlsconfig,
+		ReadHeaderTimeout: serveReadHeaderTimeout,
 	}
 
 	// Run the server
 	fmt.Println(version.ExecName(), version.Version())
 	fmt.Println("Listening on", ctx.HTTP.Addr+ctx.HTTP.Prefix)
-	return server.Run(ctx.ctx)
+	return cmd.serve(ctx.ctx, server)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Serve requests until the context is cancelled, then stop accepting
+// connections and wait for the requests which are in progress to complete.
+// Connections are closed when they have not completed after the shutdown
+// timeout
+func (cmd *ServeCommand) serve(ctx context.Context, server *http.Server) error {
+	result := make(chan error, 1)
+	go func() {
+		if server.TLSConfig != nil {
+			result <- server.ListenAndServeTLS("", "")
+		} else {
+			result <- server.ListenAndServe()
+		}
+	}()
+
+	// Wait for the server to fail, or to be interrupted
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Shut down the server
+	fmt.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cmd.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); errors.Is(err, context.DeadlineExceeded) {
+		fmt.Println("Requests did not complete after", cmd.ShutdownTimeout, "and were closed")
+		return server.Close()
+	} else if err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+// Return the credentials which are required for requests, or nil if there
+// are none
+func (cmd *ServeCommand) credentials() (*httphandler.Credentials, error) {
+	if len(cmd.API.AdminTokens) == 0 && len(cmd.API.ReadTokens) == 0 && len(cmd.API.AdminUsers) == 0 && len(cmd.API.ReadUsers) == 0 {
+		return nil, nil
+	}
+	auth := httphandler.NewCredentials()
+	for _, token := range cmd.API.AdminTokens {
+		if err := auth.AddToken(token, httphandler.ScopeAdmin); err != nil {
+			return nil, err
+		}
+	}
+	for _, token := range cmd.API.ReadTokens {
+		if err := auth.AddToken(token, httphandler.ScopeRead); err != nil {
+			return nil, err
+		}
+	}
+	for _, user := range cmd.API.AdminUsers {
+		name, password, _ := strings.Cut(user, ":")
+		if err := auth.AddUser(name, password, httphandler.ScopeAdmin); err != nil {
+			return nil, err
+		}
+	}
+	for _, user := range cmd.API.ReadUsers {
+		name, password, _ := strings.Cut(user, ":")
+		if err := auth.AddUser(name, password, httphandler.ScopeRead); err != nil {
+			return nil, err
+		}
+	}
+	return auth, nil
 }
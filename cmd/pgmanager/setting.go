@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	// Packages
 	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
@@ -14,19 +15,39 @@ import (
 type SettingCommands struct {
 	ListSetting   ListSettingCommand   `cmd:"" name:"settings" help:"List server settings."`
 	ListCategory  ListCategoryCommand  `cmd:"" name:"setting-categories" help:"List setting categories."`
+	ListHistory   ListHistoryCommand   `cmd:"" name:"setting-history" help:"List changes made to server settings."`
+	ListPending   ListPendingCommand   `cmd:"" name:"pending-settings" help:"List settings which differ from the boot value or require a restart."`
+	DiffSettings  DiffSettingsCommand  `cmd:"" name:"diff-settings" help:"Show the statements which change settings to desired values."`
 	GetSetting    GetSettingCommand    `cmd:"" name:"setting" help:"Get a server setting."`
 	UpdateSetting UpdateSettingCommand `cmd:"" name:"update-setting" help:"Update a server setting."`
 	ResetSetting  ResetSettingCommand  `cmd:"" name:"reset-setting" help:"Reset a server setting to default."`
+	ReloadConfig  ReloadConfigCommand  `cmd:"" name:"reload-config" help:"Reload the server configuration files."`
 }
 
 type ListSettingCommand struct {
-	Category string  `name:"category" help:"Filter by category name"`
-	Offset   uint64  `name:"offset" help:"Offset for pagination"`
-	Limit    *uint64 `name:"limit" help:"Limit for pagination"`
+	Category       string  `name:"category" help:"Filter by category name"`
+	PendingRestart bool    `name:"pending-restart" help:"Only list settings which require a restart to take effect"`
+	Offset         uint64  `name:"offset" help:"Offset for pagination"`
+	Limit          *uint64 `name:"limit" help:"Limit for pagination"`
 }
 
 type ListCategoryCommand struct{}
 
+type ListPendingCommand struct {
+	Offset uint64  `name:"offset" help:"Offset for pagination"`
+	Limit  *uint64 `name:"limit" help:"Limit for pagination"`
+}
+
+type DiffSettingsCommand struct {
+	Settings []string `arg:"" name:"settings" help:"Desired values, as name=value"`
+}
+
+type ListHistoryCommand struct {
+	Name   *string `name:"name" help:"Filter by setting name"`
+	Offset uint64  `name:"offset" help:"Offset for pagination"`
+	Limit  *uint64 `name:"limit" help:"Limit for pagination"`
+}
+
 type GetSettingCommand struct {
 	Name string `arg:"" name:"name" help:"Setting name"`
 }
@@ -35,11 +56,15 @@ type UpdateSettingCommand struct {
 	Name   string `arg:"" name:"name" help:"Setting name"`
 	Value  string `arg:"" name:"value" help:"New setting value"`
 	Reload bool   `name:"reload" help:"Reload configuration after update (only for sighup context settings)"`
+	System bool   `name:"system" help:"Validate the value, and allow settings which require a restart"`
 }
 
+type ReloadConfigCommand struct{}
+
 type ResetSettingCommand struct {
 	Name   string `arg:"" name:"name" help:"Setting name"`
 	Reload bool   `name:"reload" help:"Reload configuration after reset (only for sighup context settings)"`
+	System bool   `name:"system" help:"Allow settings which require a restart"`
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -56,6 +81,9 @@ func (cmd *ListSettingCommand) Run(ctx *Globals) error {
 	if cmd.Category != "" {
 		opts = append(opts, httpclient.WithCategory(&cmd.Category))
 	}
+	if cmd.PendingRestart {
+		opts = append(opts, httpclient.WithPendingRestart(true))
+	}
 
 	// List settings
 	settings, err := client.ListSettings(ctx.ctx, opts...)
@@ -64,8 +92,7 @@ func (cmd *ListSettingCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(settings)
-	return nil
+	return ctx.Print(settings)
 }
 
 func (cmd *ListCategoryCommand) Run(ctx *Globals) error {
@@ -81,8 +108,65 @@ func (cmd *ListCategoryCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(categories)
-	return nil
+	return ctx.Print(categories)
+}
+
+func (cmd *ListPendingCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List pending settings
+	settings, err := client.ListPendingSettings(ctx.ctx, httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit))
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(settings)
+}
+
+func (cmd *DiffSettingsCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Parse the desired values
+	desired := make(map[string]string, len(cmd.Settings))
+	for _, v := range cmd.Settings {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid setting %q, expected name=value", v)
+		}
+		desired[strings.TrimSpace(name)] = value
+	}
+
+	// Compare the settings
+	diff, err := client.DiffSettings(ctx.ctx, desired)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(diff)
+}
+
+func (cmd *ListHistoryCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List setting history
+	history, err := client.ListSettingHistory(ctx.ctx, httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit), httpclient.WithName(cmd.Name))
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(history)
 }
 
 func (cmd *GetSettingCommand) Run(ctx *Globals) error {
@@ -98,8 +182,7 @@ func (cmd *GetSettingCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(setting)
-	return nil
+	return ctx.Print(setting)
 }
 
 func (cmd *UpdateSettingCommand) Run(ctx *Globals) error {
@@ -118,6 +201,9 @@ func (cmd *UpdateSettingCommand) Run(ctx *Globals) error {
 	if cmd.Reload {
 		opts = append(opts, httpclient.WithReload(true))
 	}
+	if cmd.System {
+		opts = append(opts, httpclient.WithSystemScope(true))
+	}
 
 	// Update the setting
 	setting, err := client.UpdateSetting(ctx.ctx, cmd.Name, meta, opts...)
@@ -126,8 +212,7 @@ func (cmd *UpdateSettingCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(setting)
-	return nil
+	return ctx.Print(setting)
 }
 
 func (cmd *ResetSettingCommand) Run(ctx *Globals) error {
@@ -146,6 +231,9 @@ func (cmd *ResetSettingCommand) Run(ctx *Globals) error {
 	if cmd.Reload {
 		opts = append(opts, httpclient.WithReload(true))
 	}
+	if cmd.System {
+		opts = append(opts, httpclient.WithSystemScope(true))
+	}
 
 	// Reset the setting
 	setting, err := client.UpdateSetting(ctx.ctx, cmd.Name, meta, opts...)
@@ -154,6 +242,21 @@ func (cmd *ResetSettingCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(setting)
-	return nil
+	return ctx.Print(setting)
+}
+
+func (cmd *ReloadConfigCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Reload the configuration
+	server, err := client.ReloadConfig(ctx.ctx)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(server)
 }
@@ -5,6 +5,7 @@ import (
 
 	// Packages
 	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -13,6 +14,8 @@ import (
 type StatementCommands struct {
 	ListStatement  ListStatementCommand  `cmd:"" name:"statements" help:"List query statistics from pg_stat_statements."`
 	ResetStatement ResetStatementCommand `cmd:"" name:"reset-statements" help:"Reset all statement statistics."`
+	ResetStats     ResetStatsCommand     `cmd:"" name:"reset-stats" help:"Reset the statistics of a database, a table or statements."`
+	TempUsage      TempUsageCommand      `cmd:"" name:"temp-usage" help:"List the temporary files written by each database and statement."`
 }
 
 type ListStatementCommand struct {
@@ -21,10 +24,25 @@ type ListStatementCommand struct {
 	Sort     string  `name:"sort" help:"Sort by field (calls, rows, total_ms, min_ms, max_ms, mean_ms)"`
 	Offset   uint64  `name:"offset" help:"Offset for pagination"`
 	Limit    *uint64 `name:"limit" help:"Limit for pagination"`
+	After    string  `name:"after" help:"Token for the next page"`
 }
 
 type ResetStatementCommand struct{}
 
+type TempUsageCommand struct {
+	Database string  `name:"database" short:"d" help:"Filter by database name"`
+	Offset   uint64  `name:"offset" help:"Offset for pagination of statements"`
+	Limit    *uint64 `name:"limit" help:"Limit for pagination of statements"`
+}
+
+type ResetStatsCommand struct {
+	Type      string `arg:"" name:"type" enum:"database,table,statements" help:"Statistics to reset (database, table, statements)"`
+	Database  string `name:"database" short:"d" help:"Database, or all databases for statements"`
+	Namespace string `name:"schema" short:"s" help:"Schema of the table"`
+	Table     string `name:"table" short:"t" help:"Table"`
+	Confirm   bool   `name:"confirm" help:"Confirm the statistics are to be reset, as they cannot be recovered"`
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // COMMANDS
 
@@ -45,6 +63,9 @@ func (cmd *ListStatementCommand) Run(ctx *Globals) error {
 	if cmd.Sort != "" {
 		opts = append(opts, httpclient.WithSort(cmd.Sort))
 	}
+	if cmd.After != "" {
+		opts = append(opts, httpclient.WithAfter(cmd.After))
+	}
 
 	// List statements
 	statements, err := client.ListStatements(ctx.ctx, opts...)
@@ -53,8 +74,7 @@ func (cmd *ListStatementCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(statements)
-	return nil
+	return ctx.Print(statements)
 }
 
 func (cmd *ResetStatementCommand) Run(ctx *Globals) error {
@@ -71,3 +91,51 @@ func (cmd *ResetStatementCommand) Run(ctx *Globals) error {
 	fmt.Println("Statement statistics reset successfully")
 	return nil
 }
+
+func (cmd *ResetStatsCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Reset statistics
+	if err := client.ResetStatistics(ctx.ctx, schema.StatisticsTarget{
+		Type:     schema.StatisticsType(cmd.Type),
+		Database: cmd.Database,
+		Schema:   cmd.Namespace,
+		Table:    cmd.Table,
+	}, httpclient.WithConfirm(cmd.Confirm)); err != nil {
+		return err
+	}
+
+	fmt.Println("Statistics reset successfully")
+	return nil
+}
+
+func (cmd *TempUsageCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the temporary files
+	usage, err := client.GetTempUsage(ctx.ctx, cmd.Database, httpclient.WithOffsetLimit(cmd.Offset, cmd.Limit))
+	if err != nil {
+		return err
+	}
+
+	// Print the databases and the statements as separate tables
+	if ctx.Output == outputTable {
+		fmt.Printf("work_mem: %s, temp_file_limit: %s\n", usage.WorkMem, usage.TempFileLimit)
+		if err := ctx.Print(schema.DatabaseTempUsageList{Count: uint64(len(usage.Databases)), Body: usage.Databases}); err != nil {
+			return err
+		}
+		if len(usage.Statements) == 0 {
+			return nil
+		}
+		return ctx.Print(schema.StatementTempUsageList{Count: uint64(len(usage.Statements)), Body: usage.Statements})
+	}
+
+	// Print
+	return ctx.Print(usage)
+}
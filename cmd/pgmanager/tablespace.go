@@ -14,6 +14,7 @@ import (
 type TablespaceCommands struct {
 	ListTablespace   ListTablespaceCommand   `cmd:"" name:"tablespaces" help:"List tablespaces."`
 	GetTablespace    GetTablespaceCommand    `cmd:"" name:"tablespace" help:"Get tablespace."`
+	TablespaceStats  TablespaceStatsCommand  `cmd:"" name:"tablespace-stats" help:"List the space on the filesystem of each tablespace."`
 	CreateTablespace CreateTablespaceCommand `cmd:"" name:"create-tablespace" help:"Create tablespace."`
 	DeleteTablespace DeleteTablespaceCommand `cmd:"" name:"delete-tablespace" help:"Delete tablespace."`
 	UpdateTablespace UpdateTablespaceCommand `cmd:"" name:"update-tablespace" help:"Update tablespace."`
@@ -24,6 +25,8 @@ type ListTablespaceCommand struct {
 	Limit  *uint64 `name:"limit" help:"Limit for pagination"`
 }
 
+type TablespaceStatsCommand struct{}
+
 type GetTablespaceCommand struct {
 	Name string `arg:"" name:"name" help:"Tablespace name"`
 }
@@ -62,8 +65,23 @@ func (cmd *ListTablespaceCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(tablespaces)
-	return nil
+	return ctx.Print(tablespaces)
+}
+
+func (cmd *TablespaceStatsCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// List the space on the filesystem of each tablespace
+	stats, err := client.ListTablespaceStats(ctx.ctx)
+	if err != nil {
+		return err
+	}
+
+	// Print
+	return ctx.Print(stats)
 }
 
 func (cmd *GetTablespaceCommand) Run(ctx *Globals) error {
@@ -79,8 +97,7 @@ func (cmd *GetTablespaceCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(tablespace)
-	return nil
+	return ctx.Print(tablespace)
 }
 
 func (cmd *CreateTablespaceCommand) Run(ctx *Globals) error {
@@ -110,8 +127,7 @@ func (cmd *CreateTablespaceCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(tablespace)
-	return nil
+	return ctx.Print(tablespace)
 }
 
 func (cmd *DeleteTablespaceCommand) Run(ctx *Globals) error {
@@ -161,6 +177,5 @@ func (cmd *UpdateTablespaceCommand) Run(ctx *Globals) error {
 	}
 
 	// Print
-	fmt.Println(tablespace)
-	return nil
+	return ctx.Print(tablespace)
 }
@@ -0,0 +1,505 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	// Packages
+	tea "github.com/charmbracelet/bubbletea"
+	lipgloss "github.com/charmbracelet/lipgloss"
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type TopCommands struct {
+	Top TopCommand `cmd:"" name:"top" help:"Show live connections, locks, statements and replication lag until quit."`
+}
+
+type TopCommand struct {
+	Database string        `name:"database" help:"Filter connections and statements by database name"`
+	Interval time.Duration `name:"interval" help:"Interval between refreshes" default:"2s"`
+}
+
+// topView is a view of the terminal UI, selected with tab or a number key
+type topView int
+
+// topRow is a row of a view, with the values which the row is sorted by
+type topRow struct {
+	pid   uint64 // Process ID for rows which are connections, or zero
+	cells []string
+	keys  []any // float64 or string for each cell
+}
+
+// topModel is the state of the terminal UI
+type topModel struct {
+	ctx    context.Context
+	client *httpclient.Client
+	cmd    *TopCommand
+
+	// Data for each view, and the error when it could not be refreshed
+	rows    [topViews][]topRow
+	errs    [topViews]error
+	updated time.Time
+
+	// Selected view, sort column and row
+	view    topView
+	sort    [topViews]int
+	reverse [topViews]bool
+	cursor  int
+	offset  int
+
+	// Process ID of the connection to terminate, when waiting for confirmation
+	kill   uint64
+	status string
+
+	// Size of the terminal
+	width, height int
+}
+
+// Messages which update the model
+type (
+	topTickMsg    struct{}
+	topRefreshMsg struct {
+		rows [topViews][]topRow
+		errs [topViews]error
+		at   time.Time
+	}
+	topKillMsg struct {
+		pid uint64
+		err error
+	}
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	topConnections topView = iota
+	topLocks
+	topStatements
+	topReplication
+	topViews
+)
+
+var (
+	topTitles  = [topViews]string{"Connections", "Locks", "Statements", "Replication"}
+	topColumns = [topViews][]string{
+		{"pid", "database", "role", "application", "client", "state", "wait", "duration", "query"},
+		{"pid", "database", "role", "wait", "blocked_by", "blocking", "duration", "query"},
+		{"calls", "rows", "total_ms", "mean_ms", "max_ms", "database", "role", "query"},
+		{"name", "type", "database", "status", "client", "lag_bytes", "lag_ms"},
+	}
+)
+
+var (
+	topActiveStyle   = lipgloss.NewStyle().Bold(true).Reverse(true).Padding(0, 1)
+	topTabStyle      = lipgloss.NewStyle().Padding(0, 1)
+	topHeaderStyle   = lipgloss.NewStyle().Bold(true)
+	topSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	topErrorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	topHelpStyle     = lipgloss.NewStyle().Faint(true)
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (cmd *TopCommand) Run(ctx *Globals) error {
+	client, err := ctx.Client()
+	if err != nil {
+		return err
+	}
+
+	// Statements are sorted by total time, and replication slots by lag,
+	// with the largest first
+	model := &topModel{ctx: ctx.ctx, client: client, cmd: cmd}
+	model.sort[topStatements], model.reverse[topStatements] = 2, true
+	model.sort[topReplication], model.reverse[topReplication] = 5, true
+
+	// Run until quit or interrupted
+	_, err = tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(ctx.ctx)).Run()
+	if ctx.ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// MODEL
+
+func (m *topModel) Init() tea.Cmd {
+	return m.refresh
+}
+
+func (m *topModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case topTickMsg:
+		return m, m.refresh
+	case topRefreshMsg:
+		m.rows, m.errs, m.updated = msg.rows, msg.errs, msg.at
+		for view := range m.rows {
+			m.order(topView(view))
+		}
+		m.clamp()
+		return m, tea.Tick(m.cmd.Interval, func(time.Time) tea.Msg {
+			return topTickMsg{}
+		})
+	case topKillMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("pid %d: %v", msg.pid, msg.err)
+		} else {
+			m.status = fmt.Sprintf("pid %d terminated", msg.pid)
+		}
+	case tea.KeyMsg:
+		return m, m.key(msg)
+	}
+	return m, nil
+}
+
+func (m *topModel) View() string {
+	var b strings.Builder
+
+	// Tabs for each view
+	tabs := make([]string, 0, topViews)
+	for view, title := range topTitles {
+		title = fmt.Sprintf("%d %s", view+1, title)
+		if topView(view) == m.view {
+			tabs = append(tabs, topActiveStyle.Render(title))
+		} else {
+			tabs = append(tabs, topTabStyle.Render(title))
+		}
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabs...) + "\n")
+
+	// Status line
+	switch {
+	case m.kill != 0:
+		b.WriteString(topErrorStyle.Render(fmt.Sprintf("Terminate pid %d? (y/n)", m.kill)))
+	case m.errs[m.view] != nil:
+		b.WriteString(topErrorStyle.Render(m.errs[m.view].Error()))
+	case m.status != "":
+		b.WriteString(m.status)
+	case !m.updated.IsZero():
+		b.WriteString(fmt.Sprintf("%d rows, updated %s", len(m.rows[m.view]), m.updated.Format(time.TimeOnly)))
+	}
+	b.WriteString("\n\n")
+
+	// Table, with the rows which fit in the terminal
+	columns, rows := topColumns[m.view], m.rows[m.view]
+	widths := m.widths(columns, rows)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		column = strings.ToUpper(column)
+		if i == m.sort[m.view] {
+			column += map[bool]string{false: "▲", true: "▼"}[m.reverse[m.view]]
+		}
+		header[i] = column
+	}
+	b.WriteString(topHeaderStyle.Render(line(header, widths)) + "\n")
+	for i := m.offset; i < len(rows) && i < m.offset+m.lines(); i++ {
+		text := line(rows[i].cells, widths)
+		if i == m.cursor {
+			text = topSelectedStyle.Render(text)
+		}
+		b.WriteString(text + "\n")
+	}
+
+	// Help
+	help := "tab/1-4 view  ↑/↓ select  s sort  r reverse  q quit"
+	if m.view == topConnections || m.view == topLocks {
+		help = "tab/1-4 view  ↑/↓ select  s sort  r reverse  k terminate  q quit"
+	}
+	b.WriteString("\n" + topHelpStyle.Render(help))
+	return b.String()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Handle a key press, and return a command to run
+func (m *topModel) key(msg tea.KeyMsg) tea.Cmd {
+	// Confirm termination of a connection
+	if m.kill != 0 {
+		pid := m.kill
+		m.kill = 0
+		if msg.String() == "y" {
+			return m.terminate(pid)
+		}
+		m.status = ""
+		return nil
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c", "esc":
+		return tea.Quit
+	case "tab", "right":
+		m.show((m.view + 1) % topViews)
+	case "shift+tab", "left":
+		m.show((m.view + topViews - 1) % topViews)
+	case "1", "2", "3", "4":
+		m.show(topView(msg.String()[0] - '1'))
+	case "up":
+		m.cursor--
+	case "down":
+		m.cursor++
+	case "pgup":
+		m.cursor -= m.lines()
+	case "pgdown":
+		m.cursor += m.lines()
+	case "home":
+		m.cursor = 0
+	case "end":
+		m.cursor = len(m.rows[m.view]) - 1
+	case "s":
+		m.sort[m.view] = (m.sort[m.view] + 1) % len(topColumns[m.view])
+		m.order(m.view)
+	case "r":
+		m.reverse[m.view] = !m.reverse[m.view]
+		m.order(m.view)
+	case "k":
+		if rows := m.rows[m.view]; m.cursor < len(rows) && rows[m.cursor].pid != 0 {
+			m.kill = rows[m.cursor].pid
+		}
+	}
+	m.clamp()
+	return nil
+}
+
+// Show a view, with the first row selected
+func (m *topModel) show(view topView) {
+	m.view, m.cursor, m.offset, m.status = view, 0, 0, ""
+}
+
+// Keep the selected row within the rows, and scroll so that it is shown
+func (m *topModel) clamp() {
+	m.cursor = max(0, min(m.cursor, len(m.rows[m.view])-1))
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if lines := m.lines(); m.cursor >= m.offset+lines {
+		m.offset = m.cursor - lines + 1
+	}
+}
+
+// Return the number of rows which fit in the terminal, below the tabs,
+// status and header and above the help
+func (m *topModel) lines() int {
+	return max(1, m.height-6)
+}
+
+// Sort the rows of a view by the sort column
+func (m *topModel) order(view topView) {
+	column, reverse := m.sort[view], m.reverse[view]
+	slices.SortStableFunc(m.rows[view], func(a, b topRow) int {
+		result := compare(a.keys[column], b.keys[column])
+		if reverse {
+			return -result
+		}
+		return result
+	})
+}
+
+// Return the width of each column. The last column has the remaining width
+// of the terminal
+func (m *topModel) widths(columns []string, rows []topRow) []int {
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = len(column) + 1
+		for _, row := range rows {
+			widths[i] = max(widths[i], lipgloss.Width(row.cells[i]))
+		}
+		widths[i] = min(widths[i], outputMaxWidth)
+	}
+	if last := len(widths) - 1; last >= 0 && m.width > 0 {
+		used := 0
+		for _, width := range widths[:last] {
+			used += width + 2
+		}
+		widths[last] = max(len(columns[last])+1, m.width-used)
+	}
+	return widths
+}
+
+// Return a command which requests the data for all the views
+func (m *topModel) refresh() tea.Msg {
+	msg := topRefreshMsg{at: time.Now()}
+	ctx, cancel := context.WithTimeout(m.ctx, max(m.cmd.Interval, 5*time.Second))
+	defer cancel()
+
+	// Connections and locks
+	var opts []httpclient.Opt
+	if m.cmd.Database != "" {
+		opts = append(opts, httpclient.OptDatabase(m.cmd.Database))
+	}
+	var connections []schema.Connection
+	for connection, err := range m.client.ListConnectionsAll(ctx, opts...) {
+		if err != nil {
+			msg.errs[topConnections], msg.errs[topLocks] = err, err
+			break
+		}
+		connections = append(connections, connection)
+	}
+	msg.rows[topConnections], msg.rows[topLocks] = connectionRows(connections, msg.at)
+
+	// Statements with the largest total time
+	if statements, err := m.client.ListStatements(ctx, append(opts, httpclient.WithSort("total_ms"))...); err != nil {
+		msg.errs[topStatements] = err
+	} else {
+		msg.rows[topStatements] = statementRows(statements.Body)
+	}
+
+	// Replication slots
+	if slots, err := m.client.ListReplicationSlots(ctx); err != nil {
+		msg.errs[topReplication] = err
+	} else {
+		msg.rows[topReplication] = replicationRows(slots.Body)
+	}
+
+	return msg
+}
+
+// Return a command which terminates a connection
+func (m *topModel) terminate(pid uint64) tea.Cmd {
+	m.status = fmt.Sprintf("terminating pid %d", pid)
+	return tea.Sequence(func() tea.Msg {
+		return topKillMsg{pid, m.client.DeleteConnection(m.ctx, pid)}
+	}, m.refresh)
+}
+
+// Return the rows for the connections and locks views. The locks view has the
+// connections which are waiting for a lock, or which hold a lock that other
+// connections are waiting for
+func connectionRows(connections []schema.Connection, now time.Time) ([]topRow, []topRow) {
+	blocking := make(map[uint32][]uint32)
+	for _, c := range connections {
+		for _, pid := range c.BlockedBy {
+			blocking[pid] = append(blocking[pid], c.Pid)
+		}
+	}
+
+	var rows, locks []topRow
+	for _, c := range connections {
+		var duration time.Duration
+		if c.State != "idle" && !c.QueryStart.IsZero() {
+			duration = now.Sub(c.QueryStart).Truncate(time.Second)
+		}
+		wait := strings.Trim(deref(c.WaitType)+":"+deref(c.WaitEvent), ":")
+		client := c.ClientAddr
+		if c.ClientPort != 0 {
+			client += ":" + strconv.FormatUint(uint64(c.ClientPort), 10)
+		}
+		rows = append(rows, row(uint64(c.Pid),
+			c.Pid, c.Database, c.Role, deref(c.Application), client, c.State, wait, duration, c.Query,
+		))
+		if deref(c.WaitType) == "Lock" || len(c.BlockedBy) > 0 || len(blocking[c.Pid]) > 0 {
+			locks = append(locks, row(uint64(c.Pid),
+				c.Pid, c.Database, c.Role, wait, pids(c.BlockedBy), pids(blocking[c.Pid]), duration, c.Query,
+			))
+		}
+	}
+	return rows, locks
+}
+
+// Return the rows for the statements view
+func statementRows(statements []schema.Statement) []topRow {
+	rows := make([]topRow, 0, len(statements))
+	for _, s := range statements {
+		rows = append(rows, row(0, s.Calls, s.Rows, s.Total, s.Mean, s.Max, s.Database, s.Role, s.Query))
+	}
+	return rows
+}
+
+// Return the rows for the replication view
+func replicationRows(slots []schema.ReplicationSlot) []topRow {
+	rows := make([]topRow, 0, len(slots))
+	for _, s := range slots {
+		var lagBytes, lagMs any = "", ""
+		if s.LagBytes != nil {
+			lagBytes = *s.LagBytes
+		}
+		if s.LagMs != nil {
+			lagMs = *s.LagMs
+		}
+		rows = append(rows, row(0, s.Name, s.Type, s.Database, s.Status, s.ClientAddr, lagBytes, lagMs))
+	}
+	return rows
+}
+
+// Return a row with a cell for each value, sorted by number for numbers and
+// durations and otherwise by text
+func row(pid uint64, values ...any) topRow {
+	r := topRow{pid: pid, cells: make([]string, len(values)), keys: make([]any, len(values))}
+	for i, value := range values {
+		switch v := value.(type) {
+		case uint32:
+			r.cells[i], r.keys[i] = strconv.FormatUint(uint64(v), 10), float64(v)
+		case int64:
+			r.cells[i], r.keys[i] = strconv.FormatInt(v, 10), float64(v)
+		case float64:
+			r.cells[i], r.keys[i] = strconv.FormatFloat(v, 'f', 2, 64), v
+		case time.Duration:
+			if v > 0 {
+				r.cells[i] = v.String()
+			}
+			r.keys[i] = v.Seconds()
+		default:
+			r.cells[i] = fmt.Sprint(v)
+			r.keys[i] = r.cells[i]
+		}
+	}
+	return r
+}
+
+// Compare two sort keys. Numbers are sorted before text
+func compare(a, b any) int {
+	x, xok := a.(float64)
+	y, yok := b.(float64)
+	switch {
+	case xok && yok:
+		return cmp.Compare(x, y)
+	case xok:
+		return -1
+	case yok:
+		return 1
+	default:
+		return strings.Compare(strings.ToLower(a.(string)), strings.ToLower(b.(string)))
+	}
+}
+
+// Return the cells of a row padded to the widths of the columns, with the
+// values truncated to fit
+func line(cells []string, widths []int) string {
+	parts := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = strings.Join(strings.Fields(cell), " ")
+		if r := []rune(cell); len(r) > widths[i] {
+			cell = string(r[:max(0, widths[i]-1)]) + "…"
+		}
+		parts[i] = cell + strings.Repeat(" ", max(0, widths[i]-lipgloss.Width(cell)))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// Return process IDs separated by commas
+func pids(values []uint32) string {
+	result := make([]string, len(values))
+	for i, pid := range values {
+		result[i] = strconv.FormatUint(uint64(pid), 10)
+	}
+	return strings.Join(result, ",")
+}
+
+func deref(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
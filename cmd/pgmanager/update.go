@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	pg "github.com/mutablelogic/go-pg"
+	version "github.com/mutablelogic/go-pg/pkg/version"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// release is the latest release on GitHub
+type release struct {
+	Tag    string `json:"tag_name"`
+	Assets []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	githubEndpoint = "https://api.github.com"
+	githubRepo     = "mutablelogic/go-pg"
+
+	// Release asset with the SHA-256 checksums of the binaries, in the
+	// format written by sha256sum
+	githubChecksums = "checksums.txt"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// latestRelease returns the latest release of the repository which the
+// binary was built from
+func latestRelease(ctx context.Context) (*release, error) {
+	github, err := client.New(client.OptEndpoint(githubEndpoint))
+	if err != nil {
+		return nil, err
+	}
+	repo := githubRepo
+	if source, ok := strings.CutPrefix(version.GitSource, "github.com/"); ok {
+		repo = source
+	}
+	owner, name, _ := strings.Cut(repo, "/")
+
+	// Get the latest release
+	var response release
+	if err := github.DoWithContext(ctx, client.NewRequest(), &response, client.OptPath("repos", owner, name, "releases", "latest")); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// asset returns the name of the binary for this platform, which is named as
+// pgmanager-<os>-<arch>, and the download URLs of the binary and the
+// checksums of the release
+func (r *release) asset() (string, string, string, error) {
+	name := fmt.Sprintf("pgmanager-%s-%s", runtime.GOOS, runtime.GOARCH)
+	var url, checksums string
+	for _, asset := range r.Assets {
+		switch asset.Name {
+		case name:
+			url = asset.URL
+		case githubChecksums:
+			checksums = asset.URL
+		}
+	}
+	if url == "" {
+		return "", "", "", pg.ErrNotFound.Withf("release %s has no %q binary", r.Tag, name)
+	} else if checksums == "" {
+		return "", "", "", pg.ErrNotFound.Withf("release %s has no %q file", r.Tag, githubChecksums)
+	}
+	return name, url, checksums, nil
+}
+
+// checksum returns the SHA-256 checksum of the named binary from the
+// checksums file downloaded from the URL
+func checksum(ctx context.Context, url, name string) ([]byte, error) {
+	resp, err := download(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Each line is the checksum and the name, which is prefixed with an
+	// asterisk when it was read in binary mode
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum, err := hex.DecodeString(fields[0])
+		if err != nil || len(sum) != sha256.Size {
+			return nil, pg.ErrBadParameter.Withf("invalid checksum for %q", name)
+		}
+		return sum, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, pg.ErrNotFound.Withf("no checksum for %q", name)
+}
+
+// download returns the response for the URL, or an error if the status is
+// not OK
+func download(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download %s: %s", url, resp.Status)
+	}
+	return resp, nil
+}
+
+// selfUpdate replaces the running binary with the binary downloaded from
+// the URL, when it has the SHA-256 checksum. The binary is written next to
+// the running binary and then renamed, so that a failed download leaves the
+// running binary in place.
+func selfUpdate(ctx context.Context, url string, sum []byte) error {
+	exec, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if exec, err = filepath.EvalSymlinks(exec); err != nil {
+		return err
+	}
+
+	// Download the binary
+	resp, err := download(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Write to a temporary file in the same directory
+	w, err := os.CreateTemp(filepath.Dir(exec), "."+filepath.Base(exec)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(w.Name())
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, hash), resp.Body); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	// Check the checksum before the binary is replaced
+	if got := hash.Sum(nil); !bytes.Equal(got, sum) {
+		return pg.ErrBadParameter.Withf("checksum mismatch for %s: expected %x, got %x", url, sum, got)
+	}
+	if err := os.Chmod(w.Name(), 0o755); err != nil {
+		return err
+	}
+
+	// Replace the running binary
+	return os.Rename(w.Name(), exec)
+}
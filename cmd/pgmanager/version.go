@@ -1,9 +1,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
 	"github.com/mutablelogic/go-pg/pkg/version"
 )
 
@@ -14,7 +18,10 @@ type VersionCommands struct {
 	Version VersionCommand `cmd:"version" help:"Print version information"`
 }
 
-type VersionCommand struct{}
+type VersionCommand struct {
+	Check  bool `name:"check" help:"Check the server supports the API version of this client"`
+	Update bool `name:"update" help:"Replace this binary with the latest release"`
+}
 
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
@@ -35,5 +42,85 @@ func (cmd *VersionCommand) Run(g *Globals) error {
 		fmt.Printf("Build Time: %s\n", version.GoBuildTime)
 	}
 	fmt.Printf("Compiler: %s\n", version.Compiler())
+	fmt.Printf("API Version: %d\n", schema.APIVersion)
+
+	// Check the server
+	if cmd.Check {
+		if err := cmd.check(g); err != nil {
+			return err
+		}
+	}
+
+	// Update the binary
+	if cmd.Update {
+		if err := cmd.update(g); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// check returns an error if the server does not serve the version of the API
+// which this client requests, and warns if the server is a different release
+func (cmd *VersionCommand) check(g *Globals) error {
+	c, err := g.Client()
+	if err != nil {
+		return err
+	}
+
+	// Get the server version. Servers which predate the version endpoint
+	// return not found.
+	server, err := c.GetVersion(g.ctx)
+	if err != nil {
+		if errors.Is(err, pg.ErrNotFound) {
+			return pg.ErrNotAvailable.Withf("server does not report its version, and is older than this client (API version %d)", schema.APIVersion)
+		}
+		return err
+	}
+	fmt.Printf("\nServer: %s %s\n", server.Name, server.Version)
+	if server.Commit != "" {
+		fmt.Printf("Server Commit: %s\n", server.Commit)
+	}
+	fmt.Printf("Server API Versions: %v\n", server.APIVersions)
+
+	// Check compatibility
+	if !server.Supports(schema.APIVersion) {
+		return pg.ErrNotAvailable.Withf("server does not support API version %d, it supports versions %v", schema.APIVersion, server.APIVersions)
+	}
+	if server.Version != version.Version() {
+		fmt.Fprintf(os.Stderr, "Warning: client version %s differs from server version %s\n", version.Version(), server.Version)
+	}
+	return nil
+}
+
+// update replaces this binary with the binary from the latest release, when
+// the release is newer than this binary
+func (cmd *VersionCommand) update(g *Globals) error {
+	release, err := latestRelease(g.ctx)
+	if err != nil {
+		return err
+	}
+	if newer, err := version.Compare(release.Tag, version.GitTag); err != nil {
+		return pg.ErrNotAvailable.Withf("cannot compare release %s with this binary %q: %v", release.Tag, version.GitTag, err)
+	} else if newer <= 0 {
+		fmt.Printf("\nAlready at or after the latest release %s\n", release.Tag)
+		return nil
+	}
+	name, url, checksums, err := release.asset()
+	if err != nil {
+		return err
+	}
+	sum, err := checksum(g.ctx, checksums, name)
+	if err != nil {
+		return err
+	}
+	if err := selfUpdate(g.ctx, url, sum); err != nil {
+		return err
+	}
+	fmt.Printf("\nUpdated to %s\n", release.Tag)
 	return nil
 }
@@ -3,6 +3,8 @@ package pg
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	// Packages
 	pgx "github.com/jackc/pgx/v5"
@@ -25,24 +27,44 @@ type Conn interface {
 	// should be in a transaction)
 	Bulk(context.Context, func(Conn) error) error
 
-	// Execute a query
-	Exec(context.Context, string) error
+	// Execute a query, with options such as Timeout
+	Exec(context.Context, string, ...ExecOpt) error
 
 	// Perform an insert
 	Insert(context.Context, Reader, Writer) error
 
+	// Perform an insert, or an update of the conflicting row when the
+	// conflict target columns are given, or nothing otherwise
+	Upsert(context.Context, Reader, Writer, ...string) error
+
 	// Perform an update
 	Update(context.Context, Reader, Selector, Writer) error
 
 	// Perform a delete
 	Delete(context.Context, Reader, Selector) error
 
+	// Perform an update without scanning the rows, returning the number of
+	// rows updated
+	UpdateMany(context.Context, Selector, Writer) (int64, error)
+
+	// Perform a delete without scanning the rows, returning the number of
+	// rows deleted
+	DeleteMany(context.Context, Selector) (int64, error)
+
 	// Perform a get
 	Get(context.Context, Reader, Selector) error
 
 	// Perform a list. If the reader is a ListReader, then the
 	// count of items is also calculated
 	List(context.Context, Reader, Selector) error
+
+	// Perform a list, calling the function for each row rather than
+	// scanning all rows into a reader
+	ListStream(context.Context, Selector, func(Row) error) error
+
+	// Declare a cursor for a query, which fetches rows in batches of
+	// the given size. The cursor must be closed after use
+	Cursor(context.Context, string, uint) (Cursor, error)
 }
 
 // Op represents a database operation type.
@@ -100,6 +122,7 @@ const (
 	Update
 	Delete
 	List
+	Upsert
 )
 
 func (o Op) String() string {
@@ -114,6 +137,8 @@ func (o Op) String() string {
 		return "DELETE"
 	case List:
 		return "LIST"
+	case Upsert:
+		return "UPSERT"
 	}
 	return "UNKNOWN"
 }
@@ -143,8 +168,8 @@ func (p *conn) Bulk(ctx context.Context, fn func(Conn) error) error {
 }
 
 // Execute a query
-func (p *conn) Exec(ctx context.Context, query string) error {
-	return p.bind.Exec(ctx, p.conn, query)
+func (p *conn) Exec(ctx context.Context, query string, opts ...ExecOpt) error {
+	return execTimeout(ctx, p.conn, p.bind, query, applyExec(opts...))
 }
 
 // Perform an insert, binding parameters from
@@ -153,6 +178,12 @@ func (p *conn) Insert(ctx context.Context, reader Reader, writer Writer) error {
 	return insert(ctx, p.conn, p.bind, reader, writer)
 }
 
+// Perform an upsert, binding parameters from the writer, and scanning the
+// inserted or updated row into the reader
+func (p *conn) Upsert(ctx context.Context, reader Reader, writer Writer, target ...string) error {
+	return upsert(ctx, p.conn, p.bind, reader, writer, target...)
+}
+
 // Perform an update, selecting using the selector, binding parameters from
 // the writer, and scanning the result into the reader
 func (p *conn) Update(ctx context.Context, reader Reader, sel Selector, writer Writer) error {
@@ -165,6 +196,22 @@ func (p *conn) Delete(ctx context.Context, reader Reader, sel Selector) error {
 	return del(ctx, p.conn, p.bind, reader, sel)
 }
 
+// Perform an update, selecting using the selector and binding parameters
+// from the writer, and return the number of rows updated
+func (p *conn) UpdateMany(ctx context.Context, sel Selector, writer Writer) (int64, error) {
+	return rowsAffected(ctx, func(ctx context.Context) error {
+		return update(ctx, p.conn, p.bind, nil, sel, writer)
+	})
+}
+
+// Perform a delete, binding parameters with the selector, and return the
+// number of rows deleted
+func (p *conn) DeleteMany(ctx context.Context, sel Selector) (int64, error) {
+	return rowsAffected(ctx, func(ctx context.Context) error {
+		return del(ctx, p.conn, p.bind, nil, sel)
+	})
+}
+
 // Perform a get, binding parameters with the selector and scanning a single
 // row into the reader
 func (p *conn) Get(ctx context.Context, reader Reader, sel Selector) error {
@@ -177,6 +224,17 @@ func (p *conn) List(ctx context.Context, reader Reader, sel Selector) error {
 	return list(ctx, p.conn, p.bind, reader, sel)
 }
 
+// Perform a list, binding parameters with the selector and calling the
+// function for each row
+func (p *conn) ListStream(ctx context.Context, sel Selector, fn func(Row) error) error {
+	return stream(ctx, p.conn, p.bind, sel, fn)
+}
+
+// Declare a cursor within a savepoint, which fetches rows in batches
+func (p *conn) Cursor(ctx context.Context, query string, batchSize uint) (Cursor, error) {
+	return declare(ctx, p.conn, p.bind, query, batchSize)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
@@ -186,6 +244,16 @@ func tx(ctx context.Context, tx pgx.Tx, bind *Bind, fn func(Conn) error) error {
 		return err
 	}
 
+	// If the function panics, roll back the transaction so the connection
+	// is returned clean, trace the panic, and then panic again
+	defer func() {
+		if r := recover(); r != nil {
+			err := tx.Rollback(context.WithoutCancel(ctx))
+			tracePanic(ctx, tx.Conn(), r, err)
+			panic(r)
+		}
+	}()
+
 	tx_ := &conn{tx, bind.Copy()}
 	if err := fn(tx_); err != nil {
 		return errors.Join(pgerror(err), tx.Rollback(ctx))
@@ -202,6 +270,42 @@ func insert(ctx context.Context, conn pgx.Tx, bind *Bind, reader Reader, writer
 	return exec(ctx, conn, bind, query, reader)
 }
 
+func upsert(ctx context.Context, conn pgx.Tx, bind *Bind, reader Reader, writer Writer, target ...string) error {
+	query, err := upsertQuery(bind, writer, target...)
+	if err != nil {
+		return err
+	}
+	return exec(ctx, conn, bind, query, reader)
+}
+
+// Bind the parameters for an upsert from the writer, and set the conflict
+// clause, which updates the row with the patch from the writer when there is
+// a conflict target, or does nothing otherwise. The writer must also be a
+// Selector which returns the upsert query, with a ${conflict} variable.
+func upsertQuery(bind *Bind, writer Writer, target ...string) (string, error) {
+	sel, ok := writer.(Selector)
+	if !ok {
+		return "", ErrNotImplemented.Withf("%T does not support upsert", writer)
+	}
+	if _, err := writer.Insert(bind); err != nil {
+		return "", err
+	}
+	if len(target) == 0 {
+		bind.Set("conflict", `ON CONFLICT DO NOTHING`)
+	} else if err := writer.Update(bind); err != nil {
+		return "", err
+	} else if patch, ok := bind.Get("patch").(string); !ok || patch == "" {
+		return "", ErrBadParameter.With("no columns to update")
+	} else {
+		columns := make([]string, 0, len(target))
+		for _, column := range target {
+			columns = append(columns, quoteIdentifier(column))
+		}
+		bind.Set("conflict", `ON CONFLICT (`+strings.Join(columns, ", ")+`) DO UPDATE SET `+patch)
+	}
+	return sel.Select(bind, Upsert)
+}
+
 func update(ctx context.Context, conn pgx.Tx, bind *Bind, reader Reader, sel Selector, writer Writer) error {
 	query, err := sel.Select(bind, Update)
 	if err != nil {
@@ -254,6 +358,31 @@ func list(ctx context.Context, conn pgx.Tx, bind *Bind, reader Reader, sel Selec
 	}
 }
 
+func stream(ctx context.Context, conn pgx.Tx, bind *Bind, sel Selector, fn func(Row) error) error {
+	bind.Set("offsetlimit", "")
+	query, err := sel.Select(bind, List)
+	if err != nil {
+		return pgerror(err)
+	}
+
+	// Execute the query
+	rows, err := bind.Query(ctx, conn, query+` ${offsetlimit}`)
+	if err != nil {
+		return pgerror(err)
+	}
+	defer rows.Close()
+
+	// Call the function for each row, stopping on the first error
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+
+	// Return any error
+	return pgerror(rows.Err())
+}
+
 func count(ctx context.Context, conn pgx.Tx, query string, bind *Bind, reader ListReader) error {
 	// Make a subquery
 	return pgerror(reader.ScanCount(bind.Copy("as", "t (count BIGINT)").QueryRow(ctx, conn, `WITH sq AS (`+query+`) SELECT COUNT(*) AS "count" FROM sq`)))
@@ -265,24 +394,34 @@ func exec(ctx context.Context, conn pgx.Tx, bind *Bind, query string, reader Rea
 		return pgerror(bind.Exec(ctx, conn, query))
 	}
 	// Execute the query
+	start := time.Now()
 	rows, err := bind.Query(ctx, conn, query)
 	if err != nil {
 		return pgerror(err)
 	}
 	defer rows.Close()
 
-	// Read rows
+	// Read rows. Once the reader has scanned a row, errors are not retried,
+	// since the reader would scan the rows again.
 	var scanned bool
 	for rows.Next() {
 		if err := reader.Scan(rows); err != nil {
-			return pgerror(err)
+			return partialError{pgerror(err)}
 		}
 		scanned = true
 	}
 
 	if err := rows.Err(); err != nil {
+		if scanned {
+			return partialError{err}
+		}
 		return err
-	} else if !scanned {
+	}
+
+	// The command tag is available once the rows are closed
+	rows.Close()
+	setResult(ctx, rows.CommandTag(), start)
+	if !scanned {
 		return pgerror(pgx.ErrNoRows)
 	}
 
@@ -0,0 +1,105 @@
+package pg
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	// Packages
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ConnConfig is the parts of a connection, which can be used in place of a
+// URL. Fields which are empty are not set.
+type ConnConfig struct {
+	Host            string   // Host name or address
+	Port            uint16   // Port, or zero for the default port
+	User            string   // Role to connect as
+	Password        string   // Password for the role
+	PasswordFile    string   // File of passwords in the .pgpass format, used when there is no password
+	Database        string   // Database to connect to
+	SearchPath      []string // Schemas to search for unqualified names, in order, such as "$user"
+	ApplicationName string   // Name of the application, which is shown in pg_stat_activity
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// WithConnConfig sets connection parameters from the parts of a connection.
+func WithConnConfig(config ConnConfig) Opt {
+	return func(o *opt) error {
+		for key, value := range config.params() {
+			o.Set(key, value)
+		}
+		return nil
+	}
+}
+
+// URL returns the connection as a URL, including the password, which can be
+// used with WithURL or passed to tools such as psql.
+func (c ConnConfig) URL() string {
+	u := &url.URL{Scheme: defaultScheme[0], Host: c.Host}
+	if c.Database != "" {
+		u.Path = "/" + c.Database
+	}
+	if c.Port != 0 {
+		u.Host = net.JoinHostPort(c.Host, strconv.FormatUint(uint64(c.Port), 10))
+	}
+	if c.Password != "" {
+		u.User = url.UserPassword(c.User, c.Password)
+	} else if c.User != "" {
+		u.User = url.User(c.User)
+	}
+	q := make(url.Values)
+	for key, value := range c.params() {
+		switch key {
+		case "host", "port", "user", "password", "dbname":
+			continue
+		}
+		q.Set(key, value)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the connection parameters which are set. The search path is set
+// with the options parameter, which is understood by both pgx and libpq
+func (c ConnConfig) params() map[string]string {
+	params := make(map[string]string)
+	set := func(key, value string) {
+		if value != "" {
+			params[key] = value
+		}
+	}
+	set("host", c.Host)
+	if c.Port != 0 {
+		set("port", strconv.FormatUint(uint64(c.Port), 10))
+	}
+	set("user", c.User)
+	set("password", c.Password)
+	set("passfile", c.PasswordFile)
+	set("dbname", c.Database)
+	set("application_name", c.ApplicationName)
+	if len(c.SearchPath) > 0 {
+		schemas := make([]string, len(c.SearchPath))
+		for i, schema := range c.SearchPath {
+			schemas[i] = escapeOption(quote.Ident(schema))
+		}
+		set("options", "-c search_path="+strings.Join(schemas, ","))
+	}
+	return params
+}
+
+// Escape spaces and backslashes in a value of the options parameter, which
+// separates arguments with spaces
+func escapeOption(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, ` `, `\ `)
+}
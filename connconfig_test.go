@@ -0,0 +1,81 @@
+package pg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	// Packages
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConnConfig_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// Parts of a connection are connection parameters
+	config := ConnConfig{
+		Host:            "db.example.com",
+		Port:            6432,
+		User:            "app",
+		Password:        "secret word",
+		Database:        "orders",
+		SearchPath:      []string{"$user", "My Schema", "public"},
+		ApplicationName: "orders-api",
+	}
+	o, err := apply(WithConnConfig(config))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.Equal(map[string]string{
+		"host":             "db.example.com",
+		"port":             "6432",
+		"user":             "app",
+		"password":         "secret word",
+		"dbname":           "orders",
+		"application_name": "orders-api",
+		"options":          `-c search_path="$user","My\ Schema","public"`,
+	}, o.params())
+
+	// The connection string is understood by pgx
+	parsed, err := pgconn.ParseConfig(o.Encode())
+	if assert.NoError(err) {
+		assert.Equal("db.example.com", parsed.Host)
+		assert.Equal(uint16(6432), parsed.Port)
+		assert.Equal("secret word", parsed.Password)
+		assert.Equal("orders-api", parsed.RuntimeParams["application_name"])
+		assert.Equal(`-c search_path="$user","My\ Schema","public"`, parsed.RuntimeParams["options"])
+	}
+
+	// The URL has the same parameters
+	u, err := apply(WithURL(config.URL()))
+	if assert.NoError(err) {
+		assert.Equal(o.params(), u.params())
+	}
+}
+
+func Test_ConnConfig_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// Password is read from the password file when it is not set
+	passfile := filepath.Join(t.TempDir(), "pgpass")
+	if !assert.NoError(os.WriteFile(passfile, []byte("db.example.com:5432:*:app:from-file\n"), 0600)) {
+		t.FailNow()
+	}
+	o, err := apply(WithConnConfig(ConnConfig{Host: "db.example.com", User: "app", Database: "orders", PasswordFile: passfile}))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.Equal(passfile, o.Get("passfile"))
+	parsed, err := pgconn.ParseConfig(o.Encode())
+	if assert.NoError(err) {
+		assert.Equal("from-file", parsed.Password)
+	}
+
+	// Empty fields are not set
+	o, err = apply(WithConnConfig(ConnConfig{}))
+	if assert.NoError(err) {
+		assert.Equal("host=localhost pool_max_conns=10 port=5432", o.Encode())
+	}
+	assert.Equal("postgres://db.example.com:5432/orders?application_name=x", ConnConfig{Host: "db.example.com", Port: 5432, Database: "orders", ApplicationName: "x"}.URL())
+}
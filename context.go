@@ -0,0 +1,106 @@
+package pg
+
+import (
+	"context"
+	"maps"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+)
+
+//////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ContextKey is a well-known key for application-level identifiers which
+// are stored in the context, and are reported to the trace function
+type ContextKey string
+
+// bindKey is the context key for bind vars which are bound for every
+// statement executed with the context
+type bindKey struct{}
+
+//////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	ContextKeyRequestId ContextKey = "request_id"
+	ContextKeyTenant    ContextKey = "tenant"
+	ContextKeyUser      ContextKey = "user"
+)
+
+var (
+	contextKeys = []ContextKey{ContextKeyRequestId, ContextKeyTenant, ContextKeyUser}
+)
+
+//////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ContextWithRequestId returns a context with a request identifier
+func ContextWithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ContextKeyRequestId, id)
+}
+
+// ContextWithTenant returns a context with a tenant identifier
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, ContextKeyTenant, tenant)
+}
+
+// ContextWithUser returns a context with a user identifier
+func ContextWithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, ContextKeyUser, user)
+}
+
+// ContextWithBind returns a context with bind vars, as name/value pairs,
+// which are bound for every statement executed with the context, unless the
+// statement binds the same name. This is used for cross-cutting predicates,
+// such as WHERE tenant_id=@tenant_id, which should not depend on each call
+// site binding them. The bind vars of the parent context are kept. Panics if
+// the pairs are not names and values.
+func ContextWithBind(ctx context.Context, pairs ...any) context.Context {
+	if len(pairs)%2 != 0 {
+		panic("ContextWithBind: odd number of arguments")
+	}
+	vars := make(pgx.NamedArgs, len(pairs)>>1)
+	if parent := contextBind(ctx); parent != nil {
+		maps.Copy(vars, parent)
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		if key, ok := pairs[i].(string); !ok || key == "" {
+			panic("ContextWithBind: name is not a string")
+		} else {
+			vars[key] = pairs[i+1]
+		}
+	}
+	return context.WithValue(ctx, bindKey{}, vars)
+}
+
+// ContextValues returns the well-known identifiers which are set in the
+// context, keyed by name, or nil if none are set. The trace function can
+// use this to include the identifiers with every SQL event.
+func ContextValues(ctx context.Context) map[string]string {
+	var result map[string]string
+	if ctx == nil {
+		return nil
+	}
+	for _, key := range contextKeys {
+		if value, ok := ctx.Value(key).(string); ok && value != "" {
+			if result == nil {
+				result = make(map[string]string, len(contextKeys))
+			}
+			result[string(key)] = value
+		}
+	}
+	return result
+}
+
+//////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the bind vars set with ContextWithBind, or nil
+func contextBind(ctx context.Context) pgx.NamedArgs {
+	if ctx == nil {
+		return nil
+	}
+	vars, _ := ctx.Value(bindKey{}).(pgx.NamedArgs)
+	return vars
+}
@@ -0,0 +1,46 @@
+package pg_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Context_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// No values set
+	assert.Nil(pg.ContextValues(context.Background()))
+
+	// Set values
+	ctx := pg.ContextWithRequestId(context.Background(), "req-1")
+	ctx = pg.ContextWithTenant(ctx, "acme")
+	ctx = pg.ContextWithUser(ctx, "alice")
+	assert.Equal(map[string]string{
+		"request_id": "req-1",
+		"tenant":     "acme",
+		"user":       "alice",
+	}, pg.ContextValues(ctx))
+
+	// Empty values are ignored
+	ctx = pg.ContextWithUser(context.Background(), "")
+	assert.Nil(pg.ContextValues(ctx))
+}
+
+func Test_Context_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// Pairs need to be names and values
+	assert.Panics(func() {
+		pg.ContextWithBind(context.Background(), "tenant_id")
+	})
+	assert.Panics(func() {
+		pg.ContextWithBind(context.Background(), 1, "tenant_id")
+	})
+	assert.Panics(func() {
+		pg.ContextWithBind(context.Background(), "", "tenant_id")
+	})
+}
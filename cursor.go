@@ -0,0 +1,210 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Cursor iterates over the results of a query in batches, so that the
+// memory used is bounded by the batch size rather than the number of rows.
+type Cursor interface {
+	// Fetch the next batch of rows, scanning each row into the reader, and
+	// return the number of rows fetched. When no rows are returned, the
+	// cursor is closed.
+	Fetch(context.Context, Reader) (int, error)
+
+	// Close the cursor and end the transaction in which it was declared.
+	Close(context.Context) error
+}
+
+// cursor is a named cursor declared within a transaction. A cursor on a
+// remote database is opened with dblink on a named connection, which is
+// opened in the outer transaction and closed with the cursor, and the rows
+// are fetched within a savepoint, so that the connection can be closed
+// after an error
+type cursor struct {
+	tx     pgx.Tx
+	outer  pgx.Tx // The transaction of a remote cursor, or nil
+	name   string
+	fetch  string
+	close  string
+	closed bool
+}
+
+// Ensure interfaces are satisfied
+var _ Cursor = (*cursor)(nil)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// DefaultCursorBatchSize is the number of rows fetched in each batch
+	// when the batch size is zero
+	DefaultCursorBatchSize = 1000
+)
+
+var (
+	// Counter used to generate unique cursor names
+	cursorId atomic.Uint64
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Fetch the next batch of rows, scanning each row into the reader. Returns
+// zero rows and closes the cursor when there are no more rows.
+func (c *cursor) Fetch(ctx context.Context, reader Reader) (int, error) {
+	if c.closed {
+		return 0, nil
+	}
+
+	// Fetch the next batch
+	n, err := c.scan(ctx, reader)
+	if err != nil {
+		return 0, errors.Join(pgerror(err), c.rollback(ctx))
+	} else if n == 0 {
+		return 0, c.Close(ctx)
+	}
+
+	// Return the number of rows fetched
+	return n, nil
+}
+
+// Close the cursor and commit the transaction. It is safe to call Close
+// more than once.
+func (c *cursor) Close(ctx context.Context) error {
+	if c.closed {
+		return nil
+	}
+
+	// Close the cursor, then end the transaction
+	if _, err := c.tx.Exec(ctx, c.close); err != nil {
+		return errors.Join(pgerror(err), c.rollback(ctx))
+	}
+	c.closed = true
+	if err := c.tx.Commit(ctx); err != nil || c.outer == nil {
+		return pgerror(err)
+	}
+
+	// Close the connection to the remote database
+	if _, err := c.outer.Exec(ctx, c.disconnect()); err != nil {
+		return errors.Join(pgerror(err), c.outer.Rollback(ctx))
+	}
+	return pgerror(c.outer.Commit(ctx))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// declare a cursor for the query within a new transaction, or within a
+// savepoint when the connection is already a transaction
+func declare(ctx context.Context, conn pgx.Tx, bind *Bind, query string, batchSize uint) (Cursor, error) {
+	if batchSize == 0 {
+		batchSize = DefaultCursorBatchSize
+	}
+	if bind.dblink != "" {
+		return declareRemote(ctx, conn, bind, query, batchSize)
+	}
+
+	// Begin the transaction
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, pgerror(err)
+	}
+
+	// Declare the cursor
+	name := fmt.Sprintf("cursor_%d", cursorId.Add(1))
+	if err := bind.Exec(ctx, tx, `DECLARE `+name+` NO SCROLL CURSOR FOR `+query); err != nil {
+		return nil, errors.Join(pgerror(err), tx.Rollback(ctx))
+	}
+
+	// Return the cursor
+	return &cursor{
+		tx:    tx,
+		name:  name,
+		fetch: fmt.Sprintf(`FETCH FORWARD %d FROM %s`, batchSize, name),
+		close: `CLOSE ` + name,
+	}, nil
+}
+
+// declare a cursor for the query on the remote database with dblink, where
+// the columns of the rows are defined with the 'as' bind var
+func declareRemote(ctx context.Context, conn pgx.Tx, bind *Bind, query string, batchSize uint) (Cursor, error) {
+	query, as, err := bind.remote(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Begin the transaction, and open the connection to the remote database
+	outer, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, pgerror(err)
+	}
+	c := &cursor{outer: outer, name: fmt.Sprintf("cursor_%d", cursorId.Add(1))}
+	if _, err := outer.Exec(ctx, replace(dblinkConnect, pgx.NamedArgs{"name": c.name, "conn": bind.dblink})); err != nil {
+		return nil, errors.Join(pgerror(err), outer.Rollback(ctx))
+	}
+
+	// Open the cursor within a savepoint
+	if c.tx, err = outer.Begin(ctx); err != nil {
+		return nil, errors.Join(pgerror(err), c.rollback(ctx))
+	}
+	if _, err := c.tx.Exec(ctx, replace(dblinkOpen, pgx.NamedArgs{"name": c.name, "query": query})); err != nil {
+		return nil, errors.Join(pgerror(err), c.rollback(ctx))
+	}
+
+	// Return the cursor
+	c.fetch = replace(dblinkFetch, pgx.NamedArgs{"name": c.name, "count": batchSize, "as": as})
+	c.close = replace(dblinkClose, pgx.NamedArgs{"name": c.name})
+	return c, nil
+}
+
+func (c *cursor) scan(ctx context.Context, reader Reader) (int, error) {
+	rows, err := c.tx.Query(ctx, c.fetch)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	// Read rows
+	var n int
+	for rows.Next() {
+		if err := reader.Scan(rows); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	// Return the number of rows
+	return n, rows.Err()
+}
+
+// rollback the transaction, closing the connection to the remote database
+// of a remote cursor
+func (c *cursor) rollback(ctx context.Context) error {
+	c.closed = true
+	var err error
+	if c.tx != nil {
+		err = c.tx.Rollback(ctx)
+	}
+	if c.outer == nil {
+		return err
+	}
+	if _, disconnectErr := c.outer.Exec(ctx, c.disconnect()); disconnectErr != nil {
+		err = errors.Join(err, pgerror(disconnectErr))
+	}
+	return errors.Join(err, c.outer.Rollback(ctx))
+}
+
+// Return the statement which closes the connection to the remote database
+func (c *cursor) disconnect() string {
+	return replace(dblinkDisconnect, pgx.NamedArgs{"name": c.name})
+}
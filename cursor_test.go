@@ -0,0 +1,120 @@
+package pg_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Cursor_001(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// Fetch a series in batches
+	cursor, err := conn.With("max", 25).Cursor(context.Background(), `SELECT i, 'name' || i FROM generate_series(1, @max) AS i`, 10)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	defer cursor.Close(context.Background())
+
+	var batches []int
+	var list TestList
+	for {
+		n, err := cursor.Fetch(context.Background(), &list)
+		if !assert.NoError(err) {
+			t.FailNow()
+		} else if n == 0 {
+			break
+		}
+		batches = append(batches, n)
+	}
+	assert.Equal([]int{10, 10, 5}, batches)
+	assert.Equal(25, len(list.Tests))
+	assert.Equal("name25", list.Tests[24].Name)
+
+	// The cursor is closed once exhausted
+	n, err := cursor.Fetch(context.Background(), &list)
+	assert.NoError(err)
+	assert.Equal(0, n)
+	assert.NoError(cursor.Close(context.Background()))
+}
+
+func Test_Cursor_002(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// Declare a cursor within a transaction
+	assert.NoError(conn.Tx(context.Background(), func(conn pg.Conn) error {
+		cursor, err := conn.Cursor(context.Background(), `SELECT i, 'name' || i FROM generate_series(1, 3) AS i`, 0)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(context.Background())
+
+		var list TestList
+		n, err := cursor.Fetch(context.Background(), &list)
+		assert.Equal(3, n)
+		return err
+	}))
+}
+
+func Test_Cursor_003(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// An invalid query returns an error
+	_, err := conn.Cursor(context.Background(), `SELECT * FROM nonexistent_table`, 10)
+	assert.Error(err)
+}
+
+func Test_Cursor_004(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// Cursors on a remote database are opened with dblink
+	if !assert.NoError(conn.Exec(context.Background(), `CREATE EXTENSION IF NOT EXISTS dblink`)) {
+		t.FailNow()
+	}
+	remote := conn.Remote(conn.Params()["dbname"]).With("as", `t("id" INTEGER, "name" TEXT)`, "max", 25)
+
+	// Fetch a series in batches
+	cursor, err := remote.Cursor(context.Background(), `SELECT i, 'name' || i FROM generate_series(1, @max) AS i`, 10)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	var batches []int
+	var list TestList
+	for {
+		n, err := cursor.Fetch(context.Background(), &list)
+		if !assert.NoError(err) {
+			t.FailNow()
+		} else if n == 0 {
+			break
+		}
+		batches = append(batches, n)
+	}
+	assert.Equal([]int{10, 10, 5}, batches)
+	assert.Equal("name25", list.Tests[24].Name)
+	assert.NoError(cursor.Close(context.Background()))
+
+	// A cursor closed before the rows are exhausted
+	cursor, err = remote.Cursor(context.Background(), `SELECT i, 'name' || i FROM generate_series(1, 3) AS i`, 2)
+	if assert.NoError(err) {
+		var list TestList
+		n, err := cursor.Fetch(context.Background(), &list)
+		assert.NoError(err)
+		assert.Equal(2, n)
+		assert.NoError(cursor.Close(context.Background()))
+	}
+
+	// An invalid query returns an error
+	_, err = remote.Cursor(context.Background(), `SELECT * FROM nonexistent_table`, 10)
+	assert.Error(err)
+}
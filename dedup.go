@@ -0,0 +1,295 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	pgtype "github.com/jackc/pgx/v5/pgtype"
+	singleflight "golang.org/x/sync/singleflight"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// dedup wraps a connection so that identical concurrent queries share one
+// database execution. The result rows are buffered and replayed to each
+// caller.
+type dedup struct {
+	pgx.Tx
+	group *singleflight.Group
+}
+
+// result holds the buffered rows of a query, and the types of the
+// connection which executed it. The type map is not safe for concurrent use,
+// so values are decoded with the lock held
+type result struct {
+	sync.Mutex
+	fields  []pgconn.FieldDescription
+	values  [][][]byte
+	tag     pgconn.CommandTag
+	typeMap *pgtype.Map
+}
+
+// rows replays a buffered result
+type rows struct {
+	*result
+	index  int
+	err    error
+	closed bool
+}
+
+// row returns the first row of a buffered result
+type row struct {
+	rows pgx.Rows
+	err  error
+}
+
+// Ensure interfaces are satisfied
+var _ pgx.Tx = (*dedup)(nil)
+var _ pgx.Rows = (*rows)(nil)
+var _ pgx.Row = (*row)(nil)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - DEDUP
+
+// Query executes the query, or waits for an identical query which is already
+// executing, and returns the buffered rows
+func (d *dedup) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	key, ok := dedupKey(sql, args)
+	if !ok {
+		return d.Tx.Query(ctx, sql, args...)
+	}
+
+	// The query is not cancelled when the first caller goes away, since other
+	// callers may be waiting on the result
+	ch := d.group.DoChan(key, func() (any, error) {
+		return query(context.WithoutCancel(ctx), d.Tx, sql, args...)
+	})
+
+	// Wait for the result or the context to be cancelled
+	select {
+	case r := <-ch:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return &rows{result: r.Val.(*result), index: -1}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// QueryRow executes the query, or waits for an identical query which is
+// already executing, and returns the first buffered row
+func (d *dedup) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, err := d.Query(ctx, sql, args...)
+	return &row{rows, err}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - ROWS
+
+func (r *rows) Close() {
+	r.closed = true
+}
+
+func (r *rows) Err() error {
+	return r.err
+}
+
+func (r *rows) CommandTag() pgconn.CommandTag {
+	return r.tag
+}
+
+func (r *rows) FieldDescriptions() []pgconn.FieldDescription {
+	return r.fields
+}
+
+func (r *rows) Next() bool {
+	if r.closed {
+		return false
+	}
+	r.index++
+	if r.index >= len(r.values) {
+		r.Close()
+		return false
+	}
+	return true
+}
+
+func (r *rows) Scan(dest ...any) error {
+	r.Lock()
+	defer r.Unlock()
+	if err := pgx.ScanRow(r.typeMap, r.fields, r.RawValues(), dest...); err != nil {
+		r.err = err
+		r.Close()
+		return err
+	}
+	return nil
+}
+
+func (r *rows) Values() ([]any, error) {
+	r.Lock()
+	defer r.Unlock()
+	raw := r.RawValues()
+	values := make([]any, len(raw))
+	for i, src := range raw {
+		if src == nil {
+			continue
+		}
+		field := r.fields[i]
+		if t, ok := r.typeMap.TypeForOID(field.DataTypeOID); ok {
+			value, err := t.Codec.DecodeValue(r.typeMap, field.DataTypeOID, field.Format, src)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		} else if field.Format == pgtype.TextFormatCode {
+			values[i] = string(src)
+		} else {
+			values[i] = src
+		}
+	}
+	return values, nil
+}
+
+func (r *rows) RawValues() [][]byte {
+	if r.index < 0 || r.index >= len(r.values) {
+		return nil
+	}
+	return r.values[r.index]
+}
+
+func (r *rows) Conn() *pgx.Conn {
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - ROW
+
+func (r *row) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+
+	// Scan the first row
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// dedupKey returns a key for the query and arguments, or false if the
+// arguments cannot be used to make a key. Only scalars, pointers to scalars
+// and named arguments with scalar values make a key, with their type, so
+// that arguments which differ are never given the same key
+func dedupKey(sql string, args []any) (string, bool) {
+	var sb strings.Builder
+	sb.WriteString(sql)
+	for _, arg := range args {
+		sb.WriteByte(0)
+		if !dedupArg(&sb, arg) {
+			return "", false
+		}
+	}
+	return sb.String(), true
+}
+
+// dedupArg writes an argument with its type, or returns false if the
+// argument is not a scalar
+func dedupArg(sb *strings.Builder, arg any) bool {
+	switch v := arg.(type) {
+	case nil:
+		sb.WriteString("nil")
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		fmt.Fprintf(sb, "%T(%#v)", v, v)
+	case []byte:
+		fmt.Fprintf(sb, "[]byte(%q)", v)
+	case time.Time:
+		fmt.Fprintf(sb, "time.Time(%s)", v.Format(time.RFC3339Nano))
+	case pgx.NamedArgs:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		sb.WriteString("pgx.NamedArgs{")
+		for _, key := range keys {
+			fmt.Fprintf(sb, "%q:", key)
+			if !dedupArg(sb, v[key]) {
+				return false
+			}
+			sb.WriteByte(',')
+		}
+		sb.WriteByte('}')
+	default:
+		// Dereference pointers to scalars
+		rv := reflect.ValueOf(arg)
+		if rv.Kind() != reflect.Pointer {
+			return false
+		} else if rv.IsNil() {
+			fmt.Fprintf(sb, "%T(nil)", arg)
+		} else {
+			sb.WriteByte('*')
+			return dedupArg(sb, rv.Elem().Interface())
+		}
+	}
+	return true
+}
+
+// query executes a query and buffers the rows
+func query(ctx context.Context, conn pgx.Tx, sql string, args ...any) (*result, error) {
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Copy the field descriptions and values, which are only valid until
+	// the next call to Next, and the types registered on the connection,
+	// which is returned to the pool when the rows are closed
+	result := &result{
+		fields:  append([]pgconn.FieldDescription(nil), rows.FieldDescriptions()...),
+		typeMap: typeMap(rows),
+	}
+	for rows.Next() {
+		raw := rows.RawValues()
+		values := make([][]byte, len(raw))
+		for i, src := range raw {
+			if src != nil {
+				values[i] = append([]byte{}, src...)
+			}
+		}
+		result.values = append(result.values, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	result.tag = rows.CommandTag()
+	return result, nil
+}
+
+// typeMap returns a copy of the types registered on the connection which
+// returned the rows, or the default types if the connection is not known
+func typeMap(rows pgx.Rows) *pgtype.Map {
+	if conn := rows.Conn(); conn != nil {
+		return conn.TypeMap().Copy()
+	}
+	return pgtype.NewMap()
+}
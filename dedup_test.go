@@ -0,0 +1,92 @@
+package pg
+
+import (
+	"testing"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	pgtype "github.com/jackc/pgx/v5/pgtype"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Dedup_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// Identical queries and arguments have the same key
+	a, ok := dedupKey("SELECT 1", []any{pgx.NamedArgs{"a": 1, "b": "x"}})
+	assert.True(ok)
+	b, ok := dedupKey("SELECT 1", []any{pgx.NamedArgs{"b": "x", "a": 1}})
+	assert.True(ok)
+	assert.Equal(a, b)
+
+	// Different arguments have different keys
+	c, ok := dedupKey("SELECT 1", []any{pgx.NamedArgs{"a": "1", "b": "x"}})
+	assert.True(ok)
+	assert.NotEqual(a, c)
+
+	// Arguments of different types have different keys
+	d, ok := dedupKey("SELECT 1", []any{int64(1)})
+	assert.True(ok)
+	e, ok := dedupKey("SELECT 1", []any{int32(1)})
+	assert.True(ok)
+	assert.NotEqual(d, e)
+
+	// Pointers to scalars are dereferenced
+	value, other := "x", "x"
+	f, ok := dedupKey("SELECT 1", []any{&value})
+	assert.True(ok)
+	g, ok := dedupKey("SELECT 1", []any{&other})
+	assert.True(ok)
+	assert.Equal(f, g)
+	h, ok := dedupKey("SELECT 1", []any{(*string)(nil)})
+	assert.True(ok)
+	assert.NotEqual(f, h)
+
+	// Arguments which are not scalars are not deduplicated, including
+	// structs which differ only in unexported fields
+	type key struct{ value int }
+	_, ok = dedupKey("SELECT 1", []any{make(chan int)})
+	assert.False(ok)
+	_, ok = dedupKey("SELECT 1", []any{key{1}})
+	assert.False(ok)
+	_, ok = dedupKey("SELECT 1", []any{pgx.NamedArgs{"a": key{1}}})
+	assert.False(ok)
+}
+
+func Test_Dedup_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// Replay a buffered result
+	buffered := &result{
+		fields: []pgconn.FieldDescription{
+			{Name: "id", DataTypeOID: pgtype.Int4OID, Format: pgtype.TextFormatCode},
+			{Name: "name", DataTypeOID: pgtype.TextOID, Format: pgtype.TextFormatCode},
+		},
+		values: [][][]byte{
+			{[]byte("1"), []byte("one")},
+			{[]byte("2"), nil},
+		},
+		typeMap: pgtype.NewMap(),
+	}
+	r := &rows{result: buffered, index: -1}
+
+	var id int
+	var name *string
+	assert.True(r.Next())
+	assert.NoError(r.Scan(&id, &name))
+	assert.Equal(1, id)
+	if assert.NotNil(name) {
+		assert.Equal("one", *name)
+	}
+	assert.True(r.Next())
+	values, err := r.Values()
+	assert.NoError(err)
+	assert.Equal([]any{int32(2), nil}, values)
+	assert.False(r.Next())
+	assert.NoError(r.Err())
+
+	// A row without results returns ErrNoRows
+	empty := &row{rows: &rows{result: &result{typeMap: pgtype.NewMap()}, index: -1}}
+	assert.ErrorIs(empty.Scan(&id), pgx.ErrNoRows)
+}
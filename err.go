@@ -6,6 +6,7 @@ import (
 
 	// Packages
 	pgx "github.com/jackc/pgx/v5"
+	pgconn "github.com/jackc/pgx/v5/pgconn"
 )
 
 /////////////////////////////////////////////////////////////////////
@@ -13,6 +14,14 @@ import (
 
 type Err int
 
+// sqlError is an error returned by PostgreSQL which is mapped to an Err, so
+// that it can be checked with errors.Is, and the *pgconn.PgError can still
+// be retrieved with errors.As
+type sqlError struct {
+	err  error
+	code Err
+}
+
 /////////////////////////////////////////////////////////////////////
 // GLOBALS
 
@@ -22,6 +31,32 @@ const (
 	ErrNotImplemented
 	ErrBadParameter
 	ErrNotAvailable
+	ErrReadOnly
+	ErrUniqueViolation
+	ErrForeignKeyViolation
+	ErrCheckViolation
+	ErrSerializationFailure
+	ErrConflict
+)
+
+// SQLSTATE codes returned by PostgreSQL
+const (
+	sqlStateForeignKeyViolation  = "23503"
+	sqlStateUniqueViolation      = "23505"
+	sqlStateCheckViolation       = "23514"
+	sqlStateReadOnlyTransaction  = "25006"
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+var (
+	// Errors returned by PostgreSQL which are mapped to an Err
+	sqlStates = map[string]Err{
+		sqlStateForeignKeyViolation:  ErrForeignKeyViolation,
+		sqlStateUniqueViolation:      ErrUniqueViolation,
+		sqlStateCheckViolation:       ErrCheckViolation,
+		sqlStateSerializationFailure: ErrSerializationFailure,
+	}
 )
 
 // Error returns the string representation of the error.
@@ -37,6 +72,18 @@ func (e Err) Error() string {
 		return "bad parameter"
 	case ErrNotAvailable:
 		return "not available"
+	case ErrReadOnly:
+		return "read only"
+	case ErrUniqueViolation:
+		return "unique violation"
+	case ErrForeignKeyViolation:
+		return "foreign key violation"
+	case ErrCheckViolation:
+		return "check violation"
+	case ErrSerializationFailure:
+		return "serialization failure"
+	case ErrConflict:
+		return "conflict"
 	default:
 		return fmt.Sprint("Unknown error ", int(e))
 	}
@@ -55,9 +102,30 @@ func (e Err) Withf(format string, a ...any) error {
 /////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
+// Error returns the error returned by PostgreSQL
+func (e *sqlError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error returned by PostgreSQL, and the Err it is
+// mapped to
+func (e *sqlError) Unwrap() []error {
+	return []error{e.err, e.code}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return ErrNotFound when no rows were returned, and wrap errors returned
+// by PostgreSQL so that they can be checked with errors.Is
 func pgerror(err error) error {
+	var pgerr *pgconn.PgError
 	if errors.Is(err, pgx.ErrNoRows) {
 		return ErrNotFound
+	} else if !errors.As(err, &pgerr) {
+		return err
+	} else if code, exists := sqlStates[pgerr.Code]; exists && !errors.Is(err, code) {
+		return &sqlError{err, code}
 	} else {
 		return err
 	}
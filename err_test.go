@@ -0,0 +1,60 @@
+package pg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Err_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// PostgreSQL errors are mapped to typed errors
+	tests := map[string]Err{
+		"23505": ErrUniqueViolation,
+		"23503": ErrForeignKeyViolation,
+		"23514": ErrCheckViolation,
+		"40001": ErrSerializationFailure,
+	}
+	for code, want := range tests {
+		t.Run(code, func(t *testing.T) {
+			err := pgerror(&pgconn.PgError{Severity: "ERROR", Code: code, Message: "message"})
+			assert.ErrorIs(err, want)
+			assert.Equal("ERROR: message (SQLSTATE "+code+")", err.Error())
+
+			// The PostgreSQL error is still available
+			var pgerr *pgconn.PgError
+			if assert.ErrorAs(err, &pgerr) {
+				assert.Equal(code, pgerr.Code)
+			}
+		})
+	}
+}
+
+func Test_Err_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// Wrapped errors keep their context
+	err := pgerror(fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505"}))
+	assert.ErrorIs(err, ErrUniqueViolation)
+	assert.NotErrorIs(err, ErrCheckViolation)
+	assert.Contains(err.Error(), "insert: ")
+
+	// Errors are not wrapped more than once
+	assert.Equal(err, pgerror(err))
+
+	// Other errors are returned as-is
+	other := &pgconn.PgError{Code: "42P01"}
+	assert.Equal(error(other), pgerror(other))
+	assert.Equal(ErrNotFound, pgerror(pgx.ErrNoRows))
+	assert.Nil(pgerror(nil))
+	assert.False(errors.Is(pgerror(other), ErrUniqueViolation))
+
+	// Transient errors are still transient
+	assert.True(IsTransient(pgerror(&pgconn.PgError{Code: "40001"})))
+}
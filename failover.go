@@ -0,0 +1,97 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	// Packages
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	pgxpool "github.com/jackc/pgx/v5/pgxpool"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// failover checks that the connections of a pool with more than one host are
+// to a server with the target session attributes, and closes the connections
+// when they are not, so that new connections are made to the hosts which
+// match. This is needed when the servers change roles, such as when a standby
+// is promoted and the former primary is demoted, since the connections which
+// were made before are still open.
+type failover struct {
+	pool    *pgxpool.Pool
+	standby bool // Connections should be to a server which is in recovery
+	resets  atomic.Uint64
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	failoverQuery = `SELECT pg_is_in_recovery()`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Start checking the connections of the pool every period
+func newFailover(pool *pgxpool.Pool, period time.Duration, standby bool) *failover {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &failover{pool: pool, standby: standby, cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(f.done)
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f.check(ctx)
+			}
+		}
+	}()
+	return f
+}
+
+// Stop checking the connections
+func (f *failover) Close() {
+	f.cancel()
+	<-f.done
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Check that a connection is to a server with the target session attributes,
+// and close the connections when it is not. Errors are ignored, since the
+// pool closes connections which fail.
+func (f *failover) check(ctx context.Context) {
+	var recovery bool
+	if err := f.pool.QueryRow(ctx, failoverQuery).Scan(&recovery); err != nil {
+		return
+	} else if recovery != f.standby {
+		f.reset()
+	}
+}
+
+// Close the connections when a write failed because the server is read-only,
+// and the connections should be to a primary
+func (f *failover) observe(err error) {
+	var pgerr *pgconn.PgError
+	if f.standby || !errors.As(err, &pgerr) {
+		return
+	} else if pgerr.Code == sqlStateReadOnlyTransaction {
+		f.reset()
+	}
+}
+
+// Close the connections, so that new connections are made
+func (f *failover) reset() {
+	f.resets.Add(1)
+	f.pool.Reset()
+}
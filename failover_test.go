@@ -0,0 +1,56 @@
+package pg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// Packages
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	pgxpool "github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Failover_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// The pool does not connect until a connection is acquired
+	pool, err := pgxpool.New(context.Background(), "host=localhost port=1 connect_timeout=1")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	defer pool.Close()
+
+	// A write to a read-only server closes the connections
+	f := newFailover(pool, time.Hour, false)
+	defer f.Close()
+	f.observe(pgerror(&pgconn.PgError{Severity: "ERROR", Code: sqlStateReadOnlyTransaction}))
+	assert.Equal(uint64(1), f.resets.Load())
+
+	// Other errors do not
+	f.observe(pgerror(&pgconn.PgError{Severity: "ERROR", Code: sqlStateUniqueViolation}))
+	f.observe(context.Canceled)
+	assert.Equal(uint64(1), f.resets.Load())
+
+	// A check which fails does not
+	f.check(context.Background())
+	assert.Equal(uint64(1), f.resets.Load())
+}
+
+func Test_Failover_002(t *testing.T) {
+	assert := assert.New(t)
+
+	pool, err := pgxpool.New(context.Background(), "host=localhost port=1 connect_timeout=1")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	defer pool.Close()
+
+	// Connections to a standby are not closed after a write fails
+	f := newFailover(pool, time.Hour, true)
+	f.observe(pgerror(&pgconn.PgError{Severity: "ERROR", Code: sqlStateReadOnlyTransaction}))
+	assert.Zero(f.resets.Load())
+
+	// Close stops the checks
+	f.Close()
+}
@@ -6,7 +6,7 @@ import (
 	"sync"
 
 	// Packages
-	types "github.com/mutablelogic/go-pg/pkg/types"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
 	pgxpool "github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -94,7 +94,7 @@ func (l *listener) Listen(ctx context.Context, topic string) error {
 	}
 
 	// Listen to the topic
-	_, err := l.conn.Exec(ctx, "LISTEN "+types.DoubleQuote(topic))
+	_, err := l.conn.Exec(ctx, "LISTEN "+quote.Ident(topic))
 	return err
 }
 
@@ -109,7 +109,7 @@ func (l *listener) Unlisten(ctx context.Context, topic string) error {
 	}
 
 	// Unlisten from a topic
-	_, err := l.conn.Exec(ctx, "UNLISTEN "+types.DoubleQuote(topic))
+	_, err := l.conn.Exec(ctx, "UNLISTEN "+quote.Ident(topic))
 	return err
 }
 
@@ -0,0 +1,205 @@
+package pg
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// logger is a query tracer which writes each query to a structured logger,
+// with the duration, arguments and error
+type logger struct {
+	*slog.Logger
+	level  slog.Level
+	sample float64
+	slow   time.Duration
+	redact []string
+}
+
+// loggerStart is the context key for the query start data
+type loggerStart struct{}
+
+type loggerQuery struct {
+	sql   string
+	args  []any
+	start time.Time
+}
+
+// Ensure interfaces are satisfied
+var _ pgx.QueryTracer = (*logger)(nil)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	redacted = "[REDACTED]"
+)
+
+var (
+	// Named arguments which are always redacted when logged
+	defaultRedact = []string{"password", "secret", "token"}
+
+	// The keyword which precedes a password literal, such as in CREATE ROLE
+	// and ALTER ROLE, with the prefix of an escape string
+	rePassword = regexp.MustCompile(`(?i)\bPASSWORD\s+(E?)'`)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// newLogger creates a query logger, which logs at the given level. A sample
+// rate between zero and one logs that fraction of queries, but slow queries
+// and errors are always logged. Named arguments containing any of the redact
+// strings are logged without their values.
+func newLogger(l *slog.Logger, level slog.Level, sample float64, slow time.Duration, redact []string) *logger {
+	if l == nil {
+		return nil
+	}
+	return &logger{
+		Logger: l,
+		level:  level,
+		sample: sample,
+		slow:   slow,
+		redact: append(slices.Clone(defaultRedact), redact...),
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (l *logger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, loggerStart{}, loggerQuery{
+		sql:   data.SQL,
+		args:  data.Args,
+		start: time.Now(),
+	})
+}
+
+func (l *logger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	query, ok := ctx.Value(loggerStart{}).(loggerQuery)
+	if !ok {
+		return
+	}
+	duration := time.Since(query.start)
+
+	// Determine the level, skipping queries which are not sampled
+	level := l.level
+	switch {
+	case data.Err != nil:
+		level = slog.LevelError
+	case l.slow > 0 && duration >= l.slow:
+		level = max(level, slog.LevelWarn)
+	case l.sample > 0 && l.sample < 1 && rand.Float64() >= l.sample:
+		return
+	}
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
+	// Set the attributes
+	sql := strings.TrimSpace(query.sql)
+	attrs := []slog.Attr{
+		slog.String("sql", redactPassword(sql)),
+		slog.Duration("duration", duration),
+	}
+	if args := l.args(sql, query.args); args != nil {
+		attrs = append(attrs, slog.Any("args", args))
+	}
+	for key, value := range ContextValues(ctx) {
+		attrs = append(attrs, slog.String(key, value))
+	}
+	if data.Err != nil {
+		attrs = append(attrs, slog.String("error", data.Err.Error()))
+	}
+
+	// Log the query
+	msg := "query"
+	if level == slog.LevelWarn && data.Err == nil {
+		msg = "slow query"
+	}
+	l.LogAttrs(ctx, level, msg, attrs...)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the arguments for logging. Named arguments are limited to those
+// which appear in the query, and sensitive values are redacted.
+func (l *logger) args(sql string, values []any) any {
+	if len(values) == 0 {
+		return nil
+	}
+	if named, ok := values[0].(pgx.NamedArgs); ok {
+		result := make(map[string]any, len(named))
+		for key, value := range named {
+			if !strings.Contains(sql, "@"+key) {
+				continue
+			}
+			if l.redacted(key) {
+				result[key] = redacted
+			} else {
+				result[key] = value
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	}
+	return args(values)
+}
+
+// Return true if a named argument should be redacted
+func (l *logger) redacted(key string) bool {
+	key = strings.ToLower(key)
+	for _, redact := range l.redact {
+		if strings.Contains(key, strings.ToLower(redact)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the statement with the literals which follow the PASSWORD keyword
+// redacted, as passwords are quoted within role statements rather than
+// passed as arguments
+func redactPassword(sql string) string {
+	var result strings.Builder
+	for {
+		match := rePassword.FindStringSubmatchIndex(sql)
+		if match == nil {
+			break
+		}
+
+		// Find the end of the literal, where a quote is escaped by doubling
+		// it, or with a backslash in an escape string
+		start, end := match[1], len(sql)
+		for i := start; i < len(sql); i++ {
+			if sql[i] == '\\' && match[3] > match[2] {
+				i++
+			} else if sql[i] == '\'' && i+1 < len(sql) && sql[i+1] == '\'' {
+				i++
+			} else if sql[i] == '\'' {
+				end = i + 1
+				break
+			}
+		}
+
+		// Replace the literal
+		result.WriteString(sql[:match[2]])
+		result.WriteString("'" + redacted + "'")
+		sql = sql[end:]
+	}
+	result.WriteString(sql)
+	return result.String()
+}
@@ -0,0 +1,94 @@
+package pg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Logger_001(t *testing.T) {
+	assert := assert.New(t)
+	var buf bytes.Buffer
+	logger := newLogger(slog.New(slog.NewTextHandler(&buf, nil)), slog.LevelInfo, 0, 0, []string{"apikey"})
+
+	// Named arguments are limited to those in the query, and redacted
+	ctx := logger.TraceQueryStart(context.TODO(), nil, pgx.TraceQueryStartData{
+		SQL:  "SELECT * FROM users WHERE name = @name AND password = @password AND apikey = @apikey",
+		Args: []any{pgx.NamedArgs{"name": "alice", "password": "hunter2", "apikey": "abc", "unused": "xyz"}},
+	})
+	logger.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	out := buf.String()
+	assert.Contains(out, "level=INFO")
+	assert.Contains(out, "duration=")
+	assert.Contains(out, "alice")
+	assert.NotContains(out, "hunter2")
+	assert.NotContains(out, "abc")
+	assert.NotContains(out, "xyz")
+	assert.Contains(out, redacted)
+}
+
+func Test_Logger_002(t *testing.T) {
+	assert := assert.New(t)
+	var buf bytes.Buffer
+	logger := newLogger(slog.New(slog.NewTextHandler(&buf, nil)), slog.LevelDebug, 0, 0, nil)
+
+	// Errors are logged at the error level, with the request id
+	ctx := ContextWithRequestId(context.TODO(), "req-1")
+	ctx = logger.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	logger.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+
+	out := buf.String()
+	assert.Contains(out, "level=ERROR")
+	assert.Contains(out, "error=boom")
+	assert.Contains(out, "request_id=req-1")
+}
+
+func Test_Logger_003(t *testing.T) {
+	assert := assert.New(t)
+	var buf bytes.Buffer
+
+	// Queries at the debug level are not logged by an info handler, unless slow
+	logger := newLogger(slog.New(slog.NewTextHandler(&buf, nil)), slog.LevelDebug, 0, time.Nanosecond, nil)
+	ctx := logger.TraceQueryStart(context.TODO(), nil, pgx.TraceQueryStartData{SQL: "SELECT pg_sleep(1)"})
+	time.Sleep(time.Millisecond)
+	logger.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	assert.Contains(buf.String(), "level=WARN")
+	assert.Contains(buf.String(), "slow query")
+
+	// A very small sample rate skips fast queries
+	buf.Reset()
+	logger = newLogger(slog.New(slog.NewTextHandler(&buf, nil)), slog.LevelInfo, 1e-12, 0, nil)
+	ctx = logger.TraceQueryStart(context.TODO(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	logger.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	assert.Empty(buf.String())
+}
+
+func Test_Logger_004(t *testing.T) {
+	assert := assert.New(t)
+	var buf bytes.Buffer
+	logger := newLogger(slog.New(slog.NewTextHandler(&buf, nil)), slog.LevelInfo, 0, 0, nil)
+
+	// Password literals in role statements are redacted, including on error
+	ctx := logger.TraceQueryStart(context.TODO(), nil, pgx.TraceQueryStartData{
+		SQL: `ALTER ROLE "alice" WITH LOGIN PASSWORD 'hunter''2' VALID UNTIL '2030-01-01'`,
+	})
+	logger.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+	out := buf.String()
+	assert.NotContains(out, "hunter")
+	assert.Contains(out, "PASSWORD '"+redacted+"' VALID UNTIL '2030-01-01'")
+
+	// Escape strings are redacted, as is every password
+	assert.Equal(
+		`CREATE ROLE a PASSWORD '`+redacted+`'; CREATE ROLE b password  '`+redacted+`' LOGIN`,
+		redactPassword(`CREATE ROLE a PASSWORD E'x\\\'y'; CREATE ROLE b password  'z' LOGIN`),
+	)
+	assert.Equal("SELECT password FROM users", redactPassword("SELECT password FROM users"))
+}
@@ -0,0 +1,141 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// LSN is a position in the write-ahead log. The position after a write on
+// the primary is a token which a replica must replay past before a read
+// on the replica returns the write.
+type LSN uint64
+
+// lsnQuery selects a position in the write-ahead log
+type lsnQuery string
+
+// lsnRow scans a position in the write-ahead log, which is NULL when the
+// position is not available
+type lsnRow struct {
+	lsn   LSN
+	valid bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	lsnCurrent = `SELECT pg_current_wal_insert_lsn()::TEXT`
+	lsnReplay  = `SELECT pg_last_wal_replay_lsn()::TEXT`
+
+	// Initial and maximum delay between checks of the replayed position
+	lsnDelay    = 5 * time.Millisecond
+	lsnMaxDelay = 100 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// ParseLSN parses a position in the write-ahead log, such as "16/B374D848"
+func ParseLSN(value string) (LSN, error) {
+	hi, lo, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return 0, ErrBadParameter.Withf("invalid LSN %q", value)
+	}
+	h, err := strconv.ParseUint(hi, 16, 32)
+	if err != nil {
+		return 0, ErrBadParameter.Withf("invalid LSN %q", value)
+	}
+	l, err := strconv.ParseUint(lo, 16, 32)
+	if err != nil {
+		return 0, ErrBadParameter.Withf("invalid LSN %q", value)
+	}
+	return LSN(h<<32 | l), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (lsn LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint64(lsn)>>32, uint32(lsn))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// CurrentLSN returns the position in the write-ahead log after the last
+// write, which can be used with WaitForLSN to read the write from a replica.
+// The connection must be to the primary.
+func CurrentLSN(ctx context.Context, conn Conn) (LSN, error) {
+	var row lsnRow
+	if err := conn.Get(ctx, &row, lsnQuery(lsnCurrent)); err != nil {
+		return 0, err
+	} else if !row.valid {
+		return 0, ErrNotAvailable.With("current LSN is not available")
+	}
+	return row.lsn, nil
+}
+
+// WaitForLSN waits until the server has replayed the write-ahead log past the
+// position, so that a read returns the writes made before the position was
+// returned by CurrentLSN. It returns immediately when the server is not a
+// replica, and otherwise returns an error when the context is done.
+func WaitForLSN(ctx context.Context, conn Conn, lsn LSN) error {
+	delay := lsnDelay
+	for {
+		var row lsnRow
+		if err := conn.Get(ctx, &row, lsnQuery(lsnReplay)); err != nil {
+			return err
+		} else if !row.valid || row.lsn >= lsn {
+			return nil
+		}
+
+		// Wait before checking again
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(ErrNotAvailable.Withf("replica has not replayed LSN %v", lsn), ctx.Err())
+		case <-timer.C:
+		}
+		delay = min(delay*2, lsnMaxDelay)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECTOR
+
+func (q lsnQuery) Select(_ *Bind, op Op) (string, error) {
+	switch op {
+	case Get:
+		return string(q), nil
+	default:
+		return "", ErrNotImplemented.Withf("unsupported LSN operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (r *lsnRow) Scan(row Row) error {
+	var value *string
+	if err := row.Scan(&value); err != nil {
+		return err
+	}
+	if value == nil {
+		r.valid = false
+		return nil
+	}
+	lsn, err := ParseLSN(*value)
+	if err != nil {
+		return err
+	}
+	r.lsn, r.valid = lsn, true
+	return nil
+}
@@ -0,0 +1,87 @@
+package pg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// Packages
+	assert "github.com/stretchr/testify/assert"
+)
+
+// replica is a connection which replays a position on each query
+type replica struct {
+	Conn
+	replay []*string
+}
+
+func (r *replica) Get(_ context.Context, reader Reader, _ Selector) error {
+	value := r.replay[0]
+	if len(r.replay) > 1 {
+		r.replay = r.replay[1:]
+	}
+	return reader.Scan(lsnValue{value})
+}
+
+// lsnValue scans a single value
+type lsnValue struct {
+	value *string
+}
+
+func (r lsnValue) Scan(dest ...any) error {
+	*(dest[0].(**string)) = r.value
+	return nil
+}
+
+func Test_LSN_001(t *testing.T) {
+	assert := assert.New(t)
+
+	lsn, err := ParseLSN("16/B374D848")
+	assert.NoError(err)
+	assert.Equal(LSN(0x16B374D848), lsn)
+	assert.Equal("16/B374D848", lsn.String())
+	assert.Equal("0/0", LSN(0).String())
+
+	for _, value := range []string{"", "16", "16/", "/B374D848", "G/0", "100000000/0"} {
+		_, err := ParseLSN(value)
+		assert.ErrorIs(err, ErrBadParameter, value)
+	}
+
+	// Positions are ordered
+	a, _ := ParseLSN("0/FFFFFFFF")
+	b, _ := ParseLSN("1/0")
+	assert.Less(a, b)
+}
+
+func Test_LSN_002(t *testing.T) {
+	assert := assert.New(t)
+	behind, ahead := "0/10", "0/30"
+	lsn := LSN(0x20)
+
+	// Wait until the replica has replayed past the position
+	conn := &replica{replay: []*string{&behind, &behind, &ahead}}
+	assert.NoError(WaitForLSN(context.Background(), conn, lsn))
+	assert.Len(conn.replay, 1)
+
+	// Return immediately when the server is not a replica
+	assert.NoError(WaitForLSN(context.Background(), &replica{replay: []*string{nil}}, lsn))
+
+	// Return an error when the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := WaitForLSN(ctx, &replica{replay: []*string{&behind}}, lsn)
+	assert.ErrorIs(err, ErrNotAvailable)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func Test_LSN_003(t *testing.T) {
+	assert := assert.New(t)
+	current := "A/1"
+
+	lsn, err := CurrentLSN(context.Background(), &replica{replay: []*string{&current}})
+	assert.NoError(err)
+	assert.Equal("A/1", lsn.String())
+
+	_, err = CurrentLSN(context.Background(), &replica{replay: []*string{nil}})
+	assert.ErrorIs(err, ErrNotAvailable)
+}
@@ -1,12 +1,19 @@
 package pg
 
 import (
+	"crypto/tls"
 	"fmt"
+	"log/slog"
 	"net"
 	"net/url"
+	"os"
 	"slices"
 	"sort"
 	"strings"
+	"time"
+
+	// Packages
+	pgconn "github.com/jackc/pgx/v5/pgconn"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -16,7 +23,19 @@ type opt struct {
 	TraceFn
 	Verbose bool
 	url.Values
-	bind *Bind
+	bind      *Bind
+	dedup     bool
+	stmtcache *stmtcache
+	simple    bool
+	logger    *slog.Logger
+	loglevel  slog.Level
+	logsample float64
+	logslow   time.Duration
+	logredact []string
+	retry     *RetryPolicy
+	tls       *tls.Config
+	failover  *time.Duration
+	timeout   time.Duration
 }
 
 // Opt is a function which applies options for a connection pool
@@ -30,10 +49,48 @@ const (
 	defaultHost     = "localhost"
 	defaultDatabase = "postgres"
 	defaultMaxConns = "10"
+	poolParamPrefix = "pool_"
+
+	// Interval between checks that the connections of a pool with more than
+	// one host are to a server with the target session attributes
+	defaultFailoverPeriod = 10 * time.Second
 )
 
 var (
 	defaultScheme = []string{"postgres", "postgresql"}
+
+	// Connection parameters which are understood by libpq but not by pgx,
+	// and are only passed to external tools
+	libpqParams = []string{"gssencmode"}
+
+	// Valid values for the gssencmode parameter
+	gssEncModes = []string{"disable", "prefer", "require"}
+
+	// Valid values for the target_session_attrs parameter
+	targetSessionAttrs = []string{"any", "read-write", "read-only", "primary", "standby", "prefer-standby"}
+
+	// Valid values for the sslmode parameter
+	sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+	// Environment variables which set connection parameters, as for libpq
+	envParams = map[string]string{
+		"PGHOST":               "host",
+		"PGPORT":               "port",
+		"PGDATABASE":           "dbname",
+		"PGUSER":               "user",
+		"PGPASSWORD":           "password",
+		"PGPASSFILE":           "passfile",
+		"PGAPPNAME":            "application_name",
+		"PGOPTIONS":            "options",
+		"PGCONNECT_TIMEOUT":    "connect_timeout",
+		"PGSSLMODE":            "sslmode",
+		"PGSSLROOTCERT":        "sslrootcert",
+		"PGSSLCERT":            "sslcert",
+		"PGSSLKEY":             "sslkey",
+		"PGTARGETSESSIONATTRS": "target_session_attrs",
+		"PGGSSENCMODE":         "gssencmode",
+		"PGKRBSRVNAME":         "krbsrvname",
+	}
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -57,6 +114,39 @@ func apply(opts ...Opt) (*opt, error) {
 		}
 	}
 
+	// Check the SSL mode, which may be set in the URL, and that it does not
+	// disable the TLS configuration
+	if o.Has("sslmode") {
+		mode := strings.ToLower(strings.TrimSpace(o.Get("sslmode")))
+		if !slices.Contains(sslModes, mode) {
+			return nil, ErrBadParameter.Withf("invalid sslmode %q", o.Get("sslmode"))
+		}
+		o.Set("sslmode", mode)
+	}
+	if attrs := o.Get("target_session_attrs"); attrs != "" && !slices.Contains(targetSessionAttrs, attrs) {
+		return nil, ErrBadParameter.Withf("invalid target_session_attrs %q", attrs)
+	}
+	if o.tls != nil && o.Get("sslmode") == "disable" {
+		return nil, ErrBadParameter.With("sslmode=disable cannot be used with a TLS configuration")
+	}
+
+	// Statements are not prepared with the simple protocol, which is used
+	// whatever the order of the options
+	if o.simple {
+		if o.stmtcache != nil && o.stmtcache.capacity > 0 {
+			return nil, ErrBadParameter.With("statement cache cannot be used with the simple protocol")
+		}
+		o.Set("default_query_exec_mode", "simple_protocol")
+	}
+
+	// GSS encryption is not supported by the connection pool, only
+	// GSSAPI authentication
+	if mode := o.Get("gssencmode"); mode != "" && !slices.Contains(gssEncModes, mode) {
+		return nil, ErrBadParameter.Withf("invalid gssencmode %q", mode)
+	} else if mode == "require" {
+		return nil, ErrNotImplemented.With("gssencmode=require is not supported, use sslmode instead")
+	}
+
 	// Return success
 	return &o, nil
 }
@@ -64,15 +154,25 @@ func apply(opts ...Opt) (*opt, error) {
 ////////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
-// WithURL sets connection parameters from a PostgreSQL URL.
+// WithURL sets connection parameters from a PostgreSQL URL. Query parameters,
+// such as sslmode, sslrootcert, sslcert and sslkey, are connection parameters.
+// The URL can have more than one host, such as postgres://a:5432,b:5432/db,
+// which are used as with WithHosts.
 func WithURL(value string) Opt {
 	return func(o *opt) error {
+		value, hosts := splitHosts(value)
 		url, err := parseUrl(value)
 		if err != nil {
 			return err
 		}
-		o.Values.Set("host", url.Hostname())
-		o.Values.Set("port", url.Port())
+		if len(hosts) > 1 {
+			if err := WithHosts(hosts...)(o); err != nil {
+				return err
+			}
+		} else {
+			o.Values.Set("host", url.Hostname())
+			o.Values.Set("port", url.Port())
+		}
 		o.Values.Set("dbname", strings.TrimPrefix(url.Path, "/"))
 		if user := url.User.Username(); user != "" {
 			o.Values.Set("user", user)
@@ -80,16 +180,91 @@ func WithURL(value string) Opt {
 		if password, ok := url.User.Password(); ok {
 			o.Values.Set("password", password)
 		}
-		q := url.Query()
-		for key, values := range q {
-			for _, v := range values {
-				o.Values.Add(key, v)
+		// Parameters replace those set by earlier options, and when a
+		// parameter is repeated the last value is used
+		for key, values := range url.Query() {
+			o.Values.Set(key, values[len(values)-1])
+		}
+		return nil
+	}
+}
+
+// WithEnv sets connection parameters from the PG* environment variables
+// which are set, as used by libpq and tools such as psql: PGHOST, PGPORT,
+// PGDATABASE, PGUSER, PGPASSWORD, PGPASSFILE, PGAPPNAME, PGOPTIONS,
+// PGCONNECT_TIMEOUT, PGSSLMODE, PGSSLROOTCERT, PGSSLCERT, PGSSLKEY,
+// PGTARGETSESSIONATTRS, PGGSSENCMODE and PGKRBSRVNAME. Parameters set by
+// later options replace those from the environment.
+func WithEnv() Opt {
+	return func(o *opt) error {
+		for env, key := range envParams {
+			if value, exists := os.LookupEnv(env); exists && value != "" {
+				o.Set(key, value)
+			}
+		}
+		return nil
+	}
+}
+
+// WithHosts sets the hosts, as host or host:port, which are tried in order
+// when a connection is made. When there is more than one host and
+// target_session_attrs is not set, connections are only made to a host which
+// accepts writes, so that the pool connects to a standby once it has been
+// promoted. The connections of the pool are checked as set by
+// WithFailoverCheck.
+func WithHosts(hosts ...string) Opt {
+	return func(o *opt) error {
+		if len(hosts) == 0 {
+			return ErrBadParameter.With("no hosts")
+		}
+		names, ports := make([]string, 0, len(hosts)), make([]string, 0, len(hosts))
+		for _, host := range hosts {
+			name, port, err := splitHostPort(host)
+			if err != nil {
+				return err
 			}
+			names, ports = append(names, name), append(ports, port)
+		}
+		o.Set("host", strings.Join(names, ","))
+		o.Set("port", strings.Join(ports, ","))
+		if len(hosts) > 1 && !o.Has("target_session_attrs") {
+			o.Set("target_session_attrs", "read-write")
 		}
 		return nil
 	}
 }
 
+// WithTargetSessionAttrs sets the servers which connections are made to when
+// there is more than one host. Valid values are "any", "read-write",
+// "read-only", "primary", "standby" and "prefer-standby".
+func WithTargetSessionAttrs(attrs string) Opt {
+	return func(o *opt) error {
+		if !slices.Contains(targetSessionAttrs, attrs) {
+			return ErrBadParameter.Withf("invalid target_session_attrs %q", attrs)
+		}
+		o.Set("target_session_attrs", attrs)
+		return nil
+	}
+}
+
+// WithFailoverCheck sets the interval between checks that the connections of
+// a pool with more than one host are to a server with the target session
+// attributes, such as a primary for "read-write". When they are not, such as
+// after a standby has been promoted and the primary has been demoted, the
+// connections are closed so that new connections are made to the hosts which
+// match. A write which fails because the server is read-only also closes the
+// connections. The default interval is ten seconds, and zero disables the
+// checks.
+func WithFailoverCheck(period time.Duration) Opt {
+	return func(o *opt) error {
+		if period < 0 {
+			return ErrBadParameter.Withf("invalid failover check interval %v", period)
+		}
+		o.failover = &period
+		return nil
+	}
+}
+
 // WithCredentials sets the connection pool username and password. If the database
 // name is not set, then the username will be used as the default database name.
 func WithCredentials(user, password string) Opt {
@@ -154,16 +329,108 @@ func WithHostPort(host, port string) Opt {
 }
 
 // WithSSLMode sets the PostgreSQL SSL mode. Valid values are "disable", "allow",
-// "prefer", "require", "verify-ca", "verify-full".
+// "prefer", "require", "verify-ca", "verify-full". The "verify-ca" and
+// "verify-full" modes check the server certificate against the root
+// certificates set with WithSSLRootCert, or the system root certificates.
 func WithSSLMode(mode string) Opt {
 	return func(o *opt) error {
-		if mode != "" {
-			o.Set("sslmode", mode)
+		if mode == "" {
+			return nil
+		} else if mode = strings.ToLower(mode); !slices.Contains(sslModes, mode) {
+			return ErrBadParameter.Withf("invalid sslmode %q", mode)
+		}
+		o.Set("sslmode", mode)
+		return nil
+	}
+}
+
+// WithSSLRootCert sets the path of a file of PEM-encoded root certificates,
+// such as a CA bundle, which the server certificate is checked against.
+func WithSSLRootCert(path string) Opt {
+	return func(o *opt) error {
+		if path == "" {
+			o.Del("sslrootcert")
+		} else {
+			o.Set("sslrootcert", path)
+		}
+		return nil
+	}
+}
+
+// WithClientCert sets the paths of a PEM-encoded client certificate and key,
+// which are sent to servers which require client certificates (mTLS).
+func WithClientCert(cert, key string) Opt {
+	return func(o *opt) error {
+		if cert == "" || key == "" {
+			return ErrBadParameter.With("client certificate and key are both required")
+		}
+		o.Set("sslcert", cert)
+		o.Set("sslkey", key)
+		return nil
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of connections, which replaces
+// the configuration from sslmode, sslrootcert, sslcert and sslkey. Connections
+// are only made with TLS, and the server name is the host when it is not set
+// in the configuration. The configuration is not passed to external tools
+// such as pg_dump, which use the connection parameters instead.
+func WithTLSConfig(config *tls.Config) Opt {
+	return func(o *opt) error {
+		if config == nil {
+			return ErrBadParameter.With("tls config is nil")
+		}
+		o.tls = config.Clone()
+		return nil
+	}
+}
+
+// WithGSSEncMode sets the GSS encryption mode, which is one of "disable",
+// "prefer" or "require". The connection pool does not support GSS encryption,
+// so "require" returns an error, but the mode is passed to external tools
+// such as pg_dump which use the connection parameters.
+func WithGSSEncMode(mode string) Opt {
+	return func(o *opt) error {
+		if mode == "" {
+			o.Del("gssencmode")
+		} else if !slices.Contains(gssEncModes, mode) {
+			return ErrBadParameter.Withf("invalid gssencmode %q", mode)
+		} else {
+			o.Set("gssencmode", mode)
+		}
+		return nil
+	}
+}
+
+// WithKerberos sets the Kerberos service name and service principal name
+// used for GSSAPI authentication. If the service name is empty, "postgres"
+// is used. If the principal name is set, it is used instead of the service
+// name and host. A GSS provider must be registered with WithGSSProvider.
+func WithKerberos(srvname, spn string) Opt {
+	return func(o *opt) error {
+		if srvname != "" {
+			o.Set("krbsrvname", srvname)
+		}
+		if spn != "" {
+			o.Set("krbspn", spn)
 		}
 		return nil
 	}
 }
 
+// WithGSSProvider registers a provider for GSSAPI authentication, such as
+// Kerberos on Unix or SSPI on Windows. The provider is registered for all
+// connections in the process.
+func WithGSSProvider(fn pgconn.NewGSSFunc) Opt {
+	return func(o *opt) error {
+		if fn == nil {
+			return ErrBadParameter.With("gss provider is nil")
+		}
+		pgconn.RegisterGSSProvider(fn)
+		return nil
+	}
+}
+
 // WithTrace sets the trace function for the connection pool.
 func WithTrace(fn TraceFn) Opt {
 	return func(o *opt) error {
@@ -172,6 +439,149 @@ func WithTrace(fn TraceFn) Opt {
 	}
 }
 
+// WithLogger writes each query to a structured logger at the given level,
+// with the duration, arguments and any error. Queries which return an error
+// are logged at the error level.
+func WithLogger(logger *slog.Logger, level slog.Level) Opt {
+	return func(o *opt) error {
+		o.logger = logger
+		o.loglevel = level
+		return nil
+	}
+}
+
+// WithLogSampling logs a fraction of queries with the logger, where rate is
+// between zero and one. Slow queries and queries which return an error are
+// always logged. A rate of zero or one logs every query.
+func WithLogSampling(rate float64) Opt {
+	return func(o *opt) error {
+		if rate < 0 || rate > 1 {
+			return ErrBadParameter.Withf("invalid log sampling rate %v", rate)
+		}
+		o.logsample = rate
+		return nil
+	}
+}
+
+// WithSlowQuery logs queries which take at least the threshold duration with
+// the logger, at the warning level, regardless of sampling. A threshold of
+// zero disables slow query logging.
+func WithSlowQuery(threshold time.Duration) Opt {
+	return func(o *opt) error {
+		if threshold < 0 {
+			return ErrBadParameter.Withf("invalid slow query threshold %v", threshold)
+		}
+		o.logslow = threshold
+		return nil
+	}
+}
+
+// WithLogRedact logs named arguments which contain any of the keys, ignoring
+// case, without their values. Arguments containing "password", "secret" or
+// "token" are always redacted.
+func WithLogRedact(keys ...string) Opt {
+	return func(o *opt) error {
+		o.logredact = append(o.logredact, keys...)
+		return nil
+	}
+}
+
+// WithRetry retries Exec, Insert, Update, Delete, Get, List and transactions
+// on the connection pool after serialization failures, deadlocks, and
+// connection errors which occurred before the query was sent, with
+// exponential backoff between attempts. Operations within a transaction are
+// not retried individually; the whole transaction is retried instead. An
+// operation is not retried once rows have been scanned into the reader.
+func WithRetry(policy RetryPolicy) Opt {
+	return func(o *opt) error {
+		if policy.Attempts == 0 {
+			return ErrBadParameter.With("retry attempts must be at least one")
+		}
+		if policy.Delay < 0 || policy.MaxDelay < 0 {
+			return ErrBadParameter.With("retry delay cannot be negative")
+		}
+		if policy.MaxDelay > 0 && policy.Delay > policy.MaxDelay {
+			return ErrBadParameter.Withf("retry delay %v exceeds maximum delay %v", policy.Delay, policy.MaxDelay)
+		}
+		o.retry = &policy
+		return nil
+	}
+}
+
+// WithQueryTimeout sets statement_timeout on each connection, so that the
+// server cancels statements which run for longer, and cancels the context of
+// each operation on the pool after the timeout. Transactions are cancelled
+// when they run for longer as a whole. A timeout of zero means no timeout.
+// The Timeout option of Exec replaces the timeout for one statement.
+func WithQueryTimeout(d time.Duration) Opt {
+	return func(o *opt) error {
+		if d < 0 {
+			return ErrBadParameter.With("query timeout cannot be negative")
+		} else if d == 0 {
+			o.Del("statement_timeout")
+		} else {
+			o.Set("statement_timeout", fmt.Sprint(max(d.Milliseconds(), 1)))
+		}
+		o.timeout = d
+		return nil
+	}
+}
+
+// WithDeduplication shares one database execution between identical
+// concurrent Get and List calls on the connection pool. Reads within a
+// transaction or bulk operation are not shared.
+func WithDeduplication() Opt {
+	return func(o *opt) error {
+		o.dedup = true
+		return nil
+	}
+}
+
+// WithStatementCache caches up to n prepared statements on each connection,
+// keyed by the SQL after ${} substitution and @name rewriting, so that hot
+// queries are parsed once per connection. Hit and miss counters are returned
+// by the StatementCacheStats method of the connection pool. When n is zero,
+// statements are not prepared or cached.
+func WithStatementCache(n uint) Opt {
+	return func(o *opt) error {
+		o.Set("statement_cache_capacity", fmt.Sprint(n))
+		if n == 0 {
+			o.Set("default_query_exec_mode", "exec")
+		} else {
+			o.Set("default_query_exec_mode", "cache_statement")
+		}
+		o.stmtcache = &stmtcache{capacity: n}
+		return nil
+	}
+}
+
+// WithSimpleProtocol sends queries with the simple query protocol, with the
+// arguments interpolated by the client, and does not prepare or describe
+// statements. This is needed when connecting through a pooler in transaction
+// pooling mode, such as pgbouncer, where consecutive statements may be sent
+// on different server connections. Session state does not persist between
+// transactions in that mode, so notifications, session settings and session
+// advisory locks cannot be used.
+func WithSimpleProtocol() Opt {
+	return func(o *opt) error {
+		o.Set("default_query_exec_mode", "simple_protocol")
+		o.Set("statement_cache_capacity", "0")
+		o.Set("description_cache_capacity", "0")
+		o.simple = true
+		return nil
+	}
+}
+
+// WithLint checks each query with Lint before it is executed, and returns
+// an error rather than executing a query with a substitution which is not
+// escaped. This is intended for tests, to flag unsafe templates.
+func WithLint() Opt {
+	return func(o *opt) error {
+		o.bind.lint = true
+		return nil
+	}
+}
+
 // WithBind sets a bind variable for the connection pool.
 func WithBind(k string, v any) Opt {
 	return func(o *opt) error {
@@ -198,16 +608,135 @@ func (o *opt) encode(skip ...string) []string {
 			continue
 		}
 		if value := o.Values.Get(key); value != "" {
-			parts = append(parts, fmt.Sprintf("%v=%v", key, o.Values.Get(key)))
+			parts = append(parts, key+"="+quoteParam(value))
 		}
 	}
 
 	return parts
 }
 
-// Encode the options as a connection string
+// Return the connection parameters, excluding pool parameters
+func (o *opt) params() map[string]string {
+	params := make(map[string]string, len(o.Values))
+	for key := range o.Values {
+		if strings.HasPrefix(key, poolParamPrefix) {
+			continue
+		}
+		if value := o.Values.Get(key); value != "" {
+			params[key] = value
+		}
+	}
+	return params
+}
+
+// Return the interval between failover checks, and whether the connections
+// should be to a standby, or zero when the connections are not checked, which
+// is when there is one host or any server can be connected to
+func (o *opt) failoverCheck() (time.Duration, bool) {
+	if !strings.Contains(o.Get("host"), ",") {
+		return 0, false
+	}
+	period := defaultFailoverPeriod
+	if o.failover != nil {
+		period = *o.failover
+	}
+	switch o.Get("target_session_attrs") {
+	case "read-write", "primary":
+		return period, false
+	case "read-only", "standby":
+		return period, true
+	default:
+		return 0, false
+	}
+}
+
+// Set the TLS configuration of the connection and each fallback host, removing
+// the fallbacks which would connect to the same host again
+func (o *opt) configureTLS(config *pgconn.Config) {
+	if o.tls == nil {
+		return
+	}
+	hosts := map[string]bool{net.JoinHostPort(config.Host, fmt.Sprint(config.Port)): true}
+	fallbacks := make([]*pgconn.FallbackConfig, 0, len(config.Fallbacks))
+	for _, fallback := range config.Fallbacks {
+		if host := net.JoinHostPort(fallback.Host, fmt.Sprint(fallback.Port)); !hosts[host] {
+			hosts[host] = true
+			fallback.TLSConfig = o.tlsConfig(fallback.Host)
+			fallbacks = append(fallbacks, fallback)
+		}
+	}
+	config.TLSConfig = o.tlsConfig(config.Host)
+	config.Fallbacks = fallbacks
+}
+
+// Return the TLS configuration for a host
+func (o *opt) tlsConfig(host string) *tls.Config {
+	config := o.tls.Clone()
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+	return config
+}
+
+// Encode the options as a connection string, excluding any parameters
+// which are not understood by pgx
 func (o *opt) Encode() string {
-	return strings.Join(o.encode(), " ")
+	return strings.Join(o.encode(libpqParams...), " ")
+}
+
+// Quote a value of a connection string when it contains spaces, quotes or
+// backslashes
+func quoteParam(value string) string {
+	if !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+// Return a URL with only the first of its hosts, and the hosts in the URL, so
+// that URLs with more than one host can be parsed
+func splitHosts(value string) (string, []string) {
+	scheme, rest, found := strings.Cut(value, "://")
+	if !found {
+		return value, nil
+	}
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	authority := rest[:end]
+	userinfo, hosts := "", authority
+	if at := strings.LastIndex(authority, "@"); at >= 0 {
+		userinfo, hosts = authority[:at+1], authority[at+1:]
+	}
+	if hosts == "" {
+		return value, nil
+	}
+	list := strings.Split(hosts, ",")
+	return scheme + "://" + userinfo + list[0] + rest[end:], list
+}
+
+// Return the host and port of an address, with the default port when it is
+// not set. IPv6 addresses with a port are in brackets, such as [::1]:5432
+func splitHostPort(addr string) (string, string, error) {
+	if addr == "" {
+		return "", "", ErrBadParameter.With("empty host")
+	} else if ip := net.ParseIP(addr); ip != nil || !strings.Contains(addr, ":") {
+		return addr, DefaultPort, nil
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", "", ErrBadParameter.Withf("invalid host %q", addr)
+	}
+	if host == "" {
+		host = defaultHost
+	}
+	if port == "" {
+		port = DefaultPort
+	}
+	return host, port, nil
 }
 
 // Parse the URL
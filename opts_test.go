@@ -1,9 +1,15 @@
 package pg
 
 import (
+	"crypto/tls"
+	"log/slog"
 	"testing"
+	"time"
 
 	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	pgxpool "github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -81,3 +87,396 @@ func Test_Opts_006(t *testing.T) {
 		assert.Equal("host=localhost pool_max_conns=10 port=5432 sslmode=disable", o.Encode())
 	}
 }
+
+func Test_Opts_007(t *testing.T) {
+	assert := assert.New(t)
+
+	// Pool parameters are excluded from the connection parameters
+	o, err := apply(
+		WithCredentials("user", "password"),
+		WithSSLMode("disable"),
+	)
+	if assert.NoError(err) {
+		assert.NotNil(o)
+		assert.Equal(map[string]string{
+			"dbname":   "user",
+			"host":     "localhost",
+			"password": "password",
+			"port":     "5432",
+			"sslmode":  "disable",
+			"user":     "user",
+		}, o.params())
+	}
+}
+
+func Test_Opts_008(t *testing.T) {
+	assert := assert.New(t)
+
+	// Deduplication is disabled by default
+	o, err := apply()
+	if assert.NoError(err) {
+		assert.False(o.dedup)
+	}
+
+	// Enable deduplication
+	o, err = apply(WithDeduplication())
+	if assert.NoError(err) {
+		assert.True(o.dedup)
+	}
+}
+
+func Test_Opts_009(t *testing.T) {
+	assert := assert.New(t)
+
+	// Kerberos parameters are passed to pgx
+	o, err := apply(WithKerberos("pgsvc", "pgsvc/db.example.com@EXAMPLE.COM"))
+	if assert.NoError(err) {
+		assert.Equal("pgsvc", o.Get("krbsrvname"))
+		assert.Equal("pgsvc/db.example.com@EXAMPLE.COM", o.Get("krbspn"))
+		assert.Contains(o.Encode(), "krbsrvname=pgsvc")
+	}
+
+	// Empty values are ignored
+	o, err = apply(WithKerberos("", ""))
+	if assert.NoError(err) {
+		assert.False(o.Has("krbsrvname"))
+		assert.False(o.Has("krbspn"))
+	}
+}
+
+func Test_Opts_010(t *testing.T) {
+	assert := assert.New(t)
+
+	// GSS encryption mode is passed to external tools, but not to pgx
+	o, err := apply(WithGSSEncMode("prefer"))
+	if assert.NoError(err) {
+		assert.Equal("prefer", o.params()["gssencmode"])
+		assert.NotContains(o.Encode(), "gssencmode")
+	}
+
+	// Invalid mode
+	_, err = apply(WithGSSEncMode("invalid"))
+	assert.ErrorIs(err, ErrBadParameter)
+
+	// GSS encryption cannot be required
+	_, err = apply(WithGSSEncMode("require"))
+	assert.ErrorIs(err, ErrNotImplemented)
+
+	// Mode from the URL is also checked
+	_, err = apply(WithURL("postgres://localhost/db?gssencmode=require"))
+	assert.ErrorIs(err, ErrNotImplemented)
+
+	// Nil provider
+	_, err = apply(WithGSSProvider(nil))
+	assert.ErrorIs(err, ErrBadParameter)
+}
+
+func Test_Opts_011(t *testing.T) {
+	assert := assert.New(t)
+
+	// Statement cache sets the capacity and execution mode
+	o, err := apply(WithStatementCache(100))
+	if assert.NoError(err) {
+		assert.Contains(o.Encode(), "statement_cache_capacity=100")
+		assert.Contains(o.Encode(), "default_query_exec_mode=cache_statement")
+		if assert.NotNil(o.stmtcache) {
+			assert.Equal(uint(100), o.stmtcache.capacity)
+		}
+	}
+
+	// Zero capacity disables the cache
+	o, err = apply(WithStatementCache(0))
+	if assert.NoError(err) {
+		assert.Contains(o.Encode(), "default_query_exec_mode=exec")
+	}
+}
+
+func Test_Opts_012(t *testing.T) {
+	assert := assert.New(t)
+
+	// Logger with sampling, slow query threshold and redacted keys
+	o, err := apply(WithLogger(slog.Default(), slog.LevelDebug), WithLogSampling(0.5), WithSlowQuery(time.Second), WithLogRedact("apikey"))
+	if assert.NoError(err) {
+		assert.NotNil(o.logger)
+		assert.Equal(slog.LevelDebug, o.loglevel)
+		assert.Equal(0.5, o.logsample)
+		assert.Equal(time.Second, o.logslow)
+		assert.Equal([]string{"apikey"}, o.logredact)
+	}
+
+	// Invalid sampling rate and threshold
+	_, err = apply(WithLogSampling(1.5))
+	assert.ErrorIs(err, ErrBadParameter)
+	_, err = apply(WithSlowQuery(-time.Second))
+	assert.ErrorIs(err, ErrBadParameter)
+}
+
+func Test_Opts_013(t *testing.T) {
+	assert := assert.New(t)
+
+	// Retry policy
+	o, err := apply(WithRetry(DefaultRetryPolicy))
+	if assert.NoError(err) && assert.NotNil(o.retry) {
+		assert.Equal(DefaultRetryPolicy, *o.retry)
+	}
+
+	// Invalid policies
+	_, err = apply(WithRetry(RetryPolicy{}))
+	assert.ErrorIs(err, ErrBadParameter)
+	_, err = apply(WithRetry(RetryPolicy{Attempts: 2, Delay: time.Second, MaxDelay: time.Millisecond}))
+	assert.ErrorIs(err, ErrBadParameter)
+	_, err = apply(WithRetry(RetryPolicy{Attempts: 2, Delay: -time.Second}))
+	assert.ErrorIs(err, ErrBadParameter)
+}
+
+func Test_Opts_014(t *testing.T) {
+	assert := assert.New(t)
+
+	// SSL mode is checked and normalized
+	o, err := apply(WithSSLMode("Verify-Full"))
+	if assert.NoError(err) {
+		assert.Equal("verify-full", o.Get("sslmode"))
+	}
+	_, err = apply(WithSSLMode("invalid"))
+	assert.ErrorIs(err, ErrBadParameter)
+
+	// SSL mode in the URL replaces the mode set earlier, and is checked
+	o, err = apply(WithSSLMode("disable"), WithURL("postgres://localhost/db?sslmode=require&sslmode=VERIFY-CA"))
+	if assert.NoError(err) {
+		assert.Equal("verify-ca", o.Get("sslmode"))
+	}
+	_, err = apply(WithURL("postgres://localhost/db?sslmode=true"))
+	assert.ErrorIs(err, ErrBadParameter)
+}
+
+func Test_Opts_015(t *testing.T) {
+	assert := assert.New(t)
+
+	// Root certificates and client certificate are connection parameters
+	o, err := apply(WithSSLMode("verify-full"), WithSSLRootCert("ca.pem"), WithClientCert("client.pem", "client.key"))
+	if assert.NoError(err) {
+		assert.Equal(map[string]string{
+			"host":        "localhost",
+			"port":        "5432",
+			"sslmode":     "verify-full",
+			"sslrootcert": "ca.pem",
+			"sslcert":     "client.pem",
+			"sslkey":      "client.key",
+		}, o.params())
+	}
+
+	// Certificate and key are both required
+	_, err = apply(WithClientCert("client.pem", ""))
+	assert.ErrorIs(err, ErrBadParameter)
+}
+
+func Test_Opts_016(t *testing.T) {
+	assert := assert.New(t)
+
+	// TLS configuration cannot be nil, or used with sslmode=disable
+	_, err := apply(WithTLSConfig(nil))
+	assert.ErrorIs(err, ErrBadParameter)
+	_, err = apply(WithTLSConfig(&tls.Config{}), WithSSLMode("disable"))
+	assert.ErrorIs(err, ErrBadParameter)
+
+	// TLS configuration is used for every host, without connections which
+	// do not use TLS
+	o, err := apply(WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS13}), WithSSLMode("prefer"))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	config, err := pgconn.ParseConfig("host=a,b port=5432,5433 sslmode=prefer")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	o.configureTLS(config)
+	if assert.NotNil(config.TLSConfig) {
+		assert.Equal("a", config.TLSConfig.ServerName)
+		assert.Equal(uint16(tls.VersionTLS13), config.TLSConfig.MinVersion)
+	}
+	if assert.Len(config.Fallbacks, 1) {
+		assert.Equal("b", config.Fallbacks[0].Host)
+		if assert.NotNil(config.Fallbacks[0].TLSConfig) {
+			assert.Equal("b", config.Fallbacks[0].TLSConfig.ServerName)
+		}
+	}
+
+	// Server name in the configuration is kept
+	o, err = apply(WithTLSConfig(&tls.Config{ServerName: "db.example.com"}))
+	if assert.NoError(err) {
+		config, err := pgconn.ParseConfig("host=10.0.0.1")
+		if assert.NoError(err) {
+			o.configureTLS(config)
+			assert.Equal("db.example.com", config.TLSConfig.ServerName)
+			assert.Empty(config.Fallbacks)
+		}
+	}
+}
+
+func Test_Opts_017(t *testing.T) {
+	assert := assert.New(t)
+
+	// Connection parameters from the environment
+	t.Setenv("PGHOST", "db.example.com")
+	t.Setenv("PGPORT", "6432")
+	t.Setenv("PGUSER", "app")
+	t.Setenv("PGDATABASE", "orders")
+	t.Setenv("PGAPPNAME", "orders-api")
+	t.Setenv("PGSSLMODE", "require")
+	t.Setenv("PGPASSWORD", "")
+	o, err := apply(WithEnv())
+	if assert.NoError(err) {
+		assert.Equal("application_name=orders-api dbname=orders host=db.example.com pool_max_conns=10 port=6432 sslmode=require user=app", o.Encode())
+	}
+
+	// Later options replace the environment
+	o, err = apply(WithEnv(), WithDatabase("other"))
+	if assert.NoError(err) {
+		assert.Equal("other", o.Get("dbname"))
+	}
+
+	// Values with spaces are quoted in the connection string
+	t.Setenv("PGOPTIONS", "-c statement_timeout=5s")
+	o, err = apply(WithEnv())
+	if assert.NoError(err) {
+		assert.Contains(o.Encode(), "options='-c statement_timeout=5s'")
+	}
+}
+
+func Test_Opts_018(t *testing.T) {
+	assert := assert.New(t)
+
+	// More than one host connects to a host which accepts writes
+	o, err := apply(WithHosts("a", "b:5433", "[::1]:5434", "::2"))
+	if assert.NoError(err) {
+		assert.Equal("a,b,::1,::2", o.Get("host"))
+		assert.Equal("5432,5433,5434,5432", o.Get("port"))
+		assert.Equal("read-write", o.Get("target_session_attrs"))
+		period, standby := o.failoverCheck()
+		assert.Equal(defaultFailoverPeriod, period)
+		assert.False(standby)
+	}
+
+	// One host is not checked
+	o, err = apply(WithHosts("a"))
+	if assert.NoError(err) {
+		assert.False(o.Has("target_session_attrs"))
+		period, _ := o.failoverCheck()
+		assert.Zero(period)
+	}
+
+	// Target session attributes and the failover check can be set
+	o, err = apply(WithTargetSessionAttrs("standby"), WithHosts("a", "b"), WithFailoverCheck(time.Second))
+	if assert.NoError(err) {
+		assert.Equal("standby", o.Get("target_session_attrs"))
+		period, standby := o.failoverCheck()
+		assert.Equal(time.Second, period)
+		assert.True(standby)
+	}
+	o, err = apply(WithHosts("a", "b"), WithTargetSessionAttrs("any"))
+	if assert.NoError(err) {
+		period, _ := o.failoverCheck()
+		assert.Zero(period)
+	}
+
+	// Invalid values
+	_, err = apply(WithHosts())
+	assert.ErrorIs(err, ErrBadParameter)
+	_, err = apply(WithHosts("a", ""))
+	assert.ErrorIs(err, ErrBadParameter)
+	_, err = apply(WithTargetSessionAttrs("invalid"))
+	assert.ErrorIs(err, ErrBadParameter)
+	_, err = apply(WithFailoverCheck(-time.Second))
+	assert.ErrorIs(err, ErrBadParameter)
+}
+
+func Test_Opts_019(t *testing.T) {
+	assert := assert.New(t)
+
+	// URL with more than one host
+	o, err := apply(WithURL("postgres://user:pass@a:5432,b,[::1]:5433/db?sslmode=require"))
+	if assert.NoError(err) {
+		assert.Equal("a,b,::1", o.Get("host"))
+		assert.Equal("5432,5432,5433", o.Get("port"))
+		assert.Equal("db", o.Get("dbname"))
+		assert.Equal("user", o.Get("user"))
+		assert.Equal("pass", o.Get("password"))
+		assert.Equal("read-write", o.Get("target_session_attrs"))
+	}
+
+	// Target session attributes in the URL are used
+	o, err = apply(WithURL("postgres://a,b/db?target_session_attrs=prefer-standby"))
+	if assert.NoError(err) {
+		assert.Equal("prefer-standby", o.Get("target_session_attrs"))
+	}
+	_, err = apply(WithURL("postgres://a,b/db?target_session_attrs=invalid"))
+	assert.ErrorIs(err, ErrBadParameter)
+
+	// The connection string is understood by pgx
+	o, err = apply(WithURL("postgres://a:5432,b:5433/db"))
+	if assert.NoError(err) {
+		config, err := pgconn.ParseConfig(o.Encode())
+		if assert.NoError(err) {
+			assert.Equal("a", config.Host)
+			assert.NotNil(config.ValidateConnect)
+			hosts := []string{}
+			for _, fallback := range config.Fallbacks {
+				hosts = append(hosts, fallback.Host)
+			}
+			assert.Contains(hosts, "b")
+		}
+	}
+}
+
+func Test_Opts_020(t *testing.T) {
+	assert := assert.New(t)
+
+	// Simple protocol, without prepared statements
+	o, err := apply(WithSimpleProtocol())
+	if assert.NoError(err) {
+		assert.True(o.simple)
+		assert.Equal("simple_protocol", o.Get("default_query_exec_mode"))
+		assert.Equal("0", o.Get("statement_cache_capacity"))
+		assert.Equal("0", o.Get("description_cache_capacity"))
+		config, err := pgxpool.ParseConfig(o.Encode())
+		if assert.NoError(err) {
+			assert.Equal(pgx.QueryExecModeSimpleProtocol, config.ConnConfig.DefaultQueryExecMode)
+			assert.Zero(config.ConnConfig.StatementCacheCapacity)
+			assert.Zero(config.ConnConfig.DescriptionCacheCapacity)
+		}
+	}
+
+	// The statement cache can be disabled, but not enabled
+	o, err = apply(WithSimpleProtocol(), WithStatementCache(0))
+	if assert.NoError(err) {
+		assert.Equal("simple_protocol", o.Get("default_query_exec_mode"))
+	}
+	_, err = apply(WithStatementCache(10), WithSimpleProtocol())
+	assert.ErrorIs(err, ErrBadParameter)
+}
+
+func Test_Opts_021(t *testing.T) {
+	assert := assert.New(t)
+
+	// Query timeout, in milliseconds
+	o, err := apply(WithQueryTimeout(1500 * time.Millisecond))
+	if assert.NoError(err) {
+		assert.Equal(1500*time.Millisecond, o.timeout)
+		assert.Equal("1500", o.Get("statement_timeout"))
+		config, err := pgconn.ParseConfig(o.Encode())
+		if assert.NoError(err) {
+			assert.Equal("1500", config.RuntimeParams["statement_timeout"])
+		}
+	}
+
+	// No timeout
+	o, err = apply(WithQueryTimeout(time.Second), WithQueryTimeout(0))
+	if assert.NoError(err) {
+		assert.Zero(o.timeout)
+		assert.False(o.Has("statement_timeout"))
+	}
+
+	// Invalid timeout
+	_, err = apply(WithQueryTimeout(-time.Second))
+	assert.ErrorIs(err, ErrBadParameter)
+}
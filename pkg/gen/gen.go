@@ -0,0 +1,327 @@
+package gen
+
+import (
+	"bytes"
+	_ "embed"
+	"go/format"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Table is a table, view or materialized view and its columns, which are
+// returned by the ListColumns method of the manager
+type Table struct {
+	Schema  string
+	Name    string
+	Columns []schema.Column
+}
+
+// table is the data for the template
+type table struct {
+	Type, Var, Name string
+	Fields          []field
+	Limit           uint64
+}
+
+// field is a column of a table for the template
+type field struct {
+	Name, Column, Type, Tag string
+	PrimaryKey, Auto        bool
+}
+
+// goType is the Go type for a PostgreSQL type
+type goType struct {
+	Type   string
+	Import string
+	Null   bool // True if the type can represent NULL without a pointer
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Default maximum number of rows returned by a list
+	DefaultListLimit = 100
+)
+
+var (
+	//go:embed gen.tmpl
+	source string
+
+	// Template for the code for each table
+	tmpl = template.Must(template.New("gen").Funcs(template.FuncMap{
+		"columns": columns,
+		"insert":  insert,
+		"patch":   patch,
+		"where":   where,
+		"pk":      primaryKey,
+		"fields":  fieldsWhere,
+	}).Parse(source))
+
+	// Remove type modifiers such as "(255)" from a type
+	reTypeModifier = regexp.MustCompile(`\([^)]*\)`)
+
+	// Go types for PostgreSQL types
+	goTypes = map[string]goType{
+		"smallint":                    {Type: "int16"},
+		"integer":                     {Type: "int32"},
+		"bigint":                      {Type: "int64"},
+		"real":                        {Type: "float32"},
+		"double precision":            {Type: "float64"},
+		"numeric":                     {Type: "pgtype.Numeric", Import: "github.com/jackc/pgx/v5/pgtype", Null: true},
+		"boolean":                     {Type: "bool"},
+		"text":                        {Type: "string"},
+		"character varying":           {Type: "string"},
+		"character":                   {Type: "string"},
+		"name":                        {Type: "string"},
+		"citext":                      {Type: "string"},
+		"uuid":                        {Type: "string"},
+		"timestamp without time zone": {Type: "time.Time", Import: "time"},
+		"timestamp with time zone":    {Type: "time.Time", Import: "time"},
+		"date":                        {Type: "time.Time", Import: "time"},
+		"time without time zone":      {Type: "pgtype.Time", Import: "github.com/jackc/pgx/v5/pgtype", Null: true},
+		"interval":                    {Type: "time.Duration", Import: "time"},
+		"json":                        {Type: "json.RawMessage", Import: "encoding/json", Null: true},
+		"jsonb":                       {Type: "json.RawMessage", Import: "encoding/json", Null: true},
+		"bytea":                       {Type: "[]byte", Null: true},
+		"inet":                        {Type: "netip.Prefix", Import: "net/netip"},
+		"cidr":                        {Type: "netip.Prefix", Import: "net/netip"},
+	}
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Generate writes Go source for the package, with a struct, list and list
+// request type for each table, which implement the pg.Reader, pg.Writer and
+// pg.Selector interfaces. The source is formatted with gofmt.
+func Generate(w io.Writer, pkg string, tables ...Table) error {
+	if pkg == "" {
+		return pg.ErrBadParameter.With("package name is empty")
+	}
+	if len(tables) == 0 {
+		return pg.ErrBadParameter.With("no tables")
+	}
+
+	// Generate the code for each table, collecting the imports
+	var body bytes.Buffer
+	imports := []string{"github.com/mutablelogic/go-pg"}
+	for _, t := range tables {
+		data, err := newTable(t, &imports)
+		if err != nil {
+			return err
+		}
+		if err := tmpl.Execute(&body, data); err != nil {
+			return err
+		}
+	}
+
+	// Write the header and imports
+	var src bytes.Buffer
+	src.WriteString("// Code generated by pgmanager gen. DO NOT EDIT.\n\n")
+	src.WriteString("package " + pkg + "\n\n")
+	src.WriteString("import (\n")
+	slices.Sort(imports)
+	imports = slices.Compact(imports)
+	for _, path := range imports {
+		if !strings.Contains(path, ".") {
+			src.WriteString("\t" + strconv.Quote(path) + "\n")
+		}
+	}
+	src.WriteString("\n\t// Packages\n")
+	for _, path := range imports {
+		switch {
+		case path == "github.com/mutablelogic/go-pg":
+			src.WriteString("\tpg " + strconv.Quote(path) + "\n")
+		case strings.Contains(path, "."):
+			src.WriteString("\t" + strconv.Quote(path) + "\n")
+		}
+	}
+	src.WriteString(")\n")
+	src.Write(body.Bytes())
+
+	// Format and write the source
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the template data for a table, and append the imports it needs
+func newTable(t Table, imports *[]string) (*table, error) {
+	if t.Name == "" {
+		return nil, pg.ErrBadParameter.With("table name is empty")
+	}
+	if len(t.Columns) == 0 {
+		return nil, pg.ErrBadParameter.Withf("table %q has no columns", t.Name)
+	}
+	name := quoteIdentifier(t.Name)
+	if t.Schema != "" {
+		name = quoteIdentifier(t.Schema) + "." + name
+	}
+	result := &table{
+		Type:  goName(t.Name),
+		Name:  name,
+		Limit: DefaultListLimit,
+	}
+	result.Var = string(unicode.ToLower(rune(result.Type[0]))) + result.Type[1:]
+
+	// Set the fields
+	for _, column := range t.Columns {
+		typ := mapType(column.Type)
+		if typ.Import != "" {
+			*imports = append(*imports, typ.Import)
+		}
+		f := field{
+			Name:       goName(column.Name),
+			Column:     column.Name,
+			Type:       typ.Type,
+			PrimaryKey: column.PrimaryKey,
+			Auto:       column.Auto,
+		}
+		if column.Nullable && !typ.Null {
+			f.Type = "*" + f.Type
+		}
+
+		// Set the struct tags
+		tag := column.Name
+		if f.PrimaryKey {
+			tag += ",pk"
+		}
+		if f.Auto {
+			tag += ",auto"
+		}
+		json := column.Name
+		if column.Nullable {
+			json += ",omitempty"
+		}
+		f.Tag = "`json:" + strconv.Quote(json) + " pg:" + strconv.Quote(tag) + "`"
+		result.Fields = append(result.Fields, f)
+	}
+
+	// Return the table
+	return result, nil
+}
+
+// Return the Go type for a PostgreSQL type
+func mapType(pgtype string) goType {
+	pgtype = strings.TrimSpace(reTypeModifier.ReplaceAllString(pgtype, ""))
+	pgtype = strings.Join(strings.Fields(pgtype), " ")
+	if elem, ok := strings.CutSuffix(pgtype, "[]"); ok {
+		typ := mapType(elem)
+		typ.Type = "[]" + typ.Type
+		typ.Null = true
+		return typ
+	}
+	if typ, ok := goTypes[pgtype]; ok {
+		return typ
+	}
+	return goType{Type: "any", Null: true}
+}
+
+// Convert a name such as "user_id" to "UserId"
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(part)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	result := b.String()
+	if result == "" || !unicode.IsLetter(rune(result[0])) {
+		result = "T" + result
+	}
+	return result
+}
+
+// Quote an identifier
+func quoteIdentifier(name string) string {
+	return quote.Ident(name)
+}
+
+// Return the fields which match the filter
+func fieldsWhere(fields []field, filter string) []field {
+	var result []field
+	for _, f := range fields {
+		switch filter {
+		case "pk":
+			if f.PrimaryKey {
+				result = append(result, f)
+			}
+		case "insert":
+			if !f.Auto {
+				result = append(result, f)
+			}
+		case "update":
+			if !f.PrimaryKey && !f.Auto {
+				result = append(result, f)
+			}
+		}
+	}
+	return result
+}
+
+// Return true if there is a primary key
+func primaryKey(fields []field) bool {
+	return len(fieldsWhere(fields, "pk")) > 0
+}
+
+// Return the quoted column names
+func columns(fields []field) string {
+	result := make([]string, 0, len(fields))
+	for _, f := range fields {
+		result = append(result, quoteIdentifier(f.Column))
+	}
+	return strings.Join(result, ", ")
+}
+
+// Return the insert statement
+func insert(t *table) string {
+	fields := fieldsWhere(t.Fields, "insert")
+	if len(fields) == 0 {
+		return `INSERT INTO ` + t.Name + ` DEFAULT VALUES RETURNING ` + columns(t.Fields)
+	}
+	values := make([]string, 0, len(fields))
+	for _, f := range fields {
+		values = append(values, "@"+f.Column)
+	}
+	return `INSERT INTO ` + t.Name + ` (` + columns(fields) + `) VALUES (` + strings.Join(values, ", ") + `) RETURNING ` + columns(t.Fields)
+}
+
+// Return the columns which are set by an update
+func patch(fields []field) string {
+	result := make([]string, 0, len(fields))
+	for _, f := range fieldsWhere(fields, "update") {
+		result = append(result, quoteIdentifier(f.Column)+" = @"+f.Column)
+	}
+	return strings.Join(result, ", ")
+}
+
+// Return the condition on the primary key
+func where(fields []field) string {
+	result := make([]string, 0, len(fields))
+	for _, f := range fieldsWhere(fields, "pk") {
+		result = append(result, quoteIdentifier(f.Column)+" = @"+f.Column)
+	}
+	return strings.Join(result, " AND ")
+}
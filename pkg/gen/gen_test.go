@@ -0,0 +1,89 @@
+package gen_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	gen "github.com/mutablelogic/go-pg/pkg/gen"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Generate_001(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	err := gen.Generate(&buf, "model", gen.Table{Schema: "public", Name: "user_account", Columns: []schema.Column{
+		{Name: "id", Type: "bigint", PrimaryKey: true, Auto: true},
+		{Name: "email", Type: "character varying(255)"},
+		{Name: "tags", Type: "text[]", Nullable: true},
+		{Name: "created_at", Type: "timestamp with time zone", Nullable: true},
+		{Name: "geom", Type: "geometry"},
+	}})
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// The source should parse
+	src := buf.String()
+	_, err = parser.ParseFile(token.NewFileSet(), "model.go", src, 0)
+	assert.NoError(err)
+
+	// Check the types and tags
+	assert.Contains(src, "DO NOT EDIT")
+	assert.Contains(src, "package model")
+	assert.Contains(src, "type UserAccount struct")
+	assert.Contains(src, "type UserAccountList struct")
+	assert.Contains(src, "type UserAccountListRequest struct")
+	assert.Regexp(`Id\s+int64\s+`+"`"+`json:"id" pg:"id,pk,auto"`+"`", src)
+	assert.Regexp(`Email\s+string\s+`, src)
+	assert.Regexp(`Tags\s+\[\]string\s+`+"`"+`json:"tags,omitempty"`, src)
+	assert.Regexp(`CreatedAt\s+\*time.Time\s+`, src)
+	assert.Regexp(`Geom\s+any\s+`, src)
+	assert.Contains(src, `"time"`)
+
+	// Check the queries
+	assert.Contains(src, `INSERT INTO "public"."user_account" ("email", "tags", "created_at", "geom") VALUES (@email, @tags, @created_at, @geom)`)
+	assert.Contains(src, `WHERE "id" = @id`)
+	assert.Contains(src, `bind.Set("patch", `+"`"+`"email" = @email, "tags" = @tags, "created_at" = @created_at, "geom" = @geom`+"`)")
+}
+
+func Test_Generate_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// A table without a primary key has no get, update or delete
+	var buf bytes.Buffer
+	err := gen.Generate(&buf, "model", gen.Table{Name: "log", Columns: []schema.Column{
+		{Name: "line", Type: "text"},
+	}})
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	src := buf.String()
+	assert.Contains(src, `INSERT INTO "log" ("line")`)
+	assert.NotContains(src, "logGet")
+	assert.NotContains(src, `"time"`)
+}
+
+func Test_Generate_003(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("NoPackage", func(t *testing.T) {
+		err := gen.Generate(&bytes.Buffer{}, "", gen.Table{Name: "log", Columns: []schema.Column{{Name: "line", Type: "text"}}})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("NoTables", func(t *testing.T) {
+		err := gen.Generate(&bytes.Buffer{}, "model")
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("NoColumns", func(t *testing.T) {
+		err := gen.Generate(&bytes.Buffer{}, "model", gen.Table{Name: "log"})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
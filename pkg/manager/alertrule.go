@@ -0,0 +1,367 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// alerts holds the metrics when alert rules were last evaluated, which are
+// used to calculate the rate of disk growth
+type alerts struct {
+	sync.Mutex
+	metrics schema.AlertMetrics
+	when    time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum time to wait for a sink to accept a notification
+	alertNotifyTimeout = 10 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ListAlertRules returns the alert rules, optionally filtered by whether they
+// are enabled. The path of webhook targets is obfuscated.
+func (manager *Manager) ListAlertRules(ctx context.Context, req schema.AlertRuleListRequest) (*schema.AlertRuleList, error) {
+	var list schema.AlertRuleList
+	if err := manager.conn.List(ctx, &list, req); err != nil {
+		return nil, err
+	}
+	for i := range list.Body {
+		list.Body[i] = list.Body[i].Redacted()
+	}
+	return &list, nil
+}
+
+// GetAlertRule returns an alert rule by name. The path of a webhook target
+// is obfuscated.
+func (manager *Manager) GetAlertRule(ctx context.Context, name string) (*schema.AlertRule, error) {
+	var rule schema.AlertRule
+	if err := manager.conn.Get(ctx, &rule, schema.AlertRuleName(name)); err != nil {
+		return nil, err
+	}
+	rule = rule.Redacted()
+	return &rule, nil
+}
+
+// CreateAlertRule creates an alert rule, which is enabled unless set
+// otherwise, and returns it.
+func (manager *Manager) CreateAlertRule(ctx context.Context, meta schema.AlertRuleMeta) (_ *schema.AlertRule, err error) {
+	defer manager.audit(ctx, schema.AuditCreate, "alertrule", meta.Name, redactAlertRule(meta), &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
+	// Create the rule
+	var rule schema.AlertRule
+	if err := manager.conn.Insert(ctx, &rule, meta); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	rule = rule.Redacted()
+	return &rule, nil
+}
+
+// UpdateAlertRule updates the metric, threshold, sink, target or whether an
+// alert rule is enabled, leaving fields which are not set unchanged, and
+// returns the rule.
+func (manager *Manager) UpdateAlertRule(ctx context.Context, name string, meta schema.AlertRuleMeta) (_ *schema.AlertRule, err error) {
+	defer manager.audit(ctx, schema.AuditUpdate, "alertrule", name, redactAlertRule(meta), &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
+	// The target must be valid for the sink, so both are checked when
+	// either changes
+	if meta.Sink != "" || meta.Target != "" {
+		var rule schema.AlertRule
+		if err := manager.conn.Get(ctx, &rule, schema.AlertRuleName(name)); err != nil {
+			return nil, err
+		}
+		check := schema.AlertRuleMeta{Sink: rule.Sink, Target: rule.Target}
+		if meta.Sink != "" {
+			check.Sink = meta.Sink
+		}
+		if meta.Target != "" {
+			check.Target = meta.Target
+		}
+		if err := check.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update the rule
+	var rule schema.AlertRule
+	if err := manager.conn.Update(ctx, &rule, schema.AlertRuleName(name), meta); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	rule = rule.Redacted()
+	return &rule, nil
+}
+
+// DeleteAlertRule deletes an alert rule by name, and returns the deleted rule.
+func (manager *Manager) DeleteAlertRule(ctx context.Context, name string) (_ *schema.AlertRule, err error) {
+	defer manager.audit(ctx, schema.AuditDelete, "alertrule", name, nil, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
+	// Delete the rule
+	var rule schema.AlertRule
+	if err := manager.conn.Delete(ctx, &rule, schema.AlertRuleName(name)); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	rule = rule.Redacted()
+	return &rule, nil
+}
+
+// EvaluateAlertRules measures the metrics on the server and compares them
+// with the threshold of each enabled rule. A notification is sent when a
+// rule fires or recovers, and the state of the rule is only changed once the
+// notification is accepted, so a failed notification is retried when the
+// rules are next evaluated. Rules are not evaluated on a standby, as their
+// state cannot be written.
+func (manager *Manager) EvaluateAlertRules(ctx context.Context) error {
+	if err := manager.writable(ctx); errors.Is(err, pg.ErrReadOnly) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// Get the enabled rules
+	rules, err := manager.enabledAlertRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Measure the metrics, and the time since they were last measured. The
+	// dead tuple ratio requires a connection to each database, so is only
+	// measured when a rule uses it
+	var metrics schema.AlertMetrics
+	if err := manager.conn.Get(ctx, &metrics, metrics); err != nil {
+		return err
+	}
+	if slices.ContainsFunc(rules, func(rule schema.AlertRule) bool { return rule.Metric == schema.AlertDeadTuples }) {
+		if metrics.DeadTuples, err = manager.deadTupleRatio(ctx); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	manager.alerts.Lock()
+	previous, elapsed := manager.alerts.metrics, now.Sub(manager.alerts.when)
+	manager.alerts.metrics, manager.alerts.when = metrics, now
+	manager.alerts.Unlock()
+
+	// Evaluate each rule
+	var result error
+	for _, rule := range rules {
+		value := metrics.Value(rule.Metric, previous, elapsed)
+		firing := value > types.PtrFloat64(rule.Threshold)
+		if firing != rule.Firing {
+			if err := manager.notify(ctx, rule, schema.AlertNotification{
+				Rule:      rule.Name,
+				Metric:    rule.Metric,
+				Value:     value,
+				Threshold: types.PtrFloat64(rule.Threshold),
+				Firing:    firing,
+				Timestamp: now,
+			}); err != nil {
+				result = errors.Join(result, fmt.Errorf("alert rule %q: %w", rule.Name, err))
+				continue
+			}
+		}
+		state := schema.AlertRuleState{Name: rule.Name, Firing: firing, Value: value}
+		if err := manager.conn.Update(ctx, nil, state, state); err != nil {
+			result = errors.Join(result, err)
+		}
+	}
+
+	// Return any errors
+	return result
+}
+
+// RunAlertRules evaluates the alert rules at an interval until the context is
+// cancelled. Errors evaluating the rules are passed to the error function,
+// which can be nil.
+func (manager *Manager) RunAlertRules(ctx context.Context, interval time.Duration, errfn func(error)) error {
+	if interval <= 0 {
+		return pg.ErrBadParameter.With("interval must be positive")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := manager.EvaluateAlertRules(ctx); err != nil && errfn != nil && ctx.Err() == nil {
+			errfn(err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListAlerts returns the enabled alert rules which are firing. The path of
+// webhook targets is obfuscated.
+func (manager *Manager) ListAlerts(ctx context.Context, req schema.AlertRuleListRequest) (*schema.AlertRuleList, error) {
+	req.Enabled = types.BoolPtr(true)
+	req.Firing = types.BoolPtr(true)
+	return manager.ListAlertRules(ctx, req)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Create the alert rules set with WithAlertRules, or update them when they
+// already exist, so that the configuration replaces any changes made through
+// the API. Nothing is written on a standby.
+func (manager *Manager) applyAlertRules(ctx context.Context) error {
+	if len(manager.opt.alertrules) == 0 {
+		return nil
+	} else if err := manager.writable(ctx); errors.Is(err, pg.ErrReadOnly) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, meta := range manager.opt.alertrules {
+		var rule schema.AlertRule
+		if err := manager.conn.Update(ctx, &rule, schema.AlertRuleName(meta.Name), meta); errors.Is(err, pg.ErrNotFound) {
+			if _, err := manager.CreateAlertRule(ctx, meta); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Return the maximum ratio of dead tuples in a table across all databases
+func (manager *Manager) deadTupleRatio(ctx context.Context) (float64, error) {
+	var result float64
+	if _, err := manager.withDatabases(ctx, func(database *schema.Database) error {
+		var ratio schema.AlertDeadTupleRatio
+		if err := manager.conn.Remote(database.Name).With("as", schema.AlertDeadTupleDef).Get(ctx, &ratio, ratio); err != nil {
+			return err
+		}
+		result = max(result, float64(ratio))
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// Return every enabled alert rule, without obfuscating the targets
+func (manager *Manager) enabledAlertRules(ctx context.Context) ([]schema.AlertRule, error) {
+	var rules []schema.AlertRule
+	req := schema.AlertRuleListRequest{Enabled: types.BoolPtr(true)}
+	req.Limit = types.Uint64Ptr(schema.AlertRuleListLimit)
+	for {
+		var list schema.AlertRuleList
+		if err := manager.conn.List(ctx, &list, req); err != nil {
+			return nil, err
+		}
+		rules = append(rules, list.Body...)
+		if req.Offset += uint64(len(list.Body)); len(list.Body) == 0 || req.Offset >= list.Count {
+			return rules, nil
+		}
+	}
+}
+
+// Send a notification to the sink of a rule
+func (manager *Manager) notify(ctx context.Context, rule schema.AlertRule, notification schema.AlertNotification) error {
+	ctx, cancel := context.WithTimeout(ctx, alertNotifyTimeout)
+	defer cancel()
+
+	switch rule.Sink {
+	case schema.AlertWebhook:
+		return notifyPost(ctx, rule.Target, notification)
+	case schema.AlertSlack:
+		return notifyPost(ctx, rule.Target, map[string]string{"text": notification.Message()})
+	case schema.AlertEmail:
+		return manager.notifyEmail(rule.Target, notification)
+	default:
+		return pg.ErrBadParameter.Withf("invalid sink %q", rule.Sink)
+	}
+}
+
+// Post a notification as JSON to a URL
+func notifyPost(ctx context.Context, url string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(types.ContentTypeHeader, types.ContentTypeJSON)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Send a notification by email, which requires a mail server to be set
+// with WithSMTP
+func (manager *Manager) notifyEmail(to string, notification schema.AlertNotification) error {
+	if manager.opt.smtp.addr == "" {
+		return pg.ErrNotAvailable.With("no mail server for email notifications")
+	}
+	var auth smtp.Auth
+	if manager.opt.smtp.user != "" {
+		host, _, _ := net.SplitHostPort(manager.opt.smtp.addr)
+		auth = smtp.PlainAuth("", manager.opt.smtp.user, manager.opt.smtp.password, host)
+	}
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", manager.opt.smtp.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", notification.Message())
+	fmt.Fprintf(&msg, "Date: %s\r\n\r\n", notification.Timestamp.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "%s\r\n", notification.Message())
+	return smtp.SendMail(manager.opt.smtp.addr, auth, manager.opt.smtp.from, []string{to}, []byte(msg.String()))
+}
+
+// Return the rule for the audit log, with the target obfuscated
+func redactAlertRule(meta schema.AlertRuleMeta) schema.AlertRuleMeta {
+	return schema.AlertRule{AlertRuleMeta: meta}.Redacted().AlertRuleMeta
+}
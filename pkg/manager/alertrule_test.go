@@ -0,0 +1,161 @@
+package manager_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// ALERT RULE TESTS
+
+func Test_Manager_AlertRule(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Webhook sink which records notifications
+	var notifications []schema.AlertNotification
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification schema.AlertNotification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err == nil {
+			notifications = append(notifications, notification)
+		}
+	}))
+	defer sink.Close()
+
+	t.Run("CreateGetDelete", func(t *testing.T) {
+		rule, err := mgr.CreateAlertRule(context.TODO(), schema.AlertRuleMeta{
+			Name:      "alert_crud",
+			Metric:    schema.AlertWraparound,
+			Threshold: types.Float64Ptr(1e9),
+			Sink:      schema.AlertWebhook,
+			Target:    sink.URL + "/secret",
+		})
+		if !assert.NoError(err) {
+			return
+		}
+		assert.True(types.PtrBool(rule.Enabled))
+		assert.NotContains(rule.Target, "secret")
+
+		rule, err = mgr.UpdateAlertRule(context.TODO(), "alert_crud", schema.AlertRuleMeta{Enabled: types.BoolPtr(false)})
+		if assert.NoError(err) {
+			assert.False(types.PtrBool(rule.Enabled))
+			assert.Equal(float64(1e9), types.PtrFloat64(rule.Threshold))
+		}
+
+		_, err = mgr.DeleteAlertRule(context.TODO(), "alert_crud")
+		assert.NoError(err)
+		_, err = mgr.GetAlertRule(context.TODO(), "alert_crud")
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("InvalidTarget", func(t *testing.T) {
+		_, err := mgr.CreateAlertRule(context.TODO(), schema.AlertRuleMeta{
+			Name:      "alert_invalid",
+			Metric:    schema.AlertLag,
+			Threshold: types.Float64Ptr(0),
+			Sink:      schema.AlertEmail,
+			Target:    "not an address",
+		})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("FireAndRecover", func(t *testing.T) {
+		// At least this connection is in use, so the rule fires
+		_, err := mgr.CreateAlertRule(context.TODO(), schema.AlertRuleMeta{
+			Name:      "alert_connections",
+			Metric:    schema.AlertConnections,
+			Threshold: types.Float64Ptr(0),
+			Sink:      schema.AlertWebhook,
+			Target:    sink.URL,
+		})
+		if !assert.NoError(err) {
+			return
+		}
+		defer mgr.DeleteAlertRule(context.TODO(), "alert_connections")
+
+		if !assert.NoError(mgr.EvaluateAlertRules(context.TODO())) {
+			return
+		}
+		rule, err := mgr.GetAlertRule(context.TODO(), "alert_connections")
+		if assert.NoError(err) {
+			assert.True(rule.Firing)
+			assert.NotNil(rule.Changed)
+		}
+		if assert.Len(notifications, 1) {
+			assert.True(notifications[0].Firing)
+		}
+		alerts, err := mgr.ListAlerts(context.TODO(), schema.AlertRuleListRequest{})
+		if assert.NoError(err) {
+			assert.Equal(uint64(1), alerts.Count)
+		}
+
+		// No notification while the rule is still firing
+		assert.NoError(mgr.EvaluateAlertRules(context.TODO()))
+		assert.Len(notifications, 1)
+
+		// Raise the threshold, so the rule recovers
+		_, err = mgr.UpdateAlertRule(context.TODO(), "alert_connections", schema.AlertRuleMeta{Threshold: types.Float64Ptr(100)})
+		assert.NoError(err)
+		assert.NoError(mgr.EvaluateAlertRules(context.TODO()))
+		if assert.Len(notifications, 2) {
+			assert.False(notifications[1].Firing)
+		}
+	})
+
+	t.Run("EmailNotAvailable", func(t *testing.T) {
+		_, err := mgr.CreateAlertRule(context.TODO(), schema.AlertRuleMeta{
+			Name:      "alert_email",
+			Metric:    schema.AlertConnections,
+			Threshold: types.Float64Ptr(0),
+			Sink:      schema.AlertEmail,
+			Target:    "dba@example.com",
+		})
+		if !assert.NoError(err) {
+			return
+		}
+		defer mgr.DeleteAlertRule(context.TODO(), "alert_email")
+
+		// Without a mail server, the notification fails and the rule does not fire
+		assert.ErrorIs(mgr.EvaluateAlertRules(context.TODO()), pg.ErrNotAvailable)
+		rule, err := mgr.GetAlertRule(context.TODO(), "alert_email")
+		if assert.NoError(err) {
+			assert.False(rule.Firing)
+		}
+	})
+
+	t.Run("ConfigRules", func(t *testing.T) {
+		rule, err := schema.ParseAlertRule("alert_config dead_tuples>0.5 webhook " + sink.URL)
+		if !assert.NoError(err) {
+			return
+		}
+		mgr, err := manager.New(context.TODO(), conn, manager.WithAlertRules(rule))
+		if !assert.NoError(err) {
+			return
+		}
+		defer mgr.DeleteAlertRule(context.TODO(), "alert_config")
+
+		// The rule is created, and the dead tuples are measured in each database
+		created, err := mgr.GetAlertRule(context.TODO(), "alert_config")
+		if assert.NoError(err) {
+			assert.Equal(schema.AlertDeadTuples, created.Metric)
+			assert.True(types.PtrBool(created.Enabled))
+		}
+		assert.NoError(mgr.EvaluateAlertRules(context.TODO()))
+	})
+}
@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"context"
+	"errors"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ListAudit returns the create, update and delete operations recorded in the
+// audit log, most recent first, optionally filtered by resource, actor and
+// time range.
+func (manager *Manager) ListAudit(ctx context.Context, req schema.AuditListRequest) (*schema.AuditList, error) {
+	var list schema.AuditList
+	if err := manager.conn.List(ctx, &list, req); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Record an operation and its result in the audit log, and join any error
+// recording it with the result of the operation. The actor is the user set
+// in the context with pg.ContextWithUser, or the current role. Nothing is
+// recorded on a standby, as the audit log cannot be written.
+func (manager *Manager) audit(ctx context.Context, op schema.AuditOperation, resource, name string, req any, result *error) {
+	if errors.Is(*result, pg.ErrReadOnly) {
+		return
+	}
+
+	// Make the record
+	meta := schema.AuditMeta{
+		Resource:  resource,
+		Operation: op,
+		Name:      name,
+	}
+	if user, exists := pg.ContextValues(ctx)[string(pg.ContextKeyUser)]; exists {
+		meta.Actor = types.StringPtr(user)
+	}
+	if req != nil {
+		if request, err := schema.AuditRequest(req); err != nil {
+			*result = errors.Join(*result, err)
+			return
+		} else {
+			meta.Request = request
+		}
+	}
+	if *result != nil {
+		meta.Error = types.StringPtr((*result).Error())
+	}
+
+	// Record the operation, even if the request was cancelled
+	ctx = context.WithoutCancel(ctx)
+	if err := manager.conn.Insert(ctx, nil, meta); err != nil {
+		// Operations which are allowed on a standby are not recorded
+		if errors.Is(manager.writable(ctx), pg.ErrReadOnly) {
+			return
+		}
+		*result = errors.Join(*result, err)
+	}
+}
@@ -0,0 +1,96 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// AUDIT TESTS
+
+func Test_Manager_Audit(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	since := time.Now().Add(-time.Minute)
+	ctx := pg.ContextWithUser(context.TODO(), "audit_user")
+
+	t.Run("CreateAndDelete", func(t *testing.T) {
+		_, err := mgr.CreateRole(ctx, schema.RoleMeta{Name: "audit_role", Password: types.StringPtr("secret")})
+		if !assert.NoError(err) {
+			return
+		}
+		_, err = mgr.DeleteRole(ctx, "audit_role")
+		assert.NoError(err)
+
+		list, err := mgr.ListAudit(context.TODO(), schema.AuditListRequest{
+			Resource: types.StringPtr("role"),
+			Actor:    types.StringPtr("audit_user"),
+			Since:    &since,
+		})
+		if assert.NoError(err) && assert.Len(list.Body, 2) {
+			// Most recent first
+			assert.Equal(schema.AuditDelete, list.Body[0].Operation)
+			assert.Equal(schema.AuditCreate, list.Body[1].Operation)
+			assert.Equal("audit_role", list.Body[1].Name)
+			assert.Nil(list.Body[1].Error)
+			assert.NotContains(string(list.Body[1].Request), "secret")
+		}
+	})
+
+	t.Run("RecordsError", func(t *testing.T) {
+		_, err := mgr.DeleteRole(ctx, "audit_role_missing")
+		assert.Error(err)
+
+		list, err := mgr.ListAudit(context.TODO(), schema.AuditListRequest{
+			Resource: types.StringPtr("role"),
+			Since:    &since,
+		})
+		if assert.NoError(err) && assert.NotEmpty(list.Body) {
+			assert.Equal("audit_role_missing", list.Body[0].Name)
+			assert.NotNil(list.Body[0].Error)
+		}
+	})
+
+	t.Run("CurrentRole", func(t *testing.T) {
+		_, err := mgr.CreateRole(context.TODO(), schema.RoleMeta{Name: "audit_role2"})
+		if !assert.NoError(err) {
+			return
+		}
+		defer mgr.DeleteRole(context.TODO(), "audit_role2")
+
+		list, err := mgr.ListAudit(context.TODO(), schema.AuditListRequest{
+			Resource: types.StringPtr("role"),
+			Since:    &since,
+		})
+		if assert.NoError(err) && assert.NotEmpty(list.Body) {
+			assert.Equal("audit_role2", list.Body[0].Name)
+			assert.NotEqual("audit_user", types.PtrString(list.Body[0].Actor))
+		}
+	})
+
+	t.Run("Until", func(t *testing.T) {
+		list, err := mgr.ListAudit(context.TODO(), schema.AuditListRequest{
+			Until: &since,
+		})
+		if assert.NoError(err) {
+			for _, audit := range list.Body {
+				assert.True(audit.Timestamp.Before(since))
+			}
+		}
+	})
+}
@@ -0,0 +1,283 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// backup holds the session for a base backup, which needs to remain open
+// between pg_backup_start and pg_backup_stop. The stop channel is buffered so
+// that StopBackup never blocks when the backup has been abandoned. The done
+// channel is closed when the session has ended, with any error in err
+type backup struct {
+	schema.Backup
+	stop     chan schema.BackupStopRequest
+	done     chan struct{}
+	err      error
+	stopping bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+// Connection parameters which are understood by pgx but not by libpq
+var pgxParams = []string{
+	"statement_cache_capacity",
+	"description_cache_capacity",
+	"default_query_exec_mode",
+	"statement_timeout",
+	"krbspn",
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - BACKUP
+
+// StartBackup starts a base backup with pg_backup_start. A database session,
+// and so a connection from the pool, is held open until StopBackup is called
+// or the backup timeout expires, when the backup is abandoned. Only one backup
+// can be in progress at any time.
+func (manager *Manager) StartBackup(ctx context.Context, meta schema.BackupMeta) (*schema.Backup, error) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	// Check for a backup in progress, or one which has stopped but has not
+	// been returned by StopBackup
+	if manager.backup != nil && manager.backup.stopping {
+		return nil, pg.ErrBadParameter.Withf("backup %q is stopping, call StopBackup to return it", manager.backup.Label)
+	} else if manager.backup != nil {
+		return nil, pg.ErrBadParameter.Withf("backup %q is already in progress", manager.backup.Label)
+	}
+
+	// Create the backup
+	b := &backup{
+		stop: make(chan schema.BackupStopRequest, 1),
+		done: make(chan struct{}),
+	}
+	b.BackupMeta = meta
+
+	// Run the backup session in the background, waiting for the stop request
+	// before calling pg_backup_stop on the same session. The session outlives
+	// the request, so it is not cancelled with the request context
+	started := make(chan error, 1)
+	go func(ctx context.Context) {
+		var ok bool
+		err := manager.conn.Tx(ctx, func(conn pg.Conn) error {
+			if err := conn.Insert(ctx, &b.Backup, meta); err != nil {
+				return err
+			}
+			ok = true
+			started <- nil
+			req, err := manager.waitBackup(b)
+			if err != nil {
+				return err
+			}
+			return conn.Delete(ctx, schema.BackupStopReader{Backup: &b.Backup}, req)
+		})
+		if !ok {
+			started <- err
+		} else {
+			b.err = err
+			close(b.done)
+		}
+	}(context.WithoutCancel(ctx))
+
+	// Wait for the backup to start
+	if err := <-started; err != nil {
+		return nil, err
+	}
+
+	// Set the backup in progress
+	manager.backup = b
+
+	// Return a copy of the backup
+	result := b.Backup
+	return &result, nil
+}
+
+// GetBackup returns the backup in progress, or ErrNotFound if there is
+// no backup in progress.
+func (manager *Manager) GetBackup(ctx context.Context) (*schema.Backup, error) {
+	manager.Lock()
+	defer manager.Unlock()
+
+	if manager.backup == nil {
+		return nil, pg.ErrNotFound.With("no backup in progress")
+	}
+
+	// Return a copy of the backup
+	result := manager.backup.Backup
+	return &result, nil
+}
+
+// StopBackup completes the backup in progress with pg_backup_stop, and returns
+// the backup including the contents of the backup_label and tablespace_map
+// files, which need to be stored with the backup. Returns ErrNotFound if there
+// is no backup in progress. When the context is cancelled before the backup
+// has stopped, the backup continues to stop, and is returned by the next call.
+func (manager *Manager) StopBackup(ctx context.Context, req schema.BackupStopRequest) (*schema.Backup, error) {
+	b, err := func() (*backup, error) {
+		manager.Lock()
+		defer manager.Unlock()
+		if manager.backup == nil {
+			return nil, pg.ErrNotFound.With("no backup in progress")
+		}
+		b := manager.backup
+		if !b.stopping {
+			b.stopping = true
+			b.stop <- req
+		}
+		return b, nil
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for the backup to complete
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	// The backup is no longer in progress
+	manager.Lock()
+	if manager.backup == b {
+		manager.backup = nil
+	}
+	manager.Unlock()
+
+	// Return the backup
+	if b.err != nil {
+		return nil, b.err
+	}
+	result := b.Backup
+	return &result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - DUMP
+
+// DumpDatabase writes a dump of the named database to the writer, by running
+// the pg_dump command with the connection parameters of the manager. Returns
+// ErrNotAvailable if the pg_dump command cannot be found.
+func (manager *Manager) DumpDatabase(ctx context.Context, name string, req schema.DatabaseDumpRequest, w io.Writer) error {
+	if name == "" {
+		return pg.ErrBadParameter.With("name is empty")
+	} else if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Check the database exists
+	if _, err := manager.GetDatabase(ctx, name); err != nil {
+		return err
+	}
+
+	// Create the command
+	var stderr bytes.Buffer
+	cmd := manager.command(ctx, manager.opt.pgdump, name, req.Args()...)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+
+	// Run the command
+	if err := cmd.Run(); err != nil {
+		return commandError(manager.opt.pgdump, err, stderr.String())
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// waitBackup returns the stop request for a backup, or abandons the backup
+// when it is not stopped within the backup timeout, so that its session is
+// ended and the connection is returned to the pool
+func (manager *Manager) waitBackup(b *backup) (schema.BackupStopRequest, error) {
+	timer := time.NewTimer(manager.opt.backup)
+	defer timer.Stop()
+	select {
+	case req := <-b.stop:
+		return req, nil
+	case <-timer.C:
+	}
+
+	manager.Lock()
+	defer manager.Unlock()
+
+	// The backup was stopped as the timeout expired
+	if b.stopping {
+		return <-b.stop, nil
+	}
+
+	// Abandon the backup
+	manager.backup = nil
+	return schema.BackupStopRequest{}, pg.ErrConflict.Withf("backup %q was not stopped within %v", b.Label, manager.opt.backup)
+}
+
+// command returns a command which connects to the named database with the
+// connection parameters of the manager, passing the password through the
+// environment
+func (manager *Manager) command(ctx context.Context, path, database string, args ...string) *exec.Cmd {
+	params := manager.conn.Params()
+	params["dbname"] = database
+	password := params["password"]
+	delete(params, "password")
+
+	// Create the command
+	cmd := exec.CommandContext(ctx, path, append(args, "--dbname="+connString(params))...)
+	cmd.Env = os.Environ()
+	if password != "" {
+		cmd.Env = append(cmd.Env, "PGPASSWORD="+password)
+	}
+	return cmd
+}
+
+// commandError returns ErrNotAvailable if the command could not be found,
+// or otherwise the error with any message written to stderr
+func commandError(path string, err error, stderr string) error {
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+		return pg.ErrNotAvailable.With(path)
+	} else if message := strings.TrimSpace(stderr); message != "" {
+		return fmt.Errorf("%w: %s", err, message)
+	}
+	return err
+}
+
+// connString returns a libpq connection string from connection parameters,
+// skipping any parameters which are specific to pgx
+func connString(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if !slices.Contains(pgxParams, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	// Quote values and join
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := strings.ReplaceAll(params[key], `\`, `\\`)
+		value = strings.ReplaceAll(value, `'`, `\'`)
+		parts = append(parts, key+"='"+value+"'")
+	}
+	return strings.Join(parts, " ")
+}
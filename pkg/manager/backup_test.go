@@ -0,0 +1,204 @@
+package manager_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// BACKUP TESTS
+
+func Test_Manager_Backup(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("GetWithoutBackup", func(t *testing.T) {
+		_, err := mgr.GetBackup(context.TODO())
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("StopWithoutBackup", func(t *testing.T) {
+		_, err := mgr.StopBackup(context.TODO(), schema.BackupStopRequest{})
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("StartWithoutLabel", func(t *testing.T) {
+		_, err := mgr.StartBackup(context.TODO(), schema.BackupMeta{})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("StartStop", func(t *testing.T) {
+		backup, err := mgr.StartBackup(context.TODO(), schema.BackupMeta{Label: "test", Fast: true})
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		assert.Equal("test", backup.Label)
+		assert.NotEmpty(backup.StartLSN)
+
+		// Get the backup in progress
+		current, err := mgr.GetBackup(context.TODO())
+		assert.NoError(err)
+		assert.Equal(backup.StartLSN, current.StartLSN)
+
+		// A second backup cannot be started
+		_, err = mgr.StartBackup(context.TODO(), schema.BackupMeta{Label: "test2"})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+
+		// Stop the backup
+		stopped, err := mgr.StopBackup(context.TODO(), schema.BackupStopRequest{})
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		assert.NotNil(stopped.StopLSN)
+		assert.NotNil(stopped.StopTime)
+		if assert.NotNil(stopped.LabelFile) {
+			assert.Contains(*stopped.LabelFile, "LABEL: test")
+		}
+
+		// No backup in progress
+		_, err = mgr.GetBackup(context.TODO())
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("StartWithCancelledContext", func(t *testing.T) {
+		// The session outlives the context of the request which started it
+		ctx, cancel := context.WithCancel(context.TODO())
+		_, err := mgr.StartBackup(ctx, schema.BackupMeta{Label: "test", Fast: true})
+		cancel()
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		stopped, err := mgr.StopBackup(context.TODO(), schema.BackupStopRequest{})
+		if assert.NoError(err) {
+			assert.NotNil(stopped.StopLSN)
+		}
+	})
+
+	t.Run("StopWithCancelledContext", func(t *testing.T) {
+		_, err := mgr.StartBackup(context.TODO(), schema.BackupMeta{Label: "test", Fast: true})
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+
+		// The backup continues to stop when the context is cancelled
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		_, err = mgr.StopBackup(ctx, schema.BackupStopRequest{})
+		assert.ErrorIs(err, context.Canceled)
+
+		// A new backup cannot be started until the stopped backup is returned
+		_, err = mgr.StartBackup(context.TODO(), schema.BackupMeta{Label: "test2"})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+
+		// The stopped backup is returned by the next call
+		stopped, err := mgr.StopBackup(context.TODO(), schema.BackupStopRequest{})
+		if assert.NoError(err) {
+			assert.NotNil(stopped.StopLSN)
+			if assert.NotNil(stopped.LabelFile) {
+				assert.Contains(*stopped.LabelFile, "LABEL: test")
+			}
+		}
+		_, err = mgr.GetBackup(context.TODO())
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+}
+
+func Test_Manager_BackupTimeout(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn, manager.WithBackupTimeout(time.Second))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Abandon", func(t *testing.T) {
+		_, err := mgr.StartBackup(context.TODO(), schema.BackupMeta{Label: "test", Fast: true})
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+
+		// The backup is abandoned after the timeout
+		assert.Eventually(func() bool {
+			_, err := mgr.GetBackup(context.TODO())
+			return errors.Is(err, pg.ErrNotFound)
+		}, 5*time.Second, 100*time.Millisecond)
+
+		// Another backup can be started
+		_, err = mgr.StartBackup(context.TODO(), schema.BackupMeta{Label: "test2", Fast: true})
+		if assert.NoError(err) {
+			_, err = mgr.StopBackup(context.TODO(), schema.BackupStopRequest{})
+			assert.NoError(err)
+		}
+	})
+
+	t.Run("NegativeTimeout", func(t *testing.T) {
+		_, err := manager.New(context.TODO(), conn, manager.WithBackupTimeout(-time.Second))
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// DUMP TESTS
+
+func Test_Manager_DumpDatabase(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("EmptyName", func(t *testing.T) {
+		err := mgr.DumpDatabase(context.TODO(), "", schema.DatabaseDumpRequest{}, &bytes.Buffer{})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		err := mgr.DumpDatabase(context.TODO(), "postgres", schema.DatabaseDumpRequest{Format: "invalid"}, &bytes.Buffer{})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		err := mgr.DumpDatabase(context.TODO(), "nonexistent_database", schema.DatabaseDumpRequest{}, &bytes.Buffer{})
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("MissingBinary", func(t *testing.T) {
+		mgr, err := manager.New(context.TODO(), conn, manager.WithPgDump("/nonexistent/pg_dump"))
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		err = mgr.DumpDatabase(context.TODO(), "postgres", schema.DatabaseDumpRequest{}, &bytes.Buffer{})
+		assert.ErrorIs(err, pg.ErrNotAvailable)
+	})
+
+	t.Run("SchemaOnly", func(t *testing.T) {
+		if _, err := exec.LookPath("pg_dump"); err != nil {
+			t.Skip("pg_dump not found")
+		}
+		var buf bytes.Buffer
+		err := mgr.DumpDatabase(context.TODO(), "postgres", schema.DatabaseDumpRequest{SchemaOnly: true}, &buf)
+		assert.NoError(err)
+		assert.Contains(buf.String(), "PostgreSQL database dump")
+	})
+}
@@ -0,0 +1,156 @@
+package manager
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// growth holds the size of each database when the bloat report was last
+// produced, which is used to calculate how much each database has grown
+type growth struct {
+	sync.Mutex
+	samples map[string]sample
+}
+
+type sample struct {
+	size uint64
+	when time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - BLOAT
+
+// ListBloat returns the estimated bloat of tables and btree indexes across all
+// databases, with the most bloat first within each database. If Database is
+// specified in the request, only tables and indexes from that database are
+// returned.
+func (manager *Manager) ListBloat(ctx context.Context, req schema.BloatListRequest) (*schema.BloatList, error) {
+	var list schema.BloatList
+	var offset, limit uint64
+
+	// Set limit lower if request limit is lower
+	limit = schema.BloatListLimit
+	if req.Limit != nil && types.PtrUint64(req.Limit) < limit {
+		limit = types.PtrUint64(req.Limit)
+	}
+
+	// Allocate the body with capacity
+	list.Body = make([]schema.Bloat, 0, limit)
+
+	// Iterate through all the databases
+	if _, err := manager.withDatabases(ctx, func(database *schema.Database) error {
+		// Filter by database
+		if name := strings.TrimSpace(types.PtrString(req.Database)); name != "" && name != database.Name {
+			return nil
+		}
+
+		// Iterate through the estimates
+		count, err := manager.withBloat(ctx, database.Name, req, func(bloat *schema.Bloat) error {
+			if offset >= req.Offset && uint64(len(list.Body)) < limit {
+				list.Body = append(list.Body, *bloat)
+			}
+			offset++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Increment the count
+		list.Count += count
+
+		// Return success
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return &list, nil
+}
+
+// GetBloatReport returns the estimated bloat of tables and btree indexes, and
+// the size of each database with how much it has grown since the report was
+// previously produced. The sizes are sampled each time the report is
+// produced, so the growth of a database is omitted the first time.
+func (manager *Manager) GetBloatReport(ctx context.Context, req schema.BloatListRequest) (*schema.BloatReport, error) {
+	var report schema.BloatReport
+
+	// Sample the size of the databases
+	now := time.Now()
+	if _, err := manager.withDatabases(ctx, func(database *schema.Database) error {
+		if name := strings.TrimSpace(types.PtrString(req.Database)); name != "" && name != database.Name {
+			return nil
+		}
+		report.Databases = append(report.Databases, schema.DatabaseGrowth{
+			Name: database.Name,
+			Size: database.Size,
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Calculate the growth since the previous samples, and replace them
+	manager.growth.Lock()
+	if manager.growth.samples == nil {
+		manager.growth.samples = make(map[string]sample, len(report.Databases))
+	}
+	for i, database := range report.Databases {
+		if previous, exists := manager.growth.samples[database.Name]; exists {
+			report.Databases[i].Growth = types.Int64Ptr(int64(database.Size) - int64(previous.size))
+			report.Databases[i].Previous = types.TimePtr(previous.when)
+		}
+		manager.growth.samples[database.Name] = sample{size: database.Size, when: now}
+	}
+	manager.growth.Unlock()
+
+	// Estimate the bloat
+	list, err := manager.ListBloat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	report.BloatList = *list
+
+	// Return success
+	return &report, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Iterate through the bloat estimates for a database
+func (manager *Manager) withBloat(ctx context.Context, database string, req schema.BloatListRequest, fn func(bloat *schema.Bloat) error) (uint64, error) {
+	req.Offset = 0
+	req.Limit = types.Uint64Ptr(schema.BloatListLimit)
+
+	for {
+		var list schema.BloatList
+		if err := manager.conn.Remote(database).With("as", schema.BloatDef).List(ctx, &list, &req); err != nil {
+			return 0, err
+		}
+
+		for _, bloat := range list.Body {
+			if err := fn(&bloat); err != nil {
+				return 0, err
+			}
+		}
+
+		// Determine if the next page is over the count
+		next := req.Offset + types.PtrUint64(req.Limit)
+		if next >= list.Count {
+			return list.Count, nil
+		} else {
+			req.Offset = next
+		}
+	}
+}
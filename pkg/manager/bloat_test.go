@@ -0,0 +1,58 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// BLOAT TESTS
+
+func Test_Manager_GetBloatReport(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("ListAll", func(t *testing.T) {
+		list, err := mgr.ListBloat(context.TODO(), schema.BloatListRequest{})
+		if assert.NoError(err) {
+			assert.LessOrEqual(len(list.Body), int(list.Count))
+			for _, bloat := range list.Body {
+				assert.LessOrEqual(bloat.Bloat, bloat.Size)
+			}
+		}
+	})
+
+	t.Run("Growth", func(t *testing.T) {
+		dbName := "postgres"
+		req := schema.BloatListRequest{Database: &dbName}
+
+		// The first report has no previous sample
+		report, err := mgr.GetBloatReport(context.TODO(), req)
+		if !assert.NoError(err) || !assert.Len(report.Databases, 1) {
+			t.FailNow()
+		}
+		assert.Equal(dbName, report.Databases[0].Name)
+		assert.Nil(report.Databases[0].Growth)
+		for _, bloat := range report.Body {
+			assert.Equal(dbName, bloat.Database)
+		}
+
+		// The second report has the growth since the first
+		report, err = mgr.GetBloatReport(context.TODO(), req)
+		if assert.NoError(err) && assert.Len(report.Databases, 1) {
+			assert.NotNil(report.Databases[0].Growth)
+			assert.NotNil(report.Databases[0].Previous)
+		}
+	})
+}
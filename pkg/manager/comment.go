@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"context"
+	"strings"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// SetComment sets the comment on a database, role, schema or an object in a
+// schema with COMMENT ON, or removes the comment when it is empty. The comment
+// is returned with the object by obj_description or shobj_description.
+// Returns ErrNotFound if the object does not exist.
+func (manager *Manager) SetComment(ctx context.Context, target schema.CommentTarget) (err error) {
+	if err := target.Validate(); err != nil {
+		return err
+	}
+	defer manager.audit(ctx, schema.AuditUpdate, "comment", target.Target(), target, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return err
+	}
+
+	// Check the object exists, and set the comment
+	database, name := strings.TrimSpace(target.Database), strings.TrimSpace(target.Name)
+	switch target.Type {
+	case schema.CommentDatabase:
+		if _, err := manager.GetDatabase(ctx, name); err != nil {
+			return err
+		}
+		return target.Set(ctx, manager.conn, "")
+	case schema.CommentRole:
+		if _, err := manager.GetRole(ctx, name); err != nil {
+			return err
+		}
+		return target.Set(ctx, manager.conn, "")
+	case schema.CommentSchema:
+		if _, err := manager.GetSchema(ctx, database, name); err != nil {
+			return err
+		}
+		return target.Set(ctx, manager.conn.Remote(database), "")
+	default:
+		object, err := manager.GetObject(ctx, database, strings.TrimSpace(target.Schema), name)
+		if err != nil {
+			return err
+		}
+		return target.Set(ctx, manager.conn.Remote(database), object.Type)
+	}
+}
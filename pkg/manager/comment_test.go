@@ -0,0 +1,103 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// COMMENT TESTS
+
+func Test_Manager_Comment(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Create a database with a comment
+	name := "test_comment_db"
+	t.Cleanup(func() {
+		mgr.DeleteDatabase(context.TODO(), name, true)
+	})
+	database, err := mgr.CreateDatabase(context.TODO(), schema.DatabaseMeta{Name: name, Comment: types.StringPtr("Test database")})
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.Equal("Test database", types.PtrString(database.Comment))
+
+	t.Run("UpdateDatabase", func(t *testing.T) {
+		database, err := mgr.UpdateDatabase(context.TODO(), name, schema.DatabaseMeta{Comment: types.StringPtr("Updated")})
+		if assert.NoError(err) {
+			assert.Equal("Updated", types.PtrString(database.Comment))
+		}
+
+		// The comment is unchanged when it is not set
+		database, err = mgr.UpdateDatabase(context.TODO(), name, schema.DatabaseMeta{})
+		if assert.NoError(err) {
+			assert.Equal("Updated", types.PtrString(database.Comment))
+		}
+
+		// The comment is removed when it is empty
+		database, err = mgr.UpdateDatabase(context.TODO(), name, schema.DatabaseMeta{Comment: types.StringPtr("")})
+		if assert.NoError(err) {
+			assert.Nil(database.Comment)
+		}
+	})
+
+	t.Run("Role", func(t *testing.T) {
+		role := "test_comment_role"
+		t.Cleanup(func() {
+			mgr.DeleteRole(context.TODO(), role)
+		})
+		created, err := mgr.CreateRole(context.TODO(), schema.RoleMeta{Name: role, Comment: types.StringPtr("Test role")})
+		if assert.NoError(err) {
+			assert.Equal("Test role", types.PtrString(created.Comment))
+		}
+		assert.NoError(mgr.SetComment(context.TODO(), schema.CommentTarget{Type: schema.CommentRole, Name: role}))
+		updated, err := mgr.GetRole(context.TODO(), role)
+		if assert.NoError(err) {
+			assert.Nil(updated.Comment)
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		assert.NoError(mgr.SetComment(context.TODO(), schema.CommentTarget{Type: schema.CommentSchema, Database: name, Name: "public", Comment: "Public schema"}))
+		s, err := mgr.GetSchema(context.TODO(), name, "public")
+		if assert.NoError(err) {
+			assert.Equal("Public schema", types.PtrString(s.Comment))
+		}
+	})
+
+	t.Run("Object", func(t *testing.T) {
+		if !assert.NoError(conn.Remote(name).Exec(context.TODO(), "CREATE TABLE comment_test (id INTEGER PRIMARY KEY)")) {
+			t.FailNow()
+		}
+		for _, object := range []string{"comment_test", "comment_test_pkey"} {
+			assert.NoError(mgr.SetComment(context.TODO(), schema.CommentTarget{Type: schema.CommentObject, Database: name, Schema: "public", Name: object, Comment: "It's " + object}))
+			result, err := mgr.GetObject(context.TODO(), name, "public", object)
+			if assert.NoError(err) {
+				assert.Equal("It's "+object, types.PtrString(result.Comment))
+			}
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		assert.ErrorIs(mgr.SetComment(context.TODO(), schema.CommentTarget{Type: schema.CommentDatabase, Name: "nonexistent_database"}), pg.ErrNotFound)
+		assert.ErrorIs(mgr.SetComment(context.TODO(), schema.CommentTarget{Type: schema.CommentObject, Database: name, Schema: "public", Name: "nonexistent_table"}), pg.ErrNotFound)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert.ErrorIs(mgr.SetComment(context.TODO(), schema.CommentTarget{Type: schema.CommentObject, Name: "comment_test"}), pg.ErrBadParameter)
+	})
+}
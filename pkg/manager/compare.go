@@ -0,0 +1,150 @@
+package manager
+
+import (
+	"context"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// GetProfile returns the settings, the extensions installed in each database,
+// and the roles of the server, which can be compared with another server.
+func (manager *Manager) GetProfile(ctx context.Context) (*schema.Profile, error) {
+	profile := schema.Profile{
+		Settings:   make(map[string]string),
+		Extensions: make(map[string]string),
+		Roles:      make(map[string]string),
+	}
+
+	// Settings
+	var settings schema.SettingListRequest
+	settings.Limit = types.Uint64Ptr(schema.SettingListLimit)
+	for {
+		list, err := manager.ListSettings(ctx, settings)
+		if err != nil {
+			return nil, err
+		}
+		for _, setting := range list.Body {
+			profile.Settings[setting.Name] = types.PtrString(setting.Value)
+		}
+		if settings.Offset += uint64(len(list.Body)); len(list.Body) == 0 || settings.Offset >= list.Count {
+			break
+		}
+	}
+
+	// Roles
+	var roles schema.RoleListRequest
+	roles.Limit = types.Uint64Ptr(schema.RoleListLimit)
+	for {
+		list, err := manager.ListRoles(ctx, roles)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range list.Body {
+			profile.Roles[role.Name] = schema.RoleProfile(role)
+		}
+		if roles.Offset += uint64(len(list.Body)); len(list.Body) == 0 || roles.Offset >= list.Count {
+			break
+		}
+	}
+
+	// Extensions installed in each database
+	if _, err := manager.withDatabases(ctx, func(database *schema.Database) error {
+		req := schema.ExtensionListRequest{
+			Database:  types.StringPtr(database.Name),
+			Installed: types.BoolPtr(true),
+		}
+		req.Limit = types.Uint64Ptr(schema.ExtensionListLimit)
+		for {
+			list, err := manager.ListExtensions(ctx, req)
+			if err != nil {
+				return err
+			}
+			for _, extension := range list.Body {
+				profile.Extensions[database.Name+"/"+extension.Name] = types.PtrString(extension.InstalledVersion)
+			}
+			if req.Offset += uint64(len(list.Body)); len(list.Body) == 0 || req.Offset >= list.Count {
+				return nil
+			}
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return &profile, nil
+}
+
+// CompareProfile returns the settings, extensions and roles which differ
+// between the server and the profile of another server, such as to check a
+// new server matches the server it replaces.
+func (manager *Manager) CompareProfile(ctx context.Context, other schema.Profile) (*schema.Comparison, error) {
+	profile, err := manager.GetProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	comparison := profile.Compare(other)
+	return &comparison, nil
+}
+
+// DiffSchemas returns the tables, columns, constraints and indexes which
+// differ between two databases, and the statements which change the first
+// database to match the other. When a schema is set in the request, only the
+// schema is compared, with the other schema in the other database, which can
+// be the same database.
+func (manager *Manager) DiffSchemas(ctx context.Context, database, other string, req schema.SchemaDiffRequest) (*schema.SchemaDiff, error) {
+	if database == "" {
+		return nil, pg.ErrBadParameter.With("database is empty")
+	} else if other == "" {
+		return nil, pg.ErrBadParameter.With("other database is empty")
+	}
+
+	// The other schema is the same schema unless set
+	if req.OtherSchema == "" {
+		req.OtherSchema = req.Schema
+	} else if req.Schema == "" {
+		return nil, pg.ErrBadParameter.With("schema is required with the other schema")
+	}
+	if database == other && req.Schema == req.OtherSchema {
+		return nil, pg.ErrBadParameter.Withf("cannot compare %q with itself", database)
+	}
+
+	// Read the objects in each database
+	objects, err := manager.schemaObjects(ctx, database, req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	others, err := manager.schemaObjects(ctx, other, req.OtherSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return the differences
+	diff := objects.Diff(*others, req.Schema, req.OtherSchema)
+	return &diff, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the tables, columns, constraints and indexes of a database, or of a
+// schema when set, which must exist
+func (manager *Manager) schemaObjects(ctx context.Context, database, namespace string) (*schema.SchemaObjectList, error) {
+	if _, err := manager.GetDatabase(ctx, database); err != nil {
+		return nil, err
+	} else if namespace != "" {
+		if _, err := manager.GetSchema(ctx, database, namespace); err != nil {
+			return nil, err
+		}
+	}
+	var list schema.SchemaObjectList
+	if err := manager.conn.Remote(database).With("as", schema.SchemaObjectDef).List(ctx, &list, schema.SchemaObjectListRequest{Schema: namespace}); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
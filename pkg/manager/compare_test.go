@@ -0,0 +1,134 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// COMPARE TESTS
+
+func Test_Manager_GetProfile(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	profile, err := mgr.GetProfile(context.TODO())
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.Contains(profile.Settings, "work_mem")
+	assert.NotEmpty(profile.Roles)
+	assert.NotEmpty(profile.Extensions)
+}
+
+func Test_Manager_CompareProfile(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// The server is the same as itself
+	profile, err := mgr.GetProfile(context.TODO())
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	comparison, err := mgr.CompareProfile(context.TODO(), *profile)
+	assert.NoError(err)
+	assert.Zero(comparison.Count)
+
+	// A setting which differs is reported
+	profile.Settings["work_mem"] = "1"
+	comparison, err = mgr.CompareProfile(context.TODO(), *profile)
+	assert.NoError(err)
+	if assert.Equal(uint64(1), comparison.Count) {
+		assert.Equal(schema.DifferenceSetting, comparison.Body[0].Type)
+		assert.Equal("work_mem", comparison.Body[0].Name)
+	}
+}
+
+func Test_Manager_DiffSchemas(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Create two databases, with a table in the second
+	for _, name := range []string{"test_diff_a", "test_diff_b"} {
+		t.Cleanup(func() {
+			mgr.DeleteDatabase(context.TODO(), name, true)
+		})
+		if _, err := mgr.CreateDatabase(context.TODO(), schema.DatabaseMeta{Name: name}); !assert.NoError(err) {
+			t.FailNow()
+		}
+	}
+	if !assert.NoError(conn.Remote("test_diff_b").Exec(context.TODO(), "CREATE TABLE diff_test (id INTEGER PRIMARY KEY, name TEXT NOT NULL)")) {
+		t.FailNow()
+	}
+
+	t.Run("EmptyDatabase", func(t *testing.T) {
+		_, err := mgr.DiffSchemas(context.TODO(), "", "test_diff_b", schema.SchemaDiffRequest{})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("Itself", func(t *testing.T) {
+		_, err := mgr.DiffSchemas(context.TODO(), "test_diff_a", "test_diff_a", schema.SchemaDiffRequest{})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("OtherSchemaOnly", func(t *testing.T) {
+		_, err := mgr.DiffSchemas(context.TODO(), "test_diff_a", "test_diff_b", schema.SchemaDiffRequest{OtherSchema: "public"})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := mgr.DiffSchemas(context.TODO(), "test_diff_a", "nonexistent_database", schema.SchemaDiffRequest{})
+		assert.ErrorIs(err, pg.ErrNotFound)
+		_, err = mgr.DiffSchemas(context.TODO(), "test_diff_a", "test_diff_b", schema.SchemaDiffRequest{Schema: "nonexistent_schema"})
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("Migrate", func(t *testing.T) {
+		diff, err := mgr.DiffSchemas(context.TODO(), "test_diff_a", "test_diff_b", schema.SchemaDiffRequest{Schema: "public"})
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		if assert.Equal(uint64(1), diff.Count) {
+			assert.Equal(schema.DifferenceTable, diff.Body[0].Type)
+			assert.Equal("public.diff_test", diff.Body[0].Name)
+		}
+		if !assert.Len(diff.Statements, 2) {
+			t.FailNow()
+		}
+		assert.Equal(`CREATE TABLE "public"."diff_test" ("id" integer NOT NULL, "name" text NOT NULL)`, diff.Statements[0])
+
+		// Applying the statements leaves no differences
+		for _, statement := range diff.Statements {
+			assert.NoError(conn.Remote("test_diff_a").Exec(context.TODO(), statement))
+		}
+		diff, err = mgr.DiffSchemas(context.TODO(), "test_diff_a", "test_diff_b", schema.SchemaDiffRequest{})
+		if assert.NoError(err) {
+			assert.Zero(diff.Count)
+			assert.Empty(diff.Statements)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ListConfigFiles returns the settings in the configuration files, in the
+// order they are read, optionally filtered by whether they have an error.
+// The files are read when the method is called, so the settings include
+// changes which have not yet been loaded.
+func (manager *Manager) ListConfigFiles(ctx context.Context, req schema.ConfigFileSettingListRequest) (*schema.ConfigFileSettingList, error) {
+	var list schema.ConfigFileSettingList
+	if err := manager.conn.List(ctx, &list, req); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ListHBARules returns the rules in pg_hba.conf, in the order they are
+// matched, optionally filtered by whether they have an error. The file is
+// read when the method is called, so rules with an error are reported
+// before the configuration is reloaded.
+func (manager *Manager) ListHBARules(ctx context.Context, req schema.HBARuleListRequest) (*schema.HBARuleList, error) {
+	var list schema.HBARuleList
+	if err := manager.conn.List(ctx, &list, req); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// AddHBARule validates a rule and inserts it into pg_hba.conf before a line,
+// or appends it when the line is zero, then reloads the configuration and
+// returns the rules. Editing requires the file to be set with WithHBAFile.
+func (manager *Manager) AddHBARule(ctx context.Context, meta schema.HBARuleMeta, before uint64) (_ *schema.HBARuleList, err error) {
+	defer manager.audit(ctx, schema.AuditCreate, "hba", meta.Line(), meta, &err)
+
+	// Validate the rule
+	if err := meta.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Insert the rule
+	return manager.editHBA(ctx, func(lines []string) ([]string, error) {
+		if before == 0 {
+			return append(lines, meta.Line()), nil
+		} else if before > uint64(len(lines))+1 {
+			return nil, pg.ErrBadParameter.Withf("line %d is past the end of pg_hba.conf", before)
+		}
+		return slices.Insert(lines, int(before-1), meta.Line()), nil
+	})
+}
+
+// RemoveHBARule removes the rule on a line of pg_hba.conf, then reloads the
+// configuration and returns the rules. Editing requires the file to be set
+// with WithHBAFile.
+func (manager *Manager) RemoveHBARule(ctx context.Context, line uint64) (_ *schema.HBARuleList, err error) {
+	defer manager.audit(ctx, schema.AuditDelete, "hba", strconv.FormatUint(line, 10), nil, &err)
+
+	// Remove the rule
+	return manager.editHBA(ctx, func(lines []string) ([]string, error) {
+		if line == 0 || line > uint64(len(lines)) {
+			return nil, pg.ErrNotFound.Withf("line %d is not in pg_hba.conf", line)
+		} else if rule := strings.TrimSpace(lines[line-1]); rule == "" || strings.HasPrefix(rule, "#") {
+			return nil, pg.ErrNotFound.Withf("line %d of pg_hba.conf is not a rule", line)
+		} else if strings.HasSuffix(rule, "\\") || (line > 1 && strings.HasSuffix(strings.TrimSpace(lines[line-2]), "\\")) {
+			return nil, pg.ErrBadParameter.Withf("line %d of pg_hba.conf is continued over several lines", line)
+		}
+		return slices.Delete(lines, int(line-1), int(line)), nil
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Edit the lines of pg_hba.conf. The file is replaced, then the rules are
+// read by the server, and if there are more rules with errors than before
+// the file is restored. Otherwise the configuration is reloaded. Edits are
+// made one at a time with their own lock, so that the manager is not locked
+// while the database is called.
+func (manager *Manager) editHBA(ctx context.Context, fn func(lines []string) ([]string, error)) (*schema.HBARuleList, error) {
+	path := manager.opt.hba
+	if path == "" {
+		return nil, pg.ErrNotAvailable.With("editing pg_hba.conf is not enabled")
+	}
+
+	// Edit the file
+	if err := manager.editHBAFile(ctx, path, fn); err != nil {
+		return nil, err
+	}
+
+	// Return the rules
+	return manager.ListHBARules(ctx, schema.HBARuleListRequest{})
+}
+
+// Edit the file, check the rules and reload the configuration while holding
+// the lock for edits
+func (manager *Manager) editHBAFile(ctx context.Context, path string, fn func(lines []string) ([]string, error)) error {
+	manager.hba.Lock()
+	defer manager.hba.Unlock()
+
+	// Read the file, and the rules which have errors before the edit
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	before, err := manager.ListHBARules(ctx, schema.HBARuleListRequest{Error: types.BoolPtr(true)})
+	if err != nil {
+		return err
+	}
+
+	// Edit the lines
+	var lines []string
+	if text := strings.TrimSuffix(string(data), "\n"); text != "" {
+		lines = strings.Split(text, "\n")
+	}
+	lines, err = fn(lines)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, []byte(strings.Join(lines, "\n")+"\n"), info); err != nil {
+		return err
+	}
+
+	// Restore the file if the server cannot parse the rules
+	after, err := manager.ListHBARules(ctx, schema.HBARuleListRequest{Error: types.BoolPtr(true)})
+	if err == nil && after.Count > before.Count {
+		err = pg.ErrBadParameter.With(strings.Join(after.Errors(), "; "))
+	}
+	if err != nil {
+		if restoreErr := writeFileAtomic(path, data, info); restoreErr != nil {
+			return restoreErr
+		}
+		return err
+	}
+
+	// Reload the configuration
+	return manager.ReloadConfig(ctx)
+}
+
+// Replace a file by writing a temporary file in the same directory and
+// renaming it, so the server never reads a partly written file. The
+// temporary file has the mode and owner of the file it replaces, so the
+// server can still read it
+func writeFileAtomic(path string, data []byte, info os.FileInfo) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(info.Mode().Perm()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := chown(f, info); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
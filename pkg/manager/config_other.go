@@ -0,0 +1,15 @@
+//go:build !unix
+
+package manager
+
+import (
+	"os"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Files are not owned by a user and group on this platform
+func chown(f *os.File, info os.FileInfo) error {
+	return nil
+}
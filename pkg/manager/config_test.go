@@ -0,0 +1,110 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONFIG FILE TESTS
+
+func Test_Manager_ConfigFiles(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("ListConfigFiles", func(t *testing.T) {
+		list, err := mgr.ListConfigFiles(context.TODO(), schema.ConfigFileSettingListRequest{})
+		if assert.NoError(err) {
+			assert.NotZero(list.Count)
+			assert.Empty(list.Errors())
+		}
+	})
+
+	t.Run("ListHBARules", func(t *testing.T) {
+		list, err := mgr.ListHBARules(context.TODO(), schema.HBARuleListRequest{})
+		if assert.NoError(err) && assert.NotEmpty(list.Body) {
+			assert.NotZero(list.Body[0].Line)
+			assert.Empty(list.Errors())
+		}
+	})
+
+	t.Run("ListHBARulesWithError", func(t *testing.T) {
+		list, err := mgr.ListHBARules(context.TODO(), schema.HBARuleListRequest{Error: types.BoolPtr(true)})
+		if assert.NoError(err) {
+			assert.Zero(list.Count)
+		}
+	})
+}
+
+func Test_Manager_HBARules(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("AddHBARuleNotEnabled", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "host", Database: []string{"all"}, User: []string{"all"}, Address: "10.0.0.0/8", Method: "reject"}
+		_, err := mgr.AddHBARule(context.TODO(), rule, 0)
+		assert.ErrorIs(err, pg.ErrNotAvailable)
+	})
+
+	t.Run("RemoveHBARuleNotEnabled", func(t *testing.T) {
+		_, err := mgr.RemoveHBARule(context.TODO(), 1)
+		assert.ErrorIs(err, pg.ErrNotAvailable)
+	})
+
+	t.Run("EditKeepsMode", func(t *testing.T) {
+		// The file is not read by the server, so the rules are unchanged
+		path := filepath.Join(t.TempDir(), "pg_hba.conf")
+		if !assert.NoError(os.WriteFile(path, []byte("local all all trust\n"), 0640)) {
+			t.FailNow()
+		}
+		mgr, err := manager.New(context.TODO(), conn, manager.WithHBAFile(path))
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+
+		rule := schema.HBARuleMeta{Type: "host", Database: []string{"all"}, User: []string{"all"}, Address: "10.0.0.0/8", Method: "reject"}
+		_, err = mgr.AddHBARule(context.TODO(), rule, 0)
+		if assert.NoError(err) {
+			data, err := os.ReadFile(path)
+			assert.NoError(err)
+			assert.Equal("local all all trust\n"+rule.Line()+"\n", string(data))
+			info, err := os.Stat(path)
+			if assert.NoError(err) {
+				assert.Equal(os.FileMode(0640), info.Mode().Perm())
+			}
+		}
+
+		_, err = mgr.RemoveHBARule(context.TODO(), 2)
+		if assert.NoError(err) {
+			data, err := os.ReadFile(path)
+			assert.NoError(err)
+			assert.Equal("local all all trust\n", string(data))
+		}
+	})
+
+	t.Run("WithHBAFileRelative", func(t *testing.T) {
+		_, err := manager.New(context.TODO(), conn, manager.WithHBAFile("pg_hba.conf"))
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
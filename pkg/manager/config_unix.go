@@ -0,0 +1,27 @@
+//go:build unix
+
+package manager
+
+import (
+	"os"
+	"syscall"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Change the owner of a file to the owner of another file, when it differs
+func chown(f *os.File, info os.FileInfo) error {
+	want, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	got, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if stat, ok := got.Sys().(*syscall.Stat_t); ok && stat.Uid == want.Uid && stat.Gid == want.Gid {
+		return nil
+	}
+	return f.Chown(int(want.Uid), int(want.Gid))
+}
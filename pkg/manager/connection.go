@@ -2,12 +2,21 @@ package manager
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
 )
 
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ConnectionWatchFn is called with each change to the watched connections
+type ConnectionWatchFn func(schema.ConnectionEvent)
+
 ////////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS - CONNECTION
 
@@ -17,9 +26,15 @@ func (manager *Manager) ListConnections(ctx context.Context, req schema.Connecti
 	var list schema.ConnectionList
 	if err := manager.conn.List(ctx, &list, req); err != nil {
 		return nil, err
-	} else {
-		return &list, nil
 	}
+
+	// Set the token for the next page
+	if n := len(list.Body); n > 0 {
+		list.Next = req.Next(req.Offset, uint64(n), list.Count, list.Body[n-1].Pid)
+	}
+
+	// Return success
+	return &list, nil
 }
 
 // GetConnection retrieves a single connection by process ID.
@@ -37,7 +52,9 @@ func (manager *Manager) GetConnection(ctx context.Context, pid uint64) (*schema.
 
 // DeleteConnection terminates a connection by process ID and returns the terminated connection.
 // Returns an error if the pid is zero or the connection is not found.
-func (manager *Manager) DeleteConnection(ctx context.Context, pid uint64) (*schema.Connection, error) {
+func (manager *Manager) DeleteConnection(ctx context.Context, pid uint64) (_ *schema.Connection, err error) {
+	defer manager.audit(ctx, schema.AuditDelete, "connection", strconv.FormatUint(pid, 10), nil, &err)
+
 	if pid == 0 {
 		return nil, pg.ErrBadParameter.With("pid is zero")
 	}
@@ -47,3 +64,81 @@ func (manager *Manager) DeleteConnection(ctx context.Context, pid uint64) (*sche
 	}
 	return &connection, nil
 }
+
+// ListLongRunningQueries returns the connections which have been running a
+// query for longer than the threshold, longest first. The count is the
+// number of such connections, and up to ConnectionListLimit are returned.
+func (manager *Manager) ListLongRunningQueries(ctx context.Context, threshold time.Duration) (*schema.LongRunningQueryList, error) {
+	var list schema.LongRunningQueryList
+	if err := manager.conn.List(ctx, &list, schema.LongRunning{Threshold: threshold}); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ListIdleInTransaction returns the connections which have been idle in a
+// transaction for longer than the threshold, longest first. These hold locks
+// and prevent vacuum from removing dead tuples. The count is the number of
+// such connections, and up to ConnectionListLimit are returned.
+func (manager *Manager) ListIdleInTransaction(ctx context.Context, threshold time.Duration) (*schema.LongRunningQueryList, error) {
+	var list schema.LongRunningQueryList
+	if err := manager.conn.List(ctx, &list, schema.LongRunning{Threshold: threshold, IdleInTransaction: true}); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// WatchConnections polls the connections at the interval in the request until
+// the context is cancelled, and calls the function with each connection which
+// is opened, starts a query, changes state or is closed. The connections which
+// exist when watching starts are reported as new. Returns nil when the context
+// is cancelled, or the error if the connections cannot be polled.
+func (manager *Manager) WatchConnections(ctx context.Context, req schema.ConnectionWatchRequest, fn ConnectionWatchFn) error {
+	interval, err := req.Duration()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var previous []schema.Connection
+	for {
+		current, err := manager.allConnections(ctx, schema.ConnectionListRequest{Database: req.Database, Role: req.Role})
+		if ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		for _, event := range schema.ConnectionChanges(previous, current) {
+			fn(event)
+		}
+		previous = current
+
+		// Wait for the next poll
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS - CONNECTION
+
+// Return every connection which matches the request, a page at a time
+func (manager *Manager) allConnections(ctx context.Context, req schema.ConnectionListRequest) ([]schema.Connection, error) {
+	var result []schema.Connection
+	req.Limit = types.Uint64Ptr(schema.ConnectionListLimit)
+	for {
+		list, err := manager.ListConnections(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, list.Body...)
+		if list.Next == "" {
+			return result, nil
+		}
+		req.After = list.Next
+	}
+}
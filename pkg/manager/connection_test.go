@@ -3,11 +3,13 @@ package manager_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
 	manager "github.com/mutablelogic/go-pg/pkg/manager"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
 	assert "github.com/stretchr/testify/assert"
 )
 
@@ -151,6 +153,81 @@ func Test_Manager_GetConnection(t *testing.T) {
 	})
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// WATCH CONNECTION TESTS
+
+func Test_Manager_WatchConnections(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("ReportsExisting", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+		defer cancel()
+
+		// The existing connections are reported as new on the first poll
+		var events []schema.ConnectionEvent
+		err := mgr.WatchConnections(ctx, schema.ConnectionWatchRequest{Interval: types.StringPtr("100ms")}, func(event schema.ConnectionEvent) {
+			events = append(events, event)
+		})
+		assert.NoError(err)
+		if assert.NotEmpty(events) {
+			assert.Equal(schema.ConnectionEventNew, events[0].Type)
+		}
+	})
+
+	t.Run("InvalidInterval", func(t *testing.T) {
+		err := mgr.WatchConnections(context.TODO(), schema.ConnectionWatchRequest{Interval: types.StringPtr("1ms")}, func(schema.ConnectionEvent) {})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LONG RUNNING TESTS
+
+func Test_Manager_ListLongRunningQueries(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Active", func(t *testing.T) {
+		list, err := mgr.ListLongRunningQueries(context.TODO(), 0)
+		if assert.NoError(err) {
+			assert.LessOrEqual(len(list.Body), int(list.Count))
+			for i, query := range list.Body {
+				assert.Equal("active", query.State)
+				if i > 0 {
+					assert.False(query.Since.Before(list.Body[i-1].Since))
+				}
+			}
+		}
+	})
+
+	t.Run("IdleInTransaction", func(t *testing.T) {
+		list, err := mgr.ListIdleInTransaction(context.TODO(), time.Hour)
+		if assert.NoError(err) {
+			for _, query := range list.Body {
+				assert.GreaterOrEqual(query.Duration, float64(time.Hour.Milliseconds()))
+			}
+		}
+	})
+
+	t.Run("NegativeThreshold", func(t *testing.T) {
+		_, err := mgr.ListLongRunningQueries(context.TODO(), -time.Second)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // DELETE CONNECTION TESTS
 
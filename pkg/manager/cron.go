@@ -0,0 +1,136 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - CRON
+
+// ListCronJobs returns a list of jobs scheduled with pg_cron, filtered by
+// database and username. Returns ErrNotAvailable if pg_cron is not installed.
+func (manager *Manager) ListCronJobs(ctx context.Context, req schema.CronJobListRequest) (*schema.CronJobList, error) {
+	if err := manager.cronAvailable(ctx); err != nil {
+		return nil, err
+	}
+
+	// List the jobs
+	var list schema.CronJobList
+	if err := manager.conn.List(ctx, &list, req); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return &list, nil
+}
+
+// GetCronJob returns a job scheduled with pg_cron by identifier. Returns
+// ErrNotAvailable if pg_cron is not installed.
+func (manager *Manager) GetCronJob(ctx context.Context, id uint64) (*schema.CronJob, error) {
+	if id == 0 {
+		return nil, pg.ErrBadParameter.With("id is zero")
+	} else if err := manager.cronAvailable(ctx); err != nil {
+		return nil, err
+	}
+
+	// Get the job
+	var job schema.CronJob
+	if err := manager.conn.Get(ctx, &job, schema.CronJobId(id)); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return &job, nil
+}
+
+// CreateCronJob schedules a job with pg_cron. If a job with the same name
+// already exists for the user, it is replaced. Returns ErrNotAvailable if
+// pg_cron is not installed.
+func (manager *Manager) CreateCronJob(ctx context.Context, meta schema.CronJobMeta) (_ *schema.CronJob, err error) {
+	defer manager.audit(ctx, schema.AuditCreate, "cron", meta.Name, meta, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := manager.cronAvailable(ctx); err != nil {
+		return nil, err
+	}
+
+	// Schedule the job, and then get it
+	var id schema.CronJobId
+	if err := manager.conn.Insert(ctx, &id, meta); err != nil {
+		return nil, err
+	}
+
+	// Return the job
+	return manager.GetCronJob(ctx, uint64(id))
+}
+
+// DeleteCronJob unschedules a job with pg_cron by identifier, and returns the
+// deleted job. Returns ErrNotAvailable if pg_cron is not installed.
+func (manager *Manager) DeleteCronJob(ctx context.Context, id uint64) (_ *schema.CronJob, err error) {
+	defer manager.audit(ctx, schema.AuditDelete, "cron", strconv.FormatUint(id, 10), nil, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
+	if id == 0 {
+		return nil, pg.ErrBadParameter.With("id is zero")
+	} else if err := manager.cronAvailable(ctx); err != nil {
+		return nil, err
+	}
+
+	// Unschedule the job
+	var job schema.CronJob
+	if err := manager.conn.Delete(ctx, &job, schema.CronJobId(id)); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return &job, nil
+}
+
+// ListCronJobRuns returns the run history of jobs scheduled with pg_cron,
+// most recent first, filtered by job and status. Returns ErrNotAvailable if
+// pg_cron is not installed.
+func (manager *Manager) ListCronJobRuns(ctx context.Context, req schema.CronJobRunListRequest) (*schema.CronJobRunList, error) {
+	if err := manager.cronAvailable(ctx); err != nil {
+		return nil, err
+	}
+
+	// List the runs
+	var list schema.CronJobRunList
+	if err := manager.conn.List(ctx, &list, req); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return &list, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// cronAvailable returns ErrNotAvailable if pg_cron is not installed in the
+// database of the connection
+func (manager *Manager) cronAvailable(ctx context.Context) error {
+	extension, err := manager.GetExtension(ctx, schema.CronExtension)
+	if errors.Is(err, pg.ErrNotFound) {
+		return pg.ErrNotAvailable.With(schema.CronExtension)
+	} else if err != nil {
+		return err
+	} else if extension.InstalledVersion == nil {
+		return pg.ErrNotAvailable.With(schema.CronExtension)
+	}
+	return nil
+}
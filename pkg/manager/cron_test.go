@@ -0,0 +1,48 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CRON TESTS
+
+func Test_Manager_Cron(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("GetZeroId", func(t *testing.T) {
+		_, err := mgr.GetCronJob(context.TODO(), 0)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("DeleteZeroId", func(t *testing.T) {
+		_, err := mgr.DeleteCronJob(context.TODO(), 0)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	// The test container does not preload pg_cron
+	t.Run("NotAvailable", func(t *testing.T) {
+		_, err := mgr.ListCronJobs(context.TODO(), schema.CronJobListRequest{})
+		assert.ErrorIs(err, pg.ErrNotAvailable)
+
+		_, err = mgr.CreateCronJob(context.TODO(), schema.CronJobMeta{Name: "test", Schedule: "* * * * *", Command: "SELECT 1"})
+		assert.ErrorIs(err, pg.ErrNotAvailable)
+
+		_, err = mgr.ListCronJobRuns(context.TODO(), schema.CronJobRunListRequest{})
+		assert.ErrorIs(err, pg.ErrNotAvailable)
+	})
+}
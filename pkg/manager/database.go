@@ -3,11 +3,11 @@ package manager
 import (
 	"context"
 	"errors"
-	"slices"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -41,45 +41,80 @@ func (manager *Manager) GetDatabase(ctx context.Context, name string) (*schema.D
 // The database creation cannot be done in a transaction, but ACL grants are
 // applied within a transaction. If ACL grants fail, the database is deleted
 // to maintain consistency.
-func (manager *Manager) CreateDatabase(ctx context.Context, meta schema.DatabaseMeta) (*schema.Database, error) {
-	var database schema.Database
+func (manager *Manager) CreateDatabase(ctx context.Context, meta schema.DatabaseMeta) (_ *schema.Database, err error) {
+	defer manager.audit(ctx, schema.AuditCreate, "database", meta.Name, meta, &err)
 
-	// Validate metadata
-	if err := meta.Validate(); err != nil {
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
 		return nil, err
 	}
 
-	// Create the database - cannot be done in a transaction
-	if err := manager.conn.Insert(ctx, nil, meta); err != nil {
+	// Create the database
+	return manager.createDatabase(ctx, meta)
+}
+
+// CloneDatabase creates a new database which is a copy of the source
+// database, with CREATE DATABASE ... TEMPLATE. The source cannot have other
+// connections while it is copied, so when force is set the connections to it
+// are terminated first. The new database has the access privileges of the
+// source and its comment, and is owned by the owner of the source unless
+// another owner is set.
+func (manager *Manager) CloneDatabase(ctx context.Context, source, target string, req schema.DatabaseCloneRequest) (_ *schema.Database, err error) {
+	defer manager.audit(ctx, schema.AuditCreate, "database", target, schema.DatabaseMeta{Name: target, Owner: req.Owner, Template: source}, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
 		return nil, err
 	}
 
-	// Set ACL's - this can be done in a transaction
-	if err := manager.conn.Tx(ctx, func(conn pg.Conn) error {
-		for _, acl := range meta.Acl {
-			if err := acl.GrantDatabase(ctx, conn, meta.Name); err != nil {
-				return err
-			}
-		}
-		return nil
-	}); err != nil {
-		// Delete the database if there is an issue with ACL's
-		deleteErr := manager.conn.Delete(ctx, nil, schema.DatabaseName(meta.Name))
-		return nil, errors.Join(err, deleteErr)
+	if source == "" {
+		return nil, pg.ErrBadParameter.With("source is empty")
+	} else if source == target {
+		return nil, pg.ErrBadParameter.Withf("cannot clone database %q to itself", source)
 	}
 
-	// Get the database
-	if err := manager.conn.Get(ctx, &database, schema.DatabaseName(meta.Name)); err != nil {
+	// Get the source database
+	var database schema.Database
+	if err := manager.conn.Get(ctx, &database, schema.DatabaseName(source)); err != nil {
 		return nil, err
 	}
+	meta := schema.DatabaseMeta{Name: target, Owner: database.Owner, Acl: database.Acl, Comment: database.Comment, Template: source}
+	if req.Owner != "" {
+		meta.Owner = req.Owner
+	}
 
-	// Return success
-	return &database, nil
+	// Validate metadata before terminating any connections
+	if err := meta.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Terminate the connections to the source database
+	if req.Force {
+		connections, err := manager.allConnections(ctx, schema.ConnectionListRequest{Database: &source})
+		if err != nil {
+			return nil, err
+		}
+		for _, connection := range connections {
+			if err := manager.conn.Delete(ctx, nil, schema.ConnectionPid(connection.Pid)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Create the database
+	return manager.createDatabase(ctx, meta)
 }
 
 // DeleteDatabase drops a database by name and returns its metadata before deletion.
 // If force is true, the database is dropped even if there are active connections.
-func (manager *Manager) DeleteDatabase(ctx context.Context, name string, force bool) (*schema.Database, error) {
+func (manager *Manager) DeleteDatabase(ctx context.Context, name string, force bool) (_ *schema.Database, err error) {
+	defer manager.audit(ctx, schema.AuditDelete, "database", name, nil, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	if name == "" {
 		return nil, pg.ErrBadParameter.With("name is empty")
 	}
@@ -92,11 +127,18 @@ func (manager *Manager) DeleteDatabase(ctx context.Context, name string, force b
 	return &database, nil
 }
 
-// UpdateDatabase modifies an existing database's metadata including name, owner, and ACLs.
+// UpdateDatabase modifies an existing database's metadata including name, owner, ACLs and comment.
 // All changes are applied within a transaction to ensure atomicity.
 // If meta.Name is provided and differs from name, the database is renamed.
 // ACL changes are synchronized by revoking removed privileges and granting new ones.
-func (manager *Manager) UpdateDatabase(ctx context.Context, name string, meta schema.DatabaseMeta) (*schema.Database, error) {
+func (manager *Manager) UpdateDatabase(ctx context.Context, name string, meta schema.DatabaseMeta) (_ *schema.Database, err error) {
+	defer manager.audit(ctx, schema.AuditUpdate, "database", name, meta, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	if name == "" {
 		return nil, pg.ErrBadParameter.With("name is empty")
 	}
@@ -142,6 +184,13 @@ func (manager *Manager) UpdateDatabase(ctx context.Context, name string, meta sc
 			}
 		}
 
+		// Update the comment
+		if meta.Comment != nil {
+			if err := (schema.CommentTarget{Type: schema.CommentDatabase, Name: meta.Name, Comment: *meta.Comment}).Set(ctx, conn, ""); err != nil {
+				return err
+			}
+		}
+
 		// Return success
 		return nil
 	}); err != nil {
@@ -160,63 +209,62 @@ func (manager *Manager) UpdateDatabase(ctx context.Context, name string, meta sc
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
-// updateDatabaseACLs synchronizes ACLs between the current and desired state.
-// It performs the following operations:
-//   - Revokes all privileges for roles that are no longer in the desired list
-//   - For existing roles, revokes privileges that were removed and grants new ones
-//   - Grants all privileges for new roles
+// updateDatabaseACLs synchronizes ACLs between the current and desired state,
+// by revoking removed privileges and granting new ones.
 func (manager *Manager) updateDatabaseACLs(ctx context.Context, conn pg.Conn, dbName string, current, desired schema.ACLList) error {
-	// Process existing ACLs
-	for _, acl := range current {
-		role := desired.Find(acl.Role)
-		if role == nil {
-			// Revoke all privileges for this role
-			if err := acl.RevokeDatabase(ctx, conn, dbName); err != nil {
+	for _, change := range current.Diff(desired) {
+		if change.Grant {
+			if err := change.GrantDatabase(ctx, conn, dbName); err != nil {
 				return err
 			}
-			continue
+		} else if err := change.RevokeDatabase(ctx, conn, dbName); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		// Check if privileges are the same
-		if slices.Equal(acl.Priv, role.Priv) {
-			continue
-		}
+// createDatabase creates a database, grants the access privileges and sets
+// the comment. The database creation cannot be done in a transaction, but ACL
+// grants and the comment are applied within a transaction. If they fail, the
+// database is deleted to maintain consistency.
+func (manager *Manager) createDatabase(ctx context.Context, meta schema.DatabaseMeta) (*schema.Database, error) {
+	var database schema.Database
 
-		// If new role has ALL, just grant it
-		if role.IsAll() {
-			if err := role.GrantDatabase(ctx, conn, dbName); err != nil {
-				return err
-			}
-			continue
-		}
+	// Validate metadata
+	if err := meta.Validate(); err != nil {
+		return nil, err
+	}
 
-		// Revoke privileges that are no longer needed
-		for _, priv := range acl.Priv {
-			if !slices.Contains(role.Priv, priv) {
-				if err := acl.WithPriv(priv).RevokeDatabase(ctx, conn, dbName); err != nil {
-					return err
-				}
-			}
-		}
+	// Create the database - cannot be done in a transaction
+	if err := manager.conn.Insert(ctx, nil, meta); err != nil {
+		return nil, err
+	}
 
-		// Grant new privileges
-		for _, priv := range role.Priv {
-			if !slices.Contains(acl.Priv, priv) {
-				if err := acl.WithPriv(priv).GrantDatabase(ctx, conn, dbName); err != nil {
-					return err
-				}
+	// Set ACL's - this can be done in a transaction
+	if err := manager.conn.Tx(ctx, func(conn pg.Conn) error {
+		for _, acl := range meta.Acl {
+			if err := acl.GrantDatabase(ctx, conn, meta.Name); err != nil {
+				return err
 			}
 		}
-	}
-
-	// Grant privileges for new roles
-	for _, acl := range desired {
-		if current.Find(acl.Role) == nil {
-			if err := acl.GrantDatabase(ctx, conn, dbName); err != nil {
+		if comment := types.PtrString(meta.Comment); comment != "" {
+			if err := (schema.CommentTarget{Type: schema.CommentDatabase, Name: meta.Name, Comment: comment}).Set(ctx, conn, ""); err != nil {
 				return err
 			}
 		}
+		return nil
+	}); err != nil {
+		// Delete the database if there is an issue with ACL's
+		deleteErr := manager.conn.Delete(ctx, nil, schema.DatabaseName(meta.Name))
+		return nil, errors.Join(err, deleteErr)
 	}
 
-	return nil
+	// Get the database
+	if err := manager.conn.Get(ctx, &database, schema.DatabaseName(meta.Name)); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return &database, nil
 }
@@ -15,9 +15,10 @@ import (
 // Global connection variable
 var conn test.Conn
 
-// Start up a container and test the pool
+// Start up a container for each version in PG_TEST_VERSIONS, or the default
+// version when it is not set, and run the tests
 func TestMain(m *testing.M) {
-	test.Main(m, &conn)
+	test.MainMatrix(m, &conn)
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -190,6 +191,72 @@ func Test_Manager_CreateDatabase(t *testing.T) {
 	})
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// CLONE DATABASE TESTS
+
+func Test_Manager_CloneDatabase(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Create a source database with a privilege
+	source := "test_clone_source"
+	t.Cleanup(func() {
+		mgr.DeleteDatabase(context.TODO(), source, true)
+	})
+	if _, err := mgr.CreateDatabase(context.TODO(), schema.DatabaseMeta{
+		Name: source,
+		Acl:  schema.ACLList{{Role: "PUBLIC", Priv: []string{"CONNECT"}}},
+	}); !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Clone", func(t *testing.T) {
+		target := "test_clone_target"
+		t.Cleanup(func() {
+			mgr.DeleteDatabase(context.TODO(), target, true)
+		})
+
+		database, err := mgr.CloneDatabase(context.TODO(), source, target, schema.DatabaseCloneRequest{})
+		if assert.NoError(err) {
+			assert.Equal(target, database.Name)
+			assert.NotNil(database.Acl.Find("PUBLIC"))
+		}
+	})
+
+	t.Run("CloneWithForce", func(t *testing.T) {
+		target := "test_clone_force"
+		t.Cleanup(func() {
+			mgr.DeleteDatabase(context.TODO(), target, true)
+		})
+
+		database, err := mgr.CloneDatabase(context.TODO(), source, target, schema.DatabaseCloneRequest{Force: true})
+		if assert.NoError(err) {
+			assert.Equal(target, database.Name)
+		}
+	})
+
+	t.Run("CloneToItself", func(t *testing.T) {
+		_, err := mgr.CloneDatabase(context.TODO(), source, source, schema.DatabaseCloneRequest{})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("CloneReservedPrefix", func(t *testing.T) {
+		_, err := mgr.CloneDatabase(context.TODO(), source, "pg_clone", schema.DatabaseCloneRequest{})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("CloneNotFound", func(t *testing.T) {
+		_, err := mgr.CloneDatabase(context.TODO(), "nonexistent_db_xyz", "test_clone_missing", schema.DatabaseCloneRequest{})
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // DELETE DATABASE TESTS
 
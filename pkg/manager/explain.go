@@ -0,0 +1,50 @@
+package manager
+
+import (
+	"context"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ExplainQuery returns the plan for a query in a database, or the database of
+// the connection if the database is empty. The query is explained within a
+// read-only transaction, so that analyzing a query cannot modify data.
+func (manager *Manager) ExplainQuery(ctx context.Context, database, sql string, opts schema.ExplainOptions) (*schema.Explain, error) {
+	req := schema.ExplainRequest{Database: database, Query: sql, ExplainOptions: opts}
+
+	// Explain the query. Remote queries run on a new connection, so the
+	// transaction is started as part of the query and rolled back when the
+	// connection closes.
+	var explain schema.Explain
+	if database != "" {
+		if err := manager.conn.Remote(database).With("as", schema.ExplainDef, "readonly", explainRemoteReadOnly).Get(ctx, &explain, req); err != nil {
+			return nil, err
+		}
+	} else if err := manager.conn.Tx(ctx, func(conn pg.Conn) error {
+		if err := conn.Exec(ctx, explainReadOnly); err != nil {
+			return err
+		}
+		return conn.Get(ctx, &explain, req)
+	}); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	explain.Database = database
+	explain.Query = strings.TrimSpace(sql)
+	return &explain, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE CONSTANTS
+
+const (
+	explainReadOnly       = `SET TRANSACTION READ ONLY`
+	explainRemoteReadOnly = `START TRANSACTION READ ONLY; `
+)
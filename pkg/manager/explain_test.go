@@ -0,0 +1,54 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// EXPLAIN TESTS
+
+func Test_Manager_Explain(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Explain", func(t *testing.T) {
+		explain, err := mgr.ExplainQuery(context.TODO(), "", "SELECT 1", schema.ExplainOptions{})
+		if assert.NoError(err) {
+			assert.Equal("SELECT 1", explain.Query)
+			assert.Equal("Result", explain.Plan.NodeType)
+			assert.Nil(explain.ExecutionTime)
+		}
+	})
+
+	t.Run("Analyze", func(t *testing.T) {
+		explain, err := mgr.ExplainQuery(context.TODO(), "", "SELECT * FROM pg_class", schema.ExplainOptions{Analyze: true, Buffers: true})
+		if assert.NoError(err) {
+			assert.NotEmpty(explain.Plan.NodeType)
+			assert.NotNil(explain.ExecutionTime)
+			assert.NotNil(explain.Plan.ActualLoops)
+		}
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		_, err := mgr.ExplainQuery(context.TODO(), "", "CREATE TABLE explain_test AS SELECT 1", schema.ExplainOptions{Analyze: true})
+		assert.Error(err)
+	})
+
+	t.Run("MultipleStatements", func(t *testing.T) {
+		_, err := mgr.ExplainQuery(context.TODO(), "", "SELECT 1; SELECT 2", schema.ExplainOptions{})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	// Packages
@@ -62,7 +63,14 @@ func (manager *Manager) GetExtension(ctx context.Context, name string) (*schema.
 // CreateExtension installs an extension in a database.
 // The Database field in meta specifies which database to install into.
 // If cascade is true, dependent extensions are also installed.
-func (manager *Manager) CreateExtension(ctx context.Context, meta schema.ExtensionMeta, cascade bool) (*schema.Extension, error) {
+func (manager *Manager) CreateExtension(ctx context.Context, meta schema.ExtensionMeta, cascade bool) (_ *schema.Extension, err error) {
+	defer manager.audit(ctx, schema.AuditCreate, "extension", meta.Database+"/"+meta.Name, meta, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	// Check parameters
 	database := strings.TrimSpace(meta.Database)
 	if database == "" {
@@ -93,10 +101,18 @@ func (manager *Manager) CreateExtension(ctx context.Context, meta schema.Extensi
 
 // UpdateExtension updates an extension's version and/or schema.
 // The Database field in meta specifies which database to update.
-// The Version field specifies the target version (empty means latest).
+// The Version field specifies the target version (empty means latest), which
+// must be reachable from the installed version by one of its update paths.
 // The Schema field specifies a new schema to move the extension to (only for relocatable extensions).
 // Note: Name and Owner cannot be changed for extensions in PostgreSQL.
-func (manager *Manager) UpdateExtension(ctx context.Context, name string, meta schema.ExtensionMeta) (*schema.Extension, error) {
+func (manager *Manager) UpdateExtension(ctx context.Context, name string, meta schema.ExtensionMeta) (_ *schema.Extension, err error) {
+	defer manager.audit(ctx, schema.AuditUpdate, "extension", meta.Database+"/"+name, meta, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	// Check parameters
 	database := strings.TrimSpace(meta.Database)
 	if database == "" {
@@ -117,13 +133,27 @@ func (manager *Manager) UpdateExtension(ctx context.Context, name string, meta s
 
 	conn := manager.conn.Remote(database)
 
+	// Check the extension is installed
+	var current schema.Extension
+	if err := conn.With("as", schema.ExtensionDef).Get(ctx, &current, schema.ExtensionName(name)); err != nil {
+		return nil, err
+	} else if current.InstalledVersion == nil {
+		return nil, pg.ErrNotFound.Withf("extension %q is not installed in %q", name, database)
+	}
+
+	// If a version is requested, check it can be reached from the installed version
+	if version := strings.TrimSpace(meta.Version); version != "" && version != *current.InstalledVersion {
+		if versions := current.UpdateVersions(); !slices.Contains(versions, version) {
+			if len(versions) == 0 {
+				return nil, pg.ErrBadParameter.Withf("extension %q cannot be updated from version %q", name, *current.InstalledVersion)
+			}
+			return nil, pg.ErrBadParameter.Withf("extension %q cannot be updated from version %q to %q, but can be updated to %s", name, *current.InstalledVersion, version, strings.Join(versions, ", "))
+		}
+	}
+
 	// If schema change is requested, first check if the extension is relocatable
 	if meta.Schema != "" {
-		var ext schema.Extension
-		if err := conn.With("as", schema.ExtensionDef).Get(ctx, &ext, schema.ExtensionName(name)); err != nil {
-			return nil, err
-		}
-		if ext.Relocatable == nil || !*ext.Relocatable {
+		if current.Relocatable == nil || !*current.Relocatable {
 			return nil, pg.ErrBadParameter.Withf("extension %q is not relocatable", name)
 		}
 	}
@@ -156,7 +186,14 @@ func (manager *Manager) UpdateExtension(ctx context.Context, name string, meta s
 	return &ext, nil
 }
 
-func (manager *Manager) DeleteExtension(ctx context.Context, database, name string, cascade bool) error {
+func (manager *Manager) DeleteExtension(ctx context.Context, database, name string, cascade bool) (err error) {
+	defer manager.audit(ctx, schema.AuditDelete, "extension", database+"/"+name, nil, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return err
+	}
+
 	// Check parameters
 	database = strings.TrimSpace(database)
 	if database == "" {
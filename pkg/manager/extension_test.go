@@ -156,6 +156,16 @@ func Test_Manager_GetExtension(t *testing.T) {
 		assert.Empty(ext.Database)
 	})
 
+	t.Run("GetExtensionVersions", func(t *testing.T) {
+		// plpgsql is installed at its only available version
+		ext, err := mgr.GetExtension(context.TODO(), "plpgsql")
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		assert.Contains(ext.Versions, ext.DefaultVersion)
+		assert.Empty(ext.UpdateVersions())
+	})
+
 	t.Run("GetNonExistentExtension", func(t *testing.T) {
 		_, err := mgr.GetExtension(context.TODO(), "nonexistent_extension_xyz")
 		assert.Error(err)
@@ -281,6 +291,22 @@ func Test_Manager_UpdateExtension(t *testing.T) {
 		assert.Contains(err.Error(), "owner cannot be changed")
 	})
 
+	t.Run("UnreachableVersion", func(t *testing.T) {
+		_, err := mgr.UpdateExtension(context.TODO(), "plpgsql", schema.ExtensionMeta{
+			Database: "postgres",
+			Version:  "99.0",
+		})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+		assert.Contains(err.Error(), "cannot be updated")
+	})
+
+	t.Run("NotInstalled", func(t *testing.T) {
+		_, err := mgr.UpdateExtension(context.TODO(), "hstore", schema.ExtensionMeta{
+			Database: "postgres",
+		})
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
 	t.Run("UpdateToLatest", func(t *testing.T) {
 		// Update plpgsql (always installed) to latest version
 		ext, err := mgr.UpdateExtension(context.TODO(), "plpgsql", schema.ExtensionMeta{
@@ -0,0 +1,106 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ListAlertRules returns the alert rules.
+func (c *Client) ListAlertRules(ctx context.Context, opts ...Opt) (*schema.AlertRuleList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.AlertRuleList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("alertrule"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// ListAlerts returns the enabled alert rules which are firing.
+func (c *Client) ListAlerts(ctx context.Context, opts ...Opt) (*schema.AlertRuleList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.AlertRuleList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("alerts"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// GetAlertRule returns an alert rule by name.
+func (c *Client) GetAlertRule(ctx context.Context, name string) (*schema.AlertRule, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.AlertRule
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("alertrule", name)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// CreateAlertRule creates an alert rule.
+func (c *Client) CreateAlertRule(ctx context.Context, meta schema.AlertRuleMeta) (*schema.AlertRule, error) {
+	req, err := client.NewJSONRequest(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.AlertRule
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("alertrule")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// UpdateAlertRule updates an alert rule by name.
+func (c *Client) UpdateAlertRule(ctx context.Context, name string, meta schema.AlertRuleMeta) (*schema.AlertRule, error) {
+	req, err := client.NewJSONRequestEx(http.MethodPatch, meta, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.AlertRule
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("alertrule", name)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// DeleteAlertRule deletes an alert rule by name.
+func (c *Client) DeleteAlertRule(ctx context.Context, name string) error {
+	return c.DoWithContext(ctx, client.MethodDelete, nil, client.OptPath("alertrule", name))
+}
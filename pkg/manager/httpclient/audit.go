@@ -0,0 +1,33 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	client "github.com/mutablelogic/go-client"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ListAudit returns the create, update and delete operations recorded in
+// the audit log, most recent first.
+func (c *Client) ListAudit(ctx context.Context, opts ...Opt) (*schema.AuditList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.AuditList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("audit"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -0,0 +1,155 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// streamRequest is a request which streams the body from a reader, and
+// accepts a text stream in response
+type streamRequest struct {
+	io.Reader
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// GetBackup returns the base backup in progress.
+func (c *Client) GetBackup(ctx context.Context) (*schema.Backup, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.Backup
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("backup")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// StartBackup starts a base backup.
+func (c *Client) StartBackup(ctx context.Context, meta schema.BackupMeta, opts ...Opt) (*schema.Backup, error) {
+	req, err := client.NewJSONRequest(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.Backup
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("backup"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// StopBackup stops the base backup in progress, and returns the contents
+// of the backup label and tablespace map files.
+func (c *Client) StopBackup(ctx context.Context, opts ...Opt) (*schema.Backup, error) {
+	req := client.NewRequestEx(http.MethodDelete, client.ContentTypeAny)
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.Backup
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("backup"), client.OptQuery(opt.Values), client.OptNoTimeout()); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// DumpDatabase writes a dump of a database to the writer.
+func (c *Client) DumpDatabase(ctx context.Context, w io.Writer, name string, opts ...Opt) error {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return err
+	}
+
+	// Perform request
+	return c.DoWithContext(ctx, req, w, client.OptPath("database", name, "dump"), client.OptQuery(opt.Values), client.OptNoTimeout())
+}
+
+// RestoreDatabase restores a database from a dump read from the reader. The
+// progress function, if not nil, is called as the server reports progress.
+func (c *Client) RestoreDatabase(ctx context.Context, r io.Reader, name string, fn func(schema.DatabaseRestoreProgress), opts ...Opt) (*schema.DatabaseRestore, error) {
+	req := &streamRequest{r}
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request, reading events from the text stream
+	var response schema.DatabaseRestore
+	var result error
+	callback := func(event client.TextStreamEvent) error {
+		switch event.Event {
+		case schema.RestoreEventProgress:
+			var progress schema.DatabaseRestoreProgress
+			if err := event.Json(&progress); err != nil {
+				return err
+			} else if fn != nil {
+				fn(progress)
+			}
+		case schema.RestoreEventResult:
+			return event.Json(&response)
+		case schema.RestoreEventError:
+			var message string
+			if err := event.Json(&message); err != nil {
+				return err
+			}
+			result = errors.New(message)
+		}
+		return nil
+	}
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("database", name, "restore"), client.OptQuery(opt.Values), client.OptTextStreamCallback(callback), client.OptNoTimeout()); err != nil {
+		return nil, err
+	} else if result != nil {
+		return nil, result
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (req *streamRequest) Method() string {
+	return http.MethodPost
+}
+
+func (req *streamRequest) Type() string {
+	return "application/octet-stream"
+}
+
+func (req *streamRequest) Accept() string {
+	return client.ContentTypeTextStream
+}
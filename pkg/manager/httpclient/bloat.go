@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// GetBloatReport returns the estimated bloat of tables and indexes, and the
+// growth of each database since the report was previously produced. If
+// database is non-empty, only that database is reported.
+func (c *Client) GetBloatReport(ctx context.Context, database string, opts ...Opt) (*schema.BloatReport, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build path based on whether database is specified
+	var pathOpt client.RequestOpt
+	if database != "" {
+		pathOpt = client.OptPath("bloat", database)
+	} else {
+		pathOpt = client.OptPath("bloat")
+	}
+
+	// Perform request
+	var response schema.BloatReport
+	if err := c.DoWithContext(ctx, req, &response, pathOpt, client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -1,8 +1,15 @@
 package httpclient
 
 import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+
 	// Packages
 	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -10,14 +17,26 @@ import (
 
 type Client struct {
 	*client.Client
+
+	// The endpoint, credentials and dial function, which are used to open
+	// WebSockets
+	endpoint string
+	token    *client.Token
+	dial     DialFunc
 }
 
+// DialFunc opens a connection to the server
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
 ///////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
-// New creates a new HTTP client for the PostgreSQL management API.
+// New creates a new HTTP client for the PostgreSQL management API. The client
+// requests the version of the API it was built for, so that responses keep
+// their shape when the server adds a new version.
 func New(url string, opts ...client.ClientOpt) (*Client, error) {
-	c := new(Client)
+	c := &Client{endpoint: url}
+	opts = append([]client.ClientOpt{client.OptParent(c), client.OptHeader(schema.APIVersionHeader, fmt.Sprint(schema.APIVersion))}, opts...)
 	if client, err := client.New(append(opts, client.OptEndpoint(url))...); err != nil {
 		return nil, err
 	} else {
@@ -25,3 +44,46 @@ func New(url string, opts ...client.ClientOpt) (*Client, error) {
 	}
 	return c, nil
 }
+
+///////////////////////////////////////////////////////////////////////////////
+// OPTIONS
+
+// WithToken returns a client option which sends a bearer token with each
+// request, for servers which require credentials
+func WithToken(token string) client.ClientOpt {
+	return withToken(client.Token{Scheme: client.Bearer, Value: token})
+}
+
+// WithBasicAuth returns a client option which sends a user and password with
+// each request, for servers which require credentials
+func WithBasicAuth(user, password string) client.ClientOpt {
+	return withToken(client.Token{Scheme: "Basic", Value: base64.StdEncoding.EncodeToString([]byte(user + ":" + password))})
+}
+
+// WithDial returns a client option which opens connections to the server
+// with a function rather than over the network, such as to a server in the
+// same process. It replaces the transport of the client, so it should be
+// given before WithRetry and WithCircuitBreaker.
+func WithDial(dial DialFunc) client.ClientOpt {
+	return func(cl *client.Client) error {
+		if c, ok := cl.Parent.(*Client); ok {
+			c.dial = dial
+		}
+		cl.Client.Transport = &http.Transport{DialContext: dial}
+		return nil
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return a client option which sends a token with each request, and keeps
+// the token so that it can be sent when a WebSocket is opened
+func withToken(token client.Token) client.ClientOpt {
+	return func(cl *client.Client) error {
+		if c, ok := cl.Parent.(*Client); ok {
+			c.token = &token
+		}
+		return client.OptReqToken(token)(cl)
+	}
+}
@@ -0,0 +1,24 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// SetComment sets the comment on a database, role, schema or object, or
+// removes the comment when it is empty.
+func (c *Client) SetComment(ctx context.Context, target schema.CommentTarget) error {
+	req, err := client.NewJSONRequest(target)
+	if err != nil {
+		return err
+	}
+
+	// Perform request
+	return c.DoWithContext(ctx, req, nil, client.OptPath("comment"))
+}
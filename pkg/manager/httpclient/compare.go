@@ -0,0 +1,72 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// GetProfile returns the settings, installed extensions and roles of the
+// server, which can be compared with another server.
+func (c *Client) GetProfile(ctx context.Context) (*schema.Profile, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.Profile
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("profile")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// CompareProfile returns the settings, extensions and roles which differ
+// between the server and the profile of another server.
+func (c *Client) CompareProfile(ctx context.Context, other schema.Profile) (*schema.Comparison, error) {
+	payload, err := client.NewJSONRequest(other)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.Comparison
+	if err := c.DoWithContext(ctx, payload, &response, client.OptPath("compare")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// DiffSchemas returns the tables, columns, constraints and indexes which
+// differ between two databases, and the statements which migrate the
+// first database to match the other.
+func (c *Client) DiffSchemas(ctx context.Context, database, other string, diff schema.SchemaDiffRequest) (*schema.SchemaDiff, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(
+		OptSet("database", database),
+		OptSet("other", other),
+		OptSet("schema", diff.Schema),
+		OptSet("other_schema", diff.OtherSchema),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.SchemaDiff
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("diff"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
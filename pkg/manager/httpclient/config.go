@@ -0,0 +1,93 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ListConfigFiles returns the settings in the configuration files.
+func (c *Client) ListConfigFiles(ctx context.Context, opts ...Opt) (*schema.ConfigFileSettingList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.ConfigFileSettingList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("config", "files"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// ListHBARules returns the rules in pg_hba.conf.
+func (c *Client) ListHBARules(ctx context.Context, opts ...Opt) (*schema.HBARuleList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.HBARuleList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("config", "hba"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// AddHBARule adds a rule to pg_hba.conf before a line, or at the end when
+// the line is zero, and returns the rules.
+func (c *Client) AddHBARule(ctx context.Context, meta schema.HBARuleMeta, before uint64) (*schema.HBARuleList, error) {
+	req, err := client.NewJSONRequest(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply options
+	opt, err := applyOpts(OptSet("before", fmt.Sprint(before)))
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.HBARuleList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("config", "hba"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// RemoveHBARule removes the rule on a line of pg_hba.conf, and returns the
+// rules.
+func (c *Client) RemoveHBARule(ctx context.Context, line uint64) (*schema.HBARuleList, error) {
+	req := client.NewRequestEx(http.MethodDelete, client.ContentTypeAny)
+
+	// Perform request
+	var response schema.HBARuleList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("config", "hba", fmt.Sprint(line))); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -2,6 +2,10 @@ package httpclient
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
 
 	// Packages
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
@@ -46,3 +50,78 @@ func (c *Client) GetConnection(ctx context.Context, pid uint64) (*schema.Connect
 func (c *Client) DeleteConnection(ctx context.Context, pid uint64) error {
 	return c.DoWithContext(ctx, client.MethodDelete, nil, client.OptPath("connection", pid))
 }
+
+// ListLongRunningQueries returns the connections which have been running a
+// query for longer than the threshold, longest first.
+func (c *Client) ListLongRunningQueries(ctx context.Context, threshold time.Duration) (*schema.LongRunningQueryList, error) {
+	return c.longRunning(ctx, threshold, false)
+}
+
+// ListIdleInTransaction returns the connections which have been idle in a
+// transaction for longer than the threshold, longest first.
+func (c *Client) ListIdleInTransaction(ctx context.Context, threshold time.Duration) (*schema.LongRunningQueryList, error) {
+	return c.longRunning(ctx, threshold, true)
+}
+
+// WatchConnections calls the function with each connection which is opened,
+// starts a query, changes state or is closed, until the context is cancelled.
+// Use OptDatabase, OptRole and WithInterval to filter the connections and
+// set how often the server polls them.
+func (c *Client) WatchConnections(ctx context.Context, fn func(schema.ConnectionEvent), opts ...Opt) error {
+	req := client.NewRequestEx(http.MethodGet, client.ContentTypeTextStream)
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return err
+	}
+
+	// Perform request, reading events from the text stream
+	var result error
+	callback := func(event client.TextStreamEvent) error {
+		switch event.Event {
+		case schema.ConnectionEventNew, schema.ConnectionEventQuery, schema.ConnectionEventState, schema.ConnectionEventTerminated:
+			change := schema.ConnectionEvent{Type: event.Event}
+			if err := event.Json(&change.Connection); err != nil {
+				return err
+			}
+			fn(change)
+		case schema.ConnectionEventError:
+			var message string
+			if err := event.Json(&message); err != nil {
+				return err
+			}
+			result = errors.New(message)
+		}
+		return nil
+	}
+	if err := c.DoWithContext(ctx, req, nil, client.OptPath("connection", "watch"), client.OptQuery(opt.Values), client.OptTextStreamCallback(callback), client.OptNoTimeout()); err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	// Return any error from the server
+	return result
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (c *Client) longRunning(ctx context.Context, threshold time.Duration, idle bool) (*schema.LongRunningQueryList, error) {
+	req := client.NewRequest()
+
+	// Set the query
+	query := url.Values{}
+	query.Set("threshold", threshold.String())
+	if idle {
+		query.Set("idle_in_transaction", "true")
+	}
+
+	// Perform request
+	var response schema.LongRunningQueryList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("connection", "long-running"), client.OptQuery(query)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -0,0 +1,88 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ListCronJobs returns the jobs scheduled with pg_cron.
+func (c *Client) ListCronJobs(ctx context.Context, opts ...Opt) (*schema.CronJobList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.CronJobList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("cronjob"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// GetCronJob returns a job scheduled with pg_cron.
+func (c *Client) GetCronJob(ctx context.Context, id uint64) (*schema.CronJob, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.CronJob
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("cronjob", id)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// CreateCronJob schedules a job with pg_cron.
+func (c *Client) CreateCronJob(ctx context.Context, meta schema.CronJobMeta) (*schema.CronJob, error) {
+	req, err := client.NewJSONRequest(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.CronJob
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("cronjob")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// DeleteCronJob unschedules a job with pg_cron.
+func (c *Client) DeleteCronJob(ctx context.Context, id uint64) error {
+	return c.DoWithContext(ctx, client.MethodDelete, nil, client.OptPath("cronjob", id))
+}
+
+// ListCronJobRuns returns the run history of jobs scheduled with pg_cron.
+func (c *Client) ListCronJobRuns(ctx context.Context, opts ...Opt) (*schema.CronJobRunList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.CronJobRunList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("cronrun"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
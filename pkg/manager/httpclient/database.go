@@ -60,6 +60,27 @@ func (c *Client) CreateDatabase(ctx context.Context, database schema.DatabaseMet
 	return &response, nil
 }
 
+// CloneDatabase creates a new database which is a copy of the source database
+func (c *Client) CloneDatabase(ctx context.Context, source, target string, clone schema.DatabaseCloneRequest) (*schema.Database, error) {
+	type request struct {
+		Name string `json:"name"`
+		schema.DatabaseCloneRequest
+	}
+	req, err := client.NewJSONRequest(request{target, clone})
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.Database
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("database", source, "clone")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
 func (c *Client) DeleteDatabase(ctx context.Context, name string, opt ...Opt) error {
 	opts, err := applyOpts(opt...)
 	if err != nil {
@@ -0,0 +1,29 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ExplainQuery returns the plan for a query.
+func (c *Client) ExplainQuery(ctx context.Context, req schema.ExplainRequest) (*schema.Explain, error) {
+	payload, err := client.NewJSONRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.Explain
+	if err := c.DoWithContext(ctx, payload, &response, client.OptPath("explain")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// GetIOStats returns the blocks of tables and indexes read from disk or found
+// in the buffer cache, with the hit ratios. If database is non-empty, only
+// tables and indexes from that database are returned.
+func (c *Client) GetIOStats(ctx context.Context, database string, opts ...Opt) (*schema.IOStatList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build path based on whether database is specified
+	var pathOpt client.RequestOpt
+	if database != "" {
+		pathOpt = client.OptPath("iostat", database)
+	} else {
+		pathOpt = client.OptPath("iostat")
+	}
+
+	// Perform request
+	var response schema.IOStatList
+	if err := c.DoWithContext(ctx, req, &response, pathOpt, client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
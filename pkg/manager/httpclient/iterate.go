@@ -0,0 +1,159 @@
+package httpclient
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Page is a page of a list, which is returned by the function that Iterate
+// calls for each page
+type Page[T any] struct {
+	Count uint64 // Number of items in the list, or after the token
+	Body  []T    // Items in the page
+	Next  string // Token for the next page, for lists paginated by key
+}
+
+// PageFunc returns a page of a list, with the offset or token for the page
+// set by the options
+type PageFunc[T any] func(ctx context.Context, opts ...Opt) (Page[T], error)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Iterate returns the items of a list, requesting pages until there are no
+// more items. The token for the next page is followed when the list returns
+// one, and otherwise the offset is advanced by the number of items in the
+// page. The limit in the options sets the page size, and the offset or token
+// in the options sets the first page. Iteration stops on the first error,
+// which is yielded with a zero item.
+func Iterate[T any](ctx context.Context, fn PageFunc[T], opts ...Opt) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		o, err := applyOpts(opts...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		// Determine the first page
+		var offset uint64
+		if v := o.Get("offset"); v != "" {
+			if offset, err = strconv.ParseUint(v, 10, 64); err != nil {
+				yield(zero, err)
+				return
+			}
+		}
+
+		for {
+			// Request the page, replacing the options with the values for the page
+			values := o.Values
+			page, err := fn(ctx, func(o *opt) error {
+				o.Values = cloneValues(values)
+				return nil
+			})
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			// Yield the items
+			for _, item := range page.Body {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			// Determine the next page
+			n := uint64(len(page.Body))
+			switch {
+			case page.Next != "":
+				offset = 0
+				o.Set("after", page.Next)
+				o.Del("offset")
+			case n > 0 && offset+n < page.Count:
+				offset += n
+				o.Set("offset", strconv.FormatUint(offset, 10))
+			default:
+				return
+			}
+		}
+	}
+}
+
+// ListObjectsAll returns all the objects, following the pages of the list.
+// If database is non-empty, only objects from that database are returned. If
+// namespace is also non-empty, objects are further filtered by schema.
+func (c *Client) ListObjectsAll(ctx context.Context, database, namespace string, opts ...Opt) iter.Seq2[schema.Object, error] {
+	return Iterate(ctx, func(ctx context.Context, opts ...Opt) (Page[schema.Object], error) {
+		list, err := c.ListObjects(ctx, database, namespace, opts...)
+		if err != nil {
+			return Page[schema.Object]{}, err
+		}
+		return Page[schema.Object]{Count: list.Count, Body: list.Body, Next: list.Next}, nil
+	}, opts...)
+}
+
+// ListDatabasesAll returns all the databases, following the pages of the list
+func (c *Client) ListDatabasesAll(ctx context.Context, opts ...Opt) iter.Seq2[schema.Database, error] {
+	return Iterate(ctx, func(ctx context.Context, opts ...Opt) (Page[schema.Database], error) {
+		list, err := c.ListDatabases(ctx, opts...)
+		if err != nil {
+			return Page[schema.Database]{}, err
+		}
+		return Page[schema.Database]{Count: list.Count, Body: list.Body}, nil
+	}, opts...)
+}
+
+// ListRolesAll returns all the roles, following the pages of the list
+func (c *Client) ListRolesAll(ctx context.Context, opts ...Opt) iter.Seq2[schema.Role, error] {
+	return Iterate(ctx, func(ctx context.Context, opts ...Opt) (Page[schema.Role], error) {
+		list, err := c.ListRoles(ctx, opts...)
+		if err != nil {
+			return Page[schema.Role]{}, err
+		}
+		return Page[schema.Role]{Count: list.Count, Body: list.Body}, nil
+	}, opts...)
+}
+
+// ListSchemasAll returns all the schemas, following the pages of the list.
+// If database is non-empty, only schemas from that database are returned.
+func (c *Client) ListSchemasAll(ctx context.Context, database string, opts ...Opt) iter.Seq2[schema.Schema, error] {
+	return Iterate(ctx, func(ctx context.Context, opts ...Opt) (Page[schema.Schema], error) {
+		list, err := c.ListSchemas(ctx, database, opts...)
+		if err != nil {
+			return Page[schema.Schema]{}, err
+		}
+		return Page[schema.Schema]{Count: list.Count, Body: list.Body}, nil
+	}, opts...)
+}
+
+// ListConnectionsAll returns all the connections, following the pages of the
+// list
+func (c *Client) ListConnectionsAll(ctx context.Context, opts ...Opt) iter.Seq2[schema.Connection, error] {
+	return Iterate(ctx, func(ctx context.Context, opts ...Opt) (Page[schema.Connection], error) {
+		list, err := c.ListConnections(ctx, opts...)
+		if err != nil {
+			return Page[schema.Connection]{}, err
+		}
+		return Page[schema.Connection]{Count: list.Count, Body: list.Body, Next: list.Next}, nil
+	}, opts...)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func cloneValues(values url.Values) url.Values {
+	result := make(url.Values, len(values))
+	for k, v := range values {
+		result[k] = append([]string(nil), v...)
+	}
+	return result
+}
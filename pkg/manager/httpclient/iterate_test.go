@@ -0,0 +1,251 @@
+package httpclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	// Packages
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// pages is a server which returns a list of roles a page at a time by
+// offset, and a list of objects a page at a time by key, and records the
+// query of each request
+type pages struct {
+	sync.Mutex
+	roles   []string
+	objects []string
+	fail    int // Return an error for this request, when non-zero
+	queries []url.Values
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Iterate_Offset(t *testing.T) {
+	assert := assert.New(t)
+	server, client := newPages(t, &pages{roles: names("role", 7)})
+
+	var result []string
+	for role, err := range client.ListRolesAll(context.TODO(), httpclient.WithOffsetLimit(0, types.Uint64Ptr(3))) {
+		if !assert.NoError(err) {
+			break
+		}
+		result = append(result, role.Name)
+	}
+	assert.Equal(names("role", 7), result)
+
+	// Three pages are requested, advancing the offset
+	if assert.Len(server.queries, 3) {
+		assert.Equal("", server.queries[0].Get("offset"))
+		assert.Equal("3", server.queries[1].Get("offset"))
+		assert.Equal("6", server.queries[2].Get("offset"))
+		for _, query := range server.queries {
+			assert.Equal("3", query.Get("limit"))
+		}
+	}
+}
+
+func Test_Iterate_FirstPage(t *testing.T) {
+	assert := assert.New(t)
+	_, client := newPages(t, &pages{roles: names("role", 7)})
+
+	var result []string
+	for role, err := range client.ListRolesAll(context.TODO(), httpclient.WithOffsetLimit(2, types.Uint64Ptr(3))) {
+		if !assert.NoError(err) {
+			break
+		}
+		result = append(result, role.Name)
+	}
+	assert.Equal(names("role", 7)[2:], result)
+}
+
+func Test_Iterate_Key(t *testing.T) {
+	assert := assert.New(t)
+	server, client := newPages(t, &pages{objects: names("object", 5)})
+
+	var result []string
+	for object, err := range client.ListObjectsAll(context.TODO(), "", "", httpclient.WithOffsetLimit(0, types.Uint64Ptr(2))) {
+		if !assert.NoError(err) {
+			break
+		}
+		result = append(result, object.Name)
+	}
+	assert.Equal(names("object", 5), result)
+
+	// The token of the previous page is followed
+	if assert.Len(server.queries, 3) {
+		assert.Equal("", server.queries[0].Get("after"))
+		assert.Equal("object1", server.queries[1].Get("after"))
+		assert.Equal("object3", server.queries[2].Get("after"))
+		for _, query := range server.queries {
+			assert.Equal("", query.Get("offset"))
+		}
+	}
+}
+
+func Test_Iterate_Break(t *testing.T) {
+	assert := assert.New(t)
+	server, client := newPages(t, &pages{roles: names("role", 7)})
+
+	var result []string
+	for role, err := range client.ListRolesAll(context.TODO(), httpclient.WithOffsetLimit(0, types.Uint64Ptr(3))) {
+		if !assert.NoError(err) {
+			break
+		}
+		result = append(result, role.Name)
+		if len(result) == 4 {
+			break
+		}
+	}
+	assert.Equal(names("role", 4), result)
+
+	// No more pages are requested after the break
+	assert.Len(server.queries, 2)
+}
+
+func Test_Iterate_Error(t *testing.T) {
+	assert := assert.New(t)
+	server, client := newPages(t, &pages{roles: names("role", 7), fail: 2})
+
+	var result []string
+	var errs []error
+	for role, err := range client.ListRolesAll(context.TODO(), httpclient.WithOffsetLimit(0, types.Uint64Ptr(3))) {
+		if err != nil {
+			assert.Empty(role.Name)
+			errs = append(errs, err)
+			continue
+		}
+		result = append(result, role.Name)
+	}
+
+	// The items of the first page are yielded, then the error, once
+	assert.Equal(names("role", 3), result)
+	assert.Len(errs, 1)
+	assert.Len(server.queries, 2)
+}
+
+func Test_Iterate_PageError(t *testing.T) {
+	assert := assert.New(t)
+
+	// An error returned by the page function is yielded, and stops iteration
+	calls := 0
+	var errs []error
+	for _, err := range httpclient.Iterate(context.TODO(), func(ctx context.Context, opts ...httpclient.Opt) (httpclient.Page[int], error) {
+		calls++
+		return httpclient.Page[int]{}, fmt.Errorf("page error")
+	}) {
+		errs = append(errs, err)
+	}
+	assert.Equal(1, calls)
+	if assert.Len(errs, 1) {
+		assert.EqualError(errs[0], "page error")
+	}
+}
+
+func Test_Iterate_EmptyPage(t *testing.T) {
+	assert := assert.New(t)
+
+	// An empty page stops iteration, even when the count says there are more
+	calls := 0
+	for range httpclient.Iterate(context.TODO(), func(ctx context.Context, opts ...httpclient.Opt) (httpclient.Page[int], error) {
+		calls++
+		return httpclient.Page[int]{Count: 10}, nil
+	}) {
+		assert.Fail("unexpected item")
+	}
+	assert.Equal(1, calls)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return a server for the pages, and a client for the server
+func newPages(t *testing.T, p *pages) (*pages, *httpclient.Client) {
+	t.Helper()
+	server := httptest.NewServer(p)
+	t.Cleanup(server.Close)
+	client, err := httpclient.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, client
+}
+
+func (p *pages) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.Lock()
+	query := r.URL.Query()
+	p.queries = append(p.queries, query)
+	fail := p.fail == len(p.queries)
+	p.Unlock()
+	if fail {
+		http.Error(w, "page error", http.StatusInternalServerError)
+		return
+	}
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/role":
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		list := schema.RoleList{Count: uint64(len(p.roles))}
+		for _, name := range page(p.roles, offset, limit) {
+			list.Body = append(list.Body, schema.Role{RoleMeta: schema.RoleMeta{Name: name}})
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case "/object":
+		offset := 0
+		if after := query.Get("after"); after != "" {
+			for i, name := range p.objects {
+				if name == after {
+					offset = i + 1
+				}
+			}
+		}
+		body := page(p.objects, offset, limit)
+		list := schema.ObjectList{Count: uint64(len(p.objects) - offset)}
+		for _, name := range body {
+			list.Body = append(list.Body, schema.Object{ObjectMeta: schema.ObjectMeta{Name: name}})
+		}
+		if offset+len(body) < len(p.objects) {
+			list.Next = body[len(body)-1]
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Return the items in a page
+func page(items []string, offset, limit int) []string {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// Return n names with a prefix
+func names(prefix string, n int) []string {
+	result := make([]string, 0, n)
+	for i := range n {
+		result = append(result, prefix+strconv.Itoa(i))
+	}
+	return result
+}
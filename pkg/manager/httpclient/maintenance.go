@@ -0,0 +1,45 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	client "github.com/mutablelogic/go-client"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// GetMaintenance returns the maintenance windows, when the next window
+// opens, and the heavy operations which are queued or have recently run.
+func (c *Client) GetMaintenance(ctx context.Context) (*schema.Maintenance, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.Maintenance
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("maintenance")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// QueueMaintenance queues a heavy operation until the next maintenance
+// window opens, and returns the task.
+func (c *Client) QueueMaintenance(ctx context.Context, meta schema.MaintenanceTaskMeta) (*schema.MaintenanceTask, error) {
+	req, err := client.NewJSONRequest(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.MaintenanceTask
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("maintenance")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -0,0 +1,98 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	websocket "golang.org/x/net/websocket"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Subscribe opens a WebSocket which receives the notifications on a channel,
+// and calls the function for each notification until the context is
+// cancelled or the server closes the connection, when it returns nil. An
+// error from the function, or an error sent by the server, is returned.
+func (c *Client) Subscribe(ctx context.Context, channel string, fn func(schema.Notification) error) error {
+	config, err := c.websocketConfig("notify", channel)
+	if err != nil {
+		return err
+	}
+
+	// Open the WebSocket, and close it when the context is cancelled
+	ws, err := c.websocketDial(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer ws.Close()
+	go func() {
+		<-ctx.Done()
+		ws.Close()
+	}()
+
+	// Receive notifications
+	for {
+		var notification schema.Notification
+		if err := websocket.JSON.Receive(ws, &notification); errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			return err
+		} else if notification.Error != "" {
+			return errors.New(notification.Error)
+		}
+		if err := fn(notification); err != nil {
+			return err
+		}
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the configuration for a WebSocket on a path relative to the
+// endpoint, with the API version and credentials
+func (c *Client) websocketConfig(path ...string) (*websocket.Config, error) {
+	origin, err := url.Parse(c.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	location := origin.JoinPath(path...)
+	switch location.Scheme {
+	case "http":
+		location.Scheme = "ws"
+	case "https":
+		location.Scheme = "wss"
+	}
+	config, err := websocket.NewConfig(location.String(), origin.String())
+	if err != nil {
+		return nil, err
+	}
+	config.Header.Set(schema.APIVersionHeader, fmt.Sprint(schema.APIVersion))
+	if c.token != nil {
+		config.Header.Set("Authorization", c.token.String())
+	}
+	return config, nil
+}
+
+// Open a WebSocket, with the dial function of the client when it has one
+func (c *Client) websocketDial(ctx context.Context, config *websocket.Config) (*websocket.Conn, error) {
+	if c.dial == nil {
+		return config.DialContext(ctx)
+	}
+	conn, err := c.dial(ctx, "tcp", config.Location.Host)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := websocket.NewClient(config, conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
@@ -2,6 +2,9 @@ package httpclient
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
 
 	// Packages
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
@@ -57,3 +60,119 @@ func (c *Client) GetObject(ctx context.Context, database, namespace, name string
 	// Return the responses
 	return &response, nil
 }
+
+// ListColumns returns the columns of a table, view or materialized view by
+// database, namespace (schema) and name.
+func (c *Client) ListColumns(ctx context.Context, database, namespace, name string) (*schema.ColumnList, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.ColumnList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("object", database, namespace, name, "column")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// GetObjectDDL returns the statements which create a table, view, sequence,
+// index or function by database, namespace (schema) and name.
+func (c *Client) GetObjectDDL(ctx context.Context, database, namespace, name string) (*schema.ObjectDDL, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.ObjectDDL
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("object", database, namespace, name, "ddl")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// ExportObject writes the rows of a table, view, materialized view or
+// foreign table to the writer as newline-delimited JSON, one row per line.
+func (c *Client) ExportObject(ctx context.Context, w io.Writer, database, namespace, name string) error {
+	req := client.NewRequest()
+
+	// Perform request, writing each row as it is read
+	var row json.RawMessage
+	return c.DoWithContext(ctx, req, &row, client.OptPath("object", database, namespace, name, "export"), client.OptNoTimeout(), client.OptJsonStreamCallback(func(any) error {
+		_, err := w.Write(append(row, '\n'))
+		return err
+	}))
+}
+
+// ReindexObject rebuilds an index, or all the indexes of a table, and returns
+// the object.
+func (c *Client) ReindexObject(ctx context.Context, database, namespace, name string, opts ...Opt) (*schema.Object, error) {
+	req := client.NewRequestEx(http.MethodPost, client.ContentTypeAny)
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.Object
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("object", database, namespace, name, "reindex"), client.OptQuery(opt.Values), client.OptNoTimeout()); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// GetColumnStatistics returns the planner statistics of the columns of a
+// table by database, namespace (schema) and name.
+func (c *Client) GetColumnStatistics(ctx context.Context, database, namespace, name string) (*schema.ColumnStatisticsList, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.ColumnStatisticsList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("object", database, namespace, name, "statistics")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// SetStatisticsTarget sets the statistics target of a column of a table, or
+// resets it to the default when the target is not set, and returns the
+// statistics of the columns.
+func (c *Client) SetStatisticsTarget(ctx context.Context, database, namespace, name string, meta schema.ColumnStatisticsMeta) (*schema.ColumnStatisticsList, error) {
+	req, err := client.NewJSONRequestEx(http.MethodPatch, meta, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.ColumnStatisticsList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("object", database, namespace, name, "statistics")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// VerifyObject checks a table, materialized view or btree index for
+// corruption with amcheck, and returns the corruption which was found.
+func (c *Client) VerifyObject(ctx context.Context, database, namespace, name string, req schema.ObjectVerifyRequest) (*schema.ObjectVerification, error) {
+	payload, err := client.NewJSONRequestEx(http.MethodPost, req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.ObjectVerification
+	if err := c.DoWithContext(ctx, payload, &response, client.OptPath("object", database, namespace, name, "verify"), client.OptNoTimeout()); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -0,0 +1,40 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// Packages
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_ExportObject(t *testing.T) {
+	assert := assert.New(t)
+
+	// Return the rows with whitespace between them, which is not written
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Write([]byte("{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n\n{\"id\":3,\"name\":null}\n"))
+	}))
+	t.Cleanup(server.Close)
+	client, err := httpclient.New(server.URL)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Rows", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(client.ExportObject(context.TODO(), &out, "db", "public", "users"))
+		assert.Equal("/object/db/public/users/export", path)
+		assert.Equal("{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n{\"id\":3,\"name\":null}\n", out.String())
+	})
+}
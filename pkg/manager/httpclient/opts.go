@@ -3,6 +3,7 @@ package httpclient
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	// Packages
 	types "github.com/mutablelogic/go-server/pkg/types"
@@ -76,6 +77,17 @@ func WithCategory(v *string) Opt {
 	return OptSet("category", types.PtrString(v))
 }
 
+func WithName(v *string) Opt {
+	return OptSet("name", types.PtrString(v))
+}
+
+func WithPendingRestart(v bool) Opt {
+	if v {
+		return OptSet("pending_restart", "true")
+	}
+	return OptSet("pending_restart", "")
+}
+
 func WithReload(v bool) Opt {
 	if v {
 		return OptSet("reload", "true")
@@ -83,10 +95,34 @@ func WithReload(v bool) Opt {
 	return OptSet("reload", "")
 }
 
+// WithSystemScope validates a setting update and writes it with ALTER SYSTEM,
+// including settings which require a restart
+func WithSystemScope(v bool) Opt {
+	if v {
+		return OptSet("scope", "system")
+	}
+	return OptSet("scope", "")
+}
+
 func WithType(v *string) Opt {
 	return OptSet("type", types.PtrString(v))
 }
 
+func WithUsername(v *string) Opt {
+	return OptSet("username", types.PtrString(v))
+}
+
+func WithStatus(v *string) Opt {
+	return OptSet("status", types.PtrString(v))
+}
+
+func WithJob(v *uint64) Opt {
+	if v == nil {
+		return OptSet("job", "")
+	}
+	return OptSet("job", fmt.Sprint(*v))
+}
+
 func WithInstalled(v *bool) Opt {
 	return func(o *opt) error {
 		if v == nil {
@@ -131,6 +167,143 @@ func WithSort(v string) Opt {
 	return OptSet("sort", v)
 }
 
+// WithAfter sets the token for the next page, from the "next" field of a
+// previous list response
+func WithAfter(v string) Opt {
+	return OptSet("after", v)
+}
+
+func WithWait(v bool) Opt {
+	if v {
+		return OptSet("wait", "true")
+	}
+	return OptSet("wait", "")
+}
+
+func WithFormat(v string) Opt {
+	return OptSet("format", v)
+}
+
+func WithSchemaOnly(v bool) Opt {
+	if v {
+		return OptSet("schema_only", "true")
+	}
+	return OptSet("schema_only", "")
+}
+
+func WithDataOnly(v bool) Opt {
+	if v {
+		return OptSet("data_only", "true")
+	}
+	return OptSet("data_only", "")
+}
+
+func WithClean(v bool) Opt {
+	if v {
+		return OptSet("clean", "true")
+	}
+	return OptSet("clean", "")
+}
+
+func WithSingleTransaction(v bool) Opt {
+	if v {
+		return OptSet("single_transaction", "true")
+	}
+	return OptSet("single_transaction", "")
+}
+
+// WithConfirm confirms an operation which cannot be undone
+func WithConfirm(v bool) Opt {
+	if v {
+		return OptSet("confirm", "true")
+	}
+	return OptSet("confirm", "")
+}
+
+func WithConcurrently(v bool) Opt {
+	if v {
+		return OptSet("concurrently", "true")
+	}
+	return OptSet("concurrently", "")
+}
+
+// WithDays sets the number of days without activity for a stale table
+func WithDays(v *uint64) Opt {
+	if v == nil {
+		return OptSet("days", "")
+	}
+	return OptSet("days", fmt.Sprint(*v))
+}
+
+func WithResource(v *string) Opt {
+	return OptSet("resource", types.PtrString(v))
+}
+
+func WithActor(v *string) Opt {
+	return OptSet("actor", types.PtrString(v))
+}
+
+// WithSince sets the start of a time range, inclusive
+func WithSince(v *time.Time) Opt {
+	if v == nil || v.IsZero() {
+		return OptSet("since", "")
+	}
+	return OptSet("since", v.Format(time.RFC3339))
+}
+
+// WithUntil sets the end of a time range, exclusive
+func WithUntil(v *time.Time) Opt {
+	if v == nil || v.IsZero() {
+		return OptSet("until", "")
+	}
+	return OptSet("until", v.Format(time.RFC3339))
+}
+
+// WithRequest filters the audit log by a JSON document which the request
+// contains
+func WithRequest(v *string) Opt {
+	return OptSet("request", types.PtrString(v))
+}
+
+// WithPath filters the audit log by a jsonpath expression which matches the
+// request
+func WithPath(v *string) Opt {
+	return OptSet("path", types.PtrString(v))
+}
+
+// WithError filters the configuration files by entries which have an error
+func WithError(v *bool) Opt {
+	if v == nil {
+		return OptSet("error", "")
+	}
+	return OptSet("error", fmt.Sprint(*v))
+}
+
+// WithEnabled filters alert rules by whether they are evaluated
+func WithEnabled(v *bool) Opt {
+	if v == nil {
+		return OptSet("enabled", "")
+	}
+	return OptSet("enabled", fmt.Sprint(*v))
+}
+
+// WithInterval sets how often the server polls watched connections
+func WithInterval(v time.Duration) Opt {
+	if v <= 0 {
+		return OptSet("interval", "")
+	}
+	return OptSet("interval", v.String())
+}
+
+// WithOverride runs a heavy operation, such as a reindex or base backup,
+// outside of the maintenance windows
+func WithOverride(v bool) Opt {
+	if v {
+		return OptSet("override", "true")
+	}
+	return OptSet("override", "")
+}
+
 func OptSet(k, v string) Opt {
 	return func(o *opt) error {
 		if v == "" {
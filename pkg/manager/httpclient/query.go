@@ -0,0 +1,29 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Query runs a read-only query and returns the columns and rows.
+func (c *Client) Query(ctx context.Context, req schema.QueryRequest) (*schema.QueryResult, error) {
+	payload, err := client.NewJSONRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.QueryResult
+	if err := c.DoWithContext(ctx, payload, &response, client.OptPath("query")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -0,0 +1,55 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// GetRecoveryStatus returns whether the server is a standby, with the
+// positions of the WAL it has received and replayed.
+func (c *Client) GetRecoveryStatus(ctx context.Context) (*schema.RecoveryStatus, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.RecoveryStatus
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("recovery")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// IsInRecovery returns true if the server is a standby.
+func (c *Client) IsInRecovery(ctx context.Context) (bool, error) {
+	status, err := c.GetRecoveryStatus(ctx)
+	if err != nil {
+		return false, err
+	}
+	return status.InRecovery, nil
+}
+
+// PromoteStandby promotes the standby to a primary, and returns the recovery
+// status.
+func (c *Client) PromoteStandby(ctx context.Context, promote schema.PromoteRequest) (*schema.RecoveryStatus, error) {
+	req, err := client.NewJSONRequestEx(http.MethodPost, promote, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.RecoveryStatus
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("recovery", "promote")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
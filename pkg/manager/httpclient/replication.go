@@ -0,0 +1,27 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// GetReplicationStatus returns the standbys which stream from the server,
+// and the WAL receiver when the server is a standby.
+func (c *Client) GetReplicationStatus(ctx context.Context) (*schema.ReplicationStatus, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.ReplicationStatus
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("replication", "status")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -41,6 +41,35 @@ func (c *Client) ListSchemas(ctx context.Context, database string, opts ...Opt)
 	return &response, nil
 }
 
+// ListSchemaSizes returns the sizes of schemas. If database is non-empty,
+// only schemas from that database are returned.
+func (c *Client) ListSchemaSizes(ctx context.Context, database string, opts ...Opt) (*schema.SchemaRollupList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build path based on whether database is specified
+	var pathOpt client.RequestOpt
+	if database != "" {
+		pathOpt = client.OptPath("schemasize", database)
+	} else {
+		pathOpt = client.OptPath("schemasize")
+	}
+
+	// Perform request
+	var response schema.SchemaRollupList
+	if err := c.DoWithContext(ctx, req, &response, pathOpt, client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
 // GetSchema returns a schema by database and namespace name.
 func (c *Client) GetSchema(ctx context.Context, database, namespace string) (*schema.Schema, error) {
 	req := client.NewRequest()
@@ -0,0 +1,27 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// GetServer returns the metadata for the connected server, including whether
+// it is a standby.
+func (c *Client) GetServer(ctx context.Context) (*schema.Server, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.Server
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("server")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -44,6 +44,41 @@ func (c *Client) ListSettingCategories(ctx context.Context) (*schema.SettingCate
 	return &response, nil
 }
 
+// ListSettingHistory returns the changes made to settings, most recent first.
+func (c *Client) ListSettingHistory(ctx context.Context, opts ...Opt) (*schema.SettingHistoryList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.SettingHistoryList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("setting", "history"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// ReloadConfig reloads the configuration files, and returns the server with
+// the time the configuration was loaded.
+func (c *Client) ReloadConfig(ctx context.Context) (*schema.Server, error) {
+	req := client.NewRequestEx(http.MethodPost, client.ContentTypeAny)
+
+	// Perform request
+	var response schema.Server
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("setting", "reload")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
 func (c *Client) GetSetting(ctx context.Context, name string) (*schema.Setting, error) {
 	req := client.NewRequest()
 
@@ -78,3 +113,44 @@ func (c *Client) UpdateSetting(ctx context.Context, name string, meta schema.Set
 	// Return the responses
 	return &response, nil
 }
+
+func (c *Client) ListPendingSettings(ctx context.Context, opts ...Opt) (*schema.SettingList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.SettingList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("setting", "pending"), client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+func (c *Client) DiffSettings(ctx context.Context, desired map[string]string) (*schema.SettingDiff, error) {
+	req, err := client.NewJSONRequest(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform request
+	var response schema.SettingDiff
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("setting", "diff")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
+// SetSystemSetting validates a setting value and writes it with ALTER SYSTEM,
+// including settings which require a restart. A nil value resets the setting.
+func (c *Client) SetSystemSetting(ctx context.Context, name string, meta schema.SettingMeta, opts ...Opt) (*schema.Setting, error) {
+	return c.UpdateSetting(ctx, name, meta, append(opts, WithSystemScope(true))...)
+}
@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ListStaleTables returns tables with no reads or writes since the statistics
+// were reset. If database is non-empty, only tables from that database are
+// returned.
+func (c *Client) ListStaleTables(ctx context.Context, database string, opts ...Opt) (*schema.StaleTableList, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build path based on whether database is specified
+	var pathOpt client.RequestOpt
+	if database != "" {
+		pathOpt = client.OptPath("staletable", database)
+	} else {
+		pathOpt = client.OptPath("staletable")
+	}
+
+	// Perform request
+	var response schema.StaleTableList
+	if err := c.DoWithContext(ctx, req, &response, pathOpt, client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -36,3 +36,21 @@ func (c *Client) ListStatements(ctx context.Context, opts ...Opt) (*schema.State
 func (c *Client) ResetStatements(ctx context.Context) error {
 	return c.DoWithContext(ctx, client.MethodDelete, nil, client.OptPath("statement"))
 }
+
+// ResetStatistics resets the statistics of a database, a table or statements.
+// The reset is refused unless it is confirmed with WithConfirm(true).
+func (c *Client) ResetStatistics(ctx context.Context, target schema.StatisticsTarget, opts ...Opt) error {
+	req, err := client.NewJSONRequest(target)
+	if err != nil {
+		return err
+	}
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return err
+	}
+
+	// Perform request
+	return c.DoWithContext(ctx, req, nil, client.OptPath("statistics", "reset"), client.OptQuery(opt.Values))
+}
@@ -31,6 +31,20 @@ func (c *Client) ListTablespaces(ctx context.Context, opts ...Opt) (*schema.Tabl
 	return &response, nil
 }
 
+// ListTablespaceStats returns the space on the filesystem of each tablespace
+func (c *Client) ListTablespaceStats(ctx context.Context) (*schema.TablespaceStatList, error) {
+	req := client.NewRequest()
+
+	// Perform request
+	var response schema.TablespaceStatList
+	if err := c.DoWithContext(ctx, req, &response, client.OptPath("tablespace", "stat")); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
+
 func (c *Client) CreateTablespace(ctx context.Context, meta schema.TablespaceMeta, location string) (*schema.Tablespace, error) {
 	type create struct {
 		schema.TablespaceMeta
@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	"context"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// GetTempUsage returns the temporary files written in each database, and by
+// the statements which wrote the most temporary files. If database is
+// non-empty, only that database and its statements are returned.
+func (c *Client) GetTempUsage(ctx context.Context, database string, opts ...Opt) (*schema.TempUsage, error) {
+	req := client.NewRequest()
+
+	// Apply options
+	opt, err := applyOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build path based on whether database is specified
+	var pathOpt client.RequestOpt
+	if database != "" {
+		pathOpt = client.OptPath("tempusage", database)
+	} else {
+		pathOpt = client.OptPath("tempusage")
+	}
+
+	// Perform request
+	var response schema.TempUsage
+	if err := c.DoWithContext(ctx, req, &response, pathOpt, client.OptQuery(opt.Values)); err != nil {
+		return nil, err
+	}
+
+	// Return the responses
+	return &response, nil
+}
@@ -0,0 +1,251 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	pg "github.com/mutablelogic/go-pg"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// retry is a transport which repeats requests which fail with a transient
+// error, such as a 502 or 503 from a proxy in front of the server
+type retry struct {
+	http.RoundTripper
+	max     uint
+	backoff time.Duration
+}
+
+// breaker is a transport which fails requests without sending them when
+// there have been too many consecutive transient errors, until the cooldown
+// has passed. A single request is then sent, which closes the circuit when
+// it succeeds and opens it again when it fails
+type breaker struct {
+	http.RoundTripper
+	sync.Mutex
+	threshold uint
+	cooldown  time.Duration
+	failures  uint
+	opened    time.Time
+	probing   bool
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum delay before a request is repeated, including the delay
+	// requested by the server with Retry-After
+	retryMaxDelay = 30 * time.Second
+
+	// Maximum size of a request body which is kept so that the request can
+	// be repeated. Larger bodies, such as a restore, are not repeated
+	retryMaxBody = 1 << 20
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// OPTIONS
+
+// WithRetry returns a client option which repeats a request up to max times
+// when it fails with a 502, 503 or 504 status or a connection error. The
+// delay before the first repeat is backoff, which doubles for each further
+// repeat, unless the server requests a delay with Retry-After. Requests which
+// are not idempotent are only repeated on a 503, and requests with a body
+// which cannot be read again are not repeated.
+func WithRetry(max uint, backoff time.Duration) client.ClientOpt {
+	return func(cl *client.Client) error {
+		if max > 0 {
+			cl.Client.Transport = &retry{transport(cl), max, backoff}
+		}
+		return nil
+	}
+}
+
+// WithCircuitBreaker returns a client option which fails requests without
+// sending them after threshold consecutive transient errors, until the
+// cooldown has passed. When used with WithRetry, the option given last is
+// applied first, so that a circuit breaker given after WithRetry counts
+// each request once however many times it is repeated.
+func WithCircuitBreaker(threshold uint, cooldown time.Duration) client.ClientOpt {
+	return func(cl *client.Client) error {
+		if threshold > 0 {
+			cl.Client.Transport = &breaker{RoundTripper: transport(cl), threshold: threshold, cooldown: cooldown}
+		}
+		return nil
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RoundTrip sends the request, repeating it after transient errors
+func (r *retry) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, err := rewindable(req)
+	if err != nil {
+		return nil, err
+	}
+	delay := r.backoff
+	for attempt := uint(0); ; attempt++ {
+		resp, err := r.RoundTripper.RoundTrip(req)
+		if attempt >= r.max || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		// Wait for the delay requested by the server, or the backoff
+		wait := min(retryAfter(resp, delay), retryMaxDelay)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		delay *= 2
+
+		// Read the body again
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// RoundTrip sends the request unless the circuit is open
+func (b *breaker) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !b.allow(time.Now()) {
+		return nil, pg.ErrNotAvailable.With("circuit breaker is open for ", req.URL.Host)
+	}
+	resp, err := b.RoundTripper.RoundTrip(req)
+	b.record(transient(req, resp, err), time.Now())
+	return resp, err
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the transport of the client
+func transport(cl *client.Client) http.RoundTripper {
+	if cl.Client.Transport == nil {
+		return http.DefaultTransport
+	}
+	return cl.Client.Transport
+}
+
+// Return true if the request is allowed, which is when the circuit is closed,
+// or when the cooldown has passed and no other request is being sent
+func (b *breaker) allow(now time.Time) bool {
+	b.Lock()
+	defer b.Unlock()
+	switch {
+	case b.failures < b.threshold:
+		return true
+	case b.probing || now.Sub(b.opened) < b.cooldown:
+		return false
+	default:
+		b.probing = true
+		return true
+	}
+}
+
+// Record the result of a request, opening the circuit when there have been
+// too many consecutive failures
+func (b *breaker) record(failed bool, now time.Time) {
+	b.Lock()
+	defer b.Unlock()
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+	if b.failures++; b.failures >= b.threshold {
+		b.opened = now
+	}
+}
+
+// Return the request with a body which can be read again, when the body is
+// small enough to be kept in memory
+func rewindable(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return req, nil
+	}
+
+	// Read the body, up to the maximum size
+	data, err := io.ReadAll(io.LimitReader(req.Body, retryMaxBody+1))
+	if err != nil {
+		req.Body.Close()
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	if len(data) > retryMaxBody {
+		// Send the body which has been read, then the rest
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), req.Body), req.Body}
+		return req, nil
+	}
+	req.Body.Close()
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.Body, _ = req.GetBody()
+	return req, nil
+}
+
+// Return true if the request failed with a transient error and can be sent
+// again
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if !transient(req, resp, err) {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return resp != nil && resp.StatusCode == http.StatusServiceUnavailable
+	}
+}
+
+// Return true if the response is a transient error from a proxy, or there
+// was no response and the request was not cancelled
+func transient(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// Return the delay requested by the server in seconds with Retry-After, or
+// the default
+func retryAfter(resp *http.Response, delay time.Duration) time.Duration {
+	if resp == nil {
+		return delay
+	}
+	if seconds, err := strconv.ParseUint(resp.Header.Get("Retry-After"), 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	return delay
+}
@@ -0,0 +1,318 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	pg "github.com/mutablelogic/go-pg"
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// statuses is a server which responds to each request with the next status,
+// or 200 when there are no more, and records the time and body of each
+// request
+type statuses struct {
+	sync.Mutex
+	*httptest.Server
+	status     []int
+	retryAfter string
+	times      []time.Time
+	bodies     []string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RETRY TESTS
+
+func Test_Retry_Backoff(t *testing.T) {
+	assert := assert.New(t)
+	server, c := newStatuses(t, &statuses{status: []int{503, 502, 504}}, httpclient.WithRetry(3, 20*time.Millisecond))
+
+	resp, err := do(c, server.URL, http.MethodGet, nil)
+	if assert.NoError(err) {
+		assert.Equal(http.StatusOK, resp.StatusCode)
+	}
+
+	// The delay doubles for each repeat
+	if assert.Len(server.times, 4) {
+		for i, delay := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 80 * time.Millisecond} {
+			assert.GreaterOrEqual(server.times[i+1].Sub(server.times[i]), delay)
+		}
+	}
+}
+
+func Test_Retry_Max(t *testing.T) {
+	assert := assert.New(t)
+	server, c := newStatuses(t, &statuses{status: []int{503, 503, 503, 503}}, httpclient.WithRetry(2, time.Millisecond))
+
+	// The last response is returned after the maximum number of repeats
+	resp, err := do(c, server.URL, http.MethodGet, nil)
+	if assert.NoError(err) {
+		assert.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	assert.Len(server.times, 3)
+}
+
+func Test_Retry_NotTransient(t *testing.T) {
+	assert := assert.New(t)
+	server, c := newStatuses(t, &statuses{status: []int{500}}, httpclient.WithRetry(3, time.Millisecond))
+
+	resp, err := do(c, server.URL, http.MethodGet, nil)
+	if assert.NoError(err) {
+		assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+	}
+	assert.Len(server.times, 1)
+}
+
+func Test_Retry_RetryAfter(t *testing.T) {
+	assert := assert.New(t)
+
+	// The delay requested by the server replaces the backoff
+	server, c := newStatuses(t, &statuses{status: []int{503}, retryAfter: "0"}, httpclient.WithRetry(1, time.Minute))
+	start := time.Now()
+	resp, err := do(c, server.URL, http.MethodGet, nil)
+	if assert.NoError(err) {
+		assert.Equal(http.StatusOK, resp.StatusCode)
+	}
+	assert.Less(time.Since(start), 10*time.Second)
+	assert.Len(server.times, 2)
+
+	// A delay in seconds is waited for
+	server, c = newStatuses(t, &statuses{status: []int{503}, retryAfter: "1"}, httpclient.WithRetry(1, time.Millisecond))
+	resp, err = do(c, server.URL, http.MethodGet, nil)
+	if assert.NoError(err) {
+		assert.Equal(http.StatusOK, resp.StatusCode)
+	}
+	if assert.Len(server.times, 2) {
+		assert.GreaterOrEqual(server.times[1].Sub(server.times[0]), time.Second)
+	}
+}
+
+func Test_Retry_Rewind(t *testing.T) {
+	assert := assert.New(t)
+	server, c := newStatuses(t, &statuses{status: []int{503}}, httpclient.WithRetry(1, time.Millisecond))
+
+	// A body which cannot be read again by the transport is kept, and sent
+	// again with the repeated request
+	resp, err := do(c, server.URL, http.MethodPost, io.NopCloser(strings.NewReader(`{"name":"test"}`)))
+	if assert.NoError(err) {
+		assert.Equal(http.StatusOK, resp.StatusCode)
+	}
+	assert.Equal([]string{`{"name":"test"}`, `{"name":"test"}`}, server.bodies)
+}
+
+func Test_Retry_LargeBody(t *testing.T) {
+	assert := assert.New(t)
+	server, c := newStatuses(t, &statuses{status: []int{503}}, httpclient.WithRetry(1, time.Millisecond))
+
+	// A body which is too large to keep is sent in full, and not repeated
+	body := bytes.Repeat([]byte("x"), 2<<20)
+	resp, err := do(c, server.URL, http.MethodPut, io.NopCloser(bytes.NewReader(body)))
+	if assert.NoError(err) {
+		assert.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	if assert.Len(server.bodies, 1) {
+		assert.Equal(len(body), len(server.bodies[0]))
+	}
+}
+
+func Test_Retry_NotIdempotent(t *testing.T) {
+	assert := assert.New(t)
+	server, c := newStatuses(t, &statuses{status: []int{502}}, httpclient.WithRetry(3, time.Millisecond))
+
+	// A POST is only repeated on a 503, as a 502 may have been processed
+	resp, err := do(c, server.URL, http.MethodPost, strings.NewReader(`{}`))
+	if assert.NoError(err) {
+		assert.Equal(http.StatusBadGateway, resp.StatusCode)
+	}
+	assert.Len(server.times, 1)
+}
+
+func Test_Retry_Cancel(t *testing.T) {
+	assert := assert.New(t)
+	server, c := newStatuses(t, &statuses{status: []int{503}}, httpclient.WithRetry(1, time.Minute))
+
+	// The delay is cancelled with the request
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	_, err = c.Client.Client.Do(req)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CIRCUIT BREAKER TESTS
+
+func Test_CircuitBreaker(t *testing.T) {
+	assert := assert.New(t)
+	server, c := newStatuses(t, &statuses{status: []int{503, 503, 503}}, httpclient.WithCircuitBreaker(2, 100*time.Millisecond))
+
+	// The circuit opens after two consecutive failures
+	for range 2 {
+		resp, err := do(c, server.URL, http.MethodGet, nil)
+		if assert.NoError(err) {
+			assert.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+		}
+	}
+	_, err := do(c, server.URL, http.MethodGet, nil)
+	assert.ErrorIs(err, pg.ErrNotAvailable)
+	assert.Len(server.times, 2)
+
+	// After the cooldown, a single request is sent, which fails and opens
+	// the circuit again
+	time.Sleep(150 * time.Millisecond)
+	resp, err := do(c, server.URL, http.MethodGet, nil)
+	if assert.NoError(err) {
+		assert.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	_, err = do(c, server.URL, http.MethodGet, nil)
+	assert.ErrorIs(err, pg.ErrNotAvailable)
+	assert.Len(server.times, 3)
+
+	// After the cooldown, a request which succeeds closes the circuit
+	time.Sleep(150 * time.Millisecond)
+	for range 3 {
+		resp, err := do(c, server.URL, http.MethodGet, nil)
+		if assert.NoError(err) {
+			assert.Equal(http.StatusOK, resp.StatusCode)
+		}
+	}
+	assert.Len(server.times, 6)
+}
+
+func Test_CircuitBreaker_HalfOpen(t *testing.T) {
+	assert := assert.New(t)
+
+	// A server which fails twice, then blocks until released
+	release := make(chan struct{})
+	var count int
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		count++
+		n := count
+		mu.Unlock()
+		if n <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		<-release
+	}))
+	defer server.Close()
+	c, err := httpclient.New(server.URL, httpclient.WithCircuitBreaker(2, 50*time.Millisecond))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Open the circuit, and wait for the cooldown
+	for range 2 {
+		_, _ = do(c, server.URL, http.MethodGet, nil)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// Send the probe, which blocks
+	probe := make(chan error)
+	go func() {
+		_, err := do(c, server.URL, http.MethodGet, nil)
+		probe <- err
+	}()
+	assert.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return count == 3
+	}, time.Second, 10*time.Millisecond)
+
+	// Other requests fail without being sent while the probe is in progress
+	_, err = do(c, server.URL, http.MethodGet, nil)
+	assert.ErrorIs(err, pg.ErrNotAvailable)
+
+	// The probe succeeds, which closes the circuit
+	close(release)
+	assert.NoError(<-probe)
+	_, err = do(c, server.URL, http.MethodGet, nil)
+	assert.NoError(err)
+	mu.Lock()
+	assert.Equal(4, count)
+	mu.Unlock()
+}
+
+func Test_CircuitBreaker_Retry(t *testing.T) {
+	assert := assert.New(t)
+
+	// With the circuit breaker given after the retry, a request counts once
+	// however many times it is repeated
+	server, c := newStatuses(t, &statuses{status: []int{503, 503, 503, 503}}, httpclient.WithRetry(1, time.Millisecond), httpclient.WithCircuitBreaker(2, time.Minute))
+	resp, err := do(c, server.URL, http.MethodGet, nil)
+	if assert.NoError(err) {
+		assert.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	_, err = do(c, server.URL, http.MethodGet, nil)
+	assert.NoError(err)
+	_, err = do(c, server.URL, http.MethodGet, nil)
+	assert.ErrorIs(err, pg.ErrNotAvailable)
+	assert.Len(server.times, 4)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return a server which responds with the statuses, and a client for it
+func newStatuses(t *testing.T, s *statuses, opts ...client.ClientOpt) (*statuses, *httpclient.Client) {
+	t.Helper()
+	s.Server = httptest.NewServer(s)
+	t.Cleanup(s.Close)
+	c, err := httpclient.New(s.URL, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, c
+}
+
+// Send a request with the transport of the client, and return the response
+// with the body read
+func do(c *httpclient.Client, url, method string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(context.TODO(), method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Client.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(io.Discard, resp.Body)
+	return resp, err
+}
+
+func (s *statuses) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.Lock()
+	defer s.Unlock()
+	s.times = append(s.times, time.Now())
+	s.bodies = append(s.bodies, string(body))
+	if len(s.status) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	status := s.status[0]
+	s.status = s.status[1:]
+	if s.retryAfter != "" {
+		w.Header().Set("Retry-After", s.retryAfter)
+	}
+	w.WriteHeader(status)
+}
@@ -0,0 +1,60 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	// Packages
+	client "github.com/mutablelogic/go-client"
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// GetVersion returns the version of the server, and the versions of the API
+// which it serves. Returns ErrNotFound when the server predates the version
+// endpoint.
+func (c *Client) GetVersion(ctx context.Context) (*schema.Version, error) {
+	// The request is sent with the HTTP client rather than DoWithContext, so
+	// that the status of the response can be checked
+	endpoint, err := url.JoinPath(c.endpoint, "version")
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", client.ContentTypeJson)
+	req.Header.Set(schema.APIVersionHeader, fmt.Sprint(schema.APIVersion))
+	if c.token != nil {
+		req.Header.Set("Authorization", c.token.String())
+	}
+
+	// Perform request
+	resp, err := c.Client.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, pg.ErrNotFound.With("server does not report its version")
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s: %s", resp.Status, data)
+	}
+
+	// Return the responses
+	var response schema.Version
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,66 @@
+package httpclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Client_GetVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Version", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal("/api/version", r.URL.Path)
+			assert.Equal("Bearer token", r.Header.Get("Authorization"))
+			assert.NotEmpty(r.Header.Get(schema.APIVersionHeader))
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(schema.Version{Name: "pgmanager", Version: "v1.2.3"})
+		}))
+		defer server.Close()
+
+		client, err := httpclient.New(server.URL+"/api", httpclient.WithToken("token"))
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		version, err := client.GetVersion(context.TODO())
+		if assert.NoError(err) {
+			assert.Equal("v1.2.3", version.Version)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		client, err := httpclient.New(server.URL)
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		_, err = client.GetVersion(context.TODO())
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		client, err := httpclient.New(server.URL)
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		_, err = client.GetVersion(context.TODO())
+		assert.Error(err)
+		assert.NotErrorIs(err, pg.ErrNotFound)
+	})
+}
@@ -0,0 +1,156 @@
+package httphandler
+
+import (
+	"net/http"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterAlertRuleHandlers registers HTTP handlers for alert rule CRUD
+// operations, and for listing the rules which are firing, on the provided
+// router with the given path prefix. The manager must be non-nil.
+func RegisterAlertRuleHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	// List or create rules
+	router.HandleFunc(joinPath(prefix, "alertrule"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = alertRuleList(w, r, manager)
+		case http.MethodPost:
+			_ = alertRuleCreate(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// List the rules which are firing
+	router.HandleFunc(joinPath(prefix, "alerts"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = alertList(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Get, update or delete a rule
+	router.HandleFunc(joinPath(prefix, "alertrule/{name}"), func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if name == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid alert rule name"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = alertRuleGet(w, r, manager, name)
+		case http.MethodPatch:
+			_ = alertRuleUpdate(w, r, manager, name)
+		case http.MethodDelete:
+			_ = alertRuleDelete(w, r, manager, name)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func alertRuleList(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.AlertRuleListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+
+	// List the rules
+	response, err := manager.ListAlertRules(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func alertList(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.AlertRuleListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+
+	// List the firing rules
+	response, err := manager.ListAlerts(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func alertRuleCreate(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.AlertRuleMeta
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Create the rule
+	response, err := manager.CreateAlertRule(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusCreated, httprequest.Indent(r), response)
+}
+
+func alertRuleGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
+	response, err := manager.GetAlertRule(r.Context(), name)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func alertRuleUpdate(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
+	// Parse request
+	var req schema.AlertRuleMeta
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Update the rule
+	response, err := manager.UpdateAlertRule(r.Context(), name, req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func alertRuleDelete(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
+	_, err := manager.DeleteAlertRule(r.Context(), name)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.Empty(w, http.StatusOK)
+}
@@ -0,0 +1,104 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Content type for the audit log as newline-delimited JSON
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterAuditHandlers registers HTTP handlers for the audit log on the
+// provided router with the given path prefix. The manager must be non-nil.
+func RegisterAuditHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	// List the audit log, or export it as newline-delimited JSON
+	router.HandleFunc(joinPath(prefix, "audit"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = auditList(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func auditList(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.AuditListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Export every operation which matches when newline-delimited JSON is accepted
+	if accept, _ := types.AcceptContentType(r); accept == contentTypeNDJSON {
+		return auditExport(w, r, manager, req)
+	}
+
+	// List the audit log
+	response, err := manager.ListAudit(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+// Write the operations which match the request as newline-delimited JSON, a
+// page at a time, ignoring the limit. Operations recorded after the export
+// starts are not included, so that pages do not overlap.
+func auditExport(w http.ResponseWriter, r *http.Request, manager *manager.Manager, req schema.AuditListRequest) error {
+	if req.Until == nil || req.Until.IsZero() {
+		req.Until = types.TimePtr(time.Now())
+	}
+	req.Limit = types.Uint64Ptr(schema.AuditListLimit)
+
+	// Get the first page, so that an error can be returned as a response
+	list, err := manager.ListAudit(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Write the operations, flushing after each page
+	w.Header().Set(types.ContentTypeHeader, contentTypeNDJSON)
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	for {
+		for _, audit := range list.Body {
+			if err := encoder.Encode(audit); err != nil {
+				return err
+			}
+		}
+		_ = http.NewResponseController(w).Flush()
+
+		// Get the next page
+		if req.Offset += uint64(len(list.Body)); len(list.Body) == 0 || req.Offset >= list.Count {
+			return nil
+		} else if list, err = manager.ListAudit(r.Context(), req); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package httphandler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Scope is the access granted to the credentials of a request
+type Scope uint
+
+// Authenticator returns the scope of the credentials for a request, or
+// ScopeNone when the request does not have valid credentials
+type Authenticator interface {
+	Authenticate(*http.Request) Scope
+}
+
+// Credentials is an Authenticator for bearer tokens and basic credentials,
+// each of which is granted a scope
+type Credentials struct {
+	tokens []credential
+	users  []credential
+}
+
+// credential is a token, or a user and password, with a scope
+type credential struct {
+	user, secret string
+	scope        Scope
+}
+
+// authenticated is a router which refuses requests without credentials
+// which have the scope required for the request
+type authenticated struct {
+	Router
+	auth  Authenticator
+	allow []string
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	ScopeNone  Scope = iota // No access
+	ScopeRead               // Requests which do not modify the server
+	ScopeAdmin              // All requests
+)
+
+const (
+	authRealm = "pgmanager"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// NewCredentials returns an empty set of credentials, which authenticates
+// no requests until tokens or users are added
+func NewCredentials() *Credentials {
+	return new(Credentials)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeNone:
+		return "none"
+	case ScopeRead:
+		return "read"
+	case ScopeAdmin:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// AddToken adds a bearer token with a scope
+func (c *Credentials) AddToken(token string, scope Scope) error {
+	if token == "" {
+		return pg.ErrBadParameter.With("token is empty")
+	} else if scope != ScopeRead && scope != ScopeAdmin {
+		return pg.ErrBadParameter.Withf("invalid scope %d", scope)
+	}
+	c.tokens = append(c.tokens, credential{secret: token, scope: scope})
+	return nil
+}
+
+// AddUser adds a user and password for basic authentication with a scope
+func (c *Credentials) AddUser(user, password string, scope Scope) error {
+	if user == "" || password == "" {
+		return pg.ErrBadParameter.With("user or password is empty")
+	} else if scope != ScopeRead && scope != ScopeAdmin {
+		return pg.ErrBadParameter.Withf("invalid scope %d", scope)
+	}
+	c.users = append(c.users, credential{user: user, secret: password, scope: scope})
+	return nil
+}
+
+// Authenticate returns the scope of the bearer token or basic credentials
+// of a request, or ScopeNone when they do not match
+func (c *Credentials) Authenticate(req *http.Request) Scope {
+	if user, password, ok := req.BasicAuth(); ok {
+		return match(c.users, user, password)
+	}
+	if scheme, token, ok := strings.Cut(req.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return match(c.tokens, "", strings.TrimSpace(token))
+	}
+	return ScopeNone
+}
+
+// HandleFunc registers a handler which responds to OPTIONS requests, to
+// requests with read scope which are GET, HEAD or for paths which are
+// read-only, and to other requests with admin scope
+func (a *authenticated) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	allow := slices.Contains(a.allow, pattern)
+	a.Router.HandleFunc(pattern, func(w http.ResponseWriter, req *http.Request) {
+		// Preflight requests do not have credentials
+		if req.Method == http.MethodOptions {
+			handler(w, req)
+			return
+		}
+
+		// Determine the scope required
+		required := ScopeAdmin
+		if req.Method == http.MethodGet || req.Method == http.MethodHead || allow {
+			required = ScopeRead
+		}
+
+		// Check the credentials
+		switch scope := a.auth.Authenticate(req); {
+		case scope == ScopeNone:
+			w.Header().Add("WWW-Authenticate", `Bearer realm="`+authRealm+`"`)
+			w.Header().Add("WWW-Authenticate", `Basic realm="`+authRealm+`"`)
+			_ = problem(w, httpresponse.ErrNotAuthorized.With("credentials are required"))
+		case scope < required:
+			_ = problem(w, httpresponse.ErrForbidden.With("credentials do not have "+required.String()+" scope"))
+		default:
+			// Record the user in the audit log
+			if user, _, ok := req.BasicAuth(); ok {
+				req = req.WithContext(pg.ContextWithUser(req.Context(), user))
+			}
+			handler(w, req)
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the scope of the credential which matches, comparing every
+// credential in constant time
+func match(credentials []credential, user, secret string) Scope {
+	scope := ScopeNone
+	for _, c := range credentials {
+		if subtle.ConstantTimeCompare([]byte(c.user), []byte(user))&subtle.ConstantTimeCompare([]byte(c.secret), []byte(secret)) == 1 {
+			scope = max(scope, c.scope)
+		}
+	}
+	return scope
+}
@@ -0,0 +1,103 @@
+package httphandler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// Packages
+	httprequest "github.com/mutablelogic/go-pg/pkg/manager/httphandler"
+	test "github.com/mutablelogic/go-pg/pkg/test"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Credentials(t *testing.T) {
+	assert := assert.New(t)
+
+	auth := httprequest.NewCredentials()
+	assert.NoError(auth.AddToken("admin-token", httprequest.ScopeAdmin))
+	assert.NoError(auth.AddToken("read-token", httprequest.ScopeRead))
+	assert.NoError(auth.AddUser("alice", "secret", httprequest.ScopeRead))
+	assert.Error(auth.AddToken("", httprequest.ScopeRead))
+	assert.Error(auth.AddToken("none", httprequest.ScopeNone))
+	assert.Error(auth.AddUser("bob", "", httprequest.ScopeAdmin))
+
+	tests := []struct {
+		Header string
+		User   string
+		Scope  httprequest.Scope
+	}{
+		{"", "", httprequest.ScopeNone},
+		{"Bearer admin-token", "", httprequest.ScopeAdmin},
+		{"bearer read-token", "", httprequest.ScopeRead},
+		{"Bearer other-token", "", httprequest.ScopeNone},
+		{"Token admin-token", "", httprequest.ScopeNone},
+		{"", "alice:secret", httprequest.ScopeRead},
+		{"", "alice:other", httprequest.ScopeNone},
+		{"", "admin-token:", httprequest.ScopeNone},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.Header != "" {
+			req.Header.Set("Authorization", test.Header)
+		}
+		if test.User != "" {
+			user, password, _ := bytes.Cut([]byte(test.User), []byte(":"))
+			req.SetBasicAuth(string(user), string(password))
+		}
+		assert.Equal(test.Scope, auth.Authenticate(req), test)
+	}
+}
+
+func Test_RegisterHandlers_Auth(t *testing.T) {
+	assert := assert.New(t)
+
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
+
+	auth := httprequest.NewCredentials()
+	assert.NoError(auth.AddToken("admin-token", httprequest.ScopeAdmin))
+	assert.NoError(auth.AddToken("read-token", httprequest.ScopeRead))
+
+	router := http.NewServeMux()
+	httprequest.RegisterHandlers(router, "/api", manager.Manager, httprequest.Options{
+		Auth: auth,
+	})
+
+	request := func(method, path, body, token string) int {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("NoCredentials", func(t *testing.T) {
+		assert.Equal(http.StatusUnauthorized, request(http.MethodGet, "/api/role", "", ""))
+		assert.Equal(http.StatusUnauthorized, request(http.MethodGet, "/api/version", "", ""))
+		assert.Equal(http.StatusUnauthorized, request(http.MethodGet, "/api/role", "", "other-token"))
+	})
+
+	t.Run("ReadScope", func(t *testing.T) {
+		assert.Equal(http.StatusOK, request(http.MethodGet, "/api/role", "", "read-token"))
+		assert.Equal(http.StatusForbidden, request(http.MethodPost, "/api/explain", `{"query":"SELECT 1","analyze":true}`, "read-token"))
+		assert.Equal(http.StatusForbidden, request(http.MethodPost, "/api/query", `{"query":"SELECT 1"}`, "read-token"))
+		assert.Equal(http.StatusForbidden, request(http.MethodPost, "/api/role", `{"name":"auth_role"}`, "read-token"))
+	})
+
+	t.Run("AdminScope", func(t *testing.T) {
+		t.Cleanup(func() {
+			request(http.MethodDelete, "/api/role/auth_role", "", "admin-token")
+		})
+		assert.Equal(http.StatusCreated, request(http.MethodPost, "/api/role", `{"name":"auth_role"}`, "admin-token"))
+		assert.Equal(http.StatusOK, request(http.MethodPost, "/api/query", `{"query":"SELECT 1"}`, "admin-token"))
+		assert.Equal(http.StatusOK, request(http.MethodPost, "/api/explain", `{"query":"SELECT 1","analyze":true}`, "admin-token"))
+	})
+}
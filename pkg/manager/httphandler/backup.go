@@ -0,0 +1,229 @@
+package httphandler
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// attachmentWriter writes the response headers for an attachment on the
+// first write, so that an error can still be returned before any data
+// has been written
+type attachmentWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	written     bool
+}
+
+var _ io.Writer = (*attachmentWriter)(nil)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterBackupHandlers registers HTTP handlers for base backup and database
+// dump operations on the provided router with the given path prefix. The
+// manager must be non-nil.
+func RegisterBackupHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	// Get, start or stop a base backup
+	router.HandleFunc(joinPath(prefix, "backup"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = backupGet(w, r, manager)
+		case http.MethodPost:
+			_ = backupStart(w, r, manager)
+		case http.MethodDelete:
+			_ = backupStop(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Dump a database
+	router.HandleFunc(joinPath(prefix, "database/{name}/dump"), func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if name == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+		if strings.HasPrefix(name, "pg_") {
+			_ = problem(w, httpresponse.ErrBadRequest.With("database name cannot start with reserved prefix 'pg_'"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = databaseDump(w, r, manager, name)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Restore a database
+	router.HandleFunc(joinPath(prefix, "database/{name}/restore"), func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if name == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+		if strings.HasPrefix(name, "pg_") {
+			_ = problem(w, httpresponse.ErrBadRequest.With("database name cannot start with reserved prefix 'pg_'"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPost:
+			_ = databaseRestore(w, r, manager, name)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func backupGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	backup, err := manager.GetBackup(r.Context())
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), backup)
+}
+
+func backupStart(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.BackupMeta
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Refuse to start a backup outside of the maintenance windows
+	if err := maintenanceWindow(r, manager); err != nil {
+		return problem(w, err)
+	}
+
+	// Start the backup
+	backup, err := manager.StartBackup(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusCreated, httprequest.Indent(r), backup)
+}
+
+func backupStop(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse the query
+	var req schema.BackupStopRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Stop the backup
+	backup, err := manager.StopBackup(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), backup)
+}
+
+func databaseDump(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
+	// Parse the query
+	var req schema.DatabaseDumpRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Stream the dump as an attachment
+	writer := &attachmentWriter{w: w, contentType: req.ContentType(), filename: req.Filename(name)}
+	if err := manager.DumpDatabase(r.Context(), name, req, writer); err != nil {
+		if !writer.written {
+			return problem(w, err)
+		}
+		return err
+	}
+
+	// Write the headers if the dump was empty
+	if !writer.written {
+		writer.writeHeader()
+	}
+
+	// Return success
+	return nil
+}
+
+func databaseRestore(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
+	// Parse the query
+	var req schema.DatabaseRestoreRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	} else if err := req.Validate(); err != nil {
+		return problem(w, err)
+	}
+
+	// Without a text stream, return the result when the restore completes
+	if accept, _ := types.AcceptContentType(r); accept != types.ContentTypeTextStream {
+		result, err := manager.RestoreDatabase(r.Context(), name, req, r.Body, nil)
+		if err != nil {
+			return problem(w, err)
+		}
+		return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), result)
+	}
+
+	// The dump is read from the request body while progress is written to
+	// the response
+	if err := http.NewResponseController(w).EnableFullDuplex(); err != nil {
+		return problem(w, httpresponse.ErrInternalError.With(err.Error()))
+	}
+
+	// Report progress as a text stream, followed by the result or error
+	stream := httpresponse.NewTextStream(w)
+	result, err := manager.RestoreDatabase(r.Context(), name, req, r.Body, func(progress schema.DatabaseRestoreProgress) {
+		stream.Write(schema.RestoreEventProgress, progress)
+	})
+	if err != nil {
+		stream.Write(schema.RestoreEventError, httperr(err).Error())
+	} else {
+		stream.Write(schema.RestoreEventResult, result)
+	}
+	return stream.Close()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS - ATTACHMENT WRITER
+
+func (a *attachmentWriter) writeHeader() {
+	a.w.Header().Set(types.ContentTypeHeader, a.contentType)
+	a.w.Header().Set(types.ContentDispositonHeader, mime.FormatMediaType("attachment", map[string]string{
+		"filename": a.filename,
+	}))
+	a.w.WriteHeader(http.StatusOK)
+	a.written = true
+}
+
+func (a *attachmentWriter) Write(data []byte) (int, error) {
+	if !a.written {
+		a.writeHeader()
+	}
+	return a.w.Write(data)
+}
@@ -0,0 +1,71 @@
+package httphandler
+
+import (
+	"net/http"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterBloatHandlers registers HTTP handlers for the bloat report
+// on the provided router with the given path prefix. The manager must be non-nil.
+func RegisterBloatHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	// Estimate bloat across all databases
+	router.HandleFunc(joinPath(prefix, "bloat"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = bloatReport(w, r, manager, nil)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Estimate bloat in a specific database
+	router.HandleFunc(joinPath(prefix, "bloat/{database}"), func(w http.ResponseWriter, r *http.Request) {
+		database := r.PathValue("database")
+		if database == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = bloatReport(w, r, manager, &database)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func bloatReport(w http.ResponseWriter, r *http.Request, manager *manager.Manager, database *string) error {
+	// Parse request
+	var req schema.BloatListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+	if database != nil {
+		req.Database = database
+	}
+
+	// Get the report
+	response, err := manager.GetBloatReport(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
@@ -0,0 +1,52 @@
+package httphandler
+
+import (
+	"net/http"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterCommentHandlers registers HTTP handlers for setting the comment on
+// a database, role, schema or object on the provided router with the given
+// path prefix. The manager must be non-nil.
+func RegisterCommentHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	// Set a comment
+	router.HandleFunc(joinPath(prefix, "comment"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			_ = commentSet(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func commentSet(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.CommentTarget
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Set the comment
+	if err := manager.SetComment(r.Context(), req); err != nil {
+		return problem(w, err)
+	}
+
+	// Return success (no content)
+	return httpresponse.Empty(w, http.StatusNoContent)
+}
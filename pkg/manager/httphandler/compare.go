@@ -0,0 +1,105 @@
+package httphandler
+
+import (
+	"net/http"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterCompareHandlers registers HTTP handlers for the profile of the
+// server, for comparing it with the profile of another server, and for
+// comparing the schemas of databases, on the provided router with the given
+// path prefix. The manager must be non-nil.
+func RegisterCompareHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	// Get the profile
+	router.HandleFunc(joinPath(prefix, "profile"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = profileGet(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Compare with the profile of another server
+	router.HandleFunc(joinPath(prefix, "compare"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			_ = compareProfile(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Compare the schemas of two databases
+	router.HandleFunc(joinPath(prefix, "diff"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = diffSchemas(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func profileGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	response, err := manager.GetProfile(r.Context())
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func compareProfile(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.Profile
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Compare the profiles
+	response, err := manager.CompareProfile(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func diffSchemas(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse query
+	var req struct {
+		Database string `json:"database" help:"Database which is changed to match the other database"`
+		Other    string `json:"other" help:"Other database"`
+		schema.SchemaDiffRequest
+	}
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Compare the schemas
+	response, err := manager.DiffSchemas(r.Context(), req.Database, req.Other, req.SchemaDiffRequest)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
@@ -0,0 +1,135 @@
+package httphandler
+
+import (
+	"net/http"
+	"strconv"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterConfigHandlers registers HTTP handlers for the contents of the
+// configuration files, and for adding and removing pg_hba.conf rules, on the
+// provided router with the given path prefix. The manager must be non-nil.
+func RegisterConfigHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	// List settings in the configuration files
+	router.HandleFunc(joinPath(prefix, "config/files"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = configFileList(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// List or add rules in pg_hba.conf
+	router.HandleFunc(joinPath(prefix, "config/hba"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = hbaRuleList(w, r, manager)
+		case http.MethodPost:
+			_ = hbaRuleAdd(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Remove a rule from pg_hba.conf
+	router.HandleFunc(joinPath(prefix, "config/hba/{line}"), func(w http.ResponseWriter, r *http.Request) {
+		line, err := strconv.ParseUint(r.PathValue("line"), 10, 64)
+		if err != nil || line == 0 {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid line"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodDelete:
+			_ = hbaRuleRemove(w, r, manager, line)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func configFileList(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.ConfigFileSettingListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+
+	// List the settings
+	response, err := manager.ListConfigFiles(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func hbaRuleList(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.HBARuleListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+
+	// List the rules
+	response, err := manager.ListHBARules(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func hbaRuleAdd(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse query for the line to insert before
+	var opts struct {
+		Before uint64 `json:"before,omitempty" help:"Insert before this line, or append when zero"`
+	}
+	if err := httprequest.Query(r.URL.Query(), &opts); err != nil {
+		return problem(w, err)
+	}
+
+	// Parse request body
+	var req schema.HBARuleMeta
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Add the rule
+	response, err := manager.AddHBARule(r.Context(), req, opts.Before)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusCreated, httprequest.Indent(r), response)
+}
+
+func hbaRuleRemove(w http.ResponseWriter, r *http.Request, manager *manager.Manager, line uint64) error {
+	// Remove the rule
+	response, err := manager.RemoveHBARule(r.Context(), line)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
@@ -16,7 +16,7 @@ import (
 
 // RegisterConnectionHandlers registers HTTP handlers for connection operations
 // on the provided router with the given path prefix. The manager must be non-nil.
-func RegisterConnectionHandlers(router *http.ServeMux, prefix string, manager *manager.Manager) {
+func RegisterConnectionHandlers(router Router, prefix string, manager *manager.Manager) {
 	if manager == nil {
 		panic("manager is nil")
 	}
@@ -25,14 +25,34 @@ func RegisterConnectionHandlers(router *http.ServeMux, prefix string, manager *m
 		case http.MethodGet:
 			_ = connectionList(w, r, manager)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Stream changes to the connections
+	router.HandleFunc(joinPath(prefix, "connection/watch"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = connectionWatch(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// List long running queries, or connections idle in a transaction
+	router.HandleFunc(joinPath(prefix, "connection/long-running"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = connectionLongRunning(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
 	router.HandleFunc(joinPath(prefix, "connection/{pid}"), func(w http.ResponseWriter, r *http.Request) {
 		pid, err := strconv.ParseUint(r.PathValue("pid"), 10, 64)
 		if err != nil {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid pid"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid pid"))
 			return
 		}
 
@@ -42,7 +62,7 @@ func RegisterConnectionHandlers(router *http.ServeMux, prefix string, manager *m
 		case http.MethodDelete:
 			_ = connectionDelete(w, r, manager, pid)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 }
@@ -54,23 +74,70 @@ func connectionList(w http.ResponseWriter, r *http.Request, manager *manager.Man
 	// Parse request
 	var req schema.ConnectionListRequest
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// List the connections
 	response, err := manager.ListConnections(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func connectionLongRunning(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.LongRunningListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+	threshold, err := req.Duration()
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// List the connections
+	var response *schema.LongRunningQueryList
+	if req.IdleInTransaction {
+		response, err = manager.ListIdleInTransaction(r.Context(), threshold)
+	} else {
+		response, err = manager.ListLongRunningQueries(r.Context(), threshold)
+	}
+	if err != nil {
+		return problem(w, err)
 	}
 
 	// Return success
 	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
 }
 
+// Stream the connections which are opened, start a query, change state or
+// are closed as a text stream, until the client disconnects
+func connectionWatch(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.ConnectionWatchRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	} else if _, err := req.Duration(); err != nil {
+		return problem(w, err)
+	}
+
+	// Write each change as an event, followed by an error if polling fails
+	stream := httpresponse.NewTextStream(w)
+	if err := manager.WatchConnections(r.Context(), req, func(event schema.ConnectionEvent) {
+		stream.Write(event.Type, event.Connection)
+	}); err != nil {
+		stream.Write(schema.ConnectionEventError, httperr(err).Error())
+	}
+	return stream.Close()
+}
+
 func connectionGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager, pid uint64) error {
 	connection, err := manager.GetConnection(r.Context(), pid)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -80,7 +147,7 @@ func connectionGet(w http.ResponseWriter, r *http.Request, manager *manager.Mana
 func connectionDelete(w http.ResponseWriter, r *http.Request, manager *manager.Manager, pid uint64) error {
 	_, err := manager.DeleteConnection(r.Context(), pid)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
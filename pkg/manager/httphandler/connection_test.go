@@ -20,11 +20,8 @@ import (
 func Test_Connection_RegisterHandlers(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	t.Run("PanicOnNilManager", func(t *testing.T) {
 		router := http.NewServeMux()
@@ -44,11 +41,8 @@ func Test_Connection_RegisterHandlers(t *testing.T) {
 func Test_Connection_List(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterConnectionHandlers(router, "/api", manager.Manager)
@@ -131,11 +125,8 @@ func Test_Connection_List(t *testing.T) {
 func Test_Connection_Get(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterConnectionHandlers(router, "/api", manager.Manager)
@@ -207,11 +198,8 @@ func Test_Connection_Get(t *testing.T) {
 func Test_Connection_Delete(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterConnectionHandlers(router, "/api", manager.Manager)
@@ -0,0 +1,150 @@
+package httphandler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// CORS determines which origins can make cross-origin requests, such as a
+// frontend which is served from another origin
+type CORS struct {
+	// Origins which can make requests, such as "https://admin.example.com",
+	// or "*" for any origin. When empty, cross-origin requests are not allowed
+	Origins []string
+
+	// Methods which can be used in cross-origin requests. When empty, all
+	// methods which the handlers respond to can be used
+	Methods []string
+}
+
+// headers is a router which sets the security headers on every response,
+// and the CORS headers on responses to allowed origins. It responds to
+// preflight requests without calling the handler
+type headers struct {
+	Router
+	cors     CORS
+	security map[string]string
+	hsts     string
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Time a browser can cache the response to a preflight request
+	corsMaxAge = 10 * time.Minute
+
+	// Header which requires browsers to use HTTPS, which is only set on
+	// responses to requests over TLS
+	hstsHeader = "Strict-Transport-Security"
+	hstsValue  = "max-age=63072000"
+)
+
+var (
+	// Methods which can be used in cross-origin requests when none are set
+	corsMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPatch, http.MethodDelete}
+
+	// Request headers which can be sent in cross-origin requests
+	corsAllowHeaders = []string{"Accept", "Authorization", "Content-Type", schema.APIVersionHeader}
+
+	// Response headers which can be read by cross-origin requests
+	corsExposeHeaders = []string{schema.APIVersionHeader, "Deprecation", "Sunset", "Link", "Retry-After", "Content-Disposition"}
+
+	// Security headers which are set on every response, unless they are
+	// replaced or removed with Options.Headers
+	securityHeaders = map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"X-Frame-Options":        "DENY",
+		"Referrer-Policy":        "no-referrer",
+		"Cache-Control":          "no-store",
+	}
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newHeaders(router Router, opts Options) *headers {
+	security := make(map[string]string, len(securityHeaders)+len(opts.Headers))
+	for key, value := range securityHeaders {
+		security[key] = value
+	}
+	for key, value := range opts.Headers {
+		security[http.CanonicalHeaderKey(key)] = value
+	}
+	hsts, exists := security[hstsHeader]
+	if !exists {
+		hsts = hstsValue
+	}
+	delete(security, hstsHeader)
+	cors := opts.CORS
+	if len(cors.Methods) == 0 {
+		cors.Methods = corsMethods
+	}
+	return &headers{router, cors, security, hsts}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// WithCORS returns the options with the origins which can make cross-origin
+// requests, and the methods they can use
+func (opts Options) WithCORS(origins, methods []string) Options {
+	opts.CORS = CORS{Origins: origins, Methods: methods}
+	return opts
+}
+
+// Allow returns true if an origin can make cross-origin requests
+func (cors CORS) Allow(origin string) bool {
+	return origin != "" && (slices.Contains(cors.Origins, "*") || slices.Contains(cors.Origins, origin))
+}
+
+// HandleFunc registers a handler which sets the security and CORS headers,
+// and responds to preflight requests
+func (h *headers) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	h.Router.HandleFunc(pattern, func(w http.ResponseWriter, req *http.Request) {
+		header := w.Header()
+		for key, value := range h.security {
+			if value != "" {
+				header.Set(key, value)
+			}
+		}
+		if req.TLS != nil && h.hsts != "" {
+			header.Set(hstsHeader, h.hsts)
+		}
+
+		// Responses depend on the origin when cross-origin requests are allowed
+		origin := req.Header.Get("Origin")
+		if len(h.cors.Origins) > 0 {
+			header.Add("Vary", "Origin")
+		}
+		if !h.cors.Allow(origin) {
+			handler(w, req)
+			return
+		}
+		header.Set("Access-Control-Allow-Origin", origin)
+
+		// Respond to preflight requests
+		if method := req.Header.Get("Access-Control-Request-Method"); req.Method == http.MethodOptions && method != "" {
+			if slices.Contains(h.cors.Methods, method) {
+				header.Set("Access-Control-Allow-Methods", strings.Join(h.cors.Methods, ", "))
+				header.Set("Access-Control-Allow-Headers", strings.Join(corsAllowHeaders, ", "))
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(corsMaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// Allow the response headers to be read
+		header.Set("Access-Control-Expose-Headers", strings.Join(corsExposeHeaders, ", "))
+		handler(w, req)
+	})
+}
@@ -0,0 +1,101 @@
+package httphandler_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	httphandler "github.com/mutablelogic/go-pg/pkg/manager/httphandler"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_CORS_Allow(t *testing.T) {
+	assert := assert.New(t)
+
+	cors := httphandler.CORS{Origins: []string{"https://admin.example.com"}}
+	assert.True(cors.Allow("https://admin.example.com"))
+	assert.False(cors.Allow("https://other.example.com"))
+	assert.False(cors.Allow(""))
+
+	wildcard := httphandler.CORS{Origins: []string{"*"}}
+	assert.True(wildcard.Allow("https://other.example.com"))
+	assert.False(wildcard.Allow(""))
+
+	assert.False(httphandler.CORS{}.Allow("https://admin.example.com"))
+}
+
+func Test_RegisterHandlers_Headers(t *testing.T) {
+	assert := assert.New(t)
+
+	// The version handler does not use the manager
+	router := http.NewServeMux()
+	httphandler.RegisterHandlers(router, "/api/v1", new(manager.Manager), httphandler.Options{
+		Resources: []httphandler.Resource{httphandler.ResourceOpenAPI},
+		Headers:   map[string]string{"x-frame-options": ""},
+	}.WithCORS([]string{"https://admin.example.com"}, []string{http.MethodGet}))
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("SecurityHeaders", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodGet, "/api/v1/version", nil))
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.Empty(w.Header().Get("X-Frame-Options"))
+		assert.Empty(w.Header().Get("Strict-Transport-Security"))
+		assert.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("StrictTransportSecurity", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/version", nil)
+		req.TLS = new(tls.ConnectionState)
+		w := serve(req)
+		assert.NotEmpty(w.Header().Get("Strict-Transport-Security"))
+	})
+
+	t.Run("AllowedOrigin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/version", nil)
+		req.Header.Set("Origin", "https://admin.example.com")
+		w := serve(req)
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("https://admin.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(w.Header().Get("Access-Control-Expose-Headers"), "Api-Version")
+		assert.Equal("Origin", w.Header().Get("Vary"))
+	})
+
+	t.Run("OtherOrigin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/version", nil)
+		req.Header.Set("Origin", "https://other.example.com")
+		w := serve(req)
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("Preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/version", nil)
+		req.Header.Set("Origin", "https://admin.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		w := serve(req)
+		assert.Equal(http.StatusNoContent, w.Code)
+		assert.Equal("https://admin.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(http.MethodGet, w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Contains(w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+	})
+
+	t.Run("PreflightMethodNotAllowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/version", nil)
+		req.Header.Set("Origin", "https://admin.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+		w := serve(req)
+		assert.Equal(http.StatusNoContent, w.Code)
+		assert.Empty(w.Header().Get("Access-Control-Allow-Methods"))
+	})
+}
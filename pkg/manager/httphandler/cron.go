@@ -0,0 +1,155 @@
+package httphandler
+
+import (
+	"net/http"
+	"strconv"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterCronHandlers registers HTTP handlers for pg_cron job operations
+// on the provided router with the given path prefix. The manager must be non-nil.
+func RegisterCronHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	// List or create jobs
+	router.HandleFunc(joinPath(prefix, "cronjob"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = cronJobList(w, r, manager)
+		case http.MethodPost:
+			_ = cronJobCreate(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Get or delete a job
+	router.HandleFunc(joinPath(prefix, "cronjob/{id}"), func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+		if err != nil {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid job id"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = cronJobGet(w, r, manager, id)
+		case http.MethodDelete:
+			_ = cronJobDelete(w, r, manager, id)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// List job runs
+	router.HandleFunc(joinPath(prefix, "cronrun"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = cronJobRunList(w, r, manager, nil)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// List runs for a job
+	router.HandleFunc(joinPath(prefix, "cronjob/{id}/run"), func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+		if err != nil {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid job id"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = cronJobRunList(w, r, manager, &id)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func cronJobList(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.CronJobListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+
+	// List the jobs
+	response, err := manager.ListCronJobs(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func cronJobCreate(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.CronJobMeta
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Create the job
+	response, err := manager.CreateCronJob(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusCreated, httprequest.Indent(r), response)
+}
+
+func cronJobGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager, id uint64) error {
+	response, err := manager.GetCronJob(r.Context(), id)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func cronJobDelete(w http.ResponseWriter, r *http.Request, manager *manager.Manager, id uint64) error {
+	_, err := manager.DeleteCronJob(r.Context(), id)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.Empty(w, http.StatusOK)
+}
+
+func cronJobRunList(w http.ResponseWriter, r *http.Request, manager *manager.Manager, id *uint64) error {
+	// Parse request
+	var req schema.CronJobRunListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	} else if id != nil {
+		req.Job = id
+	}
+
+	// List the runs
+	response, err := manager.ListCronJobRuns(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
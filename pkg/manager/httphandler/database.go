@@ -16,7 +16,7 @@ import (
 
 // RegisterDatabaseHandlers registers HTTP handlers for database CRUD operations
 // on the provided router with the given path prefix. The manager must be non-nil.
-func RegisterDatabaseHandlers(router *http.ServeMux, prefix string, manager *manager.Manager) {
+func RegisterDatabaseHandlers(router Router, prefix string, manager *manager.Manager) {
 	if manager == nil {
 		panic("manager is nil")
 	}
@@ -27,18 +27,18 @@ func RegisterDatabaseHandlers(router *http.ServeMux, prefix string, manager *man
 		case http.MethodPost:
 			_ = databaseCreate(w, r, manager)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
 	router.HandleFunc(joinPath(prefix, "database/{name}"), func(w http.ResponseWriter, r *http.Request) {
 		name := r.PathValue("name")
 		if name == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
 			return
 		}
 		if strings.HasPrefix(name, "pg_") {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("database name cannot start with reserved prefix 'pg_'"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("database name cannot start with reserved prefix 'pg_'"))
 			return
 		}
 
@@ -50,7 +50,26 @@ func RegisterDatabaseHandlers(router *http.ServeMux, prefix string, manager *man
 		case http.MethodDelete:
 			_ = databaseDelete(w, r, manager, name)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	router.HandleFunc(joinPath(prefix, "database/{name}/clone"), func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if name == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+		if strings.HasPrefix(name, "pg_") {
+			_ = problem(w, httpresponse.ErrBadRequest.With("database name cannot start with reserved prefix 'pg_'"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPost:
+			_ = databaseClone(w, r, manager, name)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 }
@@ -61,7 +80,7 @@ func RegisterDatabaseHandlers(router *http.ServeMux, prefix string, manager *man
 func databaseGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
 	database, err := manager.GetDatabase(r.Context(), name)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -72,13 +91,13 @@ func databaseList(w http.ResponseWriter, r *http.Request, manager *manager.Manag
 	// Parse request
 	var req schema.DatabaseListRequest
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// List the databases
 	response, err := manager.ListDatabases(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -89,13 +108,13 @@ func databaseCreate(w http.ResponseWriter, r *http.Request, manager *manager.Man
 	// Parse request
 	var req schema.DatabaseMeta
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Create the database
 	response, err := manager.CreateDatabase(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -108,13 +127,13 @@ func databaseDelete(w http.ResponseWriter, r *http.Request, manager *manager.Man
 		Force bool `json:"force,omitempty" help:"Force delete"`
 	}
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Delete the database
 	_, err := manager.DeleteDatabase(r.Context(), name, req.Force)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -125,15 +144,35 @@ func databaseUpdate(w http.ResponseWriter, r *http.Request, manager *manager.Man
 	// Parse request
 	var req schema.DatabaseMeta
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Perform update
 	database, err := manager.UpdateDatabase(r.Context(), name, req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
 	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), database)
 }
+
+func databaseClone(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
+	// Parse request
+	var req struct {
+		Name string `json:"name"`
+		schema.DatabaseCloneRequest
+	}
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Clone the database
+	response, err := manager.CloneDatabase(r.Context(), name, req.Name, req.DatabaseCloneRequest)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusCreated, httprequest.Indent(r), response)
+}
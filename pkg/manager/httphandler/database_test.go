@@ -20,11 +20,8 @@ import (
 func Test_Database_RegisterHandlers(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	t.Run("PanicOnNilManager", func(t *testing.T) {
 		router := http.NewServeMux()
@@ -44,11 +41,8 @@ func Test_Database_RegisterHandlers(t *testing.T) {
 func Test_Database_List(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterDatabaseHandlers(router, "/api", manager.Manager)
@@ -81,11 +75,8 @@ func Test_Database_List(t *testing.T) {
 func Test_Database_Get(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterDatabaseHandlers(router, "/api", manager.Manager)
@@ -136,11 +127,8 @@ func Test_Database_Get(t *testing.T) {
 func Test_Database_Create(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterDatabaseHandlers(router, "/api", manager.Manager)
@@ -215,8 +203,14 @@ func Test_Database_Create(t *testing.T) {
 		w2 := httptest.NewRecorder()
 
 		router.ServeHTTP(w2, req2)
-		// Duplicate database returns 500 (PostgreSQL error)
-		assert.Equal(http.StatusInternalServerError, w2.Code)
+		// Duplicate database returns 409 with the PostgreSQL error
+		assert.Equal(http.StatusConflict, w2.Code)
+		assert.Equal(schema.ProblemContentType, w2.Header().Get("Content-Type"))
+		var problem schema.Problem
+		assert.NoError(json.Unmarshal(w2.Body.Bytes(), &problem))
+		assert.Equal(http.StatusConflict, problem.Status)
+		assert.Equal("duplicate_database", problem.Code)
+		assert.Equal("42P04", problem.SQLState)
 
 		// Cleanup
 		t.Cleanup(func() {
@@ -228,11 +222,8 @@ func Test_Database_Create(t *testing.T) {
 func Test_Database_Delete(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterDatabaseHandlers(router, "/api", manager.Manager)
@@ -292,15 +283,70 @@ func Test_Database_Delete(t *testing.T) {
 	})
 }
 
-func Test_Database_Update(t *testing.T) {
+func Test_Database_Clone(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
+
+	router := http.NewServeMux()
+	httprequest.RegisterDatabaseHandlers(router, "/api", manager.Manager)
+
+	t.Run("CloneExisting", func(t *testing.T) {
+		// First create a database
+		body := `{"name": "test_http_clone_source"}`
+		createReq := httptest.NewRequest(http.MethodPost, "/api/database", bytes.NewBufferString(body))
+		createReq.Header.Set("Content-Type", "application/json")
+		createW := httptest.NewRecorder()
+		router.ServeHTTP(createW, createReq)
+		assert.Equal(http.StatusCreated, createW.Code)
+
+		// Clone the database
+		req := httptest.NewRequest(http.MethodPost, "/api/database/test_http_clone_source/clone", bytes.NewBufferString(`{"name": "test_http_clone_target", "force": true}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusCreated, w.Code)
+		var resp schema.Database
+		assert.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal("test_http_clone_target", resp.Name)
+
+		// Delete the databases
+		for _, name := range []string{"test_http_clone_target", "test_http_clone_source"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/database/"+name+"?force=true", nil))
+			assert.Equal(http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("CloneNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/database/nonexistent_db_xyz/clone", bytes.NewBufferString(`{"name": "test_http_clone_missing"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusNotFound, w.Code)
 	})
 
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/database/postgres/clone", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func Test_Database_Update(t *testing.T) {
+	assert := assert.New(t)
+
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
+
 	router := http.NewServeMux()
 	httprequest.RegisterDatabaseHandlers(router, "/api", manager.Manager)
 
@@ -396,11 +442,8 @@ func Test_Database_Update(t *testing.T) {
 func Test_httperr(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterDatabaseHandlers(router, "/api", manager.Manager)
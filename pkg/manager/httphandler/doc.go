@@ -6,6 +6,6 @@
 //	httphandler.RegisterBackendHandlers(mux, "/api/v1", mgr)
 //
 // This registers endpoints for roles, databases, schemas, objects, tablespaces,
-// extensions, connections, settings, statements, replication slots, and
-// Prometheus metrics.
+// extensions, connections, settings, statements, replication slots, pg_cron jobs,
+// backups, dumps, restores and Prometheus metrics.
 package httphandler
@@ -15,7 +15,7 @@ import (
 
 // RegisterExtensionHandlers registers HTTP handlers for extension CRUD operations
 // on the provided router with the given path prefix. The manager must be non-nil.
-func RegisterExtensionHandlers(router *http.ServeMux, prefix string, manager *manager.Manager) {
+func RegisterExtensionHandlers(router Router, prefix string, manager *manager.Manager) {
 	if manager == nil {
 		panic("manager is nil")
 	}
@@ -26,14 +26,14 @@ func RegisterExtensionHandlers(router *http.ServeMux, prefix string, manager *ma
 		case http.MethodPost:
 			_ = extensionCreate(w, r, manager)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
 	router.HandleFunc(joinPath(prefix, "extension/{name}"), func(w http.ResponseWriter, r *http.Request) {
 		name := r.PathValue("name")
 		if name == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid extension name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid extension name"))
 			return
 		}
 
@@ -45,7 +45,7 @@ func RegisterExtensionHandlers(router *http.ServeMux, prefix string, manager *ma
 		case http.MethodDelete:
 			_ = extensionDelete(w, r, manager, name)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 }
@@ -57,13 +57,13 @@ func extensionList(w http.ResponseWriter, r *http.Request, manager *manager.Mana
 	// Parse request
 	var req schema.ExtensionListRequest
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// List the extensions
 	response, err := manager.ListExtensions(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -74,7 +74,7 @@ func extensionCreate(w http.ResponseWriter, r *http.Request, manager *manager.Ma
 	// Parse request
 	var req schema.ExtensionMeta
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Parse cascade from query params
@@ -83,7 +83,7 @@ func extensionCreate(w http.ResponseWriter, r *http.Request, manager *manager.Ma
 	// Create the extension
 	response, err := manager.CreateExtension(r.Context(), req, cascade)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -94,7 +94,7 @@ func extensionGet(w http.ResponseWriter, r *http.Request, manager *manager.Manag
 	// Get the extension
 	response, err := manager.GetExtension(r.Context(), name)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -108,13 +108,13 @@ func extensionDelete(w http.ResponseWriter, r *http.Request, manager *manager.Ma
 		Cascade  bool   `json:"cascade,omitempty" help:"Cascade delete to dependent objects"`
 	}
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Delete the extension
 	err := manager.DeleteExtension(r.Context(), req.Database, name, req.Cascade)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -125,13 +125,13 @@ func extensionUpdate(w http.ResponseWriter, r *http.Request, manager *manager.Ma
 	// Parse request
 	var req schema.ExtensionMeta
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Update the extension
 	response, err := manager.UpdateExtension(r.Context(), name, req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
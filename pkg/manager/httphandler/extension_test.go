@@ -20,11 +20,8 @@ import (
 func Test_Extension_RegisterHandlers(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	t.Run("PanicOnNilManager", func(t *testing.T) {
 		router := http.NewServeMux()
@@ -44,11 +41,8 @@ func Test_Extension_RegisterHandlers(t *testing.T) {
 func Test_Extension_List(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterExtensionHandlers(router, "/api", manager.Manager)
@@ -146,11 +140,8 @@ func Test_Extension_List(t *testing.T) {
 func Test_Extension_Get(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterExtensionHandlers(router, "/api", manager.Manager)
@@ -183,11 +174,8 @@ func Test_Extension_Get(t *testing.T) {
 func Test_Extension_Create(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterExtensionHandlers(router, "/api", manager.Manager)
@@ -244,11 +232,8 @@ func Test_Extension_Create(t *testing.T) {
 func Test_Extension_Update(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterExtensionHandlers(router, "/api", manager.Manager)
@@ -269,11 +254,8 @@ func Test_Extension_Update(t *testing.T) {
 func Test_Extension_Delete(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterExtensionHandlers(router, "/api", manager.Manager)
@@ -13,6 +13,6 @@ import (
 func RegisterFrontendHandler(router *http.ServeMux, prefix string, enabled bool) {
 	// Catch all handler returns a "not found" error
 	router.HandleFunc(joinPath(prefix, "/"), func(w http.ResponseWriter, r *http.Request) {
-		_ = httpresponse.Error(w, httpresponse.ErrNotFound, r.URL.String())
+		_ = problem(w, httpresponse.ErrNotFound.With(r.URL.String()))
 	})
 }
@@ -22,7 +22,7 @@ func RegisterFrontendHandler(router *http.ServeMux, prefix string, enabled bool)
 	if !enabled {
 		// Fallback handler returns a "not found" error
 		router.HandleFunc(joinPath(prefix, "/"), func(w http.ResponseWriter, r *http.Request) {
-			_ = httpresponse.Error(w, httpresponse.ErrNotFound, r.URL.String())
+			_ = problem(w, httpresponse.ErrNotFound.With(r.URL.String()))
 		})
 		return
 	}
@@ -3,29 +3,255 @@ package httphandler
 import (
 	"errors"
 	"net/http"
+	"slices"
+	"time"
 
 	// Packages
-	pg "github.com/mutablelogic/go-pg"
 	manager "github.com/mutablelogic/go-pg/pkg/manager"
 	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
 	types "github.com/mutablelogic/go-server/pkg/types"
 )
 
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Router is the interface for registering handlers, which is satisfied
+// by http.ServeMux
+type Router interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// Resource is a group of handlers which can be registered together
+type Resource string
+
+// Options determine which handlers are registered by RegisterHandlers
+type Options struct {
+	// Resources to register. When empty, all resources are registered
+	// except ResourceRecovery, which is only registered when it is listed
+	// and Auth is set
+	Resources []Resource
+
+	// Resources which are not registered, even if they are listed in Resources
+	Disable []Resource
+
+	// When true, requests which create, update or delete resources, or start
+	// and stop backups, are refused with 405 Method Not Allowed
+	ReadOnly bool
+
+	// Versions of the API which are served. When empty, only the latest
+	// version is served
+	Versions []uint
+
+	// Versions or paths which are deprecated
+	Deprecations []Deprecation
+
+	// When set, requests must have credentials with read scope for requests
+	// which do not modify the server, and admin scope for other requests
+	Auth Authenticator
+
+	// Requests per second allowed from each client address, and the requests
+	// which a client can make at once. When zero, requests are not limited
+	RateLimit float64
+	RateBurst uint
+
+	// Time after which a request is cancelled, so that a slow query does not
+	// hold a connection from the pool. When zero, requests are not cancelled
+	Timeout time.Duration
+
+	// Timeouts for paths relative to the prefix, such as "setting/history",
+	// which replace Timeout. Paths which stream responses have no timeout
+	// unless one is set here
+	Timeouts map[string]time.Duration
+
+	// Origins which can make cross-origin requests, such as a frontend
+	// served from another origin, and the methods they can use
+	CORS CORS
+
+	// Headers which replace the security headers set on every response, or
+	// remove them when the value is empty
+	Headers map[string]string
+}
+
+// readonly is a router which refuses requests which are not read-only
+type readonly struct {
+	Router
+	allow []string
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	ResourceAlertRule       Resource = "alertrule"
+	ResourceAudit           Resource = "audit"
+	ResourceBackup          Resource = "backup"
+	ResourceBloat           Resource = "bloat"
+	ResourceComment         Resource = "comment"
+	ResourceCompare         Resource = "compare"
+	ResourceConfig          Resource = "config"
+	ResourceConnection      Resource = "connection"
+	ResourceCron            Resource = "cron"
+	ResourceDatabase        Resource = "database"
+	ResourceExplain         Resource = "explain"
+	ResourceExtension       Resource = "extension"
+	ResourceIOStat          Resource = "iostat"
+	ResourceMaintenance     Resource = "maintenance"
+	ResourceMetrics         Resource = "metrics"
+	ResourceNotify          Resource = "notify"
+	ResourceObject          Resource = "object"
+	ResourceOpenAPI         Resource = "openapi"
+	ResourceQuery           Resource = "query"
+	ResourceRecovery        Resource = "recovery"
+	ResourceReplication     Resource = "replication"
+	ResourceReplicationSlot Resource = "replicationslot"
+	ResourceRole            Resource = "role"
+	ResourceSchema          Resource = "schema"
+	ResourceServer          Resource = "server"
+	ResourceSetting         Resource = "setting"
+	ResourceStaleTable      Resource = "staletable"
+	ResourceStatement       Resource = "statement"
+	ResourceStatistics      Resource = "statistics"
+	ResourceTablespace      Resource = "tablespace"
+	ResourceTempUsage       Resource = "tempusage"
+)
+
+var (
+	// Functions to register the handlers for each resource
+	resources = []struct {
+		Resource
+		register func(Router, string, *manager.Manager)
+	}{
+		{ResourceAlertRule, RegisterAlertRuleHandlers},
+		{ResourceAudit, RegisterAuditHandlers},
+		{ResourceBackup, RegisterBackupHandlers},
+		{ResourceBloat, RegisterBloatHandlers},
+		{ResourceComment, RegisterCommentHandlers},
+		{ResourceCompare, RegisterCompareHandlers},
+		{ResourceConfig, RegisterConfigHandlers},
+		{ResourceConnection, RegisterConnectionHandlers},
+		{ResourceCron, RegisterCronHandlers},
+		{ResourceDatabase, RegisterDatabaseHandlers},
+		{ResourceExplain, RegisterExplainHandlers},
+		{ResourceExtension, RegisterExtensionHandlers},
+		{ResourceIOStat, RegisterIOStatHandlers},
+		{ResourceMaintenance, RegisterMaintenanceHandlers},
+		{ResourceMetrics, RegisterMetricsHandler},
+		{ResourceNotify, RegisterNotifyHandlers},
+		{ResourceObject, RegisterObjectHandlers},
+		{ResourceQuery, RegisterQueryHandlers},
+		{ResourceRecovery, RegisterRecoveryHandlers},
+		{ResourceReplication, RegisterReplicationHandlers},
+		{ResourceReplicationSlot, RegisterReplicationSlotHandlers},
+		{ResourceRole, RegisterRoleHandlers},
+		{ResourceSchema, RegisterSchemaHandlers},
+		{ResourceServer, RegisterServerHandlers},
+		{ResourceSetting, RegisterSettingHandlers},
+		{ResourceStaleTable, RegisterStaleTableHandlers},
+		{ResourceStatement, RegisterStatementHandlers},
+		{ResourceStatistics, RegisterStatisticsHandlers},
+		{ResourceTablespace, RegisterTablespaceHandlers},
+		{ResourceTempUsage, RegisterTempUsageHandlers},
+	}
+
+	// Resources which are only registered when they are listed, and when
+	// credentials are required, so that promotion needs admin scope
+	explicitResources = []Resource{ResourceRecovery}
+
+	// Paths which accept POST requests, but do not modify the server. Ad-hoc
+	// queries are not included, as a read-only transaction can still call
+	// functions such as pg_terminate_backend and pg_read_file, and neither
+	// are explained queries, which are run when they are analyzed
+	readonlyPaths = []string{"compare", "object/{database}/{schema}/{name}/verify"}
+)
+
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
-func RegisterBackendHandlers(router *http.ServeMux, prefix string, manager *manager.Manager) {
-	RegisterConnectionHandlers(router, prefix, manager)
-	RegisterDatabaseHandlers(router, prefix, manager)
-	RegisterExtensionHandlers(router, prefix, manager)
-	RegisterMetricsHandler(router, prefix, manager)
-	RegisterObjectHandlers(router, prefix, manager)
-	RegisterReplicationSlotHandlers(router, prefix, manager)
-	RegisterRoleHandlers(router, prefix, manager)
-	RegisterSchemaHandlers(router, prefix, manager)
-	RegisterSettingHandlers(router, prefix, manager)
-	RegisterStatementHandlers(router, prefix, manager)
-	RegisterTablespaceHandlers(router, prefix, manager)
+// RegisterBackendHandlers registers the handlers for all resources on the
+// provided router with the given path prefix. The manager must be non-nil.
+func RegisterBackendHandlers(router Router, prefix string, manager *manager.Manager) {
+	RegisterHandlers(router, prefix, manager, Options{})
+}
+
+// RegisterHandlers registers the handlers for the resources selected by the
+// options on the provided router with the given path prefix, so that whole
+// groups of capabilities can be disabled. The manager must be non-nil.
+func RegisterHandlers(router Router, prefix string, manager *manager.Manager, opts Options) {
+	// Set the security and CORS headers on every response, including
+	// responses which refuse a request
+	router = newHeaders(router, opts)
+
+	// Refuse requests from clients which make too many, before any other
+	// work is done for them
+	if opts.RateLimit > 0 {
+		router = &limited{router, newRateLimiter(opts.RateLimit, opts.RateBurst)}
+	}
+
+	// Refuse requests without credentials
+	if opts.Auth != nil {
+		router = &authenticated{router, opts.Auth, readonlyAllow(prefix)}
+	}
+
+	// Record the paths which are registered, for the OpenAPI document
+	routes := &routes{Router: router, prefix: prefix}
+	router = routes
+
+	// Report the versions of the API, for any requested version
+	registerVersionHandler(router, prefix, opts)
+
+	// Negotiate the version of the API for each request
+	router = newVersioned(router, prefix, opts)
+
+	// Refuse requests which modify the server
+	if opts.ReadOnly {
+		router = &readonly{router, readonlyAllow(prefix)}
+	}
+
+	// Cancel requests which run for too long
+	router = newTimeout(router, prefix, opts)
+
+	// Register the selected resources
+	for _, resource := range resources {
+		if opts.Enabled(resource.Resource) {
+			resource.register(router, prefix, manager)
+		}
+	}
+
+	// Describe the registered resources
+	if opts.Enabled(ResourceOpenAPI) {
+		registerOpenAPIHandlers(router, prefix, routes.paths, opts.Auth != nil)
+	}
+}
+
+// Enabled returns true if the handlers for a resource are registered. The
+// handlers which promote a standby are only registered when they are listed
+// and credentials are required
+func (opts Options) Enabled(resource Resource) bool {
+	if slices.Contains(opts.Disable, resource) {
+		return false
+	}
+	if slices.Contains(explicitResources, resource) {
+		return opts.Auth != nil && slices.Contains(opts.Resources, resource)
+	}
+	return len(opts.Resources) == 0 || slices.Contains(opts.Resources, resource)
+}
+
+// HandleFunc registers a handler which responds to GET, HEAD and OPTIONS
+// requests, and to other requests only for paths which are read-only
+func (r *readonly) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	allow := slices.Contains(r.allow, pattern)
+	r.Router.HandleFunc(pattern, func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			handler(w, req)
+		default:
+			if allow {
+				handler(w, req)
+			} else {
+				_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(req.Method))
+			}
+		}
+	})
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -35,9 +261,18 @@ func joinPath(prefix, path string) string {
 	return types.JoinPath(prefix, path)
 }
 
-// httperr converts pg errors to appropriate HTTP errors.
-// Returns the original error if it's already an httpresponse.Err,
-// otherwise maps pg errors to their HTTP equivalents.
+// Return the paths which accept POST requests, but do not modify the server
+func readonlyAllow(prefix string) []string {
+	allow := make([]string, 0, len(readonlyPaths))
+	for _, path := range readonlyPaths {
+		allow = append(allow, joinPath(prefix, path))
+	}
+	return allow
+}
+
+// httperr converts pg errors and PostgreSQL errors to appropriate HTTP
+// errors, for responses which are not written with problem, such as events
+// in a stream. Returns the original error if it's already an httpresponse.Err
 func httperr(err error) error {
 	if err == nil {
 		return nil
@@ -49,17 +284,7 @@ func httperr(err error) error {
 		return err
 	}
 
-	// Map pg errors to HTTP errors
-	switch {
-	case errors.Is(err, pg.ErrNotFound):
-		return httpresponse.ErrNotFound.With(err.Error())
-	case errors.Is(err, pg.ErrBadParameter):
-		return httpresponse.ErrBadRequest.With(err.Error())
-	case errors.Is(err, pg.ErrNotImplemented):
-		return httpresponse.ErrNotImplemented.With(err.Error())
-	case errors.Is(err, pg.ErrNotAvailable):
-		return httpresponse.ErrNotImplemented.With(err.Error())
-	default:
-		return httpresponse.ErrInternalError.With(err.Error())
-	}
+	// Map the error to a HTTP error
+	p := newProblem(err)
+	return httpresponse.Err(p.Status).With(p.Detail)
 }
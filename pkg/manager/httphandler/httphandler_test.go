@@ -0,0 +1,90 @@
+package httphandler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// Packages
+	httprequest "github.com/mutablelogic/go-pg/pkg/manager/httphandler"
+	test "github.com/mutablelogic/go-pg/pkg/test"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Options_Enabled(t *testing.T) {
+	assert := assert.New(t)
+
+	// All resources are enabled by default
+	assert.True(httprequest.Options{}.Enabled(httprequest.ResourceRole))
+
+	// Only listed resources are enabled
+	opts := httprequest.Options{Resources: []httprequest.Resource{httprequest.ResourceRole}}
+	assert.True(opts.Enabled(httprequest.ResourceRole))
+	assert.False(opts.Enabled(httprequest.ResourceDatabase))
+
+	// Disabled resources are not enabled, even if listed
+	opts.Disable = []httprequest.Resource{httprequest.ResourceRole}
+	assert.False(opts.Enabled(httprequest.ResourceRole))
+
+	// Recovery is only enabled when listed and authentication is configured
+	assert.False(httprequest.Options{}.Enabled(httprequest.ResourceRecovery))
+	opts = httprequest.Options{Resources: []httprequest.Resource{httprequest.ResourceRecovery}}
+	assert.False(opts.Enabled(httprequest.ResourceRecovery))
+	opts.Auth = httprequest.NewCredentials()
+	assert.True(opts.Enabled(httprequest.ResourceRecovery))
+}
+
+func Test_RegisterHandlers(t *testing.T) {
+	assert := assert.New(t)
+
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
+
+	router := http.NewServeMux()
+	httprequest.RegisterHandlers(router, "/api", manager.Manager, httprequest.Options{
+		Disable:  []httprequest.Resource{httprequest.ResourceDatabase},
+		ReadOnly: true,
+	})
+
+	t.Run("ListRoles", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/role", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusOK, w.Code)
+	})
+
+	t.Run("CreateRoleReadOnly", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/role", bytes.NewReader([]byte(`{"name":"readonly_role"}`)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("ExplainReadOnly", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/explain", bytes.NewReader([]byte(`{"query":"SELECT 1","analyze":true}`)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("QueryReadOnly", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/query", bytes.NewReader([]byte(`{"query":"SELECT 1"}`)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("DatabaseDisabled", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/database", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusNotFound, w.Code)
+	})
+}
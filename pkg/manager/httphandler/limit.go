@@ -0,0 +1,173 @@
+package httphandler
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	// Packages
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// limited is a router which refuses requests from a client which exceed the
+// rate of requests allowed for each client
+type limited struct {
+	Router
+	*rateLimiter
+}
+
+// rateLimiter is a token bucket for each client, which is refilled at a rate
+// of tokens per second up to the burst
+type rateLimiter struct {
+	sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// bucket is the tokens remaining for a client, and when they were counted
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// timeout is a router which cancels the context of a request when it has run
+// for longer than the timeout for its path
+type timeout struct {
+	Router
+	prefix   string
+	timeout  time.Duration
+	timeouts map[string]time.Duration
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Interval between removing the buckets of clients which have stopped
+	// making requests
+	rateSweepInterval = time.Minute
+)
+
+var (
+	// Paths which stream responses, and have no timeout unless one is set
+	// for the path
+	streamingPaths = []string{"connection/watch", "notify/{channel}", "database/{name}/dump", "database/{name}/restore"}
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newRateLimiter(rate float64, burst uint) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(max(burst, 1)),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func newTimeout(router Router, prefix string, opts Options) *timeout {
+	timeouts := make(map[string]time.Duration, len(streamingPaths)+len(opts.Timeouts))
+	for _, path := range streamingPaths {
+		timeouts[path] = 0
+	}
+	for path, duration := range opts.Timeouts {
+		timeouts[strings.Trim(path, "/")] = duration
+	}
+	return &timeout{router, prefix, opts.Timeout, timeouts}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// HandleFunc registers a handler which responds with 429 Too Many Requests
+// when the client has made more requests than its rate allows
+func (l *limited) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	l.Router.HandleFunc(pattern, func(w http.ResponseWriter, req *http.Request) {
+		if wait := l.allow(clientKey(req), time.Now()); wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			_ = problem(w, httpresponse.Err(http.StatusTooManyRequests).With("rate limit exceeded"))
+			return
+		}
+		handler(w, req)
+	})
+}
+
+// HandleFunc registers a handler whose context is cancelled when the timeout
+// for the path has passed, so that its queries are cancelled and its
+// connections are returned to the pool
+func (t *timeout) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	duration := t.timeout
+	if v, exists := t.timeouts[strings.TrimPrefix(strings.TrimPrefix(pattern, t.prefix), "/")]; exists {
+		duration = v
+	}
+	if duration <= 0 {
+		t.Router.HandleFunc(pattern, handler)
+		return
+	}
+	t.Router.HandleFunc(pattern, func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), duration)
+		defer cancel()
+		handler(w, req.WithContext(ctx))
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Take a token from the bucket of a client, and return zero when it was
+// taken, or the time to wait until a token is available
+func (r *rateLimiter) allow(key string, now time.Time) time.Duration {
+	r.Lock()
+	defer r.Unlock()
+
+	// Remove the buckets which have refilled, which are the same as new ones
+	if now.Sub(r.swept) > rateSweepInterval {
+		for k, b := range r.buckets {
+			if r.refill(b, now) >= r.burst {
+				delete(r.buckets, k)
+			}
+		}
+		r.swept = now
+	}
+
+	// Refill the bucket for the client, and take a token
+	b, exists := r.buckets[key]
+	if !exists {
+		b = &bucket{tokens: r.burst, last: now}
+		r.buckets[key] = b
+	}
+	if tokens := r.refill(b, now); tokens < 1 {
+		return time.Duration((1 - tokens) / r.rate * float64(time.Second))
+	}
+	b.tokens--
+	return 0
+}
+
+// Add the tokens to a bucket which have accrued since it was last counted,
+// and return the tokens in the bucket
+func (r *rateLimiter) refill(b *bucket, now time.Time) float64 {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(r.burst, b.tokens+elapsed.Seconds()*r.rate)
+		b.last = now
+	}
+	return b.tokens
+}
+
+// Return the key which identifies the client of a request, which is its
+// address without the port
+func clientKey(req *http.Request) string {
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+	return req.RemoteAddr
+}
@@ -0,0 +1,89 @@
+package httphandler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	httphandler "github.com/mutablelogic/go-pg/pkg/manager/httphandler"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	test "github.com/mutablelogic/go-pg/pkg/test"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_RegisterHandlers_RateLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	// The version handler does not use the manager
+	router := http.NewServeMux()
+	httphandler.RegisterHandlers(router, "/api/v1", new(manager.Manager), httphandler.Options{
+		Resources: []httphandler.Resource{httphandler.ResourceOpenAPI},
+		RateLimit: 1,
+		RateBurst: 2,
+	})
+	get := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/version", nil)
+		req.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Burst", func(t *testing.T) {
+		assert.Equal(http.StatusOK, get("192.0.2.1:1000").Code)
+		assert.Equal(http.StatusOK, get("192.0.2.1:1001").Code)
+
+		// The port is not part of the client
+		w := get("192.0.2.1:1002")
+		assert.Equal(http.StatusTooManyRequests, w.Code)
+		assert.Equal("1", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("OtherClient", func(t *testing.T) {
+		assert.Equal(http.StatusOK, get("192.0.2.2:1000").Code)
+	})
+
+	t.Run("Refill", func(t *testing.T) {
+		time.Sleep(time.Second)
+		assert.Equal(http.StatusOK, get("192.0.2.1:1000").Code)
+	})
+}
+
+func Test_RegisterHandlers_Timeout(t *testing.T) {
+	assert := assert.New(t)
+
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
+
+	router := http.NewServeMux()
+	httphandler.RegisterHandlers(router, "/api/v1", manager.Manager, httphandler.Options{
+		Resources: []httphandler.Resource{httphandler.ResourceQuery, httphandler.ResourceServer},
+		Timeout:   time.Minute,
+		Timeouts:  map[string]time.Duration{"query": 100 * time.Millisecond},
+	})
+
+	t.Run("QueryTimeout", func(t *testing.T) {
+		body, err := json.Marshal(schema.QueryRequest{Query: "SELECT pg_sleep(5)"})
+		assert.NoError(err)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/query", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusGatewayTimeout, w.Code)
+	})
+
+	t.Run("DefaultTimeout", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/server", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusOK, w.Code)
+	})
+}
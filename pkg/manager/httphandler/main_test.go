@@ -0,0 +1,16 @@
+package httphandler_test
+
+import (
+	"os"
+	"testing"
+
+	// Packages
+	test "github.com/mutablelogic/go-pg/pkg/test"
+)
+
+// Run the tests, then remove the container shared by the tests
+func TestMain(m *testing.M) {
+	code := m.Run()
+	test.CloseSharedManager()
+	os.Exit(code)
+}
@@ -0,0 +1,83 @@
+package httphandler
+
+import (
+	"net/http"
+	"time"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterMaintenanceHandlers registers HTTP handlers for the maintenance
+// windows, and for queueing heavy operations until a window opens, on the
+// provided router with the given path prefix. The manager must be non-nil.
+func RegisterMaintenanceHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	// Get the schedule and tasks, or queue a task
+	router.HandleFunc(joinPath(prefix, "maintenance"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = maintenanceGet(w, r, manager)
+		case http.MethodPost:
+			_ = maintenanceQueue(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func maintenanceGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	response, err := manager.GetMaintenance(r.Context())
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func maintenanceQueue(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.MaintenanceTaskMeta
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Queue the task
+	response, err := manager.QueueMaintenance(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusAccepted, httprequest.Indent(r), response)
+}
+
+// Return an error for a heavy operation outside of the maintenance windows,
+// unless the override query parameter is set
+func maintenanceWindow(r *http.Request, manager *manager.Manager) error {
+	if r.URL.Query().Get("override") == "true" || manager.InMaintenanceWindow() {
+		return nil
+	}
+	maintenance, err := manager.GetMaintenance(r.Context())
+	if err != nil {
+		return httperr(err)
+	}
+	next := "no window is scheduled"
+	if maintenance.Next != nil && !maintenance.Next.IsZero() {
+		next = "the next window opens at " + maintenance.Next.Format(time.RFC3339)
+	}
+	return httpresponse.ErrConflict.Withf("outside of the maintenance windows, %s: queue the operation with /maintenance, or set override=true", next)
+}
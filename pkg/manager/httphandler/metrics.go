@@ -11,36 +11,60 @@ import (
 	manager "github.com/mutablelogic/go-pg/pkg/manager"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
 	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+	types "github.com/mutablelogic/go-server/pkg/types"
 	prometheus "github.com/prometheus/client_golang/prometheus"
 	promhttp "github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-///////////////////////////////////////////////////////////////////////////////
-// CONSTANTS
-
-const (
-	metricsTimeout = 30 * time.Second
-)
-
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
 type metrics struct {
+	sync.Mutex
 	manager             *manager.Manager
+	cache               []prometheus.Metric
+	cached              time.Time
+	scrapeDuration      *prometheus.Desc
 	connections         *prometheus.Desc
+	queryMaxAge         *prometheus.Desc
+	idleInTransaction   *prometheus.Desc
 	databaseSize        *prometheus.Desc
 	tablespaceSize      *prometheus.Desc
 	tableSize           *prometheus.Desc
 	indexSize           *prometheus.Desc
 	deadTupleRatio      *prometheus.Desc
+	tableBloat          *prometheus.Desc
+	indexBloat          *prometheus.Desc
+	tableCacheHitRatio  *prometheus.Desc
+	indexCacheHitRatio  *prometheus.Desc
+	tempBytes           *prometheus.Desc
+	statementSeconds    *prometheus.Desc
+	statementMean       *prometheus.Desc
+	statementStdDev     *prometheus.Desc
+	statementMin        *prometheus.Desc
+	statementMax        *prometheus.Desc
+	statementCalls      *prometheus.Desc
+	statementRows       *prometheus.Desc
 	replicationSlots    *prometheus.Desc
 	replicationLagBytes *prometheus.Desc
 	replicationLagMs    *prometheus.Desc
+	standbyLagBytes     *prometheus.Desc
+	standbyLagMs        *prometheus.Desc
+	receiverLagBytes    *prometheus.Desc
+	receiverLagMs       *prometheus.Desc
+	alertFiring         *prometheus.Desc
+	poolConns           *prometheus.Desc
+	poolMaxConns        *prometheus.Desc
+	poolAcquires        *prometheus.Desc
+	poolAcquireSeconds  *prometheus.Desc
+	poolWaitSeconds     *prometheus.Desc
+	poolConstructErrors *prometheus.Desc
+	poolFailovers       *prometheus.Desc
 }
 
 // RegisterMetricsHandler registers a HTTP handler for prometheus metrics
 // on the provided router with the given path prefix. The manager must be non-nil.
-func RegisterMetricsHandler(router *http.ServeMux, prefix string, manager *manager.Manager) {
+func RegisterMetricsHandler(router Router, prefix string, manager *manager.Manager) {
 	if manager == nil {
 		panic("manager is nil")
 	}
@@ -49,11 +73,26 @@ func RegisterMetricsHandler(router *http.ServeMux, prefix string, manager *manag
 	registry := prometheus.NewRegistry()
 	registry.MustRegister(&metrics{
 		manager: manager,
+		scrapeDuration: prometheus.NewDesc(
+			"pg_exporter_scrape_duration_seconds",
+			"Time taken to collect the metrics from the database by collector",
+			[]string{"collector"}, nil,
+		),
 		connections: prometheus.NewDesc(
 			"pg_connections",
 			"Number of connections to the database server",
 			[]string{"database", "state"}, nil,
 		),
+		queryMaxAge: prometheus.NewDesc(
+			"pg_query_max_age_seconds",
+			"Age of the longest running query",
+			nil, nil,
+		),
+		idleInTransaction: prometheus.NewDesc(
+			"pg_idle_in_transaction_connections",
+			"Number of connections which are idle in a transaction",
+			nil, nil,
+		),
 		databaseSize: prometheus.NewDesc(
 			"pg_database_size_bytes",
 			"Size of database in bytes",
@@ -79,6 +118,66 @@ func RegisterMetricsHandler(router *http.ServeMux, prefix string, manager *manag
 			"Ratio of dead tuples to total tuples (0.0-1.0)",
 			[]string{"database", "schema", "table"}, nil,
 		),
+		tableBloat: prometheus.NewDesc(
+			"pg_table_bloat_bytes",
+			"Estimated bytes of a table which are not used by live tuples",
+			[]string{"database", "schema", "table"}, nil,
+		),
+		indexBloat: prometheus.NewDesc(
+			"pg_index_bloat_bytes",
+			"Estimated bytes of a btree index which are not used by live tuples",
+			[]string{"database", "schema", "index"}, nil,
+		),
+		tableCacheHitRatio: prometheus.NewDesc(
+			"pg_table_cache_hit_ratio",
+			"Ratio of table heap blocks found in the buffer cache (0.0-1.0)",
+			[]string{"database", "schema", "table"}, nil,
+		),
+		indexCacheHitRatio: prometheus.NewDesc(
+			"pg_index_cache_hit_ratio",
+			"Ratio of index blocks found in the buffer cache (0.0-1.0)",
+			[]string{"database", "schema", "index"}, nil,
+		),
+		tempBytes: prometheus.NewDesc(
+			"pg_temp_bytes_total",
+			"Bytes of temporary files written by queries which exceeded work_mem",
+			[]string{"database"}, nil,
+		),
+		statementSeconds: prometheus.NewDesc(
+			"pg_statement_seconds",
+			"Time spent executing statements by role and database",
+			[]string{"database", "user"}, nil,
+		),
+		statementMean: prometheus.NewDesc(
+			"pg_statement_mean_seconds",
+			"Mean time spent executing a statement by role and database",
+			[]string{"database", "user"}, nil,
+		),
+		statementStdDev: prometheus.NewDesc(
+			"pg_statement_stddev_seconds",
+			"Standard deviation of the time spent executing a statement by role and database",
+			[]string{"database", "user"}, nil,
+		),
+		statementMin: prometheus.NewDesc(
+			"pg_statement_min_seconds",
+			"Minimum time spent executing a statement by role and database",
+			[]string{"database", "user"}, nil,
+		),
+		statementMax: prometheus.NewDesc(
+			"pg_statement_max_seconds",
+			"Maximum time spent executing a statement by role and database",
+			[]string{"database", "user"}, nil,
+		),
+		statementCalls: prometheus.NewDesc(
+			"pg_statement_calls_total",
+			"Number of times statements were executed by role and database",
+			[]string{"database", "user"}, nil,
+		),
+		statementRows: prometheus.NewDesc(
+			"pg_statement_rows_total",
+			"Number of rows retrieved or affected by statements by role and database",
+			[]string{"database", "user"}, nil,
+		),
 		replicationSlots: prometheus.NewDesc(
 			"pg_replication_slots",
 			"Number of replication slots by status",
@@ -94,6 +193,66 @@ func RegisterMetricsHandler(router *http.ServeMux, prefix string, manager *manag
 			"Replication lag in milliseconds",
 			[]string{"slot", "type"}, nil,
 		),
+		standbyLagBytes: prometheus.NewDesc(
+			"pg_standby_lag_bytes",
+			"Bytes of WAL which a standby has not replayed",
+			[]string{"standby", "client_addr"}, nil,
+		),
+		standbyLagMs: prometheus.NewDesc(
+			"pg_standby_lag_ms",
+			"Time for WAL to be written, flushed or replayed by a standby in milliseconds",
+			[]string{"standby", "client_addr", "type"}, nil,
+		),
+		receiverLagBytes: prometheus.NewDesc(
+			"pg_wal_receiver_lag_bytes",
+			"Bytes of WAL which this standby has received and not replayed",
+			nil, nil,
+		),
+		receiverLagMs: prometheus.NewDesc(
+			"pg_wal_receiver_lag_ms",
+			"Time since the last transaction replayed by this standby in milliseconds",
+			nil, nil,
+		),
+		alertFiring: prometheus.NewDesc(
+			"pg_alert_firing",
+			"Whether an enabled alert rule is firing (1) or not (0)",
+			[]string{"rule", "metric"}, nil,
+		),
+		poolConns: prometheus.NewDesc(
+			"pg_pool_connections",
+			"Number of connections in the pool by state",
+			[]string{"state"}, nil,
+		),
+		poolMaxConns: prometheus.NewDesc(
+			"pg_pool_max_connections",
+			"Maximum number of connections in the pool",
+			nil, nil,
+		),
+		poolAcquires: prometheus.NewDesc(
+			"pg_pool_acquires_total",
+			"Number of connections acquired from the pool by result",
+			[]string{"result"}, nil,
+		),
+		poolAcquireSeconds: prometheus.NewDesc(
+			"pg_pool_acquire_seconds_total",
+			"Total time spent acquiring connections from the pool",
+			nil, nil,
+		),
+		poolWaitSeconds: prometheus.NewDesc(
+			"pg_pool_acquire_wait_seconds_total",
+			"Total time spent waiting for a connection when the pool was empty",
+			nil, nil,
+		),
+		poolConstructErrors: prometheus.NewDesc(
+			"pg_pool_construct_errors_total",
+			"Number of connections which failed to be established",
+			nil, nil,
+		),
+		poolFailovers: prometheus.NewDesc(
+			"pg_pool_failovers_total",
+			"Number of times the connections were closed because the server no longer matched the target session attributes",
+			nil, nil,
+		),
 	})
 	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
 
@@ -103,7 +262,7 @@ func RegisterMetricsHandler(router *http.ServeMux, prefix string, manager *manag
 		case http.MethodGet:
 			handler.ServeHTTP(w, r)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 }
@@ -113,73 +272,156 @@ func RegisterMetricsHandler(router *http.ServeMux, prefix string, manager *manag
 
 // Describe sends metric descriptors to the channel
 func (m *metrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.scrapeDuration
 	ch <- m.connections
+	ch <- m.queryMaxAge
+	ch <- m.idleInTransaction
 	ch <- m.databaseSize
 	ch <- m.tablespaceSize
 	ch <- m.tableSize
 	ch <- m.indexSize
 	ch <- m.deadTupleRatio
+	ch <- m.tableBloat
+	ch <- m.indexBloat
+	ch <- m.tableCacheHitRatio
+	ch <- m.indexCacheHitRatio
+	ch <- m.tempBytes
+	ch <- m.statementSeconds
+	ch <- m.statementMean
+	ch <- m.statementStdDev
+	ch <- m.statementMin
+	ch <- m.statementMax
+	ch <- m.statementCalls
+	ch <- m.statementRows
 	ch <- m.replicationSlots
 	ch <- m.replicationLagBytes
 	ch <- m.replicationLagMs
+	ch <- m.standbyLagBytes
+	ch <- m.standbyLagMs
+	ch <- m.receiverLagBytes
+	ch <- m.receiverLagMs
+	ch <- m.alertFiring
+	ch <- m.poolConns
+	ch <- m.poolMaxConns
+	ch <- m.poolAcquires
+	ch <- m.poolAcquireSeconds
+	ch <- m.poolWaitSeconds
+	ch <- m.poolConstructErrors
+	ch <- m.poolFailovers
 }
 
-// Collect fetches metrics from the database and sends them to the channel
+// Collect fetches metrics from the database and sends them to the channel.
+// The metrics from the database are cached when a cache duration is set, so
+// that frequent scrapes do not query the database each time
 func (m *metrics) Collect(ch chan<- prometheus.Metric) {
-	ctx, cancel := context.WithTimeout(context.Background(), metricsTimeout)
-	defer cancel()
+	// Pool statistics do not query the database
+	m.collectPool(ch)
 
-	var wg sync.WaitGroup
+	// Send the metrics from the database
+	for _, metric := range m.collectCached() {
+		ch <- metric
+	}
+}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := m.collectConnections(ctx, ch); err != nil {
-			ch <- prometheus.NewInvalidMetric(m.connections, err)
-		}
-	}()
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := m.collectDatabaseSize(ctx, ch); err != nil {
-			ch <- prometheus.NewInvalidMetric(m.databaseSize, err)
-		}
-	}()
+// collectCached returns the metrics from the database, which are collected
+// again when the cache has expired. Concurrent scrapes wait for the metrics
+// to be collected once
+func (m *metrics) collectCached() []prometheus.Metric {
+	m.Lock()
+	defer m.Unlock()
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := m.collectTablespaceSize(ctx, ch); err != nil {
-			ch <- prometheus.NewInvalidMetric(m.tablespaceSize, err)
-		}
-	}()
+	// Return the cached metrics, if they have not expired
+	if ttl := m.manager.MetricsCache(); ttl > 0 && m.cache != nil && time.Since(m.cached) < ttl {
+		return m.cache
+	}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := m.collectObjectSize(ctx, ch); err != nil {
-			ch <- prometheus.NewInvalidMetric(m.tableSize, err)
-			ch <- prometheus.NewInvalidMetric(m.indexSize, err)
-			ch <- prometheus.NewInvalidMetric(m.deadTupleRatio, err)
-		}
-	}()
+	// Collect and cache the metrics
+	m.cache, m.cached = m.collectDatabase(), time.Now()
+	return m.cache
+}
 
-	wg.Add(1)
+// collectDatabase runs the collectors which query the database concurrently,
+// each with the timeout set with WithMetricsTimeout, and returns the metrics
+// with the time each collector took. As the collectors run concurrently, the
+// scrape takes no longer than the timeout
+func (m *metrics) collectDatabase() []prometheus.Metric {
+	collectors := []struct {
+		name    string
+		collect func(context.Context, chan<- prometheus.Metric) error
+		descs   []*prometheus.Desc
+	}{
+		{"connections", m.collectConnections, []*prometheus.Desc{m.connections}},
+		{"long_running", m.collectLongRunning, []*prometheus.Desc{m.queryMaxAge, m.idleInTransaction}},
+		{"database_size", m.collectDatabaseSize, []*prometheus.Desc{m.databaseSize}},
+		{"tablespace_size", m.collectTablespaceSize, []*prometheus.Desc{m.tablespaceSize}},
+		{"object_size", m.collectObjectSize, []*prometheus.Desc{m.tableSize, m.indexSize, m.deadTupleRatio}},
+		{"bloat", m.collectBloat, []*prometheus.Desc{m.tableBloat, m.indexBloat}},
+		{"iostat", m.collectIOStats, []*prometheus.Desc{m.tableCacheHitRatio, m.indexCacheHitRatio}},
+		{"temp_usage", m.collectTempUsage, []*prometheus.Desc{m.tempBytes}},
+		{"statements", m.collectStatements, []*prometheus.Desc{m.statementSeconds, m.statementMean, m.statementStdDev, m.statementMin, m.statementMax, m.statementCalls, m.statementRows}},
+		{"replication_slots", m.collectReplicationSlots, []*prometheus.Desc{m.replicationSlots, m.replicationLagBytes, m.replicationLagMs}},
+		{"replication_status", m.collectReplicationStatus, []*prometheus.Desc{m.standbyLagBytes, m.standbyLagMs, m.receiverLagBytes, m.receiverLagMs}},
+		{"alerts", m.collectAlerts, []*prometheus.Desc{m.alertFiring}},
+	}
+
+	// Gather the metrics sent by the collectors
+	ch := make(chan prometheus.Metric)
+	done := make(chan []prometheus.Metric)
 	go func() {
-		defer wg.Done()
-		if err := m.collectReplicationSlots(ctx, ch); err != nil {
-			ch <- prometheus.NewInvalidMetric(m.replicationSlots, err)
-			ch <- prometheus.NewInvalidMetric(m.replicationLagBytes, err)
-			ch <- prometheus.NewInvalidMetric(m.replicationLagMs, err)
+		var result []prometheus.Metric
+		for metric := range ch {
+			result = append(result, metric)
 		}
+		done <- result
 	}()
 
+	// Run the collectors, each of which is cancelled after the timeout
+	var wg sync.WaitGroup
+	for _, collector := range collectors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), m.manager.MetricsTimeout())
+			defer cancel()
+
+			// Report an error for each metric of the collector when it fails
+			start := time.Now()
+			if err := collector.collect(ctx, ch); err != nil {
+				for _, desc := range collector.descs {
+					ch <- prometheus.NewInvalidMetric(desc, err)
+				}
+			}
+			ch <- prometheus.MustNewConstMetric(m.scrapeDuration, prometheus.GaugeValue, time.Since(start).Seconds(), collector.name)
+		}()
+	}
 	wg.Wait()
+	close(ch)
+
+	// Return the metrics
+	return <-done
 }
 
-///////////////////////////////////////////////////////////////////////////////
-// PRIVATE METHODS
+func (m *metrics) collectPool(ch chan<- prometheus.Metric) {
+	stat := m.manager.PoolStat()
+
+	// Connections by state
+	ch <- prometheus.MustNewConstMetric(m.poolConns, prometheus.GaugeValue, float64(stat.IdleConns), "idle")
+	ch <- prometheus.MustNewConstMetric(m.poolConns, prometheus.GaugeValue, float64(stat.AcquiredConns), "acquired")
+	ch <- prometheus.MustNewConstMetric(m.poolConns, prometheus.GaugeValue, float64(stat.ConstructingConns), "constructing")
+	ch <- prometheus.MustNewConstMetric(m.poolMaxConns, prometheus.GaugeValue, float64(stat.MaxConns))
+
+	// Acquires by result, and the time spent acquiring
+	ch <- prometheus.MustNewConstMetric(m.poolAcquires, prometheus.CounterValue, float64(stat.AcquireCount-stat.EmptyAcquireCount), "immediate")
+	ch <- prometheus.MustNewConstMetric(m.poolAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount), "waited")
+	ch <- prometheus.MustNewConstMetric(m.poolAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount), "canceled")
+	ch <- prometheus.MustNewConstMetric(m.poolAcquireSeconds, prometheus.CounterValue, stat.AcquireDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(m.poolWaitSeconds, prometheus.CounterValue, stat.AcquireWaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(m.poolConstructErrors, prometheus.CounterValue, float64(stat.ConstructErrors))
+	ch <- prometheus.MustNewConstMetric(m.poolFailovers, prometheus.CounterValue, float64(stat.Failovers))
+}
 
 func (m *metrics) collectConnections(ctx context.Context, ch chan<- prometheus.Metric) error {
 	// Count connections by database and state
@@ -225,6 +467,62 @@ func (m *metrics) collectConnections(ctx context.Context, ch chan<- prometheus.M
 	return nil
 }
 
+func (m *metrics) collectLongRunning(ctx context.Context, ch chan<- prometheus.Metric) error {
+	// The longest running query is first
+	queries, err := m.manager.ListLongRunningQueries(ctx, 0)
+	if err != nil {
+		return err
+	}
+	var age float64
+	if len(queries.Body) > 0 {
+		age = queries.Body[0].Duration / 1000
+	}
+	ch <- prometheus.MustNewConstMetric(m.queryMaxAge, prometheus.GaugeValue, age)
+
+	// Count the connections idle in a transaction
+	idle, err := m.manager.ListIdleInTransaction(ctx, 0)
+	if err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(m.idleInTransaction, prometheus.GaugeValue, float64(idle.Count))
+
+	return nil
+}
+
+func (m *metrics) collectAlerts(ctx context.Context, ch chan<- prometheus.Metric) error {
+	// Paginate through the enabled rules
+	var offset uint64
+	for {
+		req := schema.AlertRuleListRequest{
+			Enabled: types.BoolPtr(true),
+			OffsetLimit: pg.OffsetLimit{
+				Offset: offset,
+			},
+		}
+
+		list, err := m.manager.ListAlertRules(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		for _, rule := range list.Body {
+			var firing float64
+			if rule.Firing {
+				firing = 1
+			}
+			ch <- prometheus.MustNewConstMetric(m.alertFiring, prometheus.GaugeValue, firing, rule.Name, string(rule.Metric))
+		}
+
+		// Check if we've fetched all rules
+		offset += uint64(len(list.Body))
+		if offset >= list.Count || len(list.Body) == 0 {
+			break
+		}
+	}
+
+	return nil
+}
+
 func (m *metrics) collectDatabaseSize(ctx context.Context, ch chan<- prometheus.Metric) error {
 	// Paginate through all databases
 	var offset uint64
@@ -327,6 +625,121 @@ func (m *metrics) collectObjectSize(ctx context.Context, ch chan<- prometheus.Me
 	return nil
 }
 
+func (m *metrics) collectBloat(ctx context.Context, ch chan<- prometheus.Metric) error {
+	// Paginate through all the estimates
+	var offset uint64
+	for {
+		req := schema.BloatListRequest{
+			OffsetLimit: pg.OffsetLimit{
+				Offset: offset,
+			},
+		}
+
+		list, err := m.manager.ListBloat(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		for _, bloat := range list.Body {
+			switch bloat.Type {
+			case schema.BloatTypeTable:
+				ch <- prometheus.MustNewConstMetric(m.tableBloat, prometheus.GaugeValue, float64(bloat.Bloat), bloat.Database, bloat.Schema, bloat.Table)
+			case schema.BloatTypeIndex:
+				ch <- prometheus.MustNewConstMetric(m.indexBloat, prometheus.GaugeValue, float64(bloat.Bloat), bloat.Database, bloat.Schema, bloat.Index)
+			}
+		}
+
+		// Check if we've fetched all estimates
+		offset += uint64(len(list.Body))
+		if offset >= list.Count || len(list.Body) == 0 {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (m *metrics) collectIOStats(ctx context.Context, ch chan<- prometheus.Metric) error {
+	// Paginate through all the statistics
+	var offset uint64
+	for {
+		req := schema.IOStatListRequest{
+			OffsetLimit: pg.OffsetLimit{
+				Offset: offset,
+			},
+		}
+
+		list, err := m.manager.GetIOStats(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		// Tables and indexes which have not been accessed have no ratio
+		for _, stat := range list.Body {
+			switch {
+			case stat.Type == schema.IOStatTypeTable && stat.HeapRatio != nil:
+				ch <- prometheus.MustNewConstMetric(m.tableCacheHitRatio, prometheus.GaugeValue, *stat.HeapRatio, stat.Database, stat.Schema, stat.Table)
+			case stat.Type == schema.IOStatTypeIndex && stat.IdxRatio != nil:
+				ch <- prometheus.MustNewConstMetric(m.indexCacheHitRatio, prometheus.GaugeValue, *stat.IdxRatio, stat.Database, stat.Schema, stat.Index)
+			}
+		}
+
+		// Check if we've fetched all statistics
+		offset += uint64(len(list.Body))
+		if offset >= list.Count || len(list.Body) == 0 {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (m *metrics) collectTempUsage(ctx context.Context, ch chan<- prometheus.Metric) error {
+	usage, err := m.manager.GetTempUsage(ctx, schema.TempUsageRequest{})
+	if err != nil {
+		return err
+	}
+
+	// Report the bytes written by each database since the statistics were reset
+	for _, database := range usage.Databases {
+		ch <- prometheus.MustNewConstMetric(m.tempBytes, prometheus.CounterValue, float64(database.Bytes), database.Database)
+	}
+
+	return nil
+}
+
+func (m *metrics) collectStatements(ctx context.Context, ch chan<- prometheus.Metric) error {
+	// Statement metrics are disabled, or pg_stat_statements is not installed
+	limit := m.manager.StatementMetrics()
+	if limit == 0 || !m.manager.StatStatementsAvailable() {
+		return nil
+	}
+
+	// Report the pairs of role and database which spent the most time
+	// executing statements, so that the number of series is bounded
+	list, err := m.manager.ListStatementSummaries(ctx, schema.StatementSummaryListRequest{
+		OffsetLimit: pg.OffsetLimit{
+			Limit: types.Uint64Ptr(uint64(limit)),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Times are in milliseconds
+	for _, summary := range list.Body {
+		ch <- prometheus.MustNewConstSummary(m.statementSeconds, uint64(summary.Calls), summary.Total/1000, nil, summary.Database, summary.Role)
+		ch <- prometheus.MustNewConstMetric(m.statementMean, prometheus.GaugeValue, summary.Mean/1000, summary.Database, summary.Role)
+		ch <- prometheus.MustNewConstMetric(m.statementStdDev, prometheus.GaugeValue, summary.StdDev/1000, summary.Database, summary.Role)
+		ch <- prometheus.MustNewConstMetric(m.statementMin, prometheus.GaugeValue, summary.Min/1000, summary.Database, summary.Role)
+		ch <- prometheus.MustNewConstMetric(m.statementMax, prometheus.GaugeValue, summary.Max/1000, summary.Database, summary.Role)
+		ch <- prometheus.MustNewConstMetric(m.statementCalls, prometheus.CounterValue, float64(summary.Calls), summary.Database, summary.Role)
+		ch <- prometheus.MustNewConstMetric(m.statementRows, prometheus.CounterValue, float64(summary.Rows), summary.Database, summary.Role)
+	}
+
+	return nil
+}
+
 func (m *metrics) collectReplicationSlots(ctx context.Context, ch chan<- prometheus.Metric) error {
 	// Count slots by status
 	statusCounts := make(map[string]float64)
@@ -372,3 +785,34 @@ func (m *metrics) collectReplicationSlots(ctx context.Context, ch chan<- prometh
 
 	return nil
 }
+
+func (m *metrics) collectReplicationStatus(ctx context.Context, ch chan<- prometheus.Metric) error {
+	status, err := m.manager.GetReplicationStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Report the lag of each standby
+	for _, standby := range status.Standbys {
+		if standby.LagBytes != nil {
+			ch <- prometheus.MustNewConstMetric(m.standbyLagBytes, prometheus.GaugeValue, float64(*standby.LagBytes), standby.Name, standby.ClientAddr)
+		}
+		for lag, value := range map[string]*float64{"write": standby.WriteLagMs, "flush": standby.FlushLagMs, "replay": standby.ReplayLagMs} {
+			if value != nil {
+				ch <- prometheus.MustNewConstMetric(m.standbyLagMs, prometheus.GaugeValue, *value, standby.Name, standby.ClientAddr, lag)
+			}
+		}
+	}
+
+	// Report the lag of this server, when it is a standby
+	if receiver := status.Receiver; receiver != nil {
+		if receiver.LagBytes != nil {
+			ch <- prometheus.MustNewConstMetric(m.receiverLagBytes, prometheus.GaugeValue, float64(*receiver.LagBytes))
+		}
+		if receiver.ReplayLagMs != nil {
+			ch <- prometheus.MustNewConstMetric(m.receiverLagMs, prometheus.GaugeValue, *receiver.ReplayLagMs)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	httprequest "github.com/mutablelogic/go-pg/pkg/manager/httphandler"
+	test "github.com/mutablelogic/go-pg/pkg/test"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Metrics_RegisterHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("PanicOnNilManager", func(t *testing.T) {
+		router := http.NewServeMux()
+		assert.Panics(func() {
+			httprequest.RegisterMetricsHandler(router, "/api", nil)
+		})
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		router := metricsRouter(t)
+		req := httptest.NewRequest(http.MethodPost, "/api/metrics", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func Test_Metrics_Scrape(t *testing.T) {
+	assert := assert.New(t)
+	router := metricsRouter(t)
+
+	// Each collector reports the time it took, and the pool is reported
+	code, body := scrape(router)
+	assert.Equal(http.StatusOK, code)
+	durations := scrapeDurations(body)
+	for _, collector := range []string{"connections", "long_running", "database_size", "tablespace_size", "object_size", "bloat", "iostat", "temp_usage", "statements", "replication_slots", "replication_status", "alerts"} {
+		assert.Contains(strings.Join(durations, "\n"), `pg_exporter_scrape_duration_seconds{collector="`+collector+`"}`)
+	}
+	assert.Contains(body, "pg_pool_max_connections")
+
+	// Without a cache, the collectors run for each scrape
+	_, body = scrape(router)
+	assert.NotEqual(durations, scrapeDurations(body))
+}
+
+func Test_Metrics_Cache(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Reuse", func(t *testing.T) {
+		router := metricsRouter(t, manager.WithMetricsCache(time.Minute))
+
+		// The collectors run once within the cache duration, so the second
+		// scrape reports the same durations
+		code, first := scrape(router)
+		assert.Equal(http.StatusOK, code)
+		code, second := scrape(router)
+		assert.Equal(http.StatusOK, code)
+		assert.NotEmpty(scrapeDurations(first))
+		assert.Equal(scrapeDurations(first), scrapeDurations(second))
+	})
+
+	t.Run("Expire", func(t *testing.T) {
+		router := metricsRouter(t, manager.WithMetricsCache(100*time.Millisecond))
+
+		// The collectors run again once the cache has expired
+		_, first := scrape(router)
+		time.Sleep(200 * time.Millisecond)
+		_, second := scrape(router)
+		assert.NotEmpty(scrapeDurations(first))
+		assert.NotEqual(scrapeDurations(first), scrapeDurations(second))
+	})
+}
+
+func Test_Metrics_Timeout(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Exceeded", func(t *testing.T) {
+		router := metricsRouter(t, manager.WithMetricsTimeout(time.Nanosecond))
+
+		// The collectors which query the database are cancelled, and their
+		// metrics are reported as errors
+		code, body := scrape(router)
+		assert.Equal(http.StatusInternalServerError, code)
+		assert.Contains(body, "deadline exceeded")
+	})
+
+	t.Run("LongerThanScrape", func(t *testing.T) {
+		router := metricsRouter(t, manager.WithMetricsTimeout(time.Minute))
+
+		code, body := scrape(router)
+		assert.Equal(http.StatusOK, code)
+		assert.NotEmpty(scrapeDurations(body))
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return a router with the metrics handler for a manager with the options
+func metricsRouter(t *testing.T, opts ...manager.Opt) *http.ServeMux {
+	t.Helper()
+	router := http.NewServeMux()
+	httprequest.RegisterMetricsHandler(router, "/api", test.SharedManager(t, opts...).Manager)
+	return router
+}
+
+// Scrape the metrics, and return the status and body
+func scrape(router http.Handler) (int, string) {
+	req := httptest.NewRequest(http.MethodGet, "/api/metrics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code, w.Body.String()
+}
+
+// Return the lines which report the time taken by each collector, sorted
+func scrapeDurations(body string) []string {
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "pg_exporter_scrape_duration_seconds{") {
+			lines = append(lines, line)
+		}
+	}
+	slices.Sort(lines)
+	return lines
+}
@@ -0,0 +1,75 @@
+package httphandler
+
+import (
+	"context"
+	"net/http"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+	websocket "golang.org/x/net/websocket"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterNotifyHandlers registers a WebSocket handler which relays the
+// notifications on a channel to the client, on the provided router with the
+// given path prefix. The manager must be non-nil.
+func RegisterNotifyHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	router.HandleFunc(joinPath(prefix, "notify/{channel}"), func(w http.ResponseWriter, r *http.Request) {
+		channel := r.PathValue("channel")
+		switch r.Method {
+		case http.MethodGet:
+			_ = notifySubscribe(w, r, manager, channel)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Upgrade the request to a WebSocket, and send each notification on the
+// channel as a JSON message until the client disconnects. If listening
+// fails, a message with the error is sent before the connection is closed.
+func notifySubscribe(w http.ResponseWriter, r *http.Request, manager *manager.Manager, channel string) error {
+	if err := schema.ValidateChannel(channel); err != nil {
+		return problem(w, err)
+	}
+
+	// The origin is not checked, as requests are authenticated with
+	// credentials rather than cookies
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		// Stop listening when the client closes the connection. Messages
+		// from the client are discarded
+		go func() {
+			defer cancel()
+			var discard []byte
+			for {
+				if err := websocket.Message.Receive(ws, &discard); err != nil {
+					return
+				}
+			}
+		}()
+
+		// Relay notifications
+		if err := manager.Listen(ctx, channel, func(notification schema.Notification) error {
+			return websocket.JSON.Send(ws, notification)
+		}); err != nil && ctx.Err() == nil {
+			_ = websocket.JSON.Send(ws, schema.Notification{Channel: channel, Error: httperr(err).Error()})
+		}
+	}}.ServeHTTP(w, r)
+
+	// Return success
+	return nil
+}
@@ -0,0 +1,92 @@
+package httphandler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	// Packages
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	httphandler "github.com/mutablelogic/go-pg/pkg/manager/httphandler"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	test "github.com/mutablelogic/go-pg/pkg/test"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Notify_Subscribe(t *testing.T) {
+	assert := assert.New(t)
+
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
+
+	router := http.NewServeMux()
+	httphandler.RegisterNotifyHandlers(router, "/api", manager.Manager)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	client, err := httpclient.New(server.URL + "/api")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("ReceivesNotification", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+		defer cancel()
+
+		// Subscribe in the background
+		received := make(chan schema.Notification, 1)
+		result := make(chan error, 1)
+		go func() {
+			result <- client.Subscribe(ctx, "test_subscribe", func(notification schema.Notification) error {
+				select {
+				case received <- notification:
+				default:
+				}
+				return nil
+			})
+		}()
+
+		// Notify until the client receives a notification. pg_notify is
+		// allowed in the read-only transaction of an ad-hoc query
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			_, err := manager.Query(ctx, schema.QueryRequest{Query: `SELECT pg_notify('test_subscribe', 'hello')`})
+			assert.NoError(err)
+			select {
+			case notification := <-received:
+				assert.Equal("test_subscribe", notification.Channel)
+				assert.Equal("hello", notification.Payload)
+				cancel()
+				assert.NoError(<-result)
+				return
+			case <-ctx.Done():
+				t.Fatal("timed out waiting for notification")
+			case <-ticker.C:
+			}
+		}
+	})
+
+	t.Run("NotWebSocket", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/notify/test_subscribe", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/notify/test_subscribe", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusMethodNotAllowed, w.Code)
+	})
+}
@@ -15,7 +15,7 @@ import (
 
 // RegisterObjectHandlers registers HTTP handlers for object listing and retrieval
 // on the provided router with the given path prefix. The manager must be non-nil.
-func RegisterObjectHandlers(router *http.ServeMux, prefix string, manager *manager.Manager) {
+func RegisterObjectHandlers(router Router, prefix string, manager *manager.Manager) {
 	if manager == nil {
 		panic("manager is nil")
 	}
@@ -26,7 +26,7 @@ func RegisterObjectHandlers(router *http.ServeMux, prefix string, manager *manag
 		case http.MethodGet:
 			_ = objectList(w, r, manager, nil, nil, nil)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
@@ -34,7 +34,7 @@ func RegisterObjectHandlers(router *http.ServeMux, prefix string, manager *manag
 	router.HandleFunc(joinPath(prefix, "object/{database}"), func(w http.ResponseWriter, r *http.Request) {
 		database := r.PathValue("database")
 		if database == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
 			return
 		}
 
@@ -42,7 +42,7 @@ func RegisterObjectHandlers(router *http.ServeMux, prefix string, manager *manag
 		case http.MethodGet:
 			_ = objectList(w, r, manager, &database, nil, nil)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
@@ -50,12 +50,12 @@ func RegisterObjectHandlers(router *http.ServeMux, prefix string, manager *manag
 	router.HandleFunc(joinPath(prefix, "object/{database}/{schema}"), func(w http.ResponseWriter, r *http.Request) {
 		database := r.PathValue("database")
 		if database == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
 			return
 		}
 		namespace := r.PathValue("schema")
 		if namespace == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
 			return
 		}
 
@@ -63,7 +63,7 @@ func RegisterObjectHandlers(router *http.ServeMux, prefix string, manager *manag
 		case http.MethodGet:
 			_ = objectList(w, r, manager, &database, &namespace, nil)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
@@ -71,17 +71,17 @@ func RegisterObjectHandlers(router *http.ServeMux, prefix string, manager *manag
 	router.HandleFunc(joinPath(prefix, "object/{database}/{schema}/{name}"), func(w http.ResponseWriter, r *http.Request) {
 		database := r.PathValue("database")
 		if database == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
 			return
 		}
 		namespace := r.PathValue("schema")
 		if namespace == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
 			return
 		}
 		name := r.PathValue("name")
 		if name == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid object name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid object name"))
 			return
 		}
 
@@ -89,7 +89,166 @@ func RegisterObjectHandlers(router *http.ServeMux, prefix string, manager *manag
 		case http.MethodGet:
 			_ = objectGet(w, r, manager, database, namespace, name)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// List the columns of a specific object
+	router.HandleFunc(joinPath(prefix, "object/{database}/{schema}/{name}/column"), func(w http.ResponseWriter, r *http.Request) {
+		database := r.PathValue("database")
+		if database == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+		namespace := r.PathValue("schema")
+		if namespace == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
+			return
+		}
+		name := r.PathValue("name")
+		if name == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid object name"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = objectColumnList(w, r, manager, database, namespace, name)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Get the statements which create a specific object
+	router.HandleFunc(joinPath(prefix, "object/{database}/{schema}/{name}/ddl"), func(w http.ResponseWriter, r *http.Request) {
+		database := r.PathValue("database")
+		if database == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+		namespace := r.PathValue("schema")
+		if namespace == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
+			return
+		}
+		name := r.PathValue("name")
+		if name == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid object name"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = objectDDL(w, r, manager, database, namespace, name)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Export the rows of a specific object
+	router.HandleFunc(joinPath(prefix, "object/{database}/{schema}/{name}/export"), func(w http.ResponseWriter, r *http.Request) {
+		database := r.PathValue("database")
+		if database == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+		namespace := r.PathValue("schema")
+		if namespace == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
+			return
+		}
+		name := r.PathValue("name")
+		if name == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid object name"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = objectExport(w, r, manager, database, namespace, name)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Reindex a specific object
+	router.HandleFunc(joinPath(prefix, "object/{database}/{schema}/{name}/reindex"), func(w http.ResponseWriter, r *http.Request) {
+		database := r.PathValue("database")
+		if database == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+		namespace := r.PathValue("schema")
+		if namespace == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
+			return
+		}
+		name := r.PathValue("name")
+		if name == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid object name"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPost:
+			_ = objectReindex(w, r, manager, database, namespace, name)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Get the column statistics of a specific object, or set the statistics
+	// target of a column
+	router.HandleFunc(joinPath(prefix, "object/{database}/{schema}/{name}/statistics"), func(w http.ResponseWriter, r *http.Request) {
+		database := r.PathValue("database")
+		if database == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+		namespace := r.PathValue("schema")
+		if namespace == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
+			return
+		}
+		name := r.PathValue("name")
+		if name == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid object name"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = objectStatistics(w, r, manager, database, namespace, name)
+		case http.MethodPatch:
+			_ = objectStatisticsTarget(w, r, manager, database, namespace, name)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Check a specific object for corruption
+	router.HandleFunc(joinPath(prefix, "object/{database}/{schema}/{name}/verify"), func(w http.ResponseWriter, r *http.Request) {
+		database := r.PathValue("database")
+		if database == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+		namespace := r.PathValue("schema")
+		if namespace == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
+			return
+		}
+		name := r.PathValue("name")
+		if name == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid object name"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPost:
+			_ = objectVerify(w, r, manager, database, namespace, name)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 }
@@ -101,7 +260,7 @@ func objectList(w http.ResponseWriter, r *http.Request, manager *manager.Manager
 	// Parse request
 	var req schema.ObjectListRequest
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Apply path filters
@@ -118,7 +277,7 @@ func objectList(w http.ResponseWriter, r *http.Request, manager *manager.Manager
 	// List the objects
 	response, err := manager.ListObjects(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -129,7 +288,112 @@ func objectGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager,
 	// Get the object
 	response, err := manager.GetObject(r.Context(), database, namespace, name)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func objectDDL(w http.ResponseWriter, r *http.Request, manager *manager.Manager, database, namespace, name string) error {
+	// Get the statements
+	response, err := manager.GetObjectDDL(r.Context(), database, namespace, name)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func objectExport(w http.ResponseWriter, r *http.Request, manager *manager.Manager, database, namespace, name string) error {
+	// Stream the rows as an attachment
+	writer := &attachmentWriter{w: w, contentType: contentTypeNDJSON, filename: namespace + "." + name + ".ndjson"}
+	if err := manager.ExportObject(r.Context(), database, namespace, name, writer); err != nil {
+		if !writer.written {
+			return problem(w, err)
+		}
+		return err
+	}
+
+	// Write the headers if there were no rows
+	if !writer.written {
+		writer.writeHeader()
+	}
+
+	// Return success
+	return nil
+}
+
+func objectColumnList(w http.ResponseWriter, r *http.Request, manager *manager.Manager, database, namespace, name string) error {
+	// List the columns
+	response, err := manager.ListColumns(r.Context(), database, namespace, name)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func objectReindex(w http.ResponseWriter, r *http.Request, manager *manager.Manager, database, namespace, name string) error {
+	// Parse concurrently from query params
+	concurrently := r.URL.Query().Get("concurrently") == "true"
+
+	// Refuse to reindex outside of the maintenance windows
+	if err := maintenanceWindow(r, manager); err != nil {
+		return problem(w, err)
+	}
+
+	// Reindex the object
+	response, err := manager.ReindexObject(r.Context(), database, namespace, name, concurrently)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func objectStatistics(w http.ResponseWriter, r *http.Request, manager *manager.Manager, database, namespace, name string) error {
+	// Get the column statistics
+	response, err := manager.GetColumnStatistics(r.Context(), database, namespace, name)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func objectStatisticsTarget(w http.ResponseWriter, r *http.Request, manager *manager.Manager, database, namespace, name string) error {
+	// Parse request
+	var req schema.ColumnStatisticsMeta
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Set the statistics target
+	response, err := manager.SetStatisticsTarget(r.Context(), database, namespace, name, req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func objectVerify(w http.ResponseWriter, r *http.Request, manager *manager.Manager, database, namespace, name string) error {
+	// Parse request
+	var req schema.ObjectVerifyRequest
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Check the object for corruption
+	response, err := manager.VerifyObject(r.Context(), database, namespace, name, req)
+	if err != nil {
+		return problem(w, err)
 	}
 
 	// Return success
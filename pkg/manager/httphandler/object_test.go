@@ -19,11 +19,8 @@ import (
 func Test_Object_RegisterHandlers(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	t.Run("PanicOnNilManager", func(t *testing.T) {
 		router := http.NewServeMux()
@@ -43,11 +40,8 @@ func Test_Object_RegisterHandlers(t *testing.T) {
 func Test_Object_List(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterObjectHandlers(router, "/api", manager.Manager)
@@ -186,11 +180,8 @@ func Test_Object_List(t *testing.T) {
 func Test_Object_Get(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterObjectHandlers(router, "/api", manager.Manager)
@@ -260,3 +251,32 @@ func Test_Object_Get(t *testing.T) {
 		assert.Equal(http.StatusMethodNotAllowed, w.Code)
 	})
 }
+
+func Test_Object_Export(t *testing.T) {
+	assert := assert.New(t)
+
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
+
+	router := http.NewServeMux()
+	httprequest.RegisterObjectHandlers(router, "/api", manager.Manager)
+
+	t.Run("NotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/object/postgres/public/nonexistent_object_xyz/export", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusNotFound, w.Code)
+		assert.Empty(w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/object/postgres/public/test/export", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusMethodNotAllowed, w.Code)
+	})
+}
@@ -0,0 +1,702 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	version "github.com/mutablelogic/go-pg/pkg/version"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// routes is a router which records the paths which are registered, so that
+// the OpenAPI document only describes the resources which are served
+type routes struct {
+	Router
+	prefix string
+	paths  []string
+}
+
+// operation describes a method on a path for the OpenAPI document. The query,
+// request and response are values of the types which the handler reads and
+// writes, or nil when there are none
+type operation struct {
+	Method       string
+	Summary      string
+	Query        any
+	Request      any
+	Response     any
+	Status       int    // Status on success, or 200 when zero
+	RequestType  string // Content type of a request body which is not JSON
+	ResponseType string // Content type of a response body which is not JSON
+}
+
+// OpenAPI 3.0 document, with the subset of fields which are used
+type openapiDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openapiInfo                             `json:"info"`
+	Servers    []openapiServer                         `json:"servers"`
+	Paths      map[string]map[string]*openapiOperation `json:"paths"`
+	Components openapiComponents                       `json:"components"`
+	Security   []map[string][]string                   `json:"security,omitempty"`
+}
+
+type openapiInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openapiServer struct {
+	URL string `json:"url"`
+}
+
+type openapiOperation struct {
+	Summary     string                      `json:"summary,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []openapiParameter          `json:"parameters,omitempty"`
+	RequestBody *openapiBody                `json:"requestBody,omitempty"`
+	Responses   map[string]*openapiResponse `json:"responses"`
+}
+
+type openapiParameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Schema      *jsonSchema `json:"schema"`
+}
+
+type openapiBody struct {
+	Required bool                    `json:"required,omitempty"`
+	Content  map[string]openapiMedia `json:"content"`
+}
+
+type openapiResponse struct {
+	Description string                  `json:"description"`
+	Content     map[string]openapiMedia `json:"content,omitempty"`
+}
+
+type openapiMedia struct {
+	Schema *jsonSchema `json:"schema"`
+}
+
+type openapiComponents struct {
+	Schemas         map[string]*jsonSchema    `json:"schemas"`
+	SecuritySchemes map[string]map[string]any `json:"securitySchemes,omitempty"`
+}
+
+// jsonSchema is the subset of the OpenAPI schema object which describes the
+// schema types
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Nullable             bool                   `json:"nullable,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	openapiVersion = "3.0.3"
+	openapiError   = "Problem"
+
+	// Swagger UI page, which loads the document from the same directory
+	swaggerPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>pgmanager API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });</script>
+</body>
+</html>
+`
+)
+
+var (
+	reOpenAPIPathParam = regexp.MustCompile(`\{([a-z]+)\}`)
+	typeTime           = reflect.TypeOf(time.Time{})
+	typeRawMessage     = reflect.TypeOf(json.RawMessage{})
+)
+
+// Query parameters which are read by handlers into anonymous structs
+var (
+	forceQuery = struct {
+		Force bool `json:"force,omitempty" help:"Force delete"`
+	}{}
+	cascadeQuery = struct {
+		Cascade bool `json:"cascade,omitempty" help:"Cascade to dependent objects"`
+	}{}
+	extensionDeleteQuery = struct {
+		Database string `json:"database" help:"Database to delete extension from"`
+		Cascade  bool   `json:"cascade,omitempty" help:"Cascade delete to dependent objects"`
+	}{}
+	settingQuery = struct {
+		Reload bool   `json:"reload,omitempty" help:"Reload config after update"`
+		Scope  string `json:"scope,omitempty" help:"With system, validate the value and write settings which require a restart"`
+	}{}
+	hbaQuery = struct {
+		Before uint64 `json:"before,omitempty" help:"Insert before this line, or append when zero"`
+	}{}
+	overrideQuery = struct {
+		Override bool `json:"override,omitempty" help:"Run outside of the maintenance windows"`
+	}{}
+	confirmQuery = struct {
+		Confirm bool `json:"confirm" help:"Confirm the statistics are to be reset"`
+	}{}
+	reindexQuery = struct {
+		Concurrently bool `json:"concurrently,omitempty" help:"Reindex without locking out writes"`
+		Override     bool `json:"override,omitempty" help:"Run outside of the maintenance windows"`
+	}{}
+	diffQuery = struct {
+		Database string `json:"database" help:"Database which is changed to match the other database"`
+		Other    string `json:"other" help:"Other database"`
+		schema.SchemaDiffRequest
+	}{}
+	databaseCloneRequest = struct {
+		Name string `json:"name" help:"Name of the new database"`
+		schema.DatabaseCloneRequest
+	}{}
+	tablespaceCreateRequest = struct {
+		Location string `json:"location" help:"Directory for the tablespace"`
+		schema.TablespaceMeta
+	}{}
+)
+
+// Operations for each path relative to the prefix
+var operations = map[string][]operation{
+	"alertrule": {
+		{Method: http.MethodGet, Summary: "List alert rules", Query: schema.AlertRuleListRequest{}, Response: schema.AlertRuleList{}},
+		{Method: http.MethodPost, Summary: "Create an alert rule", Request: schema.AlertRuleMeta{}, Response: schema.AlertRule{}, Status: http.StatusCreated},
+	},
+	"alerts": {
+		{Method: http.MethodGet, Summary: "List the alert rules which are firing", Query: schema.AlertRuleListRequest{}, Response: schema.AlertRuleList{}},
+	},
+	"alertrule/{name}": {
+		{Method: http.MethodGet, Summary: "Get an alert rule", Response: schema.AlertRule{}},
+		{Method: http.MethodPatch, Summary: "Update an alert rule", Request: schema.AlertRuleMeta{}, Response: schema.AlertRule{}},
+		{Method: http.MethodDelete, Summary: "Delete an alert rule"},
+	},
+	"audit": {
+		{Method: http.MethodGet, Summary: "List the audit log", Query: schema.AuditListRequest{}, Response: schema.AuditList{}},
+	},
+	"backup": {
+		{Method: http.MethodGet, Summary: "Get the base backup in progress", Response: schema.Backup{}},
+		{Method: http.MethodPost, Summary: "Start a base backup", Query: overrideQuery, Request: schema.BackupMeta{}, Response: schema.Backup{}, Status: http.StatusCreated},
+		{Method: http.MethodDelete, Summary: "Stop the base backup in progress", Query: schema.BackupStopRequest{}, Response: schema.Backup{}},
+	},
+	"bloat": {
+		{Method: http.MethodGet, Summary: "Get the estimated bloat of tables and indexes, and the growth of each database", Query: schema.BloatListRequest{}, Response: schema.BloatReport{}},
+	},
+	"bloat/{database}": {
+		{Method: http.MethodGet, Summary: "Get the estimated bloat of tables and indexes in a database, and its growth", Query: schema.BloatListRequest{}, Response: schema.BloatReport{}},
+	},
+	"comment": {
+		{Method: http.MethodPost, Summary: "Set or remove the comment on a database, role, schema or object", Request: schema.CommentTarget{}, Status: http.StatusNoContent},
+	},
+	"compare": {
+		{Method: http.MethodPost, Summary: "Compare a profile with this server", Request: schema.Profile{}, Response: schema.Comparison{}},
+	},
+	"config/files": {
+		{Method: http.MethodGet, Summary: "List the settings in the configuration files", Query: schema.ConfigFileSettingListRequest{}, Response: schema.ConfigFileSettingList{}},
+	},
+	"config/hba": {
+		{Method: http.MethodGet, Summary: "List the rules in pg_hba.conf", Query: schema.HBARuleListRequest{}, Response: schema.HBARuleList{}},
+		{Method: http.MethodPost, Summary: "Add a rule to pg_hba.conf and reload the configuration", Query: hbaQuery, Request: schema.HBARuleMeta{}, Response: schema.HBARuleList{}, Status: http.StatusCreated},
+	},
+	"config/hba/{line}": {
+		{Method: http.MethodDelete, Summary: "Remove a rule from pg_hba.conf and reload the configuration", Response: schema.HBARuleList{}},
+	},
+	"connection": {
+		{Method: http.MethodGet, Summary: "List connections", Query: schema.ConnectionListRequest{}, Response: schema.ConnectionList{}},
+	},
+	"connection/long-running": {
+		{Method: http.MethodGet, Summary: "List long running queries, or connections idle in a transaction", Query: schema.LongRunningListRequest{}, Response: schema.LongRunningQueryList{}},
+	},
+	"connection/watch": {
+		{Method: http.MethodGet, Summary: "Stream changes to connections", Query: schema.ConnectionWatchRequest{}, ResponseType: types.ContentTypeTextStream},
+	},
+	"connection/{pid}": {
+		{Method: http.MethodGet, Summary: "Get a connection", Response: schema.Connection{}},
+		{Method: http.MethodDelete, Summary: "Terminate a connection"},
+	},
+	"cronjob": {
+		{Method: http.MethodGet, Summary: "List pg_cron jobs", Query: schema.CronJobListRequest{}, Response: schema.CronJobList{}},
+		{Method: http.MethodPost, Summary: "Schedule a pg_cron job", Request: schema.CronJobMeta{}, Response: schema.CronJob{}, Status: http.StatusCreated},
+	},
+	"cronjob/{id}": {
+		{Method: http.MethodGet, Summary: "Get a pg_cron job", Response: schema.CronJob{}},
+		{Method: http.MethodDelete, Summary: "Unschedule a pg_cron job"},
+	},
+	"cronjob/{id}/run": {
+		{Method: http.MethodGet, Summary: "List runs of a pg_cron job", Query: schema.CronJobRunListRequest{}, Response: schema.CronJobRunList{}},
+	},
+	"cronrun": {
+		{Method: http.MethodGet, Summary: "List pg_cron job runs", Query: schema.CronJobRunListRequest{}, Response: schema.CronJobRunList{}},
+	},
+	"database": {
+		{Method: http.MethodGet, Summary: "List databases", Query: schema.DatabaseListRequest{}, Response: schema.DatabaseList{}},
+		{Method: http.MethodPost, Summary: "Create a database", Request: schema.DatabaseMeta{}, Response: schema.Database{}, Status: http.StatusCreated},
+	},
+	"database/{name}": {
+		{Method: http.MethodGet, Summary: "Get a database", Response: schema.Database{}},
+		{Method: http.MethodPatch, Summary: "Update a database", Request: schema.DatabaseMeta{}, Response: schema.Database{}},
+		{Method: http.MethodDelete, Summary: "Delete a database", Query: forceQuery},
+	},
+	"database/{name}/clone": {
+		{Method: http.MethodPost, Summary: "Copy a database to a new database", Request: databaseCloneRequest, Response: schema.Database{}, Status: http.StatusCreated},
+	},
+	"database/{name}/dump": {
+		{Method: http.MethodGet, Summary: "Dump a database", Query: schema.DatabaseDumpRequest{}, ResponseType: types.ContentTypeBinary},
+	},
+	"database/{name}/restore": {
+		{Method: http.MethodPost, Summary: "Restore a dump into a database", Query: schema.DatabaseRestoreRequest{}, RequestType: types.ContentTypeBinary, Response: schema.DatabaseRestore{}},
+	},
+	"diff": {
+		{Method: http.MethodGet, Summary: "Compare the tables, columns, constraints and indexes of two databases", Query: diffQuery, Response: schema.SchemaDiff{}},
+	},
+	"explain": {
+		{Method: http.MethodPost, Summary: "Explain a query", Request: schema.ExplainRequest{}, Response: schema.Explain{}},
+	},
+	"extension": {
+		{Method: http.MethodGet, Summary: "List extensions", Query: schema.ExtensionListRequest{}, Response: schema.ExtensionList{}},
+		{Method: http.MethodPost, Summary: "Create an extension", Query: cascadeQuery, Request: schema.ExtensionMeta{}, Response: schema.Extension{}, Status: http.StatusCreated},
+	},
+	"extension/{name}": {
+		{Method: http.MethodGet, Summary: "Get an extension", Response: schema.Extension{}},
+		{Method: http.MethodPatch, Summary: "Update an extension", Request: schema.ExtensionMeta{}, Response: schema.Extension{}},
+		{Method: http.MethodDelete, Summary: "Delete an extension", Query: extensionDeleteQuery},
+	},
+	"iostat": {
+		{Method: http.MethodGet, Summary: "List the cache hit ratios of tables and indexes", Query: schema.IOStatListRequest{}, Response: schema.IOStatList{}},
+	},
+	"iostat/{database}": {
+		{Method: http.MethodGet, Summary: "List the cache hit ratios of tables and indexes in a database", Query: schema.IOStatListRequest{}, Response: schema.IOStatList{}},
+	},
+	"maintenance": {
+		{Method: http.MethodGet, Summary: "Get the maintenance windows and queued operations", Response: schema.Maintenance{}},
+		{Method: http.MethodPost, Summary: "Queue an operation until the next maintenance window", Request: schema.MaintenanceTaskMeta{}, Response: schema.MaintenanceTask{}, Status: http.StatusAccepted},
+	},
+	"metrics": {
+		{Method: http.MethodGet, Summary: "Get Prometheus metrics", ResponseType: types.ContentTypeTextPlain},
+	},
+	"notify/{channel}": {
+		{Method: http.MethodGet, Summary: "Subscribe to notifications on a channel over a WebSocket", Status: http.StatusSwitchingProtocols, Response: schema.Notification{}},
+	},
+	"object": {
+		{Method: http.MethodGet, Summary: "List objects", Query: schema.ObjectListRequest{}, Response: schema.ObjectList{}},
+	},
+	"object/{database}": {
+		{Method: http.MethodGet, Summary: "List objects in a database", Query: schema.ObjectListRequest{}, Response: schema.ObjectList{}},
+	},
+	"object/{database}/{schema}": {
+		{Method: http.MethodGet, Summary: "List objects in a schema", Query: schema.ObjectListRequest{}, Response: schema.ObjectList{}},
+	},
+	"object/{database}/{schema}/{name}": {
+		{Method: http.MethodGet, Summary: "Get an object", Response: schema.Object{}},
+	},
+	"object/{database}/{schema}/{name}/column": {
+		{Method: http.MethodGet, Summary: "List the columns of an object", Response: schema.ColumnList{}},
+	},
+	"object/{database}/{schema}/{name}/ddl": {
+		{Method: http.MethodGet, Summary: "Get the statements which create an object", Response: schema.ObjectDDL{}},
+	},
+	"object/{database}/{schema}/{name}/export": {
+		{Method: http.MethodGet, Summary: "Export the rows of an object as newline-delimited JSON", ResponseType: contentTypeNDJSON},
+	},
+	"object/{database}/{schema}/{name}/reindex": {
+		{Method: http.MethodPost, Summary: "Reindex an object", Query: reindexQuery, Response: schema.Object{}},
+	},
+	"object/{database}/{schema}/{name}/statistics": {
+		{Method: http.MethodGet, Summary: "Get the planner statistics of the columns of an object", Response: schema.ColumnStatisticsList{}},
+		{Method: http.MethodPatch, Summary: "Set the statistics target of a column", Request: schema.ColumnStatisticsMeta{}, Response: schema.ColumnStatisticsList{}},
+	},
+	"object/{database}/{schema}/{name}/verify": {
+		{Method: http.MethodPost, Summary: "Check an object for corruption with amcheck", Request: schema.ObjectVerifyRequest{}, Response: schema.ObjectVerification{}},
+	},
+	"profile": {
+		{Method: http.MethodGet, Summary: "Get the profile of this server", Response: schema.Profile{}},
+	},
+	"query": {
+		{Method: http.MethodPost, Summary: "Run a read-only query", Request: schema.QueryRequest{}, Response: schema.QueryResult{}},
+	},
+	"recovery": {
+		{Method: http.MethodGet, Summary: "Get whether the server is a standby, with the positions of the WAL it has received and replayed", Response: schema.RecoveryStatus{}},
+	},
+	"recovery/promote": {
+		{Method: http.MethodPost, Summary: "Promote the standby to a primary", Request: schema.PromoteRequest{}, Response: schema.RecoveryStatus{}},
+	},
+	"replication/status": {
+		{Method: http.MethodGet, Summary: "Get the standbys which stream from the server, and the WAL receiver when the server is a standby", Response: schema.ReplicationStatus{}},
+	},
+	"replicationslot": {
+		{Method: http.MethodGet, Summary: "List replication slots", Query: schema.ReplicationSlotListRequest{}, Response: schema.ReplicationSlotList{}},
+		{Method: http.MethodPost, Summary: "Create a replication slot", Request: schema.ReplicationSlotMeta{}, Response: schema.ReplicationSlot{}, Status: http.StatusCreated},
+	},
+	"replicationslot/{name}": {
+		{Method: http.MethodGet, Summary: "Get a replication slot", Response: schema.ReplicationSlot{}},
+		{Method: http.MethodDelete, Summary: "Delete a replication slot"},
+	},
+	"role": {
+		{Method: http.MethodGet, Summary: "List roles", Query: schema.RoleListRequest{}, Response: schema.RoleList{}},
+		{Method: http.MethodPost, Summary: "Create a role", Request: schema.RoleMeta{}, Response: schema.Role{}, Status: http.StatusCreated},
+	},
+	"role/{name}": {
+		{Method: http.MethodGet, Summary: "Get a role", Response: schema.Role{}},
+		{Method: http.MethodPatch, Summary: "Update a role", Request: schema.RoleMeta{}, Response: schema.Role{}},
+		{Method: http.MethodDelete, Summary: "Delete a role"},
+	},
+	"schema": {
+		{Method: http.MethodGet, Summary: "List schemas", Query: schema.SchemaListRequest{}, Response: schema.SchemaList{}},
+	},
+	"schema/{database}": {
+		{Method: http.MethodGet, Summary: "List schemas in a database", Query: schema.SchemaListRequest{}, Response: schema.SchemaList{}},
+		{Method: http.MethodPost, Summary: "Create a schema", Request: schema.SchemaMeta{}, Response: schema.Schema{}, Status: http.StatusCreated},
+	},
+	"schema/{database}/{namespace}": {
+		{Method: http.MethodGet, Summary: "Get a schema", Response: schema.Schema{}},
+		{Method: http.MethodPatch, Summary: "Update a schema", Request: schema.SchemaMeta{}, Response: schema.Schema{}},
+		{Method: http.MethodDelete, Summary: "Delete a schema", Query: forceQuery},
+	},
+	"schemasize": {
+		{Method: http.MethodGet, Summary: "List schema sizes", Query: schema.SchemaListRequest{}, Response: schema.SchemaRollupList{}},
+	},
+	"schemasize/{database}": {
+		{Method: http.MethodGet, Summary: "List schema sizes in a database", Query: schema.SchemaListRequest{}, Response: schema.SchemaRollupList{}},
+	},
+	"server": {
+		{Method: http.MethodGet, Summary: "Get the connected server", Response: schema.Server{}},
+	},
+	"setting": {
+		{Method: http.MethodGet, Summary: "List settings", Query: schema.SettingListRequest{}, Response: schema.SettingList{}},
+	},
+	"setting/category": {
+		{Method: http.MethodGet, Summary: "List setting categories", Response: schema.SettingCategoryList{}},
+	},
+	"setting/history": {
+		{Method: http.MethodGet, Summary: "List setting changes", Query: schema.SettingHistoryListRequest{}, Response: schema.SettingHistoryList{}},
+	},
+	"setting/pending": {
+		{Method: http.MethodGet, Summary: "List settings which differ from the boot value or require a restart", Query: schema.SettingListRequest{}, Response: schema.SettingList{}},
+	},
+	"setting/diff": {
+		{Method: http.MethodPost, Summary: "Compare settings with desired values", Request: map[string]string{}, Response: schema.SettingDiff{}},
+	},
+	"setting/reload": {
+		{Method: http.MethodPost, Summary: "Reload the configuration files", Response: schema.Server{}},
+	},
+	"setting/{name}": {
+		{Method: http.MethodGet, Summary: "Get a setting", Response: schema.Setting{}},
+		{Method: http.MethodPatch, Summary: "Update a setting", Query: settingQuery, Request: schema.SettingMeta{}, Response: schema.Setting{}},
+	},
+	"staletable": {
+		{Method: http.MethodGet, Summary: "List tables which need vacuuming or analyzing", Query: schema.StaleTableListRequest{}, Response: schema.StaleTableList{}},
+	},
+	"staletable/{database}": {
+		{Method: http.MethodGet, Summary: "List tables in a database which need vacuuming or analyzing", Query: schema.StaleTableListRequest{}, Response: schema.StaleTableList{}},
+	},
+	"statement": {
+		{Method: http.MethodGet, Summary: "List statement statistics", Query: schema.StatementListRequest{}, Response: schema.StatementList{}},
+		{Method: http.MethodDelete, Summary: "Reset statement statistics", Status: http.StatusNoContent},
+	},
+	"statistics/reset": {
+		{Method: http.MethodPost, Summary: "Reset the statistics of a database, a table or statements", Query: confirmQuery, Request: schema.StatisticsTarget{}, Status: http.StatusNoContent},
+	},
+	"tablespace": {
+		{Method: http.MethodGet, Summary: "List tablespaces", Query: schema.TablespaceListRequest{}, Response: schema.TablespaceList{}},
+		{Method: http.MethodPost, Summary: "Create a tablespace", Request: tablespaceCreateRequest, Response: schema.Tablespace{}, Status: http.StatusCreated},
+	},
+	"tablespace/stat": {
+		{Method: http.MethodGet, Summary: "List the space on the filesystem of each tablespace", Response: schema.TablespaceStatList{}},
+	},
+	"tablespace/{name}": {
+		{Method: http.MethodGet, Summary: "Get a tablespace", Response: schema.Tablespace{}},
+		{Method: http.MethodPatch, Summary: "Update a tablespace", Request: schema.TablespaceMeta{}, Response: schema.Tablespace{}},
+		{Method: http.MethodDelete, Summary: "Delete a tablespace"},
+	},
+	"tempusage": {
+		{Method: http.MethodGet, Summary: "Get the temporary files written by each database and statement", Query: schema.TempUsageRequest{}, Response: schema.TempUsage{}},
+	},
+	"tempusage/{database}": {
+		{Method: http.MethodGet, Summary: "Get the temporary files written by a database and its statements", Query: schema.TempUsageRequest{}, Response: schema.TempUsage{}},
+	},
+	"version": {
+		{Method: http.MethodGet, Summary: "Get the version of the manager and the API", Response: schema.Version{}},
+	},
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// HandleFunc records the path, and registers the handler
+func (r *routes) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	r.paths = append(r.paths, strings.TrimPrefix(strings.TrimPrefix(pattern, r.prefix), "/"))
+	r.Router.HandleFunc(pattern, handler)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// registerOpenAPIHandlers registers the handlers which serve the OpenAPI
+// document for the paths which are registered, and a Swagger UI page which
+// displays it.
+func registerOpenAPIHandlers(router Router, prefix string, paths []string, auth bool) {
+	document := newOpenAPIDocument(prefix, paths, auth)
+
+	// Serve the document
+	router.HandleFunc(joinPath(prefix, "openapi.json"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), document)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Serve the Swagger UI page
+	router.HandleFunc(joinPath(prefix, "openapi"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set(types.ContentTypeHeader, "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(swaggerPage))
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+// Return the OpenAPI document for the paths, which are relative to the
+// prefix. Paths without operations are not included.
+func newOpenAPIDocument(prefix string, paths []string, auth bool) *openapiDocument {
+	document := &openapiDocument{
+		OpenAPI: openapiVersion,
+		Info:    openapiInfo{Title: version.ExecName(), Version: version.Version()},
+		Servers: []openapiServer{{URL: prefix}},
+		Paths:   make(map[string]map[string]*openapiOperation, len(paths)),
+		Components: openapiComponents{
+			Schemas: make(map[string]*jsonSchema),
+		},
+	}
+
+	// Errors are returned as a problem, as described in RFC 7807
+	document.schema(reflect.TypeOf(schema.Problem{}), true)
+
+	// Requests require a bearer token or basic credentials
+	if auth {
+		document.Components.SecuritySchemes = map[string]map[string]any{
+			"bearer": {"type": "http", "scheme": "bearer"},
+			"basic":  {"type": "http", "scheme": "basic"},
+		}
+		document.Security = []map[string][]string{{"bearer": {}}, {"basic": {}}}
+	}
+
+	// Describe the operations on each path
+	for _, path := range paths {
+		ops, exists := operations[path]
+		if !exists {
+			continue
+		}
+		methods := make(map[string]*openapiOperation, len(ops))
+		for _, op := range ops {
+			methods[strings.ToLower(op.Method)] = document.operation(path, op)
+		}
+		document.Paths["/"+path] = methods
+	}
+
+	// Return the document
+	return document
+}
+
+// Return the description of an operation on a path
+func (document *openapiDocument) operation(path string, op operation) *openapiOperation {
+	result := &openapiOperation{
+		Summary:   op.Summary,
+		Tags:      []string{strings.SplitN(path, "/", 2)[0]},
+		Responses: make(map[string]*openapiResponse, 2),
+	}
+
+	// Path parameters
+	for _, match := range reOpenAPIPathParam.FindAllStringSubmatch(path, -1) {
+		result.Parameters = append(result.Parameters, openapiParameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &jsonSchema{Type: "string"},
+		})
+	}
+
+	// Query parameters
+	if op.Query != nil {
+		query := document.schema(reflect.TypeOf(op.Query), false)
+		names := make([]string, 0, len(query.Properties))
+		for name := range query.Properties {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			property := query.Properties[name]
+			result.Parameters = append(result.Parameters, openapiParameter{
+				Name:        name,
+				In:          "query",
+				Description: property.Description,
+				Schema:      &jsonSchema{Type: property.Type, Format: property.Format, Items: property.Items},
+			})
+		}
+	}
+
+	// Request body
+	if op.RequestType != "" {
+		result.RequestBody = &openapiBody{Required: true, Content: map[string]openapiMedia{
+			op.RequestType: {Schema: &jsonSchema{Type: "string", Format: "binary"}},
+		}}
+	} else if op.Request != nil {
+		result.RequestBody = &openapiBody{Required: true, Content: map[string]openapiMedia{
+			types.ContentTypeJSON: {Schema: document.schema(reflect.TypeOf(op.Request), true)},
+		}}
+	}
+
+	// Response body
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := &openapiResponse{Description: http.StatusText(status)}
+	if op.ResponseType != "" {
+		response.Content = map[string]openapiMedia{
+			op.ResponseType: {Schema: &jsonSchema{Type: "string"}},
+		}
+	} else if op.Response != nil {
+		response.Content = map[string]openapiMedia{
+			types.ContentTypeJSON: {Schema: document.schema(reflect.TypeOf(op.Response), true)},
+		}
+	}
+	result.Responses[strconv.Itoa(status)] = response
+	result.Responses["default"] = &openapiResponse{
+		Description: "Error",
+		Content: map[string]openapiMedia{
+			schema.ProblemContentType: {Schema: &jsonSchema{Ref: "#/components/schemas/" + openapiError}},
+		},
+	}
+
+	// Return the operation
+	return result
+}
+
+// Return the schema for a type. When ref is true, named structs are added to
+// the components and referenced, otherwise they are described inline.
+func (document *openapiDocument) schema(t reflect.Type, ref bool) *jsonSchema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t, nullable = t.Elem(), true
+	}
+
+	// Types which marshal to JSON themselves
+	switch t {
+	case typeTime:
+		return &jsonSchema{Type: "string", Format: "date-time", Nullable: nullable}
+	case typeRawMessage:
+		return &jsonSchema{Nullable: nullable}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean", Nullable: nullable}
+	case reflect.String:
+		return &jsonSchema{Type: "string", Nullable: nullable}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer", Format: integerFormat(t), Nullable: nullable}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number", Nullable: nullable}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &jsonSchema{Type: "string", Format: "byte", Nullable: nullable}
+		}
+		return &jsonSchema{Type: "array", Items: document.schema(t.Elem(), ref), Nullable: nullable}
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: document.schema(t.Elem(), ref), Nullable: nullable}
+	case reflect.Struct:
+		if !ref || t.Name() == "" {
+			return document.object(t)
+		}
+		if _, exists := document.Components.Schemas[t.Name()]; !exists {
+			// Add a placeholder first, so that recursive types terminate
+			document.Components.Schemas[t.Name()] = &jsonSchema{}
+			*document.Components.Schemas[t.Name()] = *document.object(t)
+		}
+		return &jsonSchema{Ref: "#/components/schemas/" + t.Name()}
+	default:
+		return &jsonSchema{}
+	}
+}
+
+// Return the schema for the fields of a struct, including the fields of
+// embedded structs, named by their json tags
+func (document *openapiDocument) object(t reflect.Type) *jsonSchema {
+	result := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+
+		// Embedded structs without a name are flattened
+		if field.Anonymous && tag == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for name, property := range document.object(embedded).Properties {
+					result.Properties[name] = property
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		property := document.schema(field.Type, true)
+		if help := field.Tag.Get("help"); help != "" && property.Ref == "" {
+			property.Description = help
+		}
+		result.Properties[tag] = property
+	}
+	return result
+}
+
+// Return the OpenAPI format for an integer type
+func integerFormat(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int64, reflect.Uint64, reflect.Int, reflect.Uint:
+		return "int64"
+	default:
+		return "int32"
+	}
+}
@@ -0,0 +1,96 @@
+package httphandler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	httphandler "github.com/mutablelogic/go-pg/pkg/manager/httphandler"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// patterns is a router which records the patterns which are registered
+type patterns struct {
+	*http.ServeMux
+	patterns []string
+}
+
+func (p *patterns) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	p.patterns = append(p.patterns, pattern)
+	p.ServeMux.HandleFunc(pattern, handler)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_OpenAPI(t *testing.T) {
+	assert := assert.New(t)
+
+	// Handlers are registered without connecting to a database
+	router := &patterns{ServeMux: http.NewServeMux()}
+	httphandler.RegisterHandlers(router, "/api/v1", new(manager.Manager), httphandler.Options{
+		Disable: []httphandler.Resource{httphandler.ResourceDatabase},
+	})
+
+	// Get the document
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if !assert.Equal(http.StatusOK, w.Code) {
+		t.FailNow()
+	}
+	var document struct {
+		OpenAPI string                               `json:"openapi"`
+		Servers []struct{ URL string }               `json:"servers"`
+		Paths   map[string]map[string]map[string]any `json:"paths"`
+		Schemas struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if !assert.NoError(json.Unmarshal(w.Body.Bytes(), &document)) {
+		t.FailNow()
+	}
+	assert.Equal("3.0.3", document.OpenAPI)
+	assert.Equal("/api/v1", document.Servers[0].URL)
+
+	t.Run("EveryPathDescribed", func(t *testing.T) {
+		for _, pattern := range router.patterns {
+			path := strings.TrimPrefix(pattern, "/api/v1")
+			if strings.HasPrefix(path, "/openapi") {
+				continue
+			}
+			assert.Contains(document.Paths, path, "path %q is not described", path)
+		}
+	})
+
+	t.Run("DisabledResource", func(t *testing.T) {
+		assert.NotContains(document.Paths, "/database")
+		assert.Contains(document.Paths, "/role")
+	})
+
+	t.Run("Operations", func(t *testing.T) {
+		role := document.Paths["/role/{name}"]
+		assert.Contains(role, "get")
+		assert.Contains(role, "patch")
+		assert.Contains(role, "delete")
+		assert.Contains(role["patch"], "requestBody")
+		assert.Contains(document.Paths["/role"]["post"]["responses"], "201")
+		assert.Contains(document.Schemas.Schemas, "RoleMeta")
+		assert.Contains(document.Schemas.Schemas, "Problem")
+	})
+
+	t.Run("SwaggerUI", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Contains(w.Body.String(), "openapi.json")
+	})
+}
@@ -0,0 +1,124 @@
+package httphandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	// Packages
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// condition is the status and machine-readable code for a PostgreSQL error
+type condition struct {
+	status int
+	code   string
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// PostgreSQL errors which are caused by the request rather than the
+	// server, keyed by SQLSTATE
+	sqlstates = map[string]condition{
+		"22P02": {http.StatusBadRequest, "invalid_text_representation"},
+		"23502": {http.StatusBadRequest, "not_null_violation"},
+		"23503": {http.StatusConflict, "foreign_key_violation"},
+		"23505": {http.StatusConflict, "unique_violation"},
+		"23514": {http.StatusBadRequest, "check_violation"},
+		"23P01": {http.StatusConflict, "exclusion_violation"},
+		"2BP01": {http.StatusConflict, "dependent_objects_still_exist"},
+		"3D000": {http.StatusNotFound, "invalid_catalog_name"},
+		"3F000": {http.StatusNotFound, "invalid_schema_name"},
+		"40001": {http.StatusConflict, "serialization_failure"},
+		"40P01": {http.StatusConflict, "deadlock_detected"},
+		"42501": {http.StatusForbidden, "insufficient_privilege"},
+		"42601": {http.StatusBadRequest, "syntax_error"},
+		"42704": {http.StatusNotFound, "undefined_object"},
+		"42710": {http.StatusConflict, "duplicate_object"},
+		"42P01": {http.StatusNotFound, "undefined_table"},
+		"42P04": {http.StatusConflict, "duplicate_database"},
+		"42P06": {http.StatusConflict, "duplicate_schema"},
+		"42P07": {http.StatusConflict, "duplicate_table"},
+		"55006": {http.StatusConflict, "object_in_use"},
+	}
+
+	// PostgreSQL errors which are not listed above, keyed by SQLSTATE class
+	sqlclasses = map[string]condition{
+		"22": {http.StatusBadRequest, "data_exception"},
+		"23": {http.StatusConflict, "integrity_constraint_violation"},
+		"42": {http.StatusBadRequest, "syntax_error_or_access_rule_violation"},
+	}
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// problem writes an error response as application/problem+json, with the
+// status and code determined by the error
+func problem(w http.ResponseWriter, err error) error {
+	p := newProblem(err)
+	w.Header().Set(types.ContentTypeHeader, schema.ProblemContentType)
+	w.WriteHeader(p.Status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// newProblem returns the problem for an error. HTTP errors keep their status,
+// pg errors and PostgreSQL errors are mapped to their HTTP equivalents, and
+// any other error is an internal error
+func newProblem(err error) schema.Problem {
+	p := schema.Problem{Type: schema.ProblemType, Status: http.StatusInternalServerError, Detail: err.Error()}
+
+	var httpErr httpresponse.Err
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &httpErr):
+		p.Status = int(httpErr)
+		p.Detail = strings.TrimPrefix(strings.TrimPrefix(p.Detail, httpErr.Error()), ": ")
+	case errors.Is(err, context.DeadlineExceeded):
+		p.Status, p.Code = http.StatusGatewayTimeout, "timeout"
+	case errors.As(err, &pgErr):
+		p.SQLState, p.Constraint = pgErr.Code, pgErr.ConstraintName
+		p.Schema, p.Table, p.Column = pgErr.SchemaName, pgErr.TableName, pgErr.ColumnName
+		p.Detail = pgErr.Message
+		if pgErr.Detail != "" {
+			p.Detail += ": " + pgErr.Detail
+		}
+		if c, exists := sqlstates[pgErr.Code]; exists {
+			p.Status, p.Code = c.status, c.code
+		} else if c, exists := sqlclasses[pgErr.Code[:min(2, len(pgErr.Code))]]; exists {
+			p.Status, p.Code = c.status, c.code
+		}
+	case errors.Is(err, pg.ErrNotFound):
+		p.Status, p.Code = http.StatusNotFound, "not_found"
+	case errors.Is(err, pg.ErrBadParameter):
+		p.Status, p.Code = http.StatusBadRequest, "bad_parameter"
+	case errors.Is(err, pg.ErrNotImplemented):
+		p.Status, p.Code = http.StatusNotImplemented, "not_implemented"
+	case errors.Is(err, pg.ErrNotAvailable):
+		p.Status, p.Code = http.StatusNotImplemented, "not_available"
+	case errors.Is(err, pg.ErrReadOnly):
+		p.Status, p.Code = http.StatusConflict, "read_only"
+	case errors.Is(err, pg.ErrConflict):
+		p.Status, p.Code = http.StatusConflict, "conflict"
+	}
+
+	// The title is the status, and the code defaults to the status
+	p.Title = http.StatusText(p.Status)
+	if p.Code == "" {
+		p.Code = strings.ReplaceAll(strings.ToLower(p.Title), " ", "_")
+	}
+	return p
+}
@@ -0,0 +1,53 @@
+package httphandler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	httphandler "github.com/mutablelogic/go-pg/pkg/manager/httphandler"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_RegisterHandlers_Problem(t *testing.T) {
+	assert := assert.New(t)
+
+	// The version handler does not use the manager
+	router := http.NewServeMux()
+	httphandler.RegisterHandlers(router, "/api/v1", new(manager.Manager), httphandler.Options{
+		Resources: []httphandler.Resource{httphandler.ResourceOpenAPI},
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/version", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(schema.ProblemContentType, w.Header().Get("Content-Type"))
+
+		var problem schema.Problem
+		assert.NoError(json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(schema.ProblemType, problem.Type)
+		assert.Equal("Method Not Allowed", problem.Title)
+		assert.Equal(http.StatusMethodNotAllowed, problem.Status)
+		assert.Equal("method_not_allowed", problem.Code)
+		assert.Equal(http.MethodDelete, problem.Detail)
+		assert.Empty(problem.SQLState)
+	})
+
+	t.Run("OpenAPI", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Contains(w.Body.String(), schema.ProblemContentType)
+		assert.Contains(w.Body.String(), `"sqlstate"`)
+	})
+}
@@ -16,7 +16,7 @@ import (
 
 // RegisterReplicationSlotHandlers registers HTTP handlers for replication slot
 // CRUD operations on the provided router with the given path prefix.
-func RegisterReplicationSlotHandlers(router *http.ServeMux, prefix string, manager *manager.Manager) {
+func RegisterReplicationSlotHandlers(router Router, prefix string, manager *manager.Manager) {
 	if manager == nil {
 		panic("manager is nil")
 	}
@@ -27,18 +27,18 @@ func RegisterReplicationSlotHandlers(router *http.ServeMux, prefix string, manag
 		case http.MethodPost:
 			_ = replicationSlotCreate(w, r, manager)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
 	router.HandleFunc(joinPath(prefix, "replicationslot/{name}"), func(w http.ResponseWriter, r *http.Request) {
 		name := r.PathValue("name")
 		if name == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid slot name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid slot name"))
 			return
 		}
 		if strings.HasPrefix(name, "pg_") {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("slot name cannot start with reserved prefix 'pg_'"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("slot name cannot start with reserved prefix 'pg_'"))
 			return
 		}
 
@@ -48,7 +48,7 @@ func RegisterReplicationSlotHandlers(router *http.ServeMux, prefix string, manag
 		case http.MethodDelete:
 			_ = replicationSlotDelete(w, r, manager, name)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 }
@@ -59,7 +59,7 @@ func RegisterReplicationSlotHandlers(router *http.ServeMux, prefix string, manag
 func replicationSlotGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
 	slot, err := manager.GetReplicationSlot(r.Context(), name)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -70,13 +70,13 @@ func replicationSlotList(w http.ResponseWriter, r *http.Request, manager *manage
 	// Parse request
 	var req schema.ReplicationSlotListRequest
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// List the slots
 	response, err := manager.ListReplicationSlots(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -87,13 +87,13 @@ func replicationSlotCreate(w http.ResponseWriter, r *http.Request, manager *mana
 	// Parse request
 	var req schema.ReplicationSlotMeta
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Create the slot
 	response, err := manager.CreateReplicationSlot(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -104,7 +104,7 @@ func replicationSlotDelete(w http.ResponseWriter, r *http.Request, manager *mana
 	// Delete the slot
 	_, err := manager.DeleteReplicationSlot(r.Context(), name)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
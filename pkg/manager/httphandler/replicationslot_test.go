@@ -21,11 +21,8 @@ import (
 func Test_ReplicationSlot_RegisterHandlers(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	t.Run("PanicOnNilManager", func(t *testing.T) {
 		router := http.NewServeMux()
@@ -45,11 +42,8 @@ func Test_ReplicationSlot_RegisterHandlers(t *testing.T) {
 func Test_ReplicationSlot_List(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterReplicationSlotHandlers(router, "/api", manager.Manager)
@@ -83,11 +77,8 @@ func Test_ReplicationSlot_List(t *testing.T) {
 func Test_ReplicationSlot_Get(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterReplicationSlotHandlers(router, "/api", manager.Manager)
@@ -123,11 +114,8 @@ func Test_ReplicationSlot_Get(t *testing.T) {
 func Test_ReplicationSlot_Create(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterReplicationSlotHandlers(router, "/api", manager.Manager)
@@ -249,8 +237,8 @@ func Test_ReplicationSlot_Create(t *testing.T) {
 		w2 := httptest.NewRecorder()
 
 		router.ServeHTTP(w2, req2)
-		// Duplicate slot returns 500 (PostgreSQL error)
-		assert.Equal(http.StatusInternalServerError, w2.Code)
+		// Duplicate slot returns 409 (PostgreSQL error)
+		assert.Equal(http.StatusConflict, w2.Code)
 
 		// Cleanup
 		t.Cleanup(func() {
@@ -262,11 +250,8 @@ func Test_ReplicationSlot_Create(t *testing.T) {
 func Test_ReplicationSlot_Delete(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterReplicationSlotHandlers(router, "/api", manager.Manager)
@@ -317,11 +302,8 @@ func Test_ReplicationSlot_Delete(t *testing.T) {
 func Test_ReplicationSlot_RoundTrip(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterReplicationSlotHandlers(router, "/api", manager.Manager)
@@ -16,7 +16,7 @@ import (
 
 // RegisterRoleHandlers registers HTTP handlers for role CRUD operations
 // on the provided router with the given path prefix. The manager must be non-nil.
-func RegisterRoleHandlers(router *http.ServeMux, prefix string, manager *manager.Manager) {
+func RegisterRoleHandlers(router Router, prefix string, manager *manager.Manager) {
 	if manager == nil {
 		panic("manager is nil")
 	}
@@ -27,18 +27,18 @@ func RegisterRoleHandlers(router *http.ServeMux, prefix string, manager *manager
 		case http.MethodPost:
 			_ = roleCreate(w, r, manager)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
 	router.HandleFunc(joinPath(prefix, "role/{name}"), func(w http.ResponseWriter, r *http.Request) {
 		name := r.PathValue("name")
 		if name == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid role name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid role name"))
 			return
 		}
 		if strings.HasPrefix(name, "pg_") {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("role name cannot start with reserved prefix 'pg_'"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("role name cannot start with reserved prefix 'pg_'"))
 			return
 		}
 
@@ -50,7 +50,7 @@ func RegisterRoleHandlers(router *http.ServeMux, prefix string, manager *manager
 		case http.MethodDelete:
 			_ = roleDelete(w, r, manager, name)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 }
@@ -61,7 +61,7 @@ func RegisterRoleHandlers(router *http.ServeMux, prefix string, manager *manager
 func roleGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
 	role, err := manager.GetRole(r.Context(), name)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -72,13 +72,13 @@ func roleList(w http.ResponseWriter, r *http.Request, manager *manager.Manager)
 	// Parse request
 	var req schema.RoleListRequest
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// List the roles
 	response, err := manager.ListRoles(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -89,13 +89,13 @@ func roleCreate(w http.ResponseWriter, r *http.Request, manager *manager.Manager
 	// Parse request
 	var req schema.RoleMeta
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Create the role
 	response, err := manager.CreateRole(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -105,7 +105,7 @@ func roleCreate(w http.ResponseWriter, r *http.Request, manager *manager.Manager
 func roleDelete(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
 	_, err := manager.DeleteRole(r.Context(), name)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -116,13 +116,13 @@ func roleUpdate(w http.ResponseWriter, r *http.Request, manager *manager.Manager
 	// Parse request
 	var req schema.RoleMeta
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Perform update
 	role, err := manager.UpdateRole(r.Context(), name, req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
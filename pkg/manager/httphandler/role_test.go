@@ -20,11 +20,8 @@ import (
 func Test_Role_RegisterHandlers(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	t.Run("PanicOnNilManager", func(t *testing.T) {
 		router := http.NewServeMux()
@@ -44,11 +41,8 @@ func Test_Role_RegisterHandlers(t *testing.T) {
 func Test_Role_List(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterRoleHandlers(router, "/api", manager.Manager)
@@ -81,11 +75,8 @@ func Test_Role_List(t *testing.T) {
 func Test_Role_Get(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterRoleHandlers(router, "/api", manager.Manager)
@@ -136,11 +127,8 @@ func Test_Role_Get(t *testing.T) {
 func Test_Role_Create(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterRoleHandlers(router, "/api", manager.Manager)
@@ -215,8 +203,8 @@ func Test_Role_Create(t *testing.T) {
 		w2 := httptest.NewRecorder()
 
 		router.ServeHTTP(w2, req2)
-		// Duplicate role returns 500 (PostgreSQL error)
-		assert.Equal(http.StatusInternalServerError, w2.Code)
+		// Duplicate role returns 409 (PostgreSQL error)
+		assert.Equal(http.StatusConflict, w2.Code)
 
 		// Cleanup
 		t.Cleanup(func() {
@@ -228,11 +216,8 @@ func Test_Role_Create(t *testing.T) {
 func Test_Role_Delete(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterRoleHandlers(router, "/api", manager.Manager)
@@ -277,11 +262,8 @@ func Test_Role_Delete(t *testing.T) {
 func Test_Role_Update(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterRoleHandlers(router, "/api", manager.Manager)
@@ -16,7 +16,7 @@ import (
 
 // RegisterSchemaHandlers registers HTTP handlers for schema CRUD operations
 // on the provided router with the given path prefix. The manager must be non-nil.
-func RegisterSchemaHandlers(router *http.ServeMux, prefix string, manager *manager.Manager) {
+func RegisterSchemaHandlers(router Router, prefix string, manager *manager.Manager) {
 	if manager == nil {
 		panic("manager is nil")
 	}
@@ -27,7 +27,33 @@ func RegisterSchemaHandlers(router *http.ServeMux, prefix string, manager *manag
 		case http.MethodGet:
 			_ = schemaList(w, r, manager, nil)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// List schema sizes across all databases
+	router.HandleFunc(joinPath(prefix, "schemasize"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = schemaRollupList(w, r, manager, nil)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// List schema sizes in a specific database
+	router.HandleFunc(joinPath(prefix, "schemasize/{database}"), func(w http.ResponseWriter, r *http.Request) {
+		database := r.PathValue("database")
+		if database == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = schemaRollupList(w, r, manager, &database)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
@@ -35,7 +61,7 @@ func RegisterSchemaHandlers(router *http.ServeMux, prefix string, manager *manag
 	router.HandleFunc(joinPath(prefix, "schema/{database}"), func(w http.ResponseWriter, r *http.Request) {
 		database := r.PathValue("database")
 		if database == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
 			return
 		}
 
@@ -45,7 +71,7 @@ func RegisterSchemaHandlers(router *http.ServeMux, prefix string, manager *manag
 		case http.MethodPost:
 			_ = schemaCreate(w, r, manager, database)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
@@ -53,16 +79,16 @@ func RegisterSchemaHandlers(router *http.ServeMux, prefix string, manager *manag
 	router.HandleFunc(joinPath(prefix, "schema/{database}/{namespace}"), func(w http.ResponseWriter, r *http.Request) {
 		database := r.PathValue("database")
 		if database == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
 			return
 		}
 		namespace := r.PathValue("namespace")
 		if namespace == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid schema name"))
 			return
 		}
 		if strings.HasPrefix(namespace, "pg_") {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("schema name cannot start with reserved prefix 'pg_'"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("schema name cannot start with reserved prefix 'pg_'"))
 			return
 		}
 
@@ -74,7 +100,7 @@ func RegisterSchemaHandlers(router *http.ServeMux, prefix string, manager *manag
 		case http.MethodDelete:
 			_ = schemaDelete(w, r, manager, database, namespace)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 }
@@ -85,7 +111,7 @@ func RegisterSchemaHandlers(router *http.ServeMux, prefix string, manager *manag
 func schemaGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager, database, namespace string) error {
 	s, err := manager.GetSchema(r.Context(), database, namespace)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -96,14 +122,32 @@ func schemaList(w http.ResponseWriter, r *http.Request, manager *manager.Manager
 	// Parse request
 	var req schema.SchemaListRequest
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 	req.Database = database
 
 	// List the schemas
 	response, err := manager.ListSchemas(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func schemaRollupList(w http.ResponseWriter, r *http.Request, manager *manager.Manager, database *string) error {
+	// Parse request
+	var req schema.SchemaListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+	req.Database = database
+
+	// List the schema sizes
+	response, err := manager.ListSchemaRollups(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
 	}
 
 	// Return success
@@ -114,13 +158,13 @@ func schemaCreate(w http.ResponseWriter, r *http.Request, manager *manager.Manag
 	// Parse request
 	var req schema.SchemaMeta
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Create the schema
 	response, err := manager.CreateSchema(r.Context(), database, req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -133,13 +177,13 @@ func schemaDelete(w http.ResponseWriter, r *http.Request, manager *manager.Manag
 		Force bool `json:"force,omitempty" help:"Force delete with CASCADE"`
 	}
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Delete the schema
 	_, err := manager.DeleteSchema(r.Context(), database, namespace, req.Force)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -150,13 +194,13 @@ func schemaUpdate(w http.ResponseWriter, r *http.Request, manager *manager.Manag
 	// Parse request
 	var req schema.SchemaMeta
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Perform update
 	s, err := manager.UpdateSchema(r.Context(), database, namespace, req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -20,11 +20,8 @@ import (
 func Test_Schema_RegisterHandlers(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	t.Run("PanicOnNilManager", func(t *testing.T) {
 		router := http.NewServeMux()
@@ -44,11 +41,8 @@ func Test_Schema_RegisterHandlers(t *testing.T) {
 func Test_Schema_List(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterSchemaHandlers(router, "/api", manager.Manager)
@@ -101,11 +95,8 @@ func Test_Schema_List(t *testing.T) {
 func Test_Schema_Get(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterSchemaHandlers(router, "/api", manager.Manager)
@@ -157,11 +148,8 @@ func Test_Schema_Get(t *testing.T) {
 func Test_Schema_Create(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterSchemaHandlers(router, "/api", manager.Manager)
@@ -284,8 +272,8 @@ func Test_Schema_Create(t *testing.T) {
 		w2 := httptest.NewRecorder()
 
 		router.ServeHTTP(w2, req2)
-		// Duplicate schema returns 500 (PostgreSQL error)
-		assert.Equal(http.StatusInternalServerError, w2.Code)
+		// Duplicate schema returns 409 (PostgreSQL error)
+		assert.Equal(http.StatusConflict, w2.Code)
 
 		// Cleanup
 		t.Cleanup(func() {
@@ -297,11 +285,8 @@ func Test_Schema_Create(t *testing.T) {
 func Test_Schema_Delete(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterSchemaHandlers(router, "/api", manager.Manager)
@@ -364,11 +349,8 @@ func Test_Schema_Delete(t *testing.T) {
 func Test_Schema_Update(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httprequest.RegisterSchemaHandlers(router, "/api", manager.Manager)
@@ -462,7 +444,7 @@ func Test_Schema_Update(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		// PostgreSQL returns an error for reserved prefix names
-		assert.Equal(http.StatusInternalServerError, w.Code)
+		assert.Equal(http.StatusBadRequest, w.Code)
 
 		// Cleanup
 		t.Cleanup(func() {
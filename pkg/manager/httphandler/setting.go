@@ -10,12 +10,20 @@ import (
 	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
 )
 
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Scope of a setting update which is validated and written with ALTER SYSTEM
+	settingScopeSystem = "system"
+)
+
 ///////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
 // RegisterSettingHandlers registers HTTP handlers for server setting operations
 // on the provided router with the given path prefix. The manager must be non-nil.
-func RegisterSettingHandlers(router *http.ServeMux, prefix string, manager *manager.Manager) {
+func RegisterSettingHandlers(router Router, prefix string, manager *manager.Manager) {
 	if manager == nil {
 		panic("manager is nil")
 	}
@@ -26,7 +34,7 @@ func RegisterSettingHandlers(router *http.ServeMux, prefix string, manager *mana
 		case http.MethodGet:
 			_ = settingList(w, r, manager)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
@@ -36,7 +44,47 @@ func RegisterSettingHandlers(router *http.ServeMux, prefix string, manager *mana
 		case http.MethodGet:
 			_ = settingCategoryList(w, r, manager)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// List setting history
+	router.HandleFunc(joinPath(prefix, "setting/history"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = settingHistoryList(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// List settings which differ from the boot value or require a restart
+	router.HandleFunc(joinPath(prefix, "setting/pending"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = settingPendingList(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Compare settings with desired values
+	router.HandleFunc(joinPath(prefix, "setting/diff"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			_ = settingDiff(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Reload the configuration files
+	router.HandleFunc(joinPath(prefix, "setting/reload"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			_ = settingReload(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
@@ -44,7 +92,7 @@ func RegisterSettingHandlers(router *http.ServeMux, prefix string, manager *mana
 	router.HandleFunc(joinPath(prefix, "setting/{name}"), func(w http.ResponseWriter, r *http.Request) {
 		name := r.PathValue("name")
 		if name == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid setting name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid setting name"))
 			return
 		}
 
@@ -54,7 +102,7 @@ func RegisterSettingHandlers(router *http.ServeMux, prefix string, manager *mana
 		case http.MethodPatch:
 			_ = settingUpdate(w, r, manager, name)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 }
@@ -65,7 +113,7 @@ func RegisterSettingHandlers(router *http.ServeMux, prefix string, manager *mana
 func settingGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
 	setting, err := manager.GetSetting(r.Context(), name)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -76,13 +124,47 @@ func settingList(w http.ResponseWriter, r *http.Request, manager *manager.Manage
 	// Parse request
 	var req schema.SettingListRequest
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// List the settings
 	response, err := manager.ListSettings(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func settingPendingList(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.SettingListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+
+	// List the pending settings
+	response, err := manager.ListPendingSettings(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func settingDiff(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request body, which is the desired values by setting name
+	var req map[string]string
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Compare the settings
+	response, err := manager.DiffSettings(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
 	}
 
 	// Return success
@@ -93,7 +175,40 @@ func settingCategoryList(w http.ResponseWriter, r *http.Request, manager *manage
 	// List the setting categories
 	response, err := manager.ListSettingCategories(r.Context())
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func settingHistoryList(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Parse request
+	var req schema.SettingHistoryListRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+
+	// List the setting history
+	response, err := manager.ListSettingHistory(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func settingReload(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Reload the configuration
+	if err := manager.ReloadConfig(r.Context()); err != nil {
+		return problem(w, err)
+	}
+
+	// Return the server, which includes when the configuration was loaded
+	response, err := manager.GetServer(r.Context())
+	if err != nil {
+		return problem(w, err)
 	}
 
 	// Return success
@@ -101,30 +216,45 @@ func settingCategoryList(w http.ResponseWriter, r *http.Request, manager *manage
 }
 
 func settingUpdate(w http.ResponseWriter, r *http.Request, manager *manager.Manager, name string) error {
-	// Parse query for reload option
+	// Parse query for reload and scope options
 	var opts struct {
-		Reload bool `json:"reload,omitempty" help:"Reload config after update"`
+		Reload bool   `json:"reload,omitempty" help:"Reload config after update"`
+		Scope  string `json:"scope,omitempty" help:"Scope of the update (system)"`
 	}
 	if err := httprequest.Query(r.URL.Query(), &opts); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Parse request body
 	var req schema.SettingMeta
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
+	}
+
+	// Validate and write the setting with ALTER SYSTEM
+	switch opts.Scope {
+	case "":
+		// Update the setting below
+	case settingScopeSystem:
+		response, err := manager.SetSystemSetting(r.Context(), name, req, opts.Reload)
+		if err != nil {
+			return problem(w, err)
+		}
+		return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+	default:
+		return problem(w, httpresponse.ErrBadRequest.Withf("invalid scope %q", opts.Scope))
 	}
 
 	// Update the setting
 	response, err := manager.UpdateSetting(r.Context(), name, req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Reload config if requested and the setting context supports it
 	if opts.Reload && response.Context == "sighup" {
 		if err := manager.ReloadConfig(r.Context()); err != nil {
-			return httpresponse.Error(w, httperr(err))
+			return problem(w, err)
 		}
 	}
 
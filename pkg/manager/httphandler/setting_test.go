@@ -21,11 +21,8 @@ import (
 func Test_Setting_RegisterHandlers(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	t.Run("PanicOnNilManager", func(t *testing.T) {
 		router := http.NewServeMux()
@@ -45,11 +42,8 @@ func Test_Setting_RegisterHandlers(t *testing.T) {
 func Test_Setting_List(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterSettingHandlers(router, "/api", manager.Manager)
@@ -137,11 +131,8 @@ func Test_Setting_List(t *testing.T) {
 func Test_Setting_CategoryList(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterSettingHandlers(router, "/api", manager.Manager)
@@ -175,11 +166,8 @@ func Test_Setting_CategoryList(t *testing.T) {
 func Test_Setting_Get(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterSettingHandlers(router, "/api", manager.Manager)
@@ -213,11 +201,8 @@ func Test_Setting_Get(t *testing.T) {
 func Test_Setting_Update(t *testing.T) {
 	assert := assert.New(t)
 
-	// Create manager with test container
-	manager := test.NewManager(t)
-	t.Cleanup(func() {
-		manager.Close()
-	})
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
 
 	router := http.NewServeMux()
 	httphandler.RegisterSettingHandlers(router, "/api", manager.Manager)
@@ -283,3 +268,37 @@ func Test_Setting_Update(t *testing.T) {
 		assert.Equal("log_min_duration_statement", setting.Name)
 	})
 }
+
+func Test_Setting_Reload(t *testing.T) {
+	assert := assert.New(t)
+
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
+
+	router := http.NewServeMux()
+	httphandler.RegisterSettingHandlers(router, "/api", manager.Manager)
+
+	t.Run("Reload", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/setting/reload", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+
+		// Parse response
+		var server schema.Server
+		err := json.Unmarshal(w.Body.Bytes(), &server)
+		assert.NoError(err)
+		assert.False(server.ConfigLoaded.IsZero())
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/setting/reload", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusMethodNotAllowed, w.Code)
+	})
+}
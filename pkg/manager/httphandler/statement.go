@@ -15,7 +15,7 @@ import (
 
 // RegisterStatementHandlers registers HTTP handlers for pg_stat_statements operations
 // on the provided router with the given path prefix. The manager must be non-nil.
-func RegisterStatementHandlers(router *http.ServeMux, prefix string, manager *manager.Manager) {
+func RegisterStatementHandlers(router Router, prefix string, manager *manager.Manager) {
 	if manager == nil {
 		panic("manager is nil")
 	}
@@ -28,7 +28,7 @@ func RegisterStatementHandlers(router *http.ServeMux, prefix string, manager *ma
 		case http.MethodDelete:
 			_ = statementReset(w, r, manager)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 }
@@ -40,13 +40,13 @@ func statementList(w http.ResponseWriter, r *http.Request, manager *manager.Mana
 	// Parse request
 	var req schema.StatementListRequest
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// List the statements
 	response, err := manager.ListStatements(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -56,7 +56,7 @@ func statementList(w http.ResponseWriter, r *http.Request, manager *manager.Mana
 func statementReset(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
 	// Reset the statements
 	if err := manager.ResetStatements(r.Context()); err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success (no content)
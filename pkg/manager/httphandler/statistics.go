@@ -0,0 +1,57 @@
+package httphandler
+
+import (
+	"net/http"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterStatisticsHandlers registers HTTP handlers for resetting the
+// statistics of a database, a table or statements on the provided router
+// with the given path prefix. The manager must be non-nil.
+func RegisterStatisticsHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	// Reset statistics
+	router.HandleFunc(joinPath(prefix, "statistics/reset"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			_ = statisticsReset(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func statisticsReset(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// The statistics cannot be recovered, so the reset must be confirmed
+	if r.URL.Query().Get("confirm") != "true" {
+		return problem(w, httpresponse.ErrBadRequest.With("statistics cannot be recovered once reset: set confirm=true"))
+	}
+
+	// Parse request
+	var req schema.StatisticsTarget
+	if err := httprequest.Read(r, &req); err != nil {
+		return problem(w, err)
+	}
+
+	// Reset the statistics
+	if err := manager.ResetStatistics(r.Context(), req); err != nil {
+		return problem(w, err)
+	}
+
+	// Return success (no content)
+	return httpresponse.Empty(w, http.StatusNoContent)
+}
@@ -16,7 +16,7 @@ import (
 
 // RegisterTablespaceHandlers registers HTTP handlers for tablespace CRUD operations
 // on the provided router with the given path prefix. The manager must be non-nil.
-func RegisterTablespaceHandlers(router *http.ServeMux, prefix string, manager *manager.Manager) {
+func RegisterTablespaceHandlers(router Router, prefix string, manager *manager.Manager) {
 	if manager == nil {
 		panic("manager is nil")
 	}
@@ -27,18 +27,27 @@ func RegisterTablespaceHandlers(router *http.ServeMux, prefix string, manager *m
 		case http.MethodPost:
 			_ = tablespaceCreate(w, r, manager)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	router.HandleFunc(joinPath(prefix, "tablespace/stat"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = tablespaceStatList(w, r, manager)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 
 	router.HandleFunc(joinPath(prefix, "tablespace/{name}"), func(w http.ResponseWriter, r *http.Request) {
 		name := r.PathValue("name")
 		if name == "" {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("missing or invalid tablespace name"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid tablespace name"))
 			return
 		}
 		if strings.HasPrefix(name, "pg_") {
-			_ = httpresponse.Error(w, httpresponse.ErrBadRequest.With("tablespace name cannot start with reserved prefix 'pg_'"))
+			_ = problem(w, httpresponse.ErrBadRequest.With("tablespace name cannot start with reserved prefix 'pg_'"))
 			return
 		}
 
@@ -50,7 +59,7 @@ func RegisterTablespaceHandlers(router *http.ServeMux, prefix string, manager *m
 		case http.MethodDelete:
 			_ = tablespaceDelete(w, r, manager, name)
 		default:
-			_ = httpresponse.Error(w, httpresponse.Err(http.StatusMethodNotAllowed), r.Method)
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
 		}
 	})
 }
@@ -62,13 +71,24 @@ func tablespaceList(w http.ResponseWriter, r *http.Request, manager *manager.Man
 	// Parse request
 	var req schema.TablespaceListRequest
 	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// List the tablespaces
 	response, err := manager.ListTablespaces(r.Context(), req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
+
+func tablespaceStatList(w http.ResponseWriter, r *http.Request, manager *manager.Manager) error {
+	// Read the space on the filesystem of each tablespace
+	response, err := manager.ListTablespaceStats(r.Context())
+	if err != nil {
+		return problem(w, err)
 	}
 
 	// Return success
@@ -82,13 +102,13 @@ func tablespaceCreate(w http.ResponseWriter, r *http.Request, manager *manager.M
 		schema.TablespaceMeta
 	}
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Create the tablespace
 	response, err := manager.CreateTablespace(r.Context(), req.TablespaceMeta, req.Location)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -99,7 +119,7 @@ func tablespaceGet(w http.ResponseWriter, r *http.Request, manager *manager.Mana
 	// Get the tablespace
 	response, err := manager.GetTablespace(r.Context(), name)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -110,7 +130,7 @@ func tablespaceDelete(w http.ResponseWriter, r *http.Request, manager *manager.M
 	// Delete the tablespace
 	_, err := manager.DeleteTablespace(r.Context(), name)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -121,13 +141,13 @@ func tablespaceUpdate(w http.ResponseWriter, r *http.Request, manager *manager.M
 	// Parse request
 	var req schema.TablespaceMeta
 	if err := httprequest.Read(r, &req); err != nil {
-		return httpresponse.Error(w, err)
+		return problem(w, err)
 	}
 
 	// Update the tablespace
 	response, err := manager.UpdateTablespace(r.Context(), name, req)
 	if err != nil {
-		return httpresponse.Error(w, httperr(err))
+		return problem(w, err)
 	}
 
 	// Return success
@@ -0,0 +1,72 @@
+package httphandler
+
+import (
+	"net/http"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RegisterTempUsageHandlers registers HTTP handlers for the temporary files
+// written by each database and statement on the provided router with the
+// given path prefix. The manager must be non-nil.
+func RegisterTempUsageHandlers(router Router, prefix string, manager *manager.Manager) {
+	if manager == nil {
+		panic("manager is nil")
+	}
+
+	// Get the temporary files written across all databases
+	router.HandleFunc(joinPath(prefix, "tempusage"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = tempUsageGet(w, r, manager, nil)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+
+	// Get the temporary files written in a specific database
+	router.HandleFunc(joinPath(prefix, "tempusage/{database}"), func(w http.ResponseWriter, r *http.Request) {
+		database := r.PathValue("database")
+		if database == "" {
+			_ = problem(w, httpresponse.ErrBadRequest.With("missing or invalid database name"))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = tempUsageGet(w, r, manager, &database)
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func tempUsageGet(w http.ResponseWriter, r *http.Request, manager *manager.Manager, database *string) error {
+	// Parse request
+	var req schema.TempUsageRequest
+	if err := httprequest.Query(r.URL.Query(), &req); err != nil {
+		return problem(w, err)
+	}
+	if database != nil {
+		req.Database = database
+	}
+
+	// Get the temporary files
+	response, err := manager.GetTempUsage(r.Context(), req)
+	if err != nil {
+		return problem(w, err)
+	}
+
+	// Return success
+	return httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), response)
+}
@@ -0,0 +1,160 @@
+package httphandler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	version "github.com/mutablelogic/go-pg/pkg/version"
+	httprequest "github.com/mutablelogic/go-server/pkg/httprequest"
+	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Deprecation marks a version of the API, or a path within a version, as
+// deprecated. Responses include the Deprecation header, and the Sunset and
+// Link headers when set, so that clients can migrate before it is removed.
+type Deprecation struct {
+	Version uint      // Version which is deprecated
+	Path    string    // Path relative to the prefix, or empty for all paths
+	Date    time.Time // When the version or path was deprecated, or zero
+	Sunset  time.Time // When the version or path will be removed, or zero
+	Link    string    // URL which documents the deprecation, or empty
+}
+
+// versioned is a router which negotiates the version of the API for each
+// request, and sets the version and deprecation headers on the response
+type versioned struct {
+	Router
+	prefix       string
+	versions     []uint
+	fallback     uint
+	deprecations []Deprecation
+}
+
+// versionKey is the context key for the negotiated version
+type versionKey struct{}
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// newVersioned returns a router which serves the versions in the options. The
+// version in a request is read from the Api-Version header, then from a "v1"
+// segment in the prefix if that version is supported, and is otherwise the
+// latest supported version.
+func newVersioned(router Router, prefix string, opts Options) *versioned {
+	versions := slices.Clone(opts.Versions)
+	if len(versions) == 0 {
+		versions = []uint{schema.APIVersion}
+	}
+	fallback := slices.Max(versions)
+	for _, segment := range strings.Split(prefix, "/") {
+		if version, ok := parseVersion(segment); ok && strings.HasPrefix(segment, "v") && slices.Contains(versions, version) {
+			fallback = version
+		}
+	}
+	return &versioned{router, prefix, versions, fallback, opts.Deprecations}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Version returns the version of the API negotiated for the request, so that
+// handlers can return the response shape which the client expects
+func Version(r *http.Request) uint {
+	if version, ok := r.Context().Value(versionKey{}).(uint); ok {
+		return version
+	}
+	return schema.APIVersion
+}
+
+// HandleFunc registers a handler which refuses requests for versions which
+// are not supported
+func (v *versioned) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	v.Router.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		version := v.fallback
+		if value := r.Header.Get(schema.APIVersionHeader); value != "" {
+			parsed, ok := parseVersion(value)
+			if !ok || !slices.Contains(v.versions, parsed) {
+				_ = problem(w, httpresponse.ErrBadRequest.Withf("unsupported API version %q, the server supports versions %v", value, v.versions))
+				return
+			}
+			version = parsed
+		}
+
+		// Set the version and deprecation headers
+		w.Header().Set(schema.APIVersionHeader, fmt.Sprint(version))
+		for _, deprecation := range v.deprecations {
+			if deprecation.Version != version {
+				continue
+			}
+			if deprecation.Path != "" && !strings.HasPrefix(r.URL.Path, joinPath(v.prefix, deprecation.Path)) {
+				continue
+			}
+			deprecation.setHeaders(w.Header())
+			break
+		}
+
+		// Serve the request with the version in the context
+		handler(w, r.WithContext(context.WithValue(r.Context(), versionKey{}, version)))
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// registerVersionHandler registers the handler which reports the version of
+// the manager and the versions of the API it serves. It is not versioned,
+// so that a client can check it is compatible whichever version it requests.
+func registerVersionHandler(router Router, prefix string, opts Options) {
+	versions := slices.Clone(opts.Versions)
+	if len(versions) == 0 {
+		versions = []uint{schema.APIVersion}
+	}
+	slices.Sort(versions)
+	router.HandleFunc(joinPath(prefix, "version"), func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = httpresponse.JSON(w, http.StatusOK, httprequest.Indent(r), schema.Version{
+				Name:        version.ExecName(),
+				Version:     version.Version(),
+				Commit:      version.GitHash,
+				APIVersions: versions,
+			})
+		default:
+			_ = problem(w, httpresponse.Err(http.StatusMethodNotAllowed).With(r.Method))
+		}
+	})
+}
+
+func (d Deprecation) setHeaders(header http.Header) {
+	if d.Date.IsZero() {
+		header.Set("Deprecation", "true")
+	} else {
+		header.Set("Deprecation", fmt.Sprintf("@%d", d.Date.Unix()))
+	}
+	if !d.Sunset.IsZero() {
+		header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Link != "" {
+		header.Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", d.Link))
+	}
+}
+
+// parseVersion parses a version such as "1" or "v1"
+func parseVersion(value string) (uint, bool) {
+	value = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "v")
+	version, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || version == 0 {
+		return 0, false
+	}
+	return uint(version), true
+}
@@ -0,0 +1,86 @@
+package httphandler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	// Packages
+	httprequest "github.com/mutablelogic/go-pg/pkg/manager/httphandler"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	test "github.com/mutablelogic/go-pg/pkg/test"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Version(t *testing.T) {
+	assert := assert.New(t)
+
+	// Use the manager shared by the tests in the package
+	manager := test.SharedManager(t)
+
+	sunset := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	router := http.NewServeMux()
+	httprequest.RegisterHandlers(router, "/api/v1", manager.Manager, httprequest.Options{
+		Resources: []httprequest.Resource{httprequest.ResourceServer},
+		Versions:  []uint{1, 2},
+		Deprecations: []httprequest.Deprecation{
+			{Version: 1, Sunset: sunset, Link: "https://example.com/deprecation"},
+		},
+	})
+
+	t.Run("PathVersion", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/server", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("1", w.Header().Get("Api-Version"))
+		assert.Equal("true", w.Header().Get("Deprecation"))
+		assert.Equal(sunset.Format(http.TimeFormat), w.Header().Get("Sunset"))
+		assert.Contains(w.Header().Get("Link"), `rel="deprecation"`)
+	})
+
+	t.Run("HeaderVersion", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/server", nil)
+		req.Header.Set("Api-Version", "2")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("2", w.Header().Get("Api-Version"))
+		assert.Empty(w.Header().Get("Deprecation"))
+	})
+
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/server", nil)
+		req.Header.Set("Api-Version", "3")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusBadRequest, w.Code)
+		assert.Equal(schema.ProblemContentType, w.Header().Get("Content-Type"))
+
+		var problem schema.Problem
+		assert.NoError(json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(http.StatusBadRequest, problem.Status)
+		assert.Equal("bad_request", problem.Code)
+		assert.Contains(problem.Detail, "[1 2]")
+	})
+
+	t.Run("ServerVersion", func(t *testing.T) {
+		// The version is reported for any requested version of the API
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/version", nil)
+		req.Header.Set("Api-Version", "3")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(http.StatusOK, w.Code)
+
+		var version schema.Version
+		assert.NoError(json.Unmarshal(w.Body.Bytes(), &version))
+		assert.Equal([]uint{1, 2}, version.APIVersions)
+		assert.True(version.Supports(2))
+		assert.False(version.Supports(3))
+	})
+}
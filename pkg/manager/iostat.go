@@ -0,0 +1,93 @@
+package manager
+
+import (
+	"context"
+	"strings"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - IO STATISTICS
+
+// GetIOStats returns the number of blocks of each table and index which were
+// read from disk or found in the buffer cache, with the hit ratios, across all
+// databases. Within each database, the tables and indexes with the most
+// blocks read from disk are returned first. If Database is specified in the
+// request, only tables and indexes from that database are returned.
+func (manager *Manager) GetIOStats(ctx context.Context, req schema.IOStatListRequest) (*schema.IOStatList, error) {
+	var list schema.IOStatList
+	var offset, limit uint64
+
+	// Set limit lower if request limit is lower
+	limit = schema.IOStatListLimit
+	if req.Limit != nil && types.PtrUint64(req.Limit) < limit {
+		limit = types.PtrUint64(req.Limit)
+	}
+
+	// Allocate the body with capacity
+	list.Body = make([]schema.IOStat, 0, limit)
+
+	// Iterate through all the databases
+	if _, err := manager.withDatabases(ctx, func(database *schema.Database) error {
+		// Filter by database
+		if name := strings.TrimSpace(types.PtrString(req.Database)); name != "" && name != database.Name {
+			return nil
+		}
+
+		// Iterate through the statistics
+		count, err := manager.withIOStats(ctx, database.Name, req, func(stat *schema.IOStat) error {
+			if offset >= req.Offset && uint64(len(list.Body)) < limit {
+				list.Body = append(list.Body, *stat)
+			}
+			offset++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Increment the count
+		list.Count += count
+
+		// Return success
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return &list, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Iterate through the IO statistics for a database
+func (manager *Manager) withIOStats(ctx context.Context, database string, req schema.IOStatListRequest, fn func(stat *schema.IOStat) error) (uint64, error) {
+	req.Offset = 0
+	req.Limit = types.Uint64Ptr(schema.IOStatListLimit)
+
+	for {
+		var list schema.IOStatList
+		if err := manager.conn.Remote(database).With("as", schema.IOStatDef).List(ctx, &list, &req); err != nil {
+			return 0, err
+		}
+
+		for _, stat := range list.Body {
+			if err := fn(&stat); err != nil {
+				return 0, err
+			}
+		}
+
+		// Determine if the next page is over the count
+		next := req.Offset + types.PtrUint64(req.Limit)
+		if next >= list.Count {
+			return list.Count, nil
+		} else {
+			req.Offset = next
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// IO STATISTICS TESTS
+
+func Test_Manager_GetIOStats(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("ListAll", func(t *testing.T) {
+		stats, err := mgr.GetIOStats(context.TODO(), schema.IOStatListRequest{})
+		if assert.NoError(err) {
+			assert.LessOrEqual(len(stats.Body), int(stats.Count))
+			for _, stat := range stats.Body {
+				if stat.HeapRatio != nil {
+					assert.GreaterOrEqual(*stat.HeapRatio, 0.0)
+					assert.LessOrEqual(*stat.HeapRatio, 1.0)
+				}
+			}
+		}
+	})
+
+	t.Run("ListIndexes", func(t *testing.T) {
+		dbName, typ := "postgres", schema.IOStatTypeIndex
+		stats, err := mgr.GetIOStats(context.TODO(), schema.IOStatListRequest{
+			Database: &dbName,
+			Type:     &typ,
+		})
+		if assert.NoError(err) {
+			for _, stat := range stats.Body {
+				assert.Equal(dbName, stat.Database)
+				assert.Equal(typ, stat.Type)
+				assert.NotEmpty(stat.Index)
+			}
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// maintenance holds the heavy operations which are queued until a
+// maintenance window opens, and those which have recently run
+type maintenance struct {
+	sync.Mutex
+	id    uint64
+	tasks []*schema.MaintenanceTask
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Number of finished operations which are kept
+	maintenanceHistory = 100
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// InMaintenanceWindow returns true if heavy operations, such as reindexing
+// and base backups, can run now. This is always true when no maintenance
+// windows are set with WithMaintenanceWindows.
+func (manager *Manager) InMaintenanceWindow() bool {
+	return manager.opt.windows.Open(time.Now())
+}
+
+// GetMaintenance returns the maintenance windows, whether a window is open,
+// when the next window opens, and the heavy operations which are queued or
+// have recently run, most recent first.
+func (manager *Manager) GetMaintenance(ctx context.Context) (*schema.Maintenance, error) {
+	now := time.Now()
+	result := schema.Maintenance{
+		Windows: manager.opt.windows,
+		Open:    manager.opt.windows.Open(now),
+	}
+	if result.Windows == nil {
+		result.Windows = schema.MaintenanceWindows{}
+	}
+	if !result.Open {
+		result.Next = types.TimePtr(manager.opt.windows.Next(now))
+	}
+
+	// Copy the tasks
+	manager.maintenance.Lock()
+	defer manager.maintenance.Unlock()
+	for i := len(manager.maintenance.tasks) - 1; i >= 0; i-- {
+		result.Tasks = append(result.Tasks, *manager.maintenance.tasks[i])
+	}
+
+	// Return success
+	return &result, nil
+}
+
+// QueueMaintenance queues a heavy operation until the next maintenance window
+// opens, or runs it in the background now if a window is open, and returns
+// the task. Use GetMaintenance to follow the task.
+func (manager *Manager) QueueMaintenance(ctx context.Context, meta schema.MaintenanceTaskMeta) (*schema.MaintenanceTask, error) {
+	if err := meta.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Check the object exists, so the error is returned now rather than when
+	// the window opens
+	if meta.Operation == schema.MaintenanceReindex {
+		if _, err := manager.GetObject(ctx, meta.Database, meta.Schema, meta.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	// Queue the task
+	manager.maintenance.Lock()
+	manager.maintenance.id++
+	task := &schema.MaintenanceTask{
+		Id:                  manager.maintenance.id,
+		MaintenanceTaskMeta: meta,
+		State:               schema.MaintenanceQueued,
+		Queued:              time.Now(),
+	}
+	manager.maintenance.tasks = append(manager.maintenance.tasks, task)
+	result := *task
+	manager.maintenance.Unlock()
+
+	// Run the task when the window opens
+	go func() {
+		if wait := time.Until(manager.opt.windows.Next(time.Now())); wait > 0 {
+			time.Sleep(wait)
+		}
+		manager.runMaintenance(task)
+	}()
+
+	// Return the queued task
+	return &result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Run a queued task, and then remove the oldest finished tasks
+func (manager *Manager) runMaintenance(task *schema.MaintenanceTask) {
+	manager.maintenance.Lock()
+	task.State = schema.MaintenanceRunning
+	task.Started = types.TimePtr(time.Now())
+	manager.maintenance.Unlock()
+
+	// Run the operation
+	ctx := context.Background()
+	var err error
+	switch task.Operation {
+	case schema.MaintenanceReindex:
+		_, err = manager.ReindexObject(ctx, task.Database, task.Schema, task.Name, task.Concurrently)
+	case schema.MaintenanceBackup:
+		_, err = manager.StartBackup(ctx, schema.BackupMeta{Label: task.Label, Fast: task.Fast})
+	}
+
+	manager.maintenance.Lock()
+	defer manager.maintenance.Unlock()
+	task.Finished = types.TimePtr(time.Now())
+	if err != nil {
+		task.State = schema.MaintenanceFailed
+		task.Error = types.StringPtr(err.Error())
+	} else {
+		task.State = schema.MaintenanceDone
+	}
+
+	// Keep the queued and running tasks, and the most recent finished tasks
+	var finished int
+	for i := len(manager.maintenance.tasks) - 1; i >= 0; i-- {
+		switch manager.maintenance.tasks[i].State {
+		case schema.MaintenanceDone, schema.MaintenanceFailed:
+			if finished++; finished > maintenanceHistory {
+				manager.maintenance.tasks = append(manager.maintenance.tasks[:i], manager.maintenance.tasks[i+1:]...)
+			}
+		}
+	}
+}
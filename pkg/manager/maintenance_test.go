@@ -0,0 +1,93 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// MAINTENANCE TESTS
+
+func Test_Manager_Maintenance(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	t.Run("NoWindows", func(t *testing.T) {
+		mgr, err := manager.New(context.TODO(), conn)
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		assert.True(mgr.InMaintenanceWindow())
+
+		maintenance, err := mgr.GetMaintenance(context.TODO())
+		if assert.NoError(err) {
+			assert.True(maintenance.Open)
+			assert.Nil(maintenance.Next)
+			assert.Empty(maintenance.Windows)
+		}
+	})
+
+	t.Run("InvalidWindow", func(t *testing.T) {
+		_, err := manager.New(context.TODO(), conn, manager.WithMaintenanceWindows(schema.MaintenanceWindow{Start: "01:00", End: "01:00"}))
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	// A window which opens in two hours
+	now := time.Now().UTC()
+	mgr, err := manager.New(context.TODO(), conn, manager.WithMaintenanceWindows(schema.MaintenanceWindow{
+		Start: now.Add(2 * time.Hour).Format("15:04"),
+		End:   now.Add(3 * time.Hour).Format("15:04"),
+	}))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Closed", func(t *testing.T) {
+		assert.False(mgr.InMaintenanceWindow())
+
+		maintenance, err := mgr.GetMaintenance(context.TODO())
+		if assert.NoError(err) && assert.NotNil(maintenance.Next) {
+			assert.False(maintenance.Open)
+			assert.True(maintenance.Next.After(now))
+		}
+	})
+
+	t.Run("QueueBackup", func(t *testing.T) {
+		task, err := mgr.QueueMaintenance(context.TODO(), schema.MaintenanceTaskMeta{
+			Operation: schema.MaintenanceBackup,
+			Label:     "queued_backup",
+		})
+		if assert.NoError(err) {
+			assert.Equal(schema.MaintenanceQueued, task.State)
+			assert.Nil(task.Started)
+		}
+
+		maintenance, err := mgr.GetMaintenance(context.TODO())
+		if assert.NoError(err) && assert.NotEmpty(maintenance.Tasks) {
+			assert.Equal(task.Id, maintenance.Tasks[0].Id)
+		}
+	})
+
+	t.Run("QueueReindexNotFound", func(t *testing.T) {
+		_, err := mgr.QueueMaintenance(context.TODO(), schema.MaintenanceTaskMeta{
+			Operation: schema.MaintenanceReindex,
+			Database:  "postgres",
+			Schema:    "public",
+			Name:      "non_existing_object_xyz",
+		})
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("QueueInvalid", func(t *testing.T) {
+		_, err := mgr.QueueMaintenance(context.TODO(), schema.MaintenanceTaskMeta{Operation: "vacuum"})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
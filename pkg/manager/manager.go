@@ -2,6 +2,8 @@ package manager
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
@@ -13,23 +15,47 @@ import (
 // TYPES
 
 type Manager struct {
+	sync.Mutex
 	conn pg.PoolConn
+	opt  *opt
 
 	// Feature flags
 	statStatementsAvailable bool
+
+	// Backup in progress, or nil
+	backup *backup
+
+	// Edits of pg_hba.conf, one at a time
+	hba sync.Mutex
+
+	// Heavy operations queued until a maintenance window opens
+	maintenance maintenance
+
+	// Metrics when alert rules were last evaluated
+	alerts alerts
+
+	// Size of each database when the bloat report was last produced
+	growth growth
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
 // New creates a new database manager.
-func New(ctx context.Context, conn pg.PoolConn) (*Manager, error) {
+func New(ctx context.Context, conn pg.PoolConn, opts ...Opt) (*Manager, error) {
 	if conn == nil {
 		return nil, pg.ErrBadParameter.With("connection is nil")
 	}
 	self := new(Manager)
 	self.conn = conn.With("schema", schema.CatalogSchema).(pg.PoolConn)
 
+	// Apply options
+	if opt, err := applyOpts(opts...); err != nil {
+		return nil, err
+	} else {
+		self.opt = opt
+	}
+
 	// Bootstrap extensions
 	result, err := schema.Bootstrap(ctx, self.conn)
 	if err != nil {
@@ -37,15 +63,37 @@ func New(ctx context.Context, conn pg.PoolConn) (*Manager, error) {
 	}
 	self.statStatementsAvailable = result.StatStatementsAvailable
 
+	// Create or update the alert rules from configuration
+	if err := self.applyAlertRules(ctx); err != nil {
+		return nil, err
+	}
+
 	// Return success
 	return self, nil
 }
 
+// PoolStat returns the statistics for the connection pool
+func (manager *Manager) PoolStat() pg.PoolStat {
+	return manager.conn.Stat()
+}
+
 // StatStatementsAvailable returns true if pg_stat_statements extension is available
 func (manager *Manager) StatStatementsAvailable() bool {
 	return manager.statStatementsAvailable
 }
 
+// MetricsCache returns the duration for which metrics are cached, which is
+// set with WithMetricsCache
+func (manager *Manager) MetricsCache() time.Duration {
+	return manager.opt.cache
+}
+
+// MetricsTimeout returns the time allowed for each metrics collector, which
+// is set with WithMetricsTimeout
+func (manager *Manager) MetricsTimeout() time.Duration {
+	return manager.opt.timeout
+}
+
 // Iterate through all the databases
 func (manager *Manager) withDatabases(ctx context.Context, fn func(database *schema.Database) error) (uint64, error) {
 	var req schema.DatabaseListRequest
@@ -0,0 +1,51 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// METRICS OPTION TESTS
+
+func Test_Manager_WithMetricsCache(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	t.Run("Default", func(t *testing.T) {
+		mgr, err := manager.New(context.TODO(), conn)
+		if assert.NoError(err) {
+			assert.Zero(mgr.MetricsCache())
+			assert.Equal(10*time.Second, mgr.MetricsTimeout())
+		}
+	})
+
+	t.Run("Cache", func(t *testing.T) {
+		mgr, err := manager.New(context.TODO(), conn, manager.WithMetricsCache(time.Minute), manager.WithMetricsTimeout(time.Second))
+		if assert.NoError(err) {
+			assert.Equal(time.Minute, mgr.MetricsCache())
+			assert.Equal(time.Second, mgr.MetricsTimeout())
+		}
+	})
+
+	t.Run("LongTimeout", func(t *testing.T) {
+		mgr, err := manager.New(context.TODO(), conn, manager.WithMetricsTimeout(time.Minute))
+		if assert.NoError(err) {
+			assert.Equal(time.Minute, mgr.MetricsTimeout())
+		}
+	})
+
+	t.Run("Negative", func(t *testing.T) {
+		_, err := manager.New(context.TODO(), conn, manager.WithMetricsCache(-time.Second))
+		assert.ErrorIs(err, pg.ErrBadParameter)
+		_, err = manager.New(context.TODO(), conn, manager.WithMetricsTimeout(-time.Second))
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
@@ -0,0 +1,50 @@
+package manager
+
+import (
+	"context"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// NotifyFn is called for each notification on a channel. Returning an
+// error stops listening.
+type NotifyFn func(schema.Notification) error
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Listen listens for notifications on a channel, and calls the function for
+// each notification until the context is cancelled, when it returns nil. A
+// connection is held from the pool while listening.
+func (manager *Manager) Listen(ctx context.Context, channel string, fn NotifyFn) error {
+	if err := schema.ValidateChannel(channel); err != nil {
+		return err
+	}
+
+	// Listen on a dedicated connection, which is closed when done
+	listener := manager.conn.Listener()
+	defer listener.Close(context.Background())
+	if err := listener.Listen(ctx, channel); err != nil {
+		return err
+	}
+
+	// Relay notifications
+	for {
+		notification, err := listener.WaitForNotification(ctx)
+		if ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := fn(schema.Notification{
+			Channel: notification.Channel,
+			Payload: string(notification.Payload),
+		}); err != nil {
+			return err
+		}
+	}
+}
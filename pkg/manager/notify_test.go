@@ -0,0 +1,68 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LISTEN TESTS
+
+func Test_Manager_Listen(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("ReceivesNotification", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+		defer cancel()
+
+		// Listen in the background
+		received := make(chan schema.Notification, 1)
+		result := make(chan error, 1)
+		go func() {
+			result <- mgr.Listen(ctx, "test_listen", func(notification schema.Notification) error {
+				select {
+				case received <- notification:
+				default:
+				}
+				return nil
+			})
+		}()
+
+		// Notify until the listener receives a notification
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			assert.NoError(conn.Exec(ctx, `NOTIFY test_listen, 'hello'`))
+			select {
+			case notification := <-received:
+				assert.Equal("test_listen", notification.Channel)
+				assert.Equal("hello", notification.Payload)
+				cancel()
+				assert.NoError(<-result)
+				return
+			case <-ctx.Done():
+				t.Fatal("timed out waiting for notification")
+			case <-ticker.C:
+			}
+		}
+	})
+
+	t.Run("MissingChannel", func(t *testing.T) {
+		err := mgr.Listen(context.TODO(), "", func(schema.Notification) error { return nil })
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
@@ -2,6 +2,9 @@ package manager
 
 import (
 	"context"
+	"errors"
+	"io"
+	"slices"
 	"strings"
 
 	// Packages
@@ -54,6 +57,12 @@ func (manager *Manager) ListObjects(ctx context.Context, req schema.ObjectListRe
 		return nil, err
 	}
 
+	// Set the token for the next page
+	if n := len(list.Body); n > 0 {
+		last := list.Body[n-1]
+		list.Next = req.Next(req.Offset, uint64(n), list.Count, last.Database, last.Schema, last.Name)
+	}
+
 	// Return success
 	return &list, nil
 }
@@ -74,3 +83,152 @@ func (manager *Manager) GetObject(ctx context.Context, database, namespace, name
 	}
 	return &response, nil
 }
+
+// ListColumns returns the columns of a table, view or materialized view in
+// order of their position, including whether each column is in the primary
+// key or is set by the database.
+func (manager *Manager) ListColumns(ctx context.Context, database, namespace, name string) (*schema.ColumnList, error) {
+	if database == "" {
+		return nil, pg.ErrBadParameter.With("database is empty")
+	}
+	var list schema.ColumnList
+	if err := manager.conn.Remote(database).With("as", schema.ColumnDef).List(ctx, &list, schema.ColumnListRequest{Schema: namespace, Table: name}); err != nil {
+		return nil, err
+	} else if list.Count == 0 {
+		return nil, pg.ErrNotFound.Withf("object %q not found in schema %q", name, namespace)
+	}
+	return &list, nil
+}
+
+// GetColumnStatistics returns the planner statistics of the columns of a
+// table, materialized view or foreign table from pg_stats, with the
+// statistics target of each column, in order of their position.
+func (manager *Manager) GetColumnStatistics(ctx context.Context, database, namespace, name string) (*schema.ColumnStatisticsList, error) {
+	if database == "" {
+		return nil, pg.ErrBadParameter.With("database is empty")
+	}
+	var list schema.ColumnStatisticsList
+	if err := manager.conn.Remote(database).With("as", schema.ColumnStatisticsDef).List(ctx, &list, schema.ColumnStatisticsListRequest{Schema: namespace, Table: name}); err != nil {
+		return nil, err
+	} else if list.Count == 0 {
+		return nil, pg.ErrNotFound.Withf("table %q not found in schema %q", name, namespace)
+	}
+	return &list, nil
+}
+
+// SetStatisticsTarget sets the statistics target of a column, or resets it
+// to the default_statistics_target when the target is not set, and analyzes
+// the column when requested. Returns the statistics of the columns, or
+// ErrNotFound if the object or column does not exist.
+func (manager *Manager) SetStatisticsTarget(ctx context.Context, database, namespace, name string, meta schema.ColumnStatisticsMeta) (_ *schema.ColumnStatisticsList, err error) {
+	if err := meta.Validate(); err != nil {
+		return nil, err
+	}
+	defer manager.audit(ctx, schema.AuditUpdate, "statistics", database+"/"+namespace+"."+name, meta, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
+	// Check the object and column exist
+	object, err := manager.GetObject(ctx, database, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	list, err := manager.GetColumnStatistics(ctx, database, namespace, name)
+	if err != nil {
+		return nil, err
+	} else if !slices.ContainsFunc(list.Body, func(column schema.ColumnStatistics) bool {
+		return column.Name == strings.TrimSpace(meta.Column)
+	}) {
+		return nil, pg.ErrNotFound.Withf("column %q not found in %q", meta.Column, name)
+	}
+
+	// Set the statistics target
+	if err := meta.Set(ctx, manager.conn.Remote(database), namespace, name, object.Type); err != nil {
+		return nil, err
+	}
+
+	// Return the statistics
+	return manager.GetColumnStatistics(ctx, database, namespace, name)
+}
+
+// GetObjectDDL returns the statements which create a table, with its columns,
+// constraints and indexes, or a view, materialized view, sequence, index or
+// function, reconstructed from the catalog. All the functions with the name
+// are returned. Returns ErrNotImplemented for other kinds of object.
+func (manager *Manager) GetObjectDDL(ctx context.Context, database, namespace, name string) (*schema.ObjectDDL, error) {
+	if database == "" {
+		return nil, pg.ErrBadParameter.With("database is empty")
+	}
+	var response schema.ObjectDDL
+	if err := manager.conn.Remote(database).With("as", schema.ObjectDDLDef).List(ctx, &response, schema.ObjectDDLRequest{ObjectName: schema.ObjectName{Schema: namespace, Name: name}}); err != nil {
+		return nil, err
+	} else if len(response.Statements) == 0 {
+		// Return ErrNotFound when the object does not exist
+		object, err := manager.GetObject(ctx, database, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return nil, pg.ErrNotImplemented.Withf("cannot reconstruct %s %q", strings.ToLower(object.Type), name)
+	}
+	return &response, nil
+}
+
+// ReindexObject rebuilds an index, or all the indexes of a table or
+// materialized view, and returns the object. When concurrently is true, the
+// object is not locked against writes while the indexes are rebuilt.
+func (manager *Manager) ReindexObject(ctx context.Context, database, namespace, name string, concurrently bool) (*schema.Object, error) {
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
+	object, err := manager.GetObject(ctx, database, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reindex the object
+	if err := (schema.ObjectName{Schema: namespace, Name: name}).Reindex(ctx, manager.conn.Remote(database), object.Type, concurrently); err != nil {
+		return nil, err
+	}
+
+	// Return the object, which may have changed size
+	return manager.GetObject(ctx, database, namespace, name)
+}
+
+// ExportObject writes the rows of a table, view, materialized view or
+// foreign table to the writer as newline-delimited JSON, reading the rows
+// in batches through a cursor so that the memory used does not depend on
+// the number of rows.
+func (manager *Manager) ExportObject(ctx context.Context, database, namespace, name string, w io.Writer) (err error) {
+	object, err := manager.GetObject(ctx, database, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	// Declare the cursor, which is closed when the rows have been read
+	cursor, err := (schema.ObjectName{Schema: namespace, Name: name}).Export(ctx, manager.conn.Remote(database), object.Type, 0)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, cursor.Close(ctx))
+	}()
+
+	// Write the rows a batch at a time
+	var rows schema.ObjectRows
+	for {
+		rows.Reset()
+		if n, err := cursor.Fetch(ctx, &rows); err != nil {
+			return err
+		} else if n == 0 {
+			return nil
+		}
+		if _, err := rows.WriteTo(w); err != nil {
+			return err
+		}
+	}
+}
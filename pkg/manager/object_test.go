@@ -1,7 +1,10 @@
 package manager_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	// Packages
@@ -194,3 +197,318 @@ func Test_Manager_GetObject(t *testing.T) {
 		assert.Equal(obj.Owner, result.Owner)
 	})
 }
+
+func Test_Manager_ListColumns(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("ListColumns", func(t *testing.T) {
+		columns, err := mgr.ListColumns(context.TODO(), "postgres", "pg_catalog", "pg_namespace")
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		assert.NotZero(columns.Count)
+		assert.Equal("oid", columns.Body[0].Name)
+		assert.Equal("oid", columns.Body[0].Type)
+		for i, column := range columns.Body {
+			assert.Equal(int32(i+1), column.Position)
+			assert.Equal("pg_namespace", column.Table)
+		}
+	})
+
+	t.Run("ListColumnsNonExistentObject", func(t *testing.T) {
+		_, err := mgr.ListColumns(context.TODO(), "postgres", "public", "non_existing_object_xyz")
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("ListColumnsEmptyDatabase", func(t *testing.T) {
+		_, err := mgr.ListColumns(context.TODO(), "", "public", "test")
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("ListColumnsEmptyName", func(t *testing.T) {
+		_, err := mgr.ListColumns(context.TODO(), "postgres", "public", "")
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
+func Test_Manager_ColumnStatistics(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Create a database with a table which has not been analyzed
+	t.Cleanup(func() {
+		mgr.DeleteDatabase(context.TODO(), "test_columnstats", true)
+	})
+	if _, err := mgr.CreateDatabase(context.TODO(), schema.DatabaseMeta{Name: "test_columnstats"}); !assert.NoError(err) {
+		t.FailNow()
+	}
+	for _, statement := range []string{
+		"CREATE TABLE stats_test (id INTEGER, status TEXT)",
+		"INSERT INTO stats_test SELECT i, CASE WHEN i % 4 = 0 THEN 'closed' ELSE 'open' END FROM generate_series(1, 1000) i",
+	} {
+		if !assert.NoError(conn.Remote("test_columnstats").Exec(context.TODO(), statement)) {
+			t.FailNow()
+		}
+	}
+
+	t.Run("NotAnalyzed", func(t *testing.T) {
+		stats, err := mgr.GetColumnStatistics(context.TODO(), "test_columnstats", "public", "stats_test")
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		if assert.Len(stats.Body, 2) {
+			assert.Equal("id", stats.Body[0].Name)
+			assert.Equal("status", stats.Body[1].Name)
+			assert.False(stats.Body[1].Analyzed)
+			assert.Nil(stats.Body[1].Target)
+		}
+	})
+
+	t.Run("SetAndAnalyze", func(t *testing.T) {
+		target := int32(500)
+		stats, err := mgr.SetStatisticsTarget(context.TODO(), "test_columnstats", "public", "stats_test", schema.ColumnStatisticsMeta{Column: "status", Target: &target, Analyze: true})
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		if assert.Len(stats.Body, 2) {
+			status := stats.Body[1]
+			assert.True(status.Analyzed)
+			if assert.NotNil(status.Target) {
+				assert.Equal(target, *status.Target)
+			}
+			assert.ElementsMatch([]string{"open", "closed"}, status.MostCommonValues)
+			assert.Len(status.MostCommonFreqs, 2)
+			if assert.NotNil(status.NullFrac) {
+				assert.Zero(*status.NullFrac)
+			}
+
+			// The id column was not analyzed
+			assert.False(stats.Body[0].Analyzed)
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		stats, err := mgr.SetStatisticsTarget(context.TODO(), "test_columnstats", "public", "stats_test", schema.ColumnStatisticsMeta{Column: "status"})
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		if assert.Len(stats.Body, 2) {
+			assert.Nil(stats.Body[1].Target)
+		}
+	})
+
+	t.Run("TargetOutOfRange", func(t *testing.T) {
+		target := int32(schema.ColumnStatisticsTargetMax + 1)
+		_, err := mgr.SetStatisticsTarget(context.TODO(), "test_columnstats", "public", "stats_test", schema.ColumnStatisticsMeta{Column: "status", Target: &target})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("NonExistentColumn", func(t *testing.T) {
+		_, err := mgr.SetStatisticsTarget(context.TODO(), "test_columnstats", "public", "stats_test", schema.ColumnStatisticsMeta{Column: "non_existing_column_xyz"})
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("NonExistentTable", func(t *testing.T) {
+		_, err := mgr.GetColumnStatistics(context.TODO(), "test_columnstats", "public", "non_existing_object_xyz")
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("EmptyDatabase", func(t *testing.T) {
+		_, err := mgr.GetColumnStatistics(context.TODO(), "", "public", "stats_test")
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
+func Test_Manager_ReindexObject(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("ReindexNonExistentObject", func(t *testing.T) {
+		_, err := mgr.ReindexObject(context.TODO(), "postgres", "public", "non_existing_object_xyz", false)
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("ReindexEmptyName", func(t *testing.T) {
+		_, err := mgr.ReindexObject(context.TODO(), "postgres", "public", "", false)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
+func Test_Manager_GetObjectDDL(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Create databases with objects, and without
+	for _, name := range []string{"test_ddl", "test_ddl_copy"} {
+		t.Cleanup(func() {
+			mgr.DeleteDatabase(context.TODO(), name, true)
+		})
+		if _, err := mgr.CreateDatabase(context.TODO(), schema.DatabaseMeta{Name: name}); !assert.NoError(err) {
+			t.FailNow()
+		}
+	}
+	for _, statement := range []string{
+		"CREATE TABLE ddl_test (id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY, name TEXT NOT NULL DEFAULT 'x', CHECK (name <> ''))",
+		"CREATE INDEX ddl_test_name_idx ON ddl_test (name)",
+		"COMMENT ON TABLE ddl_test IS 'Test table'",
+		"CREATE VIEW ddl_test_view AS SELECT id, name FROM ddl_test",
+		"CREATE SEQUENCE ddl_test_seq INCREMENT BY 2",
+		"CREATE FUNCTION ddl_test_func(x INTEGER) RETURNS INTEGER LANGUAGE SQL AS 'SELECT x + 1'",
+	} {
+		if !assert.NoError(conn.Remote("test_ddl").Exec(context.TODO(), statement)) {
+			t.FailNow()
+		}
+	}
+
+	t.Run("Table", func(t *testing.T) {
+		ddl, err := mgr.GetObjectDDL(context.TODO(), "test_ddl", "public", "ddl_test")
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		assert.Equal("TABLE", ddl.Type)
+		if assert.Len(ddl.Statements, 3) {
+			assert.Contains(ddl.Statements[0], "CREATE TABLE public.ddl_test (id integer GENERATED ALWAYS AS IDENTITY NOT NULL")
+			assert.Contains(ddl.Statements[0], "PRIMARY KEY (id)")
+			assert.Equal("CREATE INDEX ddl_test_name_idx ON public.ddl_test USING btree (name)", ddl.Statements[1])
+			assert.Equal("COMMENT ON TABLE public.ddl_test IS 'Test table'", ddl.Statements[2])
+		}
+
+		// The statements create the same table in another database
+		for _, statement := range ddl.Statements {
+			assert.NoError(conn.Remote("test_ddl_copy").Exec(context.TODO(), statement))
+		}
+		other, err := mgr.GetObjectDDL(context.TODO(), "test_ddl_copy", "public", "ddl_test")
+		if assert.NoError(err) {
+			assert.Equal(ddl.Statements, other.Statements)
+		}
+	})
+
+	t.Run("View", func(t *testing.T) {
+		ddl, err := mgr.GetObjectDDL(context.TODO(), "test_ddl", "public", "ddl_test_view")
+		if assert.NoError(err) {
+			assert.Equal("VIEW", ddl.Type)
+			if assert.Len(ddl.Statements, 1) {
+				assert.Contains(ddl.Statements[0], "CREATE VIEW public.ddl_test_view AS SELECT")
+			}
+		}
+	})
+
+	t.Run("Sequence", func(t *testing.T) {
+		ddl, err := mgr.GetObjectDDL(context.TODO(), "test_ddl", "public", "ddl_test_seq")
+		if assert.NoError(err) {
+			assert.Equal("SEQUENCE", ddl.Type)
+			if assert.Len(ddl.Statements, 1) {
+				assert.Contains(ddl.Statements[0], "CREATE SEQUENCE public.ddl_test_seq AS bigint INCREMENT BY 2")
+			}
+		}
+	})
+
+	t.Run("Function", func(t *testing.T) {
+		ddl, err := mgr.GetObjectDDL(context.TODO(), "test_ddl", "public", "ddl_test_func")
+		if assert.NoError(err) {
+			assert.Equal("FUNCTION", ddl.Type)
+			if assert.Len(ddl.Statements, 1) {
+				assert.Contains(ddl.Statements[0], "CREATE OR REPLACE FUNCTION public.ddl_test_func(x integer)")
+			}
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := mgr.GetObjectDDL(context.TODO(), "test_ddl", "public", "non_existing_object_xyz")
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("EmptyName", func(t *testing.T) {
+		_, err := mgr.GetObjectDDL(context.TODO(), "test_ddl", "public", "")
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
+func Test_Manager_ExportObject(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Create a database with a table of more rows than a batch
+	t.Cleanup(func() {
+		mgr.DeleteDatabase(context.TODO(), "test_export", true)
+	})
+	if _, err := mgr.CreateDatabase(context.TODO(), schema.DatabaseMeta{Name: "test_export"}); !assert.NoError(err) {
+		t.FailNow()
+	}
+	for _, statement := range []string{
+		"CREATE TABLE export_test (id INTEGER PRIMARY KEY, name TEXT)",
+		"INSERT INTO export_test SELECT n, 'name_' || n FROM generate_series(1, 2500) AS n",
+		"CREATE TABLE export_empty (id INTEGER)",
+		"CREATE INDEX export_test_name_idx ON export_test (name)",
+	} {
+		if !assert.NoError(conn.Remote("test_export").Exec(context.TODO(), statement)) {
+			t.FailNow()
+		}
+	}
+
+	t.Run("Table", func(t *testing.T) {
+		var out bytes.Buffer
+		if !assert.NoError(mgr.ExportObject(context.TODO(), "test_export", "public", "export_test", &out)) {
+			t.FailNow()
+		}
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		if assert.Len(lines, 2500) {
+			var row struct {
+				Id   int    `json:"id"`
+				Name string `json:"name"`
+			}
+			assert.NoError(json.Unmarshal([]byte(lines[0]), &row))
+			assert.NotZero(row.Id)
+			assert.Equal("name_", row.Name[:5])
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(mgr.ExportObject(context.TODO(), "test_export", "public", "export_empty", &out))
+		assert.Zero(out.Len())
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		var out bytes.Buffer
+		err := mgr.ExportObject(context.TODO(), "test_export", "public", "export_test_name_idx", &out)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		var out bytes.Buffer
+		err := mgr.ExportObject(context.TODO(), "test_export", "public", "non_existing_object_xyz", &out)
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+}
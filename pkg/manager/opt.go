@@ -0,0 +1,254 @@
+package manager
+
+import (
+	"net"
+	"net/mail"
+	"path/filepath"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type opt struct {
+	pgdump     string
+	pgrestore  string
+	psql       string
+	rules      schema.QueryRules
+	windows    schema.MaintenanceWindows
+	alertrules []schema.AlertRuleMeta
+	hba        string
+	statements uint
+	cache      time.Duration
+	timeout    time.Duration
+	backup     time.Duration
+	smtp       struct {
+		addr, from     string
+		user, password string
+	}
+}
+
+// Opt is a function which applies options for the manager
+type Opt func(*opt) error
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultPgDump    = "pg_dump"
+	defaultPgRestore = "pg_restore"
+	defaultPsql      = "psql"
+
+	// The number of role and database pairs with statement metrics
+	defaultStatementMetrics = 10
+
+	// The time allowed for each metrics collector to query the database
+	defaultMetricsTimeout = 10 * time.Second
+
+	// The time a base backup can remain in progress before it is abandoned
+	defaultBackupTimeout = 24 * time.Hour
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Apply options to the opt struct
+func applyOpts(opts ...Opt) (*opt, error) {
+	var o opt
+
+	// Set defaults
+	o.pgdump = defaultPgDump
+	o.pgrestore = defaultPgRestore
+	o.psql = defaultPsql
+	o.statements = defaultStatementMetrics
+	o.timeout = defaultMetricsTimeout
+	o.backup = defaultBackupTimeout
+
+	// Apply options
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	// Return success
+	return &o, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// WithPgDump sets the path to the pg_dump binary, which is used for database
+// dumps. By default, pg_dump is located using the PATH environment variable.
+func WithPgDump(path string) Opt {
+	return func(o *opt) error {
+		if path != "" {
+			o.pgdump = path
+		}
+		return nil
+	}
+}
+
+// WithPgRestore sets the path to the pg_restore binary, which is used for
+// restoring custom and tar format dumps. By default, pg_restore is located
+// using the PATH environment variable.
+func WithPgRestore(path string) Opt {
+	return func(o *opt) error {
+		if path != "" {
+			o.pgrestore = path
+		}
+		return nil
+	}
+}
+
+// WithPsql sets the path to the psql binary, which is used for restoring
+// plain format dumps. By default, psql is located using the PATH environment
+// variable.
+func WithPsql(path string) Opt {
+	return func(o *opt) error {
+		if path != "" {
+			o.psql = path
+		}
+		return nil
+	}
+}
+
+// WithQueryRules sets the rules which are checked before an ad-hoc query is
+// run, limiting the schemas which can be read, the functions which can be
+// called and the estimated cost of the query.
+func WithQueryRules(rules schema.QueryRules) Opt {
+	return func(o *opt) error {
+		if rules.MaxCost < 0 {
+			return pg.ErrBadParameter.With("max cost cannot be negative")
+		}
+		o.rules = rules
+		return nil
+	}
+}
+
+// WithMaintenanceWindows sets the times when heavy operations, such as
+// reindexing and base backups, can run. Outside of the windows, these
+// operations are queued with QueueMaintenance until the next window opens.
+func WithMaintenanceWindows(windows ...schema.MaintenanceWindow) Opt {
+	return func(o *opt) error {
+		for _, window := range windows {
+			if err := window.Validate(); err != nil {
+				return err
+			}
+		}
+		o.windows = append(o.windows, windows...)
+		return nil
+	}
+}
+
+// WithSMTP sets the mail server, as host:port, and the sender address for
+// alert rules which send notifications by email. The user and password are
+// optional, and are used for PLAIN authentication.
+func WithSMTP(addr, from, user, password string) Opt {
+	return func(o *opt) error {
+		if addr == "" {
+			return nil
+		} else if _, _, err := net.SplitHostPort(addr); err != nil {
+			return pg.ErrBadParameter.Withf("invalid mail server %q", addr)
+		} else if _, err := mail.ParseAddress(from); err != nil {
+			return pg.ErrBadParameter.Withf("invalid sender address %q", from)
+		}
+		o.smtp.addr, o.smtp.from = addr, from
+		o.smtp.user, o.smtp.password = user, password
+		return nil
+	}
+}
+
+// WithAlertRules sets alert rules from configuration, which are created when
+// the manager starts, or updated when a rule with the same name exists. Each
+// rule requires a name, metric, threshold, sink and target.
+func WithAlertRules(rules ...schema.AlertRuleMeta) Opt {
+	return func(o *opt) error {
+		for _, rule := range rules {
+			if _, err := rule.Insert(pg.NewBind()); err != nil {
+				return err
+			}
+		}
+		o.alertrules = append(o.alertrules, rules...)
+		return nil
+	}
+}
+
+// WithHBAFile enables editing pg_hba.conf with AddHBARule and RemoveHBARule,
+// by setting the path of the file which the server reads as hba_file. The
+// directory of the file must be writable by the manager, such as a volume
+// shared with the server, as the file is replaced when it is edited. By
+// default, pg_hba.conf cannot be edited.
+func WithHBAFile(path string) Opt {
+	return func(o *opt) error {
+		if path == "" {
+			return nil
+		} else if !filepath.IsAbs(path) {
+			return pg.ErrBadParameter.Withf("path of pg_hba.conf must be absolute: %q", path)
+		}
+		o.hba = path
+		return nil
+	}
+}
+
+// WithStatementMetrics sets the number of role and database pairs for which
+// statement metrics are exported, with the pairs which spent the most time
+// executing statements first, which bounds the number of series. When zero,
+// statement metrics are not exported. By default, metrics are exported for
+// ten pairs.
+func WithStatementMetrics(limit uint) Opt {
+	return func(o *opt) error {
+		if limit > schema.StatementListLimit {
+			return pg.ErrBadParameter.Withf("statement metrics cannot exceed %d", schema.StatementListLimit)
+		}
+		o.statements = limit
+		return nil
+	}
+}
+
+// WithMetricsCache sets the duration for which metrics collected from the
+// database are cached, so that frequent scrapes do not query the database
+// each time. When zero, metrics are collected on every scrape, which is the
+// default.
+func WithMetricsCache(ttl time.Duration) Opt {
+	return func(o *opt) error {
+		if ttl < 0 {
+			return pg.ErrBadParameter.With("metrics cache cannot be negative")
+		}
+		o.cache = ttl
+		return nil
+	}
+}
+
+// WithMetricsTimeout sets the time allowed for each metrics collector to
+// query the database, after which the metrics of the collector are reported
+// as errors. By default, each collector is allowed ten seconds.
+func WithMetricsTimeout(timeout time.Duration) Opt {
+	return func(o *opt) error {
+		if timeout < 0 {
+			return pg.ErrBadParameter.With("metrics timeout cannot be negative")
+		} else if timeout > 0 {
+			o.timeout = timeout
+		}
+		return nil
+	}
+}
+
+// WithBackupTimeout sets the time a base backup can remain in progress. A
+// backup holds a connection from the pool until it is stopped, so a backup
+// which is not stopped within this time is abandoned and its connection is
+// returned to the pool. By default, a backup is abandoned after a day.
+func WithBackupTimeout(timeout time.Duration) Opt {
+	return func(o *opt) error {
+		if timeout < 0 {
+			return pg.ErrBadParameter.With("backup timeout cannot be negative")
+		} else if timeout > 0 {
+			o.backup = timeout
+		}
+		return nil
+	}
+}
@@ -0,0 +1,101 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Query runs a SELECT statement in a database, or the database of the
+// connection if the database is empty, and returns the columns and rows. The
+// statement runs within a read-only transaction with a statement timeout,
+// and at most the row limit of rows are returned. Any query rules set on the
+// manager are checked before the query is run.
+func (manager *Manager) Query(ctx context.Context, req schema.QueryRequest) (*schema.QueryResult, error) {
+	timeout := req.StatementTimeout()
+
+	// Check the query against the rules
+	if err := manager.checkQuery(ctx, req); err != nil {
+		return nil, err
+	}
+
+	// Run the query. Remote queries run on a new connection, so the
+	// transaction is started as part of the query and rolled back when the
+	// connection closes.
+	var result schema.QueryResult
+	if req.Database != "" {
+		if err := manager.conn.Remote(req.Database).With("as", schema.QueryDef, "readonly", fmt.Sprintf(queryRemoteReadOnly, timeout)).List(ctx, &result, req); err != nil {
+			return nil, err
+		}
+	} else if err := manager.conn.Tx(ctx, func(conn pg.Conn) error {
+		if err := conn.Exec(ctx, explainReadOnly); err != nil {
+			return err
+		}
+		if err := conn.Exec(ctx, fmt.Sprintf(queryTimeout, timeout)); err != nil {
+			return err
+		}
+		return conn.List(ctx, &result, req)
+	}); err != nil {
+		return nil, err
+	}
+
+	// Truncate the rows to the limit
+	if limit := req.RowLimit(); uint64(len(result.Rows)) > limit {
+		result.Rows = result.Rows[:limit]
+		result.Truncated = true
+	}
+
+	// Return success
+	result.Database = req.Database
+	if result.Columns == nil {
+		result.Columns = []schema.QueryColumn{}
+	}
+	if result.Rows == nil {
+		result.Rows = [][]any{}
+	}
+	return &result, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// checkQuery returns ErrBadParameter if the query calls a denied function,
+// or the plan for the query reads from a schema which is not allowed or
+// exceeds the maximum cost
+func (manager *Manager) checkQuery(ctx context.Context, req schema.QueryRequest) error {
+	rules := manager.opt.rules
+	if rules.IsZero() {
+		return nil
+	}
+
+	// Check for denied functions
+	if err := rules.CheckQuery(req.Query); err != nil {
+		return err
+	}
+
+	// Check the plan
+	if rules.NeedsPlan() {
+		explain, err := manager.ExplainQuery(ctx, req.Database, req.Query, schema.ExplainOptions{Verbose: true})
+		if err != nil {
+			return err
+		}
+		return rules.CheckPlan(explain.Plan)
+	}
+
+	// Return success
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE CONSTANTS
+
+const (
+	queryTimeout        = `SET LOCAL statement_timeout = %d`
+	queryRemoteReadOnly = `START TRANSACTION READ ONLY; SET LOCAL statement_timeout = %d; `
+)
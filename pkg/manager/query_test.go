@@ -0,0 +1,92 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// QUERY TESTS
+
+func Test_Manager_Query(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Query", func(t *testing.T) {
+		result, err := mgr.Query(context.TODO(), schema.QueryRequest{Query: "SELECT 1 AS a, 'x' AS b"})
+		if assert.NoError(err) {
+			assert.Len(result.Columns, 2)
+			assert.Equal("a", result.Columns[0].Name)
+			assert.Len(result.Rows, 1)
+			assert.False(result.Truncated)
+		}
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		result, err := mgr.Query(context.TODO(), schema.QueryRequest{Query: "SELECT generate_series(1, 10) AS n", Limit: types.Uint64Ptr(5)})
+		if assert.NoError(err) {
+			assert.Len(result.Rows, 5)
+			assert.True(result.Truncated)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		result, err := mgr.Query(context.TODO(), schema.QueryRequest{Query: "SELECT 1 WHERE false"})
+		if assert.NoError(err) {
+			assert.Empty(result.Rows)
+		}
+	})
+
+	t.Run("NotSelect", func(t *testing.T) {
+		_, err := mgr.Query(context.TODO(), schema.QueryRequest{Query: "CREATE TABLE query_test (id INT)"})
+		assert.Error(err)
+	})
+
+	t.Run("MultipleStatements", func(t *testing.T) {
+		_, err := mgr.Query(context.TODO(), schema.QueryRequest{Query: "SELECT 1; SELECT 2"})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
+func Test_Manager_QueryRules(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn, manager.WithQueryRules(schema.QueryRules{
+		Schemas:       []string{"public"},
+		DenyFunctions: []string{"pg_sleep"},
+		MaxCost:       1000000,
+	}))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Allowed", func(t *testing.T) {
+		_, err := mgr.Query(context.TODO(), schema.QueryRequest{Query: "SELECT 1 AS a"})
+		assert.NoError(err)
+	})
+
+	t.Run("DeniedFunction", func(t *testing.T) {
+		_, err := mgr.Query(context.TODO(), schema.QueryRequest{Query: "SELECT pg_sleep(1)"})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("DeniedSchema", func(t *testing.T) {
+		_, err := mgr.Query(context.TODO(), schema.QueryRequest{Query: "SELECT * FROM pg_catalog.pg_class"})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
@@ -0,0 +1,59 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - RECOVERY
+
+// IsInRecovery returns true if the connected server is a standby
+func (manager *Manager) IsInRecovery(ctx context.Context) (bool, error) {
+	server, err := manager.GetServer(ctx)
+	if err != nil {
+		return false, err
+	}
+	return server.InRecovery, nil
+}
+
+// GetRecoveryStatus returns whether the connected server is a standby, with
+// the positions of the WAL it has received and replayed, or the position it
+// has written when it is a primary.
+func (manager *Manager) GetRecoveryStatus(ctx context.Context) (*schema.RecoveryStatus, error) {
+	var response schema.RecoveryStatus
+	if err := manager.conn.Get(ctx, &response, schema.RecoveryRequest{}); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// PromoteStandby promotes the connected standby to a primary with pg_promote,
+// and returns the recovery status. Unless NoWait is set, it waits for the
+// promotion to complete, and returns an error wrapping DeadlineExceeded if it
+// does not complete in time. Returns ErrConflict if the server is not a standby.
+func (manager *Manager) PromoteStandby(ctx context.Context, req schema.PromoteRequest) (_ *schema.RecoveryStatus, err error) {
+	defer manager.audit(ctx, schema.AuditUpdate, "recovery", "promote", req, &err)
+
+	// Check the server is a standby
+	if standby, err := manager.IsInRecovery(ctx); err != nil {
+		return nil, err
+	} else if !standby {
+		return nil, pg.ErrConflict.With("server is not a standby")
+	}
+
+	// Promote the standby
+	var promoted schema.Promoted
+	if err := manager.conn.Update(ctx, &promoted, req, nil); err != nil {
+		return nil, err
+	} else if !bool(promoted) && !req.NoWait {
+		return nil, fmt.Errorf("promotion did not complete within %d seconds: %w", req.Seconds(), context.DeadlineExceeded)
+	}
+
+	// Return the recovery status
+	return manager.GetRecoveryStatus(ctx)
+}
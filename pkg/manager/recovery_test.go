@@ -0,0 +1,47 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// RECOVERY TESTS
+
+func Test_Manager_Recovery(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("IsInRecovery", func(t *testing.T) {
+		inRecovery, err := mgr.IsInRecovery(context.TODO())
+		if assert.NoError(err) {
+			assert.False(inRecovery)
+		}
+	})
+
+	t.Run("GetRecoveryStatus", func(t *testing.T) {
+		status, err := mgr.GetRecoveryStatus(context.TODO())
+		if assert.NoError(err) {
+			assert.False(status.InRecovery)
+			assert.NotNil(status.CurrentLSN)
+			assert.NotZero(status.Timeline)
+		}
+	})
+
+	t.Run("PromotePrimary", func(t *testing.T) {
+		_, err := mgr.PromoteStandby(context.TODO(), schema.PromoteRequest{})
+		assert.ErrorIs(err, pg.ErrConflict)
+	})
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"context"
+	"errors"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - REPLICATION
+
+// GetReplicationStatus returns the standbys which stream from the connected
+// server, with the positions each has been sent, written, flushed and replayed
+// and their lag, from pg_stat_replication. When the server is a standby, the
+// WAL receiver is also returned from pg_stat_wal_receiver, unless it is not
+// running.
+func (manager *Manager) GetReplicationStatus(ctx context.Context) (*schema.ReplicationStatus, error) {
+	server, err := manager.GetServer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response := schema.ReplicationStatus{
+		InRecovery: server.InRecovery,
+	}
+
+	// Get the standbys, which a standby has when replication is cascaded
+	var standbys schema.ReplicationStandbyList
+	if err := manager.conn.List(ctx, &standbys, schema.ReplicationStandbyListRequest{}); err != nil {
+		return nil, err
+	} else {
+		response.Standbys = standbys.Body
+	}
+
+	// Get the WAL receiver when the server is a standby
+	if server.InRecovery {
+		var receiver schema.ReplicationReceiver
+		if err := manager.conn.Get(ctx, &receiver, schema.ReplicationReceiverRequest{}); errors.Is(err, pg.ErrNotFound) {
+			// The WAL receiver is not running
+		} else if err != nil {
+			return nil, err
+		} else {
+			response.Receiver = &receiver
+		}
+	}
+
+	// Return success
+	return &response, nil
+}
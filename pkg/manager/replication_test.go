@@ -0,0 +1,33 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GET REPLICATION STATUS TESTS
+
+func Test_Manager_GetReplicationStatus(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Primary", func(t *testing.T) {
+		status, err := mgr.GetReplicationStatus(context.TODO())
+		if assert.NoError(err) {
+			assert.False(status.InRecovery)
+			assert.Nil(status.Receiver)
+			assert.Empty(status.Standbys)
+		}
+	})
+}
@@ -36,7 +36,9 @@ func (manager *Manager) GetReplicationSlot(ctx context.Context, name string) (*s
 
 // CreateReplicationSlot creates a new replication slot with the specified metadata.
 // Type must be "physical" or "logical". Logical slots require a plugin name.
-func (manager *Manager) CreateReplicationSlot(ctx context.Context, meta schema.ReplicationSlotMeta) (*schema.ReplicationSlot, error) {
+func (manager *Manager) CreateReplicationSlot(ctx context.Context, meta schema.ReplicationSlotMeta) (_ *schema.ReplicationSlot, err error) {
+	defer manager.audit(ctx, schema.AuditCreate, "replicationslot", meta.Name, meta, &err)
+
 	if err := meta.Validate(); err != nil {
 		return nil, err
 	}
@@ -57,7 +59,9 @@ func (manager *Manager) CreateReplicationSlot(ctx context.Context, meta schema.R
 
 // DeleteReplicationSlot drops a replication slot by name.
 // Returns the slot metadata before deletion.
-func (manager *Manager) DeleteReplicationSlot(ctx context.Context, name string) (*schema.ReplicationSlot, error) {
+func (manager *Manager) DeleteReplicationSlot(ctx context.Context, name string) (_ *schema.ReplicationSlot, err error) {
+	defer manager.audit(ctx, schema.AuditDelete, "replicationslot", name, nil, &err)
+
 	if name == "" {
 		return nil, pg.ErrBadParameter.With("name is empty")
 	}
@@ -0,0 +1,176 @@
+package manager
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// RestoreProgressFn is called with the progress of a restore
+type RestoreProgressFn func(schema.DatabaseRestoreProgress)
+
+// restoreProgress counts the bytes read from a dump, and reports progress
+// after each interval and for each message from the restore command
+type restoreProgress struct {
+	sync.Mutex
+	r        io.Reader
+	fn       RestoreProgressFn
+	bytes    uint64
+	next     uint64
+	messages []string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Report progress after each interval of bytes read
+	restoreProgressInterval = 1 << 20
+
+	// Number of bytes read to detect the dump format
+	restoreHeaderSize = 512
+
+	// Number of messages from the restore command to include in an error
+	restoreMaxMessages = 10
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RestoreDatabase restores a dump of the named database from the reader.
+// Plain format dumps are restored with psql, and custom or tar format dumps
+// with pg_restore. The format is detected from the dump if it is not set in
+// the request. The progress function, if not nil, is called as the dump is
+// read and for each message from the restore command. Returns ErrNotAvailable
+// if the restore command cannot be found.
+func (manager *Manager) RestoreDatabase(ctx context.Context, name string, req schema.DatabaseRestoreRequest, r io.Reader, fn RestoreProgressFn) (*schema.DatabaseRestore, error) {
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, pg.ErrBadParameter.With("name is empty")
+	} else if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Check the database exists
+	if _, err := manager.GetDatabase(ctx, name); err != nil {
+		return nil, err
+	}
+
+	// Detect the format from the header
+	reader := bufio.NewReaderSize(r, restoreHeaderSize)
+	header, err := reader.Peek(restoreHeaderSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	} else if len(header) == 0 {
+		return nil, pg.ErrBadParameter.With("dump is empty")
+	}
+	req = req.WithFormat(header)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Determine the command
+	path := manager.opt.pgrestore
+	if req.Format == schema.DumpFormatPlain {
+		path = manager.opt.psql
+	}
+
+	// Create the command, reading the dump from stdin
+	progress := &restoreProgress{r: reader, fn: fn, next: restoreProgressInterval}
+	cmd := manager.command(ctx, path, name, req.Args()...)
+	cmd.Stdin = progress
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	// Start the command
+	if err := cmd.Start(); err != nil {
+		return nil, commandError(path, err, "")
+	}
+
+	// Report messages from the command until stderr is closed
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		if message := strings.TrimSpace(scanner.Text()); message != "" {
+			progress.message(message)
+		}
+	}
+
+	// Wait for the command to complete
+	if err := cmd.Wait(); err != nil {
+		return nil, commandError(path, err, progress.errors())
+	}
+
+	// Return success
+	return &schema.DatabaseRestore{
+		Database: name,
+		Format:   req.Format,
+		Bytes:    progress.total(),
+	}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (p *restoreProgress) Read(data []byte) (int, error) {
+	n, err := p.r.Read(data)
+
+	// Report progress after each interval
+	p.Lock()
+	defer p.Unlock()
+	p.bytes += uint64(n)
+	if p.bytes >= p.next {
+		p.next = p.bytes + restoreProgressInterval
+		p.report("")
+	}
+
+	// Return the bytes read
+	return n, err
+}
+
+func (p *restoreProgress) message(message string) {
+	p.Lock()
+	defer p.Unlock()
+
+	// Retain the most recent messages for errors
+	p.messages = append(p.messages, message)
+	if len(p.messages) > restoreMaxMessages {
+		p.messages = p.messages[1:]
+	}
+
+	// Report the message
+	p.report(message)
+}
+
+func (p *restoreProgress) report(message string) {
+	if p.fn != nil {
+		p.fn(schema.DatabaseRestoreProgress{Bytes: p.bytes, Message: message})
+	}
+}
+
+func (p *restoreProgress) errors() string {
+	p.Lock()
+	defer p.Unlock()
+	return strings.Join(p.messages, "\n")
+}
+
+func (p *restoreProgress) total() uint64 {
+	p.Lock()
+	defer p.Unlock()
+	return p.bytes
+}
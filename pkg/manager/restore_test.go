@@ -0,0 +1,103 @@
+package manager_test
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// RESTORE TESTS
+
+func Test_Manager_RestoreDatabase(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("EmptyName", func(t *testing.T) {
+		_, err := mgr.RestoreDatabase(context.TODO(), "", schema.DatabaseRestoreRequest{}, strings.NewReader("SELECT 1;"), nil)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := mgr.RestoreDatabase(context.TODO(), "nonexistent_database", schema.DatabaseRestoreRequest{}, strings.NewReader("SELECT 1;"), nil)
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("EmptyDump", func(t *testing.T) {
+		_, err := mgr.RestoreDatabase(context.TODO(), "postgres", schema.DatabaseRestoreRequest{}, strings.NewReader(""), nil)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("CleanPlain", func(t *testing.T) {
+		_, err := mgr.RestoreDatabase(context.TODO(), "postgres", schema.DatabaseRestoreRequest{Clean: true}, strings.NewReader("SELECT 1;"), nil)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("MissingBinary", func(t *testing.T) {
+		mgr, err := manager.New(context.TODO(), conn, manager.WithPsql("/nonexistent/psql"))
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		_, err = mgr.RestoreDatabase(context.TODO(), "postgres", schema.DatabaseRestoreRequest{}, strings.NewReader("SELECT 1;"), nil)
+		assert.ErrorIs(err, pg.ErrNotAvailable)
+	})
+
+	t.Run("Plain", func(t *testing.T) {
+		if _, err := exec.LookPath("psql"); err != nil {
+			t.Skip("psql not found")
+		}
+		dump := "CREATE TABLE restore_test (id INTEGER);\nINSERT INTO restore_test VALUES (1);\n"
+		result, err := mgr.RestoreDatabase(context.TODO(), "postgres", schema.DatabaseRestoreRequest{}, strings.NewReader(dump), nil)
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		assert.Equal("postgres", result.Database)
+		assert.Equal(schema.DumpFormatPlain, result.Format)
+		assert.Equal(uint64(len(dump)), result.Bytes)
+		assert.NoError(conn.Exec(context.TODO(), "DROP TABLE restore_test"))
+	})
+
+	t.Run("PlainError", func(t *testing.T) {
+		if _, err := exec.LookPath("psql"); err != nil {
+			t.Skip("psql not found")
+		}
+		var messages []string
+		_, err := mgr.RestoreDatabase(context.TODO(), "postgres", schema.DatabaseRestoreRequest{}, strings.NewReader("SELECT * FROM nonexistent_table;"), func(progress schema.DatabaseRestoreProgress) {
+			messages = append(messages, progress.Message)
+		})
+		assert.Error(err)
+		assert.NotEmpty(messages)
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		if _, err := exec.LookPath("pg_dump"); err != nil {
+			t.Skip("pg_dump not found")
+		} else if _, err := exec.LookPath("pg_restore"); err != nil {
+			t.Skip("pg_restore not found")
+		}
+
+		// Dump the database in custom format and restore it
+		var buf bytes.Buffer
+		if !assert.NoError(mgr.DumpDatabase(context.TODO(), "postgres", schema.DatabaseDumpRequest{Format: schema.DumpFormatCustom, SchemaOnly: true}, &buf)) {
+			t.FailNow()
+		}
+		result, err := mgr.RestoreDatabase(context.TODO(), "postgres", schema.DatabaseRestoreRequest{Clean: true}, &buf, nil)
+		if assert.NoError(err) {
+			assert.Equal(schema.DumpFormatCustom, result.Format)
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -38,12 +39,28 @@ func (manager *Manager) GetRole(ctx context.Context, name string) (*schema.Role,
 
 // CreateRole creates a new role with the specified metadata.
 // The name must be a valid identifier and cannot have the reserved "pg_" prefix.
-func (manager *Manager) CreateRole(ctx context.Context, meta schema.RoleMeta) (*schema.Role, error) {
+// The role is created and its comment set within a transaction.
+func (manager *Manager) CreateRole(ctx context.Context, meta schema.RoleMeta) (_ *schema.Role, err error) {
+	defer manager.audit(ctx, schema.AuditCreate, "role", meta.Name, meta, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	if err := meta.Validate(); err != nil {
 		return nil, err
 	}
 	var role schema.Role
-	if err := manager.conn.Insert(ctx, nil, meta); err != nil {
+	if err := manager.conn.Tx(ctx, func(conn pg.Conn) error {
+		if err := conn.Insert(ctx, nil, meta); err != nil {
+			return err
+		}
+		if comment := types.PtrString(meta.Comment); comment != "" {
+			return schema.CommentTarget{Type: schema.CommentRole, Name: meta.Name, Comment: comment}.Set(ctx, conn, "")
+		}
+		return nil
+	}); err != nil {
 		return nil, err
 	} else if err := manager.conn.Get(ctx, &role, schema.RoleName(meta.Name)); err != nil {
 		return nil, err
@@ -53,7 +70,14 @@ func (manager *Manager) CreateRole(ctx context.Context, meta schema.RoleMeta) (*
 
 // DeleteRole deletes a role by name and returns the deleted role.
 // Returns an error if the name is empty, has a reserved prefix, or the role is not found.
-func (manager *Manager) DeleteRole(ctx context.Context, name string) (*schema.Role, error) {
+func (manager *Manager) DeleteRole(ctx context.Context, name string) (_ *schema.Role, err error) {
+	defer manager.audit(ctx, schema.AuditDelete, "role", name, nil, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	if name == "" {
 		return nil, pg.ErrBadParameter.With("name is empty")
 	}
@@ -69,7 +93,15 @@ func (manager *Manager) DeleteRole(ctx context.Context, name string) (*schema.Ro
 // UpdateRole updates an existing role with the specified metadata.
 // If meta.Name is set and different from the current name, the role is renamed.
 // If meta.Groups is set (even if empty), the group memberships are updated.
-func (manager *Manager) UpdateRole(ctx context.Context, name string, meta schema.RoleMeta) (*schema.Role, error) {
+// If meta.Comment is set, the comment is updated, or removed when empty.
+func (manager *Manager) UpdateRole(ctx context.Context, name string, meta schema.RoleMeta) (_ *schema.Role, err error) {
+	defer manager.audit(ctx, schema.AuditUpdate, "role", name, meta, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	if name == "" {
 		return nil, pg.ErrBadParameter.With("name is empty")
 	}
@@ -125,6 +157,13 @@ func (manager *Manager) UpdateRole(ctx context.Context, name string, meta schema
 			}
 		}
 
+		// Update the comment
+		if meta.Comment != nil {
+			if err := (schema.CommentTarget{Type: schema.CommentRole, Name: meta.Name, Comment: *meta.Comment}).Set(ctx, conn, ""); err != nil {
+				return err
+			}
+		}
+
 		// Return success
 		return nil
 	}); err != nil {
@@ -3,7 +3,6 @@ package manager
 import (
 	"context"
 	"errors"
-	"slices"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
@@ -62,6 +61,61 @@ func (manager *Manager) ListSchemas(ctx context.Context, req schema.SchemaListRe
 	return &list, nil
 }
 
+// ListSchemaRollups returns the sizes of schemas across all databases, broken
+// down by tables, indexes and TOAST, together with the total size of all the
+// schemas. If Database is specified in the request, only schemas from that
+// database are returned.
+func (manager *Manager) ListSchemaRollups(ctx context.Context, req schema.SchemaListRequest) (*schema.SchemaRollupList, error) {
+	var list schema.SchemaRollupList
+	var offset, limit uint64
+
+	// Set limit lower if request limit is lower
+	limit = schema.SchemaListLimit
+	if req.Limit != nil && types.PtrUint64(req.Limit) < limit {
+		limit = types.PtrUint64(req.Limit)
+	}
+
+	// Allocate the body with capacity
+	list.Body = make([]schema.SchemaRollup, 0, limit)
+
+	// Iterate through all the databases
+	if _, err := manager.withDatabases(ctx, func(database *schema.Database) error {
+		// Filter by database
+		if name := types.PtrString(req.Database); name != "" && name != database.Name {
+			return nil
+		}
+
+		// Iterate through all the schemas, totalling the sizes
+		count, err := manager.withSchemas(ctx, database.Name, func(s *schema.Schema) error {
+			if offset >= req.Offset && uint64(len(list.Body)) < limit {
+				list.Body = append(list.Body, schema.SchemaRollup{
+					Database:   s.Database,
+					Name:       s.Name,
+					Size:       s.Size,
+					SchemaSize: s.SchemaSize,
+				})
+			}
+			list.Size += s.Size
+			offset++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Increment the count
+		list.Count += count
+
+		// Return success
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return &list, nil
+}
+
 // GetSchema retrieves a single schema by database and namespace name.
 // Returns an error if the database or namespace is empty or the schema is not found.
 func (manager *Manager) GetSchema(ctx context.Context, database, namespace string) (*schema.Schema, error) {
@@ -79,9 +133,16 @@ func (manager *Manager) GetSchema(ctx context.Context, database, namespace strin
 }
 
 // CreateSchema creates a new schema in the specified database with the given metadata.
-// ACL grants are applied after schema creation. If ACL grants fail, the schema is deleted
-// to maintain consistency.
-func (manager *Manager) CreateSchema(ctx context.Context, database string, meta schema.SchemaMeta) (*schema.Schema, error) {
+// ACL grants and the comment are applied after schema creation. If they fail, the schema
+// is deleted to maintain consistency.
+func (manager *Manager) CreateSchema(ctx context.Context, database string, meta schema.SchemaMeta) (_ *schema.Schema, err error) {
+	defer manager.audit(ctx, schema.AuditCreate, "schema", database+"/"+meta.Name, meta, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	if database == "" {
 		return nil, pg.ErrBadParameter.With("database is empty")
 	}
@@ -101,6 +162,11 @@ func (manager *Manager) CreateSchema(ctx context.Context, database string, meta
 				return err
 			}
 		}
+		if comment := types.PtrString(meta.Comment); comment != "" {
+			if err := (schema.CommentTarget{Type: schema.CommentSchema, Database: database, Name: meta.Name, Comment: comment}).Set(ctx, conn, ""); err != nil {
+				return err
+			}
+		}
 		return nil
 	}); err != nil {
 		// Delete the schema if there is an issue with ACL's
@@ -119,7 +185,14 @@ func (manager *Manager) CreateSchema(ctx context.Context, database string, meta
 
 // DeleteSchema drops a schema by database and namespace name, returning its metadata before deletion.
 // If force is true, the schema is dropped with CASCADE even if there are dependent objects.
-func (manager *Manager) DeleteSchema(ctx context.Context, database, namespace string, force bool) (*schema.Schema, error) {
+func (manager *Manager) DeleteSchema(ctx context.Context, database, namespace string, force bool) (_ *schema.Schema, err error) {
+	defer manager.audit(ctx, schema.AuditDelete, "schema", database+"/"+namespace, nil, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	if database == "" {
 		return nil, pg.ErrBadParameter.With("database is empty")
 	}
@@ -144,10 +217,17 @@ func (manager *Manager) DeleteSchema(ctx context.Context, database, namespace st
 	return &s, nil
 }
 
-// UpdateSchema modifies an existing schema's metadata including name, owner, and ACLs.
+// UpdateSchema modifies an existing schema's metadata including name, owner, ACLs and comment.
 // If meta.Name is provided and differs from namespace, the schema is renamed.
 // ACL changes are synchronized by revoking removed privileges and granting new ones.
-func (manager *Manager) UpdateSchema(ctx context.Context, database, namespace string, meta schema.SchemaMeta) (*schema.Schema, error) {
+func (manager *Manager) UpdateSchema(ctx context.Context, database, namespace string, meta schema.SchemaMeta) (_ *schema.Schema, err error) {
+	defer manager.audit(ctx, schema.AuditUpdate, "schema", database+"/"+namespace, meta, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	if database == "" {
 		return nil, pg.ErrBadParameter.With("database is empty")
 	}
@@ -186,6 +266,13 @@ func (manager *Manager) UpdateSchema(ctx context.Context, database, namespace st
 		}
 	}
 
+	// Update the comment
+	if meta.Comment != nil {
+		if err := (schema.CommentTarget{Type: schema.CommentSchema, Database: database, Name: meta.Name, Comment: *meta.Comment}).Set(ctx, conn, ""); err != nil {
+			return nil, err
+		}
+	}
+
 	// Get the updated schema
 	if err := conn.With("as", schema.SchemaDef).Get(ctx, &s, schema.SchemaName(meta.Name)); err != nil {
 		return nil, err
@@ -198,63 +285,17 @@ func (manager *Manager) UpdateSchema(ctx context.Context, database, namespace st
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
-// updateSchemaACLs synchronizes ACLs between the current and desired state.
-// It performs the following operations:
-//   - Revokes all privileges for roles that are no longer in the desired list
-//   - For existing roles, revokes privileges that were removed and grants new ones
-//   - Grants all privileges for new roles
+// updateSchemaACLs synchronizes ACLs between the current and desired state,
+// by revoking removed privileges and granting new ones.
 func (manager *Manager) updateSchemaACLs(ctx context.Context, conn pg.Conn, schemaName string, current, desired schema.ACLList) error {
-	// Process existing ACLs
-	for _, acl := range current {
-		role := desired.Find(acl.Role)
-		if role == nil {
-			// Revoke all privileges for this role
-			if err := acl.RevokeSchema(ctx, conn, schemaName); err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Check if privileges are the same
-		if slices.Equal(acl.Priv, role.Priv) {
-			continue
-		}
-
-		// If new role has ALL, just grant it
-		if role.IsAll() {
-			if err := role.GrantSchema(ctx, conn, schemaName); err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Revoke privileges that are no longer needed
-		for _, priv := range acl.Priv {
-			if !slices.Contains(role.Priv, priv) {
-				if err := acl.WithPriv(priv).RevokeSchema(ctx, conn, schemaName); err != nil {
-					return err
-				}
-			}
-		}
-
-		// Grant new privileges
-		for _, priv := range role.Priv {
-			if !slices.Contains(acl.Priv, priv) {
-				if err := acl.WithPriv(priv).GrantSchema(ctx, conn, schemaName); err != nil {
-					return err
-				}
-			}
-		}
-	}
-
-	// Grant privileges for new roles
-	for _, acl := range desired {
-		if current.Find(acl.Role) == nil {
-			if err := acl.GrantSchema(ctx, conn, schemaName); err != nil {
+	for _, change := range current.Diff(desired) {
+		if change.Grant {
+			if err := change.GrantSchema(ctx, conn, schemaName); err != nil {
 				return err
 			}
+		} else if err := change.RevokeSchema(ctx, conn, schemaName); err != nil {
+			return err
 		}
 	}
-
 	return nil
 }
@@ -12,8 +12,8 @@ import (
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
 	httpresponse "github.com/mutablelogic/go-server/pkg/httpresponse"
-	types "github.com/mutablelogic/go-server/pkg/types"
 )
 
 /////////////////////////////////////////////////////////////////////////////
@@ -27,6 +27,13 @@ type ACLItem struct {
 
 type ACLList []*ACLItem
 
+// ACLChange is a privilege which is granted to or revoked from a role, to
+// change the privileges on an object from one list to another
+type ACLChange struct {
+	Grant bool `json:"grant"`
+	*ACLItem
+}
+
 /////////////////////////////////////////////////////////////////////////////
 // GLOBALS
 
@@ -83,6 +90,15 @@ func init() {
 	privsIndex[privAll] = '$'
 }
 
+var (
+	// Privileges which can be granted on each type of object
+	aclObjectPrivs = map[string][]string{
+		"DATABASE":   {privCreate, privConnect, privTemporary},
+		"SCHEMA":     {privCreate, privUsage},
+		"TABLESPACE": {privCreate},
+	}
+)
+
 var (
 	reRoleName = `([^\n=]*)`
 	rePriv     = `([^\n/]*)`
@@ -162,7 +178,7 @@ func (acl ACLItem) exec(ctx context.Context, conn pg.Conn, role, sql string) err
 	if role == DefaultAclRole {
 		conn = conn.With("role", role)
 	} else {
-		conn = conn.With("role", types.DoubleQuote(role))
+		conn = conn.With("role", quote.Ident(role))
 	}
 	// Set the privileges
 	for _, v := range acl.Priv {
@@ -367,6 +383,81 @@ func (acl *ACLList) Find(role string) *ACLItem {
 	return nil
 }
 
+// Diff returns the changes which make the list the same as the desired list,
+// in the order they are applied:
+//   - Revokes all privileges for roles that are no longer in the desired list
+//   - For existing roles, revokes privileges that were removed and grants new ones
+//   - Grants all privileges for new roles
+func (acl ACLList) Diff(desired ACLList) []ACLChange {
+	var changes []ACLChange
+
+	// Process existing ACLs
+	for _, item := range acl {
+		role := desired.Find(item.Role)
+		if role == nil {
+			changes = append(changes, ACLChange{Grant: false, ACLItem: item})
+			continue
+		}
+
+		// Check if privileges are the same
+		if slices.Equal(item.Priv, role.Priv) {
+			continue
+		}
+
+		// If new role has ALL, just grant it
+		if role.IsAll() {
+			changes = append(changes, ACLChange{Grant: true, ACLItem: role})
+			continue
+		}
+
+		// Revoke privileges that are no longer needed, and grant new ones
+		for _, priv := range item.Priv {
+			if !slices.Contains(role.Priv, priv) {
+				changes = append(changes, ACLChange{Grant: false, ACLItem: item.WithPriv(priv)})
+			}
+		}
+		for _, priv := range role.Priv {
+			if !slices.Contains(item.Priv, priv) {
+				changes = append(changes, ACLChange{Grant: true, ACLItem: item.WithPriv(priv)})
+			}
+		}
+	}
+
+	// Grant privileges for new roles
+	for _, item := range desired {
+		if acl.Find(item.Role) == nil {
+			changes = append(changes, ACLChange{Grant: true, ACLItem: item})
+		}
+	}
+
+	return changes
+}
+
+// Statements returns the GRANT and REVOKE statements which are executed to
+// make the change on an object, where the type is DATABASE, SCHEMA or
+// TABLESPACE
+func (c ACLChange) Statements(objtype, name string) []string {
+	role := c.Role
+	if role != DefaultAclRole {
+		role = quote.Ident(role)
+	}
+	result := make([]string, 0, len(c.Priv))
+	for _, priv := range c.Priv {
+		if c.Grant {
+			result = append(result, "GRANT "+priv+" ON "+objtype+" "+quote.Ident(name)+" TO "+role)
+		} else {
+			result = append(result, "REVOKE "+priv+" ON "+objtype+" "+quote.Ident(name)+" FROM "+role+" CASCADE")
+		}
+	}
+	return result
+}
+
+// ACLPrivileges returns the privileges which can be granted on a type of
+// object, which is DATABASE, SCHEMA or TABLESPACE
+func ACLPrivileges(objtype string) []string {
+	return slices.Clone(aclObjectPrivs[strings.ToUpper(objtype)])
+}
+
 // IsAll returns true if the ACL has ALL privileges.
 func (acl ACLItem) IsAll() bool {
 	return slices.Contains(acl.Priv, privAll)
@@ -445,3 +445,68 @@ func Test_ACLItem_String(t *testing.T) {
 		assert.Contains(str, "SELECT")
 	})
 }
+
+func Test_ACLList_Diff(t *testing.T) {
+	assert := assert.New(t)
+
+	current := schema.ACLList{
+		{Role: "alice", Priv: []string{"CONNECT", "CREATE"}},
+		{Role: "bob", Priv: []string{"CONNECT"}},
+		{Role: schema.DefaultAclRole, Priv: []string{"TEMPORARY"}},
+	}
+
+	t.Run("NoChange", func(t *testing.T) {
+		assert.Empty(current.Diff(current))
+	})
+
+	t.Run("Changes", func(t *testing.T) {
+		desired := schema.ACLList{
+			{Role: "alice", Priv: []string{"CONNECT", "TEMPORARY"}},
+			{Role: schema.DefaultAclRole, Priv: []string{"TEMPORARY"}},
+			{Role: "carol", Priv: []string{"CONNECT"}},
+		}
+		changes := current.Diff(desired)
+		if assert.Len(changes, 4) {
+			assert.False(changes[0].Grant)
+			assert.Equal("alice", changes[0].Role)
+			assert.Equal([]string{"CREATE"}, changes[0].Priv)
+			assert.True(changes[1].Grant)
+			assert.Equal([]string{"TEMPORARY"}, changes[1].Priv)
+			assert.False(changes[2].Grant)
+			assert.Equal("bob", changes[2].Role)
+			assert.True(changes[3].Grant)
+			assert.Equal("carol", changes[3].Role)
+		}
+	})
+
+	t.Run("All", func(t *testing.T) {
+		changes := current.Diff(schema.ACLList{{Role: "bob", Priv: []string{"ALL"}}})
+		if assert.Len(changes, 3) {
+			assert.True(changes[1].Grant)
+			assert.Equal([]string{"ALL"}, changes[1].Priv)
+		}
+	})
+}
+
+func Test_ACLChange_Statements(t *testing.T) {
+	assert := assert.New(t)
+
+	grant := schema.ACLChange{Grant: true, ACLItem: &schema.ACLItem{Role: "Alice", Priv: []string{"CONNECT", "CREATE"}}}
+	assert.Equal([]string{
+		`GRANT CONNECT ON DATABASE "test" TO "Alice"`,
+		`GRANT CREATE ON DATABASE "test" TO "Alice"`,
+	}, grant.Statements("DATABASE", "test"))
+
+	revoke := schema.ACLChange{Grant: false, ACLItem: &schema.ACLItem{Role: schema.DefaultAclRole, Priv: []string{"USAGE"}}}
+	assert.Equal([]string{
+		`REVOKE USAGE ON SCHEMA "public" FROM PUBLIC CASCADE`,
+	}, revoke.Statements("SCHEMA", "public"))
+}
+
+func Test_ACLPrivileges(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal([]string{"CREATE", "CONNECT", "TEMPORARY"}, schema.ACLPrivileges("database"))
+	assert.Equal([]string{"CREATE", "USAGE"}, schema.ACLPrivileges("SCHEMA"))
+	assert.Equal([]string{"CREATE"}, schema.ACLPrivileges("TABLESPACE"))
+	assert.Empty(schema.ACLPrivileges("TABLE"))
+}
@@ -0,0 +1,482 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// AlertMetric is a value measured on the server, which is compared with the
+// threshold of an alert rule
+type AlertMetric string
+
+// AlertSink is where notifications are sent when an alert rule fires
+type AlertSink string
+
+type AlertRuleName string
+
+// AlertRuleMeta is a threshold on a metric, and where to send a notification
+// when the metric goes over the threshold and when it recovers
+type AlertRuleMeta struct {
+	Name      string      `json:"name,omitempty" arg:"" help:"Rule name"`
+	Metric    AlertMetric `json:"metric,omitempty" help:"Metric (lag, connections, wraparound, disk_growth, dead_tuples)"`
+	Threshold *float64    `json:"threshold,omitempty" help:"Threshold which fires the rule when exceeded"`
+	Sink      AlertSink   `json:"sink,omitempty" help:"Where to send notifications (webhook, slack, email)"`
+	Target    string      `json:"target,omitempty" help:"URL of the webhook, or the email address"`
+	Enabled   *bool       `json:"enabled,omitempty" help:"Whether the rule is evaluated"`
+}
+
+// AlertRule is an alert rule and its state when it was last evaluated
+type AlertRule struct {
+	AlertRuleMeta
+	Firing  bool       `json:"firing"`            // True when the metric is over the threshold
+	Value   *float64   `json:"value,omitempty"`   // Value of the metric when the rule was last evaluated
+	Changed *time.Time `json:"changed,omitempty"` // When the rule last fired or recovered
+}
+
+// AlertRuleState is the state of a rule after it is evaluated
+type AlertRuleState struct {
+	Name   string
+	Firing bool
+	Value  float64
+}
+
+type AlertRuleListRequest struct {
+	Enabled *bool `json:"enabled,omitempty" help:"Filter by whether the rule is evaluated"`
+	Firing  *bool `json:"firing,omitempty" help:"Filter by whether the rule is firing"`
+	pg.OffsetLimit
+}
+
+type AlertRuleList struct {
+	Count uint64      `json:"count"`
+	Body  []AlertRule `json:"body,omitempty"`
+}
+
+// AlertMetrics are the values of the metrics on the server
+type AlertMetrics struct {
+	Lag         float64 // Maximum bytes of WAL retained by a replication slot
+	Connections float64 // Percentage of max_connections in use
+	Wraparound  float64 // Maximum age of the oldest unfrozen transaction in a database
+	Size        float64 // Total size of the databases, in bytes
+	DeadTuples  float64 // Maximum ratio of dead tuples in a table (0.0-1.0)
+}
+
+// AlertDeadTupleRatio is the maximum ratio of dead tuples to all tuples in a
+// table of a database, which is measured with a connection to each database
+type AlertDeadTupleRatio float64
+
+// AlertNotification is sent to the sink of a rule when it fires or recovers
+type AlertNotification struct {
+	Rule      string      `json:"rule"`
+	Metric    AlertMetric `json:"metric"`
+	Value     float64     `json:"value"`
+	Threshold float64     `json:"threshold"`
+	Firing    bool        `json:"firing"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	AlertLag         AlertMetric = "lag"         // Bytes of WAL retained by a replication slot
+	AlertConnections AlertMetric = "connections" // Percentage of max_connections in use
+	AlertWraparound  AlertMetric = "wraparound"  // Transactions until a database needs freezing
+	AlertDiskGrowth  AlertMetric = "disk_growth" // Bytes per hour the databases have grown
+	AlertDeadTuples  AlertMetric = "dead_tuples" // Ratio of dead tuples in a table
+)
+
+const (
+	AlertWebhook AlertSink = "webhook" // JSON notification posted to a URL
+	AlertSlack   AlertSink = "slack"   // Message posted to a Slack incoming webhook
+	AlertEmail   AlertSink = "email"   // Message sent to an email address
+)
+
+var (
+	alertMetrics = []AlertMetric{AlertLag, AlertConnections, AlertWraparound, AlertDiskGrowth, AlertDeadTuples}
+	alertSinks   = []AlertSink{AlertWebhook, AlertSlack, AlertEmail}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (r AlertRule) String() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (r AlertRuleMeta) String() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (r AlertRuleList) String() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+// Message returns the notification as a line of text, for chat and email
+func (n AlertNotification) Message() string {
+	state := "recovered"
+	if n.Firing {
+		state = "firing"
+	}
+	return fmt.Sprintf("pgmanager alert %q is %s: %s is %v (threshold %v)", n.Rule, state, n.Metric, n.Value, n.Threshold)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PARSE
+
+// ParseAlertRule parses a rule from configuration, in the form
+// "name metric>threshold sink target", for example
+// "lag lag>1073741824 slack https://hooks.slack.com/services/...". The
+// rule is enabled.
+func ParseAlertRule(v string) (AlertRuleMeta, error) {
+	fields := strings.Fields(v)
+	if len(fields) != 4 {
+		return AlertRuleMeta{}, pg.ErrBadParameter.Withf("invalid alert rule %q, expected \"name metric>threshold sink target\"", v)
+	}
+	metric, value, ok := strings.Cut(fields[1], ">")
+	if !ok {
+		return AlertRuleMeta{}, pg.ErrBadParameter.Withf("invalid alert rule condition %q, expected \"metric>threshold\"", fields[1])
+	}
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return AlertRuleMeta{}, pg.ErrBadParameter.Withf("invalid alert rule threshold %q", value)
+	}
+	enabled := true
+	rule := AlertRuleMeta{
+		Name:      fields[0],
+		Metric:    AlertMetric(metric),
+		Threshold: &threshold,
+		Sink:      AlertSink(fields[2]),
+		Target:    fields[3],
+		Enabled:   &enabled,
+	}
+	if err := rule.Validate(); err != nil {
+		return AlertRuleMeta{}, err
+	}
+	return rule, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// VALIDATION
+
+// Validate returns an error if the metric, sink or target of a rule are
+// invalid. Fields which are empty are not checked, so that a rule can be
+// partially updated
+func (r AlertRuleMeta) Validate() error {
+	if r.Metric != "" && !slices.Contains(alertMetrics, r.Metric) {
+		return pg.ErrBadParameter.Withf("invalid metric %q", r.Metric)
+	}
+	if r.Sink != "" && !slices.Contains(alertSinks, r.Sink) {
+		return pg.ErrBadParameter.Withf("invalid sink %q", r.Sink)
+	}
+	if r.Target == "" {
+		return nil
+	}
+	switch r.Sink {
+	case AlertEmail:
+		if _, err := mail.ParseAddress(r.Target); err != nil {
+			return pg.ErrBadParameter.Withf("invalid email address %q", r.Target)
+		}
+	case AlertWebhook, AlertSlack:
+		if u, err := url.Parse(r.Target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return pg.ErrBadParameter.With("invalid webhook URL")
+		}
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Value returns the value of a metric, given the metrics now and the total
+// size of the databases when they were last measured
+func (m AlertMetrics) Value(metric AlertMetric, previous AlertMetrics, elapsed time.Duration) float64 {
+	switch metric {
+	case AlertLag:
+		return m.Lag
+	case AlertConnections:
+		return m.Connections
+	case AlertWraparound:
+		return m.Wraparound
+	case AlertDiskGrowth:
+		if elapsed <= 0 || previous.Size == 0 {
+			return 0
+		}
+		return (m.Size - previous.Size) / elapsed.Hours()
+	case AlertDeadTuples:
+		return m.DeadTuples
+	default:
+		return 0
+	}
+}
+
+// Redacted returns the rule with the path and query of a webhook URL
+// obfuscated, as they usually contain a secret token
+func (r AlertRule) Redacted() AlertRule {
+	if r.Sink == AlertWebhook || r.Sink == AlertSlack {
+		if u, err := url.Parse(r.Target); err == nil && (u.Path != "" || u.RawQuery != "") {
+			r.Target = u.Scheme + "://" + u.Host + "/" + pgObfuscatedPassword
+		}
+	}
+	return r
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (r AlertRuleName) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	if name := strings.TrimSpace(string(r)); name == "" {
+		return "", pg.ErrBadParameter.With("alert rule name is missing")
+	} else {
+		bind.Set("name", name)
+	}
+
+	// Return query
+	switch op {
+	case pg.Get:
+		return alertRuleGet, nil
+	case pg.Update:
+		return alertRuleUpdate, nil
+	case pg.Delete:
+		return alertRuleDelete, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported AlertRuleName operation %q", op)
+	}
+}
+
+func (r AlertRuleListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Where
+	where := pg.Where()
+	if r.Enabled != nil {
+		where.Eq(`"enabled"`, *r.Enabled)
+	}
+	if r.Firing != nil {
+		where.Eq(`"firing"`, *r.Firing)
+	}
+	where.Bind(bind)
+
+	// Offset and limit
+	r.OffsetLimit.Bind(bind, AlertRuleListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return alertRuleList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported AlertRuleListRequest operation %q", op)
+	}
+}
+
+func (r AlertRuleState) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	if name := strings.TrimSpace(r.Name); name == "" {
+		return "", pg.ErrBadParameter.With("alert rule name is missing")
+	} else {
+		bind.Set("name", name)
+	}
+
+	// Return query
+	switch op {
+	case pg.Update:
+		return alertRuleSetState, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported AlertRuleState operation %q", op)
+	}
+}
+
+func (m AlertMetrics) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	switch op {
+	case pg.Get:
+		return alertMetricsGet, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported AlertMetrics operation %q", op)
+	}
+}
+
+func (r AlertDeadTupleRatio) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	bind.Set("min_tuples", alertDeadTuplesMin)
+
+	// Return query
+	switch op {
+	case pg.Get:
+		return alertDeadTuplesGet, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported AlertDeadTupleRatio operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WRITER
+
+func (r AlertRuleMeta) Insert(bind *pg.Bind) (string, error) {
+	if name := strings.TrimSpace(r.Name); name == "" {
+		return "", pg.ErrBadParameter.With("name is missing")
+	} else {
+		bind.Set("name", name)
+	}
+	if r.Metric == "" {
+		return "", pg.ErrBadParameter.With("metric is missing")
+	} else if r.Threshold == nil {
+		return "", pg.ErrBadParameter.With("threshold is missing")
+	} else if r.Sink == "" {
+		return "", pg.ErrBadParameter.With("sink is missing")
+	} else if r.Target == "" {
+		return "", pg.ErrBadParameter.With("target is missing")
+	} else if err := r.Validate(); err != nil {
+		return "", err
+	}
+	bind.Set("metric", string(r.Metric))
+	bind.Set("threshold", *r.Threshold)
+	bind.Set("sink", string(r.Sink))
+	bind.Set("target", r.Target)
+
+	// Rules are enabled by default
+	if r.Enabled != nil {
+		bind.Set("enabled", *r.Enabled)
+	} else {
+		bind.Set("enabled", true)
+	}
+
+	// Return query
+	return alertRuleInsert, nil
+}
+
+func (r AlertRuleMeta) Update(bind *pg.Bind) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	bind.Set("metric", nilIfEmpty(string(r.Metric)))
+	bind.Set("threshold", r.Threshold)
+	bind.Set("sink", nilIfEmpty(string(r.Sink)))
+	bind.Set("target", nilIfEmpty(r.Target))
+	bind.Set("enabled", r.Enabled)
+	return nil
+}
+
+func (r AlertRuleState) Insert(_ *pg.Bind) (string, error) {
+	return "", pg.ErrNotImplemented.With("AlertRuleState.Insert")
+}
+
+func (r AlertRuleState) Update(bind *pg.Bind) error {
+	bind.Set("firing", r.Firing)
+	bind.Set("value", r.Value)
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (r *AlertRule) Scan(row pg.Row) error {
+	var enabled bool
+	var threshold float64
+	if err := row.Scan(&r.Name, &r.Metric, &threshold, &r.Sink, &r.Target, &enabled, &r.Firing, &r.Value, &r.Changed); err != nil {
+		return err
+	}
+	r.Threshold = &threshold
+	r.Enabled = &enabled
+	return nil
+}
+
+func (l *AlertRuleList) Scan(row pg.Row) error {
+	var rule AlertRule
+	if err := rule.Scan(row); err != nil {
+		return err
+	}
+	l.Body = append(l.Body, rule)
+	return nil
+}
+
+func (l *AlertRuleList) ScanCount(row pg.Row) error {
+	return row.Scan(&l.Count)
+}
+
+func (m *AlertMetrics) Scan(row pg.Row) error {
+	return row.Scan(&m.Lag, &m.Connections, &m.Wraparound, &m.Size)
+}
+
+func (r *AlertDeadTupleRatio) Scan(row pg.Row) error {
+	return row.Scan((*float64)(r))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return nil for an empty string, so the value is not updated
+func nilIfEmpty(v string) any {
+	if v = strings.TrimSpace(v); v == "" {
+		return nil
+	}
+	return v
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	// Tables with fewer tuples are ignored when measuring the dead tuple ratio
+	alertDeadTuplesMin = 1000
+)
+
+const (
+	AlertDeadTupleDef    = `dead_tuples ("ratio" FLOAT8)`
+	alertRuleCreateTable = `
+		CREATE TABLE IF NOT EXISTS ` + ManagerSchema + `.alert_rule (
+			"name" TEXT PRIMARY KEY,
+			"metric" TEXT NOT NULL,
+			"threshold" DOUBLE PRECISION NOT NULL,
+			"sink" TEXT NOT NULL,
+			"target" TEXT NOT NULL,
+			"enabled" BOOLEAN NOT NULL DEFAULT TRUE,
+			"firing" BOOLEAN NOT NULL DEFAULT FALSE,
+			"value" DOUBLE PRECISION,
+			"changed" TIMESTAMPTZ
+		)
+	`
+	alertRuleColumns  = `"name", "metric", "threshold", "sink", "target", "enabled", "firing", "value", "changed"`
+	alertRuleInsert   = `INSERT INTO ` + ManagerSchema + `.alert_rule ("name", "metric", "threshold", "sink", "target", "enabled") VALUES (@name, @metric, @threshold, @sink, @target, @enabled) RETURNING ` + alertRuleColumns
+	alertRuleGet      = `SELECT ` + alertRuleColumns + ` FROM ` + ManagerSchema + `.alert_rule WHERE "name" = @name`
+	alertRuleList     = `SELECT ` + alertRuleColumns + ` FROM ` + ManagerSchema + `.alert_rule ${where} ORDER BY "name"`
+	alertRuleDelete   = `DELETE FROM ` + ManagerSchema + `.alert_rule WHERE "name" = @name RETURNING ` + alertRuleColumns
+	alertRuleUpdate   = `UPDATE ` + ManagerSchema + `.alert_rule SET "metric" = COALESCE(@metric, "metric"), "threshold" = COALESCE(@threshold, "threshold"), "sink" = COALESCE(@sink, "sink"), "target" = COALESCE(@target, "target"), "enabled" = COALESCE(@enabled, "enabled") WHERE "name" = @name RETURNING ` + alertRuleColumns
+	alertRuleSetState = `UPDATE ` + ManagerSchema + `.alert_rule SET "changed" = CASE WHEN "firing" = @firing THEN "changed" ELSE NOW() END, "firing" = @firing, "value" = @value WHERE "name" = @name RETURNING ` + alertRuleColumns
+	alertMetricsGet   = `
+		SELECT
+			COALESCE((
+				SELECT MAX(pg_wal_lsn_diff(CASE WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn() ELSE pg_current_wal_lsn() END, restart_lsn))
+				FROM pg_replication_slots WHERE restart_lsn IS NOT NULL
+			), 0)::DOUBLE PRECISION AS "lag",
+			(SELECT COUNT(*) FROM pg_stat_activity)::DOUBLE PRECISION * 100 / current_setting('max_connections')::DOUBLE PRECISION AS "connections",
+			COALESCE((SELECT MAX(age(datfrozenxid)) FROM pg_database), 0)::DOUBLE PRECISION AS "wraparound",
+			COALESCE((SELECT SUM(pg_database_size(oid)) FROM pg_database WHERE datallowconn), 0)::DOUBLE PRECISION AS "size"
+	`
+	alertDeadTuplesGet = `
+		SELECT
+			COALESCE(MAX(n_dead_tup::DOUBLE PRECISION / (n_live_tup + n_dead_tup)), 0)::DOUBLE PRECISION AS "ratio"
+		FROM
+			pg_stat_user_tables
+		WHERE
+			n_live_tup + n_dead_tup >= @min_tuples
+	`
+)
@@ -0,0 +1,160 @@
+package schema_test
+
+import (
+	"testing"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_AlertRuleMeta_Insert(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Insert", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.AlertRuleMeta{
+			Name:      "lag",
+			Metric:    schema.AlertLag,
+			Threshold: types.Float64Ptr(1e9),
+			Sink:      schema.AlertWebhook,
+			Target:    "https://example.com/hook",
+		}.Insert(bind)
+		assert.NoError(err)
+		assert.Contains(sql, "alert_rule")
+		assert.Equal("lag", bind.Get("metric"))
+		assert.Equal(1e9, bind.Get("threshold"))
+		assert.Equal(true, bind.Get("enabled"))
+	})
+
+	t.Run("MissingThreshold", func(t *testing.T) {
+		_, err := schema.AlertRuleMeta{
+			Name:   "lag",
+			Metric: schema.AlertLag,
+			Sink:   schema.AlertWebhook,
+			Target: "https://example.com/hook",
+		}.Insert(pg.NewBind())
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("InvalidMetric", func(t *testing.T) {
+		_, err := schema.AlertRuleMeta{
+			Name:      "lag",
+			Metric:    "cpu",
+			Threshold: types.Float64Ptr(1),
+			Sink:      schema.AlertWebhook,
+			Target:    "https://example.com/hook",
+		}.Insert(pg.NewBind())
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
+func Test_AlertRuleMeta_Validate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(schema.AlertRuleMeta{Sink: schema.AlertEmail, Target: "dba@example.com"}.Validate())
+	assert.NoError(schema.AlertRuleMeta{Sink: schema.AlertSlack, Target: "https://hooks.slack.com/services/T/B/X"}.Validate())
+	assert.ErrorIs(schema.AlertRuleMeta{Sink: schema.AlertEmail, Target: "not an address"}.Validate(), pg.ErrBadParameter)
+	assert.ErrorIs(schema.AlertRuleMeta{Sink: schema.AlertWebhook, Target: "ftp://example.com"}.Validate(), pg.ErrBadParameter)
+	assert.ErrorIs(schema.AlertRuleMeta{Sink: "pager"}.Validate(), pg.ErrBadParameter)
+}
+
+func Test_AlertRuleMeta_Update(t *testing.T) {
+	assert := assert.New(t)
+
+	bind := pg.NewBind()
+	assert.NoError(schema.AlertRuleMeta{Enabled: types.BoolPtr(false)}.Update(bind))
+	assert.Nil(bind.Get("metric"))
+	assert.Nil(bind.Get("target"))
+	assert.Equal(types.BoolPtr(false), bind.Get("enabled"))
+
+	sql, err := schema.AlertRuleName("lag").Select(bind, pg.Update)
+	assert.NoError(err)
+	assert.Contains(sql, "COALESCE")
+}
+
+func Test_ParseAlertRule(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Parse", func(t *testing.T) {
+		rule, err := schema.ParseAlertRule(" dead  dead_tuples>0.2 email dba@example.com ")
+		assert.NoError(err)
+		assert.Equal("dead", rule.Name)
+		assert.Equal(schema.AlertDeadTuples, rule.Metric)
+		assert.Equal(types.Float64Ptr(0.2), rule.Threshold)
+		assert.Equal(schema.AlertEmail, rule.Sink)
+		assert.Equal("dba@example.com", rule.Target)
+		assert.Equal(types.BoolPtr(true), rule.Enabled)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, v := range []string{
+			"",
+			"dead dead_tuples>0.2 email",
+			"dead dead_tuples=0.2 email dba@example.com",
+			"dead dead_tuples>high email dba@example.com",
+			"dead cpu>0.2 email dba@example.com",
+			"dead dead_tuples>0.2 email not-an-address",
+		} {
+			_, err := schema.ParseAlertRule(v)
+			assert.ErrorIs(err, pg.ErrBadParameter, v)
+		}
+	})
+}
+
+func Test_AlertRuleListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	bind := pg.NewBind()
+	_, err := schema.AlertRuleListRequest{Enabled: types.BoolPtr(true), Firing: types.BoolPtr(true)}.Select(bind, pg.List)
+	assert.NoError(err)
+	assert.Equal(`WHERE "enabled" = @enabled AND "firing" = @firing`, bind.Get("where"))
+}
+
+func Test_AlertDeadTupleRatio_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	sql, err := schema.AlertDeadTupleRatio(0).Select(pg.NewBind(), pg.Get)
+	assert.NoError(err)
+	assert.Contains(sql, "pg_stat_user_tables")
+
+	_, err = schema.AlertDeadTupleRatio(0).Select(pg.NewBind(), pg.List)
+	assert.ErrorIs(err, pg.ErrNotImplemented)
+}
+
+func Test_AlertMetrics_Value(t *testing.T) {
+	assert := assert.New(t)
+
+	previous := schema.AlertMetrics{Size: 1000}
+	metrics := schema.AlertMetrics{Lag: 10, Connections: 50, Wraparound: 100, Size: 3000, DeadTuples: 0.25}
+	assert.Equal(float64(10), metrics.Value(schema.AlertLag, previous, time.Hour))
+	assert.Equal(float64(50), metrics.Value(schema.AlertConnections, previous, time.Hour))
+	assert.Equal(float64(100), metrics.Value(schema.AlertWraparound, previous, time.Hour))
+	assert.Equal(float64(1000), metrics.Value(schema.AlertDiskGrowth, previous, 2*time.Hour))
+	assert.Equal(0.25, metrics.Value(schema.AlertDeadTuples, previous, time.Hour))
+
+	// No growth is measured the first time
+	assert.Equal(float64(0), metrics.Value(schema.AlertDiskGrowth, schema.AlertMetrics{}, time.Hour))
+}
+
+func Test_AlertRule_Redacted(t *testing.T) {
+	assert := assert.New(t)
+
+	rule := schema.AlertRule{AlertRuleMeta: schema.AlertRuleMeta{Sink: schema.AlertSlack, Target: "https://hooks.slack.com/services/T/B/X"}}
+	assert.Equal("https://hooks.slack.com/********", rule.Redacted().Target)
+
+	rule = schema.AlertRule{AlertRuleMeta: schema.AlertRuleMeta{Sink: schema.AlertEmail, Target: "dba@example.com"}}
+	assert.Equal("dba@example.com", rule.Redacted().Target)
+}
+
+func Test_AlertNotification_Message(t *testing.T) {
+	assert := assert.New(t)
+
+	n := schema.AlertNotification{Rule: "lag", Metric: schema.AlertLag, Value: 2, Threshold: 1, Firing: true}
+	assert.Contains(n.Message(), "firing")
+	n.Firing = false
+	assert.Contains(n.Message(), "recovered")
+}
@@ -0,0 +1,226 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// AuditOperation is the type of operation recorded in the audit log
+type AuditOperation string
+
+// AuditMeta records a create, update or delete operation made through the
+// manager. The error is nil when the operation succeeded
+type AuditMeta struct {
+	Resource  string          `json:"resource"`
+	Operation AuditOperation  `json:"operation"`
+	Name      string          `json:"name"`              // Resource name, such as "mydb/public" for a schema
+	Actor     *string         `json:"actor,omitempty"`   // User which made the request, or the current role when nil
+	Request   json.RawMessage `json:"request,omitempty"` // Request payload, with any passwords obfuscated
+	Error     *string         `json:"error,omitempty"`
+}
+
+// Audit is an operation recorded in the audit log
+type Audit struct {
+	Id uint64 `json:"id"`
+	AuditMeta
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type AuditListRequest struct {
+	Resource *string    `json:"resource,omitempty" help:"Filter by resource"`
+	Actor    *string    `json:"actor,omitempty" help:"Filter by actor"`
+	Since    *time.Time `json:"since,omitempty" help:"Operations at or after this time"`
+	Until    *time.Time `json:"until,omitempty" help:"Operations before this time"`
+	Request  *string    `json:"request,omitempty" help:"Filter by a JSON document which the request contains"`
+	Path     *string    `json:"path,omitempty" help:"Filter by a jsonpath expression which matches the request"`
+	pg.OffsetLimit
+}
+
+type AuditList struct {
+	Count uint64  `json:"count"`
+	Body  []Audit `json:"body,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	AuditCreate AuditOperation = "create"
+	AuditUpdate AuditOperation = "update"
+	AuditDelete AuditOperation = "delete"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (a Audit) String() string {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (a AuditList) String() string {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// AuditRequest returns the request payload of an operation for the audit
+// log, replacing any password with an obfuscated value
+func AuditRequest(v any) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Not an object, so there is no password to obfuscate
+		return data, nil
+	}
+	if password, exists := fields["password"]; !exists || password == "" {
+		return data, nil
+	}
+	fields["password"] = pgObfuscatedPassword
+	return json.Marshal(fields)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (r AuditListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Where
+	where := pg.Where()
+	if r.Resource != nil {
+		if resource := strings.TrimSpace(*r.Resource); resource != "" {
+			where.Eq(`"resource"`, resource)
+		}
+	}
+	if r.Actor != nil {
+		if actor := strings.TrimSpace(*r.Actor); actor != "" {
+			where.Eq(`"actor"`, actor)
+		}
+	}
+	if r.Since != nil && !r.Since.IsZero() {
+		where.Gte(`"timestamp"`, *r.Since)
+	}
+	if r.Until != nil && !r.Until.IsZero() {
+		where.Lt(`"timestamp"`, *r.Until)
+	}
+	if r.Request != nil {
+		if request := strings.TrimSpace(*r.Request); request != "" {
+			if !json.Valid([]byte(request)) {
+				return "", pg.ErrBadParameter.With("request is not a valid JSON document")
+			}
+			where.Contains(`"request"`, json.RawMessage(request))
+		}
+	}
+	if r.Path != nil {
+		if path := strings.TrimSpace(*r.Path); path != "" {
+			where.JSONPath(`"request"`, path)
+		}
+	}
+	where.Bind(bind)
+
+	// Bind offset and limit
+	r.OffsetLimit.Bind(bind, AuditListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return auditList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported AuditListRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WRITER
+
+func (m AuditMeta) Insert(bind *pg.Bind) (string, error) {
+	if resource := strings.TrimSpace(m.Resource); resource == "" {
+		return "", pg.ErrBadParameter.With("resource is missing")
+	} else {
+		bind.Set("resource", resource)
+	}
+	switch m.Operation {
+	case AuditCreate, AuditUpdate, AuditDelete:
+		bind.Set("operation", string(m.Operation))
+	default:
+		return "", pg.ErrBadParameter.Withf("invalid operation %q", m.Operation)
+	}
+	bind.Set("name", m.Name)
+	if m.Actor != nil && strings.TrimSpace(*m.Actor) != "" {
+		bind.Set("actor", strings.TrimSpace(*m.Actor))
+	} else {
+		bind.Set("actor", nil)
+	}
+	if len(m.Request) > 0 {
+		bind.Set("request", pg.JSON(m.Request))
+	} else {
+		bind.Set("request", nil)
+	}
+	bind.Set("error", m.Error)
+
+	// Return query
+	return auditInsert, nil
+}
+
+func (m AuditMeta) Update(_ *pg.Bind) error {
+	return pg.ErrNotImplemented.With("audit log cannot be updated")
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (a *Audit) Scan(row pg.Row) error {
+	return row.Scan(&a.Id, &a.Resource, &a.Operation, &a.Name, &a.Actor, pg.ScanJSON(&a.Request), &a.Error, &a.Timestamp)
+}
+
+func (l *AuditList) Scan(row pg.Row) error {
+	var audit Audit
+	if err := audit.Scan(row); err != nil {
+		return err
+	}
+	l.Body = append(l.Body, audit)
+	return nil
+}
+
+func (l *AuditList) ScanCount(row pg.Row) error {
+	return row.Scan(&l.Count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	auditCreateTable = `
+		CREATE TABLE IF NOT EXISTS ` + ManagerSchema + `.audit (
+			"id" BIGSERIAL PRIMARY KEY,
+			"resource" TEXT NOT NULL,
+			"operation" TEXT NOT NULL,
+			"name" TEXT NOT NULL,
+			"actor" TEXT NOT NULL DEFAULT CURRENT_USER,
+			"request" JSONB,
+			"error" TEXT,
+			"timestamp" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`
+	auditCreateIndex = `CREATE INDEX IF NOT EXISTS audit_timestamp_idx ON ` + ManagerSchema + `.audit ("timestamp")`
+	auditColumns     = `"id", "resource", "operation", "name", "actor", "request", "error", "timestamp"`
+	auditInsert      = `INSERT INTO ` + ManagerSchema + `.audit ("resource", "operation", "name", "actor", "request", "error") VALUES (@resource, @operation, @name, COALESCE(@actor, CURRENT_USER), CAST(@request AS JSONB), @error) RETURNING ` + auditColumns
+	auditList        = `SELECT ` + auditColumns + ` FROM ` + ManagerSchema + `.audit ${where} ORDER BY "timestamp" DESC, "id" DESC`
+)
@@ -0,0 +1,141 @@
+package schema_test
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_AuditMeta_Insert(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Insert", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.AuditMeta{
+			Resource:  "role",
+			Operation: schema.AuditCreate,
+			Name:      "test_role",
+			Actor:     types.StringPtr("alice"),
+			Request:   json.RawMessage(`{"name":"test_role"}`),
+		}.Insert(bind)
+		assert.NoError(err)
+		assert.Contains(sql, "audit")
+		assert.Equal("role", bind.Get("resource"))
+		assert.Equal("create", bind.Get("operation"))
+		assert.Equal("alice", bind.Get("actor"))
+		if request, ok := bind.Get("request").(driver.Valuer); assert.True(ok) {
+			value, err := request.Value()
+			assert.NoError(err)
+			assert.Equal(`{"name":"test_role"}`, value)
+		}
+	})
+
+	t.Run("CurrentRole", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.AuditMeta{Resource: "role", Operation: schema.AuditDelete, Name: "test_role"}.Insert(bind)
+		assert.NoError(err)
+		assert.Nil(bind.Get("actor"))
+		assert.Nil(bind.Get("request"))
+	})
+
+	t.Run("MissingResource", func(t *testing.T) {
+		_, err := schema.AuditMeta{Operation: schema.AuditCreate}.Insert(pg.NewBind())
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("InvalidOperation", func(t *testing.T) {
+		_, err := schema.AuditMeta{Resource: "role", Operation: "select"}.Insert(pg.NewBind())
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		err := schema.AuditMeta{}.Update(pg.NewBind())
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_AuditListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.AuditListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal("", bind.Get("where"))
+	})
+
+	t.Run("ListByResourceAndActor", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.AuditListRequest{Resource: types.StringPtr("role"), Actor: types.StringPtr("alice")}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(bind.Get("where"), `"resource" = @resource`)
+		assert.Contains(bind.Get("where"), `"actor" = @actor`)
+		assert.Equal("role", bind.Get("resource"))
+		assert.Equal("alice", bind.Get("actor"))
+	})
+
+	t.Run("ListByTimeRange", func(t *testing.T) {
+		bind := pg.NewBind()
+		since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		until := since.Add(24 * time.Hour)
+		_, err := schema.AuditListRequest{Since: &since, Until: &until}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(bind.Get("where"), `"timestamp" >= @timestamp`)
+		assert.Contains(bind.Get("where"), `"timestamp" < @timestamp_2`)
+		assert.Equal(since, bind.Get("timestamp"))
+		assert.Equal(until, bind.Get("timestamp_2"))
+	})
+
+	t.Run("ListByRequest", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.AuditListRequest{Request: types.StringPtr(`{"name":"test_role"}`), Path: types.StringPtr(`$.name ? (@ starts with "test")`)}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal(`WHERE "request" @> CAST(@request AS JSONB) AND "request" @? CAST(@request_2 AS JSONPATH)`, bind.Get("where"))
+		if request, ok := bind.Get("request").(driver.Valuer); assert.True(ok) {
+			value, err := request.Value()
+			assert.NoError(err)
+			assert.Equal(`{"name":"test_role"}`, value)
+		}
+		assert.Equal(`$.name ? (@ starts with "test")`, bind.Get("request_2"))
+	})
+
+	t.Run("ListByInvalidRequest", func(t *testing.T) {
+		_, err := schema.AuditListRequest{Request: types.StringPtr(`{"name"`)}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.AuditListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_AuditRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("ObfuscatePassword", func(t *testing.T) {
+		data, err := schema.AuditRequest(schema.RoleMeta{Name: "test_role", Password: types.StringPtr("secret")})
+		assert.NoError(err)
+		assert.NotContains(string(data), "secret")
+		assert.Contains(string(data), `"password":"********"`)
+	})
+
+	t.Run("NoPassword", func(t *testing.T) {
+		data, err := schema.AuditRequest(schema.RoleMeta{Name: "test_role"})
+		assert.NoError(err)
+		assert.JSONEq(`{"name":"test_role"}`, string(data))
+	})
+
+	t.Run("NotObject", func(t *testing.T) {
+		data, err := schema.AuditRequest("value")
+		assert.NoError(err)
+		assert.Equal(`"value"`, string(data))
+	})
+}
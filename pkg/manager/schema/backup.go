@@ -0,0 +1,205 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// BackupMeta contains the parameters for starting a base backup
+type BackupMeta struct {
+	Label string `json:"label" arg:"" help:"Backup label"`
+	Fast  bool   `json:"fast,omitempty" help:"Request an immediate checkpoint"`
+}
+
+// BackupStopRequest contains the parameters for stopping a base backup
+type BackupStopRequest struct {
+	WaitForArchive bool `json:"wait,omitempty" help:"Wait for WAL segments to be archived"`
+}
+
+// Backup represents a base backup which is in progress or has completed
+type Backup struct {
+	BackupMeta
+	StartTime  time.Time  `json:"start_time"`
+	StartLSN   string     `json:"start_lsn"`
+	StopTime   *time.Time `json:"stop_time,omitempty"`
+	StopLSN    *string    `json:"stop_lsn,omitempty"`
+	LabelFile  *string    `json:"label_file,omitempty"`
+	SpcmapFile *string    `json:"spcmap_file,omitempty"`
+}
+
+// BackupStopReader scans the completion of a base backup into a Backup
+type BackupStopReader struct {
+	*Backup
+}
+
+// DatabaseDumpRequest contains the parameters for dumping a database
+type DatabaseDumpRequest struct {
+	Format     string `json:"format,omitempty" help:"Dump format (plain, custom, tar)"`
+	SchemaOnly bool   `json:"schema_only,omitempty" help:"Dump only the object definitions"`
+	DataOnly   bool   `json:"data_only,omitempty" help:"Dump only the data"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	DumpFormatPlain  = "plain"
+	DumpFormatCustom = "custom"
+	DumpFormatTar    = "tar"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (b Backup) String() string {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (b BackupMeta) String() string {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (d DatabaseDumpRequest) String() string {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (r BackupStopRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	bind.Set("wait", r.WaitForArchive)
+
+	// Return query
+	switch op {
+	case pg.Delete:
+		return backupStop, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported BackupStopRequest operation %q", op)
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// WRITER
+
+func (b BackupMeta) Insert(bind *pg.Bind) (string, error) {
+	if label := strings.TrimSpace(b.Label); label == "" {
+		return "", pg.ErrBadParameter.With("label is missing")
+	} else {
+		bind.Set("label", label)
+	}
+	bind.Set("fast", b.Fast)
+
+	// Return query
+	return backupStart, nil
+}
+
+func (b BackupMeta) Update(_ *pg.Bind) error {
+	return pg.ErrNotImplemented.With("backups cannot be updated")
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (b *Backup) Scan(row pg.Row) error {
+	return row.Scan(&b.StartLSN, &b.StartTime)
+}
+
+func (r BackupStopReader) Scan(row pg.Row) error {
+	var stopTime time.Time
+	if err := row.Scan(&r.StopLSN, &r.LabelFile, &r.SpcmapFile, &stopTime); err != nil {
+		return err
+	}
+	r.StopTime = &stopTime
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Validate checks the dump format and options
+func (d DatabaseDumpRequest) Validate() error {
+	switch d.format() {
+	case DumpFormatPlain, DumpFormatCustom, DumpFormatTar:
+		// Valid format
+	default:
+		return pg.ErrBadParameter.Withf("invalid dump format %q", d.Format)
+	}
+	if d.SchemaOnly && d.DataOnly {
+		return pg.ErrBadParameter.With("schema_only and data_only cannot both be set")
+	}
+	return nil
+}
+
+// Args returns the pg_dump command line arguments for the request
+func (d DatabaseDumpRequest) Args() []string {
+	args := []string{"--format=" + d.format(), "--no-password"}
+	if d.SchemaOnly {
+		args = append(args, "--schema-only")
+	}
+	if d.DataOnly {
+		args = append(args, "--data-only")
+	}
+	return args
+}
+
+// ContentType returns the MIME type of the dump output
+func (d DatabaseDumpRequest) ContentType() string {
+	switch d.format() {
+	case DumpFormatPlain:
+		return "application/sql"
+	case DumpFormatTar:
+		return "application/x-tar"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Filename returns a filename for the dump of the named database
+func (d DatabaseDumpRequest) Filename(database string) string {
+	switch d.format() {
+	case DumpFormatPlain:
+		return database + ".sql"
+	case DumpFormatTar:
+		return database + ".tar"
+	default:
+		return database + ".dump"
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (d DatabaseDumpRequest) format() string {
+	if format := strings.ToLower(strings.TrimSpace(d.Format)); format == "" {
+		return DumpFormatPlain
+	} else {
+		return format
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	backupStart = `SELECT pg_backup_start(@label, @fast)::TEXT, clock_timestamp()`
+	backupStop  = `SELECT lsn::TEXT, labelfile, spcmapfile, clock_timestamp() FROM pg_backup_stop(@wait)`
+)
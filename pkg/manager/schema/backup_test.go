@@ -0,0 +1,101 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_BackupMeta_Insert(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("ValidLabel", func(t *testing.T) {
+		bind := pg.NewBind()
+		q, err := schema.BackupMeta{Label: " nightly ", Fast: true}.Insert(bind)
+		assert.NoError(err)
+		assert.Contains(q, "pg_backup_start")
+		assert.Equal("nightly", bind.Get("label"))
+		assert.Equal(true, bind.Get("fast"))
+	})
+
+	t.Run("EmptyLabel", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.BackupMeta{}.Insert(bind)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		err := schema.BackupMeta{Label: "nightly"}.Update(pg.NewBind())
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_BackupStopRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Delete", func(t *testing.T) {
+		bind := pg.NewBind()
+		q, err := schema.BackupStopRequest{WaitForArchive: true}.Select(bind, pg.Delete)
+		assert.NoError(err)
+		assert.Contains(q, "pg_backup_stop")
+		assert.Equal(true, bind.Get("wait"))
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.BackupStopRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_Backup_String(t *testing.T) {
+	assert := assert.New(t)
+
+	b := schema.Backup{BackupMeta: schema.BackupMeta{Label: "nightly"}, StartLSN: "0/2000028"}
+	var parsed schema.Backup
+	assert.NoError(json.Unmarshal([]byte(b.String()), &parsed))
+	assert.Equal("nightly", parsed.Label)
+	assert.Equal("0/2000028", parsed.StartLSN)
+	assert.Nil(parsed.StopLSN)
+}
+
+func Test_DatabaseDumpRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("DefaultFormat", func(t *testing.T) {
+		req := schema.DatabaseDumpRequest{}
+		assert.NoError(req.Validate())
+		assert.Equal([]string{"--format=plain", "--no-password"}, req.Args())
+		assert.Equal("application/sql", req.ContentType())
+		assert.Equal("test.sql", req.Filename("test"))
+	})
+
+	t.Run("CustomFormat", func(t *testing.T) {
+		req := schema.DatabaseDumpRequest{Format: "Custom", SchemaOnly: true}
+		assert.NoError(req.Validate())
+		assert.Equal([]string{"--format=custom", "--no-password", "--schema-only"}, req.Args())
+		assert.Equal("application/octet-stream", req.ContentType())
+		assert.Equal("test.dump", req.Filename("test"))
+	})
+
+	t.Run("TarFormat", func(t *testing.T) {
+		req := schema.DatabaseDumpRequest{Format: "tar", DataOnly: true}
+		assert.NoError(req.Validate())
+		assert.Equal([]string{"--format=tar", "--no-password", "--data-only"}, req.Args())
+		assert.Equal("application/x-tar", req.ContentType())
+		assert.Equal("test.tar", req.Filename("test"))
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		req := schema.DatabaseDumpRequest{Format: "directory"}
+		assert.ErrorIs(req.Validate(), pg.ErrBadParameter)
+	})
+
+	t.Run("SchemaAndDataOnly", func(t *testing.T) {
+		req := schema.DatabaseDumpRequest{SchemaOnly: true, DataOnly: true}
+		assert.ErrorIs(req.Validate(), pg.ErrBadParameter)
+	})
+}
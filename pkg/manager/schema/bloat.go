@@ -0,0 +1,295 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Bloat is the estimated space in a table or btree index which is not used
+// by live tuples, calculated from the planner statistics
+type Bloat struct {
+	Database string  `json:"database"`
+	Schema   string  `json:"schema"`
+	Table    string  `json:"table"`
+	Index    string  `json:"index,omitempty"`
+	Type     string  `json:"type"`
+	Size     uint64  `json:"bytes"`
+	Bloat    uint64  `json:"bloat_bytes"`
+	Ratio    float64 `json:"bloat_ratio"` // Ratio of bloat to size (0.0-1.0)
+}
+
+type BloatListRequest struct {
+	Database *string `json:"database,omitempty" help:"Database"`
+	Schema   *string `json:"schema,omitempty" help:"Schema"`
+	Type     *string `json:"type,omitempty" help:"Type (TABLE or INDEX)"`
+	pg.OffsetLimit
+}
+
+type BloatList struct {
+	Count uint64  `json:"count"`
+	Body  []Bloat `json:"body,omitempty"`
+}
+
+// DatabaseGrowth is the size of a database, and how much it has grown since
+// the size was previously sampled
+type DatabaseGrowth struct {
+	Name     string     `json:"name"`
+	Size     uint64     `json:"bytes"`
+	Growth   *int64     `json:"growth_bytes,omitempty"` // Bytes since the previous sample, which is negative if the database shrank
+	Previous *time.Time `json:"previous,omitempty"`     // When the previous sample was taken
+}
+
+// BloatReport is the estimated bloat of tables and indexes, ordered by the
+// most bloat first within each database, and the growth of each database
+type BloatReport struct {
+	Databases []DatabaseGrowth `json:"databases,omitempty"`
+	BloatList
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	BloatTypeTable = "TABLE"
+	BloatTypeIndex = "INDEX"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (b Bloat) String() string {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (b BloatList) String() string {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (r BloatReport) String() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (b BloatListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Where
+	where := pg.Where()
+	if b.Schema != nil {
+		if schema := strings.TrimSpace(*b.Schema); schema != "" {
+			where.Eq(`schema`, schema)
+		}
+	}
+	if b.Type != nil {
+		switch t := strings.ToUpper(strings.TrimSpace(*b.Type)); t {
+		case "":
+			// No filter
+		case BloatTypeTable, BloatTypeIndex:
+			where.Eq(`type`, t)
+		default:
+			return "", pg.ErrBadParameter.Withf("invalid type %q", *b.Type)
+		}
+	}
+	where.Bind(bind)
+
+	// Bind offset and limit
+	b.OffsetLimit.Bind(bind, BloatListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return bloatList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported BloatListRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (b *Bloat) Scan(row pg.Row) error {
+	var index *string
+	if err := row.Scan(&b.Database, &b.Schema, &b.Table, &index, &b.Type, &b.Size, &b.Bloat, &b.Ratio); err != nil {
+		return err
+	}
+	if index != nil {
+		b.Index = *index
+	} else {
+		b.Index = ""
+	}
+	return nil
+}
+
+func (b *BloatList) Scan(row pg.Row) error {
+	var bloat Bloat
+	if err := bloat.Scan(row); err != nil {
+		return err
+	} else {
+		b.Body = append(b.Body, bloat)
+	}
+	return nil
+}
+
+func (b *BloatList) ScanCount(row pg.Row) error {
+	return row.Scan(&b.Count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+// The estimates are those of https://github.com/ioguix/pgsql-bloat-estimation,
+// which compare the pages used with the pages needed for the tuples, given
+// their average width and the fillfactor. Tables with columns which have no
+// statistics, or of type name, cannot be estimated and are excluded.
+const (
+	BloatDef        = `bloat ("database" TEXT, "schema" TEXT, "table" TEXT, "index" TEXT, "type" TEXT, "size" BIGINT, "bloat" BIGINT, "ratio" FLOAT8)`
+	bloatTableQuery = `
+		SELECT
+			schemaname, tblname, bs * tblpages AS size,
+			CASE WHEN tblpages - est_tblpages_ff > 0 THEN (tblpages - est_tblpages_ff) * bs ELSE 0 END AS bloat
+		FROM (
+			SELECT
+				ceil(reltuples / ((bs - page_hdr) * fillfactor / (tpl_size * 100))) + ceil(toasttuples / 4) AS est_tblpages_ff,
+				tblpages, bs, schemaname, tblname, is_na
+			FROM (
+				SELECT
+					(4 + tpl_hdr_size + tpl_data_size + (2 * ma)
+						- CASE WHEN tpl_hdr_size % ma = 0 THEN ma ELSE tpl_hdr_size % ma END
+						- CASE WHEN ceil(tpl_data_size)::INT % ma = 0 THEN ma ELSE ceil(tpl_data_size)::INT % ma END
+					) AS tpl_size,
+					(heappages + toastpages) AS tblpages, reltuples, toasttuples, bs, page_hdr, schemaname, tblname, fillfactor, is_na
+				FROM (
+					SELECT
+						ns.nspname AS schemaname, tbl.relname AS tblname, tbl.reltuples,
+						tbl.relpages AS heappages, COALESCE(toast.relpages, 0) AS toastpages,
+						COALESCE(toast.reltuples, 0) AS toasttuples,
+						COALESCE(substring(array_to_string(tbl.reloptions, ' ') FROM 'fillfactor=([0-9]+)')::SMALLINT, 100) AS fillfactor,
+						current_setting('block_size')::NUMERIC AS bs,
+						CASE WHEN version() ~ 'mingw32' OR version() ~ '64-bit|x86_64|ppc64|ia64|amd64' THEN 8 ELSE 4 END AS ma,
+						24 AS page_hdr,
+						23 + CASE WHEN MAX(COALESCE(s.null_frac, 0)) > 0 THEN (7 + count(s.attname)) / 8 ELSE 0::INT END AS tpl_hdr_size,
+						sum((1 - COALESCE(s.null_frac, 0)) * COALESCE(s.avg_width, 0)) AS tpl_data_size,
+						bool_or(att.atttypid = 'pg_catalog.name'::REGTYPE) OR sum(CASE WHEN att.attnum > 0 THEN 1 ELSE 0 END) <> count(s.attname) AS is_na
+					FROM
+						pg_attribute att
+					JOIN
+						pg_class tbl ON att.attrelid = tbl.oid
+					JOIN
+						pg_namespace ns ON ns.oid = tbl.relnamespace
+					LEFT JOIN
+						pg_stats s ON s.schemaname = ns.nspname AND s.tablename = tbl.relname AND s.inherited = false AND s.attname = att.attname
+					LEFT JOIN
+						pg_class toast ON tbl.reltoastrelid = toast.oid
+					WHERE
+						NOT att.attisdropped AND tbl.relkind IN ('r', 'm') AND ns.nspname NOT IN ('pg_catalog', 'information_schema')
+					GROUP BY
+						1, 2, 3, 4, 5, 6, 7, 8
+				) s
+			) s2
+		) s3
+		WHERE
+			NOT is_na
+	`
+	bloatIndexQuery = `
+		SELECT
+			nspname, tblname, idxname, bs * relpages AS size,
+			CASE WHEN relpages > est_pages_ff THEN bs * (relpages - est_pages_ff) ELSE 0 END AS bloat
+		FROM (
+			SELECT
+				COALESCE(1 + ceil(reltuples / floor((bs - pageopqdata - pagehdr) * fillfactor / (100 * (4 + nulldatahdrwidth)::FLOAT8))), 0) AS est_pages_ff,
+				bs, nspname, tblname, idxname, relpages, is_na
+			FROM (
+				SELECT
+					bs, nspname, tblname, idxname, reltuples, relpages, fillfactor,
+					(index_tuple_hdr_bm
+						+ maxalign - CASE WHEN index_tuple_hdr_bm % maxalign = 0 THEN maxalign ELSE index_tuple_hdr_bm % maxalign END
+						+ nulldatawidth + maxalign - CASE WHEN nulldatawidth = 0 THEN 0 WHEN nulldatawidth::INT % maxalign = 0 THEN maxalign ELSE nulldatawidth::INT % maxalign END
+					)::NUMERIC AS nulldatahdrwidth,
+					pagehdr, pageopqdata, is_na
+				FROM (
+					SELECT
+						n.nspname, i.tblname, i.idxname, i.reltuples, i.relpages, i.idxoid, i.fillfactor,
+						current_setting('block_size')::NUMERIC AS bs,
+						CASE WHEN version() ~ 'mingw32' OR version() ~ '64-bit|x86_64|ppc64|ia64|amd64' THEN 8 ELSE 4 END AS maxalign,
+						24 AS pagehdr,
+						16 AS pageopqdata,
+						CASE WHEN max(COALESCE(s.null_frac, 0)) = 0 THEN 8 ELSE 8 + ((32 + 8 - 1) / 8) END AS index_tuple_hdr_bm,
+						sum((1 - COALESCE(s.null_frac, 0)) * COALESCE(s.avg_width, 1024)) AS nulldatawidth,
+						max(CASE WHEN i.atttypid = 'pg_catalog.name'::REGTYPE THEN 1 ELSE 0 END) > 0 AS is_na
+					FROM (
+						SELECT
+							ct.relname AS tblname, ct.relnamespace, ic.idxname, ic.reltuples, ic.relpages, ic.idxoid, ic.fillfactor,
+							COALESCE(a1.attname, a2.attname) AS attname,
+							COALESCE(a1.atttypid, a2.atttypid) AS atttypid,
+							CASE WHEN a1.attnum IS NULL THEN ic.idxname ELSE ct.relname END AS attrelname
+						FROM (
+							SELECT
+								idxname, reltuples, relpages, tbloid, idxoid, fillfactor, indkey,
+								generate_series(1, indnatts) AS attpos
+							FROM (
+								SELECT
+									ci.relname AS idxname, ci.reltuples, ci.relpages, i.indrelid AS tbloid, i.indexrelid AS idxoid,
+									COALESCE(substring(array_to_string(ci.reloptions, ' ') FROM 'fillfactor=([0-9]+)')::SMALLINT, 90) AS fillfactor,
+									i.indnatts,
+									string_to_array(textin(int2vectorout(i.indkey)), ' ')::INT[] AS indkey
+								FROM
+									pg_index i
+								JOIN
+									pg_class ci ON ci.oid = i.indexrelid
+								WHERE
+									ci.relam = (SELECT oid FROM pg_am WHERE amname = 'btree') AND ci.relpages > 0
+							) idx_data
+						) ic
+						JOIN
+							pg_class ct ON ct.oid = ic.tbloid
+						LEFT JOIN
+							pg_attribute a1 ON ic.indkey[ic.attpos] <> 0 AND a1.attrelid = ic.tbloid AND a1.attnum = ic.indkey[ic.attpos]
+						LEFT JOIN
+							pg_attribute a2 ON ic.indkey[ic.attpos] = 0 AND a2.attrelid = ic.idxoid AND a2.attnum = ic.attpos
+					) i
+					JOIN
+						pg_namespace n ON n.oid = i.relnamespace
+					JOIN
+						pg_stats s ON s.schemaname = n.nspname AND s.tablename = i.attrelname AND s.attname = i.attname
+					WHERE
+						n.nspname NOT IN ('pg_catalog', 'information_schema')
+					GROUP BY
+						1, 2, 3, 4, 5, 6, 7
+				) rows_data_stats
+			) rows_hdr_pdata_stats
+		) relation_stats
+		WHERE
+			NOT is_na
+	`
+	bloatSelect = `
+		SELECT
+			current_database()::TEXT AS "database", schemaname::TEXT AS "schema", tblname::TEXT AS "table", NULL::TEXT AS "index", 'TABLE' AS "type",
+			size::BIGINT AS "size", bloat::BIGINT AS "bloat", CASE WHEN size > 0 THEN (bloat / size)::FLOAT8 ELSE 0 END AS "ratio"
+		FROM (` + bloatTableQuery + `) t
+		UNION ALL
+		SELECT
+			current_database()::TEXT AS "database", nspname::TEXT AS "schema", tblname::TEXT AS "table", idxname::TEXT AS "index", 'INDEX' AS "type",
+			size::BIGINT AS "size", bloat::BIGINT AS "bloat", CASE WHEN size > 0 THEN (bloat / size)::FLOAT8 ELSE 0 END AS "ratio"
+		FROM (` + bloatIndexQuery + `) i
+	`
+	bloatList = `WITH q AS (` + bloatSelect + `) SELECT * FROM q ${where} ORDER BY "bloat" DESC, "schema", "table", "index" NULLS FIRST`
+)
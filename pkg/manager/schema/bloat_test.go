@@ -0,0 +1,76 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_BloatListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.BloatListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_stats")
+		assert.Contains(sql, "btree")
+		assert.Equal("", bind.Get("where"))
+	})
+
+	t.Run("ListWithFilters", func(t *testing.T) {
+		bind := pg.NewBind()
+		namespace, typ := "public", "index"
+		_, err := schema.BloatListRequest{Schema: &namespace, Type: &typ}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal(`WHERE schema = @schema AND type = @type`, bind.Get("where"))
+		assert.Equal("INDEX", bind.Get("type"))
+	})
+
+	t.Run("InvalidType", func(t *testing.T) {
+		typ := "view"
+		_, err := schema.BloatListRequest{Type: &typ}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.BloatListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_BloatReport_String(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("ProducesJSON", func(t *testing.T) {
+		growth := int64(-8192)
+		report := schema.BloatReport{
+			Databases: []schema.DatabaseGrowth{
+				{Name: "testdb", Size: 16384, Growth: &growth},
+			},
+			BloatList: schema.BloatList{
+				Count: 1,
+				Body: []schema.Bloat{
+					{Database: "testdb", Schema: "public", Table: "users", Index: "users_pkey", Type: schema.BloatTypeIndex, Size: 16384, Bloat: 8192, Ratio: 0.5},
+				},
+			},
+		}
+		var parsed map[string]any
+		err := json.Unmarshal([]byte(report.String()), &parsed)
+		assert.NoError(err)
+		assert.Equal(float64(1), parsed["count"])
+
+		database := parsed["databases"].([]any)[0].(map[string]any)
+		assert.Equal(float64(-8192), database["growth_bytes"])
+		assert.NotContains(database, "previous")
+
+		body := parsed["body"].([]any)[0].(map[string]any)
+		assert.Equal("users_pkey", body["index"])
+		assert.Equal(float64(8192), body["bloat_bytes"])
+		assert.Equal(0.5, body["bloat_ratio"])
+	})
+}
@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Column is a column of a table, view or materialized view
+type Column struct {
+	Database   string  `json:"database"`
+	Schema     string  `json:"schema"`
+	Table      string  `json:"table"`
+	Name       string  `json:"name"`
+	Position   int32   `json:"position"`
+	Type       string  `json:"type"`
+	Nullable   bool    `json:"nullable,omitempty"`
+	Default    *string `json:"default,omitempty"`
+	PrimaryKey bool    `json:"primary_key,omitempty"`
+	Auto       bool    `json:"auto,omitempty"` // Identity, serial or generated column, which is set by the database
+}
+
+type ColumnListRequest struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+}
+
+type ColumnList struct {
+	Count uint64   `json:"count"`
+	Body  []Column `json:"body,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (c Column) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (c ColumnList) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (c ColumnListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	schema, table := strings.TrimSpace(c.Schema), strings.TrimSpace(c.Table)
+	if schema == "" {
+		return "", pg.ErrBadParameter.With("schema is empty")
+	}
+	if table == "" {
+		return "", pg.ErrBadParameter.With("table is empty")
+	}
+	pg.Where().Eq(`schema`, schema).Eq(`"table"`, table).Bind(bind)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return columnList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported ColumnListRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (c *Column) Scan(row pg.Row) error {
+	return row.Scan(&c.Database, &c.Schema, &c.Table, &c.Name, &c.Position, &c.Type, &c.Nullable, &c.Default, &c.PrimaryKey, &c.Auto)
+}
+
+func (c *ColumnList) Scan(row pg.Row) error {
+	var column Column
+	if err := column.Scan(row); err != nil {
+		return err
+	}
+	c.Body = append(c.Body, column)
+	return nil
+}
+
+func (c *ColumnList) ScanCount(row pg.Row) error {
+	return row.Scan(&c.Count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	ColumnDef    = `column ("database" TEXT, "schema" TEXT, "table" TEXT, "name" TEXT, "position" INTEGER, "type" TEXT, "nullable" BOOLEAN, "default" TEXT, "primary_key" BOOLEAN, "auto" BOOLEAN)`
+	columnSelect = `
+		SELECT
+			current_database() AS "database",
+			N.nspname::TEXT AS "schema",
+			C.relname::TEXT AS "table",
+			A.attname::TEXT AS "name",
+			A.attnum::INTEGER AS "position",
+			format_type(A.atttypid, A.atttypmod) AS "type",
+			NOT A.attnotnull AS "nullable",
+			pg_get_expr(D.adbin, D.adrelid) AS "default",
+			EXISTS (
+				SELECT 1 FROM pg_index I WHERE I.indrelid = C.oid AND I.indisprimary AND A.attnum = ANY(I.indkey)
+			) AS "primary_key",
+			(A.attidentity <> '' OR A.attgenerated <> '' OR COALESCE(pg_get_expr(D.adbin, D.adrelid), '') LIKE 'nextval(%') AS "auto"
+		FROM
+			pg_attribute A
+		JOIN
+			pg_class C ON C.oid = A.attrelid
+		JOIN
+			pg_namespace N ON N.oid = C.relnamespace
+		LEFT JOIN
+			pg_attrdef D ON D.adrelid = A.attrelid AND D.adnum = A.attnum
+		WHERE
+			A.attnum > 0 AND NOT A.attisdropped AND C.relkind IN ('r', 'p', 'v', 'm', 'f')
+	`
+	columnList = `WITH q AS (` + columnSelect + `) SELECT * FROM q ${where} ORDER BY "position"`
+)
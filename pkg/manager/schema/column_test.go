@@ -0,0 +1,47 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_ColumnListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		query, err := schema.ColumnListRequest{Schema: "public", Table: "users"}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(query, "pg_attribute")
+		assert.Equal(`WHERE schema = @schema AND "table" = @table`, bind.Get("where"))
+		assert.Equal("public", bind.Get("schema"))
+		assert.Equal("users", bind.Get("table"))
+	})
+
+	t.Run("QuotedTable", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.ColumnListRequest{Schema: "public", Table: "o'brien"}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal(`WHERE schema = @schema AND "table" = @table`, bind.Get("where"))
+		assert.Equal("o'brien", bind.Get("table"))
+	})
+
+	t.Run("EmptySchema", func(t *testing.T) {
+		_, err := schema.ColumnListRequest{Table: "users"}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("EmptyTable", func(t *testing.T) {
+		_, err := schema.ColumnListRequest{Schema: "public", Table: " "}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.ColumnListRequest{Schema: "public", Table: "users"}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
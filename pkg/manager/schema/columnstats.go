@@ -0,0 +1,227 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ColumnStatistics is the planner statistics of a column from pg_stats, which
+// are empty until the table has been analyzed
+type ColumnStatistics struct {
+	Database         string    `json:"database"`
+	Schema           string    `json:"schema"`
+	Table            string    `json:"table"`
+	Name             string    `json:"name"`
+	Position         int32     `json:"position"`
+	Target           *int32    `json:"target,omitempty"` // Statistics target, or the default_statistics_target when not set
+	Analyzed         bool      `json:"analyzed,omitempty"`
+	NullFrac         *float64  `json:"null_frac,omitempty"`
+	AvgWidth         *int32    `json:"avg_width,omitempty"`
+	NDistinct        *float64  `json:"n_distinct,omitempty"` // Negative when the number of distinct values is a fraction of the rows
+	MostCommonValues []string  `json:"most_common_values,omitempty"`
+	MostCommonFreqs  []float64 `json:"most_common_freqs,omitempty"`
+	Correlation      *float64  `json:"correlation,omitempty"`
+}
+
+type ColumnStatisticsListRequest struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+}
+
+type ColumnStatisticsList struct {
+	Count uint64             `json:"count"`
+	Body  []ColumnStatistics `json:"body,omitempty"`
+}
+
+// ColumnStatisticsMeta sets the statistics target of a column, which is the
+// number of most common values and histogram buckets collected by ANALYZE
+type ColumnStatisticsMeta struct {
+	Column  string `json:"column" arg:"" help:"Column name"`
+	Target  *int32 `json:"target,omitempty" help:"Statistics target between 0 and 10000, or the default_statistics_target when not set"`
+	Analyze bool   `json:"analyze,omitempty" help:"Analyze the column with the new statistics target"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// The maximum statistics target of a column
+	ColumnStatisticsTargetMax = 10000
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (c ColumnStatistics) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (c ColumnStatisticsList) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (c ColumnStatisticsMeta) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// VALIDATION
+
+// Validate checks the column is set, and the statistics target is in range
+func (c ColumnStatisticsMeta) Validate() error {
+	if strings.TrimSpace(c.Column) == "" {
+		return pg.ErrBadParameter.With("column is required")
+	}
+	if c.Target != nil && (*c.Target < 0 || *c.Target > ColumnStatisticsTargetMax) {
+		return pg.ErrBadParameter.Withf("target must be between 0 and %d", ColumnStatisticsTargetMax)
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (c ColumnStatisticsListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	schema, table := strings.TrimSpace(c.Schema), strings.TrimSpace(c.Table)
+	if schema == "" {
+		return "", pg.ErrBadParameter.With("schema is empty")
+	}
+	if table == "" {
+		return "", pg.ErrBadParameter.With("table is empty")
+	}
+	pg.Where().Eq(`schema`, schema).Eq(`"table"`, table).Bind(bind)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return columnStatisticsList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported ColumnStatisticsListRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (c *ColumnStatistics) Scan(row pg.Row) error {
+	return row.Scan(&c.Database, &c.Schema, &c.Table, &c.Name, &c.Position, &c.Target, &c.Analyzed, &c.NullFrac, &c.AvgWidth, &c.NDistinct, &c.MostCommonValues, &c.MostCommonFreqs, &c.Correlation)
+}
+
+func (c *ColumnStatisticsList) Scan(row pg.Row) error {
+	var column ColumnStatistics
+	if err := column.Scan(row); err != nil {
+		return err
+	}
+	c.Body = append(c.Body, column)
+	return nil
+}
+
+func (c *ColumnStatisticsList) ScanCount(row pg.Row) error {
+	return row.Scan(&c.Count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WRITER
+
+// Set sets the statistics target of a column of a table, materialized view
+// or foreign table, or resets it to the default when the target is not set,
+// and then analyzes the column when requested. The objectType is the type of
+// the object, such as TABLE.
+func (c ColumnStatisticsMeta) Set(ctx context.Context, conn pg.Conn, namespace, table, objectType string) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	// Determine the kind of object to alter
+	var kind string
+	switch objectType {
+	case "TABLE", "PARTITIONED TABLE":
+		kind = "TABLE"
+	case "MATERIALIZED VIEW", "FOREIGN TABLE":
+		kind = objectType
+	default:
+		return pg.ErrBadParameter.Withf("cannot set the statistics target of %s %q", strings.ToLower(objectType), table)
+	}
+
+	// A target of -1 resets to the default
+	target := int32(-1)
+	if c.Target != nil {
+		target = *c.Target
+	}
+
+	// Set the statistics target
+	conn = conn.With(
+		"kind", kind,
+		"schema", strings.TrimSpace(namespace),
+		"table", strings.TrimSpace(table),
+		"column", strings.TrimSpace(c.Column),
+		"target", strconv.FormatInt(int64(target), 10),
+	)
+	if err := conn.Exec(ctx, columnStatisticsSet); err != nil {
+		return err
+	}
+
+	// Analyze the column
+	if c.Analyze {
+		return conn.Exec(ctx, columnStatisticsAnalyze)
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	ColumnStatisticsDef    = `columnstats ("database" TEXT, "schema" TEXT, "table" TEXT, "name" TEXT, "position" INTEGER, "target" INTEGER, "analyzed" BOOLEAN, "null_frac" FLOAT8, "avg_width" INTEGER, "n_distinct" FLOAT8, "most_common_values" TEXT[], "most_common_freqs" FLOAT8[], "correlation" FLOAT8)`
+	columnStatisticsSelect = `
+		SELECT
+			current_database() AS "database",
+			N.nspname::TEXT AS "schema",
+			C.relname::TEXT AS "table",
+			A.attname::TEXT AS "name",
+			A.attnum::INTEGER AS "position",
+			NULLIF(A.attstattarget, -1)::INTEGER AS "target",
+			S.attname IS NOT NULL AS "analyzed",
+			S.null_frac::FLOAT8 AS "null_frac",
+			S.avg_width::INTEGER AS "avg_width",
+			S.n_distinct::FLOAT8 AS "n_distinct",
+			S.most_common_vals::TEXT::TEXT[] AS "most_common_values",
+			S.most_common_freqs::FLOAT8[] AS "most_common_freqs",
+			S.correlation::FLOAT8 AS "correlation"
+		FROM
+			pg_attribute A
+		JOIN
+			pg_class C ON C.oid = A.attrelid
+		JOIN
+			pg_namespace N ON N.oid = C.relnamespace
+		LEFT JOIN
+			pg_stats S ON S.schemaname = N.nspname AND S.tablename = C.relname AND S.attname = A.attname AND S.inherited = (C.relkind = 'p')
+		WHERE
+			A.attnum > 0 AND NOT A.attisdropped AND C.relkind IN ('r', 'p', 'm', 'f')
+	`
+	columnStatisticsList    = `WITH q AS (` + columnStatisticsSelect + `) SELECT * FROM q ${where} ORDER BY "position"`
+	columnStatisticsSet     = `ALTER ${kind} ${"schema"}.${"table"} ALTER COLUMN ${"column"} SET STATISTICS ${target}`
+	columnStatisticsAnalyze = `ANALYZE ${"schema"}.${"table"} (${"column"})`
+)
@@ -0,0 +1,51 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_ColumnStatisticsListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		query, err := schema.ColumnStatisticsListRequest{Schema: "public", Table: "users"}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(query, "pg_stats")
+		assert.Equal(`WHERE schema = @schema AND "table" = @table`, bind.Get("where"))
+		assert.Equal("public", bind.Get("schema"))
+		assert.Equal("users", bind.Get("table"))
+	})
+
+	t.Run("EmptySchema", func(t *testing.T) {
+		_, err := schema.ColumnStatisticsListRequest{Table: "users"}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("EmptyTable", func(t *testing.T) {
+		_, err := schema.ColumnStatisticsListRequest{Schema: "public", Table: " "}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.ColumnStatisticsListRequest{Schema: "public", Table: "users"}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_ColumnStatisticsMeta_Validate(t *testing.T) {
+	assert := assert.New(t)
+	target := func(v int32) *int32 { return &v }
+
+	assert.NoError(schema.ColumnStatisticsMeta{Column: "status"}.Validate())
+	assert.NoError(schema.ColumnStatisticsMeta{Column: "status", Target: target(0)}.Validate())
+	assert.NoError(schema.ColumnStatisticsMeta{Column: "status", Target: target(schema.ColumnStatisticsTargetMax)}.Validate())
+	assert.ErrorIs(schema.ColumnStatisticsMeta{Column: " "}.Validate(), pg.ErrBadParameter)
+	assert.ErrorIs(schema.ColumnStatisticsMeta{Column: "status", Target: target(-1)}.Validate(), pg.ErrBadParameter)
+	assert.ErrorIs(schema.ColumnStatisticsMeta{Column: "status", Target: target(schema.ColumnStatisticsTargetMax + 1)}.Validate(), pg.ErrBadParameter)
+}
@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// CommentType is the kind of object which is commented
+type CommentType string
+
+// CommentTarget selects a database, role, schema or an object in a schema,
+// and the comment which is set on it with COMMENT ON
+type CommentTarget struct {
+	Type     CommentType `json:"type" help:"Type of object (database, role, schema, object)"`
+	Database string      `json:"database,omitempty" help:"Database of the schema or object"`
+	Schema   string      `json:"schema,omitempty" help:"Schema of the object"`
+	Name     string      `json:"name" help:"Name"`
+	Comment  string      `json:"comment,omitempty" help:"Comment, or empty to remove the comment"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	CommentDatabase CommentType = "database"
+	CommentRole     CommentType = "role"
+	CommentSchema   CommentType = "schema"
+	CommentObject   CommentType = "object"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (t CommentTarget) String() string {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Target returns the name of the commented object, which is qualified with
+// the database and schema when they are set
+func (t CommentTarget) Target() string {
+	var parts []string
+	for _, part := range []string{t.Database, t.Schema, t.Name} {
+		if part := strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// Validate checks the type of the target, and that the database and schema
+// are set when they are required
+func (t CommentTarget) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return pg.ErrBadParameter.With("name is required")
+	}
+	switch t.Type {
+	case CommentDatabase, CommentRole:
+		if strings.TrimSpace(t.Database) != "" || strings.TrimSpace(t.Schema) != "" {
+			return pg.ErrBadParameter.Withf("database and schema cannot be set for a %s", t.Type)
+		}
+	case CommentSchema:
+		if strings.TrimSpace(t.Database) == "" {
+			return pg.ErrBadParameter.With("database is required")
+		} else if strings.TrimSpace(t.Schema) != "" {
+			return pg.ErrBadParameter.With("schema cannot be set for a schema")
+		}
+	case CommentObject:
+		if strings.TrimSpace(t.Database) == "" {
+			return pg.ErrBadParameter.With("database is required")
+		} else if strings.TrimSpace(t.Schema) == "" {
+			return pg.ErrBadParameter.With("schema is required")
+		}
+	default:
+		return pg.ErrBadParameter.Withf("invalid comment type %q", t.Type)
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WRITER
+
+// Set sets the comment, or removes it when the comment is empty. The comment
+// on a schema or object is set with a connection to its database, and
+// objectType is the type of the object, such as TABLE or INDEX, which is
+// ignored for other targets.
+func (t CommentTarget) Set(ctx context.Context, conn pg.Conn, objectType string) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	// The comment, which is removed when it is NULL
+	comment := "NULL"
+	if value := strings.TrimSpace(t.Comment); value != "" {
+		comment = quote.Literal(value)
+	}
+
+	// Determine the kind of object to comment
+	var kind string
+	switch t.Type {
+	case CommentDatabase:
+		kind = "DATABASE"
+	case CommentRole:
+		kind = "ROLE"
+	case CommentSchema:
+		kind = "SCHEMA"
+	default:
+		switch objectType {
+		case "TABLE", "PARTITIONED TABLE":
+			kind = "TABLE"
+		case "INDEX", "PARTITIONED INDEX":
+			kind = "INDEX"
+		case "VIEW", "MATERIALIZED VIEW", "SEQUENCE", "FOREIGN TABLE":
+			kind = objectType
+		case "COMPOSITE TYPE":
+			kind = "TYPE"
+		default:
+			return pg.ErrBadParameter.Withf("cannot comment on %s %q", strings.ToLower(objectType), t.Name)
+		}
+		return conn.With(
+			"kind", kind,
+			"schema", strings.TrimSpace(t.Schema),
+			"name", strings.TrimSpace(t.Name),
+			"comment", comment,
+		).Exec(ctx, commentObject)
+	}
+
+	// Set the comment
+	return conn.With(
+		"kind", kind,
+		"name", strings.TrimSpace(t.Name),
+		"comment", comment,
+	).Exec(ctx, commentSet)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	commentSet    = `COMMENT ON ${kind} ${"name"} IS ${comment}`
+	commentObject = `COMMENT ON ${kind} ${"schema"}.${"name"} IS ${comment}`
+)
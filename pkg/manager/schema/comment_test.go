@@ -0,0 +1,38 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_CommentTarget_Validate(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(schema.CommentTarget{Type: schema.CommentDatabase, Name: "test"}.Validate())
+		assert.NoError(schema.CommentTarget{Type: schema.CommentRole, Name: "test", Comment: "comment"}.Validate())
+		assert.NoError(schema.CommentTarget{Type: schema.CommentSchema, Database: "test", Name: "public"}.Validate())
+		assert.NoError(schema.CommentTarget{Type: schema.CommentObject, Database: "test", Schema: "public", Name: "users"}.Validate())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert.ErrorIs(schema.CommentTarget{}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.CommentTarget{Type: "function", Name: "test"}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.CommentTarget{Type: schema.CommentDatabase}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.CommentTarget{Type: schema.CommentDatabase, Database: "test", Name: "test"}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.CommentTarget{Type: schema.CommentRole, Schema: "public", Name: "test"}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.CommentTarget{Type: schema.CommentSchema, Name: "public"}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.CommentTarget{Type: schema.CommentSchema, Database: "test", Schema: "public", Name: "public"}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.CommentTarget{Type: schema.CommentObject, Database: "test", Name: "users"}.Validate(), pg.ErrBadParameter)
+	})
+
+	t.Run("Target", func(t *testing.T) {
+		assert.Equal("test", schema.CommentTarget{Type: schema.CommentDatabase, Name: "test"}.Target())
+		assert.Equal("test.public", schema.CommentTarget{Type: schema.CommentSchema, Database: "test", Name: "public"}.Target())
+		assert.Equal("test.public.users", schema.CommentTarget{Type: schema.CommentObject, Database: "test", Schema: "public", Name: "users"}.Target())
+	})
+}
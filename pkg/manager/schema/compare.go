@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	// Packages
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Profile is the configuration of a server which is compared with another
+// server, such as before moving to a new server
+type Profile struct {
+	Settings   map[string]string `json:"settings,omitempty"`   // Setting values by name
+	Extensions map[string]string `json:"extensions,omitempty"` // Installed versions by database and extension name, such as "mydb/postgis"
+	Roles      map[string]string `json:"roles,omitempty"`      // Role attributes and groups by name
+}
+
+// DifferenceType is the type of a difference between servers
+type DifferenceType string
+
+// Difference is a setting, extension or role which differs between servers.
+// The value is nil when the setting, extension or role does not exist.
+type Difference struct {
+	Type  DifferenceType `json:"type"`
+	Name  string         `json:"name"`
+	Value *string        `json:"value"` // Value on this server
+	Other *string        `json:"other"` // Value on the other server
+}
+
+// Comparison is the differences between this server and another server
+type Comparison struct {
+	Count uint64       `json:"count"`
+	Body  []Difference `json:"body,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	DifferenceSetting   DifferenceType = "setting"
+	DifferenceExtension DifferenceType = "extension"
+	DifferenceRole      DifferenceType = "role"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (p Profile) String() string {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (c Comparison) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Compare returns the settings, extensions and roles which differ between
+// this profile and another, ordered by type and name
+func (p Profile) Compare(other Profile) Comparison {
+	var result Comparison
+	result.Body = append(result.Body, compare(DifferenceSetting, p.Settings, other.Settings)...)
+	result.Body = append(result.Body, compare(DifferenceExtension, p.Extensions, other.Extensions)...)
+	result.Body = append(result.Body, compare(DifferenceRole, p.Roles, other.Roles)...)
+	result.Count = uint64(len(result.Body))
+	return result
+}
+
+// RoleProfile returns the attributes and groups of a role as a string, such
+// as "createdb login memberof=admin,readers", for comparison between servers
+func RoleProfile(role Role) string {
+	var attrs []string
+	for _, attr := range []struct {
+		name  string
+		value *bool
+	}{
+		{"bypassrls", role.BypassRowLevelSecurity},
+		{"createdb", role.CreateDatabases},
+		{"createrole", role.CreateRoles},
+		{"inherit", role.Inherit},
+		{"login", role.Login},
+		{"replication", role.Replication},
+		{"super", role.Superuser},
+	} {
+		if types.PtrBool(attr.value) {
+			attrs = append(attrs, attr.name)
+		}
+	}
+	if role.ConnectionLimit != nil {
+		attrs = append(attrs, "conlimit="+strconv.FormatUint(*role.ConnectionLimit, 10))
+	}
+	if len(role.Groups) > 0 {
+		groups := slices.Sorted(slices.Values(role.Groups))
+		attrs = append(attrs, "memberof="+strings.Join(groups, ","))
+	}
+	return strings.Join(attrs, " ")
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the differences between two sets of values, ordered by name
+func compare(t DifferenceType, values, other map[string]string) []Difference {
+	var result []Difference
+	names := slices.Collect(maps.Keys(values))
+	for name := range maps.Keys(other) {
+		if _, exists := values[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		value, ok := values[name]
+		otherValue, otherOk := other[name]
+		if ok == otherOk && value == otherValue {
+			continue
+		}
+		diff := Difference{Type: t, Name: name}
+		if ok {
+			diff.Value = types.StringPtr(value)
+		}
+		if otherOk {
+			diff.Other = types.StringPtr(otherValue)
+		}
+		result = append(result, diff)
+	}
+	return result
+}
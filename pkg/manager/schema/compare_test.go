@@ -0,0 +1,60 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Profile_Compare(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Same", func(t *testing.T) {
+		profile := schema.Profile{
+			Settings: map[string]string{"work_mem": "4096"},
+			Roles:    map[string]string{"alice": "login"},
+		}
+		comparison := profile.Compare(profile)
+		assert.Zero(comparison.Count)
+		assert.Empty(comparison.Body)
+	})
+
+	t.Run("Different", func(t *testing.T) {
+		this := schema.Profile{
+			Settings:   map[string]string{"work_mem": "4096", "shared_buffers": "16384"},
+			Extensions: map[string]string{"app/postgis": "3.4.0"},
+			Roles:      map[string]string{"alice": "login", "bob": "login"},
+		}
+		other := schema.Profile{
+			Settings:   map[string]string{"work_mem": "8192", "shared_buffers": "16384"},
+			Extensions: map[string]string{"app/postgis": "3.5.0", "app/pgcrypto": "1.3"},
+			Roles:      map[string]string{"alice": "createdb login"},
+		}
+		comparison := this.Compare(other)
+		assert.Equal(uint64(5), comparison.Count)
+		assert.Equal([]schema.Difference{
+			{Type: schema.DifferenceSetting, Name: "work_mem", Value: types.StringPtr("4096"), Other: types.StringPtr("8192")},
+			{Type: schema.DifferenceExtension, Name: "app/pgcrypto", Value: nil, Other: types.StringPtr("1.3")},
+			{Type: schema.DifferenceExtension, Name: "app/postgis", Value: types.StringPtr("3.4.0"), Other: types.StringPtr("3.5.0")},
+			{Type: schema.DifferenceRole, Name: "alice", Value: types.StringPtr("login"), Other: types.StringPtr("createdb login")},
+			{Type: schema.DifferenceRole, Name: "bob", Value: types.StringPtr("login"), Other: nil},
+		}, comparison.Body)
+	})
+}
+
+func Test_RoleProfile(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", schema.RoleProfile(schema.Role{}))
+
+	var role schema.Role
+	role.Login = types.BoolPtr(true)
+	role.CreateDatabases = types.BoolPtr(true)
+	role.Superuser = types.BoolPtr(false)
+	role.ConnectionLimit = types.Uint64Ptr(10)
+	role.Groups = []string{"readers", "admin"}
+	assert.Equal("createdb login conlimit=10 memberof=admin,readers", schema.RoleProfile(role))
+}
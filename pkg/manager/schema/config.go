@@ -0,0 +1,365 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ConfigFileSetting is a setting in a configuration file, such as
+// postgresql.conf or postgresql.auto.conf, as it would be applied if the
+// configuration were reloaded. A setting which cannot be parsed or applied
+// has an error.
+type ConfigFileSetting struct {
+	File    *string `json:"file,omitempty"`
+	Line    *uint64 `json:"line,omitempty"`
+	Seq     uint64  `json:"seq"`
+	Name    *string `json:"name,omitempty"`
+	Value   *string `json:"value,omitempty"`
+	Applied bool    `json:"applied"`
+	Error   *string `json:"error,omitempty"`
+}
+
+type ConfigFileSettingListRequest struct {
+	Error *bool `json:"error,omitempty" help:"Filter by entries which have an error"`
+	pg.OffsetLimit
+}
+
+type ConfigFileSettingList struct {
+	Count uint64              `json:"count"`
+	Body  []ConfigFileSetting `json:"body,omitempty"`
+}
+
+// HBARule is a rule in pg_hba.conf, as it would be applied if the
+// configuration were reloaded. A rule which cannot be parsed has an error,
+// and is ignored when the configuration is reloaded.
+type HBARule struct {
+	Line     uint64   `json:"line"`
+	Type     *string  `json:"type,omitempty"` // local, host, hostssl, hostnossl, hostgssenc, hostnogssenc
+	Database []string `json:"database,omitempty"`
+	User     []string `json:"user,omitempty"`
+	Address  *string  `json:"address,omitempty"`
+	Netmask  *string  `json:"netmask,omitempty"`
+	Method   *string  `json:"auth_method,omitempty"`
+	Options  []string `json:"options,omitempty"`
+	Error    *string  `json:"error,omitempty"`
+}
+
+// HBARuleMeta is a rule which is added to pg_hba.conf
+type HBARuleMeta struct {
+	Type     string   `json:"type" help:"Connection type (local, host, hostssl, hostnossl, hostgssenc, hostnogssenc)"`
+	Database []string `json:"database" help:"Databases, or all, sameuser, samerole or replication"`
+	User     []string `json:"user" help:"Roles, or all, or +group"`
+	Address  string   `json:"address,omitempty" help:"Client address as CIDR, host name, all, samehost or samenet, except for local connections"`
+	Method   string   `json:"auth_method" help:"Authentication method, such as scram-sha-256, trust or reject"`
+	Options  []string `json:"options,omitempty" help:"Authentication options, as name=value"`
+}
+
+type HBARuleListRequest struct {
+	Error *bool `json:"error,omitempty" help:"Filter by rules which have an error"`
+	pg.OffsetLimit
+}
+
+type HBARuleList struct {
+	Count uint64    `json:"count"`
+	Body  []HBARule `json:"body,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	hbaTypes   = []string{"local", "host", "hostssl", "hostnossl", "hostgssenc", "hostnogssenc"}
+	hbaMethods = []string{"trust", "reject", "scram-sha-256", "md5", "password", "gss", "sspi", "ident", "peer", "ldap", "radius", "cert", "pam", "bsd"}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s ConfigFileSetting) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (s ConfigFileSettingList) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (r HBARule) String() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (r HBARuleList) String() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// VALIDATION
+
+// Validate returns an error if the type, databases, users, address, method
+// or options of a rule are invalid
+func (r HBARuleMeta) Validate() error {
+	if !slices.Contains(hbaTypes, r.Type) {
+		return pg.ErrBadParameter.Withf("invalid connection type %q", r.Type)
+	}
+	if len(r.Database) == 0 {
+		return pg.ErrBadParameter.With("database is missing")
+	} else if len(r.User) == 0 {
+		return pg.ErrBadParameter.With("user is missing")
+	}
+	for _, v := range slices.Concat(r.Database, r.User) {
+		if strings.TrimSpace(v) == "" || strings.ContainsAny(v, "\"\n\r") {
+			return pg.ErrBadParameter.Withf("invalid database or user %q", v)
+		}
+	}
+	if r.Type == "local" {
+		if r.Address != "" {
+			return pg.ErrBadParameter.With("address cannot be set for local connections")
+		}
+	} else if err := validateHBAAddress(r.Address); err != nil {
+		return err
+	}
+	if !slices.Contains(hbaMethods, r.Method) {
+		return pg.ErrBadParameter.Withf("invalid authentication method %q", r.Method)
+	}
+	for _, option := range r.Options {
+		if name, _, ok := strings.Cut(option, "="); !ok || strings.TrimSpace(name) == "" || strings.ContainsAny(option, "\"\n\r") {
+			return pg.ErrBadParameter.Withf("invalid option %q, expected name=value", option)
+		}
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Line returns the rule as a line of pg_hba.conf, without a newline. The
+// databases and users are quoted when they contain a space or comma.
+func (r HBARuleMeta) Line() string {
+	fields := []string{r.Type, hbaList(r.Database), hbaList(r.User)}
+	if r.Address != "" {
+		fields = append(fields, r.Address)
+	}
+	fields = append(fields, r.Method)
+	for _, option := range r.Options {
+		if name, value, _ := strings.Cut(option, "="); strings.ContainsAny(value, " \t,#") {
+			option = name + "=" + `"` + value + `"`
+		}
+		fields = append(fields, option)
+	}
+	return strings.Join(fields, "\t")
+}
+
+// Errors returns the entries which have an error, as the file and line
+// followed by the error
+func (s ConfigFileSettingList) Errors() []string {
+	var result []string
+	for _, setting := range s.Body {
+		if setting.Error == nil {
+			continue
+		}
+		location := "configuration file"
+		if setting.File != nil {
+			location = *setting.File
+		}
+		if setting.Line != nil {
+			location += fmt.Sprintf(" line %d", *setting.Line)
+		}
+		result = append(result, location+": "+*setting.Error)
+	}
+	return result
+}
+
+// Errors returns the rules which have an error, as the line followed by the
+// error
+func (r HBARuleList) Errors() []string {
+	var result []string
+	for _, rule := range r.Body {
+		if rule.Error != nil {
+			result = append(result, fmt.Sprintf("pg_hba.conf line %d: %s", rule.Line, *rule.Error))
+		}
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (r ConfigFileSettingListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Where
+	where := pg.Where()
+	if r.Error != nil {
+		if *r.Error {
+			where.Expr(`error IS NOT NULL`)
+		} else {
+			where.Expr(`error IS NULL`)
+		}
+	}
+	where.Bind(bind)
+
+	// Bind offset and limit
+	r.OffsetLimit.Bind(bind, ConfigFileListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return configFileSettingList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported ConfigFileSettingListRequest operation %q", op)
+	}
+}
+
+func (r HBARuleListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Where
+	where := pg.Where()
+	if r.Error != nil {
+		if *r.Error {
+			where.Expr(`error IS NOT NULL`)
+		} else {
+			where.Expr(`error IS NULL`)
+		}
+	}
+	where.Bind(bind)
+
+	// Bind offset and limit
+	r.OffsetLimit.Bind(bind, ConfigFileListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return hbaRuleList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported HBARuleListRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (s *ConfigFileSetting) Scan(row pg.Row) error {
+	return row.Scan(&s.File, &s.Line, &s.Seq, &s.Name, &s.Value, &s.Applied, &s.Error)
+}
+
+func (l *ConfigFileSettingList) Scan(row pg.Row) error {
+	var setting ConfigFileSetting
+	if err := setting.Scan(row); err != nil {
+		return err
+	}
+	l.Body = append(l.Body, setting)
+	return nil
+}
+
+func (l *ConfigFileSettingList) ScanCount(row pg.Row) error {
+	return row.Scan(&l.Count)
+}
+
+func (r *HBARule) Scan(row pg.Row) error {
+	return row.Scan(&r.Line, &r.Type, &r.Database, &r.User, &r.Address, &r.Netmask, &r.Method, &r.Options, &r.Error)
+}
+
+func (l *HBARuleList) Scan(row pg.Row) error {
+	var rule HBARule
+	if err := rule.Scan(row); err != nil {
+		return err
+	}
+	l.Body = append(l.Body, rule)
+	return nil
+}
+
+func (l *HBARuleList) ScanCount(row pg.Row) error {
+	return row.Scan(&l.Count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return a list of databases or users as a comma-separated field
+func hbaList(values []string) string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if strings.ContainsAny(v, " \t,#") {
+			v = `"` + v + `"`
+		}
+		result = append(result, v)
+	}
+	return strings.Join(result, ",")
+}
+
+// Check the address of a rule, which is a CIDR range, a keyword or a host
+// name
+func validateHBAAddress(address string) error {
+	switch {
+	case address == "":
+		return pg.ErrBadParameter.With("address is missing")
+	case address == "all", address == "samehost", address == "samenet":
+		return nil
+	case strings.Contains(address, "/"):
+		if _, _, err := net.ParseCIDR(address); err != nil {
+			return pg.ErrBadParameter.Withf("invalid address %q", address)
+		}
+		return nil
+	case net.ParseIP(address) != nil:
+		return pg.ErrBadParameter.Withf("address %q requires a CIDR mask length", address)
+	case strings.ContainsAny(address, " \t\"#\n\r"):
+		return pg.ErrBadParameter.Withf("invalid address %q", address)
+	default:
+		return nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	configFileSettingSelect = `
+		SELECT
+			sourcefile AS "file",
+			sourceline::BIGINT AS "line",
+			seqno::BIGINT AS "seq",
+			name AS "name",
+			setting AS "value",
+			applied AS "applied",
+			error AS "error"
+		FROM
+			pg_catalog.pg_file_settings
+	`
+	hbaRuleSelect = `
+		SELECT
+			line_number::BIGINT AS "line",
+			type AS "type",
+			database AS "database",
+			user_name AS "user",
+			address AS "address",
+			netmask AS "netmask",
+			auth_method AS "auth_method",
+			options AS "options",
+			error AS "error"
+		FROM
+			pg_catalog.pg_hba_file_rules
+	`
+	configFileSettingList = `WITH q AS (` + configFileSettingSelect + `) SELECT * FROM q ${where} ORDER BY "seq"`
+	hbaRuleList           = `WITH q AS (` + hbaRuleSelect + `) SELECT * FROM q ${where} ORDER BY "line"`
+)
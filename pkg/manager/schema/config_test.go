@@ -0,0 +1,137 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigFileSettingListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.ConfigFileSettingListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_file_settings")
+		assert.Equal("", bind.Get("where"))
+	})
+
+	t.Run("WithError", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.ConfigFileSettingListRequest{Error: types.BoolPtr(true)}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal("WHERE error IS NOT NULL", bind.Get("where"))
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.ConfigFileSettingListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_HBARuleListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.HBARuleListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_hba_file_rules")
+	})
+
+	t.Run("WithoutError", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.HBARuleListRequest{Error: types.BoolPtr(false)}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal("WHERE error IS NULL", bind.Get("where"))
+	})
+}
+
+func Test_ConfigErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	files := schema.ConfigFileSettingList{Body: []schema.ConfigFileSetting{
+		{File: types.StringPtr("/etc/postgresql.conf"), Line: types.Uint64Ptr(3), Name: types.StringPtr("work_mem"), Applied: true},
+		{File: types.StringPtr("/etc/postgresql.conf"), Line: types.Uint64Ptr(7), Error: types.StringPtr("setting could not be applied")},
+	}}
+	assert.Equal([]string{"/etc/postgresql.conf line 7: setting could not be applied"}, files.Errors())
+
+	hba := schema.HBARuleList{Body: []schema.HBARule{
+		{Line: 1, Type: types.StringPtr("local"), Method: types.StringPtr("trust")},
+		{Line: 12, Error: types.StringPtr(`invalid authentication method "trusted"`)},
+	}}
+	assert.Equal([]string{`pg_hba.conf line 12: invalid authentication method "trusted"`}, hba.Errors())
+}
+
+func Test_HBARuleMeta_Validate(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Host", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "host", Database: []string{"all"}, User: []string{"app"}, Address: "10.0.0.0/8", Method: "scram-sha-256"}
+		assert.NoError(rule.Validate())
+	})
+
+	t.Run("Local", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "local", Database: []string{"all"}, User: []string{"postgres"}, Method: "peer"}
+		assert.NoError(rule.Validate())
+	})
+
+	t.Run("HostName", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "hostssl", Database: []string{"app"}, User: []string{"+readers"}, Address: ".example.com", Method: "cert", Options: []string{"clientcert=verify-full"}}
+		assert.NoError(rule.Validate())
+	})
+
+	t.Run("InvalidType", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "remote", Database: []string{"all"}, User: []string{"all"}, Address: "all", Method: "trust"}
+		assert.ErrorIs(rule.Validate(), pg.ErrBadParameter)
+	})
+
+	t.Run("InvalidMethod", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "host", Database: []string{"all"}, User: []string{"all"}, Address: "all", Method: "none"}
+		assert.ErrorIs(rule.Validate(), pg.ErrBadParameter)
+	})
+
+	t.Run("MissingDatabase", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "host", User: []string{"all"}, Address: "all", Method: "trust"}
+		assert.ErrorIs(rule.Validate(), pg.ErrBadParameter)
+	})
+
+	t.Run("QuotedUser", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "host", Database: []string{"all"}, User: []string{`a"b`}, Address: "all", Method: "trust"}
+		assert.ErrorIs(rule.Validate(), pg.ErrBadParameter)
+	})
+
+	t.Run("LocalWithAddress", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "local", Database: []string{"all"}, User: []string{"all"}, Address: "127.0.0.1/32", Method: "trust"}
+		assert.ErrorIs(rule.Validate(), pg.ErrBadParameter)
+	})
+
+	t.Run("AddressWithoutMask", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "host", Database: []string{"all"}, User: []string{"all"}, Address: "127.0.0.1", Method: "trust"}
+		assert.ErrorIs(rule.Validate(), pg.ErrBadParameter)
+	})
+
+	t.Run("InvalidOption", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "host", Database: []string{"all"}, User: []string{"all"}, Address: "all", Method: "ldap", Options: []string{"ldapserver"}}
+		assert.ErrorIs(rule.Validate(), pg.ErrBadParameter)
+	})
+}
+
+func Test_HBARuleMeta_Line(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Host", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "host", Database: []string{"app", "reports"}, User: []string{"app"}, Address: "10.0.0.0/8", Method: "scram-sha-256"}
+		assert.Equal("host\tapp,reports\tapp\t10.0.0.0/8\tscram-sha-256", rule.Line())
+	})
+
+	t.Run("LocalQuoted", func(t *testing.T) {
+		rule := schema.HBARuleMeta{Type: "local", Database: []string{"my db"}, User: []string{"all"}, Method: "ldap", Options: []string{"ldapprefix=cn=a, dc=b"}}
+		assert.Equal("local\t\"my db\"\tall\tldap\tldapprefix=\"cn=a, dc=b\"", rule.Line())
+	})
+}
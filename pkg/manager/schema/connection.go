@@ -1,7 +1,9 @@
 package schema
 
 import (
+	"cmp"
 	"encoding/json"
+	"slices"
 	"strings"
 	"time"
 
@@ -25,10 +27,14 @@ type Connection struct {
 	QueryStart  time.Time `json:"query_start,omitempty" help:"Query start"`
 	Query       string    `json:"query,omitempty" help:"Query"`
 	State       string    `json:"state,omitempty" help:"State"`
+	WaitType    *string   `json:"wait_event_type,omitempty" help:"Type of event the connection is waiting for, such as Lock"`
+	WaitEvent   *string   `json:"wait_event,omitempty" help:"Event the connection is waiting for"`
+	BlockedBy   []uint32  `json:"blocked_by,omitempty" help:"Process IDs of the connections holding the locks the connection is waiting for"`
 }
 
 type ConnectionListRequest struct {
 	pg.OffsetLimit
+	KeysetPage
 	Database *string `json:"database,omitempty" help:"Database"`
 	Role     *string `json:"role,omitempty" help:"Role"`
 	State    *string `json:"state,omitempty" help:"State"`
@@ -37,8 +43,75 @@ type ConnectionListRequest struct {
 type ConnectionList struct {
 	Count uint64       `json:"count"`
 	Body  []Connection `json:"body,omitempty"`
+	Next  string       `json:"next,omitempty"`
 }
 
+// ConnectionWatchRequest filters the connections which are watched, and sets
+// how often they are polled
+type ConnectionWatchRequest struct {
+	Database *string `json:"database,omitempty" help:"Database"`
+	Role     *string `json:"role,omitempty" help:"Role"`
+	Interval *string `json:"interval,omitempty" help:"Interval between polls, such as 1s"`
+}
+
+// LongRunningListRequest selects the connections which have been running a
+// query, or idle in a transaction, for longer than a threshold
+type LongRunningListRequest struct {
+	Threshold         *string `json:"threshold,omitempty" help:"Minimum duration, such as 30s (default 1m)"`
+	IdleInTransaction bool    `json:"idle_in_transaction,omitempty" help:"List connections which are idle in a transaction, rather than running a query"`
+}
+
+// LongRunning selects the connections which have been running a query, or
+// idle in a transaction, for longer than the threshold, longest first
+type LongRunning struct {
+	Threshold         time.Duration
+	IdleInTransaction bool
+}
+
+// LongRunningQuery is a connection which has been running a query, or idle
+// in a transaction, since a time
+type LongRunningQuery struct {
+	Connection
+	Since    time.Time `json:"since" help:"When the query started, or the connection became idle in the transaction"`
+	Duration float64   `json:"duration_ms" help:"Milliseconds since the query started, or the connection became idle in the transaction"`
+}
+
+type LongRunningQueryList struct {
+	Count uint64             `json:"count"`
+	Body  []LongRunningQuery `json:"body,omitempty"`
+}
+
+// ConnectionEvent is a change to a connection between polls. The type is one
+// of the ConnectionEvent constants
+type ConnectionEvent struct {
+	Type string `json:"type"`
+	Connection
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+// Text stream events for watched connections
+const (
+	ConnectionEventNew        = "new"        // Connection was opened
+	ConnectionEventQuery      = "query"      // Connection started a query
+	ConnectionEventState      = "state"      // Connection changed state, such as from active to idle
+	ConnectionEventTerminated = "terminated" // Connection was closed
+	ConnectionEventError      = "error"      // Connections could not be polled
+)
+
+const (
+	// Default threshold for a long running query, or a connection which is
+	// idle in a transaction
+	LongRunningThreshold = time.Minute
+)
+
+const (
+	// Default and minimum interval between polling watched connections
+	ConnectionWatchInterval    = time.Second
+	ConnectionWatchMinInterval = 100 * time.Millisecond
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -58,6 +131,14 @@ func (c ConnectionListRequest) String() string {
 	return string(data)
 }
 
+func (c ConnectionEvent) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
 func (c ConnectionList) String() string {
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
@@ -66,26 +147,112 @@ func (c ConnectionList) String() string {
 	return string(data)
 }
 
+func (q LongRunningQuery) String() string {
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (q LongRunningQueryList) String() string {
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Duration returns the interval between polls, which is the default when not
+// set, or an error if it is invalid or less than the minimum
+func (c ConnectionWatchRequest) Duration() (time.Duration, error) {
+	if c.Interval == nil || strings.TrimSpace(*c.Interval) == "" {
+		return ConnectionWatchInterval, nil
+	}
+	interval, err := time.ParseDuration(strings.TrimSpace(*c.Interval))
+	if err != nil {
+		return 0, pg.ErrBadParameter.Withf("invalid interval %q", *c.Interval)
+	} else if interval < ConnectionWatchMinInterval {
+		return 0, pg.ErrBadParameter.Withf("interval must be at least %v", ConnectionWatchMinInterval)
+	}
+	return interval, nil
+}
+
+// Duration returns the threshold, which is the default when not set, or an
+// error if it is invalid or negative
+func (r LongRunningListRequest) Duration() (time.Duration, error) {
+	if r.Threshold == nil || strings.TrimSpace(*r.Threshold) == "" {
+		return LongRunningThreshold, nil
+	}
+	threshold, err := time.ParseDuration(strings.TrimSpace(*r.Threshold))
+	if err != nil {
+		return 0, pg.ErrBadParameter.Withf("invalid threshold %q", *r.Threshold)
+	} else if threshold < 0 {
+		return 0, pg.ErrBadParameter.With("threshold cannot be negative")
+	}
+	return threshold, nil
+}
+
+// ConnectionChanges returns the changes between two polls of the connections,
+// ordered by process ID. A connection which started a query is reported as a
+// query event, and otherwise a connection which changed state is reported as
+// a state event.
+func ConnectionChanges(previous, current []Connection) []ConnectionEvent {
+	var events []ConnectionEvent
+	before := make(map[uint32]Connection, len(previous))
+	for _, connection := range previous {
+		before[connection.Pid] = connection
+	}
+	for _, connection := range current {
+		prev, exists := before[connection.Pid]
+		delete(before, connection.Pid)
+		switch {
+		case !exists || !prev.ConnStart.Equal(connection.ConnStart):
+			// A pid which is reused is a new connection
+			if exists {
+				events = append(events, ConnectionEvent{Type: ConnectionEventTerminated, Connection: prev})
+			}
+			events = append(events, ConnectionEvent{Type: ConnectionEventNew, Connection: connection})
+		case !prev.QueryStart.Equal(connection.QueryStart) || prev.Query != connection.Query:
+			events = append(events, ConnectionEvent{Type: ConnectionEventQuery, Connection: connection})
+		case prev.State != connection.State:
+			events = append(events, ConnectionEvent{Type: ConnectionEventState, Connection: connection})
+		}
+	}
+	for _, connection := range before {
+		events = append(events, ConnectionEvent{Type: ConnectionEventTerminated, Connection: connection})
+	}
+	slices.SortStableFunc(events, func(a, b ConnectionEvent) int {
+		return cmp.Compare(a.Pid, b.Pid)
+	})
+	return events
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // SELECT
 
 func (c ConnectionListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
 	// Where
-	bind.Del("where")
+	where := pg.Where()
 	if c.Database != nil {
-		bind.Append("where", `"database" = `+bind.Set("database", strings.TrimSpace(*c.Database)))
+		where.Eq(`"database"`, strings.TrimSpace(*c.Database))
 	}
 	if c.Role != nil {
-		bind.Append("where", `"role" = `+bind.Set("role", strings.TrimSpace(*c.Role)))
+		where.Eq(`"role"`, strings.TrimSpace(*c.Role))
 	}
 	if c.State != nil {
-		bind.Append("where", `"state" = `+bind.Set("state", strings.TrimSpace(*c.State)))
+		where.Eq(`"state"`, strings.TrimSpace(*c.State))
 	}
-	if where := bind.Join("where", " AND "); where != "" {
-		bind.Set("where", `WHERE `+where)
-	} else {
-		bind.Set("where", "")
+	var pid uint32
+	if ok, err := c.Key(&pid); err != nil {
+		return "", err
+	} else if ok {
+		where.After([]string{`"pid"`}, pid)
 	}
+	where.Bind(bind)
 
 	// Offset and limit
 	c.OffsetLimit.Bind(bind, ConnectionListLimit)
@@ -99,6 +266,35 @@ func (c ConnectionListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
 	}
 }
 
+func (l LongRunning) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	if l.Threshold < 0 {
+		return "", pg.ErrBadParameter.With("threshold cannot be negative")
+	}
+
+	// Where
+	where := pg.Where()
+	if l.IdleInTransaction {
+		where.In(`"state"`, []string{"idle in transaction", "idle in transaction (aborted)"})
+	} else {
+		where.Eq(`"state"`, "active")
+	}
+	bind.Set("threshold", l.Threshold.Seconds())
+	where.Expr(`"since" <= NOW() - make_interval(secs => @threshold)`)
+	where.Bind(bind)
+
+	// Return the longest first, up to the limit
+	var limit pg.OffsetLimit
+	limit.Bind(bind, ConnectionListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return longRunningList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported LongRunning operation %q", op)
+	}
+}
+
 func (c ConnectionPid) Select(bind *pg.Bind, op pg.Op) (string, error) {
 	if c == 0 {
 		return "", pg.ErrBadParameter.With("missing pid")
@@ -122,7 +318,7 @@ func (c ConnectionPid) Select(bind *pg.Bind, op pg.Op) (string, error) {
 
 func (c *Connection) Scan(row pg.Row) error {
 	var result bool
-	return row.Scan(&c.Pid, &c.Database, &c.Role, &c.Application, &c.ClientAddr, &c.ClientPort, &c.ConnStart, &c.QueryStart, &c.Query, &c.State, &result)
+	return row.Scan(&c.Pid, &c.Database, &c.Role, &c.Application, &c.ClientAddr, &c.ClientPort, &c.ConnStart, &c.QueryStart, &c.Query, &c.State, &c.WaitType, &c.WaitEvent, &c.BlockedBy, &result)
 }
 
 func (c *ConnectionList) Scan(row pg.Row) error {
@@ -139,6 +335,25 @@ func (c *ConnectionList) ScanCount(row pg.Row) error {
 	return row.Scan(&c.Count)
 }
 
+func (q *LongRunningQuery) Scan(row pg.Row) error {
+	var result bool
+	return row.Scan(&q.Pid, &q.Database, &q.Role, &q.Application, &q.ClientAddr, &q.ClientPort, &q.ConnStart, &q.QueryStart, &q.Query, &q.State, &q.WaitType, &q.WaitEvent, &q.BlockedBy, &result, &q.Since, &q.Duration)
+}
+
+func (q *LongRunningQueryList) Scan(row pg.Row) error {
+	var query LongRunningQuery
+	if err := query.Scan(row); err != nil {
+		return err
+	} else {
+		q.Body = append(q.Body, query)
+	}
+	return nil
+}
+
+func (q *LongRunningQueryList) ScanCount(row pg.Row) error {
+	return row.Scan(&q.Count)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // SQL
 
@@ -155,7 +370,10 @@ const (
 				C.backend_start AS "conn_start",
 				C.query_start AS "query_start",
 				C.query AS "query",
-				C.state AS "state"
+				C.state AS "state",
+				C.wait_event_type AS "wait_event_type",
+				C.wait_event AS "wait_event",
+				NULLIF(pg_blocking_pids(C.pid), '{}') AS "blocked_by"
 			FROM
 				${"schema"}."pg_stat_activity" C
 			WHERE
@@ -164,6 +382,7 @@ const (
 				C.state IS NOT NULL
 		) SELECT * FROM conn`
 	connectionGet    = `WITH q AS (` + connectionSelect + `) SELECT *, false FROM q WHERE "pid" = @pid`
-	connectionList   = `WITH q AS (` + connectionSelect + `) SELECT *, false FROM q ${where}`
+	connectionList   = `WITH q AS (` + connectionSelect + `) SELECT *, false FROM q ${where} ORDER BY "pid"`
+	longRunningList  = `WITH q AS (` + connectionSelect + `), s AS (SELECT q.*, false, CASE WHEN q."state" = 'active' THEN q."query_start" ELSE A.state_change END AS "since" FROM q JOIN ${"schema"}."pg_stat_activity" A ON A.pid = q."pid" WHERE q."pid" <> pg_backend_pid()) SELECT *, EXTRACT(EPOCH FROM NOW() - "since")::FLOAT8 * 1000 AS "duration_ms" FROM s ${where} ORDER BY "since"`
 	connectionDelete = `WITH q AS (` + connectionSelect + `) SELECT *, pg_terminate_backend(${pid}) FROM q WHERE pid <> pg_backend_pid()`
 )
@@ -3,10 +3,12 @@ package schema_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
 	assert "github.com/stretchr/testify/assert"
 )
 
@@ -198,3 +200,122 @@ func Test_ConnectionPid_Select(t *testing.T) {
 		assert.Error(err)
 	})
 }
+
+func Test_ConnectionWatchRequest_Duration(t *testing.T) {
+	assert := assert.New(t)
+
+	interval, err := schema.ConnectionWatchRequest{}.Duration()
+	assert.NoError(err)
+	assert.Equal(schema.ConnectionWatchInterval, interval)
+
+	interval, err = schema.ConnectionWatchRequest{Interval: types.StringPtr("5s")}.Duration()
+	assert.NoError(err)
+	assert.Equal(5*time.Second, interval)
+
+	_, err = schema.ConnectionWatchRequest{Interval: types.StringPtr("1ms")}.Duration()
+	assert.ErrorIs(err, pg.ErrBadParameter)
+
+	_, err = schema.ConnectionWatchRequest{Interval: types.StringPtr("soon")}.Duration()
+	assert.ErrorIs(err, pg.ErrBadParameter)
+}
+
+func Test_LongRunningListRequest_Duration(t *testing.T) {
+	assert := assert.New(t)
+
+	threshold, err := schema.LongRunningListRequest{}.Duration()
+	assert.NoError(err)
+	assert.Equal(schema.LongRunningThreshold, threshold)
+
+	threshold, err = schema.LongRunningListRequest{Threshold: types.StringPtr("0s")}.Duration()
+	assert.NoError(err)
+	assert.Equal(time.Duration(0), threshold)
+
+	_, err = schema.LongRunningListRequest{Threshold: types.StringPtr("-1s")}.Duration()
+	assert.ErrorIs(err, pg.ErrBadParameter)
+
+	_, err = schema.LongRunningListRequest{Threshold: types.StringPtr("soon")}.Duration()
+	assert.ErrorIs(err, pg.ErrBadParameter)
+}
+
+func Test_LongRunning_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Active", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.LongRunning{Threshold: 30 * time.Second}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_stat_activity")
+		assert.Equal(`WHERE "state" = @state AND "since" <= NOW() - make_interval(secs => @threshold)`, bind.Get("where"))
+		assert.Equal("active", bind.Get("state"))
+		assert.Equal(float64(30), bind.Get("threshold"))
+	})
+
+	t.Run("IdleInTransaction", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.LongRunning{IdleInTransaction: true}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal(`WHERE "state" = ANY(@state) AND "since" <= NOW() - make_interval(secs => @threshold)`, bind.Get("where"))
+		assert.Equal([]string{"idle in transaction", "idle in transaction (aborted)"}, bind.Get("state"))
+	})
+
+	t.Run("NegativeThreshold", func(t *testing.T) {
+		_, err := schema.LongRunning{Threshold: -time.Second}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.LongRunning{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_ConnectionChanges(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Now()
+	idle := schema.Connection{Pid: 1, ConnStart: start, QueryStart: start, Query: "SELECT 1", State: "idle"}
+	active := schema.Connection{Pid: 2, ConnStart: start, QueryStart: start, Query: "SELECT 2", State: "active"}
+
+	t.Run("FirstPoll", func(t *testing.T) {
+		events := schema.ConnectionChanges(nil, []schema.Connection{active, idle})
+		if assert.Len(events, 2) {
+			assert.Equal(schema.ConnectionEventNew, events[0].Type)
+			assert.Equal(uint32(1), events[0].Pid)
+			assert.Equal(uint32(2), events[1].Pid)
+		}
+	})
+
+	t.Run("NoChange", func(t *testing.T) {
+		assert.Empty(schema.ConnectionChanges([]schema.Connection{idle, active}, []schema.Connection{idle, active}))
+	})
+
+	t.Run("QueryAndState", func(t *testing.T) {
+		query := idle
+		query.QueryStart, query.Query, query.State = start.Add(time.Second), "SELECT 3", "active"
+		state := active
+		state.State = "idle"
+		events := schema.ConnectionChanges([]schema.Connection{idle, active}, []schema.Connection{query, state})
+		if assert.Len(events, 2) {
+			assert.Equal(schema.ConnectionEventQuery, events[0].Type)
+			assert.Equal(schema.ConnectionEventState, events[1].Type)
+		}
+	})
+
+	t.Run("Terminated", func(t *testing.T) {
+		events := schema.ConnectionChanges([]schema.Connection{idle, active}, []schema.Connection{active})
+		if assert.Len(events, 1) {
+			assert.Equal(schema.ConnectionEventTerminated, events[0].Type)
+			assert.Equal(uint32(1), events[0].Pid)
+		}
+	})
+
+	t.Run("ReusedPid", func(t *testing.T) {
+		reused := idle
+		reused.ConnStart = start.Add(time.Minute)
+		events := schema.ConnectionChanges([]schema.Connection{idle}, []schema.Connection{reused})
+		if assert.Len(events, 2) {
+			assert.Equal(schema.ConnectionEventTerminated, events[0].Type)
+			assert.Equal(schema.ConnectionEventNew, events[1].Type)
+		}
+	})
+}
@@ -0,0 +1,327 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type CronJobId uint64
+
+type CronJobMeta struct {
+	Name     string `json:"name,omitempty" arg:"" help:"Job name"`
+	Schedule string `json:"schedule,omitempty" help:"Schedule in cron syntax, or an interval such as '30 seconds'"`
+	Command  string `json:"command,omitempty" help:"SQL command to run"`
+	Database string `json:"database,omitempty" help:"Database to run the command in"`
+	Username string `json:"username,omitempty" help:"Role to run the command as"`
+	Active   *bool  `json:"active,omitempty" help:"Whether the job is active"`
+}
+
+type CronJob struct {
+	Id uint64 `json:"id"`
+	CronJobMeta
+	LastStatus *string    `json:"last_status,omitempty"` // Status of the most recent run
+	LastRun    *time.Time `json:"last_run,omitempty"`    // Start time of the most recent run
+}
+
+type CronJobListRequest struct {
+	pg.OffsetLimit
+	Database *string `json:"database,omitempty" help:"Database"`
+	Username *string `json:"username,omitempty" help:"Role"`
+}
+
+type CronJobList struct {
+	Count uint64    `json:"count"`
+	Body  []CronJob `json:"body,omitempty"`
+}
+
+type CronJobRun struct {
+	Id        uint64     `json:"id"`
+	Job       uint64     `json:"job"`
+	Pid       *uint32    `json:"pid,omitempty"`
+	Database  string     `json:"database,omitempty"`
+	Username  string     `json:"username,omitempty"`
+	Command   string     `json:"command,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	Message   *string    `json:"message,omitempty"`
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+}
+
+type CronJobRunListRequest struct {
+	pg.OffsetLimit
+	Job    *uint64 `json:"job,omitempty" help:"Job identifier"`
+	Status *string `json:"status,omitempty" help:"Run status (starting, running, sending, connecting, succeeded, failed)"`
+}
+
+type CronJobRunList struct {
+	Count uint64       `json:"count"`
+	Body  []CronJobRun `json:"body,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (c CronJob) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (c CronJobMeta) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (c CronJobList) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (c CronJobListRequest) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (c CronJobRun) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (c CronJobRunList) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (c CronJobRunListRequest) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (c CronJobId) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	if c == 0 {
+		return "", pg.ErrBadParameter.With("missing job id")
+	} else {
+		bind.Set("jobid", c)
+	}
+
+	// Return query
+	switch op {
+	case pg.Get:
+		return cronJobGet, nil
+	case pg.Delete:
+		return cronJobDelete, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported CronJobId operation %q", op)
+	}
+}
+
+func (c CronJobListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Where
+	where := pg.Where()
+	if c.Database != nil {
+		where.Eq(`"database"`, strings.TrimSpace(*c.Database))
+	}
+	if c.Username != nil {
+		where.Eq(`"username"`, strings.TrimSpace(*c.Username))
+	}
+	where.Bind(bind)
+
+	// Offset and limit
+	c.OffsetLimit.Bind(bind, CronJobListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return cronJobList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported CronJobListRequest operation %q", op)
+	}
+}
+
+func (c CronJobRunListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Where
+	where := pg.Where()
+	if c.Job != nil {
+		where.Eq(`"job"`, *c.Job)
+	}
+	if c.Status != nil {
+		where.Eq(`"status"`, strings.TrimSpace(*c.Status))
+	}
+	where.Bind(bind)
+
+	// Offset and limit
+	c.OffsetLimit.Bind(bind, CronJobRunListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return cronJobRunList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported CronJobRunListRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WRITER
+
+func (c CronJobMeta) Insert(bind *pg.Bind) (string, error) {
+	// Name, schedule and command are required
+	if name := strings.TrimSpace(c.Name); name == "" {
+		return "", pg.ErrBadParameter.With("name is missing")
+	} else {
+		bind.Set("name", name)
+	}
+	if schedule := strings.TrimSpace(c.Schedule); schedule == "" {
+		return "", pg.ErrBadParameter.With("schedule is missing")
+	} else {
+		bind.Set("schedule", schedule)
+	}
+	if command := strings.TrimSpace(c.Command); command == "" {
+		return "", pg.ErrBadParameter.With("command is missing")
+	} else {
+		bind.Set("command", command)
+	}
+
+	// Database and username default to the current database and role
+	if database := strings.TrimSpace(c.Database); database != "" {
+		bind.Set("database", database)
+	} else {
+		bind.Set("database", nil)
+	}
+	if username := strings.TrimSpace(c.Username); username != "" {
+		bind.Set("username", username)
+	} else {
+		bind.Set("username", nil)
+	}
+
+	// Jobs are active by default
+	if c.Active != nil {
+		bind.Set("active", *c.Active)
+	} else {
+		bind.Set("active", true)
+	}
+
+	// Return query
+	return cronJobCreate, nil
+}
+
+func (c CronJobMeta) Update(_ *pg.Bind) error {
+	return pg.ErrNotImplemented.With("cron jobs cannot be updated")
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (c *CronJobId) Scan(row pg.Row) error {
+	return row.Scan((*uint64)(c))
+}
+
+func (c *CronJob) Scan(row pg.Row) error {
+	var result bool
+	return row.Scan(&c.Id, &c.Name, &c.Schedule, &c.Command, &c.Database, &c.Username, &c.Active, &c.LastStatus, &c.LastRun, &result)
+}
+
+func (c *CronJobList) Scan(row pg.Row) error {
+	var job CronJob
+	if err := job.Scan(row); err != nil {
+		return err
+	} else {
+		c.Body = append(c.Body, job)
+	}
+	return nil
+}
+
+func (c *CronJobList) ScanCount(row pg.Row) error {
+	return row.Scan(&c.Count)
+}
+
+func (c *CronJobRun) Scan(row pg.Row) error {
+	return row.Scan(&c.Id, &c.Job, &c.Pid, &c.Database, &c.Username, &c.Command, &c.Status, &c.Message, &c.StartTime, &c.EndTime)
+}
+
+func (c *CronJobRunList) Scan(row pg.Row) error {
+	var run CronJobRun
+	if err := run.Scan(row); err != nil {
+		return err
+	} else {
+		c.Body = append(c.Body, run)
+	}
+	return nil
+}
+
+func (c *CronJobRunList) ScanCount(row pg.Row) error {
+	return row.Scan(&c.Count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	cronJobSelect = `
+		SELECT
+			J.jobid AS "id",
+			COALESCE(J.jobname, '') AS "name",
+			J.schedule AS "schedule",
+			J.command AS "command",
+			J.database AS "database",
+			J.username AS "username",
+			J.active AS "active",
+			R.status AS "last_status",
+			R.start_time AS "last_run"
+		FROM
+			cron.job J
+		LEFT JOIN LATERAL (
+			SELECT status, start_time FROM cron.job_run_details WHERE jobid = J.jobid ORDER BY start_time DESC NULLS FIRST, runid DESC LIMIT 1
+		) R ON TRUE
+	`
+	cronJobGet       = `WITH q AS (` + cronJobSelect + `) SELECT *, false FROM q WHERE "id" = @jobid`
+	cronJobList      = `WITH q AS (` + cronJobSelect + `) SELECT *, false FROM q ${where} ORDER BY "id"`
+	cronJobDelete    = `WITH q AS (` + cronJobSelect + ` WHERE J.jobid = @jobid) SELECT *, cron.unschedule("id") FROM q`
+	cronJobCreate    = `SELECT cron.schedule_in_database(@name, @schedule, @command, COALESCE(@database, current_database()), COALESCE(@username, current_user), @active)`
+	cronJobRunSelect = `
+		SELECT
+			R.runid AS "id",
+			R.jobid AS "job",
+			R.job_pid AS "pid",
+			COALESCE(R.database, '') AS "database",
+			COALESCE(R.username, '') AS "username",
+			COALESCE(R.command, '') AS "command",
+			COALESCE(R.status, '') AS "status",
+			R.return_message AS "message",
+			R.start_time AS "start_time",
+			R.end_time AS "end_time"
+		FROM
+			cron.job_run_details R
+	`
+	cronJobRunList = `WITH q AS (` + cronJobRunSelect + `) SELECT * FROM q ${where} ORDER BY "start_time" DESC NULLS FIRST, "id" DESC`
+)
@@ -0,0 +1,121 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_CronJobId_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Get", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.CronJobId(1).Select(bind, pg.Get)
+		assert.NoError(err)
+		assert.Contains(sql, "cron.job")
+		assert.Equal(schema.CronJobId(1), bind.Get("jobid"))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.CronJobId(1).Select(bind, pg.Delete)
+		assert.NoError(err)
+		assert.Contains(sql, "cron.unschedule")
+	})
+
+	t.Run("ZeroId", func(t *testing.T) {
+		_, err := schema.CronJobId(0).Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.CronJobId(1).Select(pg.NewBind(), pg.Update)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_CronJobListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.CronJobListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.NotEmpty(sql)
+		assert.Equal("", bind.Get("where"))
+	})
+
+	t.Run("ListWithFilters", func(t *testing.T) {
+		bind := pg.NewBind()
+		database, username := "testdb", "testuser"
+		_, err := schema.CronJobListRequest{Database: &database, Username: &username}.Select(bind, pg.List)
+		assert.NoError(err)
+		where := bind.Get("where").(string)
+		assert.Contains(where, "database")
+		assert.Contains(where, "AND")
+		assert.Contains(where, "username")
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.CronJobListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_CronJobRunListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("ListWithFilters", func(t *testing.T) {
+		bind := pg.NewBind()
+		job, status := uint64(1), "failed"
+		sql, err := schema.CronJobRunListRequest{Job: &job, Status: &status}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "cron.job_run_details")
+		where := bind.Get("where").(string)
+		assert.Contains(where, "job")
+		assert.Contains(where, "status")
+	})
+}
+
+func Test_CronJobMeta_Insert(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Defaults", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.CronJobMeta{Name: "vacuum", Schedule: "0 3 * * *", Command: "VACUUM"}.Insert(bind)
+		assert.NoError(err)
+		assert.Contains(sql, "cron.schedule_in_database")
+		assert.Equal("vacuum", bind.Get("name"))
+		assert.Nil(bind.Get("database"))
+		assert.Nil(bind.Get("username"))
+		assert.Equal(true, bind.Get("active"))
+	})
+
+	t.Run("Inactive", func(t *testing.T) {
+		bind := pg.NewBind()
+		active := false
+		_, err := schema.CronJobMeta{Name: "vacuum", Schedule: "0 3 * * *", Command: "VACUUM", Database: "testdb", Active: &active}.Insert(bind)
+		assert.NoError(err)
+		assert.Equal("testdb", bind.Get("database"))
+		assert.Equal(false, bind.Get("active"))
+	})
+
+	t.Run("MissingFields", func(t *testing.T) {
+		for _, meta := range []schema.CronJobMeta{
+			{Schedule: "0 3 * * *", Command: "VACUUM"},
+			{Name: "vacuum", Command: "VACUUM"},
+			{Name: "vacuum", Schedule: "0 3 * * *"},
+		} {
+			_, err := meta.Insert(pg.NewBind())
+			assert.ErrorIs(err, pg.ErrBadParameter)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		assert.ErrorIs(schema.CronJobMeta{}.Update(pg.NewBind()), pg.ErrNotImplemented)
+	})
+}
@@ -6,7 +6,7 @@ import (
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
-	types "github.com/mutablelogic/go-pg/pkg/types"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -24,6 +24,19 @@ type DatabaseMeta struct {
 	Name  string  `json:"name,omitempty" arg:"" help:"Name"`
 	Owner string  `json:"owner,omitempty" help:"Owner"`
 	Acl   ACLList `json:"acl,omitempty" help:"Access privileges"`
+
+	// Comment on the database, which is removed when set to empty
+	Comment *string `json:"comment,omitempty" help:"Comment"`
+
+	// Template database to copy when the database is created
+	Template string `json:"template,omitempty" help:"Template database to copy (on create)"`
+}
+
+// DatabaseCloneRequest sets the owner of a copy of a database, and whether
+// the connections to the source database are terminated so it can be copied
+type DatabaseCloneRequest struct {
+	Owner string `json:"owner,omitempty" help:"Owner of the new database, or the owner of the source database when empty"`
+	Force bool   `json:"force,omitempty" help:"Terminate the connections to the source database"`
 }
 
 type DatabaseListRequest struct {
@@ -74,9 +87,8 @@ func (d DatabaseList) String() string {
 // SELECT
 
 func (d DatabaseListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
-	// Set empty where
-	bind.Set("where", "")
-	bind.Set("orderby", "ORDER BY name ASC")
+	// Set empty where and order
+	pg.Where().OrderBy(`name`, true).Bind(bind)
 
 	// Bind offset and limit
 	d.OffsetLimit.Bind(bind, DatabaseListLimit)
@@ -196,7 +208,7 @@ func (d DatabaseName) Update(bind *pg.Bind) error {
 func (d *Database) Scan(row pg.Row) error {
 	var priv []string
 	d.Acl = ACLList{}
-	if err := row.Scan(&d.Oid, &d.Name, &d.Owner, &priv, &d.Size); err != nil {
+	if err := row.Scan(&d.Oid, &d.Name, &d.Owner, &priv, &d.Size, &d.Comment); err != nil {
 		return err
 	}
 	for _, v := range priv {
@@ -268,17 +280,24 @@ func (d DatabaseMeta) with(insert bool) string {
 	// Use validated owner - caller should have validated already
 	if owner := strings.TrimSpace(d.Owner); owner != "" {
 		if insert {
-			with = append(with, "WITH OWNER "+types.DoubleQuote(owner))
+			with = append(with, "OWNER "+quote.Ident(owner))
 		} else {
-			with = append(with, "OWNER TO "+types.DoubleQuote(owner))
+			with = append(with, "OWNER TO "+quote.Ident(owner))
 		}
 	}
 
+	// The template can only be set when the database is created
+	if template := strings.TrimSpace(d.Template); template != "" && insert {
+		with = append(with, "TEMPLATE "+quote.Ident(template))
+	}
+
 	// Return the with clause
-	if len(with) > 0 {
-		return strings.Join(with, " ")
+	if len(with) == 0 {
+		return ""
+	} else if insert {
+		return "WITH " + strings.Join(with, " ")
 	}
-	return ""
+	return strings.Join(with, " ")
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -287,7 +306,7 @@ func (d DatabaseMeta) with(insert bool) string {
 const (
 	databaseSelect = `
 		WITH s AS (SELECT
-			D.oid AS "oid", D.datname AS "name", R.rolname AS "owner", D.datacl AS "acl", pg_database_size(D.oid) AS "size"
+			D.oid AS "oid", D.datname AS "name", R.rolname AS "owner", D.datacl AS "acl", pg_database_size(D.oid) AS "size", shobj_description(D.oid, 'pg_database') AS "comment"
 		FROM
 			${"schema"}."pg_database" D
 		JOIN
@@ -208,6 +208,22 @@ func Test_DatabaseMeta_Insert(t *testing.T) {
 		assert.Contains(with, "myowner")
 	})
 
+	t.Run("InsertWithTemplate", func(t *testing.T) {
+		bind := pg.NewBind()
+		d := schema.DatabaseMeta{Name: "newdb", Owner: "myowner", Template: "tenant_template"}
+		_, err := d.Insert(bind)
+		assert.NoError(err)
+		assert.Equal(`WITH OWNER "myowner" TEMPLATE "tenant_template"`, bind.Get("with"))
+	})
+
+	t.Run("InsertWithTemplateWithoutOwner", func(t *testing.T) {
+		bind := pg.NewBind()
+		d := schema.DatabaseMeta{Name: "newdb", Template: "template0"}
+		_, err := d.Insert(bind)
+		assert.NoError(err)
+		assert.Equal(`WITH TEMPLATE "template0"`, bind.Get("with"))
+	})
+
 	t.Run("InvalidName", func(t *testing.T) {
 		bind := pg.NewBind()
 		d := schema.DatabaseMeta{Name: ""}
@@ -242,6 +258,14 @@ func Test_DatabaseMeta_Update(t *testing.T) {
 		assert.Contains(with, "OWNER TO")
 	})
 
+	t.Run("UpdateIgnoresTemplate", func(t *testing.T) {
+		bind := pg.NewBind()
+		d := schema.DatabaseMeta{Name: "mydb", Template: "template0"}
+		err := d.Update(bind)
+		assert.NoError(err)
+		assert.Equal("", bind.Get("with"))
+	})
+
 	t.Run("UpdateNoOwner", func(t *testing.T) {
 		bind := pg.NewBind()
 		d := schema.DatabaseMeta{Name: "mydb"}
@@ -0,0 +1,223 @@
+package schema
+
+import (
+	"encoding/json"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ObjectDDLRequest selects the object in a schema to reconstruct the
+// statements for, which is a relation or one or more functions with the name
+type ObjectDDLRequest struct {
+	ObjectName
+}
+
+// ObjectDDL is the statements which create an object, reconstructed from the
+// catalog with the pg_get_* functions
+type ObjectDDL struct {
+	Database   string   `json:"database"`
+	Schema     string   `json:"schema"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Statements []string `json:"statements,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (d ObjectDDL) String() string {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (d ObjectDDLRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Validate and set schema and name
+	if schema, err := d.schema(); err != nil {
+		return "", err
+	} else {
+		bind.Set("schema", schema)
+	}
+	if name, err := d.name(); err != nil {
+		return "", err
+	} else {
+		bind.Set("name", name)
+	}
+
+	// Return query
+	switch op {
+	case pg.List:
+		return objectDDLList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported ObjectDDLRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+// Scan appends a statement. The type of the object is the type of the first
+// statement, which creates it
+func (d *ObjectDDL) Scan(row pg.Row) error {
+	var objectType, statement string
+	var position int32
+	if err := row.Scan(&d.Database, &d.Schema, &d.Name, &objectType, &position, &statement); err != nil {
+		return err
+	}
+	if d.Type == "" {
+		d.Type = objectType
+	}
+	d.Statements = append(d.Statements, statement)
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+// The statements are ordered by position, which creates the object, then
+// its indexes or ownership, then its comment
+const (
+	ObjectDDLDef    = `objectddl ("database" TEXT, "schema" TEXT, "name" TEXT, "type" TEXT, "position" INTEGER, "statement" TEXT)`
+	objectDDLSelect = `
+		WITH R AS (
+			SELECT
+				C.oid, C.relkind, C.relispartition, C.relpartbound, N.nspname, C.relname,
+				CASE C.relkind
+					WHEN 'r' THEN 'TABLE'
+					WHEN 'p' THEN 'PARTITIONED TABLE'
+					WHEN 'v' THEN 'VIEW'
+					WHEN 'm' THEN 'MATERIALIZED VIEW'
+					WHEN 'S' THEN 'SEQUENCE'
+					WHEN 'i' THEN 'INDEX'
+					WHEN 'I' THEN 'PARTITIONED INDEX'
+				END AS type
+			FROM
+				pg_catalog.pg_class C
+			JOIN
+				pg_catalog.pg_namespace N ON N.oid = C.relnamespace
+			WHERE
+				N.nspname = @schema::TEXT AND C.relname = @name::TEXT AND C.relkind IN ('r', 'p', 'v', 'm', 'S', 'i', 'I')
+		), P AS (
+			SELECT
+				P.oid, CASE P.prokind WHEN 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END AS type
+			FROM
+				pg_catalog.pg_proc P
+			JOIN
+				pg_catalog.pg_namespace N ON N.oid = P.pronamespace
+			WHERE
+				N.nspname = @schema::TEXT AND P.proname = @name::TEXT AND P.prokind IN ('f', 'p')
+		), ddl AS (
+			SELECT
+				R.type, 1 AS position, CASE WHEN R.relispartition THEN format('CREATE TABLE %I.%I PARTITION OF %s %s', R.nspname, R.relname, (
+					SELECT format('%I.%I', PN.nspname, PC.relname) FROM pg_catalog.pg_inherits I JOIN pg_catalog.pg_class PC ON PC.oid = I.inhparent JOIN pg_catalog.pg_namespace PN ON PN.oid = PC.relnamespace WHERE I.inhrelid = R.oid
+				), pg_get_expr(R.relpartbound, R.oid)) ELSE format('CREATE TABLE %I.%I (%s)%s', R.nspname, R.relname, array_to_string(ARRAY(
+					SELECT
+						format('%I %s', A.attname, format_type(A.atttypid, A.atttypmod)) || CASE
+							WHEN A.attidentity = 'a' THEN ' GENERATED ALWAYS AS IDENTITY'
+							WHEN A.attidentity = 'd' THEN ' GENERATED BY DEFAULT AS IDENTITY'
+							WHEN A.attgenerated = 's' THEN ' GENERATED ALWAYS AS (' || pg_get_expr(D.adbin, D.adrelid) || ') STORED'
+							WHEN A.attgenerated = 'v' THEN ' GENERATED ALWAYS AS (' || pg_get_expr(D.adbin, D.adrelid) || ')'
+							WHEN D.adbin IS NOT NULL THEN ' DEFAULT ' || pg_get_expr(D.adbin, D.adrelid)
+							ELSE ''
+						END || CASE WHEN A.attnotnull THEN ' NOT NULL' ELSE '' END
+					FROM
+						pg_catalog.pg_attribute A
+					LEFT JOIN
+						pg_catalog.pg_attrdef D ON D.adrelid = A.attrelid AND D.adnum = A.attnum
+					WHERE
+						A.attrelid = R.oid AND A.attnum > 0 AND NOT A.attisdropped
+					ORDER BY
+						A.attnum
+				) || ARRAY(
+					SELECT
+						format('CONSTRAINT %I %s', K.conname, pg_get_constraintdef(K.oid, true))
+					FROM
+						pg_catalog.pg_constraint K
+					WHERE
+						K.conrelid = R.oid AND K.contype IN ('c', 'f', 'p', 'u', 'x')
+					ORDER BY
+						K.contype = 'f', K.conname
+				), ', '), CASE WHEN R.relkind = 'p' THEN ' PARTITION BY ' || pg_get_partkeydef(R.oid) ELSE '' END) END AS statement
+			FROM
+				R
+			WHERE
+				R.relkind IN ('r', 'p')
+			UNION ALL
+			SELECT
+				R.type, 1, format('CREATE %s %I.%I AS %s', R.type, R.nspname, R.relname, rtrim(btrim(pg_get_viewdef(R.oid, true)), ';'))
+			FROM
+				R
+			WHERE
+				R.relkind IN ('v', 'm')
+			UNION ALL
+			SELECT
+				R.type, 1, format('CREATE SEQUENCE %I.%I AS %s INCREMENT BY %s MINVALUE %s MAXVALUE %s START WITH %s CACHE %s %s', R.nspname, R.relname, format_type(S.seqtypid, NULL), S.seqincrement, S.seqmin, S.seqmax, S.seqstart, S.seqcache, CASE WHEN S.seqcycle THEN 'CYCLE' ELSE 'NO CYCLE' END)
+			FROM
+				R
+			JOIN
+				pg_catalog.pg_sequence S ON S.seqrelid = R.oid
+			UNION ALL
+			SELECT
+				R.type, 1, pg_get_indexdef(R.oid)
+			FROM
+				R
+			WHERE
+				R.relkind IN ('i', 'I')
+			UNION ALL
+			SELECT
+				P.type, 1, rtrim(pg_get_functiondef(P.oid), chr(10))
+			FROM
+				P
+			UNION ALL
+			SELECT
+				R.type, 2, pg_get_indexdef(X.indexrelid)
+			FROM
+				R
+			JOIN
+				pg_catalog.pg_index X ON X.indrelid = R.oid
+			WHERE
+				R.relkind IN ('r', 'p', 'm') AND NOT EXISTS (
+					SELECT 1 FROM pg_catalog.pg_constraint K WHERE K.conrelid = R.oid AND K.conindid = X.indexrelid AND K.contype IN ('p', 'u', 'x')
+				)
+			UNION ALL
+			SELECT
+				R.type, 2, format('ALTER SEQUENCE %I.%I OWNED BY %I.%I.%I', R.nspname, R.relname, TN.nspname, TC.relname, A.attname)
+			FROM
+				R
+			JOIN
+				pg_catalog.pg_depend DP ON DP.classid = 'pg_catalog.pg_class'::REGCLASS AND DP.objid = R.oid AND DP.refclassid = 'pg_catalog.pg_class'::REGCLASS AND DP.deptype = 'a' AND DP.refobjsubid > 0
+			JOIN
+				pg_catalog.pg_class TC ON TC.oid = DP.refobjid
+			JOIN
+				pg_catalog.pg_namespace TN ON TN.oid = TC.relnamespace
+			JOIN
+				pg_catalog.pg_attribute A ON A.attrelid = DP.refobjid AND A.attnum = DP.refobjsubid
+			WHERE
+				R.relkind = 'S'
+			UNION ALL
+			SELECT
+				R.type, 3, format('COMMENT ON %s %I.%I IS %L', CASE R.relkind WHEN 'p' THEN 'TABLE' WHEN 'I' THEN 'INDEX' ELSE R.type END, R.nspname, R.relname, obj_description(R.oid, 'pg_class'))
+			FROM
+				R
+			WHERE
+				obj_description(R.oid, 'pg_class') IS NOT NULL
+			UNION ALL
+			SELECT
+				P.type, 3, format('COMMENT ON %s %s IS %L', P.type, P.oid::REGPROCEDURE, obj_description(P.oid, 'pg_proc'))
+			FROM
+				P
+			WHERE
+				obj_description(P.oid, 'pg_proc') IS NOT NULL
+		) SELECT current_database() AS "database", @schema::TEXT AS "schema", @name::TEXT AS "name", ddl.type AS "type", ddl.position AS "position", ddl.statement AS "statement" FROM ddl
+	`
+	objectDDLList = `WITH q AS (` + objectDDLSelect + `) SELECT * FROM q ORDER BY "position", "statement"`
+)
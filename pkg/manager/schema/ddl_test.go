@@ -0,0 +1,38 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_ObjectDDLRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.ObjectDDLRequest{ObjectName: schema.ObjectName{Schema: "public", Name: "users"}}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_get_indexdef")
+		assert.Equal("public", bind.Get("schema"))
+		assert.Equal("users", bind.Get("name"))
+	})
+
+	t.Run("MissingSchema", func(t *testing.T) {
+		_, err := schema.ObjectDDLRequest{ObjectName: schema.ObjectName{Name: "users"}}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("MissingName", func(t *testing.T) {
+		_, err := schema.ObjectDDLRequest{ObjectName: schema.ObjectName{Schema: "public"}}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.ObjectDDLRequest{ObjectName: schema.ObjectName{Schema: "public", Name: "users"}}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
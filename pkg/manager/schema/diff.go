@@ -0,0 +1,468 @@
+package schema
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// SchemaObject is a table, column, constraint or index which is compared
+// between databases. The definition is the part of the statement which
+// creates the object after its name, as printed by the server.
+type SchemaObject struct {
+	Type       DifferenceType `json:"type"`
+	Schema     string         `json:"schema"`
+	Table      string         `json:"table"`
+	Name       string         `json:"name"`
+	Position   int32          `json:"position,omitempty"`
+	Definition string         `json:"definition"`
+	DataType   *string        `json:"data_type,omitempty"` // Column type
+	NotNull    *bool          `json:"not_null,omitempty"`  // Column is not null
+	Default    *string        `json:"default,omitempty"`   // Column default
+}
+
+type SchemaObjectListRequest struct {
+	Schema string `json:"schema,omitempty"`
+}
+
+type SchemaObjectList struct {
+	Count uint64         `json:"count"`
+	Body  []SchemaObject `json:"body,omitempty"`
+}
+
+// SchemaDiffRequest sets the schemas which are compared between databases.
+// When the schema is empty, all schemas are compared, otherwise the schema is
+// compared with the other schema, which is the same schema when empty.
+type SchemaDiffRequest struct {
+	Schema      string `json:"schema,omitempty" help:"Schema in the database, or all schemas when empty"`
+	OtherSchema string `json:"other_schema,omitempty" help:"Schema in the other database, or the same schema when empty"`
+}
+
+// SchemaDiff is the tables, columns, constraints and indexes which differ
+// between databases, and the statements which change the first database to
+// match the other. The statements should be reviewed before they are run.
+type SchemaDiff struct {
+	Comparison
+	Statements []string `json:"statements,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	DifferenceTable      DifferenceType = "table"
+	DifferenceColumn     DifferenceType = "column"
+	DifferenceConstraint DifferenceType = "constraint"
+	DifferenceIndex      DifferenceType = "index"
+)
+
+// The order of the statements which change a schema
+const (
+	diffCreateTable = iota
+	diffAddColumn
+	diffAlterColumn
+	diffDropConstraint
+	diffDropIndex
+	diffAddConstraint
+	diffCreateIndex
+	diffDropColumn
+	diffDropTable
+	diffPhases
+)
+
+var (
+	diffTypes = []DifferenceType{DifferenceTable, DifferenceColumn, DifferenceConstraint, DifferenceIndex}
+
+	// A name which the server prints without quotes
+	reSimpleIdent = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (o SchemaObject) String() string {
+	data, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (l SchemaObjectList) String() string {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (d SchemaDiff) String() string {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Diff returns the tables, columns, constraints and indexes which differ
+// between this list and another, and the statements which change the objects
+// in this list to match the other. When a schema is set, the objects in the
+// schema are compared with the objects in the other schema; otherwise objects
+// are compared in the schemas with the same name. The columns, constraints
+// and indexes of a table which is only in one list are created or dropped with
+// the table, and are not listed.
+func (l SchemaObjectList) Diff(other SchemaObjectList, schema, otherSchema string) SchemaDiff {
+	objects := diffObjects(l.Body, "", "")
+	others := diffObjects(other.Body, otherSchema, schema)
+
+	// Collect the keys of the objects, ordered by type and name
+	keys := make([]string, 0, len(objects)+len(others))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	for key := range others {
+		if _, exists := objects[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return compareDiffKeys(objects, others, a, b)
+	})
+
+	// Compare the objects and make the statements in phases
+	var result SchemaDiff
+	var phases [diffPhases][]string
+	for _, key := range keys {
+		object, ok := objects[key]
+		otherObject, otherOk := others[key]
+		if ok && otherOk && object.Definition == otherObject.Definition {
+			continue
+		}
+
+		// Objects of a table which is created or dropped are not listed, and
+		// the columns of a table which is created are created with the table
+		base := object
+		if !ok {
+			base = otherObject
+		}
+		_, tableOk := objects[tableKey(base)]
+		_, otherTableOk := others[tableKey(base)]
+		if base.Type != DifferenceTable && tableOk != otherTableOk {
+			if otherOk && !tableOk && base.Type != DifferenceColumn {
+				otherObject.create(&phases, others)
+			}
+			continue
+		}
+
+		// Add the difference
+		diff := Difference{Type: base.Type, Name: base.name()}
+		if ok {
+			diff.Value = types.StringPtr(object.Definition)
+		}
+		if otherOk {
+			diff.Other = types.StringPtr(otherObject.Definition)
+		}
+		result.Body = append(result.Body, diff)
+
+		// Add the statements
+		switch {
+		case !ok:
+			otherObject.create(&phases, others)
+		case !otherOk:
+			object.drop(&phases)
+		default:
+			object.alter(&phases, otherObject, others)
+		}
+	}
+
+	// Return the differences and statements
+	result.Count = uint64(len(result.Body))
+	for _, phase := range phases {
+		result.Statements = append(result.Statements, phase...)
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (r SchemaObjectListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Where
+	where := pg.Where()
+	if schema := strings.TrimSpace(r.Schema); schema != "" {
+		where.Eq(`schema`, schema)
+	}
+	where.Bind(bind)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return schemaObjectList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported SchemaObjectListRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (o *SchemaObject) Scan(row pg.Row) error {
+	return row.Scan(&o.Type, &o.Schema, &o.Table, &o.Name, &o.Position, &o.Definition, &o.DataType, &o.NotNull, &o.Default)
+}
+
+func (l *SchemaObjectList) Scan(row pg.Row) error {
+	var object SchemaObject
+	if err := object.Scan(row); err != nil {
+		return err
+	}
+	l.Body = append(l.Body, object)
+	return nil
+}
+
+func (l *SchemaObjectList) ScanCount(row pg.Row) error {
+	return row.Scan(&l.Count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the objects by key. When the schema is set, the objects are moved to
+// another schema, and references to the schema in definitions are replaced.
+func diffObjects(objects []SchemaObject, schema, to string) map[string]SchemaObject {
+	result := make(map[string]SchemaObject, len(objects))
+	for _, object := range objects {
+		if schema != "" && to != "" && schema != to {
+			object.Schema = to
+			object.Definition = replaceSchema(object.Definition, schema, to)
+			if object.Default != nil {
+				object.Default = types.StringPtr(replaceSchema(*object.Default, schema, to))
+			}
+		}
+		result[object.key()] = object
+	}
+	return result
+}
+
+// Replace references to objects in a schema, such as "other.table" or
+// "other"."table", with references to the same objects in another schema,
+// which is quoted in the same way as the server when it prints a name
+func replaceSchema(definition, schema, to string) string {
+	name := to
+	if !reSimpleIdent.MatchString(to) {
+		name = quote.Ident(to)
+	}
+	for _, from := range []string{schema, quote.Ident(schema)} {
+		re := regexp.MustCompile(`(^|[^\w"$])` + regexp.QuoteMeta(from) + `\.`)
+		definition = re.ReplaceAllString(definition, "${1}"+strings.ReplaceAll(name, "$", "$$")+".")
+	}
+	return definition
+}
+
+// Order the keys by the type of object, then by name
+func compareDiffKeys(objects, others map[string]SchemaObject, a, b string) int {
+	objectA, ok := objects[a]
+	if !ok {
+		objectA = others[a]
+	}
+	objectB, ok := objects[b]
+	if !ok {
+		objectB = others[b]
+	}
+	if n := slices.Index(diffTypes, objectA.Type) - slices.Index(diffTypes, objectB.Type); n != 0 {
+		return n
+	}
+	if n := strings.Compare(objectA.Schema+"."+objectA.Table, objectB.Schema+"."+objectB.Table); n != 0 {
+		return n
+	}
+	if objectA.Position != objectB.Position {
+		return int(objectA.Position - objectB.Position)
+	}
+	return strings.Compare(objectA.Name, objectB.Name)
+}
+
+// Return the key of an object, which is unique within the objects compared
+func (o SchemaObject) key() string {
+	return string(o.Type) + "/" + o.Schema + "/" + o.Table + "/" + o.Name
+}
+
+// Return the key of the table of an object
+func tableKey(o SchemaObject) string {
+	return SchemaObject{Type: DifferenceTable, Schema: o.Schema, Table: o.Table, Name: o.Table}.key()
+}
+
+// Return the name of an object, qualified with the schema and table
+func (o SchemaObject) name() string {
+	if o.Type == DifferenceTable {
+		return o.Schema + "." + o.Table
+	}
+	return o.Schema + "." + o.Table + "." + o.Name
+}
+
+// Return the quoted name of the table of an object
+func (o SchemaObject) table() string {
+	return quote.Ident(o.Schema, o.Table)
+}
+
+// Add the statements which create an object
+func (o SchemaObject) create(phases *[diffPhases][]string, objects map[string]SchemaObject) {
+	switch o.Type {
+	case DifferenceTable:
+		// Create the table with the columns
+		var columns []SchemaObject
+		for _, object := range objects {
+			if object.Type == DifferenceColumn && object.Schema == o.Schema && object.Table == o.Table {
+				columns = append(columns, object)
+			}
+		}
+		slices.SortFunc(columns, func(a, b SchemaObject) int {
+			return int(a.Position - b.Position)
+		})
+		fields := make([]string, 0, len(columns))
+		for _, column := range columns {
+			fields = append(fields, quote.Ident(column.Name)+" "+column.Definition)
+		}
+		statement := `CREATE TABLE ` + o.table() + ` (` + strings.Join(fields, ", ") + `)`
+		if o.Definition != "" {
+			statement += " " + o.Definition
+		}
+		phases[diffCreateTable] = append(phases[diffCreateTable], statement)
+	case DifferenceColumn:
+		phases[diffAddColumn] = append(phases[diffAddColumn], `ALTER TABLE `+o.table()+` ADD COLUMN `+quote.Ident(o.Name)+` `+o.Definition)
+	case DifferenceConstraint:
+		statement := `ALTER TABLE ` + o.table() + ` ADD CONSTRAINT ` + quote.Ident(o.Name) + ` ` + o.Definition
+		if strings.HasPrefix(o.Definition, "FOREIGN KEY") {
+			// Foreign keys are added after the keys they reference
+			phases[diffAddConstraint] = append(phases[diffAddConstraint], statement)
+		} else {
+			phases[diffAddConstraint] = append([]string{statement}, phases[diffAddConstraint]...)
+		}
+	case DifferenceIndex:
+		unique, definition := "", o.Definition
+		if rest, ok := strings.CutPrefix(definition, "UNIQUE "); ok {
+			unique, definition = "UNIQUE ", rest
+		}
+		phases[diffCreateIndex] = append(phases[diffCreateIndex], `CREATE `+unique+`INDEX `+quote.Ident(o.Name)+` ON `+o.table()+` `+definition)
+	}
+}
+
+// Add the statements which drop an object
+func (o SchemaObject) drop(phases *[diffPhases][]string) {
+	switch o.Type {
+	case DifferenceTable:
+		phases[diffDropTable] = append(phases[diffDropTable], `DROP TABLE `+o.table())
+	case DifferenceColumn:
+		phases[diffDropColumn] = append(phases[diffDropColumn], `ALTER TABLE `+o.table()+` DROP COLUMN `+quote.Ident(o.Name))
+	case DifferenceConstraint:
+		statement := `ALTER TABLE ` + o.table() + ` DROP CONSTRAINT ` + quote.Ident(o.Name)
+		if strings.HasPrefix(o.Definition, "FOREIGN KEY") {
+			// Foreign keys are dropped before the keys they reference
+			phases[diffDropConstraint] = append([]string{statement}, phases[diffDropConstraint]...)
+		} else {
+			phases[diffDropConstraint] = append(phases[diffDropConstraint], statement)
+		}
+	case DifferenceIndex:
+		phases[diffDropIndex] = append(phases[diffDropIndex], `DROP INDEX `+quote.Ident(o.Schema, o.Name))
+	}
+}
+
+// Add the statements which change an object to match another
+func (o SchemaObject) alter(phases *[diffPhases][]string, other SchemaObject, others map[string]SchemaObject) {
+	switch o.Type {
+	case DifferenceColumn:
+		prefix := `ALTER TABLE ` + o.table() + ` ALTER COLUMN ` + quote.Ident(o.Name)
+		if types.PtrString(o.DataType) != types.PtrString(other.DataType) {
+			phases[diffAlterColumn] = append(phases[diffAlterColumn], prefix+` TYPE `+types.PtrString(other.DataType))
+		}
+		if types.PtrBool(o.NotNull) != types.PtrBool(other.NotNull) {
+			if types.PtrBool(other.NotNull) {
+				phases[diffAlterColumn] = append(phases[diffAlterColumn], prefix+` SET NOT NULL`)
+			} else {
+				phases[diffAlterColumn] = append(phases[diffAlterColumn], prefix+` DROP NOT NULL`)
+			}
+		}
+		if types.PtrString(o.Default) != types.PtrString(other.Default) {
+			if other.Default != nil {
+				phases[diffAlterColumn] = append(phases[diffAlterColumn], prefix+` SET DEFAULT `+*other.Default)
+			} else {
+				phases[diffAlterColumn] = append(phases[diffAlterColumn], prefix+` DROP DEFAULT`)
+			}
+		}
+	case DifferenceConstraint, DifferenceIndex:
+		o.drop(phases)
+		other.create(phases, others)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	SchemaObjectDef    = `schemaobject ("type" TEXT, "schema" TEXT, "table" TEXT, "name" TEXT, "position" INTEGER, "definition" TEXT, "data_type" TEXT, "not_null" BOOLEAN, "default" TEXT)`
+	schemaObjectSelect = `
+		WITH R AS (
+			SELECT
+				C.oid, N.nspname::TEXT AS "schema", C.relname::TEXT AS "table"
+			FROM
+				pg_catalog.pg_class C
+			JOIN
+				pg_catalog.pg_namespace N ON N.oid = C.relnamespace
+			WHERE
+				C.relkind IN ('r', 'p') AND NOT C.relispartition
+				AND N.nspname NOT IN ('pg_catalog', 'information_schema') AND N.nspname NOT LIKE 'pg\_toast%' AND N.nspname NOT LIKE 'pg\_temp%'
+				AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_depend E WHERE E.classid = 'pg_class'::REGCLASS AND E.objid = C.oid AND E.deptype = 'e')
+		)
+		SELECT
+			'table' AS "type", R."schema", R."table", R."table" AS "name", 0 AS "position",
+			COALESCE('PARTITION BY ' || pg_get_partkeydef(R.oid), '') AS "definition",
+			NULL::TEXT AS "data_type", NULL::BOOLEAN AS "not_null", NULL::TEXT AS "default"
+		FROM
+			R
+		UNION ALL SELECT
+			'column', R."schema", R."table", A.attname::TEXT, A.attnum::INTEGER,
+			format_type(A.atttypid, A.atttypmod)
+				|| CASE WHEN A.attgenerated = 's' THEN ' GENERATED ALWAYS AS (' || pg_get_expr(D.adbin, D.adrelid) || ') STORED' ELSE '' END
+				|| CASE A.attidentity WHEN 'a' THEN ' GENERATED ALWAYS AS IDENTITY' WHEN 'd' THEN ' GENERATED BY DEFAULT AS IDENTITY' ELSE '' END
+				|| CASE WHEN A.attnotnull THEN ' NOT NULL' ELSE '' END
+				|| CASE WHEN D.adbin IS NOT NULL AND A.attgenerated = '' THEN ' DEFAULT ' || pg_get_expr(D.adbin, D.adrelid) ELSE '' END,
+			format_type(A.atttypid, A.atttypmod), A.attnotnull,
+			CASE WHEN A.attgenerated = '' THEN pg_get_expr(D.adbin, D.adrelid) END
+		FROM
+			R
+		JOIN
+			pg_catalog.pg_attribute A ON A.attrelid = R.oid AND A.attnum > 0 AND NOT A.attisdropped
+		LEFT JOIN
+			pg_catalog.pg_attrdef D ON D.adrelid = A.attrelid AND D.adnum = A.attnum
+		UNION ALL SELECT
+			'constraint', R."schema", R."table", K.conname::TEXT, 0,
+			pg_get_constraintdef(K.oid), NULL, NULL, NULL
+		FROM
+			R
+		JOIN
+			pg_catalog.pg_constraint K ON K.conrelid = R.oid AND K.contype IN ('c', 'f', 'p', 'u', 'x')
+		UNION ALL SELECT
+			'index', R."schema", R."table", I.relname::TEXT, 0,
+			CASE WHEN X.indisunique THEN 'UNIQUE ' ELSE '' END || ltrim(substring(pg_get_indexdef(X.indexrelid) FROM ' USING .*$')),
+			NULL, NULL, NULL
+		FROM
+			R
+		JOIN
+			pg_catalog.pg_index X ON X.indrelid = R.oid
+		JOIN
+			pg_catalog.pg_class I ON I.oid = X.indexrelid
+		WHERE
+			NOT EXISTS (SELECT 1 FROM pg_catalog.pg_constraint K WHERE K.conindid = X.indexrelid AND K.contype IN ('p', 'u', 'x'))
+	`
+	schemaObjectList = `WITH q AS (` + schemaObjectSelect + `) SELECT * FROM q ${where} ORDER BY "schema", "table", "type", "position", "name"`
+)
@@ -0,0 +1,184 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func diffTable(s, table string) schema.SchemaObject {
+	return schema.SchemaObject{Type: schema.DifferenceTable, Schema: s, Table: table, Name: table}
+}
+
+func diffColumn(s, table, name string, position int32, dataType string, notNull bool, def *string) schema.SchemaObject {
+	definition := dataType
+	if notNull {
+		definition += " NOT NULL"
+	}
+	if def != nil {
+		definition += " DEFAULT " + *def
+	}
+	return schema.SchemaObject{Type: schema.DifferenceColumn, Schema: s, Table: table, Name: name, Position: position, Definition: definition, DataType: types.StringPtr(dataType), NotNull: types.BoolPtr(notNull), Default: def}
+}
+
+func diffObject(t schema.DifferenceType, s, table, name, definition string) schema.SchemaObject {
+	return schema.SchemaObject{Type: t, Schema: s, Table: table, Name: name, Definition: definition}
+}
+
+func Test_SchemaObjectListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.SchemaObjectListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_constraint")
+		assert.Equal("", bind.Get("where"))
+	})
+
+	t.Run("WithSchema", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.SchemaObjectListRequest{Schema: "public"}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.NotEqual("", bind.Get("where"))
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.SchemaObjectListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_SchemaObjectList_Diff(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Same", func(t *testing.T) {
+		list := schema.SchemaObjectList{Body: []schema.SchemaObject{
+			diffTable("public", "users"),
+			diffColumn("public", "users", "id", 1, "integer", true, nil),
+		}}
+		diff := list.Diff(list, "", "")
+		assert.Zero(diff.Count)
+		assert.Empty(diff.Statements)
+	})
+
+	t.Run("CreateTable", func(t *testing.T) {
+		other := schema.SchemaObjectList{Body: []schema.SchemaObject{
+			diffTable("public", "users"),
+			diffColumn("public", "users", "name", 2, "text", false, nil),
+			diffColumn("public", "users", "id", 1, "integer", true, nil),
+			diffObject(schema.DifferenceConstraint, "public", "users", "users_pkey", "PRIMARY KEY (id)"),
+			diffObject(schema.DifferenceIndex, "public", "users", "users_name_idx", "UNIQUE USING btree (name)"),
+		}}
+		diff := schema.SchemaObjectList{}.Diff(other, "", "")
+		if assert.Equal(uint64(1), diff.Count) {
+			assert.Equal(schema.DifferenceTable, diff.Body[0].Type)
+			assert.Equal("public.users", diff.Body[0].Name)
+			assert.Nil(diff.Body[0].Value)
+			assert.NotNil(diff.Body[0].Other)
+		}
+		assert.Equal([]string{
+			`CREATE TABLE "public"."users" ("id" integer NOT NULL, "name" text)`,
+			`ALTER TABLE "public"."users" ADD CONSTRAINT "users_pkey" PRIMARY KEY (id)`,
+			`CREATE UNIQUE INDEX "users_name_idx" ON "public"."users" USING btree (name)`,
+		}, diff.Statements)
+	})
+
+	t.Run("DropTable", func(t *testing.T) {
+		list := schema.SchemaObjectList{Body: []schema.SchemaObject{
+			diffTable("public", "users"),
+			diffColumn("public", "users", "id", 1, "integer", true, nil),
+			diffObject(schema.DifferenceConstraint, "public", "users", "users_pkey", "PRIMARY KEY (id)"),
+		}}
+		diff := list.Diff(schema.SchemaObjectList{}, "", "")
+		assert.Equal(uint64(1), diff.Count)
+		assert.Equal([]string{`DROP TABLE "public"."users"`}, diff.Statements)
+	})
+
+	t.Run("Columns", func(t *testing.T) {
+		list := schema.SchemaObjectList{Body: []schema.SchemaObject{
+			diffTable("public", "users"),
+			diffColumn("public", "users", "id", 1, "integer", true, nil),
+			diffColumn("public", "users", "age", 2, "integer", false, nil),
+			diffColumn("public", "users", "old", 3, "text", false, nil),
+		}}
+		other := schema.SchemaObjectList{Body: []schema.SchemaObject{
+			diffTable("public", "users"),
+			diffColumn("public", "users", "id", 1, "bigint", true, nil),
+			diffColumn("public", "users", "age", 2, "integer", true, types.StringPtr("0")),
+			diffColumn("public", "users", "email", 4, "text", false, nil),
+		}}
+		diff := list.Diff(other, "", "")
+		assert.Equal(uint64(4), diff.Count)
+		assert.Equal([]string{
+			`ALTER TABLE "public"."users" ADD COLUMN "email" text`,
+			`ALTER TABLE "public"."users" ALTER COLUMN "id" TYPE bigint`,
+			`ALTER TABLE "public"."users" ALTER COLUMN "age" SET NOT NULL`,
+			`ALTER TABLE "public"."users" ALTER COLUMN "age" SET DEFAULT 0`,
+			`ALTER TABLE "public"."users" DROP COLUMN "old"`,
+		}, diff.Statements)
+	})
+
+	t.Run("ForeignKeys", func(t *testing.T) {
+		list := schema.SchemaObjectList{Body: []schema.SchemaObject{
+			diffTable("public", "orders"),
+			diffObject(schema.DifferenceConstraint, "public", "orders", "orders_pkey", "PRIMARY KEY (id)"),
+			diffObject(schema.DifferenceConstraint, "public", "orders", "orders_user_fkey", "FOREIGN KEY (user_id) REFERENCES users(id)"),
+		}}
+		other := schema.SchemaObjectList{Body: []schema.SchemaObject{
+			diffTable("public", "orders"),
+			diffObject(schema.DifferenceConstraint, "public", "orders", "orders_pkey", "PRIMARY KEY (id, user_id)"),
+			diffObject(schema.DifferenceConstraint, "public", "orders", "orders_user_fkey", "FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE"),
+		}}
+		diff := list.Diff(other, "", "")
+		assert.Equal(uint64(2), diff.Count)
+		assert.Equal([]string{
+			`ALTER TABLE "public"."orders" DROP CONSTRAINT "orders_user_fkey"`,
+			`ALTER TABLE "public"."orders" DROP CONSTRAINT "orders_pkey"`,
+			`ALTER TABLE "public"."orders" ADD CONSTRAINT "orders_pkey" PRIMARY KEY (id, user_id)`,
+			`ALTER TABLE "public"."orders" ADD CONSTRAINT "orders_user_fkey" FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE`,
+		}, diff.Statements)
+	})
+
+	t.Run("Indexes", func(t *testing.T) {
+		list := schema.SchemaObjectList{Body: []schema.SchemaObject{
+			diffTable("public", "users"),
+			diffObject(schema.DifferenceIndex, "public", "users", "users_name_idx", "USING btree (name)"),
+		}}
+		other := schema.SchemaObjectList{Body: []schema.SchemaObject{
+			diffTable("public", "users"),
+			diffObject(schema.DifferenceIndex, "public", "users", "users_name_idx", "USING hash (name)"),
+		}}
+		diff := list.Diff(other, "", "")
+		if assert.Equal(uint64(1), diff.Count) {
+			assert.Equal("public.users.users_name_idx", diff.Body[0].Name)
+			assert.Equal("USING btree (name)", types.PtrString(diff.Body[0].Value))
+			assert.Equal("USING hash (name)", types.PtrString(diff.Body[0].Other))
+		}
+		assert.Equal([]string{
+			`DROP INDEX "public"."users_name_idx"`,
+			`CREATE INDEX "users_name_idx" ON "public"."users" USING hash (name)`,
+		}, diff.Statements)
+	})
+
+	t.Run("OtherSchema", func(t *testing.T) {
+		list := schema.SchemaObjectList{Body: []schema.SchemaObject{
+			diffTable("staging", "orders"),
+			diffObject(schema.DifferenceConstraint, "staging", "orders", "orders_user_fkey", "FOREIGN KEY (user_id) REFERENCES staging.users(id)"),
+		}}
+		other := schema.SchemaObjectList{Body: []schema.SchemaObject{
+			diffTable("public", "orders"),
+			diffObject(schema.DifferenceConstraint, "public", "orders", "orders_user_fkey", "FOREIGN KEY (user_id) REFERENCES public.users(id)"),
+			diffTable("public", "users"),
+		}}
+		diff := list.Diff(other, "staging", "public")
+		if assert.Equal(uint64(1), diff.Count) {
+			assert.Equal("staging.users", diff.Body[0].Name)
+		}
+		assert.Equal([]string{`CREATE TABLE "staging"."users" ()`}, diff.Statements)
+	})
+}
@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"encoding/json"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ExplainOptions are the options for explaining a query
+type ExplainOptions struct {
+	Analyze bool `json:"analyze,omitempty" help:"Execute the query and report actual run times"`
+	Buffers bool `json:"buffers,omitempty" help:"Report buffer usage"`
+	Verbose bool `json:"verbose,omitempty" help:"Report the schema of relations and the output of each node"`
+}
+
+// ExplainRequest is a request to explain a query in a database
+type ExplainRequest struct {
+	Database string `json:"database,omitempty" help:"Database to explain the query in"`
+	Query    string `json:"query" arg:"" help:"Query to explain"`
+	ExplainOptions
+}
+
+// Explain is the plan for a query
+type Explain struct {
+	Database      string      `json:"database,omitempty"`
+	Query         string      `json:"query"`
+	Plan          ExplainPlan `json:"plan"`
+	PlanningTime  *float64    `json:"planning_time,omitempty"`  // In milliseconds
+	ExecutionTime *float64    `json:"execution_time,omitempty"` // In milliseconds, when analyzed
+}
+
+// ExplainPlan is a node in the plan tree, with the field names as
+// returned by EXPLAIN (FORMAT JSON) so the plan can be passed to a
+// visualizer unchanged
+type ExplainPlan struct {
+	NodeType           string        `json:"Node Type"`
+	ParentRelationship string        `json:"Parent Relationship,omitempty"`
+	RelationName       string        `json:"Relation Name,omitempty"`
+	Schema             string        `json:"Schema,omitempty"`
+	Alias              string        `json:"Alias,omitempty"`
+	IndexName          string        `json:"Index Name,omitempty"`
+	JoinType           string        `json:"Join Type,omitempty"`
+	Strategy           string        `json:"Strategy,omitempty"`
+	StartupCost        float64       `json:"Startup Cost"`
+	TotalCost          float64       `json:"Total Cost"`
+	PlanRows           float64       `json:"Plan Rows"`
+	PlanWidth          uint64        `json:"Plan Width"`
+	ActualStartupTime  *float64      `json:"Actual Startup Time,omitempty"`
+	ActualTotalTime    *float64      `json:"Actual Total Time,omitempty"`
+	ActualRows         *float64      `json:"Actual Rows,omitempty"`
+	ActualLoops        *uint64       `json:"Actual Loops,omitempty"`
+	Filter             string        `json:"Filter,omitempty"`
+	IndexCond          string        `json:"Index Cond,omitempty"`
+	HashCond           string        `json:"Hash Cond,omitempty"`
+	JoinFilter         string        `json:"Join Filter,omitempty"`
+	RowsRemoved        *uint64       `json:"Rows Removed by Filter,omitempty"`
+	SharedHitBlocks    *uint64       `json:"Shared Hit Blocks,omitempty"`
+	SharedReadBlocks   *uint64       `json:"Shared Read Blocks,omitempty"`
+	SharedDirtied      *uint64       `json:"Shared Dirtied Blocks,omitempty"`
+	SharedWritten      *uint64       `json:"Shared Written Blocks,omitempty"`
+	TempReadBlocks     *uint64       `json:"Temp Read Blocks,omitempty"`
+	TempWrittenBlocks  *uint64       `json:"Temp Written Blocks,omitempty"`
+	Plans              []ExplainPlan `json:"Plans,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (e Explain) String() string {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (e ExplainPlan) String() string {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (e ExplainRequest) String() string {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (e ExplainRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// The query is required, and must be a single statement, ignoring any
+	// semicolons in literals, quoted identifiers and comments
+	if query, err := singleStatement(e.Query); err != nil {
+		return "", err
+	} else {
+		bind.Set("query", query)
+	}
+
+	// Options
+	bind.Set("analyze", e.Analyze)
+	bind.Set("buffers", e.Buffers)
+	bind.Set("verbose", e.Verbose)
+
+	// Statement to run before the explain, if any
+	if !bind.Has("readonly") {
+		bind.Set("readonly", "")
+	}
+
+	// Return query
+	switch op {
+	case pg.Get:
+		return explainQuery, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported ExplainRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (e *Explain) Scan(row pg.Row) error {
+	var data []byte
+	if err := row.Scan(&data); err != nil {
+		return err
+	}
+
+	// EXPLAIN returns an array with a single element
+	var result []struct {
+		Plan          ExplainPlan `json:"Plan"`
+		PlanningTime  *float64    `json:"Planning Time,omitempty"`
+		ExecutionTime *float64    `json:"Execution Time,omitempty"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	} else if len(result) == 0 {
+		return pg.ErrNotFound.With("query plan")
+	}
+
+	// Set the plan
+	e.Plan = result[0].Plan
+	e.PlanningTime = result[0].PlanningTime
+	e.ExecutionTime = result[0].ExecutionTime
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	ExplainDef   = `explain ("plan" JSON)`
+	explainQuery = `${readonly}EXPLAIN (FORMAT JSON, ANALYZE ${analyze}, BUFFERS ${buffers}, VERBOSE ${verbose}) ${query}`
+)
@@ -0,0 +1,61 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_ExplainRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Get", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.ExplainRequest{Query: "SELECT 1;", ExplainOptions: schema.ExplainOptions{Analyze: true}}.Select(bind, pg.Get)
+		assert.NoError(err)
+		assert.Equal("EXPLAIN (FORMAT JSON, ANALYZE true, BUFFERS false, VERBOSE false) SELECT 1", bind.Replace(sql))
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		bind := pg.NewBind("readonly", "START TRANSACTION READ ONLY; ")
+		sql, err := schema.ExplainRequest{Query: "SELECT 1"}.Select(bind, pg.Get)
+		assert.NoError(err)
+		assert.Equal("START TRANSACTION READ ONLY; EXPLAIN (FORMAT JSON, ANALYZE false, BUFFERS false, VERBOSE false) SELECT 1", bind.Replace(sql))
+	})
+
+	t.Run("MissingQuery", func(t *testing.T) {
+		_, err := schema.ExplainRequest{Query: " ; "}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("MultipleStatements", func(t *testing.T) {
+		_, err := schema.ExplainRequest{Query: "SELECT 1; DROP TABLE test"}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("SemicolonInLiteral", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.ExplainRequest{Query: "SELECT ';', $$;$$ -- ;"}.Select(bind, pg.Get)
+		assert.NoError(err)
+		assert.Equal("EXPLAIN (FORMAT JSON, ANALYZE false, BUFFERS false, VERBOSE false) SELECT ';', $$;$$", bind.Replace(sql))
+	})
+
+	t.Run("MultipleStatementsAfterIdentifier", func(t *testing.T) {
+		_, err := schema.ExplainRequest{Query: "SELECT 1 AS a$$; DROP TABLE test; SELECT 1 AS b$$"}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.ExplainRequest{Query: "SELECT 1"}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_Explain_String(t *testing.T) {
+	assert := assert.New(t)
+	explain := schema.Explain{Query: "SELECT 1", Plan: schema.ExplainPlan{NodeType: "Result"}}
+	assert.Contains(explain.String(), `"Node Type": "Result"`)
+}
@@ -6,7 +6,7 @@ import (
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
-	types "github.com/mutablelogic/go-pg/pkg/types"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -30,6 +30,8 @@ type Extension struct {
 	Relocatable      *bool    `json:"relocatable,omitempty"`
 	Comment          string   `json:"comment,omitempty"`
 	Requires         []string `json:"requires,omitempty"`
+	Versions         []string `json:"versions,omitempty"`     // Versions which are available
+	UpdatePaths      []string `json:"update_paths,omitempty"` // Paths from the installed version, such as 1.0--1.1--1.2
 }
 
 type ExtensionListRequest struct {
@@ -78,6 +80,21 @@ func (e ExtensionListRequest) String() string {
 	return string(data)
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// UpdateVersions returns the versions which the installed extension can be
+// updated to, which are the last version of each update path
+func (e Extension) UpdateVersions() []string {
+	versions := make([]string, 0, len(e.UpdatePaths))
+	for _, path := range e.UpdatePaths {
+		if i := strings.LastIndex(path, "--"); i >= 0 {
+			versions = append(versions, path[i+2:])
+		}
+	}
+	return versions
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // SELECT
 
@@ -87,18 +104,17 @@ func (e ExtensionListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
 	}
 
 	// Filter by installed status
+	where := pg.Where().OrderBy(`name`, true)
 	if e.Installed != nil {
 		if *e.Installed {
-			bind.Set("where", "WHERE installed_version IS NOT NULL")
+			where.Expr(`installed_version IS NOT NULL`)
 		} else {
-			bind.Set("where", "WHERE installed_version IS NULL")
+			where.Expr(`installed_version IS NULL`)
 		}
-	} else {
-		bind.Set("where", "")
 	}
+	where.Bind(bind)
 
 	e.OffsetLimit.Bind(bind, ExtensionListLimit)
-	bind.Set("orderby", "ORDER BY name ASC")
 	return queryExtensionList, nil
 }
 
@@ -148,14 +164,14 @@ func (e ExtensionMeta) Insert(bind *pg.Bind) (string, error) {
 
 	// Optional schema
 	if schema := strings.TrimSpace(e.Schema); schema != "" {
-		bind.Set("with", "WITH SCHEMA "+types.DoubleQuote(schema))
+		bind.Set("with", "WITH SCHEMA "+quote.Ident(schema))
 	} else {
 		bind.Set("with", "")
 	}
 
 	// Optional version
 	if version := strings.TrimSpace(e.Version); version != "" {
-		bind.Set("version", "VERSION "+types.Quote(version))
+		bind.Set("version", "VERSION "+quote.Literal(version))
 	} else {
 		bind.Set("version", "")
 	}
@@ -188,7 +204,7 @@ func (e ExtensionMeta) Update(bind *pg.Bind) error {
 
 	// Version to update to
 	if version := strings.TrimSpace(e.Version); version != "" {
-		bind.Set("version", "TO "+types.Quote(version))
+		bind.Set("version", "TO "+quote.Literal(version))
 	} else {
 		bind.Set("version", "")
 	}
@@ -209,7 +225,7 @@ func (e ExtensionMeta) UpdateQuery(bind *pg.Bind) (string, error) {
 
 func (e *Extension) Scan(row pg.Row) error {
 	return row.Scan(&e.Oid, &e.Name, &e.Owner, &e.Schema,
-		&e.DefaultVersion, &e.InstalledVersion, &e.Relocatable, &e.Requires, &e.Comment)
+		&e.DefaultVersion, &e.InstalledVersion, &e.Relocatable, &e.Requires, &e.Comment, &e.Versions, &e.UpdatePaths)
 }
 
 func (e *ExtensionList) Scan(row pg.Row) error {
@@ -229,7 +245,7 @@ func (e *ExtensionList) ScanCount(row pg.Row) error {
 // SQL
 
 const (
-	ExtensionDef = `extension ("oid" OID, "name" TEXT, "owner" TEXT, "schema" TEXT, "default_version" TEXT, "installed_version" TEXT, "relocatable" BOOLEAN, "requires" TEXT[], "comment" TEXT)`
+	ExtensionDef = `extension ("oid" OID, "name" TEXT, "owner" TEXT, "schema" TEXT, "default_version" TEXT, "installed_version" TEXT, "relocatable" BOOLEAN, "requires" TEXT[], "comment" TEXT, "versions" TEXT[], "update_paths" TEXT[])`
 
 	queryExtensionSelect = `
 		WITH e AS (
@@ -247,7 +263,14 @@ const (
 						WHERE d.objid = E.oid AND d.deptype = 'e'),
 					ARRAY[]::text[]
 				) AS "requires",
-				COALESCE(A.comment, '') AS "comment"
+				COALESCE(A.comment, '') AS "comment",
+				ARRAY(
+					SELECT V.version FROM ${"schema"}.pg_available_extension_versions V WHERE V.name = A.name ORDER BY V.version
+				) AS "versions",
+				ARRAY(
+					SELECT U.path FROM ${"schema"}.pg_extension_update_paths(A.name) U
+					WHERE E.oid IS NOT NULL AND U.source = A.installed_version AND U.path IS NOT NULL ORDER BY U.target
+				) AS "update_paths"
 			FROM
 				${"schema"}."pg_available_extensions" A
 			LEFT JOIN
@@ -258,7 +281,7 @@ const (
 				${"schema"}."pg_namespace" N ON E.extnamespace = N.oid
 		) SELECT * FROM e
 	`
-	queryExtensionGet  = queryExtensionSelect + ` WHERE "name" = ${'name'}`
+	queryExtensionGet  = queryExtensionSelect + ` WHERE "name" = @name`
 	queryExtensionList = `WITH q AS (` + queryExtensionSelect + `) SELECT * FROM q ${where} ${orderby}`
 
 	queryExtensionCreate    = `CREATE EXTENSION IF NOT EXISTS ${"name"} ${with} ${version} ${cascade}`
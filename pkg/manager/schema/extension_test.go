@@ -79,6 +79,21 @@ func Test_Extension_String(t *testing.T) {
 	})
 }
 
+func Test_Extension_UpdateVersions(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("NotInstalled", func(t *testing.T) {
+		assert.Empty(schema.Extension{}.UpdateVersions())
+	})
+
+	t.Run("Paths", func(t *testing.T) {
+		ext := schema.Extension{
+			UpdatePaths: []string{"1.0--1.1", "1.0--1.1--1.2"},
+		}
+		assert.Equal([]string{"1.1", "1.2"}, ext.UpdateVersions())
+	})
+}
+
 func Test_ExtensionMeta_String(t *testing.T) {
 	assert := assert.New(t)
 
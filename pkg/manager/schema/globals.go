@@ -14,6 +14,18 @@ const (
 	CatalogSchema  = "pg_catalog"
 	APIPrefix      = "/pg/v1"
 	DefaultAclRole = "PUBLIC"
+
+	// Schema for tables which record the state of the manager
+	ManagerSchema = "pgmanager"
+)
+
+const (
+	// APIVersion is the latest version of the REST API, which the client requests
+	APIVersion = 1
+
+	// APIVersionHeader is the request header which selects the version of the
+	// REST API, and the response header which reports the version served
+	APIVersionHeader = "Api-Version"
 )
 
 const (
@@ -28,6 +40,22 @@ const (
 	SettingListLimit         = 500
 	StatementListLimit       = 100
 	ReplicationSlotListLimit = 100
+	CronJobListLimit         = 100
+	CronJobRunListLimit      = 100
+	StaleTableListLimit      = 100
+	SettingHistoryListLimit  = 100
+	AuditListLimit           = 100
+	AlertRuleListLimit       = 100
+	BloatListLimit           = 100
+	IOStatListLimit          = 100
+	TempUsageListLimit       = 100
+	ConfigFileListLimit      = 500
+
+	// Maximum number of rows returned by an ad-hoc query
+	QueryRowLimit = 1000
+
+	// Maximum statement timeout for an ad-hoc query, in milliseconds
+	QueryTimeout = 30000
 )
 
 const (
@@ -37,6 +65,11 @@ const (
 	reservedPrefix       = "pg_"
 )
 
+const (
+	// CronExtension is the name of the extension which schedules jobs
+	CronExtension = "pg_cron"
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // BOOTSTRAP
 
@@ -48,6 +81,7 @@ type BootstrapResult struct {
 
 // Bootstrap creates required extensions for the manager.
 // - dblink: Required for remote database queries
+// - pgmanager schema: Required for recording setting changes, the audit log and alert rules
 // - pg_stat_statements: Optional, for query statistics (requires shared_preload_libraries)
 // This should be called once when initializing the manager.
 func Bootstrap(ctx context.Context, conn pg.PoolConn) (*BootstrapResult, error) {
@@ -58,6 +92,23 @@ func Bootstrap(ctx context.Context, conn pg.PoolConn) (*BootstrapResult, error)
 		return nil, err
 	}
 
+	// Create the schema and tables which record the state of the manager
+	if err := conn.Exec(ctx, managerCreateSchema); err != nil {
+		return nil, err
+	}
+	if err := conn.Exec(ctx, settingHistoryCreateTable); err != nil {
+		return nil, err
+	}
+	if err := conn.Exec(ctx, auditCreateTable); err != nil {
+		return nil, err
+	}
+	if err := conn.Exec(ctx, auditCreateIndex); err != nil {
+		return nil, err
+	}
+	if err := conn.Exec(ctx, alertRuleCreateTable); err != nil {
+		return nil, err
+	}
+
 	// Try to create and verify pg_stat_statements extension (optional)
 	// Creating the extension can succeed but querying fails if not in shared_preload_libraries
 	if err := conn.Exec(ctx, statStatementsCreateExtension); err == nil {
@@ -72,6 +123,7 @@ func Bootstrap(ctx context.Context, conn pg.PoolConn) (*BootstrapResult, error)
 
 const (
 	dblinkCreateExtension         = `CREATE EXTENSION IF NOT EXISTS dblink WITH SCHEMA ` + defaultSchema
+	managerCreateSchema           = `CREATE SCHEMA IF NOT EXISTS ` + ManagerSchema
 	statStatementsCreateExtension = `CREATE EXTENSION IF NOT EXISTS pg_stat_statements WITH SCHEMA ` + defaultSchema
 	statStatementsVerify          = `SELECT 1 FROM public.pg_stat_statements LIMIT 1`
 )
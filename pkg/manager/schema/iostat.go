@@ -0,0 +1,173 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// IOStat is the number of blocks of a table or index which were read from
+// disk, and the number found in the buffer cache, since the statistics were
+// reset. A table with a low index hit ratio, or many heap blocks read, may
+// be missing an index.
+type IOStat struct {
+	Database  string   `json:"database"`
+	Schema    string   `json:"schema"`
+	Table     string   `json:"table"`
+	Index     string   `json:"index,omitempty"`
+	Type      string   `json:"type"`
+	HeapRead  uint64   `json:"heap_blks_read,omitempty"`
+	HeapHit   uint64   `json:"heap_blks_hit,omitempty"`
+	HeapRatio *float64 `json:"heap_hit_ratio,omitempty"` // Ratio of heap blocks found in the cache (0.0-1.0), or nil if none were accessed
+	IdxRead   uint64   `json:"idx_blks_read"`
+	IdxHit    uint64   `json:"idx_blks_hit"`
+	IdxRatio  *float64 `json:"idx_hit_ratio,omitempty"` // Ratio of index blocks found in the cache (0.0-1.0), or nil if none were accessed
+}
+
+type IOStatListRequest struct {
+	Database *string `json:"database,omitempty" help:"Database"`
+	Schema   *string `json:"schema,omitempty" help:"Schema"`
+	Type     *string `json:"type,omitempty" help:"Type (TABLE or INDEX)"`
+	pg.OffsetLimit
+}
+
+type IOStatList struct {
+	Count uint64   `json:"count"`
+	Body  []IOStat `json:"body,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	IOStatTypeTable = "TABLE"
+	IOStatTypeIndex = "INDEX"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s IOStat) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (s IOStatList) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (s IOStatListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Where
+	where := pg.Where()
+	if s.Schema != nil {
+		if schema := strings.TrimSpace(*s.Schema); schema != "" {
+			where.Eq(`schema`, schema)
+		}
+	}
+	if s.Type != nil {
+		switch t := strings.ToUpper(strings.TrimSpace(*s.Type)); t {
+		case "":
+			// No filter
+		case IOStatTypeTable, IOStatTypeIndex:
+			where.Eq(`type`, t)
+		default:
+			return "", pg.ErrBadParameter.Withf("invalid type %q", *s.Type)
+		}
+	}
+	where.Bind(bind)
+
+	// Bind offset and limit
+	s.OffsetLimit.Bind(bind, IOStatListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return ioStatList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported IOStatListRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (s *IOStat) Scan(row pg.Row) error {
+	var index *string
+	if err := row.Scan(&s.Database, &s.Schema, &s.Table, &index, &s.Type, &s.HeapRead, &s.HeapHit, &s.HeapRatio, &s.IdxRead, &s.IdxHit, &s.IdxRatio); err != nil {
+		return err
+	}
+	if index != nil {
+		s.Index = *index
+	} else {
+		s.Index = ""
+	}
+	return nil
+}
+
+func (s *IOStatList) Scan(row pg.Row) error {
+	var stat IOStat
+	if err := stat.Scan(row); err != nil {
+		return err
+	} else {
+		s.Body = append(s.Body, stat)
+	}
+	return nil
+}
+
+func (s *IOStatList) ScanCount(row pg.Row) error {
+	return row.Scan(&s.Count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	IOStatDef    = `iostat ("database" TEXT, "schema" TEXT, "table" TEXT, "index" TEXT, "type" TEXT, "heap_read" BIGINT, "heap_hit" BIGINT, "heap_ratio" FLOAT8, "idx_read" BIGINT, "idx_hit" BIGINT, "idx_ratio" FLOAT8)`
+	ioStatSelect = `
+		SELECT
+			current_database()::TEXT AS "database",
+			schemaname::TEXT AS "schema",
+			relname::TEXT AS "table",
+			NULL::TEXT AS "index",
+			'TABLE' AS "type",
+			COALESCE(heap_blks_read, 0) AS "heap_read",
+			COALESCE(heap_blks_hit, 0) AS "heap_hit",
+			CASE WHEN heap_blks_read + heap_blks_hit > 0 THEN heap_blks_hit::FLOAT8 / (heap_blks_read + heap_blks_hit) END AS "heap_ratio",
+			COALESCE(idx_blks_read, 0) AS "idx_read",
+			COALESCE(idx_blks_hit, 0) AS "idx_hit",
+			CASE WHEN idx_blks_read + idx_blks_hit > 0 THEN idx_blks_hit::FLOAT8 / (idx_blks_read + idx_blks_hit) END AS "idx_ratio"
+		FROM
+			pg_statio_user_tables
+		UNION ALL
+		SELECT
+			current_database()::TEXT AS "database",
+			schemaname::TEXT AS "schema",
+			relname::TEXT AS "table",
+			indexrelname::TEXT AS "index",
+			'INDEX' AS "type",
+			0 AS "heap_read",
+			0 AS "heap_hit",
+			NULL AS "heap_ratio",
+			COALESCE(idx_blks_read, 0) AS "idx_read",
+			COALESCE(idx_blks_hit, 0) AS "idx_hit",
+			CASE WHEN idx_blks_read + idx_blks_hit > 0 THEN idx_blks_hit::FLOAT8 / (idx_blks_read + idx_blks_hit) END AS "idx_ratio"
+		FROM
+			pg_statio_user_indexes
+	`
+	ioStatList = `WITH q AS (` + ioStatSelect + `) SELECT * FROM q ${where} ORDER BY "heap_read" + "idx_read" DESC, "schema", "table", "index" NULLS FIRST`
+)
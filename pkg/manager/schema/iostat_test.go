@@ -0,0 +1,67 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_IOStatListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.IOStatListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_statio_user_tables")
+		assert.Contains(sql, "pg_statio_user_indexes")
+		assert.Equal("", bind.Get("where"))
+	})
+
+	t.Run("ListWithFilters", func(t *testing.T) {
+		bind := pg.NewBind()
+		namespace, typ := "public", "table"
+		_, err := schema.IOStatListRequest{Schema: &namespace, Type: &typ}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal(`WHERE schema = @schema AND type = @type`, bind.Get("where"))
+		assert.Equal("TABLE", bind.Get("type"))
+	})
+
+	t.Run("InvalidType", func(t *testing.T) {
+		typ := "view"
+		_, err := schema.IOStatListRequest{Type: &typ}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.IOStatListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_IOStatList_String(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("ProducesJSON", func(t *testing.T) {
+		list := schema.IOStatList{
+			Count: 1,
+			Body: []schema.IOStat{
+				{Database: "testdb", Schema: "public", Table: "users", Type: schema.IOStatTypeTable, HeapRead: 10, HeapHit: 90, HeapRatio: types.Float64Ptr(0.9)},
+			},
+		}
+		var parsed map[string]any
+		err := json.Unmarshal([]byte(list.String()), &parsed)
+		assert.NoError(err)
+		assert.Equal(float64(1), parsed["count"])
+
+		body := parsed["body"].([]any)[0].(map[string]any)
+		assert.Equal("users", body["table"])
+		assert.Equal(0.9, body["heap_hit_ratio"])
+		assert.NotContains(body, "idx_hit_ratio")
+	})
+}
@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// KeysetPage paginates a list from the key of the last item of the previous
+// page, which unlike an offset does not degrade on large lists. The token is
+// returned as "next" in a list response, and the page size is set by the limit.
+// When a token is set, the count is the number of items after the key.
+type KeysetPage struct {
+	After string `json:"after,omitempty" help:"Token for the next page, from a previous response"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Key decodes the token into the destination values, and returns false if
+// there is no token
+func (k KeysetPage) Key(dest ...any) (bool, error) {
+	if k.After == "" {
+		return false, nil
+	}
+
+	// Decode the token
+	var values []json.RawMessage
+	if data, err := base64.RawURLEncoding.DecodeString(k.After); err != nil {
+		return false, pg.ErrBadParameter.With("invalid after token")
+	} else if err := json.Unmarshal(data, &values); err != nil || len(values) != len(dest) {
+		return false, pg.ErrBadParameter.With("invalid after token")
+	}
+
+	// Decode the values
+	for i, value := range values {
+		if err := json.Unmarshal(value, dest[i]); err != nil {
+			return false, pg.ErrBadParameter.With("invalid after token")
+		}
+	}
+
+	// Return success
+	return true, nil
+}
+
+// Next returns the token for the page after an item with the given key, or
+// an empty string if there are no more items after the offset and the number
+// of items returned
+func (k KeysetPage) Next(offset, n, count uint64, key ...any) string {
+	if n == 0 || offset+n >= count {
+		return ""
+	}
+	data, err := json.Marshal(key)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,89 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_KeysetPage_Next(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("NoMoreItems", func(t *testing.T) {
+		assert.Equal("", schema.KeysetPage{}.Next(0, 10, 10, "a"))
+		assert.Equal("", schema.KeysetPage{}.Next(0, 0, 10, "a"))
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		token := schema.KeysetPage{}.Next(0, 10, 20, "db", "public", "table")
+		assert.NotEmpty(token)
+
+		var database, namespace, name string
+		ok, err := schema.KeysetPage{After: token}.Key(&database, &namespace, &name)
+		assert.NoError(err)
+		assert.True(ok)
+		assert.Equal("db", database)
+		assert.Equal("public", namespace)
+		assert.Equal("table", name)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		var pid uint32
+		ok, err := schema.KeysetPage{}.Key(&pid)
+		assert.NoError(err)
+		assert.False(ok)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		var pid uint32
+		_, err := schema.KeysetPage{After: "!!"}.Key(&pid)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+
+		// Wrong number of values
+		token := schema.KeysetPage{}.Next(0, 1, 2, 1, 2)
+		_, err = schema.KeysetPage{After: token}.Key(&pid)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
+func Test_KeysetPage_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Connection", func(t *testing.T) {
+		bind := pg.NewBind()
+		token := schema.KeysetPage{}.Next(0, 1, 2, 1234)
+		_, err := schema.ConnectionListRequest{KeysetPage: schema.KeysetPage{After: token}}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(bind.Get("where"), `"pid" > `)
+		assert.Equal(uint32(1234), bind.Get("after_pid"))
+	})
+
+	t.Run("Object", func(t *testing.T) {
+		bind := pg.NewBind()
+		token := schema.KeysetPage{}.Next(0, 1, 2, "db", "public", "table")
+		_, err := schema.ObjectListRequest{KeysetPage: schema.KeysetPage{After: token}}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(bind.Get("where"), `(database, schema, name) > (@after_database, @after_schema, @after_name)`)
+		assert.Equal("db", bind.Get("after_database"))
+		assert.Equal("public", bind.Get("after_schema"))
+		assert.Equal("table", bind.Get("after_name"))
+	})
+
+	t.Run("Statement", func(t *testing.T) {
+		bind := pg.NewBind()
+		token := schema.KeysetPage{}.Next(0, 1, 2, "db", 42, "role")
+		_, err := schema.StatementListRequest{KeysetPage: schema.KeysetPage{After: token}}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(bind.Get("where"), `(database, queryid, role) >`)
+		assert.Equal(int64(42), bind.Get("after_queryid"))
+	})
+
+	t.Run("StatementWithSort", func(t *testing.T) {
+		token := schema.KeysetPage{}.Next(0, 1, 2, "db", 42, "role")
+		_, err := schema.StatementListRequest{Sort: "calls", KeysetPage: schema.KeysetPage{After: token}}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
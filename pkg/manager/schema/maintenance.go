@@ -0,0 +1,271 @@
+package schema
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// MaintenanceWindow is a time of day when heavy operations, such as reindexing
+// and base backups, can run. Times are in UTC, and the end can be after
+// midnight, in which case the window ends on the following day
+type MaintenanceWindow struct {
+	Days  []string `json:"days,omitempty"` // Days the window opens, such as "sat", or every day when empty
+	Start string   `json:"start"`          // Time the window opens, such as "01:00"
+	End   string   `json:"end"`            // Time the window closes, such as "05:00"
+}
+
+// MaintenanceWindows are the times when heavy operations can run. When there
+// are no windows, heavy operations can run at any time
+type MaintenanceWindows []MaintenanceWindow
+
+// MaintenanceOperation is a heavy operation which can be queued until a
+// maintenance window opens
+type MaintenanceOperation string
+
+// MaintenanceState is the state of a queued operation
+type MaintenanceState string
+
+// MaintenanceTaskMeta is a heavy operation to queue until a maintenance window
+// opens. The database, schema, name and concurrently fields are used for a
+// reindex, and the label and fast fields for a base backup
+type MaintenanceTaskMeta struct {
+	Operation    MaintenanceOperation `json:"operation" arg:"" help:"Operation (reindex, backup)"`
+	Database     string               `json:"database,omitempty" help:"Database of the object to reindex"`
+	Schema       string               `json:"schema,omitempty" help:"Schema of the object to reindex"`
+	Name         string               `json:"name,omitempty" help:"Object to reindex"`
+	Concurrently bool                 `json:"concurrently,omitempty" help:"Reindex without locking out writes"`
+	Label        string               `json:"label,omitempty" help:"Backup label"`
+	Fast         bool                 `json:"fast,omitempty" help:"Request an immediate checkpoint for the backup"`
+}
+
+// MaintenanceTask is a heavy operation which is queued, running or finished
+type MaintenanceTask struct {
+	Id uint64 `json:"id"`
+	MaintenanceTaskMeta
+	State    MaintenanceState `json:"state"`
+	Queued   time.Time        `json:"queued"`
+	Started  *time.Time       `json:"started,omitempty"`
+	Finished *time.Time       `json:"finished,omitempty"`
+	Error    *string          `json:"error,omitempty"`
+}
+
+// Maintenance is the schedule of maintenance windows and the heavy
+// operations which are queued or have recently run
+type Maintenance struct {
+	Windows MaintenanceWindows `json:"windows"`
+	Open    bool               `json:"open"`           // True when heavy operations can run now
+	Next    *time.Time         `json:"next,omitempty"` // When the next window opens, if not open now
+	Tasks   []MaintenanceTask  `json:"tasks,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	MaintenanceReindex MaintenanceOperation = "reindex"
+	MaintenanceBackup  MaintenanceOperation = "backup"
+)
+
+const (
+	MaintenanceQueued  MaintenanceState = "queued"
+	MaintenanceRunning MaintenanceState = "running"
+	MaintenanceDone    MaintenanceState = "done"
+	MaintenanceFailed  MaintenanceState = "failed"
+)
+
+var (
+	maintenanceDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// ParseMaintenanceWindow returns a maintenance window from a string such as
+// "01:00-05:00" for every day, or "sat,sun 01:00-05:00" for some days
+func ParseMaintenanceWindow(v string) (MaintenanceWindow, error) {
+	var window MaintenanceWindow
+	fields := strings.Fields(v)
+	switch len(fields) {
+	case 1:
+		// Every day
+	case 2:
+		for _, day := range strings.Split(fields[0], ",") {
+			window.Days = append(window.Days, strings.ToLower(strings.TrimSpace(day)))
+		}
+		fields = fields[1:]
+	default:
+		return window, pg.ErrBadParameter.Withf("invalid maintenance window %q", v)
+	}
+	if start, end, ok := strings.Cut(fields[0], "-"); !ok {
+		return window, pg.ErrBadParameter.Withf("invalid maintenance window %q", v)
+	} else {
+		window.Start, window.End = start, end
+	}
+	return window, window.Validate()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (w MaintenanceWindow) String() string {
+	if len(w.Days) == 0 {
+		return w.Start + "-" + w.End
+	}
+	return strings.Join(w.Days, ",") + " " + w.Start + "-" + w.End
+}
+
+func (t MaintenanceTask) String() string {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (m Maintenance) String() string {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// VALIDATION
+
+// Validate returns an error if the days or times of the window are invalid
+func (w MaintenanceWindow) Validate() error {
+	for _, day := range w.Days {
+		if !slices.Contains(maintenanceDays, day) {
+			return pg.ErrBadParameter.Withf("invalid day %q", day)
+		}
+	}
+	start, err := parseTimeOfDay(w.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseTimeOfDay(w.End)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return pg.ErrBadParameter.Withf("maintenance window %q has no duration", w)
+	}
+	return nil
+}
+
+// Validate returns an error if the operation or its parameters are invalid
+func (m MaintenanceTaskMeta) Validate() error {
+	switch m.Operation {
+	case MaintenanceReindex:
+		if m.Database == "" || m.Schema == "" || m.Name == "" {
+			return pg.ErrBadParameter.With("database, schema and name are required for reindex")
+		}
+	case MaintenanceBackup:
+		if strings.TrimSpace(m.Label) == "" {
+			return pg.ErrBadParameter.With("label is required for backup")
+		}
+	default:
+		return pg.ErrBadParameter.Withf("invalid operation %q", m.Operation)
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Open returns true if there are no windows, or a window is open at a time
+func (w MaintenanceWindows) Open(t time.Time) bool {
+	if len(w) == 0 {
+		return true
+	}
+	for _, window := range w {
+		if window.open(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the time the next window opens after a time, or the time
+// itself if a window is open
+func (w MaintenanceWindows) Next(t time.Time) time.Time {
+	if w.Open(t) {
+		return t
+	}
+	var next time.Time
+	for _, window := range w {
+		if start := window.next(t); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return next
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return true if the window is open at a time, including a window which
+// opened the previous day and ends after midnight
+func (w MaintenanceWindow) open(t time.Time) bool {
+	start, end := w.times()
+	t = t.UTC()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	for _, day := range []time.Time{midnight.AddDate(0, 0, -1), midnight} {
+		if !w.on(day.Weekday()) {
+			continue
+		}
+		opens := day.Add(start)
+		closes := day.Add(end)
+		if end <= start {
+			closes = closes.AddDate(0, 0, 1)
+		}
+		if !t.Before(opens) && t.Before(closes) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the time the window next opens after a time, or zero
+func (w MaintenanceWindow) next(t time.Time) time.Time {
+	start, _ := w.times()
+	t = t.UTC()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= len(maintenanceDays); i++ {
+		day := midnight.AddDate(0, 0, i)
+		if opens := day.Add(start); w.on(day.Weekday()) && opens.After(t) {
+			return opens
+		}
+	}
+	return time.Time{}
+}
+
+// Return true if the window opens on a day of the week
+func (w MaintenanceWindow) on(day time.Weekday) bool {
+	return len(w.Days) == 0 || slices.Contains(w.Days, maintenanceDays[day])
+}
+
+// Return the start and end of the window as durations after midnight
+func (w MaintenanceWindow) times() (time.Duration, time.Duration) {
+	start, _ := parseTimeOfDay(w.Start)
+	end, _ := parseTimeOfDay(w.End)
+	return start, end
+}
+
+// Parse a time of day such as "01:00", returning the duration after midnight
+func parseTimeOfDay(v string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(v))
+	if err != nil {
+		return 0, pg.ErrBadParameter.Withf("invalid time of day %q", v)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,102 @@
+package schema_test
+
+import (
+	"testing"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_ParseMaintenanceWindow(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		In   string
+		Days []string
+		Err  bool
+	}{
+		{"01:00-05:00", nil, false},
+		{"Sat,sun 23:00-02:00", []string{"sat", "sun"}, false},
+		{"mon 01:00", nil, true},
+		{"funday 01:00-02:00", nil, true},
+		{"01:00-01:00", nil, true},
+		{"25:00-01:00", nil, true},
+		{"mon tue 01:00-02:00", nil, true},
+	}
+	for _, test := range tests {
+		window, err := schema.ParseMaintenanceWindow(test.In)
+		if test.Err {
+			assert.ErrorIs(err, pg.ErrBadParameter, test.In)
+		} else if assert.NoError(err, test.In) {
+			assert.Equal(test.Days, window.Days)
+		}
+	}
+}
+
+func Test_MaintenanceWindows_Open(t *testing.T) {
+	assert := assert.New(t)
+
+	// Saturday 4th January 2025
+	saturday := time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	t.Run("NoWindows", func(t *testing.T) {
+		var windows schema.MaintenanceWindows
+		assert.True(windows.Open(saturday))
+		assert.Equal(saturday, windows.Next(saturday))
+	})
+
+	t.Run("EveryDay", func(t *testing.T) {
+		window, err := schema.ParseMaintenanceWindow("01:00-05:00")
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		windows := schema.MaintenanceWindows{window}
+		assert.False(windows.Open(saturday))
+		assert.True(windows.Open(saturday.Add(time.Hour)))
+		assert.False(windows.Open(saturday.Add(5 * time.Hour)))
+		assert.Equal(saturday.Add(time.Hour), windows.Next(saturday))
+		assert.Equal(saturday.Add(25*time.Hour), windows.Next(saturday.Add(6*time.Hour)))
+	})
+
+	t.Run("AfterMidnight", func(t *testing.T) {
+		window, err := schema.ParseMaintenanceWindow("fri 23:00-02:00")
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		windows := schema.MaintenanceWindows{window}
+
+		// Opened on Friday, closes on Saturday
+		assert.True(windows.Open(saturday.Add(time.Hour)))
+		assert.False(windows.Open(saturday.Add(2 * time.Hour)))
+		assert.True(windows.Open(saturday.Add(-30 * time.Minute)))
+
+		// Next opens on the following Friday
+		assert.Equal(saturday.AddDate(0, 0, 6).Add(23*time.Hour), windows.Next(saturday.Add(3*time.Hour)))
+	})
+
+	t.Run("Earliest", func(t *testing.T) {
+		sun, err := schema.ParseMaintenanceWindow("sun 01:00-02:00")
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		sat, err := schema.ParseMaintenanceWindow("sat 03:00-04:00")
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		windows := schema.MaintenanceWindows{sun, sat}
+		assert.Equal(saturday.Add(3*time.Hour), windows.Next(saturday))
+	})
+}
+
+func Test_MaintenanceTaskMeta_Validate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(schema.MaintenanceTaskMeta{Operation: schema.MaintenanceReindex, Database: "db", Schema: "public", Name: "t"}.Validate())
+	assert.NoError(schema.MaintenanceTaskMeta{Operation: schema.MaintenanceBackup, Label: "nightly"}.Validate())
+	assert.ErrorIs(schema.MaintenanceTaskMeta{Operation: schema.MaintenanceReindex, Database: "db"}.Validate(), pg.ErrBadParameter)
+	assert.ErrorIs(schema.MaintenanceTaskMeta{Operation: schema.MaintenanceBackup}.Validate(), pg.ErrBadParameter)
+	assert.ErrorIs(schema.MaintenanceTaskMeta{Operation: "vacuum"}.Validate(), pg.ErrBadParameter)
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Notification is a message sent to a channel with NOTIFY, which is relayed
+// to clients which subscribe to the channel. When the subscription fails,
+// a final message with the error is sent.
+type Notification struct {
+	Channel string `json:"channel,omitempty"`
+	Payload string `json:"payload,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum length of a channel name, which is an identifier
+	notifyChannelMaxLength = 63
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (n Notification) String() string {
+	data, err := json.MarshalIndent(n, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// VALIDATION
+
+// ValidateChannel returns an error if a channel name is empty or too long
+func ValidateChannel(channel string) error {
+	if strings.TrimSpace(channel) == "" {
+		return pg.ErrBadParameter.With("channel is missing")
+	} else if len(channel) > notifyChannelMaxLength {
+		return pg.ErrBadParameter.Withf("channel %q is longer than %d bytes", channel, notifyChannelMaxLength)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package schema_test
+
+import (
+	"strings"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateChannel(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(schema.ValidateChannel("events"))
+	assert.NoError(schema.ValidateChannel("Mixed Case"))
+	assert.ErrorIs(schema.ValidateChannel(""), pg.ErrBadParameter)
+	assert.ErrorIs(schema.ValidateChannel("  "), pg.ErrBadParameter)
+	assert.ErrorIs(schema.ValidateChannel(strings.Repeat("x", 64)), pg.ErrBadParameter)
+}
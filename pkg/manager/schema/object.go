@@ -1,12 +1,13 @@
 package schema
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"strings"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
-	types "github.com/mutablelogic/go-pg/pkg/types"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -18,9 +19,10 @@ type ObjectName struct {
 }
 
 type ObjectMeta struct {
-	Name  string  `json:"name,omitempty" arg:"" help:"Name"`
-	Owner string  `json:"owner,omitempty" help:"Owner"`
-	Acl   ACLList `json:"acl,omitempty" help:"Access privileges"`
+	Name    string  `json:"name,omitempty" arg:"" help:"Name"`
+	Owner   string  `json:"owner,omitempty" help:"Owner"`
+	Acl     ACLList `json:"acl,omitempty" help:"Access privileges"`
+	Comment *string `json:"comment,omitempty" help:"Comment"`
 }
 
 // TableMeta contains metadata specific to tables
@@ -29,15 +31,29 @@ type TableMeta struct {
 	DeadTuples *int64 `json:"dead_tuples,omitempty" help:"Number of dead tuples"`
 }
 
+// IndexMeta contains metadata specific to indexes
+type IndexMeta struct {
+	Method  string            `json:"method" help:"Access method (btree, hash, gin, hnsw, ivfflat, etc.)"`
+	Options map[string]string `json:"options,omitempty" help:"Build parameters, such as m and ef_construction for hnsw or lists for ivfflat"`
+}
+
+// VectorColumn is a pgvector column of a table or index
+type VectorColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // Type with dimensions, such as vector(1536)
+}
+
 type Object struct {
 	Oid      uint32 `json:"oid"`
 	Database string `json:"database,omitempty" help:"Database"`
 	Schema   string `json:"schema,omitempty" help:"Schema"`
 	Type     string `json:"type,omitempty" help:"Type"`
 	ObjectMeta
-	Tablespace *string    `json:"tablespace,omitempty" help:"Tablespace"`
-	Size       uint64     `json:"bytes,omitempty" help:"Size of object in bytes"`
-	Table      *TableMeta `json:"table,omitempty" help:"Table-specific metadata"`
+	Tablespace *string        `json:"tablespace,omitempty" help:"Tablespace"`
+	Size       uint64         `json:"bytes,omitempty" help:"Size of object in bytes"`
+	Table      *TableMeta     `json:"table,omitempty" help:"Table-specific metadata"`
+	Index      *IndexMeta     `json:"index,omitempty" help:"Index-specific metadata"`
+	Vectors    []VectorColumn `json:"vectors,omitempty" help:"pgvector columns"`
 }
 
 type ObjectListRequest struct {
@@ -45,11 +61,19 @@ type ObjectListRequest struct {
 	Schema   *string `json:"schema,omitempty" help:"Schema"`
 	Type     *string `json:"type,omitempty" help:"Object Type"`
 	pg.OffsetLimit
+	KeysetPage
 }
 
 type ObjectList struct {
 	Count uint64   `json:"count"`
 	Body  []Object `json:"body,omitempty"`
+	Next  string   `json:"next,omitempty"`
+}
+
+// ObjectRows is a batch of the rows of an object, each as a JSON object on
+// its own line
+type ObjectRows struct {
+	bytes.Buffer
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -71,6 +95,14 @@ func (t TableMeta) String() string {
 	return string(data)
 }
 
+func (i IndexMeta) String() string {
+	data, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
 func (o Object) String() string {
 	data, err := json.MarshalIndent(o, "", "  ")
 	if err != nil {
@@ -132,30 +164,31 @@ func (o ObjectName) Select(bind *pg.Bind, op pg.Op) (string, error) {
 
 func (o ObjectListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
 	// Order
-	bind.Set("orderby", `ORDER BY schema ASC, name ASC`)
+	where := pg.Where().OrderBy(`schema`, true).OrderBy(`name`, true)
 
 	// Where
-	bind.Del("where")
 	if o.Schema != nil {
 		if schema := strings.TrimSpace(*o.Schema); schema != "" {
-			bind.Append("where", `schema = `+types.Quote(schema))
+			where.Eq(`schema`, schema)
 		}
 	}
 	if o.Database != nil {
 		if database := strings.TrimSpace(*o.Database); database != "" {
-			bind.Append("where", `database = `+types.Quote(database))
+			where.Eq(`database`, database)
 		}
 	}
 	if o.Type != nil {
 		if objectType := strings.TrimSpace(*o.Type); objectType != "" {
-			bind.Append("where", `type = `+types.Quote(objectType))
+			where.Eq(`type`, objectType)
 		}
 	}
-	if where := bind.Join("where", " AND "); where != "" {
-		bind.Set("where", `WHERE `+where)
-	} else {
-		bind.Set("where", "")
+	var database, schema, name string
+	if ok, err := o.Key(&database, &schema, &name); err != nil {
+		return "", err
+	} else if ok {
+		where.After([]string{`database`, `schema`, `name`}, database, schema, name)
 	}
+	where.Bind(bind)
 
 	// Bind offset and limit
 	o.OffsetLimit.Bind(bind, ObjectListLimit)
@@ -175,8 +208,10 @@ func (o ObjectListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
 func (o *Object) Scan(row pg.Row) error {
 	var priv []string
 	var liveTuples, deadTuples *int64
+	var indexMethod *string
+	var indexOptions, vectorNames, vectorTypes []string
 	o.Acl = ACLList{}
-	if err := row.Scan(&o.Oid, &o.Database, &o.Schema, &o.Name, &o.Type, &o.Owner, &priv, &o.Tablespace, &o.Size, &liveTuples, &deadTuples); err != nil {
+	if err := row.Scan(&o.Oid, &o.Database, &o.Schema, &o.Name, &o.Type, &o.Owner, &priv, &o.Tablespace, &o.Size, &liveTuples, &deadTuples, &indexMethod, &indexOptions, &vectorNames, &vectorTypes, &o.Comment); err != nil {
 		return err
 	}
 	for _, v := range priv {
@@ -193,6 +228,26 @@ func (o *Object) Scan(row pg.Row) error {
 			DeadTuples: deadTuples,
 		}
 	}
+	// Only set Index if we have an access method (i.e., it's an index)
+	if indexMethod != nil {
+		o.Index = &IndexMeta{
+			Method: *indexMethod,
+		}
+		for _, option := range indexOptions {
+			if key, value, ok := strings.Cut(option, "="); ok {
+				if o.Index.Options == nil {
+					o.Index.Options = make(map[string]string, len(indexOptions))
+				}
+				o.Index.Options[key] = value
+			}
+		}
+	}
+	// Set the pgvector columns
+	for i, name := range vectorNames {
+		if i < len(vectorTypes) {
+			o.Vectors = append(o.Vectors, VectorColumn{Name: name, Type: vectorTypes[i]})
+		}
+	}
 	return nil
 }
 
@@ -210,6 +265,76 @@ func (o *ObjectList) ScanCount(row pg.Row) error {
 	return row.Scan(&o.Count)
 }
 
+func (o *ObjectRows) Scan(row pg.Row) error {
+	var line string
+	if err := row.Scan(&line); err != nil {
+		return err
+	}
+	o.WriteString(line)
+	o.WriteByte('\n')
+	return nil
+}
+
+// Export declares a cursor which returns the rows of a table, view,
+// materialized view or foreign table as JSON objects, which are read with
+// ObjectRows in batches of the given size
+func (o ObjectName) Export(ctx context.Context, conn pg.Conn, objectType string, batchSize uint) (pg.Cursor, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Only objects with rows can be exported
+	switch objectType {
+	case "TABLE", "PARTITIONED TABLE", "VIEW", "MATERIALIZED VIEW", "FOREIGN TABLE":
+	default:
+		return nil, pg.ErrBadParameter.Withf("cannot export %s %q", strings.ToLower(objectType), o.Name)
+	}
+
+	// Declare the cursor
+	return conn.With(
+		"as", ObjectRowDef,
+		"schema", strings.TrimSpace(o.Schema),
+		"name", strings.TrimSpace(o.Name),
+	).Cursor(ctx, objectExport, batchSize)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WRITER
+
+// Reindex rebuilds an index, or all the indexes of a table or materialized
+// view. When concurrently is true, the object is not locked against writes
+// while the indexes are rebuilt.
+func (o ObjectName) Reindex(ctx context.Context, conn pg.Conn, objectType string, concurrently bool) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	// Determine the kind of object to reindex
+	var kind string
+	switch objectType {
+	case "INDEX", "PARTITIONED INDEX":
+		kind = "INDEX"
+	case "TABLE", "PARTITIONED TABLE", "MATERIALIZED VIEW":
+		kind = "TABLE"
+	default:
+		return pg.ErrBadParameter.Withf("cannot reindex %s %q", strings.ToLower(objectType), o.Name)
+	}
+
+	// Options
+	var options string
+	if concurrently {
+		options = "CONCURRENTLY "
+	}
+
+	// Reindex the object
+	return conn.With(
+		"kind", kind,
+		"options", options,
+		"schema", strings.TrimSpace(o.Schema),
+		"name", strings.TrimSpace(o.Name),
+	).Exec(ctx, objectReindex)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // VALIDATION
 
@@ -248,7 +373,8 @@ func (o ObjectName) Validate() error {
 // SQL
 
 const (
-	ObjectDef    = `object ("oid" OID, "database" TEXT, "schema" TEXT, "name" TEXT, "type" TEXT, "owner" TEXT, "acl" TEXT[], "tablespace" TEXT, "size" BIGINT, "live_tuples" BIGINT, "dead_tuples" BIGINT)`
+	ObjectRowDef = `row ("row" TEXT)`
+	ObjectDef    = `object ("oid" OID, "database" TEXT, "schema" TEXT, "name" TEXT, "type" TEXT, "owner" TEXT, "acl" TEXT[], "tablespace" TEXT, "size" BIGINT, "live_tuples" BIGINT, "dead_tuples" BIGINT, "index_method" TEXT, "index_options" TEXT[], "vector_columns" TEXT[], "vector_types" TEXT[], "comment" TEXT)`
 	objectSelect = `
 		WITH objects AS (
 			SELECT
@@ -277,7 +403,12 @@ const (
 					ELSE pg_relation_size(C.oid)
 				END AS size,
 				S.n_live_tup AS live_tuples,
-				S.n_dead_tup AS dead_tuples
+				S.n_dead_tup AS dead_tuples,
+				CASE WHEN C.relkind IN ('i', 'I') THEN AM.amname::TEXT END AS index_method,
+				CASE WHEN C.relkind IN ('i', 'I') THEN C.reloptions END AS index_options,
+				V.names AS vector_columns,
+				V.types AS vector_types,
+				obj_description(C.oid, 'pg_class') AS comment
 			FROM
 				pg_class C
 			JOIN
@@ -288,10 +419,25 @@ const (
 				pg_tablespace T ON T.oid = C.reltablespace
 			LEFT JOIN
 				pg_stat_user_tables S ON S.relid = C.oid
+			LEFT JOIN
+				pg_am AM ON AM.oid = C.relam
+			LEFT JOIN LATERAL (
+				SELECT
+					ARRAY_AGG(A.attname::TEXT ORDER BY A.attnum) AS names,
+					ARRAY_AGG(format_type(A.atttypid, A.atttypmod) ORDER BY A.attnum) AS types
+				FROM
+					pg_attribute A
+				JOIN
+					pg_type VT ON VT.oid = A.atttypid
+				WHERE
+					A.attrelid = C.oid AND A.attnum > 0 AND NOT A.attisdropped AND VT.typname IN ('vector', 'halfvec', 'sparsevec')
+			) V ON TRUE
 			WHERE
 				N.nspname NOT LIKE 'pg_%' AND N.nspname != 'information_schema' AND C.relkind != 't'
 		) SELECT * FROM objects
 	`
-	objectGet  = objectSelect + `WHERE name = ${'name'} AND schema = ${'schema'}`
-	objectList = `WITH q AS (` + objectSelect + `) SELECT * FROM q ${where} ${orderby}`
+	objectGet     = objectSelect + `WHERE name = @name AND schema = @schema`
+	objectList    = `WITH q AS (` + objectSelect + `) SELECT * FROM q ${where} ${orderby}`
+	objectReindex = `REINDEX ${kind} ${options}${"schema"}.${"name"}`
+	objectExport  = `SELECT row_to_json(R)::TEXT FROM ${"schema"}.${"name"} AS R`
 )
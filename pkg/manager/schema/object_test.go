@@ -1,6 +1,7 @@
 package schema_test
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -139,6 +140,36 @@ func Test_ObjectName_Select(t *testing.T) {
 	})
 }
 
+type textRow string
+
+func (r textRow) Scan(dest ...any) error {
+	*(dest[0].(*string)) = string(r)
+	return nil
+}
+
+func Test_ObjectName_Export(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("EmptyName", func(t *testing.T) {
+		_, err := schema.ObjectName{Schema: "public"}.Export(context.TODO(), nil, "TABLE", 0)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("NoRows", func(t *testing.T) {
+		for _, objectType := range []string{"INDEX", "SEQUENCE", "COMPOSITE TYPE"} {
+			_, err := schema.ObjectName{Schema: "public", Name: "users"}.Export(context.TODO(), nil, objectType, 0)
+			assert.ErrorIs(err, pg.ErrBadParameter, objectType)
+		}
+	})
+
+	t.Run("Rows", func(t *testing.T) {
+		var rows schema.ObjectRows
+		assert.NoError(rows.Scan(textRow(`{"id":1}`)))
+		assert.NoError(rows.Scan(textRow(`{"id":2}`)))
+		assert.Equal("{\"id\":1}\n{\"id\":2}\n", rows.String())
+	})
+}
+
 func Test_ObjectListRequest_Select(t *testing.T) {
 	assert := assert.New(t)
 
@@ -160,8 +191,8 @@ func Test_ObjectListRequest_Select(t *testing.T) {
 		assert.NoError(err)
 		assert.NotEmpty(sql)
 		where := bind.Get("where").(string)
-		assert.Contains(where, "schema = ")
-		assert.Contains(where, "public")
+		assert.Contains(where, "schema = @schema")
+		assert.Equal("public", bind.Get("schema"))
 	})
 
 	t.Run("ListWithDatabaseFilter", func(t *testing.T) {
@@ -172,8 +203,8 @@ func Test_ObjectListRequest_Select(t *testing.T) {
 		assert.NoError(err)
 		assert.NotEmpty(sql)
 		where := bind.Get("where").(string)
-		assert.Contains(where, "database = ")
-		assert.Contains(where, "mydb")
+		assert.Contains(where, "database = @database")
+		assert.Equal("mydb", bind.Get("database"))
 	})
 
 	t.Run("ListWithTypeFilter", func(t *testing.T) {
@@ -184,8 +215,8 @@ func Test_ObjectListRequest_Select(t *testing.T) {
 		assert.NoError(err)
 		assert.NotEmpty(sql)
 		where := bind.Get("where").(string)
-		assert.Contains(where, "type = ")
-		assert.Contains(where, "TABLE")
+		assert.Contains(where, "type = @type")
+		assert.Equal("TABLE", bind.Get("type"))
 	})
 
 	t.Run("ListWithMultipleFilters", func(t *testing.T) {
@@ -591,3 +622,37 @@ func Test_Object_WithTableMeta(t *testing.T) {
 		assert.False(hasTable, "VIEW should not have table metadata")
 	})
 }
+
+func Test_Object_WithIndexMeta(t *testing.T) {
+	assert := assert.New(t)
+
+	o := schema.Object{
+		Oid:      16386,
+		Database: "mydb",
+		Schema:   "public",
+		Type:     "INDEX",
+		ObjectMeta: schema.ObjectMeta{
+			Name:  "items_embedding_idx",
+			Owner: "postgres",
+		},
+		Index: &schema.IndexMeta{
+			Method:  "hnsw",
+			Options: map[string]string{"m": "16", "ef_construction": "64"},
+		},
+		Vectors: []schema.VectorColumn{{Name: "embedding", Type: "vector(1536)"}},
+	}
+
+	var parsed map[string]interface{}
+	err := json.Unmarshal([]byte(o.String()), &parsed)
+	assert.NoError(err)
+
+	index, hasIndex := parsed["index"].(map[string]interface{})
+	if assert.True(hasIndex) {
+		assert.Equal("hnsw", index["method"])
+		assert.Equal(map[string]interface{}{"m": "16", "ef_construction": "64"}, index["options"])
+	}
+	vectors, hasVectors := parsed["vectors"].([]interface{})
+	if assert.True(hasVectors) {
+		assert.Equal(map[string]interface{}{"name": "embedding", "type": "vector(1536)"}, vectors[0])
+	}
+}
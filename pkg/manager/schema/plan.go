@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// SchemaPlan is the statements which migrate a database to match another,
+// written to a file so that they can be reviewed before they are run. The
+// checksum covers the databases, schemas and statements, so that a plan which
+// has been edited since it was written fails verification.
+type SchemaPlan struct {
+	Version  uint   `json:"version"`
+	Database string `json:"database"`
+	Other    string `json:"other"`
+	SchemaDiffRequest
+	Created     time.Time    `json:"created"`
+	Differences []Difference `json:"differences,omitempty"`
+	Statements  []string     `json:"statements,omitempty"`
+	Checksum    string       `json:"checksum"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// The version of the plan file format
+	SchemaPlanVersion = 1
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// NewSchemaPlan returns a plan for the statements which migrate the database
+// to match the other database
+func NewSchemaPlan(database, other string, req SchemaDiffRequest, diff SchemaDiff) SchemaPlan {
+	plan := SchemaPlan{
+		Version:           SchemaPlanVersion,
+		Database:          database,
+		Other:             other,
+		SchemaDiffRequest: req,
+		Created:           time.Now().UTC().Truncate(time.Second),
+		Differences:       diff.Body,
+		Statements:        diff.Statements,
+	}
+	plan.Checksum = plan.Sum()
+	return plan
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (p SchemaPlan) String() string {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Sum returns the SHA-256 checksum of the databases, schemas and statements
+// of the plan, as a hex string
+func (p SchemaPlan) Sum() string {
+	hash := sha256.New()
+	for _, value := range append([]string{p.Database, p.Other, p.Schema, p.OtherSchema}, p.Statements...) {
+		// Each value is terminated, so that values cannot run into each other
+		hash.Write([]byte(value))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Verify returns ErrBadParameter if the plan has been edited since it was
+// written, and ErrConflict if the statements no longer match the statements
+// in the current difference between the databases
+func (p SchemaPlan) Verify(diff SchemaDiff) error {
+	if p.Version != SchemaPlanVersion {
+		return pg.ErrBadParameter.Withf("unsupported plan version %d", p.Version)
+	} else if p.Checksum != p.Sum() {
+		return pg.ErrBadParameter.With("plan checksum does not match, the plan has been modified")
+	} else if !slices.Equal(p.Statements, diff.Statements) {
+		return pg.ErrConflict.Withf("plan is out of date: %q and %q have changed since the plan was written", p.Database, p.Other)
+	}
+	return nil
+}
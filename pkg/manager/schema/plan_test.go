@@ -0,0 +1,69 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_SchemaPlan(t *testing.T) {
+	assert := assert.New(t)
+
+	diff := schema.SchemaDiff{
+		Statements: []string{
+			`CREATE TABLE public.t (id integer)`,
+			`ALTER TABLE public.u ADD COLUMN name text`,
+		},
+	}
+	req := schema.SchemaDiffRequest{Schema: "public"}
+
+	t.Run("Verify", func(t *testing.T) {
+		plan := schema.NewSchemaPlan("staging", "production", req, diff)
+		assert.Equal(uint(schema.SchemaPlanVersion), plan.Version)
+		assert.Equal(plan.Sum(), plan.Checksum)
+		assert.NoError(plan.Verify(diff))
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		data, err := json.Marshal(schema.NewSchemaPlan("staging", "production", req, diff))
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		var plan schema.SchemaPlan
+		if assert.NoError(json.Unmarshal(data, &plan)) {
+			assert.Equal("public", plan.Schema)
+			assert.NoError(plan.Verify(diff))
+		}
+	})
+
+	t.Run("Modified", func(t *testing.T) {
+		plan := schema.NewSchemaPlan("staging", "production", req, diff)
+		plan.Statements = append([]string{`DROP TABLE public.v`}, plan.Statements...)
+		assert.ErrorIs(plan.Verify(diff), pg.ErrBadParameter)
+
+		plan = schema.NewSchemaPlan("staging", "production", req, diff)
+		plan.Database = "other"
+		assert.ErrorIs(plan.Verify(diff), pg.ErrBadParameter)
+	})
+
+	t.Run("OutOfDate", func(t *testing.T) {
+		plan := schema.NewSchemaPlan("staging", "production", req, diff)
+		assert.ErrorIs(plan.Verify(schema.SchemaDiff{Statements: diff.Statements[:1]}), pg.ErrConflict)
+	})
+
+	t.Run("Version", func(t *testing.T) {
+		plan := schema.NewSchemaPlan("staging", "production", req, diff)
+		plan.Version = schema.SchemaPlanVersion + 1
+		assert.ErrorIs(plan.Verify(diff), pg.ErrBadParameter)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		plan := schema.NewSchemaPlan("staging", "production", req, schema.SchemaDiff{})
+		assert.NoError(plan.Verify(schema.SchemaDiff{}))
+		assert.ErrorIs(plan.Verify(diff), pg.ErrConflict)
+	})
+}
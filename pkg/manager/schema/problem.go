@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"encoding/json"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Problem is the body of an error response, as described in RFC 7807, with
+// the PostgreSQL error fields when the error was returned by the server
+type Problem struct {
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Status     int    `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+	Instance   string `json:"instance,omitempty"`
+	Code       string `json:"code"`                 // Machine-readable error code, such as "unique_violation"
+	SQLState   string `json:"sqlstate,omitempty"`   // PostgreSQL error code, such as "23505"
+	Constraint string `json:"constraint,omitempty"` // Constraint which was violated
+	Schema     string `json:"schema,omitempty"`
+	Table      string `json:"table,omitempty"`
+	Column     string `json:"column,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// ProblemContentType is the content type of an error response
+	ProblemContentType = "application/problem+json"
+
+	// ProblemType is the type of a problem which is described by its status
+	// and code, rather than by a URI
+	ProblemType = "about:blank"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (p Problem) String() string {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+// Error returns the title and detail of the problem, so that a problem can
+// be returned as an error
+func (p Problem) Error() string {
+	if p.Detail == "" {
+		return p.Title
+	}
+	return p.Title + ": " + p.Detail
+}
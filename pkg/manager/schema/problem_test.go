@@ -0,0 +1,34 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Problem_String(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("ProducesJSON", func(t *testing.T) {
+		problem := schema.Problem{Type: schema.ProblemType, Title: "Conflict", Status: 409, Code: "unique_violation", SQLState: "23505", Constraint: "users_email_key"}
+		var parsed map[string]any
+		err := json.Unmarshal([]byte(problem.String()), &parsed)
+		assert.NoError(err)
+		assert.Equal("about:blank", parsed["type"])
+		assert.Equal(float64(409), parsed["status"])
+		assert.Equal("unique_violation", parsed["code"])
+		assert.Equal("23505", parsed["sqlstate"])
+		assert.Equal("users_email_key", parsed["constraint"])
+		assert.NotContains(parsed, "table")
+	})
+}
+
+func Test_Problem_Error(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("Conflict", schema.Problem{Title: "Conflict"}.Error())
+	assert.Equal("Conflict: database \"x\" already exists", schema.Problem{Title: "Conflict", Detail: "database \"x\" already exists"}.Error())
+}
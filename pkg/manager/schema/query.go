@@ -0,0 +1,174 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// QueryRequest is a request to run a read-only query in a database
+type QueryRequest struct {
+	Database string  `json:"database,omitempty" help:"Database to run the query in"`
+	Query    string  `json:"query" arg:"" help:"SELECT statement to run"`
+	Limit    *uint64 `json:"limit,omitempty" help:"Maximum number of rows to return"`
+	Timeout  *uint64 `json:"timeout_ms,omitempty" name:"timeout-ms" help:"Statement timeout in milliseconds"`
+}
+
+// QueryColumn describes a column in the result of a query
+type QueryColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"` // JSON type of the first non-null value
+}
+
+// QueryResult is the result of a query, with the rows in column order
+type QueryResult struct {
+	Database  string        `json:"database,omitempty"`
+	Columns   []QueryColumn `json:"columns"`
+	Rows      [][]any       `json:"rows"`
+	Truncated bool          `json:"truncated,omitempty"` // More rows were available than returned
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (q QueryRequest) String() string {
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (q QueryResult) String() string {
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// RowLimit returns the maximum number of rows to return, which is at most
+// QueryRowLimit
+func (q QueryRequest) RowLimit() uint64 {
+	if q.Limit == nil || *q.Limit == 0 || *q.Limit > QueryRowLimit {
+		return QueryRowLimit
+	}
+	return *q.Limit
+}
+
+// StatementTimeout returns the statement timeout in milliseconds, which is at
+// most QueryTimeout
+func (q QueryRequest) StatementTimeout() uint64 {
+	if q.Timeout == nil || *q.Timeout == 0 || *q.Timeout > QueryTimeout {
+		return QueryTimeout
+	}
+	return *q.Timeout
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (q QueryRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// The query is required, and must be a single statement, ignoring any
+	// semicolons in literals, quoted identifiers and comments
+	if query, err := singleStatement(q.Query); err != nil {
+		return "", err
+	} else {
+		bind.Set("query", query)
+	}
+
+	// Fetch one more row than the limit, to determine if the result is truncated
+	bind.Set("limit", q.RowLimit()+1)
+
+	// Statement to run before the query, if any
+	if !bind.Has("readonly") {
+		bind.Set("readonly", "")
+	}
+
+	// Return query
+	switch op {
+	case pg.List:
+		return queryRows, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported QueryRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (q *QueryResult) Scan(row pg.Row) error {
+	var data []byte
+	if err := row.Scan(&data); err != nil {
+		return err
+	}
+
+	// Decode the object, retaining the column order
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	var values []any
+	for i := 0; dec.More(); i++ {
+		name, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		values = append(values, value)
+
+		// Set the column from the first row, and the type from the first non-null value
+		if i >= len(q.Columns) {
+			q.Columns = append(q.Columns, QueryColumn{Name: name.(string)})
+		}
+		if q.Columns[i].Type == "" {
+			q.Columns[i].Type = jsonType(value)
+		}
+	}
+
+	// Append the row
+	q.Rows = append(q.Rows, values)
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return ""
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	QueryDef  = `query ("row" TEXT)`
+	queryRows = `${readonly}SELECT row_to_json(q)::TEXT FROM (${query}) q LIMIT ${limit}`
+)
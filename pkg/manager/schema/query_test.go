@@ -0,0 +1,120 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+type queryRow []byte
+
+func (r queryRow) Scan(dest ...any) error {
+	*(dest[0].(*[]byte)) = r
+	return nil
+}
+
+func Test_QueryRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.QueryRequest{Query: "SELECT 1;"}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal("SELECT row_to_json(q)::TEXT FROM (SELECT 1) q LIMIT 1001", bind.Replace(sql))
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.QueryRequest{Query: "SELECT 1", Limit: types.Uint64Ptr(10)}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal(uint64(11), bind.Get("limit"))
+	})
+
+	t.Run("MultipleStatements", func(t *testing.T) {
+		_, err := schema.QueryRequest{Query: "SELECT 1; DELETE FROM test"}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("SemicolonInLiteral", func(t *testing.T) {
+		for _, query := range []string{
+			"SELECT ';'",
+			"SELECT 'a'';b'",
+			"SELECT $$;$$",
+			"SELECT $tag$;$tag$;",
+			`SELECT 1 AS "a;b"`,
+			"SELECT 1 /* ; */",
+		} {
+			bind := pg.NewBind()
+			_, err := schema.QueryRequest{Query: query}.Select(bind, pg.List)
+			assert.NoError(err, query)
+		}
+	})
+
+	t.Run("MultipleStatementsAfterLiteral", func(t *testing.T) {
+		_, err := schema.QueryRequest{Query: "SELECT ';'; DELETE FROM test"}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("MultipleStatementsAfterIdentifier", func(t *testing.T) {
+		for _, query := range []string{
+			"SELECT * FROM (SELECT 1 AS a$$) q; COMMIT; DROP TABLE t; SELECT * FROM (SELECT 1 AS b$$) r",
+			"SELECT 1 AS \"a$$\"; DROP TABLE t; SELECT $$",
+			"SELECT $a$ $b$ $a$; DROP TABLE t; SELECT $b$ $b$",
+			"SELECT E'\\''; DROP TABLE t; SELECT '\\'",
+		} {
+			_, err := schema.QueryRequest{Query: query}.Select(pg.NewBind(), pg.List)
+			assert.ErrorIs(err, pg.ErrBadParameter, query)
+		}
+	})
+
+	t.Run("TrailingComment", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.QueryRequest{Query: "SELECT 1 -- comment\n; /* comment */"}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal("SELECT row_to_json(q)::TEXT FROM (SELECT 1) q LIMIT 1001", bind.Replace(sql))
+	})
+
+	t.Run("Unterminated", func(t *testing.T) {
+		_, err := schema.QueryRequest{Query: "SELECT $$; DROP TABLE t"}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("MissingQuery", func(t *testing.T) {
+		_, err := schema.QueryRequest{}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.QueryRequest{Query: "SELECT 1"}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_QueryRequest_Limits(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(uint64(schema.QueryRowLimit), schema.QueryRequest{}.RowLimit())
+	assert.Equal(uint64(schema.QueryRowLimit), schema.QueryRequest{Limit: types.Uint64Ptr(schema.QueryRowLimit + 1)}.RowLimit())
+	assert.Equal(uint64(5), schema.QueryRequest{Limit: types.Uint64Ptr(5)}.RowLimit())
+	assert.Equal(uint64(schema.QueryTimeout), schema.QueryRequest{}.StatementTimeout())
+	assert.Equal(uint64(100), schema.QueryRequest{Timeout: types.Uint64Ptr(100)}.StatementTimeout())
+}
+
+func Test_QueryResult_Scan(t *testing.T) {
+	assert := assert.New(t)
+
+	var result schema.QueryResult
+	assert.NoError(result.Scan(queryRow(`{"z":null,"a":1,"b":"x"}`)))
+	assert.NoError(result.Scan(queryRow(`{"z":true,"a":2,"b":"y"}`)))
+	assert.Equal([]schema.QueryColumn{
+		{Name: "z", Type: "boolean"},
+		{Name: "a", Type: "number"},
+		{Name: "b", Type: "string"},
+	}, result.Columns)
+	assert.Equal([]any{nil, json.Number("1"), "x"}, result.Rows[0])
+	assert.Len(result.Rows, 2)
+}
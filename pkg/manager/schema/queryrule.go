@@ -0,0 +1,108 @@
+package schema
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// QueryRules limit the ad-hoc queries which can be run. The rules are checked
+// before a query is run, and an empty set of rules allows all queries.
+type QueryRules struct {
+	Schemas       []string `json:"schemas,omitempty" help:"Schemas which queries may read from (default all)"`
+	DenyFunctions []string `json:"deny_functions,omitempty" help:"Functions which queries may not call, matched regardless of schema"`
+	MaxCost       float64  `json:"max_cost,omitempty" help:"Maximum estimated total cost of the query plan (default unlimited)"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (r QueryRules) String() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// IsZero returns true if there are no rules
+func (r QueryRules) IsZero() bool {
+	return len(r.Schemas) == 0 && len(r.DenyFunctions) == 0 && r.MaxCost <= 0
+}
+
+// NeedsPlan returns true if the query plan is required to check the rules
+func (r QueryRules) NeedsPlan() bool {
+	return len(r.Schemas) > 0 || r.MaxCost > 0
+}
+
+// CheckQuery returns ErrBadParameter if the query calls a denied function
+func (r QueryRules) CheckQuery(query string) error {
+	if len(r.DenyFunctions) == 0 {
+		return nil
+	}
+
+	// Normalize the denied function names
+	deny := make([]string, 0, len(r.DenyFunctions))
+	for _, name := range r.DenyFunctions {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			deny = append(deny, name[strings.LastIndex(name, ".")+1:])
+		}
+	}
+
+	// Look for identifiers followed by an opening parenthesis, which are
+	// calls to functions, ignoring literals and comments
+	tokens, err := lexQuery(query)
+	if err != nil {
+		return err
+	}
+	for i, token := range tokens[:max(len(tokens)-1, 0)] {
+		if token.kind != queryIdentifier && token.kind != queryQuotedIdentifier {
+			continue
+		} else if !tokens[i+1].isSymbol("(") {
+			continue
+		}
+		if slices.Contains(deny, token.value) {
+			return pg.ErrBadParameter.Withf("query denied: function %q is not allowed", token.value)
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// CheckPlan returns ErrBadParameter if the query plan reads from a schema
+// which is not allowed, or exceeds the maximum cost. The plan needs to be
+// verbose, so that relations are qualified with their schema.
+func (r QueryRules) CheckPlan(plan ExplainPlan) error {
+	if r.MaxCost > 0 && plan.TotalCost > r.MaxCost {
+		return pg.ErrBadParameter.Withf("query denied: estimated cost %.2f exceeds %.2f", plan.TotalCost, r.MaxCost)
+	}
+	if len(r.Schemas) > 0 {
+		return r.checkSchemas(plan)
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (r QueryRules) checkSchemas(plan ExplainPlan) error {
+	if plan.Schema != "" && !slices.Contains(r.Schemas, plan.Schema) {
+		return pg.ErrBadParameter.Withf("query denied: schema %q is not allowed", plan.Schema)
+	}
+	for _, child := range plan.Plans {
+		if err := r.checkSchemas(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_QueryRules_CheckQuery(t *testing.T) {
+	assert := assert.New(t)
+	rules := schema.QueryRules{DenyFunctions: []string{"pg_sleep", "pg_catalog.pg_read_file"}}
+
+	t.Run("Allowed", func(t *testing.T) {
+		assert.NoError(rules.CheckQuery("SELECT count(*) FROM t WHERE id IN (1, 2)"))
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		assert.ErrorIs(rules.CheckQuery("SELECT PG_SLEEP (1)"), pg.ErrBadParameter)
+		assert.ErrorIs(rules.CheckQuery(`SELECT pg_catalog."pg_sleep"(1)`), pg.ErrBadParameter)
+		assert.ErrorIs(rules.CheckQuery("SELECT pg_read_file('/etc/passwd')"), pg.ErrBadParameter)
+	})
+
+	t.Run("IgnoresLiteralsAndComments", func(t *testing.T) {
+		assert.NoError(rules.CheckQuery("SELECT 'pg_sleep(1)', $$pg_sleep(1)$$ -- pg_sleep(1)\n/* pg_sleep(1) */"))
+	})
+
+	t.Run("DollarInIdentifier", func(t *testing.T) {
+		assert.ErrorIs(rules.CheckQuery("SELECT 1 AS a$$, pg_read_file('/etc/passwd') AS b$$"), pg.ErrBadParameter)
+		assert.ErrorIs(rules.CheckQuery("SELECT 1 AS a$x$, pg_read_file('/etc/passwd') AS b$x$"), pg.ErrBadParameter)
+	})
+
+	t.Run("DollarQuoteTag", func(t *testing.T) {
+		assert.ErrorIs(rules.CheckQuery("SELECT $a$ $b$ $a$, pg_sleep(1), $b$ $b$"), pg.ErrBadParameter)
+		assert.NoError(rules.CheckQuery("SELECT $a$ $$ pg_sleep(1) $$ $a$, $1"))
+	})
+
+	t.Run("EscapeString", func(t *testing.T) {
+		assert.ErrorIs(rules.CheckQuery(`SELECT E'\'', pg_sleep(1), '\'`), pg.ErrBadParameter)
+		assert.NoError(rules.CheckQuery(`SELECT 'a\', 'pg_sleep(1)'`))
+		assert.NoError(rules.CheckQuery(`SELECT e'\' pg_sleep(1)'`))
+	})
+
+	t.Run("UnicodeIdentifier", func(t *testing.T) {
+		assert.ErrorIs(rules.CheckQuery(`SELECT U&"pg\005fsleep"(1)`), pg.ErrBadParameter)
+		assert.ErrorIs(rules.CheckQuery(`SELECT U&"pg!+00005fsleep" UESCAPE '!' (1)`), pg.ErrBadParameter)
+	})
+
+	t.Run("NestedComment", func(t *testing.T) {
+		assert.ErrorIs(rules.CheckQuery("SELECT /* /* */ pg_sleep(1) */ pg_sleep(1)"), pg.ErrBadParameter)
+		assert.NoError(rules.CheckQuery("SELECT /* /* */ pg_sleep(1) */ 1"))
+	})
+
+	t.Run("Unterminated", func(t *testing.T) {
+		for _, query := range []string{"SELECT 'a", `SELECT "a`, "SELECT $a$ b", "SELECT /* a", "SELECT E'\\'"} {
+			assert.ErrorIs(rules.CheckQuery(query), pg.ErrBadParameter, query)
+		}
+	})
+
+	t.Run("NoRules", func(t *testing.T) {
+		assert.True(schema.QueryRules{}.IsZero())
+		assert.NoError(schema.QueryRules{}.CheckQuery("SELECT pg_sleep(1)"))
+	})
+}
+
+func Test_QueryRules_CheckPlan(t *testing.T) {
+	assert := assert.New(t)
+	plan := schema.ExplainPlan{
+		NodeType:  "Nested Loop",
+		TotalCost: 100,
+		Plans: []schema.ExplainPlan{
+			{NodeType: "Seq Scan", RelationName: "a", Schema: "public", TotalCost: 10},
+			{NodeType: "Seq Scan", RelationName: "pg_class", Schema: "pg_catalog", TotalCost: 10},
+		},
+	}
+
+	t.Run("AllowedSchemas", func(t *testing.T) {
+		assert.NoError(schema.QueryRules{Schemas: []string{"public", "pg_catalog"}}.CheckPlan(plan))
+	})
+
+	t.Run("DeniedSchema", func(t *testing.T) {
+		assert.ErrorIs(schema.QueryRules{Schemas: []string{"public"}}.CheckPlan(plan), pg.ErrBadParameter)
+	})
+
+	t.Run("MaxCost", func(t *testing.T) {
+		assert.NoError(schema.QueryRules{MaxCost: 100}.CheckPlan(plan))
+		assert.ErrorIs(schema.QueryRules{MaxCost: 99.5}.CheckPlan(plan), pg.ErrBadParameter)
+	})
+}
@@ -0,0 +1,308 @@
+package schema
+
+import (
+	"strconv"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// queryToken is a token of a query, other than whitespace and comments
+type queryToken struct {
+	kind    queryTokenKind
+	value   string // Identifier folded to lower case, unquoted identifier, or the text of other tokens
+	end     int    // Offset in the query of the end of the token
+	unicode bool   // Quoted identifier with unicode escapes
+}
+
+type queryTokenKind int
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	queryIdentifier       queryTokenKind = iota // Identifier or keyword
+	queryQuotedIdentifier                       // Quoted identifier
+	queryString                                 // Quoted or dollar-quoted string
+	querySymbol                                 // Any other character, or a parameter
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// singleStatement returns the query without trailing semicolons and
+// comments, or ErrBadParameter if the query is missing or contains more
+// than one statement
+func singleStatement(query string) (string, error) {
+	tokens, err := lexQuery(query)
+	if err != nil {
+		return "", err
+	}
+
+	// Ignore the semicolons which end the statement
+	end := len(tokens)
+	for end > 0 && tokens[end-1].isSymbol(";") {
+		end--
+	}
+	if end == 0 {
+		return "", pg.ErrBadParameter.With("query is missing")
+	}
+	for _, token := range tokens[:end] {
+		if token.isSymbol(";") {
+			return "", pg.ErrBadParameter.With("query must be a single statement")
+		}
+	}
+
+	// Return the statement
+	return strings.TrimSpace(query[:tokens[end-1].end]), nil
+}
+
+// lexQuery splits a query into tokens with the lexical rules of PostgreSQL,
+// so that literals, quoted identifiers and comments are never mistaken for
+// the statements and function calls they contain. Strings are lexed as
+// with standard_conforming_strings on, which is the default, so that a
+// backslash only escapes a quote within an escape string. Returns
+// ErrBadParameter if a literal, quoted identifier or comment is not
+// terminated.
+func lexQuery(query string) ([]queryToken, error) {
+	var tokens []queryToken
+	for i := 0; i < len(query); {
+		var token queryToken
+		var n int
+		var err error
+
+		c := query[i]
+		switch {
+		case strings.IndexByte(" \t\n\r\f\v", c) >= 0:
+			i++
+			continue
+		case strings.HasPrefix(query[i:], "--"):
+			if n = strings.IndexByte(query[i:], '\n'); n < 0 {
+				n = len(query) - i
+			}
+			i += n
+			continue
+		case strings.HasPrefix(query[i:], "/*"):
+			if n, err = lexComment(query[i:]); err != nil {
+				return nil, err
+			}
+			i += n
+			continue
+		case c == '\'':
+			token.kind = queryString
+			n, err = lexQuoted(query[i:], '\'', false)
+		case c == '"':
+			token.kind = queryQuotedIdentifier
+			n, err = lexQuoted(query[i:], '"', false)
+		case c == '$':
+			token.kind, n, err = lexDollar(query[i:])
+		case isIdentifierStart(c):
+			token.kind, n, err = lexWord(query[i:])
+		default:
+			token.kind, n = querySymbol, 1
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Set the value of the token
+		switch text := query[i : i+n]; token.kind {
+		case queryIdentifier:
+			token.value = strings.ToLower(text)
+		case queryQuotedIdentifier:
+			if strings.HasPrefix(text, "U&") || strings.HasPrefix(text, "u&") {
+				token.unicode = true
+				text = text[2:]
+			}
+			token.value = strings.ReplaceAll(text[1:len(text)-1], `""`, `"`)
+		default:
+			token.value = text
+		}
+
+		// Append the token
+		i += n
+		token.end = i
+		tokens = append(tokens, token)
+	}
+
+	// Decode quoted identifiers with unicode escapes
+	return decodeUnicode(tokens)
+}
+
+// isSymbol returns true if the token is the symbol
+func (token queryToken) isSymbol(value string) bool {
+	return token.kind == querySymbol && token.value == value
+}
+
+// lexComment returns the length of a block comment, which can be nested
+func lexComment(query string) (int, error) {
+	depth := 0
+	for i := 0; i < len(query)-1; i++ {
+		switch query[i : i+2] {
+		case "/*":
+			depth++
+			i++
+		case "*/":
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1, nil
+			}
+		}
+	}
+	return 0, pg.ErrBadParameter.With("query has an unterminated comment")
+}
+
+// lexQuoted returns the length of a string or quoted identifier, where the
+// quote is escaped by doubling it, or with a backslash in an escape string
+func lexQuoted(query string, quote byte, backslash bool) (int, error) {
+	for i := 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+			} else {
+				return i + 1, nil
+			}
+		}
+	}
+	if quote == '"' {
+		return 0, pg.ErrBadParameter.With("query has an unterminated quoted identifier")
+	}
+	return 0, pg.ErrBadParameter.With("query has an unterminated string")
+}
+
+// lexDollar returns the length of a dollar-quoted string, which ends with
+// the same tag it starts with, or a parameter or symbol when the dollar
+// does not start a tag
+func lexDollar(query string) (queryTokenKind, int, error) {
+	tag := 1
+	if tag < len(query) && isIdentifierStart(query[tag]) {
+		for tag < len(query) && (isIdentifierStart(query[tag]) || isDigit(query[tag])) {
+			tag++
+		}
+	}
+
+	// Return a parameter, or the dollar symbol
+	if tag == len(query) || query[tag] != '$' {
+		n := 1
+		for tag == 1 && n < len(query) && isDigit(query[n]) {
+			n++
+		}
+		return querySymbol, n, nil
+	}
+
+	// Find the closing tag
+	tag++
+	if n := strings.Index(query[tag:], query[:tag]); n >= 0 {
+		return queryString, tag + n + tag, nil
+	}
+	return 0, 0, pg.ErrBadParameter.With("query has an unterminated dollar-quoted string")
+}
+
+// lexWord returns the length of an identifier or keyword, or of a string
+// or quoted identifier with a prefix, such as an escape string
+func lexWord(query string) (queryTokenKind, int, error) {
+	n := 1
+	for n < len(query) && (isIdentifierStart(query[n]) || isDigit(query[n]) || query[n] == '$') {
+		n++
+	}
+
+	// Strings with a prefix
+	if n == 1 && n < len(query) && query[n] == '\'' {
+		switch query[0] {
+		case 'E', 'e':
+			length, err := lexQuoted(query[n:], '\'', true)
+			return queryString, n + length, err
+		case 'B', 'b', 'X', 'x', 'N', 'n':
+			length, err := lexQuoted(query[n:], '\'', false)
+			return queryString, n + length, err
+		}
+	}
+
+	// Strings and quoted identifiers with unicode escapes
+	if n == 1 && (query[0] == 'U' || query[0] == 'u') && len(query) > 2 && query[1] == '&' {
+		switch query[2] {
+		case '\'':
+			length, err := lexQuoted(query[2:], '\'', false)
+			return queryString, 2 + length, err
+		case '"':
+			length, err := lexQuoted(query[2:], '"', false)
+			return queryQuotedIdentifier, 2 + length, err
+		}
+	}
+
+	// Return the identifier
+	return queryIdentifier, n, nil
+}
+
+// decodeUnicode decodes the escapes in quoted identifiers with a unicode
+// prefix, using the escape character of any UESCAPE clause which follows
+func decodeUnicode(tokens []queryToken) ([]queryToken, error) {
+	result := make([]queryToken, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if !token.unicode {
+			result = append(result, token)
+			continue
+		}
+
+		// Set the escape character
+		escape := byte('\\')
+		if i+2 < len(tokens) && tokens[i+1].kind == queryIdentifier && tokens[i+1].value == "uescape" && tokens[i+2].kind == queryString {
+			if value := tokens[i+2].value; len(value) == 3 && value[0] == '\'' && value[2] == '\'' {
+				escape = value[1]
+			} else {
+				return nil, pg.ErrBadParameter.With("query has an invalid unicode escape character")
+			}
+			i += 2
+		}
+
+		// Decode the identifier
+		var value strings.Builder
+		for j := 0; j < len(token.value); j++ {
+			if token.value[j] != escape {
+				value.WriteByte(token.value[j])
+				continue
+			}
+			digits := 4
+			switch {
+			case j+1 < len(token.value) && token.value[j+1] == escape:
+				value.WriteByte(escape)
+				j++
+				continue
+			case j+1 < len(token.value) && token.value[j+1] == '+':
+				digits = 6
+				j++
+			}
+			if j+digits >= len(token.value) {
+				return nil, pg.ErrBadParameter.With("query has an invalid unicode escape")
+			}
+			code, err := strconv.ParseUint(token.value[j+1:j+1+digits], 16, 32)
+			if err != nil {
+				return nil, pg.ErrBadParameter.With("query has an invalid unicode escape")
+			}
+			value.WriteRune(rune(code))
+			j += digits
+		}
+		token.value = value.String()
+		result = append(result, token)
+	}
+	return result, nil
+}
+
+func isIdentifierStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= 0x80
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,133 @@
+package schema
+
+import (
+	"encoding/json"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// RecoveryRequest selects the recovery status of the connected server
+type RecoveryRequest struct{}
+
+// RecoveryStatus is the recovery status of the connected server, which is a
+// standby when it is in recovery, with the positions of the WAL it has
+// received and replayed
+type RecoveryStatus struct {
+	InRecovery   bool       `json:"in_recovery"` // True if the server is a standby
+	Timeline     uint32     `json:"timeline"`
+	ReplayPaused bool       `json:"replay_paused,omitempty"` // True if replay has been paused on a standby
+	CurrentLSN   *string    `json:"current_lsn,omitempty"`   // The position written, when the server is a primary
+	ReceiveLSN   *string    `json:"receive_lsn,omitempty"`
+	ReplayLSN    *string    `json:"replay_lsn,omitempty"`
+	ReplayTime   *time.Time `json:"replay_time,omitempty"` // When the last replayed transaction was committed on the primary
+	LagBytes     *int64     `json:"lag_bytes,omitempty"`   // Bytes of WAL received which have not been replayed
+}
+
+// PromoteRequest promotes a standby to a primary with pg_promote, waiting
+// for the promotion to complete unless NoWait is set
+type PromoteRequest struct {
+	NoWait  bool    `json:"no_wait,omitempty" name:"no-wait" help:"Return without waiting for the promotion to complete"`
+	Timeout *uint64 `json:"timeout,omitempty" help:"Seconds to wait for the promotion to complete (default 60)"`
+}
+
+// Promoted is true when pg_promote completed the promotion, or false if it
+// did not complete before the timeout
+type Promoted bool
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// The seconds to wait for a promotion to complete, when not set
+	PromoteTimeout = 60
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (r RecoveryStatus) String() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (r PromoteRequest) String() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Seconds returns the seconds to wait for the promotion to complete
+func (r PromoteRequest) Seconds() uint64 {
+	if r.Timeout == nil || *r.Timeout == 0 {
+		return PromoteTimeout
+	}
+	return *r.Timeout
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (r RecoveryRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	switch op {
+	case pg.Get:
+		return recoveryGet, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported RecoveryRequest operation %q", op)
+	}
+}
+
+func (r PromoteRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	switch op {
+	case pg.Update:
+		bind.Set("wait", !r.NoWait)
+		bind.Set("seconds", int64(r.Seconds()))
+		return recoveryPromote, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported PromoteRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (r *RecoveryStatus) Scan(row pg.Row) error {
+	return row.Scan(&r.InRecovery, &r.Timeline, &r.ReplayPaused, &r.CurrentLSN, &r.ReceiveLSN, &r.ReplayLSN, &r.ReplayTime, &r.LagBytes)
+}
+
+func (p *Promoted) Scan(row pg.Row) error {
+	return row.Scan((*bool)(p))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	recoveryGet = `
+		SELECT
+			pg_is_in_recovery() AS "in_recovery",
+			COALESCE(
+				(SELECT received_tli FROM pg_stat_wal_receiver),
+				(SELECT timeline_id FROM pg_control_checkpoint())
+			)::BIGINT AS "timeline",
+			CASE WHEN pg_is_in_recovery() THEN pg_is_wal_replay_paused() ELSE false END AS "replay_paused",
+			CASE WHEN pg_is_in_recovery() THEN NULL ELSE pg_current_wal_lsn()::TEXT END AS "current_lsn",
+			pg_last_wal_receive_lsn()::TEXT AS "receive_lsn",
+			pg_last_wal_replay_lsn()::TEXT AS "replay_lsn",
+			pg_last_xact_replay_timestamp() AS "replay_time",
+			(pg_last_wal_receive_lsn() - pg_last_wal_replay_lsn())::BIGINT AS "lag_bytes"
+	`
+	recoveryPromote = `SELECT pg_promote(@wait::BOOLEAN, @seconds::INTEGER)`
+)
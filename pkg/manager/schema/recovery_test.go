@@ -0,0 +1,86 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_RecoveryRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Get", func(t *testing.T) {
+		sql, err := schema.RecoveryRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_is_in_recovery")
+		assert.Contains(sql, "pg_last_wal_replay_lsn")
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.RecoveryRequest{}.Select(pg.NewBind(), pg.Update)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_PromoteRequest_Seconds(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Default", func(t *testing.T) {
+		assert.Equal(uint64(schema.PromoteTimeout), schema.PromoteRequest{}.Seconds())
+	})
+
+	t.Run("Zero", func(t *testing.T) {
+		timeout := uint64(0)
+		assert.Equal(uint64(schema.PromoteTimeout), schema.PromoteRequest{Timeout: &timeout}.Seconds())
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		timeout := uint64(5)
+		assert.Equal(uint64(5), schema.PromoteRequest{Timeout: &timeout}.Seconds())
+	})
+}
+
+func Test_PromoteRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Wait", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.PromoteRequest{}.Select(bind, pg.Update)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_promote")
+		assert.Equal(true, bind.Get("wait"))
+		assert.Equal(int64(schema.PromoteTimeout), bind.Get("seconds"))
+	})
+
+	t.Run("NoWait", func(t *testing.T) {
+		bind := pg.NewBind()
+		timeout := uint64(10)
+		_, err := schema.PromoteRequest{NoWait: true, Timeout: &timeout}.Select(bind, pg.Update)
+		assert.NoError(err)
+		assert.Equal(false, bind.Get("wait"))
+		assert.Equal(int64(10), bind.Get("seconds"))
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.PromoteRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_RecoveryStatus_String(t *testing.T) {
+	assert := assert.New(t)
+
+	lsn := "0/3000148"
+	status := schema.RecoveryStatus{Timeline: 1, CurrentLSN: &lsn}
+	var parsed map[string]any
+	err := json.Unmarshal([]byte(status.String()), &parsed)
+	assert.NoError(err)
+	assert.Equal(false, parsed["in_recovery"])
+	assert.Equal(float64(1), parsed["timeline"])
+	assert.Equal(lsn, parsed["current_lsn"])
+	assert.NotContains(parsed, "replay_lsn")
+}
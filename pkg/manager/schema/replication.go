@@ -0,0 +1,194 @@
+package schema
+
+import (
+	"encoding/json"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ReplicationStatus is the status of streaming replication from the connected
+// server to its standbys, and to the connected server when it is a standby
+type ReplicationStatus struct {
+	InRecovery bool                 `json:"in_recovery"`        // True if the server is a standby
+	Receiver   *ReplicationReceiver `json:"receiver,omitempty"` // The WAL receiver, when the server is a standby
+	Standbys   []ReplicationStandby `json:"standbys,omitempty"` // The standbys which stream from the server
+}
+
+// ReplicationStandbyListRequest selects the standbys which stream from the
+// connected server, from pg_stat_replication
+type ReplicationStandbyListRequest struct{}
+
+// ReplicationStandby is a standby which streams from the connected server,
+// with the positions it has been sent, written, flushed and replayed
+type ReplicationStandby struct {
+	Pid         uint32     `json:"pid"`
+	Name        string     `json:"name"` // The application_name of the standby
+	ClientAddr  string     `json:"client_addr,omitempty"`
+	State       string     `json:"state"`      // startup, catchup, streaming, backup or stopping
+	SyncState   string     `json:"sync_state"` // async, potential, sync or quorum
+	SentLSN     *string    `json:"sent_lsn,omitempty"`
+	WriteLSN    *string    `json:"write_lsn,omitempty"`
+	FlushLSN    *string    `json:"flush_lsn,omitempty"`
+	ReplayLSN   *string    `json:"replay_lsn,omitempty"`
+	LagBytes    *int64     `json:"lag_bytes,omitempty"` // Bytes of WAL which have not been replayed
+	WriteLagMs  *float64   `json:"write_lag_ms,omitempty"`
+	FlushLagMs  *float64   `json:"flush_lag_ms,omitempty"`
+	ReplayLagMs *float64   `json:"replay_lag_ms,omitempty"`
+	ReplyTime   *time.Time `json:"reply_time,omitempty"` // When the standby last reported its positions
+}
+
+// ReplicationStandbyList is a list of standbys with a count
+type ReplicationStandbyList struct {
+	Count uint64               `json:"count"`
+	Body  []ReplicationStandby `json:"body,omitempty"`
+}
+
+// ReplicationReceiverRequest selects the WAL receiver of the connected
+// server, from pg_stat_wal_receiver, which only runs on a standby
+type ReplicationReceiverRequest struct{}
+
+// ReplicationReceiver is the WAL receiver of a standby, with the positions it
+// has received and replayed from the primary
+type ReplicationReceiver struct {
+	Pid         uint32     `json:"pid"`
+	Status      string     `json:"status"`
+	SenderHost  string     `json:"sender_host,omitempty"`
+	SenderPort  *int32     `json:"sender_port,omitempty"`
+	SlotName    string     `json:"slot_name,omitempty"`
+	Timeline    uint32     `json:"timeline"`
+	ReceiveLSN  *string    `json:"receive_lsn,omitempty"` // The position flushed to disk
+	ReplayLSN   *string    `json:"replay_lsn,omitempty"`
+	LagBytes    *int64     `json:"lag_bytes,omitempty"` // Bytes of WAL received which have not been replayed
+	ReplayLagMs *float64   `json:"replay_lag_ms,omitempty"`
+	LastMessage *time.Time `json:"last_message,omitempty"` // When a message was last received from the primary
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s ReplicationStatus) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (s ReplicationStandby) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (s ReplicationStandbyList) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (r ReplicationReceiver) String() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (r ReplicationStandbyListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	switch op {
+	case pg.List:
+		return replicationStandbyList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported ReplicationStandbyListRequest operation %q", op)
+	}
+}
+
+func (r ReplicationReceiverRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	switch op {
+	case pg.Get:
+		return replicationReceiverGet, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported ReplicationReceiverRequest operation %q", op)
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (s *ReplicationStandby) Scan(row pg.Row) error {
+	return row.Scan(&s.Pid, &s.Name, &s.ClientAddr, &s.State, &s.SyncState, &s.SentLSN, &s.WriteLSN, &s.FlushLSN, &s.ReplayLSN, &s.LagBytes, &s.WriteLagMs, &s.FlushLagMs, &s.ReplayLagMs, &s.ReplyTime)
+}
+
+func (s *ReplicationStandbyList) Scan(row pg.Row) error {
+	var standby ReplicationStandby
+	if err := standby.Scan(row); err != nil {
+		return err
+	}
+	s.Body = append(s.Body, standby)
+	return nil
+}
+
+func (s *ReplicationStandbyList) ScanCount(row pg.Row) error {
+	return row.Scan(&s.Count)
+}
+
+func (r *ReplicationReceiver) Scan(row pg.Row) error {
+	return row.Scan(&r.Pid, &r.Status, &r.SenderHost, &r.SenderPort, &r.SlotName, &r.Timeline, &r.ReceiveLSN, &r.ReplayLSN, &r.LagBytes, &r.ReplayLagMs, &r.LastMessage)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	replicationStandbySelect = `
+		SELECT
+			R.pid AS "pid",
+			R.application_name AS "name",
+			COALESCE(host(R.client_addr), '') AS "client_addr",
+			R.state AS "state",
+			R.sync_state AS "sync_state",
+			R.sent_lsn::TEXT AS "sent_lsn",
+			R.write_lsn::TEXT AS "write_lsn",
+			R.flush_lsn::TEXT AS "flush_lsn",
+			R.replay_lsn::TEXT AS "replay_lsn",
+			(CASE WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn() ELSE pg_current_wal_lsn() END - R.replay_lsn)::BIGINT AS "lag_bytes",
+			(EXTRACT(EPOCH FROM R.write_lag) * 1000)::FLOAT8 AS "write_lag_ms",
+			(EXTRACT(EPOCH FROM R.flush_lag) * 1000)::FLOAT8 AS "flush_lag_ms",
+			(EXTRACT(EPOCH FROM R.replay_lag) * 1000)::FLOAT8 AS "replay_lag_ms",
+			R.reply_time AS "reply_time"
+		FROM
+			pg_stat_replication R
+	`
+	replicationStandbyList = `WITH q AS (` + replicationStandbySelect + `) SELECT * FROM q ORDER BY "name", "pid"`
+	replicationReceiverGet = `
+		SELECT
+			W.pid AS "pid",
+			W.status AS "status",
+			COALESCE(W.sender_host, '') AS "sender_host",
+			W.sender_port AS "sender_port",
+			COALESCE(W.slot_name, '') AS "slot_name",
+			W.received_tli::BIGINT AS "timeline",
+			W.flushed_lsn::TEXT AS "receive_lsn",
+			pg_last_wal_replay_lsn()::TEXT AS "replay_lsn",
+			(W.flushed_lsn - pg_last_wal_replay_lsn())::BIGINT AS "lag_bytes",
+			CASE
+				WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+				ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) * 1000
+			END::FLOAT8 AS "replay_lag_ms",
+			W.last_msg_receipt_time AS "last_message"
+		FROM
+			pg_stat_wal_receiver W
+	`
+)
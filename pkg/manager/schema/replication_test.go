@@ -0,0 +1,79 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_ReplicationStandbyListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		sql, err := schema.ReplicationStandbyListRequest{}.Select(pg.NewBind(), pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_stat_replication")
+		assert.Contains(sql, "replay_lag")
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.ReplicationStandbyListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_ReplicationReceiverRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Get", func(t *testing.T) {
+		sql, err := schema.ReplicationReceiverRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_stat_wal_receiver")
+		assert.Contains(sql, "pg_last_wal_replay_lsn")
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.ReplicationReceiverRequest{}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_ReplicationStatus_String(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Primary", func(t *testing.T) {
+		lag := int64(1024)
+		status := schema.ReplicationStatus{
+			Standbys: []schema.ReplicationStandby{{Pid: 123, Name: "standby1", State: "streaming", SyncState: "async", LagBytes: &lag}},
+		}
+		var parsed map[string]any
+		err := json.Unmarshal([]byte(status.String()), &parsed)
+		assert.NoError(err)
+		assert.Equal(false, parsed["in_recovery"])
+		assert.NotContains(parsed, "receiver")
+		if standbys, ok := parsed["standbys"].([]any); assert.True(ok) && assert.Len(standbys, 1) {
+			assert.Equal("standby1", standbys[0].(map[string]any)["name"])
+			assert.Equal(float64(1024), standbys[0].(map[string]any)["lag_bytes"])
+		}
+	})
+
+	t.Run("Standby", func(t *testing.T) {
+		status := schema.ReplicationStatus{
+			InRecovery: true,
+			Receiver:   &schema.ReplicationReceiver{Pid: 456, Status: "streaming", SenderHost: "primary", Timeline: 2},
+		}
+		var parsed map[string]any
+		err := json.Unmarshal([]byte(status.String()), &parsed)
+		assert.NoError(err)
+		assert.Equal(true, parsed["in_recovery"])
+		assert.NotContains(parsed, "standbys")
+		if receiver, ok := parsed["receiver"].(map[string]any); assert.True(ok) {
+			assert.Equal("primary", receiver["sender_host"])
+			assert.Equal(float64(2), receiver["timeline"])
+		}
+	})
+}
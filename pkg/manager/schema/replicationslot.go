@@ -133,8 +133,7 @@ func (n ReplicationSlotName) Select(bind *pg.Bind, op pg.Op) (string, error) {
 }
 
 func (r ReplicationSlotListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
-	bind.Set("where", "")
-	bind.Set("orderby", "ORDER BY name ASC")
+	pg.Where().OrderBy(`name`, true).Bind(bind)
 
 	r.OffsetLimit.Bind(bind, ReplicationSlotListLimit)
 
@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// DatabaseRestoreRequest contains the parameters for restoring a database
+type DatabaseRestoreRequest struct {
+	Format            string `json:"format,omitempty" help:"Dump format (plain, custom, tar), detected when not set"`
+	Clean             bool   `json:"clean,omitempty" help:"Drop objects before recreating them"`
+	SingleTransaction bool   `json:"single_transaction,omitempty" help:"Restore as a single transaction"`
+}
+
+// DatabaseRestoreProgress reports the progress of a restore
+type DatabaseRestoreProgress struct {
+	Bytes   uint64 `json:"bytes"`
+	Message string `json:"message,omitempty"`
+}
+
+// DatabaseRestore is the result of a restore
+type DatabaseRestore struct {
+	Database string `json:"database"`
+	Format   string `json:"format"`
+	Bytes    uint64 `json:"bytes"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	dumpCustomMagic = []byte("PGDMP")
+	dumpTarMagic    = []byte("ustar")
+)
+
+const (
+	dumpTarMagicOffset = 257
+)
+
+// Text stream events for a restore
+const (
+	RestoreEventProgress = "progress"
+	RestoreEventResult   = "result"
+	RestoreEventError    = "error"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (d DatabaseRestoreRequest) String() string {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (d DatabaseRestoreProgress) String() string {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (d DatabaseRestore) String() string {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Validate checks the restore format and options
+func (d DatabaseRestoreRequest) Validate() error {
+	switch d.format() {
+	case "", DumpFormatPlain, DumpFormatCustom, DumpFormatTar:
+		// Valid format
+	default:
+		return pg.ErrBadParameter.Withf("invalid dump format %q", d.Format)
+	}
+	if d.Clean && d.format() == DumpFormatPlain {
+		return pg.ErrBadParameter.With("clean is not supported for plain format dumps")
+	}
+	return nil
+}
+
+// WithFormat returns the request with the format set, detecting the format
+// from the header of the dump if it is not already set
+func (d DatabaseRestoreRequest) WithFormat(header []byte) DatabaseRestoreRequest {
+	if d.format() == "" {
+		d.Format = DetectDumpFormat(header)
+	} else {
+		d.Format = d.format()
+	}
+	return d
+}
+
+// Args returns the psql command line arguments for a plain format dump, or
+// the pg_restore command line arguments for an archive
+func (d DatabaseRestoreRequest) Args() []string {
+	var args []string
+	if d.format() == DumpFormatPlain {
+		args = []string{"--no-password", "--quiet", "--set=ON_ERROR_STOP=1", "--file=-"}
+	} else {
+		args = []string{"--no-password", "--verbose", "--format=" + d.format()}
+		if d.Clean {
+			args = append(args, "--clean", "--if-exists")
+		}
+	}
+	if d.SingleTransaction {
+		args = append(args, "--single-transaction")
+	}
+	return args
+}
+
+// DetectDumpFormat returns the format of a dump from its header, which
+// should be at least 512 bytes unless the dump is shorter
+func DetectDumpFormat(header []byte) string {
+	if bytes.HasPrefix(header, dumpCustomMagic) {
+		return DumpFormatCustom
+	}
+	if len(header) >= dumpTarMagicOffset+len(dumpTarMagic) && bytes.Equal(header[dumpTarMagicOffset:dumpTarMagicOffset+len(dumpTarMagic)], dumpTarMagic) {
+		return DumpFormatTar
+	}
+	return DumpFormatPlain
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (d DatabaseRestoreRequest) format() string {
+	return strings.ToLower(strings.TrimSpace(d.Format))
+}
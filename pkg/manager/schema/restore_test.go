@@ -0,0 +1,72 @@
+package schema_test
+
+import (
+	"bytes"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_DatabaseRestoreRequest_Validate(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Detect", func(t *testing.T) {
+		assert.NoError(schema.DatabaseRestoreRequest{}.Validate())
+	})
+
+	t.Run("Formats", func(t *testing.T) {
+		for _, format := range []string{"plain", "custom", "tar", "TAR"} {
+			assert.NoError(schema.DatabaseRestoreRequest{Format: format}.Validate(), format)
+		}
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		assert.ErrorIs(schema.DatabaseRestoreRequest{Format: "directory"}.Validate(), pg.ErrBadParameter)
+	})
+
+	t.Run("CleanPlain", func(t *testing.T) {
+		assert.ErrorIs(schema.DatabaseRestoreRequest{Format: "plain", Clean: true}.Validate(), pg.ErrBadParameter)
+	})
+}
+
+func Test_DetectDumpFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Custom", func(t *testing.T) {
+		assert.Equal(schema.DumpFormatCustom, schema.DetectDumpFormat([]byte("PGDMP\x01\x0e\x00")))
+	})
+
+	t.Run("Tar", func(t *testing.T) {
+		header := bytes.Repeat([]byte{0}, 512)
+		copy(header[257:], "ustar")
+		assert.Equal(schema.DumpFormatTar, schema.DetectDumpFormat(header))
+	})
+
+	t.Run("Plain", func(t *testing.T) {
+		assert.Equal(schema.DumpFormatPlain, schema.DetectDumpFormat([]byte("--\n-- PostgreSQL database dump\n")))
+	})
+}
+
+func Test_DatabaseRestoreRequest_Args(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Plain", func(t *testing.T) {
+		req := schema.DatabaseRestoreRequest{}.WithFormat([]byte("SELECT 1;"))
+		assert.Equal(schema.DumpFormatPlain, req.Format)
+		assert.Equal([]string{"--no-password", "--quiet", "--set=ON_ERROR_STOP=1", "--file=-"}, req.Args())
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		req := schema.DatabaseRestoreRequest{Clean: true, SingleTransaction: true}.WithFormat([]byte("PGDMP"))
+		assert.Equal(schema.DumpFormatCustom, req.Format)
+		assert.Equal([]string{"--no-password", "--verbose", "--format=custom", "--clean", "--if-exists", "--single-transaction"}, req.Args())
+	})
+
+	t.Run("ExplicitFormat", func(t *testing.T) {
+		req := schema.DatabaseRestoreRequest{Format: "Tar"}.WithFormat([]byte("PGDMP"))
+		assert.Equal(schema.DumpFormatTar, req.Format)
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
 	types "github.com/mutablelogic/go-server/pkg/types"
 )
 
@@ -30,6 +31,7 @@ type RoleMeta struct {
 	Password               *string    `json:"password,omitempty" help:"Password"`
 	Expires                *time.Time `json:"expires,omitzero" help:"Password expiration"`
 	Groups                 []string   `json:"memberof,omitempty" help:"Group memberships"`
+	Comment                *string    `json:"comment,omitempty" help:"Comment"`
 }
 
 type Role struct {
@@ -93,7 +95,7 @@ func RevokeGroupMembership(ctx context.Context, conn pg.Conn, group, member stri
 	if !types.IsIdentifier(group) || !types.IsIdentifier(member) {
 		return pg.ErrBadParameter.With("invalid group or member name")
 	}
-	return conn.Exec(ctx, fmt.Sprintf("REVOKE %s FROM %s", types.DoubleQuote(group), types.DoubleQuote(member)))
+	return conn.Exec(ctx, fmt.Sprintf("REVOKE %s FROM %s", quote.Ident(group), quote.Ident(member)))
 }
 
 // GrantGroupMembership grants group membership to a role.
@@ -105,7 +107,7 @@ func GrantGroupMembership(ctx context.Context, conn pg.Conn, group, member strin
 	if !types.IsIdentifier(group) || !types.IsIdentifier(member) {
 		return pg.ErrBadParameter.With("invalid group or member name")
 	}
-	return conn.Exec(ctx, fmt.Sprintf("GRANT %s TO %s", types.DoubleQuote(group), types.DoubleQuote(member)))
+	return conn.Exec(ctx, fmt.Sprintf("GRANT %s TO %s", quote.Ident(group), quote.Ident(member)))
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -145,7 +147,7 @@ func (r RoleMeta) name() (string, error) {
 
 func (r RoleListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
 	// Set empty where
-	bind.Set("where", "")
+	pg.Where().Bind(bind)
 
 	// Bind offset and limit
 	r.OffsetLimit.Bind(bind, RoleListLimit)
@@ -204,7 +206,7 @@ func (r RoleMeta) Select(bind *pg.Bind, op pg.Op) (string, error) {
 
 func (r *Role) Scan(row pg.Row) error {
 	var connlimit int64
-	if err := row.Scan(&r.Oid, &r.Name, &r.Superuser, &r.Inherit, &r.CreateRoles, &r.CreateDatabases, &r.Replication, &connlimit, &r.BypassRowLevelSecurity, &r.Login, &r.Password, &r.Expires, &r.Groups); err != nil {
+	if err := row.Scan(&r.Oid, &r.Name, &r.Superuser, &r.Inherit, &r.CreateRoles, &r.CreateDatabases, &r.Replication, &connlimit, &r.BypassRowLevelSecurity, &r.Login, &r.Password, &r.Expires, &r.Groups, &r.Comment); err != nil {
 		return err
 	}
 	if connlimit >= 0 {
@@ -317,11 +319,11 @@ func (r RoleMeta) with(insert bool) string {
 		} else if password == "" {
 			with = append(with, "PASSWORD NULL")
 		} else {
-			with = append(with, fmt.Sprintf("PASSWORD %v", types.Quote(password)))
+			with = append(with, fmt.Sprintf("PASSWORD %v", quote.Literal(password)))
 		}
 	}
 	if expires := types.PtrTime(r.Expires).UTC(); !expires.IsZero() {
-		with = append(with, fmt.Sprintf("VALID UNTIL %v", types.Quote(expires.Format(pgTimestampFormat))))
+		with = append(with, fmt.Sprintf("VALID UNTIL %v", quote.Literal(expires.Format(pgTimestampFormat))))
 	}
 	if len(r.Groups) > 0 && insert {
 		with = append(with, "IN ROLE "+strings.Join(r.Groups, ", "))
@@ -354,7 +356,8 @@ const (
 		WITH roles AS (
 			SELECT
 				"oid", "rolname", "rolsuper", "rolinherit", "rolcreaterole", "rolcreatedb", "rolreplication", "rolconnlimit", "rolbypassrls", "rolcanlogin", "rolpassword", "rolvaliduntil",
-                ARRAY(SELECT R2.rolname FROM "pg_catalog".pg_auth_members M JOIN "pg_catalog".pg_roles R2 ON M.roleid = R2.oid WHERE M.member = R.oid) AS groups
+                ARRAY(SELECT R2.rolname FROM "pg_catalog".pg_auth_members M JOIN "pg_catalog".pg_roles R2 ON M.roleid = R2.oid WHERE M.member = R.oid) AS groups,
+                shobj_description(R.oid, 'pg_authid') AS comment
 			FROM
 				${"schema"}."pg_roles" R
 			WHERE
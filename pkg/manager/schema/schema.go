@@ -6,6 +6,7 @@ import (
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
 	types "github.com/mutablelogic/go-server/pkg/types"
 )
 
@@ -15,9 +16,17 @@ import (
 type SchemaName string
 
 type SchemaMeta struct {
-	Name  string  `json:"name,omitempty" arg:"" help:"Name"`
-	Owner string  `json:"owner,omitempty" help:"Owner"`
-	Acl   ACLList `json:"acl,omitempty" help:"Access privileges"`
+	Name    string  `json:"name,omitempty" arg:"" help:"Name"`
+	Owner   string  `json:"owner,omitempty" help:"Owner"`
+	Acl     ACLList `json:"acl,omitempty" help:"Access privileges"`
+	Comment *string `json:"comment,omitempty" help:"Comment"`
+}
+
+// SchemaSize is the size of the relations in a schema, broken down by kind
+type SchemaSize struct {
+	Tables  uint64 `json:"table_bytes,omitempty" help:"Size of tables and materialized views in bytes"`
+	Indexes uint64 `json:"index_bytes,omitempty" help:"Size of indexes in bytes"`
+	Toast   uint64 `json:"toast_bytes,omitempty" help:"Size of TOAST tables and their indexes in bytes"`
 }
 
 type Schema struct {
@@ -25,6 +34,15 @@ type Schema struct {
 	Database string `json:"database,omitempty" help:"Database"`
 	SchemaMeta
 	Size uint64 `json:"bytes,omitempty" help:"Size of schema in bytes"`
+	SchemaSize
+}
+
+// SchemaRollup is the size of a schema, without the metadata
+type SchemaRollup struct {
+	Database string `json:"database"`
+	Name     string `json:"name"`
+	Size     uint64 `json:"bytes"`
+	SchemaSize
 }
 
 type SchemaListRequest struct {
@@ -37,6 +55,12 @@ type SchemaList struct {
 	Body  []Schema `json:"body,omitempty"`
 }
 
+type SchemaRollupList struct {
+	Count uint64         `json:"count"`
+	Size  uint64         `json:"bytes"` // Total size of the schemas
+	Body  []SchemaRollup `json:"body,omitempty"`
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -48,6 +72,22 @@ func (s SchemaMeta) String() string {
 	return string(data)
 }
 
+func (s SchemaRollup) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (s SchemaRollupList) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
 func (s Schema) String() string {
 	data, err := json.MarshalIndent(s, "", "  ")
 	if err != nil {
@@ -77,18 +117,13 @@ func (s SchemaList) String() string {
 
 func (d SchemaListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
 	// Order
-	bind.Set("orderby", `ORDER BY name ASC`)
+	where := pg.Where().OrderBy(`name`, true)
 
 	// Where
-	bind.Del("where")
 	if database := types.PtrString(d.Database); database != "" {
-		bind.Append("where", `database = `+types.Quote(database))
-	}
-	if where := bind.Join("where", " AND "); where != "" {
-		bind.Set("where", `WHERE `+where)
-	} else {
-		bind.Set("where", "")
+		where.Eq(`database`, database)
 	}
+	where.Bind(bind)
 
 	// Bind offset and limit
 	d.OffsetLimit.Bind(bind, SchemaListLimit)
@@ -153,7 +188,7 @@ func (s SchemaMeta) Select(bind *pg.Bind, op pg.Op) (string, error) {
 func (s *Schema) Scan(row pg.Row) error {
 	var priv []string
 	s.Acl = ACLList{}
-	if err := row.Scan(&s.Oid, &s.Database, &s.Name, &s.Owner, &priv, &s.Size); err != nil {
+	if err := row.Scan(&s.Oid, &s.Database, &s.Name, &s.Owner, &priv, &s.Comment, &s.Size, &s.Tables, &s.Indexes, &s.Toast); err != nil {
 		return err
 	}
 	for _, v := range priv {
@@ -242,9 +277,9 @@ func (s SchemaMeta) with(insert bool) (string, error) {
 	var with []string
 	if owner := strings.TrimSpace(s.Owner); owner != "" {
 		if insert {
-			with = append(with, "AUTHORIZATION "+types.DoubleQuote(s.Owner))
+			with = append(with, "AUTHORIZATION "+quote.Ident(s.Owner))
 		} else {
-			with = append(with, "OWNER TO "+types.DoubleQuote(s.Owner))
+			with = append(with, "OWNER TO "+quote.Ident(s.Owner))
 		}
 	}
 
@@ -256,11 +291,14 @@ func (s SchemaMeta) with(insert bool) (string, error) {
 // SQL
 
 const (
-	SchemaDef    = `schema ("oid" OID, "database" TEXT, "name" TEXT, "owner" TEXT, "acl" TEXT[], "size" BIGINT)`
+	SchemaDef    = `schema ("oid" OID, "database" TEXT, "name" TEXT, "owner" TEXT, "acl" TEXT[], "comment" TEXT, "size" BIGINT, "table_size" BIGINT, "index_size" BIGINT, "toast_size" BIGINT)`
 	schemaSelect = `
 		WITH sc AS (
 			SELECT
-				S.oid AS "oid", current_database() AS "database", S.nspname AS "name", R.rolname AS "owner", S.nspacl AS "acl", COALESCE(SUM(pg_relation_size(C.oid)),0) AS "size"
+				S.oid AS "oid", current_database() AS "database", S.nspname AS "name", R.rolname AS "owner", S.nspacl AS "acl", obj_description(S.oid, 'pg_namespace') AS "comment", COALESCE(SUM(pg_relation_size(C.oid)),0) AS "size",
+				COALESCE(SUM(pg_relation_size(C.oid)) FILTER (WHERE C.relkind IN ('r', 'm')),0) AS "table_size",
+				COALESCE(SUM(pg_relation_size(C.oid)) FILTER (WHERE C.relkind IN ('i', 'I')),0) AS "index_size",
+				COALESCE(SUM(pg_total_relation_size(C.reltoastrelid)) FILTER (WHERE C.reltoastrelid <> 0),0) AS "toast_size"
 			FROM
 				"pg_catalog"."pg_namespace" S
 			LEFT JOIN
@@ -270,9 +308,9 @@ const (
 			WHERE
 				S.nspname NOT LIKE 'pg_%' AND S.nspname != 'information_schema'
 			GROUP BY
-				1, 2, 3, 4, 5				
+				1, 2, 3, 4, 5, 6
 		) SELECT * FROM sc`
-	schemaGet    = schemaSelect + ` WHERE "name" = ${'name'}`
+	schemaGet    = schemaSelect + ` WHERE "name" = @name`
 	schemaList   = `WITH q AS (` + schemaSelect + `) SELECT * FROM q ${where} ${orderby}`
 	schemaDelete = `DROP SCHEMA ${"name"} ${with}`
 	schemaCreate = `CREATE SCHEMA ${"name"} ${with}`
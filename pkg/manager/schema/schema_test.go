@@ -268,6 +268,29 @@ func Test_Schema_String(t *testing.T) {
 	})
 }
 
+func Test_SchemaRollupList_String(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("ProducesJSON", func(t *testing.T) {
+		list := schema.SchemaRollupList{
+			Count: 1,
+			Size:  3072,
+			Body: []schema.SchemaRollup{
+				{Database: "testdb", Name: "public", Size: 3072, SchemaSize: schema.SchemaSize{Tables: 1024, Indexes: 2048, Toast: 8192}},
+			},
+		}
+		var parsed map[string]any
+		err := json.Unmarshal([]byte(list.String()), &parsed)
+		assert.NoError(err)
+		assert.Equal(float64(3072), parsed["bytes"])
+
+		body := parsed["body"].([]any)[0].(map[string]any)
+		assert.Equal(float64(1024), body["table_bytes"])
+		assert.Equal(float64(2048), body["index_bytes"])
+		assert.Equal(float64(8192), body["toast_bytes"])
+	})
+}
+
 func Test_SchemaMeta_String(t *testing.T) {
 	assert := assert.New(t)
 
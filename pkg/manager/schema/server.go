@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"encoding/json"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ServerRequest selects the metadata for the connected server
+type ServerRequest struct{}
+
+// Server is the metadata for the connected server, which is used to determine
+// whether the server is a primary or a standby
+type Server struct {
+	Version          string    `json:"version"`
+	InRecovery       bool      `json:"in_recovery"`       // True if the server is a standby
+	SystemIdentifier string    `json:"system_identifier"` // Identifies the cluster, shared by a primary and its standbys
+	Timeline         uint32    `json:"timeline"`          // Incremented on each promotion of a standby
+	ConfigLoaded     time.Time `json:"config_loaded"`     // When the configuration files were last loaded
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s Server) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (s ServerRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	switch op {
+	case pg.Get:
+		return serverGet, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported ServerRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (s *Server) Scan(row pg.Row) error {
+	return row.Scan(&s.Version, &s.InRecovery, &s.SystemIdentifier, &s.Timeline, &s.ConfigLoaded)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	serverGet = `
+		SELECT
+			current_setting('server_version') AS "version",
+			pg_is_in_recovery() AS "in_recovery",
+			(SELECT system_identifier::TEXT FROM pg_control_system()) AS "system_identifier",
+			COALESCE(
+				(SELECT received_tli FROM pg_stat_wal_receiver),
+				(SELECT timeline_id FROM pg_control_checkpoint())
+			)::BIGINT AS "timeline",
+			pg_conf_load_time() AS "config_loaded"
+	`
+)
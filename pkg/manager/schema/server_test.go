@@ -0,0 +1,41 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_ServerRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Get", func(t *testing.T) {
+		sql, err := schema.ServerRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_is_in_recovery")
+		assert.Contains(sql, "pg_control_system")
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.ServerRequest{}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_Server_String(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("ProducesJSON", func(t *testing.T) {
+		server := schema.Server{Version: "17.2", InRecovery: true, SystemIdentifier: "7312345678901234567", Timeline: 2}
+		var parsed map[string]any
+		err := json.Unmarshal([]byte(server.String()), &parsed)
+		assert.NoError(err)
+		assert.Equal(true, parsed["in_recovery"])
+		assert.Equal("7312345678901234567", parsed["system_identifier"])
+		assert.Equal(float64(2), parsed["timeline"])
+	})
+}
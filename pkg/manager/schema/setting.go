@@ -2,9 +2,16 @@ package schema
 
 import (
 	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
+	types "github.com/mutablelogic/go-server/pkg/types"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -22,17 +29,28 @@ type SettingMeta struct {
 type Setting struct {
 	Name string `json:"name"`
 	SettingMeta
-	Unit        *string `json:"unit,omitempty"`
-	Category    string  `json:"category"`
-	Context     string  `json:"context"` // internal, postmaster, sighup, superuser, user
-	Description string  `json:"description,omitempty"`
-	ExtraDesc   string  `json:"extra_desc,omitempty"`
+	BootValue   *string  `json:"boot_value,omitempty"` // Value when the server was started
+	Unit        *string  `json:"unit,omitempty"`
+	Type        string   `json:"type"`                // bool, enum, integer, real, string
+	Enum        []string `json:"enum,omitempty"`      // Allowed values of an enum setting
+	Min         *string  `json:"min_value,omitempty"` // Minimum value of a numeric setting, in its unit
+	Max         *string  `json:"max_value,omitempty"` // Maximum value of a numeric setting, in its unit
+	Category    string   `json:"category"`
+	Context     string   `json:"context"` // internal, postmaster, sighup, superuser, user
+	Description string   `json:"description,omitempty"`
+	ExtraDesc   string   `json:"extra_desc,omitempty"`
+
+	// True if the setting has been changed in the configuration files, but
+	// the server needs to be restarted for the change to take effect
+	PendingRestart bool `json:"pending_restart,omitempty"`
 }
 
 // SettingListRequest is used to retrieve server settings
 type SettingListRequest struct {
 	pg.OffsetLimit
-	Category *string `json:"category,omitempty" help:"Filter by category"`
+	Category       *string `json:"category,omitempty" help:"Filter by category"`
+	PendingRestart *bool   `json:"pending_restart,omitempty" help:"Filter by settings which require a restart to take effect"`
+	Pending        *bool   `json:"pending,omitempty" help:"Filter by settings which differ from the boot value or require a restart"`
 }
 
 // SettingList contains the list of settings
@@ -41,6 +59,23 @@ type SettingList struct {
 	Body  []Setting `json:"body,omitempty"`
 }
 
+// SettingChange is a setting which differs from the desired value, and the
+// statement which changes it
+type SettingChange struct {
+	Name      string  `json:"name"`
+	Value     *string `json:"value"`
+	Desired   string  `json:"desired"`
+	Context   string  `json:"context"` // sighup settings need a reload, postmaster settings a restart
+	Statement string  `json:"statement"`
+}
+
+// SettingDiff contains the changes needed for the settings to converge
+// with the desired values
+type SettingDiff struct {
+	Count uint64          `json:"count"`
+	Body  []SettingChange `json:"body,omitempty"`
+}
+
 // SettingCategoryListRequest is used to retrieve distinct setting categories
 type SettingCategoryListRequest struct{}
 
@@ -50,6 +85,27 @@ type SettingCategoryList struct {
 	Body  []string `json:"body,omitempty"`
 }
 
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+// Units of memory and time settings, with the multiplier of the base unit
+var settingUnits = map[string]struct {
+	base       string
+	multiplier float64
+}{
+	"B":   {"B", 1},
+	"kB":  {"B", 1 << 10},
+	"MB":  {"B", 1 << 20},
+	"GB":  {"B", 1 << 30},
+	"TB":  {"B", 1 << 40},
+	"us":  {"ms", 0.001},
+	"ms":  {"ms", 1},
+	"s":   {"ms", 1000},
+	"min": {"ms", 60 * 1000},
+	"h":   {"ms", 60 * 60 * 1000},
+	"d":   {"ms", 24 * 60 * 60 * 1000},
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -69,6 +125,14 @@ func (s SettingList) String() string {
 	return string(data)
 }
 
+func (s SettingDiff) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
 func (s SettingCategoryList) String() string {
 	data, err := json.MarshalIndent(s, "", "  ")
 	if err != nil {
@@ -81,16 +145,34 @@ func (s SettingCategoryList) String() string {
 // SELECT
 
 func (r SettingListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
-	// Set empty where
-	bind.Set("where", "")
-	bind.Set("orderby", "ORDER BY category, name")
+	where := pg.Where().OrderBy(`category`, true).OrderBy(`name`, true)
 
 	// Filter by category
 	if r.Category != nil {
-		bind.Set("category", *r.Category)
-		bind.Set("where", `WHERE category = @category`)
+		where.Eq(`category`, *r.Category)
 	}
 
+	// Filter by pending restart
+	if r.PendingRestart != nil {
+		if *r.PendingRestart {
+			where.Expr(`pending_restart`)
+		} else {
+			where.Expr(`NOT pending_restart`)
+		}
+	}
+
+	// Filter by settings which differ from the boot value or require a restart
+	if r.Pending != nil {
+		if *r.Pending {
+			where.Expr(`(value IS DISTINCT FROM boot_value OR pending_restart)`)
+		} else {
+			where.Expr(`NOT (value IS DISTINCT FROM boot_value OR pending_restart)`)
+		}
+	}
+
+	// Set where and order
+	where.Bind(bind)
+
 	// Bind offset and limit
 	r.OffsetLimit.Bind(bind, SettingListLimit)
 
@@ -132,6 +214,89 @@ func (n SettingName) Select(bind *pg.Bind, op pg.Op) (string, error) {
 	}
 }
 
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Equal returns true if a value is the same as the value of the setting.
+// Enumerated values are compared without case, boolean values can be any of
+// the spellings PostgreSQL accepts, and values
+// with a unit, such as "128MB" or "5min", are compared with the value in the
+// unit of the setting.
+func (s Setting) Equal(value string) bool {
+	value = strings.TrimSpace(value)
+	if s.Value == nil {
+		return false
+	} else if *s.Value == value {
+		return true
+	}
+	switch s.Type {
+	case "enum":
+		return strings.EqualFold(*s.Value, value)
+	case "bool":
+		a, aok := parseSettingBool(*s.Value)
+		b, bok := parseSettingBool(value)
+		return aok && bok && a == b
+	case "integer", "real":
+		a, aok := parseSettingUnit(*s.Value, types.PtrString(s.Unit))
+		b, bok := parseSettingUnit(value, types.PtrString(s.Unit))
+		return aok && bok && math.Abs(a-b) < 1e-9*math.Max(1, math.Abs(a))
+	default:
+		return false
+	}
+}
+
+// Validate returns an error if a value cannot be set for the setting: an
+// enum value which is not allowed, a boolean which cannot be parsed, or a
+// number with a unit of the wrong kind, or outside of the minimum and
+// maximum values
+func (s Setting) Validate(value string) error {
+	value = strings.TrimSpace(value)
+	switch s.Type {
+	case "enum":
+		if !slices.ContainsFunc(s.Enum, func(v string) bool { return strings.EqualFold(v, value) }) {
+			return pg.ErrBadParameter.Withf("invalid value %q for %q, expected one of %s", value, s.Name, strings.Join(s.Enum, ", "))
+		}
+	case "bool":
+		if _, ok := parseSettingBool(value); !ok {
+			return pg.ErrBadParameter.Withf("invalid boolean value %q for %q", value, s.Name)
+		}
+	case "integer", "real":
+		n, ok := parseSettingUnit(value, types.PtrString(s.Unit))
+		if !ok {
+			if s.Unit != nil {
+				return pg.ErrBadParameter.Withf("invalid value %q for %q, expected a number in %s or with a unit", value, s.Name, *s.Unit)
+			}
+			return pg.ErrBadParameter.Withf("invalid value %q for %q, expected a number", value, s.Name)
+		}
+		if s.Min != nil {
+			if minimum, err := strconv.ParseFloat(*s.Min, 64); err == nil && n < minimum {
+				return pg.ErrBadParameter.Withf("value %q for %q is less than the minimum %s", value, s.Name, *s.Min)
+			}
+		}
+		if s.Max != nil {
+			if maximum, err := strconv.ParseFloat(*s.Max, 64); err == nil && n > maximum {
+				return pg.ErrBadParameter.Withf("value %q for %q is greater than the maximum %s", value, s.Name, *s.Max)
+			}
+		}
+	}
+	return nil
+}
+
+// Change returns the change needed for the setting to have a value, or nil
+// if the setting already has the value
+func (s Setting) Change(value string) *SettingChange {
+	if s.Equal(value) {
+		return nil
+	}
+	return &SettingChange{
+		Name:      s.Name,
+		Value:     s.Value,
+		Desired:   value,
+		Context:   s.Context,
+		Statement: `ALTER SYSTEM SET ` + quote.Ident(s.Name) + ` = ` + quote.Literal(value),
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // WRITER
 
@@ -152,7 +317,7 @@ func (m SettingMeta) Update(bind *pg.Bind) error {
 // READER
 
 func (s *Setting) Scan(row pg.Row) error {
-	return row.Scan(&s.Name, &s.Value, &s.Unit, &s.Category, &s.Context, &s.Description, &s.ExtraDesc)
+	return row.Scan(&s.Name, &s.Value, &s.BootValue, &s.Unit, &s.Type, &s.Enum, &s.Min, &s.Max, &s.Category, &s.Context, &s.Description, &s.ExtraDesc, &s.PendingRestart)
 }
 
 func (l *SettingList) Scan(row pg.Row) error {
@@ -181,6 +346,83 @@ func (l *SettingCategoryList) ScanCount(row pg.Row) error {
 	return row.Scan(&l.Count)
 }
 
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Parse a boolean setting value, which can be a prefix of true, false, yes,
+// no or off (of at least two letters), or on, 1 or 0
+func parseSettingBool(value string) (bool, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case value == "":
+		return false, false
+	case value == "1", value == "on", strings.HasPrefix("true", value), strings.HasPrefix("yes", value):
+		return true, true
+	case value == "0", len(value) >= 2 && strings.HasPrefix("off", value), strings.HasPrefix("false", value), strings.HasPrefix("no", value):
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// Parse a numeric setting value with an optional unit, returning the value in
+// the unit of the setting, which can have a multiplier such as "8kB"
+func parseSettingUnit(value, unit string) (float64, bool) {
+	if unit == "" {
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return n, err == nil
+	}
+	base, multiplier := settingUnit(unit)
+	if multiplier == 0 {
+		return 0, false
+	}
+
+	// Split the number from the unit
+	value = strings.TrimSpace(value)
+	i := strings.IndexFunc(value, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != '-' && r != '+'
+	})
+	number, suffix := value, ""
+	if i >= 0 {
+		number, suffix = value[:i], strings.TrimSpace(value[i:])
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	// Without a unit, the value is in the unit of the setting
+	if suffix == "" {
+		return n, true
+	}
+	if factor, exists := settingUnits[suffix]; !exists || factor.base != base {
+		return 0, false
+	} else {
+		return n * factor.multiplier / multiplier, true
+	}
+}
+
+// Return the base unit and multiplier of the unit of a setting
+func settingUnit(unit string) (string, float64) {
+	unit = strings.TrimSpace(unit)
+	i := strings.IndexFunc(unit, func(r rune) bool { return !unicode.IsDigit(r) })
+	if i < 0 {
+		return "", 0
+	}
+	n := float64(1)
+	if i > 0 {
+		v, err := strconv.ParseFloat(unit[:i], 64)
+		if err != nil {
+			return "", 0
+		}
+		n = v
+	}
+	if factor, exists := settingUnits[unit[i:]]; exists {
+		return factor.base, n * factor.multiplier
+	}
+	return "", 0
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // SQL
 
@@ -189,16 +431,22 @@ const (
 		SELECT
 			name AS "name",
 			setting AS "value",
+			boot_val AS "boot_value",
 			unit AS "unit",
+			vartype AS "type",
+			enumvals AS "enum",
+			min_val AS "min_value",
+			max_val AS "max_value",
 			category AS "category",
 			context AS "context",
 			COALESCE(short_desc, '') AS "description",
-			COALESCE(extra_desc, '') AS "extra_desc"
+			COALESCE(extra_desc, '') AS "extra_desc",
+			pending_restart AS "pending_restart"
 		FROM
 			pg_catalog.pg_settings
 	`
 	settingList         = `WITH q AS (` + settingSelect + `) SELECT * FROM q ${where} ${orderby}`
-	settingGet          = settingSelect + ` WHERE name = ${'name'}`
+	settingGet          = settingSelect + ` WHERE name = @name`
 	settingUpdate       = `ALTER SYSTEM SET ${"name"} = ${'value'}`
 	settingReset        = `ALTER SYSTEM RESET ${"name"}`
 	settingCategoryList = `SELECT DISTINCT category FROM pg_catalog.pg_settings ORDER BY category`
@@ -40,6 +40,25 @@ func Test_SettingListRequest_Select(t *testing.T) {
 		assert.Equal(category, bind.Get("category"))
 	})
 
+	t.Run("WithPendingRestart", func(t *testing.T) {
+		bind := pg.NewBind()
+		category, pending := "Connections and Authentication", true
+		r := schema.SettingListRequest{Category: &category, PendingRestart: &pending}
+		_, err := r.Select(bind, pg.List)
+		assert.NoError(err)
+		where := bind.Get("where").(string)
+		assert.Contains(where, "category")
+		assert.Contains(where, "AND pending_restart")
+	})
+
+	t.Run("WithPending", func(t *testing.T) {
+		bind := pg.NewBind()
+		pending := true
+		_, err := schema.SettingListRequest{Pending: &pending}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal("WHERE (value IS DISTINCT FROM boot_value OR pending_restart)", bind.Get("where"))
+	})
+
 	t.Run("WithoutCategory", func(t *testing.T) {
 		bind := pg.NewBind()
 		r := schema.SettingListRequest{}
@@ -144,3 +163,83 @@ func Test_SettingList_String(t *testing.T) {
 		assert.Contains(str, "shared_buffers")
 	})
 }
+
+func Test_Setting_Equal(t *testing.T) {
+	assert := assert.New(t)
+
+	setting := func(typ, value, unit string) schema.Setting {
+		s := schema.Setting{Name: "test", Type: typ, SettingMeta: schema.SettingMeta{Value: &value}}
+		if unit != "" {
+			s.Unit = &unit
+		}
+		return s
+	}
+
+	tests := []struct {
+		setting schema.Setting
+		value   string
+		equal   bool
+	}{
+		{setting("string", "public", ""), "public", true},
+		{setting("string", "public", ""), "Public", false},
+		{setting("enum", "replica", ""), "REPLICA", true},
+		{setting("bool", "on", ""), "true", true},
+		{setting("bool", "on", ""), "yes", true},
+		{setting("bool", "off", ""), "0", true},
+		{setting("bool", "off", ""), "of", true},
+		{setting("bool", "off", ""), "o", false},
+		{setting("bool", "on", ""), "false", false},
+		{setting("integer", "16384", "8kB"), "128MB", true},
+		{setting("integer", "16384", "8kB"), "131072kB", true},
+		{setting("integer", "16384", "8kB"), "16384", true},
+		{setting("integer", "16384", "8kB"), "256MB", false},
+		{setting("integer", "60000", "ms"), "1min", true},
+		{setting("integer", "60", "s"), "60000ms", true},
+		{setting("integer", "60", "s"), "1GB", false},
+		{setting("integer", "100", ""), "100", true},
+		{setting("real", "0.2", ""), "0.20", true},
+		{setting("real", "0.2", ""), "0.3", false},
+	}
+	for _, test := range tests {
+		assert.Equal(test.equal, test.setting.Equal(test.value), "%s %q", *test.setting.Value, test.value)
+	}
+}
+
+func Test_Setting_Change(t *testing.T) {
+	assert := assert.New(t)
+
+	value, unit := "16384", "8kB"
+	setting := schema.Setting{Name: "shared_buffers", Type: "integer", Unit: &unit, Context: "postmaster", SettingMeta: schema.SettingMeta{Value: &value}}
+	assert.Nil(setting.Change("128MB"))
+
+	change := setting.Change("256MB")
+	if assert.NotNil(change) {
+		assert.Equal("shared_buffers", change.Name)
+		assert.Equal("256MB", change.Desired)
+		assert.Equal("postmaster", change.Context)
+		assert.Equal(`ALTER SYSTEM SET "shared_buffers" = '256MB'`, change.Statement)
+	}
+}
+
+func Test_Setting_Validate(t *testing.T) {
+	assert := assert.New(t)
+
+	unit, minimum, maximum := "8kB", "16", "1073741823"
+	buffers := schema.Setting{Name: "shared_buffers", Type: "integer", Unit: &unit, Min: &minimum, Max: &maximum}
+	assert.NoError(buffers.Validate("128MB"))
+	assert.NoError(buffers.Validate("16384"))
+	assert.ErrorIs(buffers.Validate("100kB"), pg.ErrBadParameter)
+	assert.ErrorIs(buffers.Validate("5min"), pg.ErrBadParameter)
+	assert.ErrorIs(buffers.Validate("lots"), pg.ErrBadParameter)
+
+	level := schema.Setting{Name: "wal_level", Type: "enum", Enum: []string{"minimal", "replica", "logical"}}
+	assert.NoError(level.Validate("Logical"))
+	assert.ErrorIs(level.Validate("archive"), pg.ErrBadParameter)
+
+	fsync := schema.Setting{Name: "fsync", Type: "bool"}
+	assert.NoError(fsync.Validate("off"))
+	assert.ErrorIs(fsync.Validate("maybe"), pg.ErrBadParameter)
+
+	path := schema.Setting{Name: "search_path", Type: "string"}
+	assert.NoError(path.Validate(`"$user", public`))
+}
@@ -0,0 +1,162 @@
+package schema
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// SettingHistoryMeta records a change to a setting. A nil value means the
+// setting was reset to its default
+type SettingHistoryMeta struct {
+	Name          string  `json:"name"`
+	PreviousValue *string `json:"previous_value"`
+	Value         *string `json:"value"`
+}
+
+// SettingHistory is a change to a setting made through the manager
+type SettingHistory struct {
+	Id uint64 `json:"id"`
+	SettingHistoryMeta
+	Role      string    `json:"role,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type SettingHistoryListRequest struct {
+	Name *string `json:"name,omitempty" help:"Filter by setting name"`
+	pg.OffsetLimit
+}
+
+type SettingHistoryList struct {
+	Count uint64           `json:"count"`
+	Body  []SettingHistory `json:"body,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (h SettingHistory) String() string {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (h SettingHistoryList) String() string {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// PendingReload returns the names of the settings which were changed after
+// the configuration files were loaded, and so do not take effect until the
+// configuration is reloaded. The list is expected to be most recent first.
+func (h SettingHistoryList) PendingReload(loaded time.Time) []string {
+	var result []string
+	for _, change := range h.Body {
+		if !change.Timestamp.After(loaded) {
+			break
+		}
+		if !slices.Contains(result, change.Name) {
+			result = append(result, change.Name)
+		}
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (r SettingHistoryListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Where
+	where := pg.Where()
+	if r.Name != nil {
+		if name := strings.TrimSpace(*r.Name); name != "" {
+			where.Eq(`name`, name)
+		}
+	}
+	where.Bind(bind)
+
+	// Bind offset and limit
+	r.OffsetLimit.Bind(bind, SettingHistoryListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return settingHistoryList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported SettingHistoryListRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WRITER
+
+func (m SettingHistoryMeta) Insert(bind *pg.Bind) (string, error) {
+	if name := strings.TrimSpace(m.Name); name == "" {
+		return "", pg.ErrBadParameter.With("name is missing")
+	} else {
+		bind.Set("name", name)
+	}
+	bind.Set("previous_value", m.PreviousValue)
+	bind.Set("new_value", m.Value)
+
+	// Return query
+	return settingHistoryInsert, nil
+}
+
+func (m SettingHistoryMeta) Update(_ *pg.Bind) error {
+	return pg.ErrNotImplemented.With("setting history cannot be updated")
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (h *SettingHistory) Scan(row pg.Row) error {
+	return row.Scan(&h.Id, &h.Name, &h.PreviousValue, &h.Value, &h.Role, &h.Timestamp)
+}
+
+func (l *SettingHistoryList) Scan(row pg.Row) error {
+	var history SettingHistory
+	if err := history.Scan(row); err != nil {
+		return err
+	}
+	l.Body = append(l.Body, history)
+	return nil
+}
+
+func (l *SettingHistoryList) ScanCount(row pg.Row) error {
+	return row.Scan(&l.Count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	settingHistoryCreateTable = `
+		CREATE TABLE IF NOT EXISTS ` + ManagerSchema + `.setting_history (
+			"id" BIGSERIAL PRIMARY KEY,
+			"name" TEXT NOT NULL,
+			"previous_value" TEXT,
+			"value" TEXT,
+			"role" TEXT NOT NULL DEFAULT CURRENT_USER,
+			"timestamp" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`
+	settingHistoryColumns = `"id", "name", "previous_value", "value", "role", "timestamp"`
+	settingHistoryInsert  = `INSERT INTO ` + ManagerSchema + `.setting_history ("name", "previous_value", "value") VALUES (@name, @previous_value, @new_value) RETURNING ` + settingHistoryColumns
+	settingHistoryList    = `SELECT ` + settingHistoryColumns + ` FROM ` + ManagerSchema + `.setting_history ${where} ORDER BY "timestamp" DESC, "id" DESC`
+)
@@ -0,0 +1,79 @@
+package schema_test
+
+import (
+	"testing"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_SettingHistoryMeta_Insert(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Insert", func(t *testing.T) {
+		bind := pg.NewBind()
+		previous, value := "100", "200"
+		sql, err := schema.SettingHistoryMeta{Name: "work_mem", PreviousValue: &previous, Value: &value}.Insert(bind)
+		assert.NoError(err)
+		assert.Contains(sql, "setting_history")
+		assert.Equal("work_mem", bind.Get("name"))
+		assert.Equal(&value, bind.Get("new_value"))
+	})
+
+	t.Run("MissingName", func(t *testing.T) {
+		_, err := schema.SettingHistoryMeta{}.Insert(pg.NewBind())
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		err := schema.SettingHistoryMeta{}.Update(pg.NewBind())
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_SettingHistoryListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.SettingHistoryListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal("", bind.Get("where"))
+	})
+
+	t.Run("ListByName", func(t *testing.T) {
+		bind := pg.NewBind()
+		name := "work_mem"
+		_, err := schema.SettingHistoryListRequest{Name: &name}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Equal("WHERE name = @name", bind.Get("where"))
+		assert.Equal("work_mem", bind.Get("name"))
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.SettingHistoryListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_SettingHistoryList_PendingReload(t *testing.T) {
+	assert := assert.New(t)
+
+	loaded := time.Now()
+	change := func(name string, when time.Time) schema.SettingHistory {
+		return schema.SettingHistory{SettingHistoryMeta: schema.SettingHistoryMeta{Name: name}, Timestamp: when}
+	}
+	list := schema.SettingHistoryList{Body: []schema.SettingHistory{
+		change("work_mem", loaded.Add(2*time.Minute)),
+		change("shared_buffers", loaded.Add(time.Minute)),
+		change("work_mem", loaded.Add(time.Second)),
+		change("max_connections", loaded.Add(-time.Minute)),
+	}}
+
+	assert.Equal([]string{"work_mem", "shared_buffers"}, list.PendingReload(loaded))
+	assert.Empty(list.PendingReload(loaded.Add(time.Hour)))
+	assert.Empty(schema.SettingHistoryList{}.PendingReload(loaded))
+}
@@ -0,0 +1,167 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// StaleTable is a table with no reads or writes since the statistics were
+// reset, and no recent autovacuum or autoanalyze, which is a candidate for
+// archiving
+type StaleTable struct {
+	Database        string     `json:"database"`
+	Schema          string     `json:"schema"`
+	Name            string     `json:"name"`
+	Owner           string     `json:"owner,omitempty"`
+	Size            uint64     `json:"bytes"`
+	LiveTuples      int64      `json:"live_tuples"`
+	LastVacuum      *time.Time `json:"last_vacuum,omitempty"`
+	LastAutovacuum  *time.Time `json:"last_autovacuum,omitempty"`
+	LastAnalyze     *time.Time `json:"last_analyze,omitempty"`
+	LastAutoanalyze *time.Time `json:"last_autoanalyze,omitempty"`
+	StatsReset      *time.Time `json:"stats_reset,omitempty"` // When the statistics for the database were last reset
+}
+
+type StaleTableListRequest struct {
+	Database *string `json:"database,omitempty" help:"Database"`
+	Schema   *string `json:"schema,omitempty" help:"Schema"`
+	Days     *uint64 `json:"days,omitempty" help:"Days without autovacuum or autoanalyze activity (default 30)"`
+	pg.OffsetLimit
+}
+
+type StaleTableList struct {
+	Count uint64       `json:"count"`
+	Body  []StaleTable `json:"body,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Default number of days without autovacuum or autoanalyze activity
+	// for a table to be considered stale
+	defaultStaleDays = 30
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (t StaleTable) String() string {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (t StaleTableListRequest) String() string {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (t StaleTableList) String() string {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (t StaleTableListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Days without activity
+	days := uint64(defaultStaleDays)
+	if t.Days != nil {
+		days = *t.Days
+	}
+	bind.Set("days", days)
+
+	// Where
+	where := pg.Where()
+	if t.Schema != nil {
+		if schema := strings.TrimSpace(*t.Schema); schema != "" {
+			where.Eq(`schema`, schema)
+		}
+	}
+	where.Bind(bind)
+
+	// Bind offset and limit
+	t.OffsetLimit.Bind(bind, StaleTableListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return staleTableList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported StaleTableListRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (t *StaleTable) Scan(row pg.Row) error {
+	return row.Scan(&t.Database, &t.Schema, &t.Name, &t.Owner, &t.Size, &t.LiveTuples, &t.LastVacuum, &t.LastAutovacuum, &t.LastAnalyze, &t.LastAutoanalyze, &t.StatsReset)
+}
+
+func (t *StaleTableList) Scan(row pg.Row) error {
+	var table StaleTable
+	if err := table.Scan(row); err != nil {
+		return err
+	} else {
+		t.Body = append(t.Body, table)
+	}
+	return nil
+}
+
+func (t *StaleTableList) ScanCount(row pg.Row) error {
+	return row.Scan(&t.Count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	StaleTableDef    = `stale_table ("database" TEXT, "schema" TEXT, "name" TEXT, "owner" TEXT, "size" BIGINT, "live_tuples" BIGINT, "last_vacuum" TIMESTAMPTZ, "last_autovacuum" TIMESTAMPTZ, "last_analyze" TIMESTAMPTZ, "last_autoanalyze" TIMESTAMPTZ, "stats_reset" TIMESTAMPTZ)`
+	staleTableSelect = `
+		SELECT
+			current_database() AS "database",
+			S.schemaname::TEXT AS "schema",
+			S.relname::TEXT AS "name",
+			R.rolname::TEXT AS "owner",
+			pg_total_relation_size(S.relid) AS "size",
+			S.n_live_tup AS "live_tuples",
+			S.last_vacuum AS "last_vacuum",
+			S.last_autovacuum AS "last_autovacuum",
+			S.last_analyze AS "last_analyze",
+			S.last_autoanalyze AS "last_autoanalyze",
+			D.stats_reset AS "stats_reset"
+		FROM
+			pg_stat_user_tables S
+		JOIN
+			pg_class C ON C.oid = S.relid
+		JOIN
+			pg_roles R ON R.oid = C.relowner
+		LEFT JOIN
+			pg_stat_database D ON D.datname = current_database()
+		WHERE
+			COALESCE(S.seq_scan, 0) + COALESCE(S.idx_scan, 0) = 0
+		AND
+			S.n_tup_ins + S.n_tup_upd + S.n_tup_del = 0
+		AND
+			COALESCE(GREATEST(S.last_autovacuum, S.last_autoanalyze), '-infinity') < NOW() - make_interval(days => ${days})
+	`
+	staleTableList = `WITH q AS (` + staleTableSelect + `) SELECT * FROM q ${where} ORDER BY "size" DESC, "schema", "name"`
+)
@@ -0,0 +1,59 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_StaleTableListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.StaleTableListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_stat_user_tables")
+		assert.Equal("", bind.Get("where"))
+		assert.Equal(uint64(30), bind.Get("days"))
+	})
+
+	t.Run("ListWithFilters", func(t *testing.T) {
+		bind := pg.NewBind()
+		namespace, days := "public", uint64(7)
+		_, err := schema.StaleTableListRequest{Schema: &namespace, Days: &days}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(bind.Get("where"), "schema")
+		assert.Equal(uint64(7), bind.Get("days"))
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.StaleTableListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_StaleTableList_String(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("ProducesJSON", func(t *testing.T) {
+		list := schema.StaleTableList{
+			Count: 1,
+			Body: []schema.StaleTable{
+				{Database: "testdb", Schema: "public", Name: "archive", Size: 8192},
+			},
+		}
+		var parsed map[string]any
+		err := json.Unmarshal([]byte(list.String()), &parsed)
+		assert.NoError(err)
+		assert.Equal(float64(1), parsed["count"])
+
+		body := parsed["body"].([]any)[0].(map[string]any)
+		assert.Equal("archive", body["name"])
+		assert.Equal(float64(8192), body["bytes"])
+	})
+}
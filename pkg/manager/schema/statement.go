@@ -29,6 +29,7 @@ type Statement struct {
 type StatementList struct {
 	Count uint64      `json:"count"`
 	Body  []Statement `json:"body"`
+	Next  string      `json:"next,omitempty"`
 }
 
 // StatementListRequest contains parameters for listing statements
@@ -44,6 +45,37 @@ type StatementListRequest struct {
 	// Sort by field (calls, rows, total_ms, min_ms, max_ms, mean_ms)
 	// All sort DESC except min_ms which sorts ASC
 	Sort string `json:"sort,omitempty"`
+
+	// Paginate from the last statement of the previous page, which
+	// cannot be combined with sort
+	KeysetPage
+}
+
+// StatementSummary is the statistics of the statements executed by a role
+// in a database, combined from pg_stat_statements
+type StatementSummary struct {
+	Role       string  `json:"role,omitempty"`
+	Database   string  `json:"database,omitempty"`
+	Statements int64   `json:"statements"` // Number of distinct statements
+	Calls      int64   `json:"calls"`
+	Rows       int64   `json:"rows"`
+	Total      float64 `json:"total_ms"`
+	Min        float64 `json:"min_ms"`
+	Max        float64 `json:"max_ms"`
+	Mean       float64 `json:"mean_ms"`
+	StdDev     float64 `json:"stddev_ms"` // Standard deviation of the time spent executing each call
+}
+
+// StatementSummaryList is a list of statement summaries with a total count
+type StatementSummaryList struct {
+	Count uint64             `json:"count"`
+	Body  []StatementSummary `json:"body"`
+}
+
+// StatementSummaryListRequest selects the statement summaries of each role
+// and database, with the most time spent executing statements first
+type StatementSummaryListRequest struct {
+	pg.OffsetLimit
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -65,48 +97,66 @@ func (l StatementList) String() string {
 	return string(data)
 }
 
+func (s StatementSummary) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (l StatementSummaryList) String() string {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // SELECTOR
 
 func (r StatementListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
 	// Build WHERE clause
-	var where []string
+	where := pg.Where()
 	if r.Database != nil && *r.Database != "" {
-		bind.Set("database", *r.Database)
-		where = append(where, "d.datname = @database")
+		where.Eq(`database`, *r.Database)
 	}
 	if r.Role != nil && *r.Role != "" {
-		bind.Set("role", *r.Role)
-		where = append(where, "u.rolname = @role")
+		where.Eq(`role`, *r.Role)
 	}
-
-	if len(where) > 0 {
-		bind.Set("where", "WHERE "+strings.Join(where, " AND "))
-	} else {
-		bind.Set("where", "")
+	var database, role string
+	var queryid int64
+	if ok, err := r.Key(&database, &queryid, &role); err != nil {
+		return "", err
+	} else if ok && r.Sort != "" {
+		return "", pg.ErrBadParameter.With("after cannot be combined with sort")
+	} else if ok {
+		where.After([]string{`database`, `queryid`, `role`}, database, queryid, role)
 	}
 
 	// Build ORDER BY clause - always order by database, query_id first
-	var sortClause string
+	where.OrderBy(`database`, true).OrderBy(`queryid`, true)
 	switch strings.ToLower(r.Sort) {
 	case "":
-		// No additional sort
+		// Order by role, so that the order is stable for keyset pagination
+		where.OrderBy(`role`, true)
 	case "calls":
-		sortClause = ", calls DESC"
+		where.OrderBy(`calls`, false)
 	case "rows":
-		sortClause = ", rows DESC"
+		where.OrderBy(`rows`, false)
 	case "total_ms":
-		sortClause = ", total_exec_time DESC"
+		where.OrderBy(`total_exec_time`, false)
 	case "min_ms":
-		sortClause = ", min_exec_time ASC"
+		where.OrderBy(`min_exec_time`, true)
 	case "max_ms":
-		sortClause = ", max_exec_time DESC"
+		where.OrderBy(`max_exec_time`, false)
 	case "mean_ms":
-		sortClause = ", mean_exec_time DESC"
+		where.OrderBy(`mean_exec_time`, false)
 	default:
 		return "", pg.ErrBadParameter.Withf("invalid sort parameter %q", r.Sort)
 	}
-	bind.Set("orderby", "ORDER BY database ASC, queryid ASC"+sortClause)
+	where.Bind(bind)
 
 	// Set offset/limit
 	r.OffsetLimit.Bind(bind, StatementListLimit)
@@ -120,6 +170,19 @@ func (r StatementListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
 	}
 }
 
+func (r StatementSummaryListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Set offset/limit
+	r.OffsetLimit.Bind(bind, StatementListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return statementSummaryList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported StatementSummaryListRequest operation %q", op)
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // READER
 
@@ -144,6 +207,27 @@ func (l *StatementList) ScanCount(row pg.Row) error {
 	return row.Scan(&l.Count)
 }
 
+func (s *StatementSummary) Scan(row pg.Row) error {
+	return row.Scan(
+		&s.Role, &s.Database, &s.Statements,
+		&s.Calls, &s.Rows,
+		&s.Total, &s.Min, &s.Max, &s.Mean, &s.StdDev,
+	)
+}
+
+func (l *StatementSummaryList) Scan(row pg.Row) error {
+	var summary StatementSummary
+	if err := summary.Scan(row); err != nil {
+		return err
+	}
+	l.Body = append(l.Body, summary)
+	return nil
+}
+
+func (l *StatementSummaryList) ScanCount(row pg.Row) error {
+	return row.Scan(&l.Count)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // QUERIES
 
@@ -169,3 +253,43 @@ const statementSelect = `
 `
 
 const statementList = `WITH q AS (` + statementSelect + `) SELECT * FROM q ${where} ${orderby}`
+
+// pg_stat_statements summarized for each role and database. The standard
+// deviation is pooled from the mean and standard deviation of each statement
+const statementSummarySelect = `
+	SELECT
+		role,
+		database,
+		COUNT(*) AS statements,
+		SUM(calls)::BIGINT AS calls,
+		SUM(rows)::BIGINT AS rows,
+		SUM(total_exec_time) AS total_exec_time,
+		MIN(min_exec_time) AS min_exec_time,
+		MAX(max_exec_time) AS max_exec_time,
+		COALESCE(SUM(total_exec_time) / NULLIF(SUM(calls), 0), 0) AS mean_exec_time,
+		COALESCE(SQRT(GREATEST(
+			SUM(calls * (stddev_exec_time ^ 2 + mean_exec_time ^ 2)) / NULLIF(SUM(calls), 0) - (SUM(total_exec_time) / NULLIF(SUM(calls), 0)) ^ 2, 0
+		)), 0) AS stddev_exec_time
+	FROM (
+		SELECT
+			COALESCE(u.rolname, '') AS role,
+			COALESCE(d.datname, '') AS database,
+			s.calls,
+			s.rows,
+			s.total_exec_time,
+			s.min_exec_time,
+			s.max_exec_time,
+			s.mean_exec_time,
+			s.stddev_exec_time
+		FROM
+			public.pg_stat_statements s
+		LEFT JOIN
+			pg_catalog.pg_roles u ON s.userid = u.oid
+		LEFT JOIN
+			pg_catalog.pg_database d ON s.dbid = d.oid
+	) q
+	GROUP BY
+		role, database
+`
+
+const statementSummaryList = `WITH q AS (` + statementSummarySelect + `) SELECT * FROM q ORDER BY total_exec_time DESC, database, role`
@@ -92,7 +92,7 @@ func Test_StatementListRequest_Select(t *testing.T) {
 		sql, err := req.Select(bind, pg.List)
 		assert.NoError(err)
 		assert.NotEmpty(sql)
-		assert.Equal("ORDER BY database ASC, queryid ASC", bind.Get("orderby"))
+		assert.Equal("ORDER BY database ASC, queryid ASC, role ASC", bind.Get("orderby"))
 		assert.Equal("", bind.Get("where"))
 	})
 
@@ -111,7 +111,7 @@ func Test_StatementListRequest_Select(t *testing.T) {
 		assert.NoError(err)
 		assert.NotEmpty(sql)
 		assert.Equal("testdb", bind.Get("database"))
-		assert.Contains(bind.Get("where"), "d.datname = @database")
+		assert.Contains(bind.Get("where"), "database = @database")
 	})
 
 	t.Run("FilterByRole", func(t *testing.T) {
@@ -122,7 +122,7 @@ func Test_StatementListRequest_Select(t *testing.T) {
 		assert.NoError(err)
 		assert.NotEmpty(sql)
 		assert.Equal("testuser", bind.Get("role"))
-		assert.Contains(bind.Get("where"), "u.rolname = @role")
+		assert.Contains(bind.Get("where"), "role = @role")
 	})
 
 	t.Run("FilterByDatabaseAndRole", func(t *testing.T) {
@@ -134,8 +134,8 @@ func Test_StatementListRequest_Select(t *testing.T) {
 		assert.NoError(err)
 		assert.NotEmpty(sql)
 		where := bind.Get("where").(string)
-		assert.Contains(where, "d.datname = @database")
-		assert.Contains(where, "u.rolname = @role")
+		assert.Contains(where, "database = @database")
+		assert.Contains(where, "role = @role")
 		assert.Contains(where, " AND ")
 	})
 
@@ -264,3 +264,40 @@ func Test_StatementListRequest_OffsetLimit(t *testing.T) {
 		assert.NotEmpty(sql)
 	})
 }
+
+func Test_StatementSummaryListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.StatementSummaryListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "GROUP BY")
+		assert.Contains(sql, "stddev_exec_time")
+		assert.Contains(bind.Get("offsetlimit"), "LIMIT 100")
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		bind := pg.NewBind()
+		limit := uint64(10)
+		_, err := schema.StatementSummaryListRequest{OffsetLimit: pg.OffsetLimit{Limit: &limit}}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(bind.Get("offsetlimit"), "LIMIT 10")
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.StatementSummaryListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_StatementSummary_String(t *testing.T) {
+	assert := assert.New(t)
+
+	summary := schema.StatementSummary{Role: "postgres", Database: "test", Statements: 2, Calls: 10, Total: 25, Mean: 2.5, StdDev: 0.5}
+	var parsed map[string]any
+	err := json.Unmarshal([]byte(summary.String()), &parsed)
+	assert.NoError(err)
+	assert.Equal(float64(10), parsed["calls"])
+	assert.Equal(0.5, parsed["stddev_ms"])
+}
@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// StatisticsType is the kind of statistics which are reset
+type StatisticsType string
+
+// StatisticsTarget selects the statistics to reset: the counters of a
+// database, the counters of a table in a database, or the statement
+// statistics of all databases or of one database
+type StatisticsTarget struct {
+	Type     StatisticsType `json:"type" help:"Statistics to reset (database, table, statements)"`
+	Database string         `json:"database,omitempty" help:"Database"`
+	Schema   string         `json:"schema,omitempty" help:"Schema of the table"`
+	Table    string         `json:"table,omitempty" help:"Table"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	StatisticsDatabase   StatisticsType = "database"
+	StatisticsTable      StatisticsType = "table"
+	StatisticsStatements StatisticsType = "statements"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (t StatisticsTarget) String() string {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Name returns the name of the target, which is the database, the qualified
+// table, or the statements with the database if there is one
+func (t StatisticsTarget) Name() string {
+	switch t.Type {
+	case StatisticsTable:
+		return strings.TrimSpace(t.Database) + "." + strings.TrimSpace(t.Schema) + "." + strings.TrimSpace(t.Table)
+	case StatisticsStatements:
+		if database := strings.TrimSpace(t.Database); database != "" {
+			return string(t.Type) + "." + database
+		}
+		return string(t.Type)
+	default:
+		return strings.TrimSpace(t.Database)
+	}
+}
+
+// Validate checks the type of the target, and that the database, schema
+// and table are set when they are required
+func (t StatisticsTarget) Validate() error {
+	switch t.Type {
+	case StatisticsDatabase:
+		if strings.TrimSpace(t.Database) == "" {
+			return pg.ErrBadParameter.With("database is required")
+		}
+	case StatisticsTable:
+		if strings.TrimSpace(t.Database) == "" {
+			return pg.ErrBadParameter.With("database is required")
+		} else if strings.TrimSpace(t.Schema) == "" {
+			return pg.ErrBadParameter.With("schema is required")
+		} else if strings.TrimSpace(t.Table) == "" {
+			return pg.ErrBadParameter.With("table is required")
+		}
+	case StatisticsStatements:
+		// The database is optional
+	default:
+		return pg.ErrBadParameter.Withf("invalid statistics type %q", t.Type)
+	}
+	if t.Type != StatisticsTable && (strings.TrimSpace(t.Schema) != "" || strings.TrimSpace(t.Table) != "") {
+		return pg.ErrBadParameter.Withf("schema and table cannot be set for %s statistics", t.Type)
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WRITER
+
+// Reset resets the statistics. The counters of a database or table are reset
+// with a connection to the database, and the statement statistics with a
+// connection to any database on the server.
+func (t StatisticsTarget) Reset(ctx context.Context, conn pg.Conn) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	switch t.Type {
+	case StatisticsDatabase:
+		return conn.Exec(ctx, statisticsResetDatabase)
+	case StatisticsTable:
+		return conn.With(
+			"schema", strings.TrimSpace(t.Schema),
+			"table", strings.TrimSpace(t.Table),
+		).Exec(ctx, statisticsResetTable)
+	default:
+		if database := strings.TrimSpace(t.Database); database != "" {
+			return conn.With("database", database).Exec(ctx, statisticsResetDatabaseStatements)
+		}
+		return conn.Exec(ctx, statisticsResetStatements)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+// The counters of a database and table are reset with a block, as a
+// statement which returns rows cannot be executed remotely
+const (
+	statisticsResetDatabase           = `DO $$ BEGIN PERFORM pg_stat_reset(); END $$`
+	statisticsResetTable              = `DO $$ BEGIN PERFORM pg_stat_reset_single_table_counters(C.oid) FROM pg_class C JOIN pg_namespace N ON N.oid = C.relnamespace WHERE N.nspname = ${'schema'} AND C.relname = ${'table'}; END $$`
+	statisticsResetStatements         = `SELECT public.pg_stat_statements_reset()`
+	statisticsResetDatabaseStatements = `SELECT public.pg_stat_statements_reset(0::OID, D.oid, 0::BIGINT) FROM pg_database D WHERE D.datname = @database`
+)
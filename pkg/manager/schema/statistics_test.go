@@ -0,0 +1,37 @@
+package schema_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_StatisticsTarget_Validate(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(schema.StatisticsTarget{Type: schema.StatisticsDatabase, Database: "test"}.Validate())
+		assert.NoError(schema.StatisticsTarget{Type: schema.StatisticsTable, Database: "test", Schema: "public", Table: "users"}.Validate())
+		assert.NoError(schema.StatisticsTarget{Type: schema.StatisticsStatements}.Validate())
+		assert.NoError(schema.StatisticsTarget{Type: schema.StatisticsStatements, Database: "test"}.Validate())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert.ErrorIs(schema.StatisticsTarget{}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.StatisticsTarget{Type: "cluster"}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.StatisticsTarget{Type: schema.StatisticsDatabase}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.StatisticsTarget{Type: schema.StatisticsTable, Database: "test", Table: "users"}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.StatisticsTarget{Type: schema.StatisticsTable, Database: "test", Schema: "public"}.Validate(), pg.ErrBadParameter)
+		assert.ErrorIs(schema.StatisticsTarget{Type: schema.StatisticsDatabase, Database: "test", Table: "users"}.Validate(), pg.ErrBadParameter)
+	})
+
+	t.Run("Name", func(t *testing.T) {
+		assert.Equal("test", schema.StatisticsTarget{Type: schema.StatisticsDatabase, Database: "test"}.Name())
+		assert.Equal("test.public.users", schema.StatisticsTarget{Type: schema.StatisticsTable, Database: "test", Schema: "public", Table: "users"}.Name())
+		assert.Equal("statements", schema.StatisticsTarget{Type: schema.StatisticsStatements}.Name())
+		assert.Equal("statements.test", schema.StatisticsTarget{Type: schema.StatisticsStatements, Database: "test"}.Name())
+	})
+}
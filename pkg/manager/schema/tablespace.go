@@ -7,7 +7,7 @@ import (
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
-	types "github.com/mutablelogic/go-pg/pkg/types"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -29,6 +29,28 @@ type Tablespace struct {
 	Size     uint64   `json:"bytes,omitempty" help:"Size of schema in bytes"`
 }
 
+// TablespaceLocation is the location of a tablespace, for which the space
+// on the filesystem is read
+type TablespaceLocation struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+}
+
+// TablespaceStat is the space on the filesystem which holds a tablespace,
+// as reported by df on the database host
+type TablespaceStat struct {
+	TablespaceLocation
+	Filesystem string `json:"filesystem,omitempty"`
+	Total      uint64 `json:"total_bytes"`
+	Used       uint64 `json:"used_bytes"`
+	Free       uint64 `json:"free_bytes"`
+}
+
+type TablespaceStatList struct {
+	Count uint64           `json:"count"`
+	Body  []TablespaceStat `json:"body,omitempty"`
+}
+
 type TablespaceListRequest struct {
 	pg.OffsetLimit
 }
@@ -57,6 +79,22 @@ func (t Tablespace) String() string {
 	return string(data)
 }
 
+func (t TablespaceStat) String() string {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (t TablespaceStatList) String() string {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
 func (t TablespaceListRequest) String() string {
 	data, err := json.MarshalIndent(t, "", "  ")
 	if err != nil {
@@ -77,15 +115,12 @@ func (t TablespaceList) String() string {
 // SELECT
 
 func (t TablespaceListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
-	// Order
-	bind.Set("orderby", `ORDER BY name ASC`)
+	// Where and order
+	pg.Where().OrderBy(`name`, true).Bind(bind)
 
 	// Bind offset and limit
 	t.OffsetLimit.Bind(bind, TablespaceListLimit)
 
-	// Where
-	bind.Set("where", ``)
-
 	// Return query
 	switch op {
 	case pg.List:
@@ -133,6 +168,24 @@ func (t TablespaceMeta) Select(bind *pg.Bind, op pg.Op) (string, error) {
 	}
 }
 
+func (t TablespaceLocation) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	// Set name and location
+	if name := strings.TrimSpace(t.Name); name == "" {
+		return "", pg.ErrBadParameter.With("tablespace name is missing")
+	} else {
+		bind.Set("name", name)
+	}
+	bind.Set("location", t.Location)
+
+	// Return query
+	switch op {
+	case pg.Get:
+		return tablespaceStatGet, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported TablespaceLocation operation %q", op)
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // READER
 
@@ -166,6 +219,10 @@ func (t *TablespaceList) ScanCount(row pg.Row) error {
 	return row.Scan(&t.Count)
 }
 
+func (t *TablespaceStat) Scan(row pg.Row) error {
+	return row.Scan(&t.Name, &t.Location, &t.Filesystem, &t.Total, &t.Used, &t.Free)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // WRITER
 
@@ -213,6 +270,26 @@ func (t TablespaceMeta) Update(bind *pg.Bind) error {
 	return nil
 }
 
+// Insert reads the space on the filesystem of the location into a temporary
+// table, with df run by the server, which requires the
+// pg_execute_server_program privilege
+func (t TablespaceLocation) Insert(bind *pg.Bind) (string, error) {
+	if location := strings.TrimSpace(t.Location); location == "" {
+		return "", pg.ErrBadParameter.With("location is missing")
+	} else if !filepath.IsAbs(location) {
+		return "", pg.ErrBadParameter.Withf("location %q is not absolute", location)
+	} else {
+		bind.Set("program", "df -Pk '"+strings.ReplaceAll(location, "'", `'\''`)+"'")
+	}
+
+	// Return success
+	return tablespaceStatCopy, nil
+}
+
+func (t TablespaceLocation) Update(bind *pg.Bind) error {
+	return pg.ErrNotImplemented.With("TablespaceLocation.Update")
+}
+
 func (t TablespaceName) Insert(bind *pg.Bind) (string, error) {
 	return "", pg.ErrNotImplemented.With("TablespaceName.Insert")
 }
@@ -239,9 +316,9 @@ func (t TablespaceMeta) with(insert bool) (string, error) {
 	// Owner
 	if owner := strings.TrimSpace(t.Owner); owner != "" {
 		if insert {
-			with = append(with, `OWNER `+types.DoubleQuote(owner))
+			with = append(with, `OWNER `+quote.Ident(owner))
 		} else {
-			with = append(with, `OWNER TO `+types.DoubleQuote(owner))
+			with = append(with, `OWNER TO `+quote.Ident(owner))
 		}
 	}
 
@@ -270,10 +347,26 @@ const (
 			JOIN
 				"pg_catalog"."pg_roles" R ON T.spcowner = R.oid			
 		) SELECT * FROM t`
-	tablespaceGet    = tablespaceSelect + ` WHERE "name" = ${'name'}`
+	tablespaceGet    = tablespaceSelect + ` WHERE "name" = @name`
 	tablespaceList   = `WITH q AS (` + tablespaceSelect + `) SELECT * FROM q ${where} ${orderby}`
 	tablespaceCreate = `CREATE TABLESPACE ${"name"} ${with} LOCATION ${'location'}`
 	tablespaceRename = `ALTER TABLESPACE ${"old_name"} RENAME TO ${"name"}`
 	tablespaceUpdate = `ALTER TABLESPACE ${"name"} ${with}`
 	tablespaceDelete = `DROP TABLESPACE ${"name"}`
+
+	// The output of df is read into the temporary table tablespace_df, which is
+	// emptied when the fields of the line after the header are returned. Sizes
+	// are in kilobytes.
+	tablespaceStatCopy = `COPY pg_temp.tablespace_df (line) FROM PROGRAM ${'program'}`
+	tablespaceStatGet  = `
+		WITH d AS (
+			DELETE FROM pg_temp.tablespace_df RETURNING regexp_split_to_array(trim(line), '\s+') AS "fields"
+		) SELECT
+			@name::TEXT AS "name", @location::TEXT AS "location", fields[1] AS "filesystem",
+			fields[2]::BIGINT * 1024 AS "total", fields[3]::BIGINT * 1024 AS "used", fields[4]::BIGINT * 1024 AS "free"
+		FROM
+			d
+		WHERE
+			fields[2] ~ '^[0-9]+$'
+	`
 )
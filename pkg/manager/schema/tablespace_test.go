@@ -313,3 +313,50 @@ func Test_TablespaceName_Update(t *testing.T) {
 		assert.ErrorIs(err, pg.ErrBadParameter)
 	})
 }
+
+func Test_TablespaceLocation_Insert(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Location", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.TablespaceLocation{Name: "ts", Location: "/var/lib/postgresql/tablespaces/ts"}.Insert(bind)
+		assert.NoError(err)
+		assert.Contains(sql, "FROM PROGRAM")
+		assert.Equal("df -Pk '/var/lib/postgresql/tablespaces/ts'", bind.Get("program"))
+	})
+
+	t.Run("QuotedLocation", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.TablespaceLocation{Name: "ts", Location: "/data/it's"}.Insert(bind)
+		assert.NoError(err)
+		assert.Equal(`df -Pk '/data/it'\''s'`, bind.Get("program"))
+	})
+
+	t.Run("RelativeLocation", func(t *testing.T) {
+		_, err := schema.TablespaceLocation{Name: "ts", Location: "data"}.Insert(pg.NewBind())
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
+func Test_TablespaceLocation_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Get", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.TablespaceLocation{Name: "ts", Location: "/data"}.Select(bind, pg.Get)
+		assert.NoError(err)
+		assert.Contains(sql, "tablespace_df")
+		assert.Equal("ts", bind.Get("name"))
+		assert.Equal("/data", bind.Get("location"))
+	})
+
+	t.Run("EmptyName", func(t *testing.T) {
+		_, err := schema.TablespaceLocation{Location: "/data"}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.TablespaceLocation{Name: "ts"}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
@@ -0,0 +1,237 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// TempUsageRequest selects the temporary files written in each database, and
+// the statements which wrote the most temporary files
+type TempUsageRequest struct {
+	Database *string `json:"database,omitempty" help:"Database"`
+	pg.OffsetLimit
+}
+
+// TempUsage is the temporary files written by queries which sorted or hashed
+// more data than fits in work_mem, since the statistics were reset. Queries
+// which write many temporary files may run faster with a larger work_mem.
+type TempUsage struct {
+	WorkMem       string               `json:"work_mem"`
+	TempFileLimit string               `json:"temp_file_limit"` // -1 when the size of temporary files is not limited
+	Databases     []DatabaseTempUsage  `json:"databases,omitempty"`
+	Statements    []StatementTempUsage `json:"statements,omitempty"` // Empty when pg_stat_statements is not installed
+}
+
+// DatabaseTempUsage is the temporary files written in a database, from
+// pg_stat_database
+type DatabaseTempUsage struct {
+	Database   string     `json:"database"`
+	Files      uint64     `json:"temp_files"`
+	Bytes      uint64     `json:"temp_bytes"`
+	StatsReset *time.Time `json:"stats_reset,omitempty"`
+}
+
+// DatabaseTempUsageListRequest selects the temporary files written in each
+// database
+type DatabaseTempUsageListRequest struct {
+	Database *string
+}
+
+// DatabaseTempUsageList is a list of databases with their temporary files
+type DatabaseTempUsageList struct {
+	Count uint64              `json:"count"`
+	Body  []DatabaseTempUsage `json:"body,omitempty"`
+}
+
+// StatementTempUsage is the temporary files written by a statement, from
+// pg_stat_statements
+type StatementTempUsage struct {
+	Role          string  `json:"role,omitempty"`
+	Database      string  `json:"database,omitempty"`
+	QueryID       int64   `json:"query_id"`
+	Query         string  `json:"query"`
+	Calls         int64   `json:"calls"`
+	BlocksRead    int64   `json:"temp_blks_read"`
+	BlocksWritten int64   `json:"temp_blks_written"`
+	Bytes         int64   `json:"temp_bytes"`      // Bytes of temporary files written
+	MeanBytes     float64 `json:"mean_temp_bytes"` // Bytes of temporary files written for each call
+}
+
+// StatementTempUsageListRequest selects the statements which wrote temporary
+// files, with the statement which wrote the most first
+type StatementTempUsageListRequest struct {
+	Database *string
+	pg.OffsetLimit
+}
+
+// StatementTempUsageList is a list of statements with their temporary files
+type StatementTempUsageList struct {
+	Count uint64               `json:"count"`
+	Body  []StatementTempUsage `json:"body,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (t TempUsage) String() string {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (t DatabaseTempUsage) String() string {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (t StatementTempUsage) String() string {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (t TempUsageRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	switch op {
+	case pg.Get:
+		return tempUsageGet, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported TempUsageRequest operation %q", op)
+	}
+}
+
+func (t DatabaseTempUsageListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	where := pg.Where()
+	if t.Database != nil {
+		if database := strings.TrimSpace(*t.Database); database != "" {
+			where.Eq(`database`, database)
+		}
+	}
+	where.Bind(bind)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return databaseTempUsageList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported DatabaseTempUsageListRequest operation %q", op)
+	}
+}
+
+func (t StatementTempUsageListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	where := pg.Where()
+	if t.Database != nil {
+		if database := strings.TrimSpace(*t.Database); database != "" {
+			where.Eq(`database`, database)
+		}
+	}
+	where.Bind(bind)
+
+	// Bind offset and limit
+	t.OffsetLimit.Bind(bind, TempUsageListLimit)
+
+	// Return query
+	switch op {
+	case pg.List:
+		return statementTempUsageList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported StatementTempUsageListRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (t *TempUsage) Scan(row pg.Row) error {
+	return row.Scan(&t.WorkMem, &t.TempFileLimit)
+}
+
+func (t *DatabaseTempUsage) Scan(row pg.Row) error {
+	return row.Scan(&t.Database, &t.Files, &t.Bytes, &t.StatsReset)
+}
+
+func (t *DatabaseTempUsageList) Scan(row pg.Row) error {
+	var usage DatabaseTempUsage
+	if err := usage.Scan(row); err != nil {
+		return err
+	}
+	t.Body = append(t.Body, usage)
+	return nil
+}
+
+func (t *DatabaseTempUsageList) ScanCount(row pg.Row) error {
+	return row.Scan(&t.Count)
+}
+
+func (t *StatementTempUsage) Scan(row pg.Row) error {
+	return row.Scan(&t.Role, &t.Database, &t.QueryID, &t.Query, &t.Calls, &t.BlocksRead, &t.BlocksWritten, &t.Bytes, &t.MeanBytes)
+}
+
+func (t *StatementTempUsageList) Scan(row pg.Row) error {
+	var usage StatementTempUsage
+	if err := usage.Scan(row); err != nil {
+		return err
+	}
+	t.Body = append(t.Body, usage)
+	return nil
+}
+
+func (t *StatementTempUsageList) ScanCount(row pg.Row) error {
+	return row.Scan(&t.Count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	tempUsageGet            = `SELECT current_setting('work_mem') AS "work_mem", current_setting('temp_file_limit') AS "temp_file_limit"`
+	databaseTempUsageSelect = `
+		SELECT
+			D.datname::TEXT AS "database",
+			D.temp_files AS "temp_files",
+			D.temp_bytes AS "temp_bytes",
+			D.stats_reset AS "stats_reset"
+		FROM
+			pg_stat_database D
+		WHERE
+			D.datname IS NOT NULL
+	`
+	databaseTempUsageList    = `WITH q AS (` + databaseTempUsageSelect + `) SELECT * FROM q ${where} ORDER BY "temp_bytes" DESC, "database"`
+	statementTempUsageSelect = `
+		SELECT
+			COALESCE(U.rolname, '')::TEXT AS "role",
+			COALESCE(D.datname, '')::TEXT AS "database",
+			S.queryid AS "query_id",
+			S.query AS "query",
+			S.calls AS "calls",
+			S.temp_blks_read AS "temp_blks_read",
+			S.temp_blks_written AS "temp_blks_written",
+			(S.temp_blks_written * current_setting('block_size')::BIGINT) AS "temp_bytes",
+			CASE WHEN S.calls > 0 THEN (S.temp_blks_written * current_setting('block_size')::BIGINT)::FLOAT8 / S.calls ELSE 0 END AS "mean_temp_bytes"
+		FROM
+			public.pg_stat_statements S
+		LEFT JOIN
+			pg_catalog.pg_roles U ON S.userid = U.oid
+		LEFT JOIN
+			pg_catalog.pg_database D ON S.dbid = D.oid
+		WHERE
+			S.temp_blks_written > 0 OR S.temp_blks_read > 0
+	`
+	statementTempUsageList = `WITH q AS (` + statementTempUsageSelect + `) SELECT * FROM q ${where} ORDER BY "temp_bytes" DESC, "database", "query_id", "role"`
+)
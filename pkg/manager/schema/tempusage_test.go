@@ -0,0 +1,96 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_TempUsageRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Get", func(t *testing.T) {
+		sql, err := schema.TempUsageRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.NoError(err)
+		assert.Contains(sql, "work_mem")
+		assert.Contains(sql, "temp_file_limit")
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.TempUsageRequest{}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_DatabaseTempUsageListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.DatabaseTempUsageListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_stat_database")
+		assert.Contains(sql, "temp_bytes")
+		assert.Equal("", bind.Get("where"))
+	})
+
+	t.Run("Database", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.DatabaseTempUsageListRequest{Database: types.StringPtr(" test ")}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(bind.Get("where"), "database")
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.DatabaseTempUsageListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_StatementTempUsageListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.StatementTempUsageListRequest{}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "pg_stat_statements")
+		assert.Contains(sql, "temp_blks_written")
+		assert.Contains(bind.Get("offsetlimit"), "LIMIT 100")
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.StatementTempUsageListRequest{OffsetLimit: pg.OffsetLimit{Limit: types.Uint64Ptr(5)}}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(bind.Get("offsetlimit"), "LIMIT 5")
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.StatementTempUsageListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_TempUsage_String(t *testing.T) {
+	assert := assert.New(t)
+
+	usage := schema.TempUsage{
+		WorkMem:       "4MB",
+		TempFileLimit: "-1",
+		Databases:     []schema.DatabaseTempUsage{{Database: "test", Files: 2, Bytes: 16384}},
+	}
+	var parsed map[string]any
+	err := json.Unmarshal([]byte(usage.String()), &parsed)
+	assert.NoError(err)
+	assert.Equal("4MB", parsed["work_mem"])
+	assert.NotContains(parsed, "statements")
+	if databases, ok := parsed["databases"].([]any); assert.True(ok) && assert.Len(databases, 1) {
+		assert.Equal(float64(16384), databases[0].(map[string]any)["temp_bytes"])
+	}
+}
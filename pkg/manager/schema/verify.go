@@ -0,0 +1,298 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ChecksumListRequest selects the data checksum failures of each database,
+// from pg_stat_database
+type ChecksumListRequest struct{}
+
+// DatabaseChecksums is the number of data checksum failures detected when
+// pages of a database were read, which is always zero when data checksums
+// are not enabled on the server
+type DatabaseChecksums struct {
+	Database    string     `json:"database"`
+	Enabled     bool       `json:"enabled"` // True if data checksums are enabled on the server
+	Failures    uint64     `json:"failures"`
+	LastFailure *time.Time `json:"last_failure,omitempty"`
+}
+
+// ChecksumList is a list of databases with their checksum failures
+type ChecksumList struct {
+	Count uint64              `json:"count"`
+	Body  []DatabaseChecksums `json:"body,omitempty"`
+}
+
+// ObjectVerifyRequest checks a table, materialized view or btree index for
+// corruption with the amcheck extension
+type ObjectVerifyRequest struct {
+	HeapAllIndexed bool `json:"heap_all_indexed,omitempty" name:"heap-all-indexed" help:"Check every row of the table has an entry in each index, which is slower"`
+}
+
+// ObjectVerification is the result of checking an object for corruption,
+// with the relations which were checked and the corruption which was found
+type ObjectVerification struct {
+	Database  string              `json:"database"`
+	Schema    string              `json:"schema"`
+	Name      string              `json:"name"`
+	Type      string              `json:"type"`
+	Checked   []string            `json:"checked"` // The table and indexes which were checked
+	Corrupt   bool                `json:"corrupt"`
+	Findings  []CorruptionFinding `json:"findings,omitempty"`
+	Checksums *DatabaseChecksums  `json:"checksums,omitempty"` // The checksum failures of the database
+}
+
+// CorruptionFinding is corruption found in a relation. The block, offset
+// and attribute are set for corruption found in a table
+type CorruptionFinding struct {
+	Relation  string `json:"relation"`
+	Block     *int64 `json:"block,omitempty"`
+	Offset    *int32 `json:"offset,omitempty"`
+	Attribute *int32 `json:"attribute,omitempty"`
+	SQLState  string `json:"sqlstate,omitempty"` // The error raised for corruption found in an index
+	Message   string `json:"message"`
+}
+
+// HeapVerifyRequest checks the rows of a table or materialized view with
+// verify_heapam, which returns a row for each corruption found
+type HeapVerifyRequest ObjectName
+
+// IndexVerifyListRequest selects the valid btree indexes of a table or
+// materialized view, or the index itself when the object is a btree index
+type IndexVerifyListRequest ObjectName
+
+// IndexVerifyRequest checks a btree index with bt_index_check, which raises
+// an error when corruption is found
+type IndexVerifyRequest struct {
+	ObjectName
+	HeapAllIndexed bool
+}
+
+// IndexVerifyList is a list of the indexes to check
+type IndexVerifyList []ObjectName
+
+// IndexVerified is the name of an index which has been checked
+type IndexVerified string
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// AmcheckExtension is the name of the extension which checks relations
+	// for corruption
+	AmcheckExtension = "amcheck"
+
+	// SQLSTATE codes raised when corruption is found
+	SQLStateDataCorrupted  = "XX001"
+	SQLStateIndexCorrupted = "XX002"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (c DatabaseChecksums) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (c ChecksumList) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (v ObjectVerification) String() string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func (f CorruptionFinding) String() string {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SELECT
+
+func (c ChecksumListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	switch op {
+	case pg.List:
+		return checksumList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported ChecksumListRequest operation %q", op)
+	}
+}
+
+func (h HeapVerifyRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	if err := ObjectName(h).Validate(); err != nil {
+		return "", err
+	}
+	bind.Set("schema", strings.TrimSpace(h.Schema))
+	bind.Set("name", strings.TrimSpace(h.Name))
+
+	// Return query
+	switch op {
+	case pg.List:
+		return heapVerifyList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported HeapVerifyRequest operation %q", op)
+	}
+}
+
+func (i IndexVerifyListRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	if err := ObjectName(i).Validate(); err != nil {
+		return "", err
+	}
+	bind.Set("schema", strings.TrimSpace(i.Schema))
+	bind.Set("name", strings.TrimSpace(i.Name))
+
+	// Return query
+	switch op {
+	case pg.List:
+		return indexVerifyList, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported IndexVerifyListRequest operation %q", op)
+	}
+}
+
+func (i IndexVerifyRequest) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	if err := i.ObjectName.Validate(); err != nil {
+		return "", err
+	}
+	bind.Set("schema", strings.TrimSpace(i.Schema))
+	bind.Set("name", strings.TrimSpace(i.Name))
+	if i.HeapAllIndexed {
+		bind.Set("heapallindexed", "true")
+	} else {
+		bind.Set("heapallindexed", "false")
+	}
+
+	// Return query
+	switch op {
+	case pg.Get:
+		return indexVerify, nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported IndexVerifyRequest operation %q", op)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READER
+
+func (c *DatabaseChecksums) Scan(row pg.Row) error {
+	return row.Scan(&c.Database, &c.Enabled, &c.Failures, &c.LastFailure)
+}
+
+func (c *ChecksumList) Scan(row pg.Row) error {
+	var checksums DatabaseChecksums
+	if err := checksums.Scan(row); err != nil {
+		return err
+	}
+	c.Body = append(c.Body, checksums)
+	return nil
+}
+
+func (c *ChecksumList) ScanCount(row pg.Row) error {
+	return row.Scan(&c.Count)
+}
+
+// Scan appends the corruption found in a table
+func (v *ObjectVerification) Scan(row pg.Row) error {
+	var finding CorruptionFinding
+	if err := row.Scan(&finding.Relation, &finding.Block, &finding.Offset, &finding.Attribute, &finding.Message); err != nil {
+		return err
+	}
+	v.Findings = append(v.Findings, finding)
+	v.Corrupt = true
+	return nil
+}
+
+func (l *IndexVerifyList) Scan(row pg.Row) error {
+	var index ObjectName
+	if err := row.Scan(&index.Schema, &index.Name); err != nil {
+		return err
+	}
+	*l = append(*l, index)
+	return nil
+}
+
+func (i *IndexVerified) Scan(row pg.Row) error {
+	return row.Scan((*string)(i))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SQL
+
+const (
+	HeapVerifyDef   = `heapverify ("relation" TEXT, "block" BIGINT, "offset" INTEGER, "attribute" INTEGER, "message" TEXT)`
+	IndexVerifyDef  = `indexverify ("schema" TEXT, "name" TEXT)`
+	IndexCheckedDef = `indexchecked ("relation" TEXT)`
+	checksumList    = `
+		SELECT
+			D.datname::TEXT AS "database",
+			current_setting('data_checksums')::BOOLEAN AS "enabled",
+			COALESCE(D.checksum_failures, 0)::BIGINT AS "failures",
+			D.checksum_last_failure AS "last_failure"
+		FROM
+			pg_stat_database D
+		WHERE
+			D.datname IS NOT NULL
+		ORDER BY
+			D.datname
+	`
+	heapVerifyList = `
+		SELECT
+			format('%I.%I', ${'schema'}, ${'name'}) AS "relation",
+			H.blkno::BIGINT AS "block",
+			H.offnum::INTEGER AS "offset",
+			H.attnum::INTEGER AS "attribute",
+			H.msg::TEXT AS "message"
+		FROM
+			verify_heapam(format('%I.%I', ${'schema'}, ${'name'})::REGCLASS, check_toast => true) H
+	`
+	indexVerifyList = `
+		SELECT
+			N.nspname::TEXT AS "schema",
+			C.relname::TEXT AS "name"
+		FROM
+			pg_index I
+		JOIN
+			pg_class C ON C.oid = I.indexrelid
+		JOIN
+			pg_namespace N ON N.oid = C.relnamespace
+		JOIN
+			pg_am AM ON AM.oid = C.relam
+		JOIN
+			pg_class T ON T.oid = I.indrelid
+		JOIN
+			pg_namespace TN ON TN.oid = T.relnamespace
+		WHERE
+			AM.amname = 'btree' AND I.indisvalid AND I.indisready AND (
+				(TN.nspname = ${'schema'} AND T.relname = ${'name'}) OR
+				(N.nspname = ${'schema'} AND C.relname = ${'name'})
+			)
+		ORDER BY
+			C.relname
+	`
+	indexVerify = `SELECT format('%I.%I', ${'schema'}, ${'name'}) AS "relation" FROM bt_index_check(format('%I.%I', ${'schema'}, ${'name'})::REGCLASS, ${heapallindexed})`
+)
@@ -0,0 +1,111 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_ChecksumListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		sql, err := schema.ChecksumListRequest{}.Select(pg.NewBind(), pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "checksum_failures")
+		assert.Contains(sql, "data_checksums")
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.ChecksumListRequest{}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_HeapVerifyRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.HeapVerifyRequest{Schema: " public ", Name: "users"}.Select(bind, pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "verify_heapam")
+		assert.Equal("public", bind.Get("schema"))
+		assert.Equal("users", bind.Get("name"))
+	})
+
+	t.Run("MissingName", func(t *testing.T) {
+		_, err := schema.HeapVerifyRequest{Schema: "public"}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.HeapVerifyRequest{Schema: "public", Name: "users"}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_IndexVerifyListRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		sql, err := schema.IndexVerifyListRequest{Schema: "public", Name: "users"}.Select(pg.NewBind(), pg.List)
+		assert.NoError(err)
+		assert.Contains(sql, "btree")
+		assert.Contains(sql, "indisvalid")
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.IndexVerifyListRequest{Schema: "public", Name: "users"}.Select(pg.NewBind(), pg.Get)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_IndexVerifyRequest_Select(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Get", func(t *testing.T) {
+		bind := pg.NewBind()
+		sql, err := schema.IndexVerifyRequest{ObjectName: schema.ObjectName{Schema: "public", Name: "users_pkey"}}.Select(bind, pg.Get)
+		assert.NoError(err)
+		assert.Contains(sql, "bt_index_check")
+		assert.Equal("false", bind.Get("heapallindexed"))
+	})
+
+	t.Run("HeapAllIndexed", func(t *testing.T) {
+		bind := pg.NewBind()
+		_, err := schema.IndexVerifyRequest{ObjectName: schema.ObjectName{Schema: "public", Name: "users_pkey"}, HeapAllIndexed: true}.Select(bind, pg.Get)
+		assert.NoError(err)
+		assert.Equal("true", bind.Get("heapallindexed"))
+	})
+
+	t.Run("UnsupportedOperation", func(t *testing.T) {
+		_, err := schema.IndexVerifyRequest{ObjectName: schema.ObjectName{Schema: "public", Name: "users_pkey"}}.Select(pg.NewBind(), pg.List)
+		assert.ErrorIs(err, pg.ErrNotImplemented)
+	})
+}
+
+func Test_ObjectVerification_String(t *testing.T) {
+	assert := assert.New(t)
+
+	block := int64(3)
+	verification := schema.ObjectVerification{
+		Database: "test", Schema: "public", Name: "users", Type: "TABLE",
+		Checked:  []string{"public.users", "public.users_pkey"},
+		Corrupt:  true,
+		Findings: []schema.CorruptionFinding{{Relation: "public.users", Block: &block, Message: "line pointer redirection to item at offset 0 precedes minimum offset 1"}},
+	}
+	var parsed map[string]any
+	err := json.Unmarshal([]byte(verification.String()), &parsed)
+	assert.NoError(err)
+	assert.Equal(true, parsed["corrupt"])
+	assert.NotContains(parsed, "checksums")
+	if findings, ok := parsed["findings"].([]any); assert.True(ok) && assert.Len(findings, 1) {
+		assert.Equal(float64(3), findings[0].(map[string]any)["block"])
+		assert.NotContains(findings[0].(map[string]any), "sqlstate")
+	}
+}
@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Version is the version of the manager, and the versions of the REST API
+// which it serves, so that clients can check they are compatible
+type Version struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Commit      string `json:"commit,omitempty"`
+	APIVersions []uint `json:"api_versions"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (v Version) String() string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Supports returns true if the version of the REST API is served
+func (v Version) Supports(api uint) bool {
+	return slices.Contains(v.APIVersions, api)
+}
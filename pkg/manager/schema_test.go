@@ -87,6 +87,33 @@ func Test_Manager_ListSchemas(t *testing.T) {
 ////////////////////////////////////////////////////////////////////////////////
 // GET SCHEMA TESTS
 
+func Test_Manager_ListSchemaRollups(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("ListByDatabase", func(t *testing.T) {
+		dbName := "postgres"
+		rollups, err := mgr.ListSchemaRollups(context.TODO(), schema.SchemaListRequest{
+			Database: &dbName,
+		})
+		if assert.NoError(err) {
+			assert.GreaterOrEqual(rollups.Count, uint64(1))
+			var total uint64
+			for _, s := range rollups.Body {
+				assert.Equal(dbName, s.Database)
+				total += s.Size
+			}
+			assert.Equal(total, rollups.Size)
+		}
+	})
+}
+
 func Test_Manager_GetSchema(t *testing.T) {
 	assert := assert.New(t)
 	conn := conn.Begin(t)
@@ -0,0 +1,37 @@
+package manager
+
+import (
+	"context"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - SERVER
+
+// GetServer returns the metadata for the connected server, including whether
+// the server is in recovery (a standby), its system identifier and timeline.
+func (manager *Manager) GetServer(ctx context.Context) (*schema.Server, error) {
+	var server schema.Server
+	if err := manager.conn.Get(ctx, &server, schema.ServerRequest{}); err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// writable returns ErrReadOnly if the connected server is a standby, so that
+// mutating operations are refused before they reach the server
+func (manager *Manager) writable(ctx context.Context) error {
+	server, err := manager.GetServer(ctx)
+	if err != nil {
+		return err
+	} else if server.InRecovery {
+		return pg.ErrReadOnly.Withf("server is a standby on timeline %d", server.Timeline)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GET SERVER TESTS
+
+func Test_Manager_GetServer(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Primary", func(t *testing.T) {
+		server, err := mgr.GetServer(context.TODO())
+		if assert.NoError(err) {
+			assert.NotEmpty(server.Version)
+			assert.False(server.InRecovery)
+			assert.NotEmpty(server.SystemIdentifier)
+			assert.GreaterOrEqual(server.Timeline, uint32(1))
+		}
+	})
+}
@@ -2,10 +2,13 @@ package manager
 
 import (
 	"context"
+	"maps"
+	"slices"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -39,11 +42,14 @@ func (manager *Manager) GetSetting(ctx context.Context, name string) (*schema.Se
 }
 
 // UpdateSetting updates a setting value. If meta.Value is nil, the setting is reset to default.
+// The change is recorded in the setting history, unless the server is a standby.
 // Returns the updated setting. Check the Context field to determine if ReloadConfig() or a
 // server restart is needed for the change to take effect.
 // Returns an error for settings with 'internal' context (cannot be changed) or
 // 'postmaster' context (requires server restart, not supported via API).
-func (manager *Manager) UpdateSetting(ctx context.Context, name string, meta schema.SettingMeta) (*schema.Setting, error) {
+func (manager *Manager) UpdateSetting(ctx context.Context, name string, meta schema.SettingMeta) (_ *schema.Setting, err error) {
+	defer manager.audit(ctx, schema.AuditUpdate, "setting", name, meta, &err)
+
 	// First get the current setting to check its context
 	current, err := manager.GetSetting(ctx, name)
 	if err != nil {
@@ -58,8 +64,8 @@ func (manager *Manager) UpdateSetting(ctx context.Context, name string, meta sch
 		return nil, pg.ErrBadParameter.Withf("setting %q requires server restart (postmaster context)", name)
 	}
 
-	// Update the setting (ALTER SYSTEM doesn't return rows, so pass nil reader)
-	if err := manager.conn.Update(ctx, nil, schema.SettingName(name), meta); err != nil {
+	// Update the setting
+	if err := manager.alterSystem(ctx, current, meta); err != nil {
 		return nil, err
 	}
 
@@ -67,8 +73,108 @@ func (manager *Manager) UpdateSetting(ctx context.Context, name string, meta sch
 	return manager.GetSetting(ctx, name)
 }
 
+// SetSystemSetting writes a setting to postgresql.auto.conf with ALTER
+// SYSTEM SET, or removes it with ALTER SYSTEM RESET when meta.Value is nil.
+// The value is validated against the type, allowed values, unit and range
+// of the setting. Unlike UpdateSetting, settings with 'postmaster' context
+// can be written, and are pending a restart. When reload is true, the
+// configuration is reloaded so that the value takes effect, except for
+// settings which require a restart. Returns the setting, and an error for
+// settings with 'internal' context.
+func (manager *Manager) SetSystemSetting(ctx context.Context, name string, meta schema.SettingMeta, reload bool) (_ *schema.Setting, err error) {
+	defer manager.audit(ctx, schema.AuditUpdate, "setting", name, meta, &err)
+
+	// Get the current setting, to validate the value
+	current, err := manager.GetSetting(ctx, name)
+	if err != nil {
+		return nil, err
+	} else if current.Context == "internal" {
+		return nil, pg.ErrBadParameter.Withf("setting %q cannot be changed (internal)", name)
+	} else if meta.Value != nil {
+		if err := current.Validate(*meta.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update the setting
+	if err := manager.alterSystem(ctx, current, meta); err != nil {
+		return nil, err
+	}
+
+	// Reload the configuration
+	if reload && current.Context != "postmaster" {
+		if err := manager.ReloadConfig(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get and return the updated setting
+	return manager.GetSetting(ctx, name)
+}
+
+// ListPendingSettings returns the settings which differ from the value when
+// the server was started, or which have been changed in the configuration
+// files but require a restart to take effect.
+func (manager *Manager) ListPendingSettings(ctx context.Context, req schema.SettingListRequest) (*schema.SettingList, error) {
+	req.Pending = types.BoolPtr(true)
+	return manager.ListSettings(ctx, req)
+}
+
+// DiffSettings compares the settings with the desired values, and returns
+// the ALTER SYSTEM statements needed for them to converge, ordered by
+// setting name. Nothing is changed. Returns an error if a setting does not
+// exist or cannot be changed (internal context).
+func (manager *Manager) DiffSettings(ctx context.Context, desired map[string]string) (*schema.SettingDiff, error) {
+	diff := schema.SettingDiff{Body: []schema.SettingChange{}}
+	for _, name := range slices.Sorted(maps.Keys(desired)) {
+		setting, err := manager.GetSetting(ctx, name)
+		if err != nil {
+			return nil, err
+		} else if setting.Context == "internal" {
+			return nil, pg.ErrBadParameter.Withf("setting %q cannot be changed (internal)", name)
+		}
+		if change := setting.Change(desired[name]); change != nil {
+			diff.Body = append(diff.Body, *change)
+		}
+	}
+	diff.Count = uint64(len(diff.Body))
+	return &diff, nil
+}
+
+// ListSettingHistory returns the changes made to settings with UpdateSetting,
+// most recent first, optionally filtered by setting name.
+func (manager *Manager) ListSettingHistory(ctx context.Context, req schema.SettingHistoryListRequest) (*schema.SettingHistoryList, error) {
+	var list schema.SettingHistoryList
+	if err := manager.conn.List(ctx, &list, req); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
 // ReloadConfig calls pg_reload_conf() to reload server configuration.
 // This applies changes to settings with 'sighup' context without requiring a restart.
 func (manager *Manager) ReloadConfig(ctx context.Context) error {
 	return manager.conn.Exec(ctx, "SELECT pg_reload_conf()")
 }
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Write a setting with ALTER SYSTEM, and record the change in the history,
+// which cannot be written on a standby
+func (manager *Manager) alterSystem(ctx context.Context, current *schema.Setting, meta schema.SettingMeta) error {
+	// ALTER SYSTEM doesn't return rows, so pass nil reader
+	if err := manager.conn.Update(ctx, nil, schema.SettingName(current.Name), meta); err != nil {
+		return err
+	}
+	if err := manager.writable(ctx); err == nil {
+		if err := manager.conn.Insert(ctx, nil, schema.SettingHistoryMeta{
+			Name:          current.Name,
+			PreviousValue: current.Value,
+			Value:         meta.Value,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
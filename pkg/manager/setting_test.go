@@ -8,6 +8,7 @@ import (
 	pg "github.com/mutablelogic/go-pg"
 	manager "github.com/mutablelogic/go-pg/pkg/manager"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
 	assert "github.com/stretchr/testify/assert"
 )
 
@@ -293,6 +294,25 @@ func Test_Manager_UpdateSetting(t *testing.T) {
 		assert.Equal("log_min_duration_statement", setting.Name)
 	})
 
+	t.Run("UpdateRecordsHistory", func(t *testing.T) {
+		newValue := "250"
+		_, err := mgr.UpdateSetting(context.TODO(), "log_min_duration_statement", schema.SettingMeta{
+			Value: &newValue,
+		})
+		if !assert.NoError(err) {
+			return
+		}
+
+		name := "log_min_duration_statement"
+		history, err := mgr.ListSettingHistory(context.TODO(), schema.SettingHistoryListRequest{Name: &name})
+		if assert.NoError(err) && assert.NotEmpty(history.Body) {
+			assert.Equal(name, history.Body[0].Name)
+			if assert.NotNil(history.Body[0].Value) {
+				assert.Equal(newValue, *history.Body[0].Value)
+			}
+		}
+	})
+
 	t.Run("UpdateNotFound", func(t *testing.T) {
 		newValue := "100"
 		setting, err := mgr.UpdateSetting(context.TODO(), "nonexistent_setting_xyz", schema.SettingMeta{
@@ -325,6 +345,60 @@ func Test_Manager_UpdateSetting(t *testing.T) {
 	})
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// SET SYSTEM SETTING TESTS
+
+func Test_Manager_SetSystemSetting(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("SetAndReload", func(t *testing.T) {
+		newValue := "1s"
+		setting, err := mgr.SetSystemSetting(context.TODO(), "log_min_duration_statement", schema.SettingMeta{Value: &newValue}, true)
+		if assert.NoError(err) {
+			assert.Equal("1000", types.PtrString(setting.Value))
+		}
+		setting, err = mgr.SetSystemSetting(context.TODO(), "log_min_duration_statement", schema.SettingMeta{}, true)
+		if assert.NoError(err) {
+			assert.Equal(types.PtrString(setting.BootValue), types.PtrString(setting.Value))
+		}
+	})
+
+	t.Run("SetPostmasterSetting", func(t *testing.T) {
+		current, err := mgr.GetSetting(context.TODO(), "max_connections")
+		if !assert.NoError(err) {
+			return
+		}
+		_, err = mgr.SetSystemSetting(context.TODO(), "max_connections", schema.SettingMeta{Value: current.Value}, false)
+		assert.NoError(err)
+		_, err = mgr.SetSystemSetting(context.TODO(), "max_connections", schema.SettingMeta{}, false)
+		assert.NoError(err)
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		for name, value := range map[string]string{
+			"wal_level":                  "archive",
+			"log_min_duration_statement": "1GB",
+			"fsync":                      "maybe",
+		} {
+			_, err := mgr.SetSystemSetting(context.TODO(), name, schema.SettingMeta{Value: &value}, false)
+			assert.ErrorIs(err, pg.ErrBadParameter, name)
+		}
+	})
+
+	t.Run("InternalSetting", func(t *testing.T) {
+		value := "16384"
+		_, err := mgr.SetSystemSetting(context.TODO(), "block_size", schema.SettingMeta{Value: &value}, false)
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // RELOAD CONFIG TESTS
 
@@ -343,3 +417,52 @@ func Test_Manager_ReloadConfig(t *testing.T) {
 		assert.NoError(err)
 	})
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// PENDING AND DIFF TESTS
+
+func Test_Manager_DiffSettings(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("ListPending", func(t *testing.T) {
+		settings, err := mgr.ListPendingSettings(context.TODO(), schema.SettingListRequest{})
+		if assert.NoError(err) {
+			for _, setting := range settings.Body {
+				assert.True(setting.PendingRestart || !setting.Equal(types.PtrString(setting.BootValue)), setting.Name)
+			}
+		}
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		current, err := mgr.GetSetting(context.TODO(), "work_mem")
+		if !assert.NoError(err) {
+			return
+		}
+		diff, err := mgr.DiffSettings(context.TODO(), map[string]string{
+			"work_mem":        types.PtrString(current.Value) + "kB",
+			"max_connections": "1000",
+		})
+		if assert.NoError(err) {
+			assert.Equal(uint64(1), diff.Count)
+			assert.Equal("max_connections", diff.Body[0].Name)
+			assert.Equal("postmaster", diff.Body[0].Context)
+		}
+	})
+
+	t.Run("DiffUnknownSetting", func(t *testing.T) {
+		_, err := mgr.DiffSettings(context.TODO(), map[string]string{"not_a_setting": "1"})
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+
+	t.Run("DiffInternalSetting", func(t *testing.T) {
+		_, err := mgr.DiffSettings(context.TODO(), map[string]string{"block_size": "16384"})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
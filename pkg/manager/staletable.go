@@ -0,0 +1,93 @@
+package manager
+
+import (
+	"context"
+	"strings"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - STALE TABLES
+
+// ListStaleTables returns tables across all databases which have had no reads
+// or writes since the statistics were reset, and no autovacuum or autoanalyze
+// activity within the requested number of days. These are candidates for
+// archiving. If Database is specified in the request, only tables from that
+// database are returned.
+func (manager *Manager) ListStaleTables(ctx context.Context, req schema.StaleTableListRequest) (*schema.StaleTableList, error) {
+	var list schema.StaleTableList
+	var offset, limit uint64
+
+	// Set limit lower if request limit is lower
+	limit = schema.StaleTableListLimit
+	if req.Limit != nil && types.PtrUint64(req.Limit) < limit {
+		limit = types.PtrUint64(req.Limit)
+	}
+
+	// Allocate the body with capacity
+	list.Body = make([]schema.StaleTable, 0, limit)
+
+	// Iterate through all the databases
+	if _, err := manager.withDatabases(ctx, func(database *schema.Database) error {
+		// Filter by database
+		if name := strings.TrimSpace(types.PtrString(req.Database)); name != "" && name != database.Name {
+			return nil
+		}
+
+		// Iterate through all the stale tables
+		count, err := manager.withStaleTables(ctx, database.Name, req, func(table *schema.StaleTable) error {
+			if offset >= req.Offset && uint64(len(list.Body)) < limit {
+				list.Body = append(list.Body, *table)
+			}
+			offset++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Increment the count
+		list.Count += count
+
+		// Return success
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return &list, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Iterate through all the stale tables for a database
+func (manager *Manager) withStaleTables(ctx context.Context, database string, req schema.StaleTableListRequest, fn func(table *schema.StaleTable) error) (uint64, error) {
+	req.Offset = 0
+	req.Limit = types.Uint64Ptr(schema.StaleTableListLimit)
+
+	for {
+		var list schema.StaleTableList
+		if err := manager.conn.Remote(database).With("as", schema.StaleTableDef).List(ctx, &list, &req); err != nil {
+			return 0, err
+		}
+
+		for _, table := range list.Body {
+			if err := fn(&table); err != nil {
+				return 0, err
+			}
+		}
+
+		// Determine if the next page is over the count
+		next := req.Offset + types.PtrUint64(req.Limit)
+		if next >= list.Count {
+			return list.Count, nil
+		} else {
+			req.Offset = next
+		}
+	}
+}
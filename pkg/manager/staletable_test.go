@@ -0,0 +1,44 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIST STALE TABLES TESTS
+
+func Test_Manager_ListStaleTables(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("ListAll", func(t *testing.T) {
+		tables, err := mgr.ListStaleTables(context.TODO(), schema.StaleTableListRequest{})
+		if assert.NoError(err) {
+			assert.Equal(len(tables.Body), int(tables.Count))
+		}
+	})
+
+	t.Run("ListByDatabase", func(t *testing.T) {
+		dbName := "postgres"
+		tables, err := mgr.ListStaleTables(context.TODO(), schema.StaleTableListRequest{
+			Database: &dbName,
+		})
+		if assert.NoError(err) {
+			for _, table := range tables.Body {
+				assert.Equal(dbName, table.Database)
+			}
+		}
+	})
+}
@@ -25,6 +25,12 @@ func (manager *Manager) ListStatements(ctx context.Context, req schema.Statement
 		return nil, err
 	}
 
+	// Set the token for the next page, when in the default order
+	if n := len(list.Body); n > 0 && req.Sort == "" {
+		last := list.Body[n-1]
+		list.Next = req.Next(req.Offset, uint64(n), list.Count, last.Database, last.QueryID, last.Role)
+	}
+
 	return &list, nil
 }
 
@@ -39,6 +45,30 @@ func (manager *Manager) ResetStatements(ctx context.Context) error {
 	return manager.conn.Exec(ctx, statementReset)
 }
 
+// ListStatementSummaries returns the statistics of the statements executed
+// by each role in each database, with the most time spent executing
+// statements first. Returns ErrNotAvailable if pg_stat_statements is not
+// installed.
+func (manager *Manager) ListStatementSummaries(ctx context.Context, req schema.StatementSummaryListRequest) (*schema.StatementSummaryList, error) {
+	if !manager.statStatementsAvailable {
+		return nil, pg.ErrNotAvailable.With("pg_stat_statements")
+	}
+
+	// Execute query
+	var list schema.StatementSummaryList
+	if err := manager.conn.List(ctx, &list, req); err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// StatementMetrics returns the number of role and database pairs for which
+// statement metrics are exported, which is set with WithStatementMetrics
+func (manager *Manager) StatementMetrics() uint {
+	return manager.opt.statements
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // PRIVATE CONSTANTS
 
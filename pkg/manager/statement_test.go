@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	// Packages
+	pg "github.com/mutablelogic/go-pg"
 	manager "github.com/mutablelogic/go-pg/pkg/manager"
 	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
 	assert "github.com/stretchr/testify/assert"
@@ -62,6 +63,61 @@ func Test_Manager_ListStatements(t *testing.T) {
 	})
 }
 
+func Test_Manager_ListStatementSummaries(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("DefaultMetrics", func(t *testing.T) {
+		assert.Equal(uint(10), mgr.StatementMetrics())
+	})
+
+	t.Run("ListAll", func(t *testing.T) {
+		list, err := mgr.ListStatementSummaries(context.TODO(), schema.StatementSummaryListRequest{})
+		if assert.NoError(err) && assert.NotEmpty(list.Body) {
+			for i, summary := range list.Body {
+				assert.NotZero(summary.Statements)
+				assert.LessOrEqual(summary.Min, summary.Max)
+				assert.GreaterOrEqual(summary.StdDev, float64(0))
+				if i > 0 {
+					assert.GreaterOrEqual(list.Body[i-1].Total, summary.Total)
+				}
+			}
+		}
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		limit := uint64(1)
+		list, err := mgr.ListStatementSummaries(context.TODO(), schema.StatementSummaryListRequest{OffsetLimit: pg.OffsetLimit{Limit: &limit}})
+		if assert.NoError(err) {
+			assert.LessOrEqual(len(list.Body), 1)
+		}
+	})
+}
+
+func Test_Manager_WithStatementMetrics(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	t.Run("Disabled", func(t *testing.T) {
+		mgr, err := manager.New(context.TODO(), conn, manager.WithStatementMetrics(0))
+		if assert.NoError(err) {
+			assert.Zero(mgr.StatementMetrics())
+		}
+	})
+
+	t.Run("TooMany", func(t *testing.T) {
+		_, err := manager.New(context.TODO(), conn, manager.WithStatementMetrics(schema.StatementListLimit+1))
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+}
+
 func Test_Manager_ResetStatements(t *testing.T) {
 	assert := assert.New(t)
 	conn := conn.Begin(t)
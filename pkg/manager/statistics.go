@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"context"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ResetStatistics resets the counters of a database or of a table, with
+// pg_stat_reset or pg_stat_reset_single_table_counters, or the statement
+// statistics of all databases or of one database, with pg_stat_statements_reset.
+// Returns ErrNotFound if the database or table does not exist, and
+// ErrNotAvailable when resetting statements if pg_stat_statements is not
+// installed.
+func (manager *Manager) ResetStatistics(ctx context.Context, target schema.StatisticsTarget) (err error) {
+	if err := target.Validate(); err != nil {
+		return err
+	}
+	defer manager.audit(ctx, schema.AuditDelete, "statistics", target.Name(), target, &err)
+
+	// Check the database exists
+	database := strings.TrimSpace(target.Database)
+	if database != "" {
+		if _, err := manager.GetDatabase(ctx, database); err != nil {
+			return err
+		}
+	}
+
+	// Reset the statistics
+	switch target.Type {
+	case schema.StatisticsStatements:
+		if !manager.statStatementsAvailable {
+			return pg.ErrNotAvailable.With("pg_stat_statements")
+		}
+		return target.Reset(ctx, manager.conn)
+	case schema.StatisticsTable:
+		object, err := manager.GetObject(ctx, database, target.Schema, target.Table)
+		if err != nil {
+			return err
+		}
+		switch object.Type {
+		case "TABLE", "PARTITIONED TABLE", "MATERIALIZED VIEW":
+			// Tables have counters
+		default:
+			return pg.ErrBadParameter.Withf("cannot reset statistics of %s %q", strings.ToLower(object.Type), object.Name)
+		}
+		return target.Reset(ctx, manager.conn.Remote(database))
+	default:
+		return target.Reset(ctx, manager.conn.Remote(database))
+	}
+}
@@ -0,0 +1,56 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// RESET STATISTICS TESTS
+
+func Test_Manager_ResetStatistics(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Database", func(t *testing.T) {
+		assert.NoError(mgr.ResetStatistics(context.TODO(), schema.StatisticsTarget{
+			Type:     schema.StatisticsDatabase,
+			Database: "postgres",
+		}))
+	})
+
+	t.Run("Statements", func(t *testing.T) {
+		assert.NoError(mgr.ResetStatistics(context.TODO(), schema.StatisticsTarget{
+			Type:     schema.StatisticsStatements,
+			Database: "postgres",
+		}))
+	})
+
+	t.Run("TableNotFound", func(t *testing.T) {
+		assert.ErrorIs(mgr.ResetStatistics(context.TODO(), schema.StatisticsTarget{
+			Type:     schema.StatisticsTable,
+			Database: "postgres",
+			Schema:   "public",
+			Table:    "non_existing_table_xyz",
+		}), pg.ErrNotFound)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		assert.ErrorIs(mgr.ResetStatistics(context.TODO(), schema.StatisticsTarget{
+			Type:     schema.StatisticsDatabase,
+			Database: "nonexistent_database",
+		}), pg.ErrNotFound)
+	})
+}
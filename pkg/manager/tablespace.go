@@ -3,7 +3,6 @@ package manager
 import (
 	"context"
 	"errors"
-	"slices"
 	"strings"
 
 	// Packages
@@ -38,11 +37,71 @@ func (manager *Manager) GetTablespace(ctx context.Context, name string) (*schema
 	return &response, nil
 }
 
+// ListTablespaceStats returns the space on the filesystem which holds each
+// tablespace, as reported by df on the database host. The server runs df,
+// which requires the pg_execute_server_program privilege, and the output is
+// read into a temporary table, so the manager must not be connected to a
+// standby.
+func (manager *Manager) ListTablespaceStats(ctx context.Context) (*schema.TablespaceStatList, error) {
+	var response schema.TablespaceStatList
+
+	// A temporary table cannot be created on a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
+	// Read the tablespaces
+	var tablespaces []schema.Tablespace
+	var req schema.TablespaceListRequest
+	for {
+		var list schema.TablespaceList
+		if err := manager.conn.List(ctx, &list, req); err != nil {
+			return nil, err
+		}
+		tablespaces = append(tablespaces, list.Body...)
+		if req.Offset += uint64(len(list.Body)); len(list.Body) == 0 || req.Offset >= list.Count {
+			break
+		}
+	}
+
+	// Read the space on the filesystem of each location
+	if err := manager.conn.Tx(ctx, func(conn pg.Conn) error {
+		if err := conn.Exec(ctx, tablespaceStatTable); err != nil {
+			return err
+		}
+		for _, tablespace := range tablespaces {
+			location := schema.TablespaceLocation{Name: tablespace.Name, Location: tablespace.Location}
+			if err := conn.Insert(ctx, nil, location); err != nil {
+				return err
+			}
+			var stat schema.TablespaceStat
+			if err := conn.Get(ctx, &stat, location); err != nil {
+				return err
+			}
+			response.Body = append(response.Body, stat)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	response.Count = uint64(len(response.Body))
+	return &response, nil
+}
+
 // CreateTablespace creates a new tablespace with the specified metadata and location.
 // The tablespace creation cannot be done in a transaction, but ACL grants are
 // applied within a transaction. If ACL grants fail, the tablespace is deleted
 // to maintain consistency.
-func (manager *Manager) CreateTablespace(ctx context.Context, meta schema.TablespaceMeta, location string) (*schema.Tablespace, error) {
+func (manager *Manager) CreateTablespace(ctx context.Context, meta schema.TablespaceMeta, location string) (_ *schema.Tablespace, err error) {
+	defer manager.audit(ctx, schema.AuditCreate, "tablespace", meta.Name, meta, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	var response schema.Tablespace
 
 	// Create the tablespace (outside a transaction)
@@ -75,7 +134,14 @@ func (manager *Manager) CreateTablespace(ctx context.Context, meta schema.Tables
 
 // DeleteTablespace drops a tablespace by name and returns its metadata before deletion.
 // Returns an error if the name is empty or the tablespace is not found.
-func (manager *Manager) DeleteTablespace(ctx context.Context, name string) (*schema.Tablespace, error) {
+func (manager *Manager) DeleteTablespace(ctx context.Context, name string) (_ *schema.Tablespace, err error) {
+	defer manager.audit(ctx, schema.AuditDelete, "tablespace", name, nil, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	if name == "" {
 		return nil, pg.ErrBadParameter.With("name is empty")
 	}
@@ -99,7 +165,14 @@ func (manager *Manager) DeleteTablespace(ctx context.Context, name string) (*sch
 // All changes are applied within a transaction to ensure atomicity.
 // If meta.Name is provided and differs from name, the tablespace is renamed.
 // ACL changes are synchronized by revoking removed privileges and granting new ones.
-func (manager *Manager) UpdateTablespace(ctx context.Context, name string, meta schema.TablespaceMeta) (*schema.Tablespace, error) {
+func (manager *Manager) UpdateTablespace(ctx context.Context, name string, meta schema.TablespaceMeta) (_ *schema.Tablespace, err error) {
+	defer manager.audit(ctx, schema.AuditUpdate, "tablespace", name, meta, &err)
+
+	// Refuse writes against a standby
+	if err := manager.writable(ctx); err != nil {
+		return nil, err
+	}
+
 	if name == "" {
 		return nil, pg.ErrBadParameter.With("name is empty")
 	}
@@ -132,45 +205,13 @@ func (manager *Manager) UpdateTablespace(ctx context.Context, name string, meta
 
 		// Update ACL's
 		if meta.Acl != nil {
-			for _, acl := range response.Acl {
-				if role := meta.Acl.Find(acl.Role); role == nil {
-					// Revoke the older privileges
-					if err := acl.RevokeTablespace(ctx, conn, meta.Name); err != nil {
-						return err
-					}
-				} else if slices.Equal(acl.Priv, role.Priv) {
-					// No change
-				} else if role.IsAll() {
-					// Just grant
-					if err := role.GrantTablespace(ctx, conn, meta.Name); err != nil {
-						return err
-					}
-				} else {
-					// Revoke
-					for _, priv := range acl.Priv {
-						if !slices.Contains(role.Priv, priv) {
-							if err := acl.WithPriv(priv).RevokeTablespace(ctx, conn, meta.Name); err != nil {
-								return err
-							}
-						}
-					}
-					// Grant
-					for _, priv := range role.Priv {
-						if !slices.Contains(acl.Priv, priv) {
-							if err := acl.WithPriv(priv).GrantTablespace(ctx, conn, meta.Name); err != nil {
-								return err
-							}
-						}
-					}
-				}
-			}
-
-			// Create new privileges
-			for _, acl := range meta.Acl {
-				if role := response.Acl.Find(acl.Role); role == nil {
-					if err := acl.GrantTablespace(ctx, conn, meta.Name); err != nil {
+			for _, change := range response.Acl.Diff(meta.Acl) {
+				if change.Grant {
+					if err := change.GrantTablespace(ctx, conn, meta.Name); err != nil {
 						return err
 					}
+				} else if err := change.RevokeTablespace(ctx, conn, meta.Name); err != nil {
+					return err
 				}
 			}
 		}
@@ -189,3 +230,11 @@ func (manager *Manager) UpdateTablespace(ctx context.Context, name string, meta
 	// Return success
 	return &response, nil
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE CONSTANTS
+
+const (
+	// Temporary table which the output of df is read into
+	tablespaceStatTable = `CREATE TEMPORARY TABLE IF NOT EXISTS tablespace_df (line TEXT) ON COMMIT DROP`
+)
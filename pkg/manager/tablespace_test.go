@@ -109,8 +109,8 @@ func Test_Manager_GetTablespace(t *testing.T) {
 // CREATE TABLESPACE TESTS
 //
 // Note: Creating tablespaces requires a filesystem location that the postgres
-// user can write to. In a containerized test environment, this may not be
-// available, so these tests verify error handling for invalid inputs.
+// user can write to, which is created in the container with
+// MkdirForTablespace.
 
 func Test_Manager_CreateTablespace(t *testing.T) {
 	assert := assert.New(t)
@@ -153,6 +153,68 @@ func Test_Manager_CreateTablespace(t *testing.T) {
 		assert.Error(err)
 		assert.ErrorIs(err, pg.ErrBadParameter)
 	})
+
+	t.Run("Create", func(t *testing.T) {
+		location := "/var/lib/postgresql/tablespaces/test_create"
+		conn.MkdirForTablespace(t, location)
+
+		tablespace, err := mgr.CreateTablespace(context.TODO(), schema.TablespaceMeta{
+			Name: "test_create",
+		}, location)
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		defer mgr.DeleteTablespace(context.TODO(), tablespace.Name)
+		assert.Equal("test_create", tablespace.Name)
+		assert.Equal(location, tablespace.Location)
+
+		// The tablespace can be read back
+		tablespace, err = mgr.GetTablespace(context.TODO(), "test_create")
+		if assert.NoError(err) {
+			assert.Equal(location, tablespace.Location)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TABLESPACE STAT TESTS
+
+func Test_Manager_ListTablespaceStats(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Create a tablespace in its own directory
+	location := "/var/lib/postgresql/tablespaces/test_stat"
+	conn.MkdirForTablespace(t, location)
+	if _, err := mgr.CreateTablespace(context.TODO(), schema.TablespaceMeta{Name: "test_stat"}, location); !assert.NoError(err) {
+		t.FailNow()
+	}
+	defer mgr.DeleteTablespace(context.TODO(), "test_stat")
+
+	t.Run("List", func(t *testing.T) {
+		stats, err := mgr.ListTablespaceStats(context.TODO())
+		if !assert.NoError(err) {
+			t.FailNow()
+		}
+		assert.GreaterOrEqual(stats.Count, uint64(3))
+
+		var found bool
+		for _, stat := range stats.Body {
+			assert.NotZero(stat.Total)
+			assert.LessOrEqual(stat.Free, stat.Total)
+			if stat.Name == "test_stat" {
+				found = true
+				assert.Equal(location, stat.Location)
+			}
+		}
+		assert.True(found)
+	})
 }
 
 ////////////////////////////////////////////////////////////////////////////////
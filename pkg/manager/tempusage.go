@@ -0,0 +1,42 @@
+package manager
+
+import (
+	"context"
+
+	// Packages
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - TEMPORARY FILES
+
+// GetTempUsage returns the temporary files written in each database, with
+// the databases which wrote the most bytes first, and the statements which
+// wrote the most temporary files when pg_stat_statements is installed. If
+// Database is specified in the request, only that database and its
+// statements are returned. The offset and limit apply to the statements.
+func (manager *Manager) GetTempUsage(ctx context.Context, req schema.TempUsageRequest) (*schema.TempUsage, error) {
+	var response schema.TempUsage
+	if err := manager.conn.Get(ctx, &response, req); err != nil {
+		return nil, err
+	}
+
+	// Get the temporary files of each database
+	var databases schema.DatabaseTempUsageList
+	if err := manager.conn.List(ctx, &databases, schema.DatabaseTempUsageListRequest{Database: req.Database}); err != nil {
+		return nil, err
+	}
+	response.Databases = databases.Body
+
+	// Get the temporary files of each statement
+	if manager.statStatementsAvailable {
+		var statements schema.StatementTempUsageList
+		if err := manager.conn.List(ctx, &statements, schema.StatementTempUsageListRequest{Database: req.Database, OffsetLimit: req.OffsetLimit}); err != nil {
+			return nil, err
+		}
+		response.Statements = statements.Body
+	}
+
+	// Return success
+	return &response, nil
+}
@@ -0,0 +1,69 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEMPORARY FILE TESTS
+
+func Test_Manager_GetTempUsage(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("AllDatabases", func(t *testing.T) {
+		usage, err := mgr.GetTempUsage(context.TODO(), schema.TempUsageRequest{})
+		if assert.NoError(err) {
+			assert.NotEmpty(usage.WorkMem)
+			assert.NotEmpty(usage.TempFileLimit)
+			assert.NotEmpty(usage.Databases)
+		}
+	})
+
+	t.Run("Database", func(t *testing.T) {
+		usage, err := mgr.GetTempUsage(context.TODO(), schema.TempUsageRequest{Database: types.StringPtr("postgres")})
+		if assert.NoError(err) {
+			if assert.Len(usage.Databases, 1) {
+				assert.Equal("postgres", usage.Databases[0].Database)
+			}
+			for _, statement := range usage.Statements {
+				assert.Equal("postgres", statement.Database)
+			}
+		}
+	})
+
+	t.Run("Statements", func(t *testing.T) {
+		// Write a temporary file with a sort which exceeds work_mem
+		if !assert.NoError(conn.Tx(context.TODO(), func(conn pg.Conn) error {
+			if err := conn.Exec(context.TODO(), "SET LOCAL work_mem = '64kB'"); err != nil {
+				return err
+			}
+			return conn.Exec(context.TODO(), "SELECT * FROM generate_series(1, 100000) i ORDER BY i DESC")
+		})) {
+			t.FailNow()
+		}
+
+		usage, err := mgr.GetTempUsage(context.TODO(), schema.TempUsageRequest{OffsetLimit: pg.OffsetLimit{Limit: types.Uint64Ptr(5)}})
+		if assert.NoError(err) && mgr.StatStatementsAvailable() {
+			assert.NotEmpty(usage.Statements)
+			assert.LessOrEqual(len(usage.Statements), 5)
+			for _, statement := range usage.Statements {
+				assert.NotZero(statement.BlocksWritten + statement.BlocksRead)
+			}
+		}
+	})
+}
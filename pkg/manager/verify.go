@@ -0,0 +1,138 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	// Packages
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	pg "github.com/mutablelogic/go-pg"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// VerifyChecksums returns the data checksum failures which have been
+// detected in each database when pages were read, and whether data checksums
+// are enabled on the server.
+func (manager *Manager) VerifyChecksums(ctx context.Context) (*schema.ChecksumList, error) {
+	var list schema.ChecksumList
+	if err := manager.conn.List(ctx, &list, schema.ChecksumListRequest{}); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// VerifyObject checks a table, materialized view or btree index for
+// corruption with the amcheck extension, which must be installed in the
+// database. The rows of a table are checked with verify_heapam, and each of
+// its btree indexes with bt_index_check. Corruption is returned as findings
+// rather than as an error. Returns ErrNotAvailable if amcheck is not installed.
+func (manager *Manager) VerifyObject(ctx context.Context, database, namespace, name string, req schema.ObjectVerifyRequest) (*schema.ObjectVerification, error) {
+	object, err := manager.GetObject(ctx, database, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the object can be verified
+	var heap bool
+	switch object.Type {
+	case "TABLE", "MATERIALIZED VIEW":
+		heap = true
+	case "INDEX":
+		if object.Index == nil || object.Index.Method != "btree" {
+			return nil, pg.ErrBadParameter.Withf("cannot verify index %q, only btree indexes can be verified", name)
+		}
+	default:
+		return nil, pg.ErrBadParameter.Withf("cannot verify %s %q", strings.ToLower(object.Type), name)
+	}
+
+	// Check amcheck is installed in the database
+	if err := manager.amcheckAvailable(ctx, database); err != nil {
+		return nil, err
+	}
+
+	// Check the rows of the table
+	remote := manager.conn.Remote(database)
+	response := schema.ObjectVerification{Database: database, Schema: object.Schema, Name: object.Name, Type: object.Type}
+	if heap {
+		if err := remote.With("as", schema.HeapVerifyDef).List(ctx, &response, schema.HeapVerifyRequest{Schema: object.Schema, Name: object.Name}); err != nil {
+			return nil, err
+		}
+		response.Checked = append(response.Checked, object.Schema+"."+object.Name)
+	}
+
+	// Check the indexes, recording corruption which is raised as an error
+	var indexes schema.IndexVerifyList
+	if err := remote.With("as", schema.IndexVerifyDef).List(ctx, &indexes, schema.IndexVerifyListRequest{Schema: object.Schema, Name: object.Name}); err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		var checked schema.IndexVerified
+		relation := index.Schema + "." + index.Name
+		if err := remote.With("as", schema.IndexCheckedDef).Get(ctx, &checked, schema.IndexVerifyRequest{ObjectName: index, HeapAllIndexed: req.HeapAllIndexed}); err != nil {
+			finding, corrupt := corruptionFinding(relation, err)
+			if !corrupt {
+				return nil, err
+			}
+			response.Findings = append(response.Findings, finding)
+			response.Corrupt = true
+		}
+		response.Checked = append(response.Checked, relation)
+	}
+
+	// Add the checksum failures of the database
+	checksums, err := manager.VerifyChecksums(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if i := slices.IndexFunc(checksums.Body, func(c schema.DatabaseChecksums) bool {
+		return c.Database == database
+	}); i >= 0 {
+		response.Checksums = &checksums.Body[i]
+	}
+
+	// Return success
+	return &response, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// amcheckAvailable returns ErrNotAvailable if amcheck is not installed in
+// the database
+func (manager *Manager) amcheckAvailable(ctx context.Context, database string) error {
+	extensions, err := manager.ListExtensions(ctx, schema.ExtensionListRequest{Database: types.StringPtr(database), Installed: types.BoolPtr(true)})
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(extensions.Body, func(e schema.Extension) bool {
+		return e.Name == schema.AmcheckExtension
+	}) {
+		return pg.ErrNotAvailable.Withf("%s is not installed in database %q", schema.AmcheckExtension, database)
+	}
+	return nil
+}
+
+// corruptionFinding returns the finding for an error raised when corruption
+// was found in a relation, or false if the error is not corruption
+func corruptionFinding(relation string, err error) (schema.CorruptionFinding, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return schema.CorruptionFinding{}, false
+	}
+	switch pgErr.Code {
+	case schema.SQLStateDataCorrupted, schema.SQLStateIndexCorrupted:
+		message := pgErr.Message
+		if pgErr.Detail != "" {
+			message += ": " + pgErr.Detail
+		}
+		return schema.CorruptionFinding{Relation: relation, SQLState: pgErr.Code, Message: message}, true
+	default:
+		return schema.CorruptionFinding{}, false
+	}
+}
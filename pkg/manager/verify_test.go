@@ -0,0 +1,103 @@
+package manager_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	assert "github.com/stretchr/testify/assert"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// VERIFY TESTS
+
+func Test_Manager_VerifyChecksums(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	checksums, err := mgr.VerifyChecksums(context.TODO())
+	if assert.NoError(err) {
+		assert.NotZero(checksums.Count)
+		for _, database := range checksums.Body {
+			assert.NotEmpty(database.Database)
+			assert.Zero(database.Failures)
+		}
+	}
+}
+
+func Test_Manager_VerifyObject(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	mgr, err := manager.New(context.TODO(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Create a database with a table and indexes
+	t.Cleanup(func() {
+		mgr.DeleteDatabase(context.TODO(), "test_verify", true)
+	})
+	if _, err := mgr.CreateDatabase(context.TODO(), schema.DatabaseMeta{Name: "test_verify"}); !assert.NoError(err) {
+		t.FailNow()
+	}
+	for _, statement := range []string{
+		"CREATE TABLE verify_test (id INTEGER PRIMARY KEY, tags TEXT[])",
+		"CREATE INDEX verify_test_tags ON verify_test USING gin (tags)",
+		"INSERT INTO verify_test SELECT i, ARRAY['tag' || i] FROM generate_series(1, 1000) i",
+	} {
+		if !assert.NoError(conn.Remote("test_verify").Exec(context.TODO(), statement)) {
+			t.FailNow()
+		}
+	}
+
+	t.Run("NotAvailable", func(t *testing.T) {
+		_, err := mgr.VerifyObject(context.TODO(), "test_verify", "public", "verify_test", schema.ObjectVerifyRequest{})
+		assert.ErrorIs(err, pg.ErrNotAvailable)
+	})
+
+	// Install amcheck
+	if _, err := mgr.CreateExtension(context.TODO(), schema.ExtensionMeta{Name: schema.AmcheckExtension, Database: "test_verify"}, false); !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	t.Run("Table", func(t *testing.T) {
+		verification, err := mgr.VerifyObject(context.TODO(), "test_verify", "public", "verify_test", schema.ObjectVerifyRequest{HeapAllIndexed: true})
+		if assert.NoError(err) {
+			assert.False(verification.Corrupt)
+			assert.Empty(verification.Findings)
+			assert.Equal([]string{"public.verify_test", "public.verify_test_pkey"}, verification.Checked)
+			if assert.NotNil(verification.Checksums) {
+				assert.Equal("test_verify", verification.Checksums.Database)
+			}
+		}
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		verification, err := mgr.VerifyObject(context.TODO(), "test_verify", "public", "verify_test_pkey", schema.ObjectVerifyRequest{})
+		if assert.NoError(err) {
+			assert.False(verification.Corrupt)
+			assert.Equal([]string{"public.verify_test_pkey"}, verification.Checked)
+		}
+	})
+
+	t.Run("NotBtree", func(t *testing.T) {
+		_, err := mgr.VerifyObject(context.TODO(), "test_verify", "public", "verify_test_tags", schema.ObjectVerifyRequest{})
+		assert.ErrorIs(err, pg.ErrBadParameter)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := mgr.VerifyObject(context.TODO(), "test_verify", "public", "missing", schema.ObjectVerifyRequest{})
+		assert.ErrorIs(err, pg.ErrNotFound)
+	})
+}
@@ -0,0 +1,3 @@
+// Package quote provides functions to quote identifiers and string values
+// for PostgreSQL, for statements such as DDL which cannot use parameters.
+package quote
@@ -0,0 +1,56 @@
+package quote
+
+import (
+	"fmt"
+	"strings"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Ident returns a double-quoted identifier, such as a table or role name.
+// When there is more than one part, the parts are quoted separately and
+// joined with a dot, so Ident("public", "users") returns "public"."users".
+// Null characters, which cannot appear in an identifier, are removed.
+func Ident(parts ...string) string {
+	quoted := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.ReplaceAll(part, "\x00", "")
+		quoted = append(quoted, `"`+strings.ReplaceAll(part, `"`, `""`)+`"`)
+	}
+	return strings.Join(quoted, ".")
+}
+
+// Literal returns a single-quoted string constant. When the value contains
+// a backslash, an escape string constant such as E'a\\b' is returned, so
+// the value is the same whatever the standard_conforming_strings setting.
+// Null characters, which cannot appear in a string, are removed.
+func Literal(value string) string {
+	value = strings.ReplaceAll(value, "\x00", "")
+	if strings.Contains(value, `\`) {
+		return `E'` + strings.ReplaceAll(strings.ReplaceAll(value, `\`, `\\`), `'`, `''`) + `'`
+	}
+	return `'` + strings.ReplaceAll(value, `'`, `''`) + `'`
+}
+
+// DollarQuote returns a dollar-quoted string constant, such as the body of
+// a function, which is not escaped. The tag is empty unless the value
+// contains $$, in which case a tag which does not appear in the value is
+// used, such as $q1$.
+func DollarQuote(value string) string {
+	for i := 0; ; i++ {
+		var delim string
+		switch i {
+		case 0:
+			delim = "$$"
+		case 1:
+			delim = "$q$"
+		default:
+			delim = fmt.Sprintf("$q%d$", i-1)
+		}
+		// The first delimiter after the opening one must be the closing one
+		if strings.Index(value+delim, delim) == len(value) {
+			return delim + value + delim
+		}
+	}
+}
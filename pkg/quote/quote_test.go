@@ -0,0 +1,76 @@
+package quote_test
+
+import (
+	"fmt"
+	"testing"
+
+	// Packages
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Ident(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		Expected string
+		Input    []string
+	}{
+		{``, nil},
+		{`""`, []string{""}},
+		{`"users"`, []string{"users"}},
+		{`"Users"`, []string{"Users"}},
+		{`"a""b"`, []string{`a"b`}},
+		{`"a.b"`, []string{"a.b"}},
+		{`"ab"`, []string{"a\x00b"}},
+		{`"public"."users"`, []string{"public", "users"}},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert.Equal(test.Expected, quote.Ident(test.Input...))
+		})
+	}
+}
+
+func Test_Literal(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		Expected string
+		Input    string
+	}{
+		{`''`, ""},
+		{`'abc'`, "abc"},
+		{`''''`, "'"},
+		{`'ab''cd'`, "ab'cd"},
+		{`E'a\\b'`, `a\b`},
+		{`E'\\'' OR 1=1 --'`, `\' OR 1=1 --`},
+		{`'ab'`, "a\x00b"},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert.Equal(test.Expected, quote.Literal(test.Input))
+		})
+	}
+}
+
+func Test_DollarQuote(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		Expected string
+		Input    string
+	}{
+		{`$$$$`, ""},
+		{`$$SELECT 'a'$$`, "SELECT 'a'"},
+		{`$q$a $$ b$q$`, "a $$ b"},
+		{`$q$a$$q$`, "a$"},
+		{`$q1$$q$ $$$q1$`, "$q$ $$"},
+		{`$$a$q$$`, "a$q"},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert.Equal(test.Expected, quote.DollarQuote(test.Input))
+		})
+	}
+}
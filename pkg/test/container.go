@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	// Packages
@@ -19,20 +20,25 @@ type Container struct {
 	testcontainers.Container `json:"-"`
 	Env                      map[string]string `json:"env"`
 	MappedPorts              map[string]string `json:"mapped_ports"`
+	Reuse                    bool              `json:"reuse,omitempty"`
 }
 
+//////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Label for containers which are reused between test runs
+	ReuseLabel = "com.mutablelogic.go-pg.reuse"
+)
+
 //////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
 // NewContainer creates a new container with the given name and image.
 func NewContainer(ctx context.Context, name, image string, opt ...Opt) (*Container, error) {
-	// The name has _unixtime appended to it
-	name = fmt.Sprintf("%s_%v", name, time.Now().Unix())
-
 	// Apply the options
 	var o opts
 	o.req = testcontainers.ContainerRequest{
-		Name:       name,
 		Image:      image,
 		WaitingFor: wait.ForAll(),
 	}
@@ -42,6 +48,14 @@ func NewContainer(ctx context.Context, name, image string, opt ...Opt) (*Contain
 		}
 	}
 
+	// The name has _unixtime appended to it, or the image tag when the
+	// container is reused, so that each image has its own container
+	if o.reuse {
+		o.req.Name = fmt.Sprintf("%s_%s", name, imageTag(o.req.Image))
+	} else {
+		o.req.Name = fmt.Sprintf("%s_%v", name, time.Now().Unix())
+	}
+
 	// If there are no wait strategies, then wait for container exit
 	if o.req.WaitingFor.(*wait.MultiStrategy).Strategies == nil {
 		o.req.WaitingFor = wait.ForExit()
@@ -51,6 +65,7 @@ func NewContainer(ctx context.Context, name, image string, opt ...Opt) (*Contain
 	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
 		ContainerRequest: o.req,
 		Started:          true,
+		Reuse:            o.reuse,
 	})
 	if err != nil {
 		return nil, err
@@ -59,6 +74,7 @@ func NewContainer(ctx context.Context, name, image string, opt ...Opt) (*Contain
 	var response Container
 	response.Container = container
 	response.Env = o.req.Env
+	response.Reuse = o.reuse
 	response.MappedPorts = make(map[string]string, len(o.req.ExposedPorts))
 
 	for _, port := range o.req.ExposedPorts {
@@ -73,7 +89,11 @@ func NewContainer(ctx context.Context, name, image string, opt ...Opt) (*Contain
 	return &response, nil
 }
 
+// Close removes the container, unless it is reused
 func (c *Container) Close(ctx context.Context) error {
+	if c.Reuse {
+		return nil
+	}
 	return c.Container.Terminate(ctx)
 }
 
@@ -108,3 +128,23 @@ func (c *Container) GetPort(name string) (string, error) {
 	}
 	return "", fmt.Errorf("ports: %q not found", name)
 }
+
+//////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the tag of an image, such as "17-bookworm", with characters which
+// are not allowed in a container name replaced
+func imageTag(image string) string {
+	tag := "latest"
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+		tag = image[i+1:]
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, tag)
+}
@@ -30,3 +30,23 @@ func Test_Container_001(t *testing.T) {
 	defer container.Close(context.Background())
 	t.Log(container)
 }
+
+func Test_Container_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// Create a container which is reused
+	container, err := test.NewContainer(context.Background(), t.Name(), TEST_HELLOWORLD, test.OptReuse(true))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	defer container.Terminate(context.Background())
+	assert.True(container.Reuse)
+
+	// Closing a reused container leaves it, so the same container is returned
+	assert.NoError(container.Close(context.Background()))
+	other, err := test.NewContainer(context.Background(), t.Name(), TEST_HELLOWORLD, test.OptReuse(true))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.Equal(container.GetContainerID(), other.GetContainerID())
+}
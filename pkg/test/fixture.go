@@ -0,0 +1,189 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
+	yaml "gopkg.in/yaml.v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// fixture is the rows to insert into a table
+type fixture struct {
+	table string
+	rows  []map[string]any
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// LoadFixtures loads the fixtures in a directory, or a single fixture file,
+// in order of file name, and fails the test on error. Files with the .sql
+// extension are executed as they are, and so can contain several statements
+// but no @ parameters. Files with the .yaml or .yml extension map table names
+// to the rows to insert, and the tables are filled in the order they appear:
+//
+//	public.users:
+//	  - id: 1
+//	    name: alice
+//	  - id: 2
+//	    name: bob
+//
+// Each file is loaded in a transaction. Other files are ignored.
+func (c *Conn) LoadFixtures(t *testing.T, dir string) {
+	t.Helper()
+
+	// Determine the files to load
+	files, err := fixtureFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load each file
+	for _, path := range files {
+		if err := c.loadFixture(context.Background(), path); err != nil {
+			t.Fatalf("%s: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+// Truncate removes all the rows from the tables, resetting sequences and
+// truncating tables which reference them, and fails the test on error
+func (c *Conn) Truncate(t *testing.T, tables ...string) {
+	t.Helper()
+	if len(tables) == 0 {
+		return
+	}
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		names = append(names, quoteTable(table))
+	}
+	if err := c.Exec(context.Background(), `TRUNCATE TABLE `+strings.Join(names, ", ")+` RESTART IDENTITY CASCADE`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the fixture files in a directory in order of file name, or the file
+// if the path is not a directory
+func fixtureFiles(dir string) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{dir}, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".sql", ".yaml", ".yml":
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// Load a fixture file in a transaction
+func (c *Conn) loadFixture(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".sql":
+		return c.Tx(ctx, func(conn pg.Conn) error {
+			return conn.Exec(ctx, string(data))
+		})
+	case ".yaml", ".yml":
+		fixtures, err := parseFixtures(data)
+		if err != nil {
+			return err
+		}
+		return c.Tx(ctx, func(conn pg.Conn) error {
+			for _, fixture := range fixtures {
+				for _, row := range fixture.rows {
+					if err := insertRow(ctx, conn, fixture.table, row); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+	default:
+		return pg.ErrBadParameter.Withf("unsupported fixture file %q", filepath.Base(path))
+	}
+}
+
+// Parse the tables and rows from a YAML fixture, in the order they appear
+func parseFixtures(data []byte) ([]fixture, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, pg.ErrBadParameter.With("expected a mapping of table names to rows")
+	}
+	fixtures := make([]fixture, 0, len(root.Content)/2)
+	for i := 0; i < len(root.Content); i += 2 {
+		fixture := fixture{table: root.Content[i].Value}
+		if err := root.Content[i+1].Decode(&fixture.rows); err != nil {
+			return nil, pg.ErrBadParameter.Withf("table %q: %v", fixture.table, err)
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+// Insert a row into a table, binding the values as named arguments
+func insertRow(ctx context.Context, conn pg.Conn, table string, row map[string]any) error {
+	if len(row) == 0 {
+		return conn.Exec(ctx, `INSERT INTO `+quoteTable(table)+` DEFAULT VALUES`)
+	}
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		columns = append(columns, column)
+	}
+	slices.Sort(columns)
+
+	// Bind the values as arg0, arg1, ... so any column name can be used
+	names := make([]string, 0, len(columns))
+	values := make([]string, 0, len(columns))
+	args := make([]any, 0, len(columns)*2)
+	for i, column := range columns {
+		key := fmt.Sprintf("arg%d", i)
+		names = append(names, quote.Ident(column))
+		values = append(values, "@"+key)
+		args = append(args, key, row[column])
+	}
+	return conn.With(args...).Exec(ctx, `INSERT INTO `+quoteTable(table)+` (`+strings.Join(names, ", ")+`) VALUES (`+strings.Join(values, ", ")+`)`)
+}
+
+// Quote a table name, which can be qualified with a schema
+func quoteTable(table string) string {
+	return quote.Ident(strings.Split(table, ".")...)
+}
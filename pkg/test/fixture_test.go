@@ -0,0 +1,82 @@
+package test_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	test "github.com/mutablelogic/go-pg/pkg/test"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// execs records the statements executed, and the arguments bound to them
+type execs struct {
+	statements []string
+	args       [][]any
+	tx         int
+}
+
+// execConn is a connection which records the statements executed
+type execConn struct {
+	pg.PoolConn
+	log  *execs
+	bind []any
+}
+
+func (e *execConn) With(args ...any) pg.Conn {
+	return &execConn{log: e.log, bind: args}
+}
+
+func (e *execConn) Tx(_ context.Context, fn func(pg.Conn) error) error {
+	e.log.tx++
+	return fn(e)
+}
+
+func (e *execConn) Exec(_ context.Context, sql string, _ ...pg.ExecOpt) error {
+	e.log.statements = append(e.log.statements, sql)
+	e.log.args = append(e.log.args, e.bind)
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// UNIT TESTS
+
+func Test_LoadFixtures_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// Write the fixtures
+	dir := t.TempDir()
+	assert.NoError(os.WriteFile(filepath.Join(dir, "01_schema.sql"), []byte("CREATE TABLE users (id INTEGER, name TEXT);\nCREATE TABLE empty (id SERIAL);"), 0o644))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "02_rows.yaml"), []byte("public.users:\n  - id: 1\n    name: alice\n  - id: 2\n    name: bob\nempty:\n  - {}\n"), 0o644))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o644))
+
+	// Load the fixtures
+	e := &execs{}
+	conn := &test.Conn{PoolConn: &execConn{log: e}}
+	conn.LoadFixtures(t, dir)
+
+	assert.Equal(2, e.tx)
+	assert.Equal([]string{
+		"CREATE TABLE users (id INTEGER, name TEXT);\nCREATE TABLE empty (id SERIAL);",
+		`INSERT INTO "public"."users" ("id", "name") VALUES (@arg0, @arg1)`,
+		`INSERT INTO "public"."users" ("id", "name") VALUES (@arg0, @arg1)`,
+		`INSERT INTO "empty" DEFAULT VALUES`,
+	}, e.statements)
+	assert.Equal([]any{"arg0", 1, "arg1", "alice"}, e.args[1])
+	assert.Equal([]any{"arg0", 2, "arg1", "bob"}, e.args[2])
+}
+
+func Test_Truncate_001(t *testing.T) {
+	assert := assert.New(t)
+
+	e := &execs{}
+	conn := &test.Conn{PoolConn: &execConn{log: e}}
+	conn.Truncate(t, "public.users", "empty")
+	assert.Equal([]string{`TRUNCATE TABLE "public"."users", "empty" RESTART IDENTITY CASCADE`}, e.statements)
+}
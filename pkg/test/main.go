@@ -2,16 +2,21 @@ package test
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
 	manager "github.com/mutablelogic/go-pg/pkg/manager"
+	testcontainers "github.com/testcontainers/testcontainers-go"
 )
 
 /////////////////////////////////////////////////////////////////////
@@ -20,7 +25,8 @@ import (
 // Conn is a wrapper around pg.PoolConn which provides a test connection
 type Conn struct {
 	pg.PoolConn
-	t *testing.T
+	t         *testing.T
+	container *Container
 }
 
 /////////////////////////////////////////////////////////////////////
@@ -28,53 +34,71 @@ type Conn struct {
 
 const (
 	timeout = 2 * time.Minute
+
+	// Environment variable with the major versions of PostgreSQL for MainMatrix
+	MatrixEnv = "PG_TEST_VERSIONS"
+
+	// Environment variable which, when true, reuses the container between
+	// test runs
+	ReuseEnv = "PG_TEST_REUSE"
+)
+
+var (
+	// Manager shared by the tests in a package
+	shared struct {
+		sync.Once
+		conn *ManagerConn
+		err  error
+	}
 )
 
 /////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
 func Main(m *testing.M, conn *Conn) {
-	// Context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	code := run(m, conn)
+	appendRecord()
+	os.Exit(code)
+}
 
-	// Name of executable
-	name, err := os.Executable()
+// MainMatrix runs the tests against each major version of PostgreSQL in the
+// PG_TEST_VERSIONS environment variable, such as "14,15,16,17", with a new
+// container for each version, and reports the versions the tests failed on.
+// When the environment variable is not set, it is the same as Main.
+func MainMatrix(m *testing.M, conn *Conn) {
+	versions, err := matrixVersions(os.Getenv(MatrixEnv))
 	if err != nil {
 		panic(err)
+	} else if len(versions) == 0 {
+		Main(m, conn)
+		return
 	}
 
-	// Start the container
-	verbose := slices.Contains(os.Args, "-test.v=true")
-	container, pool, err := NewPgxContainer(ctx, filepath.Base(name), verbose, func(ctx context.Context, sql string, args any, err error) {
-		if err != nil {
-			log.Printf("ERROR: %v", err)
-		}
-		if verbose || err != nil {
-			if args == nil {
-				log.Printf("SQL: %v", sql)
-			} else {
-				log.Printf("SQL: %v, ARGS: %v", sql, args)
-			}
+	// Run the tests against each version
+	var failed []uint
+	for _, version := range versions {
+		log.Printf("=== PostgreSQL %d", version)
+		if code := run(m, conn, OptVersion(version)); code != 0 {
+			log.Printf("--- FAIL: PostgreSQL %d", version)
+			failed = append(failed, version)
+		} else {
+			log.Printf("--- PASS: PostgreSQL %d", version)
 		}
-	})
-	if err != nil {
-		panic(err)
 	}
-	defer pool.Close()
-	defer container.Close(ctx)
-
-	// Set the connection
-	*conn = Conn{pool, nil}
+	appendRecord()
 
-	// Run tests
-	os.Exit(m.Run())
+	// Report the versions which failed
+	if len(failed) > 0 {
+		log.Printf("FAIL on PostgreSQL %v", failed)
+		os.Exit(1)
+	}
+	os.Exit(0)
 }
 
 // Begin a test
 func (c *Conn) Begin(t *testing.T) *Conn {
 	t.Log("Begin", t.Name())
-	return &Conn{c.PoolConn, t}
+	return &Conn{c.PoolConn, t, c.container}
 }
 
 // Close ends the test.
@@ -97,16 +121,134 @@ func NewManager(t *testing.T) *ManagerConn {
 	t.Helper()
 	t.Log("Begin", t.Name())
 
+	mgr, err := newManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mgr
+}
+
+// SharedManager returns a Manager with a test container which is created on
+// the first call, and shared by all the tests in the package. When options
+// are given, a new Manager with the options is returned, which uses the same
+// container. Closing the returned ManagerConn does nothing; call
+// CloseSharedManager from TestMain after the tests have run to remove the
+// container.
+func SharedManager(t *testing.T, opts ...manager.Opt) *ManagerConn {
+	t.Helper()
+	t.Log("Begin", t.Name())
+
+	shared.Do(func() {
+		shared.conn, shared.err = newManager()
+	})
+	if shared.err != nil {
+		t.Fatal(shared.err)
+	}
+	if len(opts) == 0 {
+		return &ManagerConn{Manager: shared.conn.Manager}
+	}
+
+	// Create a manager with the options
+	mgr, err := manager.New(context.TODO(), shared.conn.pool, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ManagerConn{Manager: mgr}
+}
+
+// CloseSharedManager removes the container created by SharedManager, if any
+func CloseSharedManager() {
+	if shared.conn != nil {
+		shared.conn.Close()
+	}
+	appendRecord()
+}
+
+// Close closes the manager connection and container. For a shared manager,
+// this does nothing.
+func (m *ManagerConn) Close() {
+	if m.pool == nil {
+		return
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+	m.pool.Close()
+	m.container.Close(ctx)
+}
 
-	// Start the container
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Run the tests with a new container, and return the exit code
+func run(m *testing.M, conn *Conn, opt ...Opt) int {
+	// Context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Name of executable
 	name, err := os.Executable()
 	if err != nil {
-		t.Fatal(err)
+		panic(err)
 	}
+
+	// Start the container
 	verbose := slices.Contains(os.Args, "-test.v=true")
-	container, pool, err := NewPgxContainer(ctx, filepath.Base(name), verbose, func(ctx context.Context, sql string, args any, err error) {
+	container, pool, err := NewPgxContainer(ctx, filepath.Base(name), verbose, trace(verbose), append(reuseOpts(), opt...)...)
+	if err != nil {
+		panic(err)
+	}
+	defer pool.Close()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		container.Close(ctx)
+	}()
+
+	// Set the connection
+	*conn = Conn{pool, nil, container}
+
+	// Run tests
+	return m.Run()
+}
+
+// Parse the major versions of PostgreSQL from a comma-separated list
+func matrixVersions(value string) ([]uint, error) {
+	var versions []uint
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		version, err := strconv.ParseUint(field, 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("%s: invalid PostgreSQL version %q", MatrixEnv, field)
+		}
+		if !slices.Contains(versions, uint(version)) {
+			versions = append(versions, uint(version))
+		}
+	}
+	return versions, nil
+}
+
+// Return the option to reuse the container when the PG_TEST_REUSE environment
+// variable is true. The container is removed at the end of the test run unless
+// the testcontainers reaper is disabled, so warn when it is not.
+func reuseOpts() []Opt {
+	reuse, _ := strconv.ParseBool(os.Getenv(ReuseEnv))
+	if !reuse {
+		return nil
+	}
+	if !testcontainers.ReadConfig().Config.RyukDisabled {
+		log.Printf("WARNING: %s is set, but the container is removed at the end of the test run unless TESTCONTAINERS_RYUK_DISABLED=true", ReuseEnv)
+	}
+	return []Opt{OptReuse(true)}
+}
+
+// Return a trace function which records each statement, and logs errors,
+// or all statements when verbose
+func trace(verbose bool) pg.TraceFn {
+	return func(ctx context.Context, sql string, args any, err error) {
+		recorder.Trace(ctx, sql, args, err)
 		if err != nil {
 			log.Printf("ERROR: %v", err)
 		}
@@ -117,9 +259,23 @@ func NewManager(t *testing.T) *ManagerConn {
 				log.Printf("SQL: %v, ARGS: %v", sql, args)
 			}
 		}
-	})
+	}
+}
+
+// Create a manager with a new container
+func newManager() (*ManagerConn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Start the container
+	name, err := os.Executable()
 	if err != nil {
-		t.Fatal(err)
+		return nil, err
+	}
+	verbose := slices.Contains(os.Args, "-test.v=true")
+	container, pool, err := NewPgxContainer(ctx, filepath.Base(name), verbose, trace(verbose), reuseOpts()...)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create the manager
@@ -127,20 +283,12 @@ func NewManager(t *testing.T) *ManagerConn {
 	if err != nil {
 		pool.Close()
 		container.Close(ctx)
-		t.Fatal(err)
+		return nil, err
 	}
 
 	return &ManagerConn{
 		Manager:   mgr,
 		pool:      pool,
 		container: container,
-	}
-}
-
-// Close closes the manager connection and container
-func (m *ManagerConn) Close() {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-	m.pool.Close()
-	m.container.Close(ctx)
+	}, nil
 }
@@ -0,0 +1,40 @@
+package test
+
+import (
+	"testing"
+
+	// Packages
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_MatrixVersions(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		Value    string
+		Versions []uint
+		Error    bool
+	}{
+		{"", nil, false},
+		{" , ", nil, false},
+		{"17", []uint{17}, false},
+		{"14,15,16,17", []uint{14, 15, 16, 17}, false},
+		{" 16 , 14 ", []uint{16, 14}, false},
+		{"14,14,15", []uint{14, 15}, false},
+		{"14,,15", []uint{14, 15}, false},
+		{"0", nil, true},
+		{"-1", nil, true},
+		{"16.2", nil, true},
+		{"latest", nil, true},
+		{"14,abc", nil, true},
+	}
+	for _, test := range tests {
+		versions, err := matrixVersions(test.Value)
+		if test.Error {
+			assert.Error(err, test.Value)
+			assert.ErrorContains(err, MatrixEnv, test.Value)
+		} else if assert.NoError(err, test.Value) {
+			assert.Equal(test.Versions, versions, test.Value)
+		}
+	}
+}
@@ -2,6 +2,11 @@ package test
 
 import (
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	// Packages
 	nat "github.com/docker/go-connections/nat"
@@ -18,7 +23,8 @@ import (
 type Opt func(*opts) error
 
 type opts struct {
-	req testcontainers.ContainerRequest
+	req   testcontainers.ContainerRequest
+	reuse bool
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -70,6 +76,28 @@ func OptPostgres(user, password, database string) Opt {
 	}
 }
 
+// OptImage replaces the image for the container
+func OptImage(image string) Opt {
+	return func(o *opts) error {
+		if image == "" {
+			return fmt.Errorf("image is empty")
+		}
+		o.req.Image = image
+		return nil
+	}
+}
+
+// OptVersion replaces the image for a PostgreSQL container with the image
+// for a major version of PostgreSQL, such as 16
+func OptVersion(major uint) Opt {
+	return func(o *opts) error {
+		if major == 0 {
+			return fmt.Errorf("invalid PostgreSQL version %d", major)
+		}
+		return OptImage(fmt.Sprintf(pgxImage, major))(o)
+	}
+}
+
 // OptPostgresSetting adds a PostgreSQL configuration setting via -c flag
 func OptPostgresSetting(key, value string) Opt {
 	return func(o *opts) error {
@@ -78,6 +106,66 @@ func OptPostgresSetting(key, value string) Opt {
 	}
 }
 
+// OptPostgresConfig adds PostgreSQL configuration settings, such as
+// "wal_level" or "shared_preload_libraries", via -c flags
+func OptPostgresConfig(config map[string]string) Opt {
+	return func(o *opts) error {
+		keys := slices.Sorted(maps.Keys(config))
+		for _, key := range keys {
+			if err := OptPostgresSetting(key, config[key])(o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// OptInitSQL copies SQL or shell scripts into the container, which are run
+// in the order given when the database is first initialized, for example to
+// create extensions or roles. Files with the .sql, .sql.gz or .sh extension
+// can be used.
+func OptInitSQL(files ...string) Opt {
+	return func(o *opts) error {
+		for _, file := range files {
+			if info, err := os.Stat(file); err != nil {
+				return err
+			} else if info.IsDir() {
+				return fmt.Errorf("%q is a directory", file)
+			}
+			name := filepath.Base(file)
+			if !strings.HasSuffix(name, ".sql") && !strings.HasSuffix(name, ".sql.gz") && !strings.HasSuffix(name, ".sh") {
+				return fmt.Errorf("%q is not a .sql, .sql.gz or .sh file", file)
+			}
+
+			// Prefix the name with the position so the files run in order
+			o.req.Files = append(o.req.Files, testcontainers.ContainerFile{
+				HostFilePath:      file,
+				ContainerFilePath: fmt.Sprintf("%s/%03d_%s", pgxInitDir, len(o.req.Files), name),
+				FileMode:          0o755,
+			})
+		}
+		return nil
+	}
+}
+
+// OptReuse reuses a running container with the same name and image rather
+// than starting a new container, and leaves the container running when it
+// is closed. The container is labelled so it can be found and removed.
+func OptReuse(reuse bool) Opt {
+	return func(o *opts) error {
+		o.reuse = reuse
+		if reuse {
+			if o.req.Labels == nil {
+				o.req.Labels = make(map[string]string)
+			}
+			o.req.Labels[ReuseLabel] = "true"
+		} else {
+			delete(o.req.Labels, ReuseLabel)
+		}
+		return nil
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
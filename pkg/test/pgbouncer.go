@@ -0,0 +1,92 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	// Packages
+	nat "github.com/docker/go-connections/nat"
+	pg "github.com/mutablelogic/go-pg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	pgbouncerImage = "edoburu/pgbouncer:latest"
+	pgbouncerPort  = "6432/tcp"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// NewPgBouncerContainer creates a pgbouncer container in transaction pooling
+// mode, in front of a PostgreSQL container created with NewPgxContainer, and a
+// connection pool which connects through it with the simple protocol. Each
+// transaction may run on a different server connection, so the tests can
+// check that queries do not depend on prepared statements or session state.
+func NewPgBouncerContainer(ctx context.Context, name string, postgres *Container, tracer pg.TraceFn, opt ...Opt) (*Container, pg.PoolConn, error) {
+	user, err := postgres.GetEnv("POSTGRES_USER")
+	if err != nil {
+		return nil, nil, err
+	}
+	password, err := postgres.GetEnv("POSTGRES_PASSWORD")
+	if err != nil {
+		return nil, nil, err
+	}
+	database, err := postgres.GetEnv("POSTGRES_DB")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The server is reached on the network shared by the containers
+	addr, err := postgres.ContainerIP(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create the pgbouncer container
+	container, err := NewContainer(ctx, name, pgbouncerImage, append([]Opt{
+		OptEnv("DB_HOST", addr),
+		OptEnv("DB_PORT", nat.Port(pgxPort).Port()),
+		OptEnv("DB_USER", user),
+		OptEnv("DB_PASSWORD", password),
+		OptEnv("DB_NAME", database),
+		OptEnv("AUTH_TYPE", "scram-sha-256"),
+		OptEnv("POOL_MODE", "transaction"),
+		OptEnv("LISTEN_PORT", nat.Port(pgbouncerPort).Port()),
+		OptPorts(pgbouncerPort),
+	}, opt...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		return nil, nil, errors.Join(err, container.Close(ctx))
+	}
+	port, err := container.GetPort(pgbouncerPort)
+	if err != nil {
+		return nil, nil, errors.Join(err, container.Close(ctx))
+	}
+
+	// Create a connection pool which does not prepare statements
+	pool, err := pg.NewPool(ctx,
+		pg.WithCredentials(user, password),
+		pg.WithDatabase(database),
+		pg.WithHostPort(host, port),
+		pg.WithSSLMode("disable"),
+		pg.WithSimpleProtocol(),
+		pg.WithTrace(tracer),
+		pg.WithLint(),
+	)
+	if err != nil {
+		return nil, nil, errors.Join(err, container.Close(ctx))
+	} else if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, nil, errors.Join(err, container.Close(ctx))
+	}
+
+	// Return success
+	return container, pool, nil
+}
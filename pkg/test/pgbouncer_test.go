@@ -0,0 +1,52 @@
+package test_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	test "github.com/mutablelogic/go-pg/pkg/test"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// UNIT TESTS
+
+func Test_PgBouncer_001(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// Create a PostgreSQL container, and pgbouncer in front of it
+	postgres, conn, err := test.NewPgxContainer(ctx, t.Name(), false, nil)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	defer postgres.Close(ctx)
+	defer conn.Close()
+	container, pool, err := test.NewPgBouncerContainer(ctx, t.Name()+"_pgbouncer", postgres, nil)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	defer container.Close(ctx)
+	defer pool.Close()
+
+	// Statements with arguments are run on any server connection, which
+	// fails when statements are prepared on one connection and used on another
+	assert.NoError(pool.Exec(ctx, `CREATE TABLE bouncer (id INTEGER, name TEXT)`))
+	for i := range 20 {
+		assert.NoError(pool.With("id", i, "name", "row").Exec(ctx, `INSERT INTO bouncer (id, name) VALUES (@id, @name)`))
+	}
+
+	// Transactions run on one server connection
+	assert.NoError(pool.Tx(ctx, func(conn pg.Conn) error {
+		if err := conn.With("id", 10).Exec(ctx, `DELETE FROM bouncer WHERE id < @id`); err != nil {
+			return err
+		}
+		return conn.With("id", 10).Exec(ctx, `DELETE FROM bouncer WHERE id = @id`)
+	}))
+
+	var result pg.Result
+	assert.NoError(pool.With("name", "row").Exec(pg.ContextWithResult(ctx, &result), `SELECT * FROM bouncer WHERE name = @name`))
+	assert.Equal(int64(9), result.RowsAffected)
+}
@@ -6,6 +6,7 @@ import (
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -14,21 +15,48 @@ import (
 const (
 	pgxContainer = "ghcr.io/mutablelogic/docker-postgres:17-bookworm"
 	//pgxContainer = "postgis/postgis:16-master" // Postgresql container
-	pgxPort = "5432/tcp"
+	pgxImage = "ghcr.io/mutablelogic/docker-postgres:%d-bookworm" // Image for a major version
+	pgxPort  = "5432/tcp"
+
+	// Directory for scripts which are run when the database is initialized
+	pgxInitDir = "/docker-entrypoint-initdb.d"
+)
+
+const (
+	// Databases left by a previous test run in a reused container
+	pgxDatabases = `SELECT datname FROM pg_database WHERE datname NOT IN ('postgres', 'template0', 'template1', current_database())`
+
+	// Remove the schemas, and the roles which are not superusers or for
+	// replication, left by a previous test run in a reused container
+	pgxReset = `DO $$
+DECLARE
+	r RECORD;
+BEGIN
+	FOR r IN SELECT nspname FROM pg_namespace WHERE nspname NOT LIKE 'pg\_%' AND nspname <> 'information_schema' LOOP
+		EXECUTE format('DROP SCHEMA %I CASCADE', r.nspname);
+	END LOOP;
+	CREATE SCHEMA public;
+	FOR r IN SELECT rolname FROM pg_roles WHERE rolname NOT LIKE 'pg\_%' AND NOT rolsuper AND NOT rolreplication LOOP
+		EXECUTE format('DROP OWNED BY %I CASCADE', r.rolname);
+		EXECUTE format('DROP ROLE %I', r.rolname);
+	END LOOP;
+END $$`
 )
 
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
 // NewPgxContainer creates a new PostgreSQL container and connection pool.
-func NewPgxContainer(ctx context.Context, name string, verbose bool, tracer pg.TraceFn) (*Container, pg.PoolConn, error) {
+// Options such as OptVersion are applied after the defaults, so can replace
+// the image or add settings.
+func NewPgxContainer(ctx context.Context, name string, verbose bool, tracer pg.TraceFn, opt ...Opt) (*Container, pg.PoolConn, error) {
 	// Create a new container with postgresql package
-	container, err := NewContainer(ctx, name, pgxContainer,
+	container, err := NewContainer(ctx, name, pgxContainer, append([]Opt{
 		OptEnv("POSTGRES_REPLICATION_PASSWORD", "password"),
 		OptPostgres("postgres", "password", name),                            // User, Password, Database
 		OptPostgresSetting("shared_preload_libraries", "pg_stat_statements"), // Enable pg_stat_statements
 		OptPostgresSetting("wal_level", "logical"),                           // Enable logical replication
-	)
+	}, opt...)...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -39,12 +67,13 @@ func NewPgxContainer(ctx context.Context, name string, verbose bool, tracer pg.T
 		return nil, nil, err
 	}
 
-	// Create a connection pool
+	// Create a connection pool, which flags unsafe templates
 	pool, err := pg.NewPool(ctx,
 		pg.WithCredentials("postgres", "password"),
 		pg.WithDatabase(name),
 		pg.WithHostPort(host, port),
 		pg.WithTrace(tracer),
+		pg.WithLint(),
 	)
 	if err != nil {
 		return nil, nil, errors.Join(err, container.Close(ctx))
@@ -52,6 +81,39 @@ func NewPgxContainer(ctx context.Context, name string, verbose bool, tracer pg.T
 		return nil, nil, errors.Join(err, container.Close(ctx))
 	}
 
+	// Reset a reused container
+	if container.Reuse {
+		if err := resetPgx(ctx, pool); err != nil {
+			pool.Close()
+			return nil, nil, err
+		}
+	}
+
 	// Return success
 	return container, pool, nil
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Remove the databases, schemas and roles left by a previous test run in a
+// reused container, so that the tests start from an empty database
+func resetPgx(ctx context.Context, pool pg.PoolConn) error {
+	var databases []string
+	if err := pool.ListStream(ctx, query(pgxDatabases), func(row pg.Row) error {
+		var name string
+		if err := row.Scan(&name); err != nil {
+			return err
+		}
+		databases = append(databases, name)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, name := range databases {
+		if err := pool.Exec(ctx, `DROP DATABASE `+quote.Ident(name)+` WITH (FORCE)`); err != nil {
+			return err
+		}
+	}
+	return pool.Exec(ctx, pgxReset)
+}
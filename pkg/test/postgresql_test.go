@@ -2,9 +2,12 @@ package test_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	// Packages
+	pg "github.com/mutablelogic/go-pg"
 	test "github.com/mutablelogic/go-pg/pkg/test"
 	assert "github.com/stretchr/testify/assert"
 )
@@ -21,3 +24,91 @@ func Test_Postgresql_001(t *testing.T) {
 	assert.NotNil(container)
 	assert.NotNil(pool)
 }
+
+func Test_Postgresql_002(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// Create a new container with postgresql package
+	container, pool, err := test.NewPgxContainer(ctx, t.Name(), false, nil)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	defer container.Close(ctx)
+	defer pool.Close()
+
+	// Seed data and snapshot it
+	assert.NoError(pool.Exec(ctx, `CREATE TABLE seed (id INTEGER)`))
+	assert.NoError(pool.Exec(ctx, `INSERT INTO seed (id) VALUES (1), (2)`))
+	assert.NoError(container.Snapshot(ctx, "seed"))
+	pool.Reset()
+
+	// Change the data, then restore the snapshot
+	assert.NoError(pool.Exec(ctx, `DELETE FROM seed`))
+	assert.NoError(container.Restore(ctx, "seed"))
+	pool.Reset()
+
+	// The seed data is restored
+	var result pg.Result
+	assert.NoError(pool.Exec(pg.ContextWithResult(ctx, &result), `SELECT * FROM seed`))
+	assert.Equal(int64(2), result.RowsAffected)
+
+	// The test database cannot be a snapshot
+	assert.ErrorIs(container.Snapshot(ctx, ""), pg.ErrBadParameter)
+}
+
+func Test_Postgresql_003(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// Script which is run when the database is initialized
+	script := filepath.Join(t.TempDir(), "init.sql")
+	if !assert.NoError(os.WriteFile(script, []byte(`CREATE TABLE init_test (id INTEGER); INSERT INTO init_test (id) VALUES (1);`), 0o644)) {
+		t.FailNow()
+	}
+
+	// Create a new container with settings and the script
+	container, pool, err := test.NewPgxContainer(ctx, t.Name(), false, nil,
+		test.OptPostgresConfig(map[string]string{"work_mem": "8MB"}),
+		test.OptInitSQL(script),
+	)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	defer container.Close(ctx)
+	defer pool.Close()
+
+	// The setting is applied
+	var result pg.Result
+	assert.NoError(pool.Exec(pg.ContextWithResult(ctx, &result), `SELECT 1 WHERE current_setting('work_mem') = '8MB'`))
+	assert.Equal(int64(1), result.RowsAffected)
+
+	// The script has been run
+	assert.NoError(pool.Exec(pg.ContextWithResult(ctx, &result), `SELECT * FROM init_test`))
+	assert.Equal(int64(1), result.RowsAffected)
+
+	// Only scripts can be copied
+	_, _, err = test.NewPgxContainer(ctx, t.Name(), false, nil, test.OptInitSQL(t.TempDir()))
+	assert.Error(err)
+}
+
+func Test_Postgresql_004(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// Create a new container with postgresql package
+	container, pool, err := test.NewPgxContainer(ctx, t.Name(), false, nil)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	defer container.Close(ctx)
+	defer pool.Close()
+
+	// Create a directory, then a tablespace in it
+	assert.NoError(container.MkdirForTablespace(ctx, "/var/lib/postgresql/tablespaces/test"))
+	assert.NoError(pool.Exec(ctx, `CREATE TABLESPACE test LOCATION '/var/lib/postgresql/tablespaces/test'`))
+	assert.NoError(pool.Exec(ctx, `DROP TABLESPACE test`))
+
+	// The location must be absolute
+	assert.ErrorIs(container.MkdirForTablespace(ctx, "tablespaces/test"), pg.ErrBadParameter)
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"context"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Recorder records the SQL statements which are executed, normalized so
+// that statements which differ only in whitespace or literal values are
+// the same
+type Recorder struct {
+	sync.Mutex
+	statements []string
+}
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Environment variable with the path of a file, to which the statements
+	// executed by the tests in a package are appended
+	RecordEnv = "PG_TEST_RECORD"
+)
+
+var (
+	// Recorder for the statements executed on the connections created by
+	// Main, NewManager and SharedManager
+	recorder = NewRecorder()
+
+	reWhitespace = regexp.MustCompile(`\s+`)
+	reString     = regexp.MustCompile(`'(?:[^']|'')*'`)
+	reNumber     = regexp.MustCompile(`(^|[^\w$."])\d+(?:\.\d+)?`)
+)
+
+/////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// NewRecorder returns an empty recorder
+func NewRecorder() *Recorder {
+	return new(Recorder)
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Record returns the statements executed by the function on the connections
+// created by Main, NewManager and SharedManager. Statements executed by tests
+// running in parallel are also recorded, so use it in tests which are not
+// parallel, for example to check the number of queries made by a request:
+//
+//	statements := test.Record(func() {
+//		_, err = mgr.ListRoles(ctx, schema.RoleListRequest{})
+//	})
+//	assert.Len(statements, 2)
+func Record(fn func()) []string {
+	return recorder.Record(fn)
+}
+
+// Trace records a statement, and can be passed to pg.WithTrace
+func (r *Recorder) Trace(_ context.Context, sql string, _ any, _ error) {
+	r.Lock()
+	defer r.Unlock()
+	r.statements = append(r.statements, Normalize(sql))
+}
+
+// Record returns the statements recorded while the function is called
+func (r *Recorder) Record(fn func()) []string {
+	start := r.Len()
+	fn()
+	r.Lock()
+	defer r.Unlock()
+	return slices.Clone(r.statements[min(start, len(r.statements)):])
+}
+
+// Len returns the number of statements recorded
+func (r *Recorder) Len() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.statements)
+}
+
+// Statements returns the statements recorded, in order of execution
+func (r *Recorder) Statements() []string {
+	r.Lock()
+	defer r.Unlock()
+	return slices.Clone(r.statements)
+}
+
+// Reset removes the statements recorded
+func (r *Recorder) Reset() {
+	r.Lock()
+	defer r.Unlock()
+	r.statements = nil
+}
+
+// Append writes the statements recorded to a file, one per line, creating
+// the file if it does not exist
+func (r *Recorder) Append(path string) error {
+	r.Lock()
+	defer r.Unlock()
+	if len(r.statements) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(strings.Join(r.statements, "\n") + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Normalize returns a statement on one line, with string and numeric
+// literals replaced by a question mark
+func Normalize(sql string) string {
+	sql = reString.ReplaceAllString(sql, "?")
+	sql = reNumber.ReplaceAllString(sql, "${1}?")
+	sql = reWhitespace.ReplaceAllString(sql, " ")
+	return strings.TrimSpace(sql)
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Write the statements recorded to the file in the environment variable,
+// if it is set, and then reset the recorder
+func appendRecord() {
+	if path := os.Getenv(RecordEnv); path != "" {
+		if err := recorder.Append(path); err != nil {
+			log.Printf("ERROR: %v", err)
+		}
+	}
+	recorder.Reset()
+}
@@ -0,0 +1,69 @@
+package test_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	// Packages
+	test "github.com/mutablelogic/go-pg/pkg/test"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// UNIT TESTS
+
+func Test_Normalize_001(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		{"SELECT 1", "SELECT ?"},
+		{"  SELECT *\n\tFROM t1  ", "SELECT * FROM t1"},
+		{"SELECT * FROM t WHERE name = 'it''s' AND id = 42", "SELECT * FROM t WHERE name = ? AND id = ?"},
+		{"SELECT * FROM t WHERE id = $1 LIMIT 10 OFFSET 20", "SELECT * FROM t WHERE id = $1 LIMIT ? OFFSET ?"},
+		{"SELECT 1.5, x2, pg_stat_statements FROM \"t3\"", "SELECT ?, x2, pg_stat_statements FROM \"t3\""},
+		{"SELECT * FROM t WHERE id = @id", "SELECT * FROM t WHERE id = @id"},
+	}
+	for _, test_ := range tests {
+		assert.Equal(test_.expected, test.Normalize(test_.sql), test_.sql)
+	}
+}
+
+func Test_Recorder_001(t *testing.T) {
+	assert := assert.New(t)
+	r := test.NewRecorder()
+
+	r.Trace(context.Background(), "SELECT 1", nil, nil)
+	statements := r.Record(func() {
+		r.Trace(context.Background(), "SELECT * FROM t WHERE id = 1", nil, nil)
+		r.Trace(context.Background(), "SELECT * FROM t WHERE id = 2", nil, nil)
+	})
+	assert.Equal([]string{"SELECT * FROM t WHERE id = ?", "SELECT * FROM t WHERE id = ?"}, statements)
+	assert.Equal(3, r.Len())
+	assert.Equal("SELECT ?", r.Statements()[0])
+
+	r.Reset()
+	assert.Equal(0, r.Len())
+}
+
+func Test_Recorder_002(t *testing.T) {
+	assert := assert.New(t)
+	r := test.NewRecorder()
+	path := filepath.Join(t.TempDir(), "statements.sql")
+
+	// Nothing recorded, so nothing written
+	assert.NoError(r.Append(path))
+	assert.NoFileExists(path)
+
+	// Statements are appended
+	r.Trace(context.Background(), "SELECT 1", nil, nil)
+	assert.NoError(r.Append(path))
+	assert.NoError(r.Append(path))
+	data, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal("SELECT ?\nSELECT ?\n", string(data))
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
+)
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Database used to copy the test database, which is not itself copied
+	pgxMaintenance = "postgres"
+
+	// Disconnect the other sessions from a database
+	pgxDisconnect = `SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE datname = @database AND pid <> pg_backend_pid()`
+)
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - CONTAINER
+
+// Snapshot copies the test database in a PostgreSQL container to a new
+// database with the given name, so that seed data can be created once and
+// restored before each test. Other sessions connected to the test database
+// are disconnected, so reset any connection pool afterwards.
+func (c *Container) Snapshot(ctx context.Context, name string) error {
+	database, err := c.GetEnv("POSTGRES_DB")
+	if err != nil {
+		return err
+	} else if name == "" || name == database || name == pgxMaintenance {
+		return pg.ErrBadParameter.Withf("invalid snapshot name %q", name)
+	}
+	return c.maintenance(ctx, func(conn pg.PoolConn) error {
+		if err := conn.Exec(ctx, `DROP DATABASE IF EXISTS `+quote.Ident(name)+` WITH (FORCE)`); err != nil {
+			return err
+		}
+		if err := conn.With("database", database).Exec(ctx, pgxDisconnect); err != nil {
+			return err
+		}
+		return conn.Exec(ctx, `CREATE DATABASE `+quote.Ident(name)+` TEMPLATE `+quote.Ident(database))
+	})
+}
+
+// Restore replaces the test database in a PostgreSQL container with a copy
+// of a snapshot, which is kept so it can be restored again. Sessions
+// connected to the test database are disconnected, so reset any connection
+// pool afterwards.
+func (c *Container) Restore(ctx context.Context, name string) error {
+	database, err := c.GetEnv("POSTGRES_DB")
+	if err != nil {
+		return err
+	} else if name == "" || name == database || name == pgxMaintenance {
+		return pg.ErrBadParameter.Withf("invalid snapshot name %q", name)
+	}
+	return c.maintenance(ctx, func(conn pg.PoolConn) error {
+		if err := conn.Exec(ctx, `DROP DATABASE IF EXISTS `+quote.Ident(database)+` WITH (FORCE)`); err != nil {
+			return err
+		}
+		return conn.Exec(ctx, `CREATE DATABASE `+quote.Ident(database)+` TEMPLATE `+quote.Ident(name))
+	})
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - CONNECTION
+
+// Snapshot copies the test database to a new database with the given name,
+// and fails the test on error. The connection pool is reset, so don't use
+// it in parallel tests.
+func (c *Conn) Snapshot(t *testing.T, name string) {
+	t.Helper()
+	if c.container == nil {
+		t.Fatal(pg.ErrNotAvailable.With("no container for snapshot"))
+	}
+	if err := c.container.Snapshot(context.Background(), name); err != nil {
+		t.Fatal(err)
+	}
+	c.PoolConn.Reset()
+}
+
+// Restore replaces the test database with a copy of a snapshot, and fails
+// the test on error. The connection pool is reset, so don't use it in
+// parallel tests.
+func (c *Conn) Restore(t *testing.T, name string) {
+	t.Helper()
+	if c.container == nil {
+		t.Fatal(pg.ErrNotAvailable.With("no container for restore"))
+	}
+	if err := c.container.Restore(context.Background(), name); err != nil {
+		t.Fatal(err)
+	}
+	c.PoolConn.Reset()
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Connect to the maintenance database in a PostgreSQL container, and call
+// the function with the connection
+func (c *Container) maintenance(ctx context.Context, fn func(pg.PoolConn) error) error {
+	user, err := c.GetEnv("POSTGRES_USER")
+	if err != nil {
+		return err
+	}
+	password, err := c.GetEnv("POSTGRES_PASSWORD")
+	if err != nil {
+		return err
+	}
+	port, err := c.GetPort(pgxPort)
+	if err != nil {
+		return err
+	}
+	host, _ := c.GetEnv("POSTGRES_HOST")
+
+	// Connect to the maintenance database
+	pool, err := pg.NewPool(ctx,
+		pg.WithCredentials(user, password),
+		pg.WithDatabase(pgxMaintenance),
+		pg.WithHostPort(host, port),
+	)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	// Call the function
+	return fn(pool)
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	exec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// User which owns the directories of tablespaces in the container
+	pgxTablespaceOwner = "postgres"
+)
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - CONTAINER
+
+// MkdirForTablespace creates an empty directory in a PostgreSQL container
+// which is owned by the server, so that it can be used as the location of
+// a tablespace. The path must be absolute, and should not be within the
+// data directory.
+func (c *Container) MkdirForTablespace(ctx context.Context, path string) error {
+	if !filepath.IsAbs(path) {
+		return pg.ErrBadParameter.Withf("location %q is not absolute", path)
+	}
+	for _, cmd := range [][]string{
+		{"mkdir", "-p", path},
+		{"chown", pgxTablespaceOwner + ":" + pgxTablespaceOwner, path},
+		{"chmod", "700", path},
+	} {
+		code, reader, err := c.Container.Exec(ctx, cmd, exec.WithUser("root"), exec.Multiplexed())
+		if err != nil {
+			return err
+		} else if code != 0 {
+			output, _ := io.ReadAll(reader)
+			return fmt.Errorf("%s: exit code %d: %s", cmd[0], code, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - CONNECTION
+
+// MkdirForTablespace creates an empty directory for the location of a
+// tablespace, and fails the test on error
+func (c *Conn) MkdirForTablespace(t *testing.T, path string) {
+	t.Helper()
+	if c.container == nil {
+		t.Fatal(pg.ErrNotAvailable.With("no container for tablespace"))
+	}
+	if err := c.container.MkdirForTablespace(context.Background(), path); err != nil {
+		t.Fatal(err)
+	}
+}
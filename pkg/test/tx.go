@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// TxConn is a connection within a transaction, which is rolled back when
+// the connection is closed, so that changes made by a test are not seen by
+// other tests. The connection is not safe for concurrent use.
+type TxConn struct {
+	pg.Conn
+	t      *testing.T
+	once   sync.Once
+	done   chan struct{}
+	result chan error
+}
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// Returned from the transaction to roll it back
+	errRollback = errors.New("rollback")
+)
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// BeginTx begins a test within a transaction, which is rolled back when the
+// connection is closed, or otherwise when the test ends. Tests which use
+// their own transaction can run in parallel, but changes which cannot be
+// made within a transaction, such as creating a database, are not isolated.
+func (c *Conn) BeginTx(t *testing.T) *TxConn {
+	t.Helper()
+	t.Log("Begin", t.Name())
+
+	// Run the transaction in the background until the connection is closed
+	tx := &TxConn{t: t, done: make(chan struct{}), result: make(chan error, 1)}
+	ready := make(chan pg.Conn)
+	go func() {
+		tx.result <- c.PoolConn.Tx(context.Background(), func(conn pg.Conn) error {
+			ready <- conn
+			<-tx.done
+			return errRollback
+		})
+	}()
+
+	// Wait for the transaction to begin
+	select {
+	case conn := <-ready:
+		tx.Conn = conn
+	case err := <-tx.result:
+		t.Fatal(err)
+	}
+
+	// Roll back the transaction when the test ends, if not closed before
+	t.Cleanup(tx.Close)
+
+	// Return the connection
+	return tx
+}
+
+// Close rolls back the transaction and ends the test
+func (c *TxConn) Close() {
+	c.once.Do(func() {
+		close(c.done)
+		if err := <-c.result; !errors.Is(err, errRollback) {
+			c.t.Error(err)
+		}
+		c.t.Log("End", c.t.Name())
+	})
+}
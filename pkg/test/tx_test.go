@@ -0,0 +1,60 @@
+package test_test
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	test "github.com/mutablelogic/go-pg/pkg/test"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// txConn is a connection which records the result of each transaction
+type txConn struct {
+	pg.PoolConn
+	results []error
+}
+
+func (c *txConn) Tx(ctx context.Context, fn func(pg.Conn) error) error {
+	err := fn(&execConn{log: &execs{}})
+	c.results = append(c.results, err)
+	return err
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// UNIT TESTS
+
+func Test_BeginTx_001(t *testing.T) {
+	assert := assert.New(t)
+	pool := &txConn{}
+	conn := &test.Conn{PoolConn: pool}
+
+	// The transaction is open until the connection is closed
+	tx := conn.BeginTx(t)
+	assert.NoError(tx.Exec(context.Background(), "CREATE ROLE test"))
+	assert.Empty(pool.results)
+
+	// Closing the connection rolls back the transaction, and can be repeated
+	tx.Close()
+	tx.Close()
+	if assert.Len(pool.results, 1) {
+		assert.Error(pool.results[0])
+	}
+}
+
+func Test_BeginTx_002(t *testing.T) {
+	assert := assert.New(t)
+	pool := &txConn{}
+	conn := &test.Conn{PoolConn: pool}
+
+	// The transaction is rolled back when the subtest ends
+	t.Run("Subtest", func(t *testing.T) {
+		tx := conn.BeginTx(t)
+		assert.NoError(tx.Exec(context.Background(), "CREATE ROLE test"))
+	})
+	assert.Len(pool.results, 1)
+}
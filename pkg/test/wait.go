@@ -0,0 +1,108 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// query is a selector for a query which is passed as a string
+type query string
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Timeout for WaitForRow
+	WaitTimeout = 10 * time.Second
+
+	// Initial and maximum delay between checks of a condition
+	waitDelay    = 10 * time.Millisecond
+	waitMaxDelay = 250 * time.Millisecond
+)
+
+var (
+	errRow = errors.New("row found")
+)
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Eventually calls the condition until it returns true, and fails the test
+// if it does not return true within the timeout. The delay between calls
+// starts small and increases, so that changes which propagate quickly, such
+// as a notification, are seen without waiting.
+func Eventually(t testing.TB, cond func() bool, timeout time.Duration) {
+	t.Helper()
+	if err := poll(timeout, func() error {
+		if cond() {
+			return nil
+		}
+		return pg.ErrNotFound
+	}); err != nil {
+		t.Fatalf("condition not met within %v", timeout)
+	}
+}
+
+// WaitForRow runs the query until it returns at least one row, and fails the
+// test if there is no row within WaitTimeout. Errors from the query, such as
+// a table which does not exist yet, are retried. The arguments are name and
+// value pairs, which are bound to the query as named arguments.
+func WaitForRow(t testing.TB, conn pg.Conn, sql string, args ...any) {
+	t.Helper()
+	if len(args)%2 != 0 {
+		t.Fatalf("WaitForRow: expected name and value pairs, got %d arguments", len(args))
+	}
+	if err := poll(WaitTimeout, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), WaitTimeout)
+		defer cancel()
+		err := conn.With(args...).ListStream(ctx, query(sql), func(pg.Row) error {
+			return errRow
+		})
+		switch {
+		case errors.Is(err, errRow):
+			return nil
+		case err == nil:
+			return pg.ErrNotFound.With("no rows")
+		default:
+			return err
+		}
+	}); err != nil {
+		t.Fatalf("no row within %v: %v", WaitTimeout, err)
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Select returns the query for a list
+func (q query) Select(bind *pg.Bind, op pg.Op) (string, error) {
+	switch op {
+	case pg.List:
+		return string(q), nil
+	default:
+		return "", pg.ErrNotImplemented.Withf("unsupported query operation %q", op)
+	}
+}
+
+// Call the function until it returns nil or the timeout expires, and
+// return the last error
+func poll(timeout time.Duration, fn func() error) error {
+	deadline := time.Now().Add(timeout)
+	delay := waitDelay
+	for {
+		err := fn()
+		if err == nil || time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(min(delay, time.Until(deadline)))
+		delay = min(delay*2, waitMaxDelay)
+	}
+}
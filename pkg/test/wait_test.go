@@ -0,0 +1,104 @@
+package test_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	test "github.com/mutablelogic/go-pg/pkg/test"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// fatal records a call to Fatalf rather than ending the test
+type fatal struct {
+	testing.TB
+	msg string
+}
+
+func (f *fatal) Helper() {}
+
+func (f *fatal) Fatalf(format string, args ...any) {
+	f.msg = fmt.Sprintf(format, args...)
+}
+
+// rows is a connection which returns no rows until a number of queries
+// have been made
+type rows struct {
+	pg.Conn
+	calls atomic.Int32
+	after int32
+	err   error
+	sql   string
+	args  []any
+}
+
+func (r *rows) With(args ...any) pg.Conn {
+	r.args = args
+	return r
+}
+
+func (r *rows) ListStream(_ context.Context, sel pg.Selector, fn func(pg.Row) error) error {
+	sql, err := sel.Select(pg.NewBind(), pg.List)
+	if err != nil {
+		return err
+	}
+	r.sql = sql
+	if r.calls.Add(1) <= r.after {
+		return r.err
+	}
+	return fn(nil)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// UNIT TESTS
+
+func Test_Eventually_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// Condition is met on the third call
+	var calls int
+	test.Eventually(t, func() bool {
+		calls++
+		return calls == 3
+	}, time.Second)
+	assert.Equal(3, calls)
+}
+
+func Test_Eventually_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// Condition is never met
+	tb := &fatal{TB: t}
+	start := time.Now()
+	test.Eventually(tb, func() bool { return false }, 100*time.Millisecond)
+	assert.Contains(tb.msg, "condition not met")
+	assert.GreaterOrEqual(time.Since(start), 100*time.Millisecond)
+}
+
+func Test_WaitForRow_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// Row is returned on the third query, and errors are retried
+	conn := &rows{after: 2, err: errors.New("relation does not exist")}
+	test.WaitForRow(t, conn, "SELECT 1 FROM test WHERE id = @id", "id", 1)
+	assert.Equal(int32(3), conn.calls.Load())
+	assert.Equal("SELECT 1 FROM test WHERE id = @id", conn.sql)
+	assert.Equal([]any{"id", 1}, conn.args)
+}
+
+func Test_WaitForRow_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// Odd number of arguments
+	tb := &fatal{TB: t}
+	test.WaitForRow(tb, &rows{}, "SELECT 1", "id")
+	assert.Contains(tb.msg, "name and value pairs")
+}
@@ -0,0 +1,129 @@
+package version
+
+import (
+	"cmp"
+	"regexp"
+	"strconv"
+	"strings"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// semver is a parsed semantic version
+type semver struct {
+	core  [3]uint64
+	pre   []string // Pre-release identifiers
+	after bool     // A commit after the tag, as reported by git describe
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// A semantic version, with an optional "v" prefix, pre-release and build
+	reSemver = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+	// The suffix which git describe adds for commits after a tag
+	reDescribe = regexp.MustCompile(`(?:^|-)\d+-g[0-9a-f]+$`)
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Compare returns -1, 0 or +1 when the semantic version a is before, the same
+// as, or after the version b. Versions can have a "v" prefix, and the suffix
+// which git describe adds for commits after a tag, such as "v1.2.3-4-gabcdef0",
+// which is after "v1.2.3". Returns ErrBadParameter when either is not a
+// semantic version.
+func Compare(a, b string) (int, error) {
+	va, err := parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.compare(vb), nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func parse(v string) (semver, error) {
+	var result semver
+	match := reSemver.FindStringSubmatch(strings.TrimSpace(v))
+	if match == nil {
+		return result, pg.ErrBadParameter.Withf("not a semantic version: %q", v)
+	}
+	for i := range result.core {
+		n, err := strconv.ParseUint(match[i+1], 10, 64)
+		if err != nil {
+			return result, pg.ErrBadParameter.Withf("not a semantic version: %q", v)
+		}
+		result.core[i] = n
+	}
+	pre := match[4]
+	if loc := reDescribe.FindStringIndex(pre); loc != nil {
+		result.after = true
+		pre = pre[:loc[0]]
+	}
+	if pre != "" {
+		result.pre = strings.Split(pre, ".")
+	}
+	return result, nil
+}
+
+func (a semver) compare(b semver) int {
+	for i := range a.core {
+		if c := cmp.Compare(a.core[i], b.core[i]); c != 0 {
+			return c
+		}
+	}
+
+	// A version without pre-release identifiers is after one with them
+	switch {
+	case len(a.pre) == 0 && len(b.pre) > 0:
+		return 1
+	case len(a.pre) > 0 && len(b.pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.pre) && i < len(b.pre); i++ {
+		if c := comparePre(a.pre[i], b.pre[i]); c != 0 {
+			return c
+		}
+	}
+	if c := cmp.Compare(len(a.pre), len(b.pre)); c != 0 {
+		return c
+	}
+
+	// A commit after the tag is after the tag
+	switch {
+	case a.after && !b.after:
+		return 1
+	case !a.after && b.after:
+		return -1
+	}
+	return 0
+}
+
+// Compare pre-release identifiers, where numeric identifiers are compared
+// numerically and are before alphanumeric identifiers
+func comparePre(a, b string) int {
+	na, erra := strconv.ParseUint(a, 10, 64)
+	nb, errb := strconv.ParseUint(b, 10, 64)
+	switch {
+	case erra == nil && errb == nil:
+		return cmp.Compare(na, nb)
+	case erra == nil:
+		return -1
+	case errb == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
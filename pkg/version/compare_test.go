@@ -0,0 +1,48 @@
+package version_test
+
+import (
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	version "github.com/mutablelogic/go-pg/pkg/version"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Compare(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		A, B   string
+		Result int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"1.2.3", "v1.2.3", 0},
+		{"v1.2.3", "v1.2.4", -1},
+		{"v1.10.0", "v1.9.9", 1},
+		{"v2.0.0", "v1.99.99", 1},
+		{"v1.2.3-rc1", "v1.2.3", -1},
+		{"v1.2.3-alpha", "v1.2.3-beta", -1},
+		{"v1.2.3-alpha.2", "v1.2.3-alpha.10", -1},
+		{"v1.2.3-alpha", "v1.2.3-alpha.1", -1},
+		{"v1.2.3-1", "v1.2.3-alpha", -1},
+		{"v1.2.3+build", "v1.2.3", 0},
+		{"v1.2.3-4-gabcdef0", "v1.2.3", 1},
+		{"v1.2.3-4-gabcdef0", "v1.2.4", -1},
+		{"v1.2.3-rc1-4-gabcdef0", "v1.2.3-rc1", 1},
+		{"v1.2.3-rc1-4-gabcdef0", "v1.2.3", -1},
+	}
+	for _, test := range tests {
+		result, err := version.Compare(test.A, test.B)
+		if assert.NoError(err, test) {
+			assert.Equal(test.Result, result, test)
+		}
+	}
+
+	t.Run("NotVersion", func(t *testing.T) {
+		for _, v := range []string{"", "dev", "main", "abcdef0", "v1.2"} {
+			_, err := version.Compare(v, "v1.2.3")
+			assert.ErrorIs(err, pg.ErrBadParameter, v)
+		}
+	})
+}
@@ -3,12 +3,16 @@ package pg
 import (
 	"context"
 	"errors"
+	"maps"
 	"strings"
+	"time"
 
 	// Packages
 	pgx "github.com/jackc/pgx/v5"
+	multitracer "github.com/jackc/pgx/v5/multitracer"
 	pgconn "github.com/jackc/pgx/v5/pgconn"
 	pgxpool "github.com/jackc/pgx/v5/pgxpool"
+	singleflight "golang.org/x/sync/singleflight"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -28,15 +32,33 @@ type PoolConn interface {
 
 	// Return a listener for the connection pool
 	Listener() Listener
+
+	// Return the connection parameters, excluding pool parameters
+	Params() map[string]string
+
+	// Return the prepared statement cache counters, which are zero unless
+	// the pool was created with WithStatementCache
+	StatementCacheStats() StatementCacheStats
+
+	// Return the connection pool statistics, which can be used to alert on
+	// pool exhaustion
+	Stat() PoolStat
 }
 
 type pool struct {
 	*pgxpool.Pool
+	params    map[string]string
+	stmtcache *stmtcache
+	stat      *poolstat
+	retry     *RetryPolicy
+	failover  *failover
+	timeout   time.Duration
 }
 
 type poolconn struct {
-	conn *pool
-	bind *Bind
+	conn  *pool
+	bind  *Bind
+	group *singleflight.Group
 }
 
 // Ensure interfaces are satisfied
@@ -57,9 +79,16 @@ func NewPool(ctx context.Context, opts ...Opt) (PoolConn, error) {
 		return nil, err
 	}
 
+	// Use the TLS configuration, if set
+	o.configureTLS(&poolconfig.ConnConfig.Config)
+
+	// Count connection failures for the pool statistics
+	stat := new(poolstat)
+	tracers := []pgx.QueryTracer{stat}
+
 	// If there is a trace function, then set it
 	if o.TraceFn != nil {
-		poolconfig.ConnConfig.Tracer = NewTracer(o.TraceFn)
+		tracers = append(tracers, NewTracer(o.TraceFn))
 
 		// Output the connection parameters
 		parts := map[string]string{}
@@ -70,14 +99,44 @@ func NewPool(ctx context.Context, opts ...Opt) (PoolConn, error) {
 		o.TraceFn(ctx, "CONNECT", parts, nil)
 	}
 
+	// If there is a logger, then log each query
+	if o.logger != nil {
+		tracers = append(tracers, newLogger(o.logger, o.loglevel, o.logsample, o.logslow, o.logredact))
+	}
+
+	// If there is a statement cache, then count the hits and misses
+	if o.stmtcache != nil {
+		tracers = append(tracers, o.stmtcache)
+	}
+
+	// Set the tracers
+	if len(tracers) == 1 {
+		poolconfig.ConnConfig.Tracer = tracers[0]
+	} else {
+		poolconfig.ConnConfig.Tracer = multitracer.New(tracers...)
+	}
+
 	// Return the connection pool
 	p, err := pgxpool.NewWithConfig(ctx, poolconfig)
 	if err != nil {
 		return nil, err
 	}
 
+	// Share identical concurrent reads if deduplication is enabled
+	var group *singleflight.Group
+	if o.dedup {
+		group = new(singleflight.Group)
+	}
+
+	// Check that the connections are to the target server, when there is
+	// more than one host
+	var failover *failover
+	if period, standby := o.failoverCheck(); period > 0 {
+		failover = newFailover(p, period, standby)
+	}
+
 	// Wrap the connection pool as if it's a transaction
-	return &poolconn{&pool{p}, o.bind}, nil
+	return &poolconn{&pool{p, o.params(), o.stmtcache, stat, o.retry, failover, o.timeout}, o.bind, group}, nil
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -111,6 +170,9 @@ func (p *poolconn) Ping(ctx context.Context) error {
 }
 
 func (p *poolconn) Close() {
+	if p.conn.failover != nil {
+		p.conn.failover.Close()
+	}
 	p.conn.Pool.Close()
 }
 
@@ -118,19 +180,46 @@ func (p *poolconn) Reset() {
 	p.conn.Pool.Reset()
 }
 
+// Return a copy of the connection parameters, which can be used when
+// invoking external tools such as pg_dump
+func (p *poolconn) Params() map[string]string {
+	return maps.Clone(p.conn.params)
+}
+
+// Return the prepared statement cache counters
+func (p *poolconn) StatementCacheStats() StatementCacheStats {
+	if p.conn.stmtcache == nil {
+		return StatementCacheStats{}
+	}
+	return p.conn.stmtcache.Stats()
+}
+
+// Return the connection pool statistics
+func (p *poolconn) Stat() PoolStat {
+	stat := p.conn.stat.Stat(p.conn.Pool.Stat())
+	if p.conn.failover != nil {
+		stat.Failovers = p.conn.failover.resets.Load()
+	}
+	return stat
+}
+
 // Return a new connection with new bound parameters
 func (p *poolconn) With(params ...any) Conn {
-	return &poolconn{p.conn, p.bind.Copy(params...)}
+	return &poolconn{p.conn, p.bind.Copy(params...), p.group}
 }
 
 // Return a new connection to a remote database
 func (p *poolconn) Remote(database string) Conn {
-	return &poolconn{p.conn, p.bind.withRemote(database)}
+	return &poolconn{p.conn, p.bind.withRemote(database), p.group}
 }
 
-// Perform a transaction, then commit or rollback
+// Perform a transaction, then commit or rollback. The transaction is
+// retried after transient errors if there is a retry policy, so the
+// function may be called more than once
 func (p *poolconn) Tx(ctx context.Context, fn func(conn Conn) error) error {
-	return tx(ctx, p.conn, p.bind, fn)
+	return p.conn.do(ctx, func(ctx context.Context) error {
+		return tx(ctx, p.conn, p.bind, fn)
+	})
 }
 
 // Perform a bulk operation
@@ -138,32 +227,126 @@ func (p *poolconn) Bulk(ctx context.Context, fn func(conn Conn) error) error {
 	return bulk(ctx, p.conn, p.bind, fn)
 }
 
-// Execute a query
-func (p *poolconn) Exec(ctx context.Context, query string) error {
-	return p.bind.Exec(ctx, p.conn, query)
+// Execute a query. When the statement has a timeout, it is set for the
+// statement rather than as the timeout of the operation
+func (p *poolconn) Exec(ctx context.Context, query string, opts ...ExecOpt) error {
+	if timeout := applyExec(opts...); timeout > 0 {
+		return p.conn.doTimeout(ctx, 0, func(ctx context.Context) error {
+			return execTimeout(ctx, p.conn, p.bind, query, timeout)
+		})
+	}
+	return p.conn.do(ctx, func(ctx context.Context) error {
+		return pgerror(p.bind.Exec(ctx, p.conn, query))
+	})
 }
 
 // Perform an insert
 func (p *poolconn) Insert(ctx context.Context, reader Reader, writer Writer) error {
-	return insert(ctx, p.conn, p.bind, reader, writer)
+	return p.conn.do(ctx, func(ctx context.Context) error {
+		return insert(ctx, p.conn, p.bind, reader, writer)
+	})
+}
+
+// Perform an upsert
+func (p *poolconn) Upsert(ctx context.Context, reader Reader, writer Writer, target ...string) error {
+	return p.conn.do(ctx, func(ctx context.Context) error {
+		return upsert(ctx, p.conn, p.bind, reader, writer, target...)
+	})
 }
 
 // Perform a update
 func (p *poolconn) Update(ctx context.Context, reader Reader, sel Selector, writer Writer) error {
-	return update(ctx, p.conn, p.bind, reader, sel, writer)
+	return p.conn.do(ctx, func(ctx context.Context) error {
+		return update(ctx, p.conn, p.bind, reader, sel, writer)
+	})
 }
 
 // Perform a delete
 func (p *poolconn) Delete(ctx context.Context, reader Reader, sel Selector) error {
-	return del(ctx, p.conn, p.bind, reader, sel)
+	return p.conn.do(ctx, func(ctx context.Context) error {
+		return del(ctx, p.conn, p.bind, reader, sel)
+	})
+}
+
+// Perform an update, returning the number of rows updated
+func (p *poolconn) UpdateMany(ctx context.Context, sel Selector, writer Writer) (int64, error) {
+	return rowsAffected(ctx, func(ctx context.Context) error {
+		return p.conn.do(ctx, func(ctx context.Context) error {
+			return update(ctx, p.conn, p.bind, nil, sel, writer)
+		})
+	})
+}
+
+// Perform a delete, returning the number of rows deleted
+func (p *poolconn) DeleteMany(ctx context.Context, sel Selector) (int64, error) {
+	return rowsAffected(ctx, func(ctx context.Context) error {
+		return p.conn.do(ctx, func(ctx context.Context) error {
+			return del(ctx, p.conn, p.bind, nil, sel)
+		})
+	})
 }
 
 // Perform a get
 func (p *poolconn) Get(ctx context.Context, reader Reader, sel Selector) error {
-	return get(ctx, p.conn, p.bind, reader, sel)
+	return p.conn.do(ctx, func(ctx context.Context) error {
+		return get(ctx, p.reader(), p.bind, reader, sel)
+	})
 }
 
 // Perform a list
 func (p *poolconn) List(ctx context.Context, reader Reader, sel Selector) error {
-	return list(ctx, p.conn, p.bind, reader, sel)
+	return p.conn.do(ctx, func(ctx context.Context) error {
+		return list(ctx, p.reader(), p.bind, reader, sel)
+	})
+}
+
+// Perform a list, binding parameters with the selector and calling the
+// function for each row. Rows are not shared when deduplication is enabled.
+func (p *poolconn) ListStream(ctx context.Context, sel Selector, fn func(Row) error) error {
+	return stream(ctx, p.conn, p.bind, sel, fn)
+}
+
+// Declare a cursor within a transaction, which fetches rows in batches
+func (p *poolconn) Cursor(ctx context.Context, query string, batchSize uint) (Cursor, error) {
+	return declare(ctx, p.conn, p.bind, query, batchSize)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS - POOL
+
+// Perform an operation, retrying after transient errors if there is a retry
+// policy, and closing the connections when the error shows they are to a
+// server which no longer matches the target session attributes. Each attempt
+// is cancelled after the query timeout of the pool, if set.
+func (p *pool) do(ctx context.Context, fn func(context.Context) error) error {
+	return p.doTimeout(ctx, p.timeout, fn)
+}
+
+// Perform an operation with a timeout for each attempt, or no timeout when
+// the timeout is zero
+func (p *pool) doTimeout(ctx context.Context, timeout time.Duration, fn func(context.Context) error) error {
+	err := p.retry.Do(ctx, func() error {
+		if timeout <= 0 {
+			return fn(ctx)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return fn(ctx)
+	})
+	if err != nil && p.failover != nil {
+		p.failover.observe(err)
+	}
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS - POOLCONN
+
+// Return the connection used for reads, which shares identical concurrent
+// queries when deduplication is enabled
+func (p *poolconn) reader() pgx.Tx {
+	if p.group == nil {
+		return p.conn
+	}
+	return &dedup{p.conn, p.group}
 }
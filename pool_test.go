@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	// Packages
 	pg "github.com/mutablelogic/go-pg"
@@ -15,9 +16,10 @@ import (
 // Global connection variable
 var conn test.Conn
 
-// Start up a container and test the pool
+// Start up a container for each version in PG_TEST_VERSIONS, or the default
+// version when it is not set, and run the tests
 func TestMain(m *testing.M) {
-	test.Main(m, &conn)
+	test.MainMatrix(m, &conn)
 }
 
 func Test_Pool_001(t *testing.T) {
@@ -129,6 +131,239 @@ func Test_Pool_003(t *testing.T) {
 	assert.NoError(err)
 }
 
+func Test_Pool_004(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// Acquire a connection, and check the statistics
+	assert.NoError(conn.Ping(context.Background()))
+	stat := conn.Stat()
+	assert.Greater(stat.MaxConns, int32(0))
+	assert.Greater(stat.TotalConns, int32(0))
+	assert.Greater(stat.AcquireCount, int64(0))
+	assert.Equal(stat.TotalConns, stat.IdleConns+stat.AcquiredConns+stat.ConstructingConns)
+	assert.Equal(uint64(0), stat.ConstructErrors)
+}
+
+func Test_Pool_005(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// The position advances after a write
+	before, err := pg.CurrentLSN(context.Background(), conn)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.NoError(conn.Exec(context.Background(), `CREATE TEMPORARY TABLE lsn_test (id INTEGER)`))
+	after, err := pg.CurrentLSN(context.Background(), conn)
+	assert.NoError(err)
+	assert.Greater(after, before)
+
+	// The primary is not a replica, so does not wait
+	assert.NoError(pg.WaitForLSN(context.Background(), conn, after))
+}
+
+func Test_Pool_006(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// Create a table
+	assert.NoError(conn.Exec(context.Background(), `CREATE TEMPORARY TABLE result_test (id INTEGER)`))
+
+	// Rows affected are returned in the result
+	var result pg.Result
+	ctx := pg.ContextWithResult(context.Background(), &result)
+	assert.NoError(conn.Exec(ctx, `INSERT INTO result_test (id) VALUES (1), (2), (3)`))
+	assert.Equal("INSERT 0 3", result.CommandTag)
+	assert.Equal(int64(3), result.RowsAffected)
+	assert.Positive(result.Duration)
+
+	assert.NoError(conn.Exec(ctx, `DELETE FROM result_test WHERE id > 1`))
+	assert.Equal(int64(2), result.RowsAffected)
+}
+
+func Test_Pool_007(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// Create a table
+	assert.NoError(conn.Exec(context.Background(), `CREATE TABLE IF NOT EXISTS panic_test (id INTEGER)`))
+	defer conn.Exec(context.Background(), `DROP TABLE panic_test`)
+
+	// A panic within a transaction rolls back the insert, and panics again
+	assert.PanicsWithValue("oops", func() {
+		conn.Tx(context.Background(), func(conn pg.Conn) error {
+			if err := conn.Exec(context.Background(), `INSERT INTO panic_test (id) VALUES (1)`); err != nil {
+				return err
+			}
+			panic("oops")
+		})
+	})
+
+	// The connection can be used, and the row was not inserted
+	var result pg.Result
+	assert.NoError(conn.Exec(pg.ContextWithResult(context.Background(), &result), `DELETE FROM panic_test`))
+	assert.Zero(result.RowsAffected)
+}
+
+func Test_Pool_008(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// Create a table
+	assert.NoError(conn.Exec(context.Background(), `CREATE TABLE IF NOT EXISTS bind_test (tenant TEXT NOT NULL)`))
+	defer conn.Exec(context.Background(), `DROP TABLE bind_test`)
+
+	// Bind vars from the context, and its parent, are bound for every statement
+	ctx := pg.ContextWithBind(pg.ContextWithBind(context.Background(), "tenant", "acme"), "table", "bind_test")
+	assert.NoError(conn.Exec(ctx, `INSERT INTO ${"table"} (tenant) VALUES (@tenant)`))
+	assert.NoError(conn.Tx(ctx, func(conn pg.Conn) error {
+		return conn.Exec(ctx, `INSERT INTO bind_test (tenant) VALUES (@tenant)`)
+	}))
+
+	// Bind vars of the statement are not replaced
+	assert.NoError(conn.With("tenant", "other").Exec(ctx, `INSERT INTO bind_test (tenant) VALUES (@tenant)`))
+
+	// Only the rows for the tenant are deleted
+	var result pg.Result
+	assert.NoError(conn.Exec(pg.ContextWithResult(ctx, &result), `DELETE FROM bind_test WHERE tenant = @tenant`))
+	assert.Equal(int64(2), result.RowsAffected)
+	assert.NoError(conn.Exec(pg.ContextWithResult(ctx, &result), `DELETE FROM bind_test`))
+	assert.Equal(int64(1), result.RowsAffected)
+}
+
+func Test_Pool_009(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// Create tables with constraints
+	assert.NoError(conn.Exec(context.Background(), `CREATE TABLE IF NOT EXISTS err_parent (id INTEGER PRIMARY KEY CHECK (id > 0))`))
+	defer conn.Exec(context.Background(), `DROP TABLE err_parent`)
+	assert.NoError(conn.Exec(context.Background(), `CREATE TABLE IF NOT EXISTS err_child (parent INTEGER REFERENCES err_parent (id))`))
+	defer conn.Exec(context.Background(), `DROP TABLE err_child`)
+	assert.NoError(conn.Exec(context.Background(), `INSERT INTO err_parent (id) VALUES (1)`))
+
+	// Constraint violations are returned as typed errors
+	err := conn.Exec(context.Background(), `INSERT INTO err_parent (id) VALUES (1)`)
+	assert.ErrorIs(err, pg.ErrUniqueViolation)
+	assert.NotErrorIs(err, pg.ErrCheckViolation)
+	assert.ErrorIs(conn.Exec(context.Background(), `INSERT INTO err_parent (id) VALUES (-1)`), pg.ErrCheckViolation)
+	assert.ErrorIs(conn.Exec(context.Background(), `INSERT INTO err_child (parent) VALUES (2)`), pg.ErrForeignKeyViolation)
+	assert.ErrorIs(conn.Tx(context.Background(), func(conn pg.Conn) error {
+		return conn.Exec(context.Background(), `INSERT INTO err_parent (id) VALUES (1)`)
+	}), pg.ErrUniqueViolation)
+}
+
+func Test_Pool_010(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// A statement which runs for longer than the timeout is cancelled
+	err := conn.Exec(context.Background(), `SELECT pg_sleep(5)`, pg.Timeout(100*time.Millisecond))
+	assert.Error(err)
+
+	// A statement within the timeout succeeds, also within a transaction,
+	// where the timeout is restored afterwards
+	assert.NoError(conn.Exec(context.Background(), `SELECT pg_sleep(0.01)`, pg.Timeout(time.Second)))
+	assert.NoError(conn.Tx(context.Background(), func(conn pg.Conn) error {
+		if err := conn.Exec(context.Background(), `SELECT 1`, pg.Timeout(time.Second)); err != nil {
+			return err
+		}
+		return conn.Exec(context.Background(), `SELECT pg_sleep(1.1)`)
+	}))
+}
+
+func Test_Pool_011(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	type Record struct {
+		Id        int        `pg:"id,pk,auto"`
+		Value     string     `pg:"value"`
+		Version   int        `pg:"version,version"`
+		DeletedAt *time.Time `pg:"deleted_at,deleted"`
+	}
+
+	// Create a table with soft deletes and versions
+	assert.NoError(conn.Exec(context.Background(), `CREATE TABLE IF NOT EXISTS versioned (id SERIAL PRIMARY KEY, value TEXT NOT NULL, version INTEGER NOT NULL DEFAULT 0, deleted_at TIMESTAMPTZ)`))
+	defer conn.Exec(context.Background(), `DROP TABLE versioned`)
+
+	// Insert and update a record, which increments the version
+	record := Record{Value: "a"}
+	table, err := pg.NewTable(&record, "versioned")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.NoError(conn.Insert(context.Background(), table, table))
+	record.Value = "b"
+	assert.NoError(conn.Update(context.Background(), table, table, table))
+	assert.Equal(1, record.Version)
+
+	// Updating a stale copy is a conflict
+	stale := record
+	stale.Version = 0
+	staleTable, err := pg.NewTable(&stale, "versioned")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.ErrorIs(conn.Update(context.Background(), staleTable, staleTable, staleTable), pg.ErrConflict)
+
+	// Delete the record, after which it is not found
+	assert.NoError(conn.Delete(context.Background(), table, table))
+	assert.NotNil(record.DeletedAt)
+	assert.ErrorIs(conn.Get(context.Background(), table, table), pg.ErrNotFound)
+	assert.ErrorIs(conn.Update(context.Background(), table, table, table), pg.ErrNotFound)
+
+	// The deleted record is not listed
+	var records []Record
+	list, err := pg.NewTable(&records, "versioned")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.NoError(conn.List(context.Background(), list, list))
+	assert.Empty(records)
+}
+
+func Test_Pool_012(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	type Record struct {
+		Name  string `pg:"name,pk"`
+		Value int    `pg:"value"`
+	}
+
+	// Create a table with a unique name
+	assert.NoError(conn.Exec(context.Background(), `CREATE TABLE IF NOT EXISTS upsert (name TEXT PRIMARY KEY, value INTEGER NOT NULL)`))
+	defer conn.Exec(context.Background(), `DROP TABLE upsert`)
+
+	// Insert, then update the conflicting row
+	record := Record{Name: "a", Value: 1}
+	table, err := pg.NewTable(&record, "upsert")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.NoError(conn.Upsert(context.Background(), table, table, "name"))
+	record.Value = 2
+	assert.NoError(conn.Upsert(context.Background(), table, table, "name"))
+	assert.Equal(2, record.Value)
+
+	// Without a conflict target, nothing is done and no row is returned
+	record.Value = 3
+	assert.ErrorIs(conn.Upsert(context.Background(), table, table), pg.ErrNotFound)
+	assert.NoError(conn.Get(context.Background(), table, table))
+	assert.Equal(2, record.Value)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 type Test struct {
@@ -0,0 +1,77 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	// Packages
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+)
+
+//////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Result is the effect of a statement, which is set for each statement
+// executed with a context returned by ContextWithResult
+type Result struct {
+	CommandTag   string        `json:"command_tag"`   // Command tag such as "INSERT 0 1"
+	RowsAffected int64         `json:"rows_affected"` // Rows inserted, updated, deleted or returned
+	Duration     time.Duration `json:"duration"`      // Time to execute the statement and read the rows
+}
+
+// resultKey is the context key for the result
+type resultKey struct{}
+
+//////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ContextWithResult returns a context which sets the result of each statement
+// executed with it, so that the effect of Exec, Insert, Update and Delete can
+// be logged or checked without another query. When several statements are
+// executed, the result is for the last one:
+//
+//	var result pg.Result
+//	err := conn.Delete(pg.ContextWithResult(ctx, &result), &record, record)
+//	fmt.Println(result.RowsAffected)
+func ContextWithResult(ctx context.Context, result *Result) context.Context {
+	return context.WithValue(ctx, resultKey{}, result)
+}
+
+//////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (r Result) String() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+//////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Perform an operation without a reader, returning the number of rows
+// affected, and setting the result in the context, if there is one
+func rowsAffected(ctx context.Context, fn func(context.Context) error) (int64, error) {
+	var result Result
+	if err := fn(ContextWithResult(ctx, &result)); err != nil {
+		return 0, err
+	}
+	if parent, ok := ctx.Value(resultKey{}).(*Result); ok && parent != nil {
+		*parent = result
+	}
+	return result.RowsAffected, nil
+}
+
+// Set the result of a statement in the context, if there is one
+func setResult(ctx context.Context, tag pgconn.CommandTag, start time.Time) {
+	if result, ok := ctx.Value(resultKey{}).(*Result); ok && result != nil {
+		*result = Result{
+			CommandTag:   tag.String(),
+			RowsAffected: tag.RowsAffected(),
+			Duration:     time.Since(start),
+		}
+	}
+}
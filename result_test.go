@@ -0,0 +1,92 @@
+package pg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	assert "github.com/stretchr/testify/assert"
+)
+
+// tagTx returns a command tag from Exec
+type tagTx struct {
+	pgx.Tx
+	tag string
+	err error
+}
+
+func (tx *tagTx) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.NewCommandTag(tx.tag), tx.err
+}
+
+func Test_Result_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// The result is set from the command tag
+	var result Result
+	ctx := ContextWithResult(context.Background(), &result)
+	assert.NoError(NewBind().Exec(ctx, &tagTx{tag: "DELETE 3"}, `DELETE FROM test`))
+	assert.Equal("DELETE 3", result.CommandTag)
+	assert.Equal(int64(3), result.RowsAffected)
+	assert.GreaterOrEqual(result.Duration, time.Duration(0))
+}
+
+func Test_Result_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// Without a result in the context, nothing is set
+	assert.NoError(NewBind().Exec(context.Background(), &tagTx{tag: "INSERT 0 1"}, `INSERT INTO test DEFAULT VALUES`))
+
+	// The result is not set on error
+	result := Result{CommandTag: "UPDATE 1", RowsAffected: 1}
+	ctx := ContextWithResult(context.Background(), &result)
+	assert.Error(NewBind().Exec(ctx, &tagTx{err: ErrBadParameter}, `UPDATE test SET id = 1`))
+	assert.Equal("UPDATE 1", result.CommandTag)
+}
+
+func Test_Result_003(t *testing.T) {
+	assert := assert.New(t)
+
+	// The last statement sets the result
+	var result Result
+	ctx := ContextWithResult(context.Background(), &result)
+	assert.NoError(NewBind().Exec(ctx, &tagTx{tag: "INSERT 0 2"}, `INSERT INTO test DEFAULT VALUES`))
+	assert.NoError(NewBind().Exec(ctx, &tagTx{tag: "UPDATE 5"}, `UPDATE test SET id = 1`))
+	assert.Equal(int64(5), result.RowsAffected)
+	assert.Contains(result.String(), `"command_tag": "UPDATE 5"`)
+}
+
+func Test_Result_004(t *testing.T) {
+	assert := assert.New(t)
+	record := struct {
+		Id    int    `pg:"id,pk"`
+		Value string `pg:"value"`
+	}{Id: 1, Value: "hello"}
+	table, err := NewTable(&record, "test")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// The number of rows updated and deleted is returned
+	c := &conn{&tagTx{tag: "UPDATE 4"}, NewBind()}
+	n, err := c.UpdateMany(context.Background(), table, table)
+	assert.NoError(err)
+	assert.Equal(int64(4), n)
+
+	// The result in the context is also set
+	var result Result
+	c = &conn{&tagTx{tag: "DELETE 2"}, NewBind()}
+	n, err = c.DeleteMany(ContextWithResult(context.Background(), &result), table)
+	assert.NoError(err)
+	assert.Equal(int64(2), n)
+	assert.Equal("DELETE 2", result.CommandTag)
+
+	// No rows is not an error
+	c = &conn{&tagTx{tag: "DELETE 0"}, NewBind()}
+	n, err = c.DeleteMany(context.Background(), table)
+	assert.NoError(err)
+	assert.Zero(n)
+}
@@ -0,0 +1,98 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	// Packages
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// RetryPolicy determines how operations on the connection pool are retried
+// after transient errors, which are serialization failures, deadlocks, and
+// connection errors which occurred before the query was sent to the server
+type RetryPolicy struct {
+	Attempts uint          // Maximum number of attempts, including the first
+	Delay    time.Duration // Delay before the second attempt, which doubles for each further attempt
+	MaxDelay time.Duration // Maximum delay between attempts, or zero for no maximum
+}
+
+// partialError is an error which occurred after rows were scanned into a
+// reader, which is not transient, as another attempt would scan the rows
+// into the reader again
+type partialError struct {
+	error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// DefaultRetryPolicy makes up to three attempts, waiting 50ms and then 100ms
+	DefaultRetryPolicy = RetryPolicy{Attempts: 3, Delay: 50 * time.Millisecond, MaxDelay: time.Second}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// IsTransient returns true if the error is a serialization failure or
+// deadlock, or a connection error which occurred before the query was sent,
+// so that the operation can be safely retried. Errors which occurred after
+// rows were read are not transient.
+func IsTransient(err error) bool {
+	if errors.As(err, new(partialError)) {
+		return false
+	}
+	var pgerr *pgconn.PgError
+	if errors.As(err, &pgerr) {
+		switch pgerr.Code {
+		case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+			return true
+		}
+		return false
+	}
+	return pgconn.SafeToRetry(err)
+}
+
+// Do calls the function until it succeeds, returns an error which is not
+// transient, the attempts are exhausted or the context is done. The delay
+// between attempts increases exponentially. A nil policy calls the function once.
+func (p *RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	delay := time.Duration(0)
+	for attempt := uint(1); ; attempt++ {
+		err := fn()
+		if err == nil || p == nil || attempt >= p.Attempts || !IsTransient(err) {
+			return err
+		}
+
+		// Determine the delay before the next attempt
+		if delay == 0 {
+			delay = p.Delay
+		} else {
+			delay *= 2
+		}
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+
+		// Wait for the delay, or return if the context is done
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (err partialError) Unwrap() error {
+	return err.error
+}
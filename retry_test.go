@@ -0,0 +1,102 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	// Packages
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Retry_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// Serialization failures and deadlocks are transient
+	assert.True(IsTransient(&pgconn.PgError{Code: "40001"}))
+	assert.True(IsTransient(fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "40P01"})))
+
+	// Other errors are not
+	assert.False(IsTransient(&pgconn.PgError{Code: "23505"}))
+	assert.False(IsTransient(ErrNotFound))
+	assert.False(IsTransient(nil))
+}
+
+func Test_Retry_002(t *testing.T) {
+	assert := assert.New(t)
+	policy := &RetryPolicy{Attempts: 3, Delay: time.Millisecond}
+
+	// Transient errors are retried until success
+	var calls int
+	err := policy.Do(context.TODO(), func() error {
+		if calls++; calls < 3 {
+			return &pgconn.PgError{Code: "40001"}
+		}
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(3, calls)
+
+	// Attempts are exhausted
+	calls = 0
+	err = policy.Do(context.TODO(), func() error {
+		calls++
+		return &pgconn.PgError{Code: "40P01"}
+	})
+	assert.Error(err)
+	assert.Equal(3, calls)
+
+	// Errors which are not transient are not retried
+	calls = 0
+	err = policy.Do(context.TODO(), func() error {
+		calls++
+		return ErrBadParameter
+	})
+	assert.ErrorIs(err, ErrBadParameter)
+	assert.Equal(1, calls)
+
+	// A nil policy calls the function once
+	calls = 0
+	err = (*RetryPolicy)(nil).Do(context.TODO(), func() error {
+		calls++
+		return &pgconn.PgError{Code: "40001"}
+	})
+	assert.Error(err)
+	assert.Equal(1, calls)
+}
+
+func Test_Retry_003(t *testing.T) {
+	assert := assert.New(t)
+	policy := &RetryPolicy{Attempts: 10, Delay: time.Hour}
+
+	// The context is checked between attempts
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	err := policy.Do(ctx, func() error {
+		return &pgconn.PgError{Code: "40001"}
+	})
+	assert.True(errors.Is(err, context.DeadlineExceeded))
+}
+
+func Test_Retry_004(t *testing.T) {
+	assert := assert.New(t)
+	policy := &RetryPolicy{Attempts: 3, Delay: time.Millisecond}
+
+	// Errors after rows were scanned are not transient, but keep the cause
+	err := partialError{&pgconn.PgError{Code: "40001"}}
+	assert.False(IsTransient(err))
+	assert.False(IsTransient(fmt.Errorf("wrapped: %w", err)))
+	var pgerr *pgconn.PgError
+	assert.True(errors.As(err, &pgerr))
+
+	// and are not retried, so rows are not scanned into the reader twice
+	var calls int
+	assert.Error(policy.Do(context.TODO(), func() error {
+		calls++
+		return err
+	}))
+	assert.Equal(1, calls)
+}
@@ -0,0 +1,90 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	pgxpool "github.com/jackc/pgx/v5/pgxpool"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// PoolStat is a snapshot of the connection pool statistics
+type PoolStat struct {
+	MaxConns             int32         `json:"max_conns"`              // Maximum size of the pool
+	TotalConns           int32         `json:"total_conns"`            // Connections which are idle, acquired or being constructed
+	IdleConns            int32         `json:"idle_conns"`             // Connections which are idle
+	AcquiredConns        int32         `json:"acquired_conns"`         // Connections which are in use
+	ConstructingConns    int32         `json:"constructing_conns"`     // Connections which are being established
+	AcquireCount         int64         `json:"acquire_count"`          // Successful acquires from the pool
+	CanceledAcquireCount int64         `json:"canceled_acquire_count"` // Acquires cancelled by the context
+	EmptyAcquireCount    int64         `json:"empty_acquire_count"`    // Acquires which waited because the pool was empty
+	AcquireDuration      time.Duration `json:"acquire_duration"`       // Total time spent acquiring connections
+	AcquireWaitDuration  time.Duration `json:"acquire_wait_duration"`  // Total time spent waiting for a connection when the pool was empty
+	ConstructErrors      uint64        `json:"construct_errors"`       // Connections which failed to be established
+	Failovers            uint64        `json:"failovers"`              // Times the connections were closed because the server no longer matched the target session attributes
+}
+
+// poolstat counts connection failures, and is attached to the connection
+// configuration as a tracer
+type poolstat struct {
+	errors atomic.Uint64
+}
+
+// Ensure interfaces are satisfied
+var _ pgx.QueryTracer = (*poolstat)(nil)
+var _ pgx.ConnectTracer = (*poolstat)(nil)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s PoolStat) String() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (s *poolstat) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return ctx
+}
+
+func (s *poolstat) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {
+	// NOOP
+}
+
+func (s *poolstat) TraceConnectStart(ctx context.Context, _ pgx.TraceConnectStartData) context.Context {
+	return ctx
+}
+
+func (s *poolstat) TraceConnectEnd(_ context.Context, data pgx.TraceConnectEndData) {
+	if data.Err != nil {
+		s.errors.Add(1)
+	}
+}
+
+// Stat returns the pool statistics from the pgx pool and the connection counters
+func (s *poolstat) Stat(stat *pgxpool.Stat) PoolStat {
+	return PoolStat{
+		MaxConns:             stat.MaxConns(),
+		TotalConns:           stat.TotalConns(),
+		IdleConns:            stat.IdleConns(),
+		AcquiredConns:        stat.AcquiredConns(),
+		ConstructingConns:    stat.ConstructingConns(),
+		AcquireCount:         stat.AcquireCount(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		EmptyAcquireCount:    stat.EmptyAcquireCount(),
+		AcquireDuration:      stat.AcquireDuration(),
+		AcquireWaitDuration:  stat.EmptyAcquireWaitTime(),
+		ConstructErrors:      s.errors.Load(),
+	}
+}
@@ -0,0 +1,22 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_PoolStat_001(t *testing.T) {
+	assert := assert.New(t)
+	stat := new(poolstat)
+
+	// Only failed connections are counted
+	stat.TraceConnectEnd(context.TODO(), pgx.TraceConnectEndData{})
+	stat.TraceConnectEnd(context.TODO(), pgx.TraceConnectEndData{Err: errors.New("connection refused")})
+	assert.Equal(uint64(1), stat.errors.Load())
+	assert.Contains(PoolStat{ConstructErrors: 1}.String(), `"construct_errors":1`)
+}
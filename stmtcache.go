@@ -0,0 +1,89 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// StatementCacheStats are the counters for the prepared statement cache,
+// summed across all connections in the pool
+type StatementCacheStats struct {
+	Capacity uint   `json:"capacity"` // Maximum number of statements cached per connection
+	Hits     uint64 `json:"hits"`     // Queries executed without preparing a statement
+	Misses   uint64 `json:"misses"`   // Statements prepared and added to the cache
+}
+
+// stmtcache counts queries and prepared statements, and is attached to the
+// connection configuration as a tracer
+type stmtcache struct {
+	capacity uint
+	queries  atomic.Uint64
+	prepares atomic.Uint64
+}
+
+// Ensure interfaces are satisfied
+var _ pgx.QueryTracer = (*stmtcache)(nil)
+var _ pgx.PrepareTracer = (*stmtcache)(nil)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Prefix for the names of statements prepared by the pgx statement cache
+	stmtcachePrefix = "stmtcache_"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s StatementCacheStats) String() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (c *stmtcache) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	c.queries.Add(1)
+	return ctx
+}
+
+func (c *stmtcache) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {
+	// NOOP
+}
+
+func (c *stmtcache) TracePrepareStart(ctx context.Context, _ *pgx.Conn, data pgx.TracePrepareStartData) context.Context {
+	if strings.HasPrefix(data.Name, stmtcachePrefix) {
+		c.prepares.Add(1)
+	}
+	return ctx
+}
+
+func (c *stmtcache) TracePrepareEnd(context.Context, *pgx.Conn, pgx.TracePrepareEndData) {
+	// NOOP
+}
+
+// Stats returns the current counters
+func (c *stmtcache) Stats() StatementCacheStats {
+	queries, prepares := c.queries.Load(), c.prepares.Load()
+	stats := StatementCacheStats{
+		Capacity: c.capacity,
+		Misses:   prepares,
+	}
+	if queries > prepares {
+		stats.Hits = queries - prepares
+	}
+	return stats
+}
@@ -0,0 +1,29 @@
+package pg
+
+import (
+	"context"
+	"testing"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_StatementCache_001(t *testing.T) {
+	assert := assert.New(t)
+	cache := &stmtcache{capacity: 10}
+
+	// Two queries, one of which prepares a cached statement
+	cache.TraceQueryStart(context.TODO(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	cache.TracePrepareStart(context.TODO(), nil, pgx.TracePrepareStartData{Name: stmtcachePrefix + "abc", SQL: "SELECT 1"})
+	cache.TraceQueryStart(context.TODO(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+
+	// Unnamed statements are not counted as misses
+	cache.TracePrepareStart(context.TODO(), nil, pgx.TracePrepareStartData{SQL: "SELECT 2"})
+
+	stats := cache.Stats()
+	assert.Equal(uint(10), stats.Capacity)
+	assert.Equal(uint64(1), stats.Hits)
+	assert.Equal(uint64(1), stats.Misses)
+	assert.Contains(stats.String(), `"hits":1`)
+}
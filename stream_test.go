@@ -0,0 +1,50 @@
+package pg_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	// Packages
+	pg "github.com/mutablelogic/go-pg"
+	assert "github.com/stretchr/testify/assert"
+)
+
+func Test_Stream_001(t *testing.T) {
+	assert := assert.New(t)
+	conn := conn.Begin(t)
+	defer conn.Close()
+
+	// Create the table and insert rows
+	assert.NoError(conn.Exec(context.Background(), "CREATE TABLE test (id SERIAL PRIMARY KEY, name TEXT)"))
+	defer conn.Exec(context.Background(), "DROP TABLE test")
+	test := Test{Name: "Hello, World"}
+	for i := 0; i < 20; i++ {
+		assert.NoError(conn.Insert(context.Background(), &test, test))
+	}
+
+	// Stream the rows
+	var n int
+	assert.NoError(conn.ListStream(context.Background(), TestList{}, func(row pg.Row) error {
+		var test Test
+		if err := test.Scan(row); err != nil {
+			return err
+		}
+		assert.Equal("Hello, World", test.Name)
+		n++
+		return nil
+	}))
+	assert.Equal(20, n)
+
+	// Stop streaming on error
+	n = 0
+	errStop := errors.New("stop")
+	err := conn.ListStream(context.Background(), TestList{}, func(row pg.Row) error {
+		if n++; n == 5 {
+			return errStop
+		}
+		return nil
+	})
+	assert.ErrorIs(err, errStop)
+	assert.Equal(5, n)
+}
@@ -0,0 +1,397 @@
+package pg
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	// Packages
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Table is a Reader, Writer and Selector for a struct or a slice of structs,
+// which generates the SQL for a table from the pg struct tags on the fields.
+// A tag has the column name, and optionally "pk" for columns in the primary
+// key and "auto" for columns which are set by the database, such as a serial
+// identifier. Fields without a tag use the field name in snake case, and
+// fields with the tag "-" are ignored. Column names cannot start with
+// "_col_", which is reserved for the bind vars of the column values:
+//
+//	type Record struct {
+//		Id    int    `pg:"id,pk,auto"`
+//		Value string `pg:"value"`
+//	}
+//
+// A nullable timestamp column with the option "deleted" makes deletes soft,
+// so that a delete sets the column rather than removing the row, and get,
+// list and update ignore deleted rows. An integer column with the option
+// "version" is used for optimistic locking, so that an update increments
+// the version, and returns ErrConflict when the row has been updated since
+// it was read. For a versioned update, the table must also be the reader.
+type Table struct {
+	name      string
+	value     reflect.Value
+	fields    []tableField
+	count     uint64
+	versioned bool // The last select was an update which checks the version
+}
+
+type tableField struct {
+	column  string
+	index   []int
+	pk      bool
+	auto    bool
+	deleted bool
+	version bool
+}
+
+// Ensure interfaces are satisfied
+var _ ListReader = (*Table)(nil)
+var _ Writer = (*Table)(nil)
+var _ Selector = (*Table)(nil)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	tableTag     = "pg"
+	tableDeleted = "deleted"
+	tableVersion = "version"
+
+	// Prefix of the bind vars for column values, which is reserved so that
+	// columns cannot collide with other bind vars, such as patch
+	tableBind = "_col_"
+)
+
+var (
+	// Fields for each struct type, which are parsed once
+	tableFields sync.Map
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// NewTable returns a Reader, Writer and Selector for the table, where v is a
+// pointer to a struct, or a pointer to a slice of structs for a list. The
+// table name can be qualified with a schema as "schema.table".
+func NewTable(v any, table string) (*Table, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return nil, ErrBadParameter.Withf("expected a pointer to a struct or slice, got %T", v)
+	}
+	rv = rv.Elem()
+
+	// Determine the struct type
+	rt := rv.Type()
+	if rt.Kind() == reflect.Slice {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return nil, ErrBadParameter.Withf("expected a pointer to a struct or slice, got %T", v)
+	}
+
+	// Parse the fields
+	fields, err := parseTableFields(rt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Quote the table name
+	if table == "" {
+		return nil, ErrBadParameter.With("table name is empty")
+	}
+
+	// Return the table
+	return &Table{
+		name:   quote.Ident(strings.Split(table, ".")...),
+		value:  rv,
+		fields: fields,
+	}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Count returns the total number of rows counted by a list
+func (t *Table) Count() uint64 {
+	return t.count
+}
+
+// Insert binds the columns which are not set by the database, and returns
+// the query which inserts a row and returns all the columns
+func (t *Table) Insert(bind *Bind) (string, error) {
+	return t.insert(bind, "")
+}
+
+// Update binds the columns which are not in the primary key or set by the
+// database, and increments the version
+func (t *Table) Update(bind *Bind) error {
+	row, err := t.row()
+	if err != nil {
+		return err
+	}
+
+	var patch []string
+	for i, field := range t.fields {
+		if field.pk || field.auto || field.deleted || field.version {
+			continue
+		}
+		patch = append(patch, quoteIdentifier(field.column)+" = "+t.bind(bind, row, i))
+	}
+	if len(patch) == 0 {
+		return ErrBadParameter.With("no columns to update")
+	}
+	if version := t.field(tableVersion); version != nil {
+		patch = append(patch, quoteIdentifier(version.column)+" = "+quoteIdentifier(version.column)+" + 1")
+	}
+	bind.Set("patch", strings.Join(patch, ", "))
+
+	// Return success
+	return nil
+}
+
+// Select binds the primary key for get, update and delete, and returns the
+// query. For an upsert, the insert query has the conflict clause. Deleted rows are not returned, and for a versioned update, the row
+// is returned even when the version differs, so that Scan can return
+// ErrConflict rather than ErrNotFound.
+func (t *Table) Select(bind *Bind, op Op) (string, error) {
+	t.versioned = false
+	deleted := t.field(tableDeleted)
+	if op == Upsert {
+		return t.insert(bind, `${conflict} `)
+	} else if op == List {
+		if deleted != nil {
+			return `SELECT ` + t.columns() + ` FROM ` + t.name + ` WHERE ` + quoteIdentifier(deleted.column) + ` IS NULL`, nil
+		}
+		return `SELECT ` + t.columns() + ` FROM ` + t.name, nil
+	}
+
+	// Bind the primary key
+	row, err := t.row()
+	if err != nil {
+		return "", err
+	}
+	var where []string
+	for i, field := range t.fields {
+		if field.pk {
+			where = append(where, quoteIdentifier(field.column)+" = "+t.bind(bind, row, i))
+		}
+	}
+	if len(where) == 0 {
+		return "", ErrBadParameter.Withf("table %s has no primary key", t.name)
+	}
+	if deleted != nil {
+		where = append(where, quoteIdentifier(deleted.column)+" IS NULL")
+	}
+
+	// Return the query
+	switch op {
+	case Get:
+		return `SELECT ` + t.columns() + ` FROM ` + t.name + ` WHERE ` + strings.Join(where, " AND "), nil
+	case Update:
+		if version := t.field(tableVersion); version != nil {
+			t.versioned = true
+			return `WITH u AS (UPDATE ` + t.name + ` SET ${patch} WHERE ` + strings.Join(where, " AND ") + ` AND ` + quoteIdentifier(version.column) + ` = ` + t.bind(bind, row, slices.IndexFunc(t.fields, func(f tableField) bool { return f.version })) + ` RETURNING ` + t.columns() + `) ` +
+				`SELECT true, ` + t.columns() + ` FROM u UNION ALL ` +
+				`SELECT false, ` + t.columns() + ` FROM ` + t.name + ` WHERE ` + strings.Join(where, " AND ") + ` AND NOT EXISTS (SELECT 1 FROM u)`, nil
+		}
+		return `UPDATE ` + t.name + ` SET ${patch} WHERE ` + strings.Join(where, " AND ") + ` RETURNING ` + t.columns(), nil
+	case Delete:
+		if deleted != nil {
+			return `UPDATE ` + t.name + ` SET ` + quoteIdentifier(deleted.column) + ` = CURRENT_TIMESTAMP WHERE ` + strings.Join(where, " AND ") + ` RETURNING ` + t.columns(), nil
+		}
+		return `DELETE FROM ` + t.name + ` WHERE ` + strings.Join(where, " AND ") + ` RETURNING ` + t.columns(), nil
+	default:
+		return "", ErrNotImplemented.Withf("unsupported table operation %q", op)
+	}
+}
+
+// Scan a row into the struct, or append it to the slice. For a versioned
+// update, the struct is not changed and ErrConflict is returned when the
+// version differs.
+func (t *Table) Scan(row Row) error {
+	if t.versioned {
+		var updated bool
+		elem := reflect.New(t.value.Type()).Elem()
+		if err := row.Scan(append([]any{&updated}, t.dest(elem)...)...); err != nil {
+			return err
+		} else if !updated {
+			return ErrConflict.Withf("%s has been updated", t.name)
+		}
+		t.value.Set(elem)
+		return nil
+	}
+	if t.value.Kind() != reflect.Slice {
+		return row.Scan(t.dest(t.value)...)
+	}
+	elem := reflect.New(t.value.Type().Elem()).Elem()
+	if err := row.Scan(t.dest(elem)...); err != nil {
+		return err
+	}
+	t.value.Set(reflect.Append(t.value, elem))
+	return nil
+}
+
+// ScanCount scans the total number of rows for a list
+func (t *Table) ScanCount(row Row) error {
+	return row.Scan(&t.count)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return the struct for insert, update and select
+func (t *Table) row() (reflect.Value, error) {
+	if t.value.Kind() == reflect.Slice {
+		return reflect.Value{}, ErrBadParameter.With("operation requires a struct, not a slice")
+	}
+	return t.value, nil
+}
+
+// Bind the columns which are not set by the database, and return the query
+// which inserts a row, with a conflict clause
+func (t *Table) insert(bind *Bind, conflict string) (string, error) {
+	row, err := t.row()
+	if err != nil {
+		return "", err
+	}
+
+	var columns, values []string
+	for i, field := range t.fields {
+		if field.auto || field.deleted {
+			continue
+		}
+		columns = append(columns, quoteIdentifier(field.column))
+		values = append(values, t.bind(bind, row, i))
+	}
+
+	// Return the query
+	if len(columns) == 0 {
+		return `INSERT INTO ` + t.name + ` DEFAULT VALUES ` + conflict + `RETURNING ` + t.columns(), nil
+	}
+	return `INSERT INTO ` + t.name + ` (` + strings.Join(columns, ", ") + `) VALUES (` + strings.Join(values, ", ") + `) ` + conflict + `RETURNING ` + t.columns(), nil
+}
+
+// Bind the value of a field under the reserved prefix, and return the
+// named argument for the value
+func (t *Table) bind(bind *Bind, row reflect.Value, i int) string {
+	key := tableBind + strconv.Itoa(i)
+	bind.Set(key, row.FieldByIndex(t.fields[i].index).Interface())
+	return "@" + key
+}
+
+// Return the soft delete or version field, or nil if there is no such field
+func (t *Table) field(opt string) *tableField {
+	for i := range t.fields {
+		if (opt == tableDeleted && t.fields[i].deleted) || (opt == tableVersion && t.fields[i].version) {
+			return &t.fields[i]
+		}
+	}
+	return nil
+}
+
+// Return the quoted column names
+func (t *Table) columns() string {
+	columns := make([]string, 0, len(t.fields))
+	for _, field := range t.fields {
+		columns = append(columns, quoteIdentifier(field.column))
+	}
+	return strings.Join(columns, ", ")
+}
+
+// Return pointers to the fields of the struct for scanning
+func (t *Table) dest(row reflect.Value) []any {
+	dest := make([]any, 0, len(t.fields))
+	for _, field := range t.fields {
+		dest = append(dest, row.FieldByIndex(field.index).Addr().Interface())
+	}
+	return dest
+}
+
+// Parse the fields of a struct type, including embedded structs
+func parseTableFields(rt reflect.Type) ([]tableField, error) {
+	if fields, ok := tableFields.Load(rt); ok {
+		return fields.([]tableField), nil
+	}
+
+	var fields []tableField
+	columns := make(map[string]bool)
+	for _, f := range reflect.VisibleFields(rt) {
+		if !f.IsExported() || (f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get(tableTag) == "") {
+			continue
+		}
+		tag := f.Tag.Get(tableTag)
+		if tag == "-" {
+			continue
+		}
+
+		// Set the column name and options
+		parts := strings.Split(tag, ",")
+		field := tableField{column: parts[0], index: f.Index}
+		if field.column == "" {
+			field.column = snakeCase(f.Name)
+		}
+		for _, opt := range parts[1:] {
+			switch strings.TrimSpace(opt) {
+			case "pk":
+				field.pk = true
+			case "auto":
+				field.auto = true
+			case tableDeleted:
+				field.deleted = true
+			case tableVersion:
+				field.version = true
+			default:
+				return nil, ErrBadParameter.Withf("field %q has invalid tag option %q", f.Name, opt)
+			}
+		}
+		if columns[field.column] {
+			return nil, ErrBadParameter.Withf("duplicate column %q", field.column)
+		} else if strings.HasPrefix(field.column, tableBind) {
+			return nil, ErrBadParameter.Withf("column %q has the reserved prefix %q", field.column, tableBind)
+		}
+		if (field.deleted || field.version) && (field.pk || field.auto) {
+			return nil, ErrBadParameter.Withf("field %q cannot be in the primary key or set by the database", f.Name)
+		} else if field.deleted && slices.ContainsFunc(fields, func(f tableField) bool { return f.deleted }) {
+			return nil, ErrBadParameter.Withf("field %q is a second deleted column", f.Name)
+		} else if field.version && slices.ContainsFunc(fields, func(f tableField) bool { return f.version }) {
+			return nil, ErrBadParameter.Withf("field %q is a second version column", f.Name)
+		}
+		columns[field.column] = true
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil, ErrBadParameter.Withf("struct %v has no columns", rt)
+	}
+
+	// Cache and return the fields
+	tableFields.Store(rt, fields)
+	return fields, nil
+}
+
+// Quote a column name
+func quoteIdentifier(name string) string {
+	return quote.Ident(name)
+}
+
+// Convert a field name such as "CreatedAt" to "created_at"
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
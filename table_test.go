@@ -0,0 +1,273 @@
+package pg
+
+import (
+	"testing"
+	"time"
+
+	// Packages
+	assert "github.com/stretchr/testify/assert"
+)
+
+type tableRecord struct {
+	Id        int       `pg:"id,pk,auto"`
+	Value     string    `pg:"value"`
+	CreatedAt time.Time `pg:",auto"`
+	Ignored   string    `pg:"-"`
+}
+
+type tableRow []any
+
+func (r tableRow) Scan(dest ...any) error {
+	for i, v := range r {
+		switch d := dest[i].(type) {
+		case *int:
+			*d = v.(int)
+		case *string:
+			*d = v.(string)
+		case *time.Time:
+			*d = v.(time.Time)
+		case *bool:
+			*d = v.(bool)
+		case **time.Time:
+			*d, _ = v.(*time.Time)
+		}
+	}
+	return nil
+}
+
+func Test_Table_001(t *testing.T) {
+	assert := assert.New(t)
+	record := tableRecord{Id: 1, Value: "hello"}
+	table, err := NewTable(&record, "public.record")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Insert omits auto columns
+	bind := NewBind()
+	query, err := table.Insert(bind)
+	assert.NoError(err)
+	assert.Equal(`INSERT INTO "public"."record" ("value") VALUES (@_col_1) RETURNING "id", "value", "created_at"`, query)
+	assert.Equal("hello", bind.Get("_col_1"))
+
+	// Get selects by primary key
+	query, err = table.Select(bind, Get)
+	assert.NoError(err)
+	assert.Equal(`SELECT "id", "value", "created_at" FROM "public"."record" WHERE "id" = @_col_0`, query)
+	assert.Equal(1, bind.Get("_col_0"))
+
+	// Update sets the patch
+	query, err = table.Select(bind, Update)
+	assert.NoError(err)
+	assert.Equal(`UPDATE "public"."record" SET ${patch} WHERE "id" = @_col_0 RETURNING "id", "value", "created_at"`, query)
+	assert.NoError(table.Update(bind))
+	assert.Equal(`"value" = @_col_1`, bind.Get("patch"))
+
+	// Delete
+	query, err = table.Select(bind, Delete)
+	assert.NoError(err)
+	assert.Equal(`DELETE FROM "public"."record" WHERE "id" = @_col_0 RETURNING "id", "value", "created_at"`, query)
+
+	// Scan into the struct
+	now := time.Now()
+	assert.NoError(table.Scan(tableRow{2, "world", now}))
+	assert.Equal(tableRecord{Id: 2, Value: "world", CreatedAt: now}, record)
+}
+
+func Test_Table_002(t *testing.T) {
+	assert := assert.New(t)
+	var records []tableRecord
+	table, err := NewTable(&records, "record")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// List selects all columns
+	query, err := table.Select(NewBind(), List)
+	assert.NoError(err)
+	assert.Equal(`SELECT "id", "value", "created_at" FROM "record"`, query)
+
+	// Operations on a single row are not supported for a slice
+	_, err = table.Select(NewBind(), Get)
+	assert.ErrorIs(err, ErrBadParameter)
+
+	// Scan appends to the slice
+	assert.NoError(table.Scan(tableRow{1, "a", time.Time{}}))
+	assert.NoError(table.Scan(tableRow{2, "b", time.Time{}}))
+	assert.Len(records, 2)
+	assert.Equal("b", records[1].Value)
+}
+
+func Test_Table_003(t *testing.T) {
+	assert := assert.New(t)
+
+	// Invalid values
+	_, err := NewTable(tableRecord{}, "record")
+	assert.ErrorIs(err, ErrBadParameter)
+	_, err = NewTable(&tableRecord{}, "")
+	assert.ErrorIs(err, ErrBadParameter)
+	var n int
+	_, err = NewTable(&n, "record")
+	assert.ErrorIs(err, ErrBadParameter)
+
+	// Invalid tag option
+	_, err = NewTable(&struct {
+		Id int `pg:"id,unique"`
+	}{}, "record")
+	assert.ErrorIs(err, ErrBadParameter)
+
+	// Snake case column names
+	assert.Equal("created_at", snakeCase("CreatedAt"))
+	assert.Equal("http_server", snakeCase("HTTPServer"))
+	assert.Equal("id", snakeCase("Id"))
+}
+
+type tableVersioned struct {
+	Id        int        `pg:"id,pk,auto"`
+	Value     string     `pg:"value"`
+	Version   int        `pg:"version,version"`
+	DeletedAt *time.Time `pg:"deleted_at,deleted"`
+}
+
+func Test_Table_004(t *testing.T) {
+	assert := assert.New(t)
+	record := tableVersioned{Id: 1, Value: "hello", Version: 2}
+	table, err := NewTable(&record, "record")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Insert omits the deleted column
+	bind := NewBind()
+	query, err := table.Insert(bind)
+	assert.NoError(err)
+	assert.Equal(`INSERT INTO "record" ("value", "version") VALUES (@_col_1, @_col_2) RETURNING "id", "value", "version", "deleted_at"`, query)
+
+	// Get ignores deleted rows
+	query, err = table.Select(bind, Get)
+	assert.NoError(err)
+	assert.Equal(`SELECT "id", "value", "version", "deleted_at" FROM "record" WHERE "id" = @_col_0 AND "deleted_at" IS NULL`, query)
+
+	// Delete sets the deleted column
+	query, err = table.Select(bind, Delete)
+	assert.NoError(err)
+	assert.Equal(`UPDATE "record" SET "deleted_at" = CURRENT_TIMESTAMP WHERE "id" = @_col_0 AND "deleted_at" IS NULL RETURNING "id", "value", "version", "deleted_at"`, query)
+
+	// Update checks and increments the version
+	bind = NewBind()
+	query, err = table.Select(bind, Update)
+	assert.NoError(err)
+	assert.Equal(`WITH u AS (UPDATE "record" SET ${patch} WHERE "id" = @_col_0 AND "deleted_at" IS NULL AND "version" = @_col_2 RETURNING "id", "value", "version", "deleted_at") `+
+		`SELECT true, "id", "value", "version", "deleted_at" FROM u UNION ALL `+
+		`SELECT false, "id", "value", "version", "deleted_at" FROM "record" WHERE "id" = @_col_0 AND "deleted_at" IS NULL AND NOT EXISTS (SELECT 1 FROM u)`, query)
+	assert.Equal(2, bind.Get("_col_2"))
+	assert.NoError(table.Update(bind))
+	assert.Equal(`"value" = @_col_1, "version" = "version" + 1`, bind.Get("patch"))
+
+	// The updated row is scanned
+	assert.NoError(table.Scan(tableRow{true, 1, "world", 3, nil}))
+	assert.Equal(tableVersioned{Id: 1, Value: "world", Version: 3}, record)
+
+	// A row with a different version is a conflict, and is not scanned
+	assert.ErrorIs(table.Scan(tableRow{false, 1, "other", 5, nil}), ErrConflict)
+	assert.Equal(tableVersioned{Id: 1, Value: "world", Version: 3}, record)
+
+	// List ignores deleted rows
+	var records []tableVersioned
+	list, err := NewTable(&records, "record")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	query, err = list.Select(NewBind(), List)
+	assert.NoError(err)
+	assert.Equal(`SELECT "id", "value", "version", "deleted_at" FROM "record" WHERE "deleted_at" IS NULL`, query)
+}
+
+func Test_Table_005(t *testing.T) {
+	assert := assert.New(t)
+
+	// The version cannot be in the primary key
+	_, err := NewTable(&struct {
+		Id int `pg:"id,pk,version"`
+	}{}, "record")
+	assert.ErrorIs(err, ErrBadParameter)
+
+	// Only one deleted column
+	_, err = NewTable(&struct {
+		Id        int        `pg:"id,pk"`
+		DeletedAt *time.Time `pg:",deleted"`
+		RemovedAt *time.Time `pg:",deleted"`
+	}{}, "record")
+	assert.ErrorIs(err, ErrBadParameter)
+
+	// Only one version column
+	_, err = NewTable(&struct {
+		Id       int `pg:"id,pk"`
+		Version  int `pg:",version"`
+		Revision int `pg:",version"`
+	}{}, "record")
+	assert.ErrorIs(err, ErrBadParameter)
+}
+
+func Test_Table_006(t *testing.T) {
+	assert := assert.New(t)
+	record := tableRecord{Id: 1, Value: "hello"}
+	table, err := NewTable(&record, "record")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Upsert updates the conflicting row
+	bind := NewBind()
+	query, err := upsertQuery(bind, table, "value")
+	assert.NoError(err)
+	assert.Equal(`INSERT INTO "record" ("value") VALUES (@_col_1) ${conflict} RETURNING "id", "value", "created_at"`, query)
+	assert.Equal(`ON CONFLICT ("value") DO UPDATE SET "value" = @_col_1`, bind.Get("conflict"))
+	assert.Equal("hello", bind.Get("_col_1"))
+
+	// Upsert without a conflict target does nothing
+	bind = NewBind()
+	_, err = upsertQuery(bind, table)
+	assert.NoError(err)
+	assert.Equal(`ON CONFLICT DO NOTHING`, bind.Get("conflict"))
+
+	// A writer which is not a selector does not support upsert
+	_, err = upsertQuery(NewBind(), tableWriter{})
+	assert.ErrorIs(err, ErrNotImplemented)
+}
+
+type tableWriter struct{}
+
+func (tableWriter) Insert(*Bind) (string, error) { return `INSERT INTO "record" DEFAULT VALUES`, nil }
+func (tableWriter) Update(*Bind) error           { return nil }
+
+type tableReserved struct {
+	Id       int    `pg:"id,pk"`
+	Patch    string `pg:"patch"`
+	Conflict string `pg:"conflict"`
+	As       string `pg:"as"`
+}
+
+func Test_Table_007(t *testing.T) {
+	assert := assert.New(t)
+	record := tableReserved{Id: 1, Patch: "a", Conflict: "b", As: "c"}
+	table, err := NewTable(&record, "record")
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Columns with the names of other bind vars do not replace them
+	bind := NewBind()
+	query, err := upsertQuery(bind, table, "id")
+	assert.NoError(err)
+	assert.Equal(`INSERT INTO "record" ("id", "patch", "conflict", "as") VALUES (@_col_0, @_col_1, @_col_2, @_col_3) ${conflict} RETURNING "id", "patch", "conflict", "as"`, query)
+	assert.Equal(`ON CONFLICT ("id") DO UPDATE SET "patch" = @_col_1, "conflict" = @_col_2, "as" = @_col_3`, bind.Get("conflict"))
+	assert.Equal(`"patch" = @_col_1, "conflict" = @_col_2, "as" = @_col_3`, bind.Get("patch"))
+	assert.Equal("b", bind.Get("_col_2"))
+
+	// Columns cannot have the reserved prefix
+	_, err = NewTable(&struct {
+		Id int `pg:"_col_0,pk"`
+	}{}, "record")
+	assert.ErrorIs(err, ErrBadParameter)
+}
@@ -0,0 +1,540 @@
+package pg
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
+	types "github.com/mutablelogic/go-pg/pkg/types"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// template is a query which has been split into text and the bind vars which
+// are substituted into it, so that a query is only parsed once
+type template []segment
+
+// segment is text, or a bind var which is substituted
+type segment struct {
+	kind  substitution
+	value string // The text, or the key of the bind var
+}
+
+// substitution is how a bind var is substituted into a query
+type substitution uint8
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	substText    substitution = iota // Text which is not substituted
+	substValue                       // ${key} => value
+	substLiteral                     // ${'key'} => 'value'
+	substIdent                       // ${"key"} => "value"
+	substName                        // ${key} in an identifier position => value
+)
+
+const (
+	// Maximum number of templates which are kept, so that queries which are
+	// built for each request do not grow the cache without limit
+	templateCacheSize = 1024
+
+	// Bytes allowed for each substitution when a query is rendered
+	templateGrow = 16
+)
+
+var (
+	// Keywords which are followed by an identifier, such as a table or role
+	identKeywords = []string{"FROM", "JOIN", "INTO", "TABLE", "TO"}
+
+	// Templates which have been compiled, keyed by query
+	templates = struct {
+		sync.RWMutex
+		cache map[string]template
+	}{cache: make(map[string]template, templateCacheSize)}
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Return the compiled template for a query, compiling it when it has not
+// been compiled before
+func compile(query string) template {
+	templates.RLock()
+	t, exists := templates.cache[query]
+	templates.RUnlock()
+	if exists {
+		return t
+	}
+
+	// Compile the template, and keep it while there is room
+	t = parse(query)
+	templates.Lock()
+	if len(templates.cache) < templateCacheSize {
+		templates.cache[query] = t
+	}
+	templates.Unlock()
+	return t
+}
+
+// Split a query into text and substitutions, with the same syntax as
+// os.Expand
+func parse(query string) template {
+	var t template
+	text := func(s string) {
+		if s == "" {
+			return
+		} else if n := len(t); n > 0 && t[n-1].kind == substText {
+			t[n-1].value += s
+		} else {
+			t = append(t, segment{substText, s})
+		}
+	}
+
+	i := 0
+	for j := 0; j < len(query); j++ {
+		if query[j] != '$' || j+1 >= len(query) {
+			continue
+		}
+		text(query[i:j])
+		name, w := shellName(query[j+1:])
+		switch {
+		case name == "" && w > 0:
+			// Invalid syntax, the characters are removed
+		case name == "":
+			// Not followed by a name, the dollar is kept
+			text("$")
+		case name == "$":
+			text("$$")
+		case types.IsNumeric(name):
+			text("$" + name)
+		case types.IsSingleQuoted(name):
+			t = append(t, segment{substLiteral, strings.Trim(name, "'")})
+		case types.IsDoubleQuoted(name):
+			t = append(t, segment{substIdent, strings.Trim(name, "\"")})
+		default:
+			t = append(t, segment{substValue, name})
+		}
+		j += w
+		i = j + 1
+	}
+	text(query[i:])
+
+	// Values which are in an identifier position, after a keyword such as
+	// FROM or next to a dot, must be identifiers
+	for i, s := range t {
+		if s.kind != substValue {
+			continue
+		}
+		if i > 0 && t[i-1].kind == substText && identBefore(t[i-1].value) {
+			t[i].kind = substName
+		} else if i+1 < len(t) && t[i+1].kind == substText && strings.HasPrefix(t[i+1].value, ".") {
+			t[i].kind = substName
+		}
+	}
+	return t
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Lint returns ErrBadParameter when a query has a substitution which is not
+// escaped, such as '${key}' or "${key}" within quotes, which should be
+// ${'key'} or ${"key"} so the value is quoted, or a substitution which is
+// quoted twice. Comments and dollar-quoted strings are not skipped.
+func Lint(query string) error {
+	var quote byte
+	for _, s := range compile(query) {
+		switch s.kind {
+		case substText:
+			for i := 0; i < len(s.value); i++ {
+				if c := s.value[i]; quote == 0 && (c == '\'' || c == '"') {
+					quote = c
+				} else if c == quote {
+					quote = 0
+				}
+			}
+			continue
+		}
+		if quote == 0 {
+			continue
+		}
+		switch s.kind {
+		case substValue, substName:
+			return ErrBadParameter.Withf("unescaped ${%s} within quotes, use ${'%s'} or ${\"%s\"}", s.value, s.value, s.value)
+		default:
+			return ErrBadParameter.Withf("${%s} is quoted twice", s.value)
+		}
+	}
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return an error if a bind var can't be substituted safely, which is when
+// a value in an identifier position is not an identifier. When lint is true,
+// an identifier which is not bound or is empty is also an error, rather than
+// being substituted as before
+func (t template) check(vars pgx.NamedArgs, lint bool) error {
+	for _, s := range t {
+		switch s.kind {
+		case substIdent:
+			if !lint {
+				continue
+			}
+			switch v := vars[s.value].(type) {
+			case nil:
+				return ErrBadParameter.Withf("identifier ${\"%s\"} is not bound", s.value)
+			case quotedIdent:
+			case pgx.Identifier:
+				if len(v) == 0 {
+					return ErrBadParameter.Withf("identifier ${\"%s\"} is empty", s.value)
+				}
+			case []string:
+				if len(v) == 0 {
+					return ErrBadParameter.Withf("identifier ${\"%s\"} is empty", s.value)
+				}
+			default:
+				if sprint(v) == "" {
+					return ErrBadParameter.Withf("identifier ${\"%s\"} is empty", s.value)
+				}
+			}
+		case substName:
+			if v, ok := vars[s.value].(quotedIdent); ok && v != "" {
+				continue
+			} else if !isIdent(sprint(vars[s.value])) {
+				return ErrBadParameter.Withf("${%s} is not an identifier, use ${\"%s\"} or SetIdentifier", s.value, s.value)
+			}
+		}
+	}
+	return nil
+}
+
+// Return the query with the bind vars substituted
+func (t template) render(vars pgx.NamedArgs) string {
+	switch len(t) {
+	case 0:
+		return ""
+	case 1:
+		if t[0].kind == substText {
+			return t[0].value
+		}
+	}
+
+	// Allocate once for the text, and some of the substitutions
+	var sb strings.Builder
+	size := 0
+	for _, s := range t {
+		if s.kind == substText {
+			size += len(s.value)
+		} else {
+			size += templateGrow
+		}
+	}
+	sb.Grow(size)
+	for _, s := range t {
+		switch s.kind {
+		case substText:
+			sb.WriteString(s.value)
+		case substValue, substName:
+			sb.WriteString(sprint(vars[s.value]))
+		case substLiteral:
+			// Special case where value is []string for IN (${'key'})
+			if v, ok := vars[s.value].(quotedLiteral); ok {
+				sb.WriteString(string(v))
+			} else if v, ok := vars[s.value].([]string); ok {
+				for i, v := range v {
+					if i > 0 {
+						sb.WriteByte(',')
+					}
+					sb.WriteString(quote.Literal(v))
+				}
+			} else {
+				sb.WriteString(quote.Literal(sprint(vars[s.value])))
+			}
+		case substIdent:
+			switch v := vars[s.value].(type) {
+			case quotedIdent:
+				sb.WriteString(string(v))
+			case pgx.Identifier:
+				sb.WriteString(v.Sanitize())
+			case []string:
+				sb.WriteString(pgx.Identifier(v).Sanitize())
+			default:
+				sb.WriteString(quote.Ident(sprint(v)))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Return the query with @name arguments replaced by literals, for a query
+// which is sent as text to a remote database, where the arguments can't be
+// passed separately. Arguments within strings, quoted identifiers and
+// comments are not replaced, and names which are not bound are kept, the
+// same as pgx.NamedArgs.
+func unroll(query string, vars pgx.NamedArgs) string {
+	if !strings.Contains(query, "@") {
+		return query
+	}
+	var sb strings.Builder
+	sb.Grow(len(query))
+	for i := 0; i < len(query); {
+		j := i + 1
+		switch c := query[i]; {
+		case c == '\'' || c == '"':
+			// String or quoted identifier, where backslash escapes are
+			// allowed in an escape string such as E'\''
+			escape := c == '\'' && i > 0 && (query[i-1] == 'E' || query[i-1] == 'e')
+			for ; j < len(query); j++ {
+				if escape && query[j] == '\\' {
+					j++
+				} else if query[j] == c {
+					if j+1 < len(query) && query[j+1] == c {
+						j++
+					} else {
+						j++
+						break
+					}
+				}
+			}
+		case c == '$' && j < len(query) && (query[j] == '$' || isAlpha(query[j])):
+			// Dollar-quoted string, or a positional argument
+			for j < len(query) && isAlphaNum(query[j]) {
+				j++
+			}
+			if j < len(query) && query[j] == '$' {
+				tag := query[i : j+1]
+				if end := strings.Index(query[j+1:], tag); end >= 0 {
+					j += 1 + end + len(tag)
+				} else {
+					j = len(query)
+				}
+			}
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+				j = i + end
+			} else {
+				j = len(query)
+			}
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+				j = i + 2 + end + 2
+			} else {
+				j = len(query)
+			}
+		case c == '@' && j < len(query) && isAlpha(query[j]):
+			for j < len(query) && isAlphaNum(query[j]) {
+				j++
+			}
+			if value, exists := vars[query[i+1:j]]; exists {
+				sb.WriteString(literal(value))
+				i = j
+				continue
+			}
+		}
+		sb.WriteString(query[i:j])
+		i = j
+	}
+	return sb.String()
+}
+
+// Return a value as a constant, with a cast when the type is not inferred
+func literal(v any) string {
+	if isNil(v) {
+		return "NULL"
+	}
+	switch v := v.(type) {
+	case string:
+		return quote.Literal(v)
+	case []byte:
+		return quote.Literal(`\x`+hex.EncodeToString(v)) + "::BYTEA"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case float32:
+		return literal(float64(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return quote.Literal(strconv.FormatFloat(v, 'g', -1, 64)) + "::FLOAT8"
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return quote.Literal(v.Format(time.RFC3339Nano)) + "::TIMESTAMPTZ"
+	case driver.Valuer:
+		if value, err := v.Value(); err == nil {
+			return literal(value)
+		}
+	}
+
+	// Dereference pointers, and return arrays for slices and jsonb for maps
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer:
+		return literal(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		elems := make([]string, rv.Len())
+		for i := range elems {
+			elems[i] = literal(rv.Index(i).Interface())
+		}
+		return "ARRAY[" + strings.Join(elems, ",") + "]" + arrayType(rv.Type().Elem())
+	case reflect.Map:
+		if data, err := json.Marshal(v); err == nil {
+			return quote.Literal(string(data)) + "::JSONB"
+		}
+	}
+	return quote.Literal(fmt.Sprint(v))
+}
+
+// Return the cast for an array of elements of a type, so that the type of
+// an empty array is known, or an empty string if it is not known
+func arrayType(rt reflect.Type) string {
+	for rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt == reflect.TypeFor[time.Time]() {
+		return "::TIMESTAMPTZ[]"
+	}
+	switch rt.Kind() {
+	case reflect.String:
+		return "::TEXT[]"
+	case reflect.Bool:
+		return "::BOOL[]"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "::BIGINT[]"
+	case reflect.Uint, reflect.Uint64:
+		return "::NUMERIC[]"
+	case reflect.Float32, reflect.Float64:
+		return "::FLOAT8[]"
+	default:
+		return ""
+	}
+}
+
+// Return a bind var as a string, without formatting strings
+func sprint(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case quotedIdent:
+		return string(v)
+	case quotedLiteral:
+		return string(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// Return true if the text before a substitution ends with a dot, or a
+// keyword which is followed by an identifier
+func identBefore(text string) bool {
+	text = strings.TrimRightFunc(text, unicode.IsSpace)
+	if strings.HasSuffix(text, ".") {
+		return true
+	} else if len(text) == 0 {
+		return false
+	}
+	i := strings.LastIndexFunc(text, func(r rune) bool {
+		return r > unicode.MaxASCII || !isAlphaNum(byte(r))
+	})
+	word := text[i+1:]
+	for _, keyword := range identKeywords {
+		if strings.EqualFold(word, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return true if the value is an identifier which does not need quoting, a
+// quoted identifier, or these separated by dots, such as public."Users"
+func isIdent(value string) bool {
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '"':
+			// Quoted identifier, which is not empty and where quotes are doubled
+			j := i + 1
+			for ; j < len(value); j++ {
+				if value[j] != '"' {
+					continue
+				} else if j+1 < len(value) && value[j+1] == '"' {
+					j++
+				} else {
+					break
+				}
+			}
+			if j >= len(value) || j == i+1 {
+				return false
+			}
+			i = j + 1
+		case isAlpha(value[i]):
+			for i++; i < len(value) && (isAlphaNum(value[i]) || value[i] == '$'); i++ {
+			}
+		default:
+			return false
+		}
+		if i == len(value) {
+			return true
+		} else if value[i] != '.' {
+			return false
+		}
+	}
+	return false
+}
+
+// Return the name at the start of a string and the number of bytes it uses,
+// which is the same as os.Expand
+func shellName(s string) (string, int) {
+	switch {
+	case s[0] == '{':
+		if len(s) > 2 && isShellSpecial(s[1]) && s[2] == '}' {
+			return s[1:2], 3
+		}
+		for i := 1; i < len(s); i++ {
+			if s[i] == '}' {
+				if i == 1 {
+					return "", 2 // Bad syntax, "${}" is removed
+				}
+				return s[1:i], i + 1
+			}
+		}
+		return "", 1 // Bad syntax, "${" is removed
+	case isShellSpecial(s[0]):
+		return s[0:1], 1
+	}
+	var i int
+	for i = 0; i < len(s) && isAlphaNum(s[i]); i++ {
+	}
+	return s[:i], i
+}
+
+func isShellSpecial(c byte) bool {
+	switch c {
+	case '*', '#', '$', '@', '!', '?', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	}
+	return false
+}
+
+func isAlpha(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isAlphaNum(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
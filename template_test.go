@@ -0,0 +1,301 @@
+package pg
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	quote "github.com/mutablelogic/go-pg/pkg/quote"
+	types "github.com/mutablelogic/go-pg/pkg/types"
+	assert "github.com/stretchr/testify/assert"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// Queries which are compared with os.Expand
+	templateQueries = []string{
+		``,
+		`SELECT 1`,
+		`$schema`,
+		`${schema}`,
+		`${'schema'}`,
+		`${"schema"}`,
+		`$1`,
+		`${1}`,
+		`${12}`,
+		`$$`,
+		`${$}`,
+		`$`,
+		`SELECT $`,
+		`$ $`,
+		`${}`,
+		`${`,
+		`${schema`,
+		`$@ $* $# $! $? $-`,
+		`${'}`,
+		`${"}`,
+		`${''}`,
+		`${'list'}`,
+		`${"missing"}`,
+		`${number}`,
+		`$schema.table`,
+		`$$ BEGIN RETURN ${'single'}; END $$`,
+		`SELECT * FROM ${"schema"}."pg_database" WHERE name = @name ${where} ${orderby}`,
+		`WITH q AS (SELECT * FROM ${"schema"}.${"table"}) SELECT * FROM q ${offsetlimit}`,
+	}
+
+	// Vars for the queries
+	templateVars = pgx.NamedArgs{
+		"schema":      "schema",
+		"table":       "my table",
+		"single":      "'single'",
+		"double":      "\"double\"",
+		"list":        []string{"a", "b'c"},
+		"number":      42,
+		"where":       "WHERE oid > @oid",
+		"orderby":     "ORDER BY name",
+		"offsetlimit": "LIMIT 10",
+	}
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Template_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// The compiled template renders the same query as os.Expand
+	for _, query := range templateQueries {
+		t.Run(query, func(t *testing.T) {
+			assert.Equal(expand(query, templateVars), compile(query).render(templateVars))
+		})
+	}
+}
+
+func Test_Template_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// Text is merged, so a query without substitutions is one segment
+	assert.Len(parse(`SELECT $1, $$ x $$ FROM t`), 1)
+	assert.Len(parse(`SELECT ${"a"} FROM ${'b'} WHERE ${c}`), 6)
+	assert.Empty(parse(``))
+}
+
+func Test_Template_003(t *testing.T) {
+	assert := assert.New(t)
+
+	// Compiled templates are kept until the cache is full
+	query := `SELECT ${"cached"} FROM test_template_003`
+	compile(query)
+	templates.RLock()
+	_, exists := templates.cache[query]
+	templates.RUnlock()
+	assert.True(exists)
+}
+
+func Test_Template_004(t *testing.T) {
+	assert := assert.New(t)
+	ptr := "x"
+	vars := pgx.NamedArgs{
+		"name":  "o'brien",
+		"path":  `a\b`,
+		"id":    42,
+		"ok":    true,
+		"nil":   nil,
+		"ptr":   &ptr,
+		"null":  (*string)(nil),
+		"names": []string{"a", "b"},
+		"since": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"data":  []byte{0xde, 0xad},
+	}
+
+	// Arguments are replaced by literals
+	for query, expected := range map[string]string{
+		`SELECT 1`:                         `SELECT 1`,
+		`WHERE name = @name`:               `WHERE name = 'o''brien'`,
+		`WHERE path = @path`:               `WHERE path = E'a\\b'`,
+		`WHERE id = @id AND ok = @ok`:      `WHERE id = 42 AND ok = TRUE`,
+		`SELECT @nil, @ptr, @null`:         `SELECT NULL, 'x', NULL`,
+		`WHERE name = ANY(@names)`:         `WHERE name = ANY(ARRAY['a','b']::TEXT[])`,
+		`WHERE ts >= @since`:               `WHERE ts >= '2024-01-02T03:04:05Z'::TIMESTAMPTZ`,
+		`SELECT @data`:                     `SELECT E'\\xdead'::BYTEA`,
+		`WHERE name = @unknown`:            `WHERE name = @unknown`,
+		`SELECT @name_2, @id@id`:           `SELECT @name_2, 4242`,
+		`SELECT a @> b, $1, @ name`:        `SELECT a @> b, $1, @ name`,
+		`SELECT '@name', "@name", @name`:   `SELECT '@name', "@name", 'o''brien'`,
+		`SELECT 'it''s @name', @id`:        `SELECT 'it''s @name', 42`,
+		`SELECT E'\'@name', @id`:           `SELECT E'\'@name', 42`,
+		`SELECT $$@name$$, $q$@id$q$, @id`: `SELECT $$@name$$, $q$@id$q$, 42`,
+		"SELECT @id -- @name\n, @id":       "SELECT 42 -- @name\n, 42",
+		`SELECT /* @name */ @id`:           `SELECT /* @name */ 42`,
+	} {
+		assert.Equal(expected, unroll(query, vars), query)
+	}
+}
+
+func Test_Template_005(t *testing.T) {
+	assert := assert.New(t)
+
+	// Values in an identifier position must be identifiers
+	for query, position := range map[string]bool{
+		`SELECT * FROM ${table}`:        true,
+		`SELECT * FROM t JOIN ${table}`: true,
+		`INSERT INTO ${table}`:          true,
+		`GRANT ALL ON t TO ${role}`:     true,
+		`SELECT * FROM ${schema}.t`:     true,
+		`SELECT * FROM s.${table}`:      true,
+		`SELECT * FROM t ${where}`:      false,
+		`SELECT ${columns} FROM t`:      false,
+		`SELECT * FROM ${"table"}`:      false,
+	} {
+		var kinds []substitution
+		for _, s := range parse(query) {
+			kinds = append(kinds, s.kind)
+		}
+		assert.Equal(position, slices.Contains(kinds, substName), query)
+	}
+
+	// Identifiers are accepted, and other values rejected
+	for value, ok := range map[any]bool{
+		"t":                true,
+		"PUBLIC":           true,
+		"pg_catalog.t":     true,
+		`"My Table"`:       true,
+		`public."a.""b"""`: true,
+		quotedIdent(`"x"`): true,
+		"":                 false,
+		"t; DROP TABLE t":  false,
+		`"t`:               false,
+		`""`:               false,
+		`"a"b"`:            false,
+		"a.":               false,
+		"1t":               false,
+		nil:                false,
+	} {
+		err := compile(`SELECT * FROM ${table}`).check(pgx.NamedArgs{"table": value}, false)
+		if ok {
+			assert.NoError(err, value)
+		} else {
+			assert.ErrorIs(err, ErrBadParameter, value)
+		}
+	}
+
+	// Quoted identifiers must be bound and not empty when linting
+	assert.NoError(compile(`DROP TABLE ${"table"}`).check(pgx.NamedArgs{}, false))
+	assert.NoError(compile(`DROP TABLE ${"table"}`).check(pgx.NamedArgs{"table": ""}, false))
+	assert.ErrorIs(compile(`DROP TABLE ${"table"}`).check(pgx.NamedArgs{}, true), ErrBadParameter)
+	assert.ErrorIs(compile(`DROP TABLE ${"table"}`).check(pgx.NamedArgs{"table": ""}, true), ErrBadParameter)
+	assert.NoError(compile(`DROP TABLE ${"table"}`).check(pgx.NamedArgs{"table": "t; DROP TABLE t"}, true))
+	assert.Equal(`DROP TABLE ""`, compile(`DROP TABLE ${"table"}`).render(pgx.NamedArgs{"table": ""}))
+	assert.Equal(`DROP TABLE "s"."t"`, compile(`DROP TABLE ${"table"}`).render(pgx.NamedArgs{"table": []string{"s", "t"}}))
+}
+
+func Test_Template_006(t *testing.T) {
+	assert := assert.New(t)
+
+	// Substitutions which are escaped
+	for _, query := range []string{
+		`SELECT 1`,
+		`SELECT * FROM ${"table"} WHERE name = ${'name'} ${where}`,
+		`SELECT 'it''s', "a""b", ${'name'}`,
+		`SELECT '$1', @name`,
+	} {
+		assert.NoError(Lint(query), query)
+	}
+
+	// Substitutions which are not escaped, or quoted twice
+	for _, query := range []string{
+		`SELECT * FROM t WHERE name = '${name}'`,
+		`SELECT * FROM "${table}"`,
+		`SELECT 'a ${name} b'`,
+		`SELECT '${'name'}'`,
+		`SELECT "${"table"}"`,
+	} {
+		assert.ErrorIs(Lint(query), ErrBadParameter, query)
+	}
+}
+
+// Budgets for the binding layer, which fail when a change allocates more
+// for each query. Run the benchmarks to see the time taken.
+func Test_Template_Budget(t *testing.T) {
+	assert := assert.New(t)
+
+	query := `WITH q AS (SELECT * FROM ${"schema"}."pg_database" WHERE name = @name ${where}) SELECT * FROM q ${orderby} ${offsetlimit}`
+	bind := NewBind("schema", "pg_catalog", "name", "postgres", "where", "", "orderby", "ORDER BY name", "offsetlimit", "LIMIT 10")
+	compile(query)
+
+	t.Run("ReplaceText", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(100, func() {
+			bind.Replace(`SELECT 1`)
+		})
+		assert.LessOrEqual(allocs, float64(0))
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(100, func() {
+			bind.Replace(query)
+		})
+		assert.LessOrEqual(allocs, float64(2))
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(100, func() {
+			bind.Copy("as", "t (count BIGINT)")
+		})
+		assert.LessOrEqual(allocs, float64(3))
+	})
+}
+
+func Fuzz_Template(f *testing.F) {
+	for _, query := range templateQueries {
+		f.Add(query)
+	}
+	f.Fuzz(func(t *testing.T, query string) {
+		if want, got := expand(query, templateVars), parse(query).render(templateVars); want != got {
+			t.Errorf("query %q: expected %q, got %q", query, want, got)
+		}
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Substitute the vars with os.Expand, which is the behaviour the compiled
+// templates replace
+func expand(query string, vars pgx.NamedArgs) string {
+	fetch := func(key string) string {
+		return fmt.Sprint(vars[key])
+	}
+	return os.Expand(query, func(key string) string {
+		if key == "$" {
+			return "$$"
+		}
+		if types.IsNumeric(key) {
+			return "$" + key
+		}
+		if types.IsSingleQuoted(key) {
+			key := strings.Trim(key, "'")
+			switch v := vars[key].(type) {
+			case []string:
+				result := make([]string, len(v))
+				for i, s := range v {
+					result[i] = quote.Literal(s)
+				}
+				return strings.Join(result, ",")
+			default:
+				return quote.Literal(fetch(key))
+			}
+		}
+		if types.IsDoubleQuoted(key) {
+			return quote.Ident(fetch(strings.Trim(key, "\"")))
+		}
+		return fetch(key)
+	})
+}
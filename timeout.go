@@ -0,0 +1,91 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ExecOpt is a function which applies options for a single statement
+type ExecOpt func(*execopt)
+
+type execopt struct {
+	timeout time.Duration
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Set the statement timeout until the end of the transaction, returning
+	// the timeout which was set before
+	timeoutSet = `SELECT current_setting('statement_timeout'), set_config('statement_timeout', $1, true)`
+
+	// Restore the statement timeout
+	timeoutRestore = `SELECT set_config('statement_timeout', $1, true)`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Timeout sets statement_timeout for the statement, and cancels the context
+// when the timeout is exceeded, so that a slow statement returns an error
+// rather than waiting on the server. The statement is run within a
+// transaction (or a savepoint when already within a transaction), so
+// statements which can't run in a transaction, such as VACUUM, can't have a
+// timeout. The timeout replaces the query timeout of the pool, and a timeout
+// of zero or less is ignored.
+func Timeout(d time.Duration) ExecOpt {
+	return func(o *execopt) {
+		if d > 0 {
+			o.timeout = d
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Apply the statement options, returning the timeout, or zero if the timeout
+// is not set
+func applyExec(opts ...ExecOpt) time.Duration {
+	var o execopt
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.timeout
+}
+
+// Execute a query with a statement timeout, which is set for the query within
+// a transaction or savepoint, and restored before it is committed
+func execTimeout(ctx context.Context, conn pgx.Tx, bind *Bind, query string, timeout time.Duration) error {
+	if timeout <= 0 {
+		return pgerror(bind.Exec(ctx, conn, query))
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return pgerror(err)
+	}
+
+	var prev string
+	if err := tx.QueryRow(ctx, timeoutSet, fmt.Sprint(max(timeout.Milliseconds(), 1))).Scan(&prev, nil); err != nil {
+		return errors.Join(pgerror(err), tx.Rollback(context.WithoutCancel(ctx)))
+	}
+	if err := bind.Exec(ctx, tx, query); err != nil {
+		return errors.Join(pgerror(err), tx.Rollback(context.WithoutCancel(ctx)))
+	}
+	if _, err := tx.Exec(ctx, timeoutRestore, prev); err != nil {
+		return errors.Join(pgerror(err), tx.Rollback(context.WithoutCancel(ctx)))
+	}
+	return pgerror(tx.Commit(ctx))
+}
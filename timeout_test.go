@@ -0,0 +1,21 @@
+package pg
+
+import (
+	"testing"
+	"time"
+
+	// Packages
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Timeout_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// Timeout is not set
+	assert.Zero(applyExec())
+
+	// Last timeout is used, and timeouts of zero or less are ignored
+	assert.Equal(time.Second, applyExec(Timeout(time.Second)))
+	assert.Equal(time.Minute, applyExec(Timeout(time.Second), Timeout(time.Minute)))
+	assert.Equal(time.Second, applyExec(Timeout(time.Second), Timeout(0), Timeout(-time.Second)))
+}
@@ -2,10 +2,13 @@ package pg
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	// Packages
 	pgx "github.com/jackc/pgx/v5"
+	multitracer "github.com/jackc/pgx/v5/multitracer"
 )
 
 //////////////////////////////////////////////////////////////////////////////
@@ -20,7 +23,8 @@ type tracer struct {
 
 // TraceFn is a function which is called when a query is executed,
 // with the execution context, the SQL and arguments, and the error
-// if any was generated
+// if any was generated. Use ContextValues to retrieve application-level
+// identifiers such as the request id from the context.
 type TraceFn func(context.Context, string, any, error)
 
 //////////////////////////////////////////////////////////////////////////////
@@ -61,3 +65,32 @@ func args(args []any) any {
 	}
 	return args
 }
+
+// Call the trace function for a connection, if there is one, when a
+// transaction is rolled back after a panic. The arguments are the recovered
+// value, and the error includes any error from the rollback.
+func tracePanic(ctx context.Context, conn *pgx.Conn, recovered any, err error) {
+	if conn == nil {
+		return
+	}
+	if fn := traceFn(conn.Config().Tracer); fn != nil {
+		fn(ctx, "PANIC", recovered, errors.Join(fmt.Errorf("panic: %v", recovered), err))
+	}
+}
+
+// Return the trace function from a query tracer, or nil
+func traceFn(t pgx.QueryTracer) TraceFn {
+	switch t := t.(type) {
+	case *tracer:
+		if t != nil {
+			return t.TraceFn
+		}
+	case *multitracer.Tracer:
+		for _, t := range t.QueryTracers {
+			if fn := traceFn(t); fn != nil {
+				return fn
+			}
+		}
+	}
+	return nil
+}
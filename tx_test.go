@@ -0,0 +1,92 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	// Packages
+	pgx "github.com/jackc/pgx/v5"
+	multitracer "github.com/jackc/pgx/v5/multitracer"
+	assert "github.com/stretchr/testify/assert"
+)
+
+// txState records whether a transaction is committed or rolled back
+type txState struct {
+	pgx.Tx
+	commit, rollback int
+}
+
+func (tx *txState) Begin(context.Context) (pgx.Tx, error) {
+	return tx, nil
+}
+
+func (tx *txState) Commit(context.Context) error {
+	tx.commit++
+	return nil
+}
+
+func (tx *txState) Rollback(context.Context) error {
+	tx.rollback++
+	return nil
+}
+
+func (tx *txState) Conn() *pgx.Conn {
+	return nil
+}
+
+func Test_Tx_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// Commit on success, roll back on error
+	state := new(txState)
+	assert.NoError(tx(context.Background(), state, NewBind(), func(Conn) error {
+		return nil
+	}))
+	assert.Error(tx(context.Background(), state, NewBind(), func(Conn) error {
+		return ErrBadParameter
+	}))
+	assert.Equal(1, state.commit)
+	assert.Equal(1, state.rollback)
+}
+
+func Test_Tx_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// A panic rolls back the transaction, and panics again with the same value
+	state := new(txState)
+	assert.PanicsWithValue("oops", func() {
+		tx(context.Background(), state, NewBind(), func(Conn) error {
+			panic("oops")
+		})
+	})
+	assert.Equal(0, state.commit)
+	assert.Equal(1, state.rollback)
+
+	// Panics with an error value are also rolled back
+	err := errors.New("oops")
+	assert.PanicsWithError(err.Error(), func() {
+		tx(context.Background(), state, NewBind(), func(Conn) error {
+			panic(err)
+		})
+	})
+	assert.Equal(2, state.rollback)
+}
+
+func Test_Tx_003(t *testing.T) {
+	assert := assert.New(t)
+
+	// The trace function is found within a multitracer
+	var traced string
+	fn := func(_ context.Context, sql string, _ any, _ error) {
+		traced = sql
+	}
+	assert.Nil(traceFn(nil))
+	assert.Nil(traceFn(new(poolstat)))
+	assert.NotNil(traceFn(NewTracer(fn)))
+	trace := traceFn(multitracer.New(new(poolstat), NewTracer(fn)))
+	if assert.NotNil(trace) {
+		trace(context.Background(), "PANIC", nil, nil)
+		assert.Equal("PANIC", traced)
+	}
+}
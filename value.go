@@ -0,0 +1,542 @@
+package pg
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// JSONB is a value of type T which is stored as json or jsonb, and which is
+// NULL when it is not valid, in the same way as sql.Null
+type JSONB[T any] struct {
+	V     T
+	Valid bool
+}
+
+// jsonValue binds a value as json
+type jsonValue struct {
+	v any
+}
+
+// jsonScanner scans json into a value
+type jsonScanner struct {
+	v any
+}
+
+// hstoreValue binds a map as hstore
+type hstoreValue map[string]string
+
+// hstoreScanner scans hstore into a map
+type hstoreScanner struct {
+	m *map[string]string
+}
+
+// compositeValue binds a struct as a composite type
+type compositeValue struct {
+	v any
+}
+
+// compositeScanner scans a composite type into a struct
+type compositeScanner struct {
+	v any
+}
+
+// Ensure interfaces are satisfied
+var _ driver.Valuer = JSONB[any]{}
+var _ sql.Scanner = (*JSONB[any])(nil)
+var _ json.Marshaler = JSONB[any]{}
+var _ json.Unmarshaler = (*JSONB[any])(nil)
+var _ driver.Valuer = jsonValue{}
+var _ sql.Scanner = jsonScanner{}
+var _ driver.Valuer = hstoreValue{}
+var _ sql.Scanner = hstoreScanner{}
+var _ driver.Valuer = compositeValue{}
+var _ sql.Scanner = compositeScanner{}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// Layouts of the text format of timestamps, dates and times
+	timeLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999Z07:00:00",
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999Z07",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02",
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// NewJSONB returns a valid JSONB value
+func NewJSONB[T any](v T) JSONB[T] {
+	return JSONB[T]{V: v, Valid: true}
+}
+
+// JSON returns a value which is bound as json, for a json or jsonb column.
+// A nil value, or a nil pointer, map or slice, is bound as NULL.
+func JSON(v any) driver.Valuer {
+	return jsonValue{v}
+}
+
+// ScanJSON returns a scan target which unmarshals json into v, which is a
+// pointer. The value is not changed for NULL.
+func ScanJSON(v any) sql.Scanner {
+	return jsonScanner{v}
+}
+
+// Hstore returns a value which is bound as hstore. A nil map is bound as
+// NULL.
+func Hstore(m map[string]string) driver.Valuer {
+	return hstoreValue(m)
+}
+
+// ScanHstore returns a scan target for hstore, which replaces the map. The
+// map is nil for NULL, and NULL values in the hstore are empty strings.
+func ScanHstore(m *map[string]string) sql.Scanner {
+	return hstoreScanner{m}
+}
+
+// Composite returns a value which binds a struct, or a pointer to a struct,
+// as a composite type. The fields are in the order of the attributes of the
+// type, and fields with the pg tag "-" are ignored, as for NewTable. A nil
+// pointer is bound as NULL.
+func Composite(v any) driver.Valuer {
+	return compositeValue{v}
+}
+
+// ScanComposite returns a scan target for a composite type, where v is a
+// pointer to a struct with a field for each attribute of the type. The
+// struct is not changed for NULL.
+func ScanComposite(v any) sql.Scanner {
+	return compositeScanner{v}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// VALUER
+
+// Value returns the json, or nil for NULL
+func (j JSONB[T]) Value() (driver.Value, error) {
+	if !j.Valid {
+		return nil, nil
+	}
+	return jsonValue{j.V}.Value()
+}
+
+// Value returns the json, or nil for NULL
+func (j jsonValue) Value() (driver.Value, error) {
+	if isNil(j.v) {
+		return nil, nil
+	} else if data, err := json.Marshal(j.v); err != nil {
+		return nil, ErrBadParameter.With(err)
+	} else {
+		return string(data), nil
+	}
+}
+
+// Value returns the text format of the hstore, or nil for NULL
+func (h hstoreValue) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, quoteText(key)+"=>"+quoteText(h[key]))
+	}
+	return strings.Join(pairs, ", "), nil
+}
+
+// Value returns the text format of the composite type, or nil for NULL
+func (c compositeValue) Value() (driver.Value, error) {
+	if isNil(c.v) {
+		return nil, nil
+	}
+	rv := reflect.Indirect(reflect.ValueOf(c.v))
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrBadParameter.Withf("expected a struct, got %T", c.v)
+	}
+	fields, err := parseTableFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	attrs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if text, ok, err := textValue(rv.FieldByIndex(field.index).Interface()); err != nil {
+			return nil, err
+		} else if ok {
+			attrs = append(attrs, quoteText(text))
+		} else {
+			attrs = append(attrs, "")
+		}
+	}
+	return "(" + strings.Join(attrs, ",") + ")", nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SCANNER
+
+// Scan json into the value, which is not valid for NULL
+func (j *JSONB[T]) Scan(src any) error {
+	var v T
+	if src == nil {
+		*j = JSONB[T]{}
+		return nil
+	} else if err := (jsonScanner{&v}).Scan(src); err != nil {
+		return err
+	}
+	*j = JSONB[T]{V: v, Valid: true}
+	return nil
+}
+
+// Scan json into the value
+func (j jsonScanner) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), j.v)
+	case []byte:
+		return json.Unmarshal(src, j.v)
+	default:
+		return ErrBadParameter.Withf("cannot scan %T as json", src)
+	}
+}
+
+// Scan hstore into the map
+func (h hstoreScanner) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*h.m = nil
+		return nil
+	case string:
+		m, err := parseHstore(src)
+		if err != nil {
+			return err
+		}
+		*h.m = m
+		return nil
+	case []byte:
+		return h.Scan(string(src))
+	default:
+		return ErrBadParameter.Withf("cannot scan %T as hstore", src)
+	}
+}
+
+// Scan a composite type into the struct
+func (c compositeScanner) Scan(src any) error {
+	var text string
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		text = src
+	case []byte:
+		text = string(src)
+	default:
+		return ErrBadParameter.Withf("cannot scan %T as a composite type", src)
+	}
+
+	// Check the destination
+	rv := reflect.ValueOf(c.v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrBadParameter.Withf("expected a pointer to a struct, got %T", c.v)
+	}
+	rv = rv.Elem()
+	fields, err := parseTableFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	// Parse the attributes and set the fields
+	attrs, err := parseComposite(text)
+	if err != nil {
+		return err
+	} else if len(attrs) != len(fields) {
+		return ErrBadParameter.Withf("composite type has %d attributes, expected %d", len(attrs), len(fields))
+	}
+	for i, field := range fields {
+		if err := setText(rv.FieldByIndex(field.index), attrs[i]); err != nil {
+			return ErrBadParameter.Withf("%s: %v", field.column, err)
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// JSON
+
+// MarshalJSON returns the value, or null when it is not valid
+func (j JSONB[T]) MarshalJSON() ([]byte, error) {
+	if !j.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(j.V)
+}
+
+// UnmarshalJSON sets the value, which is not valid for null
+func (j *JSONB[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*j = JSONB[T]{}
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*j = JSONB[T]{V: v, Valid: true}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Return true if the value is nil, or a nil pointer, map or slice
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// Quote text with double quotes, escaping double quotes and backslashes
+func quoteText(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+}
+
+// Return the text format of a value, or false for NULL
+func textValue(v any) (string, bool, error) {
+	if isNil(v) {
+		return "", false, nil
+	}
+	switch v := v.(type) {
+	case string:
+		return v, true, nil
+	case []byte:
+		return `\x` + hex.EncodeToString(v), true, nil
+	case bool:
+		if v {
+			return "t", true, nil
+		}
+		return "f", true, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true, nil
+	case driver.Valuer:
+		value, err := v.Value()
+		if err != nil {
+			return "", false, err
+		}
+		return textValue(value)
+	}
+
+	// Dereference pointers
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		return textValue(rv.Elem().Interface())
+	}
+	return fmt.Sprint(v), true, nil
+}
+
+// Set a field from the text format of a value, where nil is NULL
+func setText(rv reflect.Value, text *string) error {
+	// Pointers are nil for NULL
+	if rv.Kind() == reflect.Pointer {
+		if text == nil {
+			rv.SetZero()
+			return nil
+		}
+		elem := reflect.New(rv.Type().Elem())
+		if err := setText(elem.Elem(), text); err != nil {
+			return err
+		}
+		rv.Set(elem)
+		return nil
+	}
+	if scanner, ok := rv.Addr().Interface().(sql.Scanner); ok {
+		if text == nil {
+			return scanner.Scan(nil)
+		}
+		return scanner.Scan(*text)
+	}
+	if text == nil {
+		rv.SetZero()
+		return nil
+	}
+
+	// Set the value
+	switch rv.Interface().(type) {
+	case time.Time:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, *text); err == nil {
+				rv.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid time %q", *text)
+	case []byte:
+		data, err := hex.DecodeString(strings.TrimPrefix(*text, `\x`))
+		if err != nil {
+			return err
+		}
+		rv.SetBytes(data)
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(*text)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(*text)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(*text, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(*text, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(*text, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(v)
+	default:
+		return fmt.Errorf("unsupported type %v", rv.Type())
+	}
+
+	// Return success
+	return nil
+}
+
+// Parse the text format of a composite type, such as (1,"a b",), returning
+// the attributes, which are nil for NULL
+func parseComposite(text string) ([]*string, error) {
+	if len(text) < 2 || text[0] != '(' || text[len(text)-1] != ')' {
+		return nil, ErrBadParameter.Withf("invalid composite type %q", text)
+	}
+	text = text[1 : len(text)-1]
+
+	var attrs []*string
+	for i := 0; ; {
+		attr, n, err := parseText(text[i:], ",", true)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, attr)
+		i += n
+		if i >= len(text) {
+			return attrs, nil
+		}
+		i++ // Skip the comma
+	}
+}
+
+// Parse the text format of hstore, such as "a"=>"1", "b"=>NULL
+func parseHstore(text string) (map[string]string, error) {
+	m := make(map[string]string)
+	for i := 0; ; {
+		i += len(text[i:]) - len(strings.TrimLeft(text[i:], " "))
+		if i >= len(text) {
+			return m, nil
+		}
+
+		// Parse the key
+		key, n, err := parseText(text[i:], "=", false)
+		if err != nil {
+			return nil, err
+		} else if key == nil {
+			return nil, ErrBadParameter.Withf("invalid hstore %q", text)
+		}
+		i += n
+		if !strings.HasPrefix(text[i:], "=>") {
+			return nil, ErrBadParameter.Withf("invalid hstore %q", text)
+		}
+		i += 2
+
+		// Parse the value, where NULL is an empty string
+		value, n, err := parseText(text[i:], ",", false)
+		if err != nil {
+			return nil, err
+		} else if value != nil && i < len(text) && text[i] != '"' && strings.EqualFold(*value, "NULL") {
+			value = nil
+		}
+		if value != nil {
+			m[*key] = *value
+		} else {
+			m[*key] = ""
+		}
+		i += n
+		if i < len(text) && text[i] == ',' {
+			i++
+		}
+	}
+}
+
+// Parse text which is double quoted, or ends at a separator, returning the
+// text and the number of bytes parsed. Unquoted empty text is nil when
+// empty is true.
+func parseText(text, sep string, empty bool) (*string, int, error) {
+	// Unquoted text
+	if !strings.HasPrefix(text, `"`) {
+		n := strings.IndexAny(text, sep)
+		if n < 0 {
+			n = len(text)
+		}
+		if value := strings.TrimSpace(text[:n]); value != "" || !empty {
+			return &value, n, nil
+		}
+		return nil, n, nil
+	}
+
+	// Quoted text, where quotes are escaped with a backslash or doubled
+	var b strings.Builder
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			if i++; i < len(text) {
+				b.WriteByte(text[i])
+			}
+		case '"':
+			if i+1 < len(text) && text[i+1] == '"' {
+				b.WriteByte('"')
+				i++
+			} else {
+				value := b.String()
+				return &value, i + 1, nil
+			}
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return nil, 0, ErrBadParameter.Withf("unterminated quote in %q", text)
+}
@@ -0,0 +1,145 @@
+package pg
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	// Packages
+	assert "github.com/stretchr/testify/assert"
+)
+
+type valueComposite struct {
+	Name    string    `pg:"name"`
+	Count   int       `pg:"count"`
+	Price   *float64  `pg:"price"`
+	Enabled bool      `pg:"enabled"`
+	Created time.Time `pg:"created"`
+	Ignored string    `pg:"-"`
+}
+
+func Test_Value_001(t *testing.T) {
+	assert := assert.New(t)
+
+	// JSON is marshalled, and nil is NULL
+	value, err := JSON(map[string]any{"a": 1}).Value()
+	assert.NoError(err)
+	assert.Equal(`{"a":1}`, value)
+	value, err = JSON([]string(nil)).Value()
+	assert.NoError(err)
+	assert.Nil(value)
+
+	// JSON is unmarshalled, and NULL does not change the value
+	var v map[string]int
+	assert.NoError(ScanJSON(&v).Scan([]byte(`{"a":1}`)))
+	assert.Equal(map[string]int{"a": 1}, v)
+	assert.NoError(ScanJSON(&v).Scan(nil))
+	assert.Equal(map[string]int{"a": 1}, v)
+	assert.ErrorIs(ScanJSON(&v).Scan(1), ErrBadParameter)
+}
+
+func Test_Value_002(t *testing.T) {
+	assert := assert.New(t)
+
+	// Hstore is quoted, with the keys in order
+	value, err := Hstore(map[string]string{"b": `say "hi"`, "a": `c:\`}).Value()
+	assert.NoError(err)
+	assert.Equal(`"a"=>"c:\\", "b"=>"say \"hi\""`, value)
+	value, err = Hstore(nil).Value()
+	assert.NoError(err)
+	assert.Nil(value)
+
+	// Hstore is parsed, and NULL values are empty
+	var m map[string]string
+	assert.NoError(ScanHstore(&m).Scan(`"a"=>"c:\\", "b"=>"say \"hi\"", "c"=>NULL, "d"=>"NULL"`))
+	assert.Equal(map[string]string{"a": `c:\`, "b": `say "hi"`, "c": "", "d": "NULL"}, m)
+	assert.NoError(ScanHstore(&m).Scan(""))
+	assert.Empty(m)
+	assert.NoError(ScanHstore(&m).Scan(nil))
+	assert.Nil(m)
+	assert.ErrorIs(ScanHstore(&m).Scan(`"a"`), ErrBadParameter)
+	assert.ErrorIs(ScanHstore(&m).Scan(`"a=>"b"`), ErrBadParameter)
+}
+
+func Test_Value_003(t *testing.T) {
+	assert := assert.New(t)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// A struct is bound as the text format of a composite type
+	value, err := Composite(valueComposite{Name: `a "b"`, Count: 2, Enabled: true, Created: created}).Value()
+	assert.NoError(err)
+	assert.Equal(`("a \"b\"","2",,"t","2024-01-02T03:04:05Z")`, value)
+	value, err = Composite((*valueComposite)(nil)).Value()
+	assert.NoError(err)
+	assert.Nil(value)
+	_, err = Composite(1).Value()
+	assert.ErrorIs(err, ErrBadParameter)
+
+	// The text format is scanned into a struct
+	var v valueComposite
+	assert.NoError(ScanComposite(&v).Scan(`("a ""b""",2,1.5,t,"2024-01-02 03:04:05+00")`))
+	assert.Equal(`a "b"`, v.Name)
+	assert.Equal(2, v.Count)
+	if assert.NotNil(v.Price) {
+		assert.Equal(1.5, *v.Price)
+	}
+	assert.True(v.Enabled)
+	assert.True(created.Equal(v.Created))
+
+	// NULL attributes are zero
+	assert.NoError(ScanComposite(&v).Scan(`(,,,f,)`))
+	assert.Equal(valueComposite{}, v)
+
+	// Errors
+	assert.ErrorIs(ScanComposite(&v).Scan(`(a,1)`), ErrBadParameter)
+	assert.ErrorIs(ScanComposite(&v).Scan(`(a,b,,f,)`), ErrBadParameter)
+	assert.ErrorIs(ScanComposite(&v).Scan(`a,1`), ErrBadParameter)
+	assert.ErrorIs(ScanComposite(v).Scan(`(a,1,,f,)`), ErrBadParameter)
+}
+
+func Test_Value_004(t *testing.T) {
+	assert := assert.New(t)
+
+	// Slices are arrays, and maps are jsonb, for remote queries
+	assert.Equal(`ARRAY[1,2]::BIGINT[]`, literal([]int{1, 2}))
+	assert.Equal(`ARRAY['a','b''c']::TEXT[]`, literal([]string{"a", "b'c"}))
+	assert.Equal(`ARRAY[]::BOOL[]`, literal([]bool{}))
+	assert.Equal(`NULL`, literal([]string(nil)))
+	assert.Equal(`'{"a":1}'::JSONB`, literal(map[string]int{"a": 1}))
+	assert.Equal(`'"a"=>"b"'`, literal(Hstore(map[string]string{"a": "b"})))
+}
+
+func Test_Value_005(t *testing.T) {
+	assert := assert.New(t)
+	type Settings struct {
+		Theme string `json:"theme"`
+	}
+
+	// A valid value is bound as json, otherwise NULL
+	value, err := NewJSONB(Settings{Theme: "dark"}).Value()
+	assert.NoError(err)
+	assert.Equal(`{"theme":"dark"}`, value)
+	value, err = JSONB[Settings]{}.Value()
+	assert.NoError(err)
+	assert.Nil(value)
+
+	// Scan json, and NULL
+	var v JSONB[Settings]
+	assert.NoError(v.Scan(`{"theme":"light"}`))
+	assert.Equal(NewJSONB(Settings{Theme: "light"}), v)
+	assert.NoError(v.Scan(nil))
+	assert.False(v.Valid)
+	assert.Error(v.Scan(`{`))
+
+	// Marshal and unmarshal as the value, or null
+	data, err := json.Marshal(NewJSONB(Settings{Theme: "dark"}))
+	assert.NoError(err)
+	assert.Equal(`{"theme":"dark"}`, string(data))
+	data, err = json.Marshal(JSONB[Settings]{})
+	assert.NoError(err)
+	assert.Equal(`null`, string(data))
+	assert.NoError(json.Unmarshal([]byte(`{"theme":"light"}`), &v))
+	assert.Equal(NewJSONB(Settings{Theme: "light"}), v)
+	assert.NoError(json.Unmarshal([]byte(`null`), &v))
+	assert.False(v.Valid)
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	// Packages
+	dom "github.com/djthorpe/go-wasmbuild"
+	bs "github.com/djthorpe/go-wasmbuild/pkg/bootstrap"
+	mvc "github.com/djthorpe/go-wasmbuild/pkg/mvc"
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	schema "github.com/mutablelogic/go-pg/pkg/manager/schema"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// aclEditor is a grid of roles and the privileges which can be granted on a
+// database, schema or tablespace. Changes are previewed as the GRANT and
+// REVOKE statements which are executed when they are applied.
+type aclEditor struct {
+	client *httpclient.Client
+
+	// The object which is being edited
+	objtype  string
+	database string
+	name     string
+
+	// The roles which can be granted privileges, and the privileges on the
+	// object before and after the pending changes
+	roles   []string
+	current schema.ACLList
+	desired schema.ACLList
+
+	// Elements which are updated
+	objects dom.Element
+	comment dom.Element
+	matrix  dom.Element
+	preview dom.Element
+	status  dom.Element
+	apply   dom.Element
+}
+
+// aclObject is a database, schema or tablespace which can be selected, with
+// its comment
+type aclObject struct {
+	database string
+	name     string
+	comment  string
+	acl      schema.ACLList
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	aclDatabase   = "DATABASE"
+	aclSchema     = "SCHEMA"
+	aclTablespace = "TABLESPACE"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Return the privilege editor view
+func aclView(client *httpclient.Client) mvc.View {
+	editor := &aclEditor{client: client, objtype: aclDatabase}
+
+	// Object type and object selection
+	objtype := mvc.HTML("SELECT", mvc.WithClass("form-select"),
+		mvc.HTML("OPTION", mvc.WithAttr("value", aclDatabase), "Database"),
+		mvc.HTML("OPTION", mvc.WithAttr("value", aclSchema), "Schema"),
+		mvc.HTML("OPTION", mvc.WithAttr("value", aclTablespace), "Tablespace"),
+	)
+	editor.objects = mvc.HTML("SELECT", mvc.WithClass("form-select"))
+	editor.comment = mvc.HTML("DIV", mvc.WithClass("fst-italic", "my-2"))
+	editor.matrix = mvc.HTML("DIV", mvc.WithClass("table-responsive", "my-3"))
+	editor.preview = mvc.HTML("PRE", mvc.WithClass("bg-body-tertiary", "border", "rounded", "p-3"))
+	editor.status = mvc.HTML("DIV", mvc.WithClass("text-body-secondary", "my-2"))
+	editor.apply = mvc.HTML("BUTTON", mvc.WithClass("btn", "btn-primary", "me-2"), mvc.WithAttr("type", "button"), "Apply")
+	reset := mvc.HTML("BUTTON", mvc.WithClass("btn", "btn-outline-secondary"), mvc.WithAttr("type", "button"), "Reset")
+
+	// Events
+	objtype.AddEventListener("change", func(dom.Event) {
+		editor.objtype = objtype.Value()
+		go editor.loadObjects()
+	})
+	editor.objects.AddEventListener("change", func(dom.Event) {
+		go editor.loadObjects()
+	})
+	editor.apply.AddEventListener("click", func(dom.Event) {
+		go editor.applyChanges()
+	})
+	reset.AddEventListener("click", func(dom.Event) {
+		editor.desired = cloneACL(editor.current)
+		editor.render()
+	})
+
+	// Load the roles and objects
+	go editor.loadObjects()
+
+	// Return the view
+	return bs.Container(
+		mvc.WithClass("my-4"),
+		bs.Heading(3, "Privileges"),
+		bs.Row(bs.Col3(objtype), bs.Col9(editor.objects)),
+		editor.comment,
+		editor.status,
+		editor.matrix,
+		bs.Heading(5, "Pending changes"),
+		editor.preview,
+		mvc.HTML("DIV", editor.apply, reset),
+	)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Load the roles and the objects of the selected type, then select the
+// object which was selected before, or the first object
+func (editor *aclEditor) loadObjects() {
+	ctx := context.Background()
+	editor.setStatus("Loading...")
+
+	// Roles which are not predefined can be granted privileges, as well as
+	// PUBLIC
+	roles, err := editor.client.ListRoles(ctx)
+	if err != nil {
+		editor.setStatus(err.Error())
+		return
+	}
+	editor.roles = []string{schema.DefaultAclRole}
+	for _, role := range roles.Body {
+		if !strings.HasPrefix(role.Name, "pg_") {
+			editor.roles = append(editor.roles, role.Name)
+		}
+	}
+
+	// Objects of the selected type
+	objects, err := editor.listObjects(ctx)
+	if err != nil {
+		editor.setStatus(err.Error())
+		return
+	}
+
+	// Keep the selection when the object still exists
+	selected := editor.objects.Value()
+	options := make([]any, 0, len(objects))
+	for _, object := range objects {
+		options = append(options, mvc.HTML("OPTION", mvc.WithAttr("value", object.key()), object.key()))
+	}
+	replaceChildren(editor.objects, options...)
+	if len(objects) == 0 {
+		editor.name, editor.current, editor.desired = "", nil, nil
+		replaceChildren(editor.comment)
+		editor.setStatus("There are no objects of this type")
+		editor.render()
+		return
+	}
+	object := objects[0]
+	for _, v := range objects {
+		if v.key() == selected {
+			object = v
+		}
+	}
+	editor.objects.SetValue(object.key())
+	replaceChildren(editor.comment, object.comment)
+
+	// Set the privileges on the object
+	editor.database, editor.name = object.database, object.name
+	editor.current, editor.desired = object.acl, cloneACL(object.acl)
+	for _, item := range object.acl {
+		if !slices.Contains(editor.roles, item.Role) {
+			editor.roles = append(editor.roles, item.Role)
+		}
+	}
+	editor.setStatus("")
+	editor.render()
+}
+
+// Return the objects of the selected type
+func (editor *aclEditor) listObjects(ctx context.Context) ([]aclObject, error) {
+	var result []aclObject
+	switch editor.objtype {
+	case aclDatabase:
+		list, err := editor.client.ListDatabases(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, database := range list.Body {
+			result = append(result, aclObject{name: database.Name, comment: types.PtrString(database.Comment), acl: database.Acl})
+		}
+	case aclSchema:
+		list, err := editor.client.ListSchemas(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, namespace := range list.Body {
+			result = append(result, aclObject{database: namespace.Database, name: namespace.Name, comment: types.PtrString(namespace.Comment), acl: namespace.Acl})
+		}
+	case aclTablespace:
+		list, err := editor.client.ListTablespaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, tablespace := range list.Body {
+			result = append(result, aclObject{name: tablespace.Name, acl: tablespace.Acl})
+		}
+	}
+	return result, nil
+}
+
+// Apply the pending changes, and reload the privileges on the object
+func (editor *aclEditor) applyChanges() {
+	ctx := context.Background()
+	desired := editor.pruned()
+	if len(editor.current.Diff(desired)) == 0 {
+		return
+	}
+
+	// An empty list leaves the privileges unchanged, so at least one
+	// privilege needs to remain
+	if len(desired) == 0 {
+		editor.setStatus("At least one privilege needs to remain granted")
+		return
+	}
+
+	// Update the object
+	editor.setStatus("Applying...")
+	var err error
+	switch editor.objtype {
+	case aclDatabase:
+		_, err = editor.client.UpdateDatabase(ctx, editor.name, schema.DatabaseMeta{Acl: desired})
+	case aclSchema:
+		_, err = editor.client.UpdateSchema(ctx, editor.database, editor.name, schema.SchemaMeta{Acl: desired})
+	case aclTablespace:
+		_, err = editor.client.UpdateTablespace(ctx, editor.name, schema.TablespaceMeta{Acl: desired})
+	}
+	if err != nil {
+		editor.setStatus(err.Error())
+		return
+	}
+
+	// Reload the object
+	editor.loadObjects()
+}
+
+// Render the privilege matrix and the pending changes
+func (editor *aclEditor) render() {
+	privs := schema.ACLPrivileges(editor.objtype)
+
+	// Header of privileges
+	header := []any{mvc.HTML("TH", "Role")}
+	for _, priv := range privs {
+		header = append(header, mvc.HTML("TH", mvc.WithClass("text-center"), priv))
+	}
+
+	// A row of privileges for each role, with changes highlighted
+	rows := make([]any, 0, len(editor.roles))
+	if editor.name != "" {
+		for _, role := range editor.roles {
+			cells := []any{mvc.HTML("TH", role)}
+			for _, priv := range privs {
+				cells = append(cells, editor.cell(role, priv))
+			}
+			rows = append(rows, mvc.HTML("TR", cells...))
+		}
+	}
+	replaceChildren(editor.matrix, mvc.HTML("TABLE", mvc.WithClass("table", "table-sm", "table-hover", "align-middle"),
+		mvc.HTML("THEAD", mvc.HTML("TR", header...)),
+		mvc.HTML("TBODY", rows...),
+	))
+
+	// Statements which are executed when the changes are applied
+	var statements []string
+	for _, change := range editor.current.Diff(editor.pruned()) {
+		statements = append(statements, change.Statements(editor.objtype, editor.name)...)
+	}
+	if len(statements) == 0 {
+		replaceChildren(editor.preview, "No pending changes")
+		editor.apply.SetAttribute("disabled", "")
+	} else {
+		replaceChildren(editor.preview, strings.Join(statements, ";\n")+";")
+		editor.apply.RemoveAttribute("disabled")
+	}
+}
+
+// Return a cell of the privilege matrix, which toggles a privilege
+func (editor *aclEditor) cell(role, priv string) dom.Element {
+	checkbox := mvc.HTML("INPUT", mvc.WithClass("form-check-input"), mvc.WithAttr("type", "checkbox"))
+	granted := hasPriv(editor.desired, role, priv)
+	checkbox.SetData(granted)
+	checkbox.AddEventListener("change", func(dom.Event) {
+		editor.setPriv(role, priv, checkbox.Data().(bool))
+		editor.render()
+	})
+
+	// Highlight cells which are changed
+	cell := mvc.HTML("TD", mvc.WithClass("text-center"), checkbox)
+	if granted != hasPriv(editor.current, role, priv) {
+		cell.ClassList().Add("table-warning")
+	}
+	return cell
+}
+
+// Grant or revoke a privilege for a role in the pending changes
+func (editor *aclEditor) setPriv(role, priv string, granted bool) {
+	item := editor.desired.Find(role)
+	if item == nil {
+		item = &schema.ACLItem{Role: role}
+		editor.desired = append(editor.desired, item)
+	}
+	item.Priv = slices.DeleteFunc(item.Priv, func(v string) bool {
+		return v == priv || v == priv+" WITH GRANT OPTION"
+	})
+	if granted {
+		// Keep the grant option if the role had it before
+		if existing := editor.current.Find(role); existing != nil && slices.Contains(existing.Priv, priv+" WITH GRANT OPTION") {
+			item.Priv = append(item.Priv, priv+" WITH GRANT OPTION")
+		} else {
+			item.Priv = append(item.Priv, priv)
+		}
+	}
+}
+
+// Return the pending privileges without roles which have none
+func (editor *aclEditor) pruned() schema.ACLList {
+	result := make(schema.ACLList, 0, len(editor.desired))
+	for _, item := range editor.desired {
+		if len(item.Priv) > 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (editor *aclEditor) setStatus(text string) {
+	replaceChildren(editor.status, text)
+}
+
+// Return the key which identifies an object in the selection
+func (object aclObject) key() string {
+	if object.database != "" {
+		return object.database + "." + object.name
+	}
+	return object.name
+}
+
+// Return true if a role has a privilege, with or without the grant option
+func hasPriv(acl schema.ACLList, role, priv string) bool {
+	if item := acl.Find(role); item != nil {
+		return item.IsAll() || slices.Contains(item.Priv, priv) || slices.Contains(item.Priv, priv+" WITH GRANT OPTION")
+	}
+	return false
+}
+
+// Return a copy of a list of privileges, so that pending changes do not
+// modify it
+func cloneACL(acl schema.ACLList) schema.ACLList {
+	result := make(schema.ACLList, 0, len(acl))
+	for _, item := range acl {
+		result = append(result, item.WithPriv(slices.Clone(item.Priv)...))
+	}
+	return result
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	// Packages
+	dom "github.com/djthorpe/go-wasmbuild"
+	bs "github.com/djthorpe/go-wasmbuild/pkg/bootstrap"
+	mvc "github.com/djthorpe/go-wasmbuild/pkg/mvc"
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+	types "github.com/mutablelogic/go-server/pkg/types"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// settingBanner is shown while changed settings are waiting for the server
+// to reload its configuration, or to restart, and when the configuration
+// files have errors
+type settingBanner struct {
+	client  *httpclient.Client
+	root    dom.Element
+	restart dom.Element
+	reload  dom.Element
+	errors  dom.Element
+	status  dom.Element
+	button  dom.Element
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Interval between checking for pending settings
+	bannerInterval = 30 * time.Second
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Return the banner for settings which are pending a reload or restart, and
+// errors in the configuration files
+func bannerView(client *httpclient.Client) mvc.View {
+	banner := &settingBanner{client: client}
+	banner.restart = mvc.HTML("DIV")
+	banner.reload = mvc.HTML("DIV")
+	banner.errors = mvc.HTML("DIV", mvc.WithClass("text-danger"))
+	banner.status = mvc.HTML("DIV", mvc.WithClass("small", "mt-2"))
+	banner.button = mvc.HTML("BUTTON", mvc.WithClass("btn", "btn-sm", "btn-warning", "mt-2"), mvc.WithAttr("type", "button"), "Reload configuration")
+	banner.root = mvc.HTML("DIV", mvc.WithClass("alert", "alert-warning", "d-none"), mvc.WithAttr("role", "alert"),
+		banner.restart, banner.reload, banner.errors, banner.button, banner.status,
+	)
+
+	// Reload the configuration when the button is clicked
+	banner.button.AddEventListener("click", func(dom.Event) {
+		go banner.reloadConfig()
+	})
+
+	// Check for pending settings until the page is closed
+	go func() {
+		ticker := time.NewTicker(bannerInterval)
+		defer ticker.Stop()
+		for {
+			banner.refresh()
+			<-ticker.C
+		}
+	}()
+
+	// Return the view
+	return bs.Container(mvc.WithClass("mt-3"), banner.root)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Update the banner with the settings which are pending a reload or restart
+func (banner *settingBanner) refresh() {
+	ctx := context.Background()
+
+	// Settings which have been reloaded, but need a restart
+	settings, err := banner.client.ListSettings(ctx, httpclient.WithPendingRestart(true))
+	if err != nil {
+		banner.setStatus(err.Error())
+		return
+	}
+	restart := make([]string, 0, len(settings.Body))
+	for _, setting := range settings.Body {
+		restart = append(restart, setting.Name)
+	}
+
+	// Settings which have changed since the configuration was loaded
+	server, err := banner.client.GetServer(ctx)
+	if err != nil {
+		banner.setStatus(err.Error())
+		return
+	}
+	history, err := banner.client.ListSettingHistory(ctx)
+	if err != nil {
+		banner.setStatus(err.Error())
+		return
+	}
+	reload := history.PendingReload(server.ConfigLoaded)
+
+	// Entries in the configuration files which have errors. Reading the files
+	// requires a superuser, so they are not checked when they cannot be read
+	var errors []string
+	if files, err := banner.client.ListConfigFiles(ctx, httpclient.WithError(types.BoolPtr(true))); err == nil {
+		errors = append(errors, files.Errors()...)
+	}
+	if hba, err := banner.client.ListHBARules(ctx, httpclient.WithError(types.BoolPtr(true))); err == nil {
+		errors = append(errors, hba.Errors()...)
+	}
+
+	// Show or hide the banner
+	setPending(banner.restart, "Restart required for ", restart)
+	setPending(banner.reload, "Reload required for ", reload)
+	setErrors(banner.errors, errors)
+	if len(reload) > 0 {
+		banner.button.ClassList().Remove("d-none")
+	} else {
+		banner.button.ClassList().Add("d-none")
+	}
+	if len(restart) > 0 || len(reload) > 0 || len(errors) > 0 {
+		banner.root.ClassList().Remove("d-none")
+	} else {
+		banner.root.ClassList().Add("d-none")
+	}
+	banner.setStatus("")
+}
+
+// Reload the configuration, then update the banner
+func (banner *settingBanner) reloadConfig() {
+	banner.button.SetAttribute("disabled", "")
+	defer banner.button.RemoveAttribute("disabled")
+	if _, err := banner.client.ReloadConfig(context.Background()); err != nil {
+		banner.setStatus(err.Error())
+		return
+	}
+	banner.refresh()
+}
+
+func (banner *settingBanner) setStatus(text string) {
+	replaceChildren(banner.status, text)
+	if text != "" {
+		banner.root.ClassList().Remove("d-none")
+	}
+}
+
+// Set the text of a list of pending settings, or hide it when the list is
+// empty
+func setPending(element dom.Element, label string, names []string) {
+	if len(names) == 0 {
+		replaceChildren(element)
+		return
+	}
+	replaceChildren(element, label, mvc.HTML("STRONG", strings.Join(names, ", ")))
+}
+
+// Set the list of errors in the configuration files, or hide it when the
+// list is empty
+func setErrors(element dom.Element, errors []string) {
+	if len(errors) == 0 {
+		replaceChildren(element)
+		return
+	}
+	items := make([]any, 0, len(errors))
+	for _, err := range errors {
+		items = append(items, mvc.HTML("LI", err))
+	}
+	replaceChildren(element, "Errors in the configuration files:", mvc.HTML("UL", append([]any{mvc.WithClass("mb-0")}, items...)...))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/url"
+	"time"
+
+	// Packages
+	dom "github.com/djthorpe/go-wasmbuild"
+	impl "github.com/djthorpe/go-wasmbuild/pkg/dom"
+	mvc "github.com/djthorpe/go-wasmbuild/pkg/mvc"
+	client "github.com/mutablelogic/go-client"
+	httpclient "github.com/mutablelogic/go-pg/pkg/manager/httpclient"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Path of the API, relative to the page which serves the frontend
+	apiPrefix = "/api/v1"
+
+	// Query parameter of the page with the endpoint of an API on another
+	// origin, which allows the origin of the frontend
+	apiParam = "api"
+
+	// Requests which fail with a transient error are repeated, and requests
+	// are not sent for a while when they keep failing
+	apiRetry            = 2
+	apiRetryBackoff     = 500 * time.Millisecond
+	apiBreakerThreshold = 5
+	apiBreakerCooldown  = 10 * time.Second
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Return a client for the API served alongside the frontend, or for the API
+// set with the "api" query parameter of the page
+func newClient() (*httpclient.Client, error) {
+	endpoint, err := url.Parse(impl.GetWindow().Location().Href())
+	if err != nil {
+		return nil, err
+	}
+	opts := []client.ClientOpt{
+		httpclient.WithRetry(apiRetry, apiRetryBackoff),
+		httpclient.WithCircuitBreaker(apiBreakerThreshold, apiBreakerCooldown),
+	}
+	if api := endpoint.Query().Get(apiParam); api != "" {
+		return httpclient.New(api, opts...)
+	}
+	endpoint.Path, endpoint.RawQuery, endpoint.Fragment = apiPrefix, "", ""
+	return httpclient.New(endpoint.String(), opts...)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Replace the children of an element
+func replaceChildren(parent dom.Element, children ...any) {
+	for _, child := range parent.ChildNodes() {
+		parent.RemoveChild(child)
+	}
+	for _, child := range children {
+		if node := mvc.NodeFromAny(child); node != nil {
+			parent.AppendChild(node)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	// Packages
 	bs "github.com/djthorpe/go-wasmbuild/pkg/bootstrap"
 	bsextra "github.com/djthorpe/go-wasmbuild/pkg/bootstrap/extra"
@@ -8,17 +10,24 @@ import (
 )
 
 func main() {
+	// Client for the API
+	client, err := newClient()
+	if err != nil {
+		panic(fmt.Sprint("pgmanager: ", err))
+	}
+
 	// Navigation controller
 	controller := bsextra.NavbarController(navbar())
 
 	// Run the application
-	mvc.New(controller.Views()[0]).Run()
+	mvc.New(controller.Views()[0], bannerView(client), mvc.Router().Page("#acl", aclView(client))).Run()
 }
 
 func navbar() mvc.View {
 	return bs.NavBar("main",
 		bs.WithPosition(bs.Sticky|bs.Top), bs.WithTheme(bs.Dark), bs.WithSize(bs.Medium),
 		bs.NavItem("#roles", "Roles"),
+		bs.NavItem("#acl", "Privileges"),
 	).Label(
 		bs.Icon("bootstrap-fill", mvc.WithClass("me-2")), "pgmanager",
 	)
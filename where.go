@@ -0,0 +1,170 @@
+package pg
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Clause builds the WHERE and ORDER BY clauses of a list query. Each
+// condition binds its value as a @key argument, where the key is the name of
+// the column, so values are never part of the query string. Columns are
+// SQL fragments and should not come from user input.
+type Clause struct {
+	where   []string
+	orderby []string
+	keys    []string
+	values  []any
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Where returns an empty clause, which binds no conditions and no ordering
+func Where() *Clause {
+	return new(Clause)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Eq adds the condition "column = @column"
+func (c *Clause) Eq(column string, value any) *Clause {
+	return c.cmp(column, "=", value)
+}
+
+// In adds the condition "column = ANY(@column)", where values is a slice
+func (c *Clause) In(column string, values any) *Clause {
+	c.where = append(c.where, column+` = ANY(@`+c.set(columnKey(column), values)+`)`)
+	return c
+}
+
+// Gt adds the condition "column > @column"
+func (c *Clause) Gt(column string, value any) *Clause {
+	return c.cmp(column, ">", value)
+}
+
+// Gte adds the condition "column >= @column"
+func (c *Clause) Gte(column string, value any) *Clause {
+	return c.cmp(column, ">=", value)
+}
+
+// Lt adds the condition "column < @column"
+func (c *Clause) Lt(column string, value any) *Clause {
+	return c.cmp(column, "<", value)
+}
+
+// Lte adds the condition "column <= @column"
+func (c *Clause) Lte(column string, value any) *Clause {
+	return c.cmp(column, "<=", value)
+}
+
+// Contains adds the condition "column @> @column" for a jsonb column, where
+// the value is bound as json, so that rows where the column contains the
+// value are returned
+func (c *Clause) Contains(column string, value any) *Clause {
+	c.where = append(c.where, column+` @> CAST(@`+c.set(columnKey(column), JSON(value))+` AS JSONB)`)
+	return c
+}
+
+// HasKey adds the condition "column ? @column" for a jsonb column, so that
+// rows where the column has the key at the top level are returned
+func (c *Clause) HasKey(column, key string) *Clause {
+	return c.cmp(column, "?", key)
+}
+
+// JSONPath adds the condition "column @? @column" for a jsonb column, so
+// that rows where the jsonpath returns an item are returned, such as
+// `$.tags[*] ? (@ == "prod")`
+func (c *Clause) JSONPath(column, path string) *Clause {
+	c.where = append(c.where, column+` @? CAST(@`+c.set(columnKey(column), path)+` AS JSONPATH)`)
+	return c
+}
+
+// Expr adds a condition which has no arguments, such as "NOT pending_restart"
+func (c *Clause) Expr(expr string) *Clause {
+	c.where = append(c.where, expr)
+	return c
+}
+
+// After adds the keyset pagination condition "(a, b) > (@after_a, @after_b)"
+// which returns the rows after the last row of the previous page. Each value
+// is bound to the column in the same position, and any columns or values
+// without a counterpart are ignored.
+func (c *Clause) After(columns []string, values ...any) *Clause {
+	n := min(len(columns), len(values))
+	if n == 0 {
+		return c
+	}
+	keys := make([]string, n)
+	for i := range n {
+		keys[i] = `@` + c.set("after_"+columnKey(columns[i]), values[i])
+	}
+	if n == 1 {
+		c.where = append(c.where, columns[0]+` > `+keys[0])
+	} else {
+		c.where = append(c.where, `(`+strings.Join(columns[:n], ", ")+`) > (`+strings.Join(keys, ", ")+`)`)
+	}
+	return c
+}
+
+// OrderBy adds a column to the ordering, in ascending or descending order
+func (c *Clause) OrderBy(column string, asc bool) *Clause {
+	if asc {
+		c.orderby = append(c.orderby, column+` ASC`)
+	} else {
+		c.orderby = append(c.orderby, column+` DESC`)
+	}
+	return c
+}
+
+// Bind sets the arguments of the conditions, and the "where" and "orderby"
+// variables, which are empty when there are no conditions or ordering
+func (c *Clause) Bind(bind *Bind) {
+	for i, key := range c.keys {
+		bind.Set(key, c.values[i])
+	}
+	if len(c.where) > 0 {
+		bind.Set("where", `WHERE `+strings.Join(c.where, " AND "))
+	} else {
+		bind.Set("where", "")
+	}
+	if len(c.orderby) > 0 {
+		bind.Set("orderby", `ORDER BY `+strings.Join(c.orderby, ", "))
+	} else {
+		bind.Set("orderby", "")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// Add a comparison of the column with a value
+func (c *Clause) cmp(column, op string, value any) *Clause {
+	c.where = append(c.where, column+` `+op+` @`+c.set(columnKey(column), value))
+	return c
+}
+
+// Set the value for a key, returning the key, which has a suffix when the
+// key is already used by another condition
+func (c *Clause) set(name string, value any) string {
+	unique := name
+	for i := 2; slices.Contains(c.keys, unique); i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	c.keys = append(c.keys, unique)
+	c.values = append(c.values, value)
+	return unique
+}
+
+// Return the argument key for a column, which is the column name without
+// the table qualifier or quotes
+func columnKey(column string) string {
+	if i := strings.LastIndexByte(column, '.'); i >= 0 {
+		column = column[i+1:]
+	}
+	return strings.Trim(column, `"`)
+}
@@ -0,0 +1,99 @@
+package pg_test
+
+import (
+	"testing"
+
+	// Packages
+	"github.com/mutablelogic/go-pg"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Where_001(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("Empty", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().Bind(bind)
+		assert.Equal("", bind.Get("where"))
+		assert.Equal("", bind.Get("orderby"))
+	})
+
+	t.Run("Eq", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().Eq(`schema`, "public").Eq(`"table"`, "users").Bind(bind)
+		assert.Equal(`WHERE schema = @schema AND "table" = @table`, bind.Get("where"))
+		assert.Equal("public", bind.Get("schema"))
+		assert.Equal("users", bind.Get("table"))
+	})
+
+	t.Run("Qualified", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().Eq(`d.datname`, "test").Bind(bind)
+		assert.Equal(`WHERE d.datname = @datname`, bind.Get("where"))
+		assert.Equal("test", bind.Get("datname"))
+	})
+
+	t.Run("In", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().In(`type`, []string{"TABLE", "VIEW"}).Bind(bind)
+		assert.Equal(`WHERE type = ANY(@type)`, bind.Get("where"))
+		assert.Equal([]string{"TABLE", "VIEW"}, bind.Get("type"))
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().Gte(`"timestamp"`, 1).Lt(`"timestamp"`, 2).Bind(bind)
+		assert.Equal(`WHERE "timestamp" >= @timestamp AND "timestamp" < @timestamp_2`, bind.Get("where"))
+		assert.Equal(1, bind.Get("timestamp"))
+		assert.Equal(2, bind.Get("timestamp_2"))
+	})
+
+	t.Run("Expr", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().Eq(`category`, "Autovacuum").Expr(`NOT pending_restart`).Bind(bind)
+		assert.Equal(`WHERE category = @category AND NOT pending_restart`, bind.Get("where"))
+	})
+
+	t.Run("After", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().After([]string{`database`, `schema`, `name`}, "db", "public", "users").Bind(bind)
+		assert.Equal(`WHERE (database, schema, name) > (@after_database, @after_schema, @after_name)`, bind.Get("where"))
+		assert.Equal("db", bind.Get("after_database"))
+		assert.Equal("public", bind.Get("after_schema"))
+		assert.Equal("users", bind.Get("after_name"))
+	})
+
+	t.Run("AfterOne", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().After([]string{`"pid"`}, 100).Bind(bind)
+		assert.Equal(`WHERE "pid" > @after_pid`, bind.Get("where"))
+		assert.Equal(100, bind.Get("after_pid"))
+	})
+
+	t.Run("AfterEmpty", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().After([]string{`name`}).Bind(bind)
+		assert.Equal("", bind.Get("where"))
+	})
+
+	t.Run("OrderBy", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().OrderBy(`database`, true).OrderBy(`calls`, false).Bind(bind)
+		assert.Equal("", bind.Get("where"))
+		assert.Equal(`ORDER BY database ASC, calls DESC`, bind.Get("orderby"))
+	})
+
+	t.Run("JSONB", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().Contains(`settings`, map[string]string{"theme": "dark"}).HasKey(`settings`, "locale").JSONPath(`settings`, `$.size ? (@ > 10)`).Bind(bind)
+		assert.Equal(`WHERE settings @> CAST(@settings AS JSONB) AND settings ? @settings_2 AND settings @? CAST(@settings_3 AS JSONPATH)`, bind.Get("where"))
+		assert.Equal("locale", bind.Get("settings_2"))
+		assert.Equal(`$.size ? (@ > 10)`, bind.Get("settings_3"))
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		bind := pg.NewBind()
+		pg.Where().Eq(`name`, "o'brien").OrderBy(`name`, true).Bind(bind)
+		assert.Equal(`SELECT * FROM t WHERE name = @name ORDER BY name ASC`, bind.Replace(`SELECT * FROM t ${where} ${orderby}`))
+	})
+}